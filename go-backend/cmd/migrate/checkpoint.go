@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCheckpointPath records which source files a migration already ingested
+const DefaultCheckpointPath = "data/.pdf-migration-checkpoint.json"
+
+// CheckpointEntry describes one successfully ingested file
+type CheckpointEntry struct {
+	Chunks      int       `json:"chunks"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// MigrationCheckpoint tracks ingested files so an interrupted migration can
+// resume. It is rewritten after every completed file.
+type MigrationCheckpoint struct {
+	path      string
+	Completed map[string]CheckpointEntry `json:"completed"`
+}
+
+// loadCheckpoint reads the checkpoint at path, starting empty if it does not exist
+func loadCheckpoint(path string) (*MigrationCheckpoint, error) {
+	checkpoint := &MigrationCheckpoint{path: path, Completed: make(map[string]CheckpointEntry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Completed == nil {
+		checkpoint.Completed = make(map[string]CheckpointEntry)
+	}
+	return checkpoint, nil
+}
+
+// resetCheckpoint deletes the checkpoint so the next run starts from scratch
+func resetCheckpoint(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to reset checkpoint: %w", err)
+	}
+	return nil
+}
+
+// IsCompleted reports whether file was already ingested
+func (c *MigrationCheckpoint) IsCompleted(file string) bool {
+	_, ok := c.Completed[checkpointKey(file)]
+	return ok
+}
+
+// MarkCompleted records file as ingested and persists the checkpoint
+func (c *MigrationCheckpoint) MarkCompleted(file string, chunks int) error {
+	c.Completed[checkpointKey(file)] = CheckpointEntry{Chunks: chunks, CompletedAt: time.Now()}
+	return c.save()
+}
+
+// save writes the checkpoint atomically so a crash never leaves it half-written
+func (c *MigrationCheckpoint) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+func checkpointKey(file string) string {
+	return filepath.Base(file)
+}
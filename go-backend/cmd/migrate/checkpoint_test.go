@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var errSimulatedCrash = errors.New("simulated crash")
+
+// runUntilCrash ingests files, aborting the whole run when crashOn is reached
+func runUntilCrash(t *testing.T, checkpoint *MigrationCheckpoint, files []string, crashOn string) (ingested []string) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil && r != errSimulatedCrash {
+			panic(r)
+		}
+	}()
+
+	_, err := ingestPDFFiles(files, checkpoint, func(file string) (int, error) {
+		if file == crashOn {
+			panic(errSimulatedCrash)
+		}
+		ingested = append(ingested, file)
+		return 10, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ingested
+}
+
+func TestResumeSkipsFilesIngestedBeforeCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	files := []string{"data/unit1.pdf", "data/unit2.pdf", "data/unit3.pdf", "data/unit4.pdf"}
+
+	first, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := runUntilCrash(t, first, files, "data/unit3.pdf"); !reflect.DeepEqual(got, files[:2]) {
+		t.Fatalf("first run ingested %v", got)
+	}
+
+	resumed, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := runUntilCrash(t, resumed, files, ""); !reflect.DeepEqual(got, files[2:]) {
+		t.Errorf("resumed run ingested %v, want %v", got, files[2:])
+	}
+	if len(resumed.Completed) != 4 {
+		t.Errorf("expected 4 completed files in checkpoint, got %d", len(resumed.Completed))
+	}
+}
+
+func TestFailedFilesAreRetriedOnResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	files := []string{"a.pdf", "b.pdf"}
+
+	checkpoint, _ := loadCheckpoint(path)
+	if _, err := ingestPDFFiles(files, checkpoint, func(file string) (int, error) {
+		if file == "a.pdf" {
+			return 0, errors.New("unreadable")
+		}
+		return 1, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, _ := loadCheckpoint(path)
+	if reloaded.IsCompleted("a.pdf") || !reloaded.IsCompleted("b.pdf") {
+		t.Errorf("unexpected checkpoint %+v", reloaded.Completed)
+	}
+}
+
+func TestResetCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	checkpoint, _ := loadCheckpoint(path)
+	if err := checkpoint.MarkCompleted("a.pdf", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resetCheckpoint(path); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.Completed) != 0 {
+		t.Errorf("expected empty checkpoint after reset, got %+v", reloaded.Completed)
+	}
+	// Resetting twice is fine
+	if err := resetCheckpoint(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPDFChunkIDIsDeterministic(t *testing.T) {
+	if pdfChunkID("unit1.pdf", 3) != pdfChunkID("unit1.pdf", 3) {
+		t.Error("expected identical IDs for the same chunk")
+	}
+	if pdfChunkID("unit1.pdf", 3) == pdfChunkID("unit1.pdf", 4) {
+		t.Error("expected different IDs for different chunks")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/data/weaviate"
+	"github.com/mathprereq/pkg/chunker"
+)
+
+const formulaLine = "Power rule: d/dx x^n = n * x^(n-1) for every real exponent n"
+
+func textbookTestOptions(minChunkLength int) chunker.Options {
+	return chunker.Options{
+		TargetSize: DefaultTextbookChunkSize,
+		Overlap:    DefaultTextbookChunkOverlap,
+		MinLength:  minChunkLength,
+		Mode:       chunker.ModeLine,
+	}
+}
+
+func TestLoadTextbookContentKeepsShortFormulaWhenThresholdLowered(t *testing.T) {
+	if len(formulaLine) != 60 {
+		t.Fatalf("fixture should be 60 chars, got %d", len(formulaLine))
+	}
+
+	path := filepath.Join(t.TempDir(), "textbook.txt")
+	content := "Chapter: Derivatives\nPower Rule:\n" + formulaLine + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := loadTextbookContent(path, textbookTestOptions(80))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 0 {
+		t.Fatalf("expected formula to be dropped at threshold 80, got %d chunks", len(chunks))
+	}
+
+	chunks, err = loadTextbookContent(path, textbookTestOptions(40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 || chunks[0].Content != formulaLine {
+		t.Fatalf("expected formula chunk to be kept, got %+v", chunks)
+	}
+	if chunks[0].Concept != "Power Rule" || chunks[0].Chapter != "Derivatives" {
+		t.Errorf("unexpected chunk metadata: concept=%q chapter=%q", chunks[0].Concept, chunks[0].Chapter)
+	}
+}
+
+func TestCreateChunksFromTextHonoursMinChunkLength(t *testing.T) {
+	text := "\n--- Page 3 ---\n" + formulaLine + "\n"
+	unit := UnitInfo{Number: "4", Title: "Calculus", Subject: "calculus"}
+
+	p := &PDFProcessor{MinChunkLength: DefaultPDFMinChunkLength}
+	if chunks := p.createChunksFromText(text, unit, weaviate.Source{Document: "Unit-4-calculus.pdf"}); len(chunks) != 0 {
+		t.Fatalf("expected formula to be dropped at default threshold, got %d chunks", len(chunks))
+	}
+
+	p.MinChunkLength = 40
+	chunks := p.createChunksFromText(text, unit, weaviate.Source{Document: "Unit-4-calculus.pdf"})
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(chunks))
+	}
+	if !strings.Contains(chunks[0].Content, "n * x^(n-1)") {
+		t.Errorf("unexpected chunk content: %q", chunks[0].Content)
+	}
+	if chunks[0].Source.Page != 3 {
+		t.Errorf("expected page 3, got %d", chunks[0].Source.Page)
+	}
+}
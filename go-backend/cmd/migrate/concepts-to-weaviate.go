@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/data/weaviate"
+)
+
+// runConceptsToWeaviateMigration stores every concept of the graph's CSV in
+// the Weaviate concept class, so context can be retrieved from concept
+// descriptions
+func runConceptsToWeaviateMigration() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client, err := weaviate.NewClient(cfg.Weaviate)
+	if err != nil {
+		return fmt.Errorf("failed to create Weaviate client: %w", err)
+	}
+
+	concepts, err := readConceptVectors(nodesCSV)
+	if err != nil {
+		return fmt.Errorf("failed to read concepts: %w", err)
+	}
+
+	if err := client.AddConceptVectors(context.Background(), concepts); err != nil {
+		return fmt.Errorf("failed to add concepts to Weaviate: %w", err)
+	}
+
+	fmt.Printf("✅ Successfully migrated %d concepts to Weaviate\n", len(concepts))
+	return nil
+}
+
+// readConceptVectors reads node_id,concept_name,description rows, skipping
+// the header
+func readConceptVectors(filename string) ([]weaviate.ConceptVector, error) {
+	records, err := readCSVRows(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	concepts := make([]weaviate.ConceptVector, 0, len(records))
+	for _, record := range records {
+		concepts = append(concepts, weaviate.ConceptVector{
+			ID:          record[0],
+			Name:        record[1],
+			Description: record[2],
+		})
+	}
+	return concepts, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadConceptVectors(t *testing.T) {
+	csv := "node_id,concept_name,description\n" +
+		"limits, Limits ,The concept of approaching a value infinitely close\n" +
+		"derivatives,Derivatives,\"Rate of change, the slope of a tangent\"\n"
+	path := filepath.Join(t.TempDir(), "nodes.csv")
+	if err := os.WriteFile(path, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	concepts, err := readConceptVectors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(concepts) != 2 {
+		t.Fatalf("expected 2 concepts, got %+v", concepts)
+	}
+	if concepts[0].ID != "limits" || concepts[0].Name != "Limits" {
+		t.Errorf("unexpected first concept %+v", concepts[0])
+	}
+	if concepts[1].Description != "Rate of change, the slope of a tangent" {
+		t.Errorf("unexpected description %q", concepts[1].Description)
+	}
+}
@@ -11,7 +11,18 @@ import (
 	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
 )
 
+// Graph CSVs loaded into Neo4j
+const (
+	nodesCSV = "data/raw/nodes.csv"
+	edgesCSV = "data/raw/edges.csv"
+)
+
 func runCsvToNeo4jMigration() error {
+	// The migration clears the graph first, so bad data must stop it here
+	if err := runCSVValidation(nodesCSV, edgesCSV); err != nil {
+		return fmt.Errorf("invalid CSV data: %w", err)
+	}
+
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -29,22 +40,22 @@ func runCsvToNeo4jMigration() error {
 	ctx := context.Background()
 
 	// Check if data already exists
-	if exists, err := checkDataExists(ctx, driver); err != nil {
+	if exists, err := checkDataExists(ctx, driver, cfg.Neo4j.Database); err != nil {
 		return fmt.Errorf("failed to check existing data: %w", err)
 	} else if exists {
 		fmt.Println("⚠️  Data already exists. Cleaning and reloading...")
-		if err := clearAllData(ctx, driver); err != nil {
+		if err := clearAllData(ctx, driver, cfg.Neo4j.Database); err != nil {
 			return fmt.Errorf("failed to clear existing data: %w", err)
 		}
 	}
 
 	// Load nodes
-	if err := loadNodes(ctx, driver, "data/raw/nodes.csv"); err != nil {
+	if err := loadNodes(ctx, driver, cfg.Neo4j.Database, nodesCSV); err != nil {
 		return fmt.Errorf("failed to load nodes: %w", err)
 	}
 
 	// Load edges
-	if err := loadEdges(ctx, driver, "data/raw/edges.csv"); err != nil {
+	if err := loadEdges(ctx, driver, cfg.Neo4j.Database, edgesCSV); err != nil {
 		return fmt.Errorf("failed to load edges: %w", err)
 	}
 
@@ -52,8 +63,8 @@ func runCsvToNeo4jMigration() error {
 	return nil
 }
 
-func checkDataExists(ctx context.Context, driver neo4j.Driver) (bool, error) {
-	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+func checkDataExists(ctx context.Context, driver neo4j.Driver, database string) (bool, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: database})
 	defer session.Close(ctx)
 
 	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
@@ -76,8 +87,8 @@ func checkDataExists(ctx context.Context, driver neo4j.Driver) (bool, error) {
 	return result.(bool), nil
 }
 
-func clearAllData(ctx context.Context, driver neo4j.Driver) error {
-	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+func clearAllData(ctx context.Context, driver neo4j.Driver, database string) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
 	defer session.Close(ctx)
 
 	// Delete all relationships first, then all nodes
@@ -101,31 +112,19 @@ func clearAllData(ctx context.Context, driver neo4j.Driver) error {
 	return nil
 }
 
-func loadNodes(ctx context.Context, driver neo4j.Driver, filename string) error {
-	file, err := os.Open(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+func loadNodes(ctx context.Context, driver neo4j.Driver, database, filename string) error {
+	records, err := readCSVRows(filename)
 	if err != nil {
 		return err
 	}
 
-	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
 	defer session.Close(ctx)
 
-	// Skip header row and process nodes
-	for i, record := range records[1:] {
-		if len(record) < 3 {
-			return fmt.Errorf("invalid record at line %d: expected 3 columns, got %d", i+2, len(record))
-		}
-
-		nodeID := strings.TrimSpace(record[0])
-		conceptName := strings.TrimSpace(record[1])
-		description := strings.TrimSpace(record[2])
+	for _, record := range records {
+		nodeID := record[0]
+		conceptName := record[1]
+		description := record[2]
 
 		_, err = session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
 			query := `
@@ -151,35 +150,23 @@ func loadNodes(ctx context.Context, driver neo4j.Driver, filename string) error
 		fmt.Printf("  📝 Created concept: %s\n", conceptName)
 	}
 
-	fmt.Printf("✅ Loaded %d nodes\n", len(records)-1)
+	fmt.Printf("✅ Loaded %d nodes\n", len(records))
 	return nil
 }
 
-func loadEdges(ctx context.Context, driver neo4j.Driver, filename string) error {
-	file, err := os.Open(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+func loadEdges(ctx context.Context, driver neo4j.Driver, database, filename string) error {
+	records, err := readCSVRows(filename)
 	if err != nil {
 		return err
 	}
 
-	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
 	defer session.Close(ctx)
 
-	// Skip header row and process edges
-	for i, record := range records[1:] {
-		if len(record) < 3 {
-			return fmt.Errorf("invalid record at line %d: expected 3 columns, got %d", i+2, len(record))
-		}
-
-		sourceID := strings.TrimSpace(record[0])
-		targetID := strings.TrimSpace(record[1])
-		relationshipType := strings.TrimSpace(record[2])
+	for _, record := range records {
+		sourceID := record[0]
+		targetID := record[1]
+		relationshipType := record[2]
 
 		_, err = session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
 			query := `
@@ -187,6 +174,7 @@ func loadEdges(ctx context.Context, driver neo4j.Driver, filename string) error
                 MATCH (target:Concept {id: $targetId})
                 CREATE (source)-[:PREREQUISITE_FOR {
                     type: $relType,
+                    weight: 1.0,
                     created_at: datetime()
                 }]->(target)
             `
@@ -220,6 +208,35 @@ func loadEdges(ctx context.Context, driver neo4j.Driver, filename string) error
 		fmt.Printf("  🔗 Created relationship: %s -> %s\n", sourceID, targetID)
 	}
 
-	fmt.Printf("✅ Loaded %d edges\n", len(records)-1)
+	fmt.Printf("✅ Loaded %d edges\n", len(records))
 	return nil
 }
+
+// readCSVRows reads a three-column CSV such as nodes.csv or edges.csv,
+// skipping the header and trimming every field
+func readCSVRows(filename string) ([][]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	rows := records[1:]
+	for i, record := range rows {
+		if len(record) < 3 {
+			return nil, fmt.Errorf("invalid record at line %d: expected 3 columns, got %d", i+2, len(record))
+		}
+		for j := range record {
+			record[j] = strings.TrimSpace(record[j])
+		}
+	}
+	return rows, nil
+}
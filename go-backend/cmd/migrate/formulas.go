@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/data/mongodb"
+	"github.com/mathprereq/internal/data/weaviate"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	infrastructurerepos "github.com/mathprereq/internal/infrastructure/repositories"
+	"github.com/mathprereq/pkg/chunker"
+	"github.com/mathprereq/pkg/logger"
+)
+
+// openFormulaStore connects to the MongoDB formula collection. Formulas are
+// extracted alongside the vector content, so when MongoDB is unreachable the
+// migration carries on without them and the returned store is nil.
+func openFormulaStore(cfg *config.Config) (repositories.FormulaRepository, func()) {
+	client, err := mongodb.NewClient(mongodb.Config{
+		URI:            cfg.MongoDB.URI,
+		Database:       cfg.MongoDB.Database,
+		ConnectTimeout: cfg.MongoDB.ConnectTimeout,
+	})
+	if err != nil {
+		fmt.Printf("⚠️  MongoDB unavailable, skipping formula extraction: %v\n", err)
+		return nil, func() {}
+	}
+
+	store := infrastructurerepos.NewMongoFormulaRepository(client.GetMongoClient(), cfg.MongoDB.Database, logger.MustGetLogger())
+	return store, func() { client.Close(context.Background()) }
+}
+
+// saveChunkFormulas extracts the formulas in chunks and stores them. A nil
+// store skips extraction.
+func saveChunkFormulas(ctx context.Context, store repositories.FormulaRepository, chunks []weaviate.ContentChunk) error {
+	if store == nil {
+		return nil
+	}
+
+	formulas := formulasFromChunks(chunks)
+	if err := store.SaveBatch(ctx, formulas); err != nil {
+		return err
+	}
+	fmt.Printf("🧮 Stored %d formulas\n", len(formulas))
+	return nil
+}
+
+// formulasFromChunks extracts the formulas of each chunk and links them to
+// the chunk's concepts and source. A formula repeated within a document is
+// kept once, linked to every concept it appeared under.
+func formulasFromChunks(chunks []weaviate.ContentChunk) []*entities.Formula {
+	var formulas []*entities.Formula
+	byID := make(map[string]*entities.Formula)
+
+	for _, chunk := range chunks {
+		concepts := chunkConcepts(chunk.Concept)
+		for _, found := range chunker.ExtractFormulas(chunk.Content) {
+			formula := entities.NewFormula(found.Text, found.Display, concepts, chunk.Source.Document, chunk.ID)
+			if existing, ok := byID[formula.ID]; ok {
+				mergeFormulaConcepts(existing, formula)
+				continue
+			}
+
+			formula.Title = chunk.Source.Title
+			formula.Chapter = chunk.Chapter
+			formula.Page = chunk.Source.Page
+			byID[formula.ID] = formula
+			formulas = append(formulas, formula)
+		}
+	}
+	return formulas
+}
+
+// chunkConcepts splits a chunk's concept field, which PDF chunks fill with
+// several "; "-separated concepts
+func chunkConcepts(field string) []string {
+	var concepts []string
+	for _, concept := range strings.Split(field, ";") {
+		if concept = strings.TrimSpace(concept); concept != "" {
+			concepts = append(concepts, concept)
+		}
+	}
+	return concepts
+}
+
+// mergeFormulaConcepts adds the concepts of a repeated formula to the first
+// occurrence
+func mergeFormulaConcepts(into, from *entities.Formula) {
+	for i, key := range from.ConceptKeys {
+		known := false
+		for _, existing := range into.ConceptKeys {
+			if existing == key {
+				known = true
+				break
+			}
+		}
+		if !known {
+			into.ConceptKeys = append(into.ConceptKeys, key)
+			into.Concepts = append(into.Concepts, from.Concepts[i])
+		}
+	}
+}
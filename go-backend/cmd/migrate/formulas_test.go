@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mathprereq/internal/data/weaviate"
+)
+
+func TestFormulasFromChunks(t *testing.T) {
+	source := weaviate.Source{Document: "calculus_textbook", Title: "Calculus Textbook", Page: 4}
+	chunks := []weaviate.ContentChunk{
+		{
+			ID:      "c1",
+			Content: "Integration by parts is used for products. The formula is ∫u dv = uv - ∫v du, where u is chosen first.",
+			Concept: "Integration by Parts",
+			Chapter: "Methods of Integration",
+			Source:  source,
+		},
+		{
+			ID:      "c2",
+			Content: "Recall that ∫u dv = uv - ∫v du. Also $$\\int_a^b f(x)\\,dx = F(b) - F(a)$$",
+			Concept: "Definite Integrals; integration_by_parts; Product Rule",
+			Chapter: "Methods of Integration",
+			Source:  source,
+		},
+		{ID: "c3", Content: "Integration is the reverse of differentiation.", Concept: "Integration", Source: source},
+	}
+
+	formulas := formulasFromChunks(chunks)
+	if len(formulas) != 2 {
+		t.Fatalf("expected 2 formulas, got %d: %+v", len(formulas), formulas)
+	}
+
+	parts := formulas[0]
+	if parts.Expression != "∫u dv = uv - ∫v du" || parts.ChunkID != "c1" || parts.Display {
+		t.Errorf("unexpected first formula %+v", parts)
+	}
+	wantKeys := []string{"integration by parts", "definite integrals", "product rule"}
+	if len(parts.ConceptKeys) != len(wantKeys) {
+		t.Fatalf("concept keys = %q, want %q", parts.ConceptKeys, wantKeys)
+	}
+	for i, key := range wantKeys {
+		if parts.ConceptKeys[i] != key {
+			t.Errorf("concept key %d = %q, want %q", i, parts.ConceptKeys[i], key)
+		}
+	}
+	if parts.Document != "calculus_textbook" || parts.Chapter != "Methods of Integration" || parts.Page != 4 {
+		t.Errorf("formula not linked to its source: %+v", parts)
+	}
+
+	ftc := formulas[1]
+	if ftc.Expression != `\int_a^b f(x)\,dx = F(b) - F(a)` || !ftc.Display || ftc.ChunkID != "c2" {
+		t.Errorf("unexpected second formula %+v", ftc)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -9,10 +10,33 @@ import (
 )
 
 func main() {
+	resume := flag.Bool("resume", false, "skip PDFs already ingested according to the checkpoint")
+	reset := flag.Bool("reset", false, "clear the migration checkpoint before running")
+	checkpointPath := flag.String("checkpoint", DefaultCheckpointPath, "path of the PDF migration checkpoint file")
+	validateOnly := flag.Bool("validate-csv", false, "check nodes.csv and edges.csv for orphan, duplicate and self-loop edges and cycles, then exit without migrating")
+	mergeResponses := flag.Bool("merge-query-responses", false, "fold the legacy query_responses collection into queries, then exit without migrating")
+	flag.Parse()
+
+	if *validateOnly {
+		if err := runCSVValidation(nodesCSV, edgesCSV); err != nil {
+			log.Fatalf("❌ CSV validation failed: %v", err)
+		}
+		return
+	}
+
+	pdfOpts := migrationOptions{Resume: *resume, Reset: *reset, CheckpointPath: *checkpointPath}
+
 	// Initialize logger
 	logger.Initialize()
 	_ = logger.MustGetLogger()
 
+	if *mergeResponses {
+		if err := runQueryResponsesMerge(); err != nil {
+			log.Fatalf("❌ Query responses merge failed: %v", err)
+		}
+		return
+	}
+
 	// Check if data directories exist
 	if err := validateDataDirectories(); err != nil {
 		log.Fatalf("❌ Data validation failed: %v", err)
@@ -23,7 +47,8 @@ func main() {
 		fn   func() error
 	}{
 		{"Neo4j (CSV)", runCsvToNeo4jMigration},
-		{"Weaviate (Textbook)", runPDFToWeaviateMigration},
+		{"Weaviate (Textbook)", func() error { return runPDFToWeaviateMigration(pdfOpts) }},
+		{"Weaviate (Concepts)", runConceptsToWeaviateMigration},
 	}
 
 	fmt.Println("🚀 Starting data migration...")
@@ -45,8 +70,8 @@ func main() {
 
 func validateDataDirectories() error {
 	requiredFiles := []string{
-		"data/raw/nodes.csv",
-		"data/raw/edges.csv",
+		nodesCSV,
+		edgesCSV,
 		"data/raw/calculus_textbook.txt",
 	}
 
@@ -13,19 +13,39 @@ import (
 	"github.com/ledongthuc/pdf"
 	"github.com/mathprereq/internal/core/config"
 	"github.com/mathprereq/internal/data/weaviate"
+	"github.com/mathprereq/pkg/chunker"
 )
 
+// DefaultPDFMinChunkLength is the shortest PDF section kept as a chunk
+const DefaultPDFMinChunkLength = 100
+
+// DefaultPDFChunkSize is the most characters packed into one PDF section
+const DefaultPDFChunkSize = 1000
+
 type PDFProcessor struct {
 	client *weaviate.Client
+
+	// MinChunkLength is the minimum trimmed section length kept as a chunk
+	MinChunkLength int
 }
 
 func NewPDFProcessor(client *weaviate.Client) *PDFProcessor {
 	return &PDFProcessor{
-		client: client,
+		client:         client,
+		MinChunkLength: getEnvInt("PDF_MIN_CHUNK_LENGTH", DefaultPDFMinChunkLength),
 	}
 }
 
-func runPDFToWeaviateMigration() error {
+// migrationOptions controls checkpointing of the PDF migration
+type migrationOptions struct {
+	// Resume skips files recorded in the checkpoint by an earlier run
+	Resume bool
+	// Reset clears the checkpoint before running
+	Reset          bool
+	CheckpointPath string
+}
+
+func runPDFToWeaviateMigration(opts migrationOptions) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -39,11 +59,30 @@ func runPDFToWeaviateMigration() error {
 	processor := NewPDFProcessor(client)
 	ctx := context.Background()
 
+	formulaStore, closeFormulaStore := openFormulaStore(cfg)
+	defer closeFormulaStore()
+
 	// Directory containing the PDFs
 	pdfDir := "data/raw/university-curriculus"
 
 	fmt.Println("🚀 Starting PDF to Weaviate migration...")
 
+	if opts.Reset {
+		if err := resetCheckpoint(opts.CheckpointPath); err != nil {
+			return err
+		}
+		fmt.Println("🧹 Cleared migration checkpoint")
+	}
+
+	checkpoint := &MigrationCheckpoint{path: opts.CheckpointPath, Completed: make(map[string]CheckpointEntry)}
+	if opts.Resume {
+		checkpoint, err = loadCheckpoint(opts.CheckpointPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("⏯️  Resuming: %d files already ingested\n", len(checkpoint.Completed))
+	}
+
 	// Get list of PDF files
 	pdfFiles, err := getPDFFiles(pdfDir)
 	if err != nil {
@@ -52,37 +91,63 @@ func runPDFToWeaviateMigration() error {
 
 	fmt.Printf("📁 Found %d PDF files to process\n", len(pdfFiles))
 
-	totalChunks := 0
-	for i, pdfFile := range pdfFiles {
-		fmt.Printf("\n📖 Processing [%d/%d]: %s\n", i+1, len(pdfFiles), filepath.Base(pdfFile))
-
+	totalChunks, err := ingestPDFFiles(pdfFiles, checkpoint, func(pdfFile string) (int, error) {
 		chunks, err := processor.processPDF(pdfFile)
 		if err != nil {
-			log.Printf("❌ Failed to process %s: %v", pdfFile, err)
-			continue
+			return 0, fmt.Errorf("failed to process: %w", err)
 		}
 
 		if len(chunks) == 0 {
-			log.Printf("⚠️  No content extracted from %s", pdfFile)
-			continue
+			return 0, fmt.Errorf("no content extracted")
 		}
 
 		// Add chunks to Weaviate
 		fmt.Printf("💾 Adding %d chunks to Weaviate...\n", len(chunks))
-		err = processor.client.AddContent(ctx, chunks)
-		if err != nil {
-			log.Printf("❌ Failed to add chunks from %s: %v", pdfFile, err)
-			continue
+		if err := processor.client.AddContent(ctx, chunks); err != nil {
+			return 0, fmt.Errorf("failed to add chunks: %w", err)
 		}
-
-		totalChunks += len(chunks)
-		fmt.Printf("✅ Successfully added %d chunks from %s\n", len(chunks), filepath.Base(pdfFile))
+		if err := saveChunkFormulas(ctx, formulaStore, chunks); err != nil {
+			return 0, fmt.Errorf("failed to store formulas: %w", err)
+		}
+		return len(chunks), nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("\n🎉 Migration completed! Total chunks added: %d\n", totalChunks)
 	return nil
 }
 
+// ingestPDFFiles runs ingest for every file not yet in the checkpoint and
+// records each success immediately, so a crash loses at most the current file.
+// Per-file failures are logged and left out of the checkpoint to be retried.
+func ingestPDFFiles(pdfFiles []string, checkpoint *MigrationCheckpoint, ingest func(pdfFile string) (int, error)) (int, error) {
+	totalChunks := 0
+	for i, pdfFile := range pdfFiles {
+		if checkpoint.IsCompleted(pdfFile) {
+			fmt.Printf("⏭️  Skipping [%d/%d]: %s (already ingested)\n", i+1, len(pdfFiles), filepath.Base(pdfFile))
+			continue
+		}
+
+		fmt.Printf("\n📖 Processing [%d/%d]: %s\n", i+1, len(pdfFiles), filepath.Base(pdfFile))
+
+		chunks, err := ingest(pdfFile)
+		if err != nil {
+			log.Printf("❌ %s: %v", pdfFile, err)
+			continue
+		}
+
+		if err := checkpoint.MarkCompleted(pdfFile, chunks); err != nil {
+			return totalChunks, err
+		}
+
+		totalChunks += chunks
+		fmt.Printf("✅ Successfully added %d chunks from %s\n", chunks, filepath.Base(pdfFile))
+	}
+	return totalChunks, nil
+}
+
 func (p *PDFProcessor) processPDF(filePath string) ([]weaviate.ContentChunk, error) {
 	// Extract text from PDF
 	text, err := extractTextFromPDF(filePath)
@@ -258,20 +323,20 @@ func (p *PDFProcessor) createChunksFromText(text string, unitInfo UnitInfo, sour
 		pageSource := source
 		pageSource.Page = pageNum
 
-		// Split page content into smaller chunks (by sections or paragraphs)
-		sections := p.splitIntoSections(pageContent)
+		// Split page content into paragraph sections, skipping very short ones
+		sections := chunker.Split(pageContent, chunker.Options{
+			TargetSize: DefaultPDFChunkSize,
+			MinLength:  p.MinChunkLength,
+			Mode:       chunker.ModeParagraph,
+		})
 
 		for _, section := range sections {
-			if len(strings.TrimSpace(section)) < 100 { // Skip very short sections
-				continue
-			}
-
 			// Detect concepts in this section
-			concepts := p.extractConcepts(section, unitInfo)
+			concepts := p.extractConcepts(section.Text, unitInfo)
 
 			chunk := weaviate.ContentChunk{
-				ID:         uuid.New().String(),
-				Content:    strings.TrimSpace(section),
+				ID:         pdfChunkID(source.Document, chunkIndex),
+				Content:    section.Text,
 				Concept:    strings.Join(concepts, "; "),
 				Chapter:    fmt.Sprintf("Unit %s: %s", unitInfo.Number, unitInfo.Title),
 				Source:     pageSource,
@@ -286,38 +351,10 @@ func (p *PDFProcessor) createChunksFromText(text string, unitInfo UnitInfo, sour
 	return chunks
 }
 
-func (p *PDFProcessor) splitIntoSections(text string) []string {
-	// Split by double newlines (paragraphs)
-	paragraphs := strings.Split(text, "\n\n")
-
-	var sections []string
-	var currentSection strings.Builder
-	maxChunkSize := 1000 // Maximum characters per chunk
-
-	for _, paragraph := range paragraphs {
-		paragraph = strings.TrimSpace(paragraph)
-		if paragraph == "" {
-			continue
-		}
-
-		// If adding this paragraph would exceed max size, finalize current section
-		if currentSection.Len() > 0 && currentSection.Len()+len(paragraph) > maxChunkSize {
-			sections = append(sections, currentSection.String())
-			currentSection.Reset()
-		}
-
-		if currentSection.Len() > 0 {
-			currentSection.WriteString("\n\n")
-		}
-		currentSection.WriteString(paragraph)
-	}
-
-	// Add the last section
-	if currentSection.Len() > 0 {
-		sections = append(sections, currentSection.String())
-	}
-
-	return sections
+// pdfChunkID derives a stable chunk ID from the document and chunk position,
+// so re-running the migration overwrites chunks instead of duplicating them
+func pdfChunkID(document string, chunkIndex int) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("pdf:%s#%d", document, chunkIndex))).String()
 }
 
 func (p *PDFProcessor) extractConcepts(text string, unitInfo UnitInfo) []string {
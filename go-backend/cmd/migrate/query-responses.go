@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/data/mongodb"
+	"github.com/mathprereq/internal/infrastructure/repositories"
+	"github.com/mathprereq/pkg/logger"
+)
+
+// runQueryResponsesMerge folds the legacy query_responses collection into
+// queries, leaving one record per query
+func runQueryResponsesMerge() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client, err := mongodb.NewClient(mongodb.Config{
+		URI:            cfg.MongoDB.URI,
+		Database:       cfg.MongoDB.Database,
+		Username:       cfg.MongoDB.Username,
+		Password:       cfg.MongoDB.Password,
+		ConnectTimeout: cfg.MongoDB.ConnectTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	defer client.Close(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	result, err := repositories.MergeLegacyQueryResponses(ctx, client.GetDatabase(), logger.MustGetLogger())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Merged %d legacy query records: %d already stored, %d moved to queries\n",
+		result.Scanned, result.Duplicates, result.Migrated)
+	return nil
+}
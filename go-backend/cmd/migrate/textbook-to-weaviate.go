@@ -5,11 +5,24 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/mathprereq/internal/core/config"
 	"github.com/mathprereq/internal/data/weaviate"
+	"github.com/mathprereq/pkg/chunker"
+)
+
+// DefaultTextbookMinChunkLength is the shortest textbook chunk kept
+const DefaultTextbookMinChunkLength = 50
+
+// Default textbook chunk packing: lines of one concept are accumulated into
+// chunks of about DefaultTextbookChunkSize characters, each repeating the
+// last DefaultTextbookChunkOverlap characters of the previous one
+const (
+	DefaultTextbookChunkSize    = 1000
+	DefaultTextbookChunkOverlap = 150
 )
 
 func runTextbookToWeaviateMigration() error {
@@ -38,8 +51,18 @@ func runTextbookToWeaviateMigration() error {
 		}
 	}
 
+	opts := chunker.Options{
+		TargetSize: getEnvInt("TEXTBOOK_CHUNK_SIZE", DefaultTextbookChunkSize),
+		Overlap:    getEnvInt("TEXTBOOK_CHUNK_OVERLAP", DefaultTextbookChunkOverlap),
+		MinLength:  getEnvInt("TEXTBOOK_MIN_CHUNK_LENGTH", DefaultTextbookMinChunkLength),
+		Mode:       chunker.ModeLine,
+	}
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid textbook chunking: %w", err)
+	}
+
 	// Load textbook content
-	content, err := loadTextbookContent("data/raw/calculus_textbook.txt")
+	content, err := loadTextbookContent("data/raw/calculus_textbook.txt", opts)
 	if err != nil {
 		return fmt.Errorf("failed to load textbook content: %w", err)
 	}
@@ -49,11 +72,20 @@ func runTextbookToWeaviateMigration() error {
 		return fmt.Errorf("failed to add content to Weaviate: %w", err)
 	}
 
+	formulaStore, closeFormulaStore := openFormulaStore(cfg)
+	defer closeFormulaStore()
+	if err := saveChunkFormulas(ctx, formulaStore, content); err != nil {
+		return fmt.Errorf("failed to store textbook formulas: %w", err)
+	}
+
 	fmt.Printf("✅ Successfully migrated %d chunks to Weaviate\n", len(content))
 	return nil
 }
 
-func loadTextbookContent(filename string) ([]weaviate.ContentChunk, error) {
+// loadTextbookContent reads the textbook file and packs the content lines
+// under each concept header into chunks. Chunks never span a chapter or
+// concept boundary, and those shorter than opts.MinLength are dropped.
+func loadTextbookContent(filename string, opts chunker.Options) ([]weaviate.ContentChunk, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -65,7 +97,7 @@ func loadTextbookContent(filename string) ([]weaviate.ContentChunk, error) {
 
 	var currentChapter string
 	var currentConcept string
-	chunkIndex := 0
+	var sectionLines []string
 
 	// Create proper Source struct
 	textbookSource := weaviate.Source{
@@ -76,6 +108,21 @@ func loadTextbookContent(filename string) ([]weaviate.ContentChunk, error) {
 		Page:     1,
 	}
 
+	// flushSection turns the lines gathered under the current headers into chunks
+	flushSection := func() {
+		for _, section := range chunker.Split(strings.Join(sectionLines, "\n"), opts) {
+			chunks = append(chunks, weaviate.ContentChunk{
+				ID:         uuid.New().String(),
+				Content:    section.Text,
+				Concept:    currentConcept,
+				Chapter:    currentChapter,
+				Source:     textbookSource, // Use Source struct
+				ChunkIndex: len(chunks),
+			})
+		}
+		sectionLines = nil
+	}
+
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 
@@ -85,36 +132,38 @@ func loadTextbookContent(filename string) ([]weaviate.ContentChunk, error) {
 
 		// Detect chapter headers
 		if strings.HasPrefix(line, "Chapter:") {
+			flushSection()
 			currentChapter = strings.TrimPrefix(line, "Chapter:")
 			currentChapter = strings.TrimSpace(currentChapter)
+			currentConcept = ""
 			continue
 		}
 
 		// Detect concept headers (lines ending with colon)
 		if strings.HasSuffix(line, ":") && !strings.Contains(line, ".") {
+			flushSection()
 			currentConcept = strings.TrimSuffix(line, ":")
 			currentConcept = strings.TrimSpace(currentConcept)
 			continue
 		}
 
-		// Create chunk for content lines
-		if len(line) > 50 { // Only meaningful content
-			chunk := weaviate.ContentChunk{
-				ID:         uuid.New().String(),
-				Content:    line,
-				Concept:    currentConcept,
-				Chapter:    currentChapter,
-				Source:     textbookSource, // Use Source struct
-				ChunkIndex: chunkIndex,
-			}
-			chunks = append(chunks, chunk)
-			chunkIndex++
-		}
+		sectionLines = append(sectionLines, line)
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
+	flushSection()
 
 	return chunks, nil
 }
+
+// getEnvInt reads an integer migration setting, falling back to defaultValue
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil && intValue >= 0 {
+			return intValue
+		}
+	}
+	return defaultValue
+}
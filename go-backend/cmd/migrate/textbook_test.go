@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTextbookContentChunksWithinConceptBoundaries(t *testing.T) {
+	lines := []string{
+		"Chapter: Limits",
+		"Limits:",
+		"A limit is the value a function approaches as the input approaches a point.",
+		"One-sided limits consider approach from only the left or the right side.",
+		"Continuity:",
+		"A function is continuous where its limit equals its value at the point.",
+		"Chapter: Derivatives",
+		"The derivative is the limit of the difference quotient as h approaches zero.",
+	}
+	path := filepath.Join(t.TempDir(), "textbook.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := loadTextbookContent(path, textbookTestOptions(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ chapter, concept, content string }{
+		{"Limits", "Limits", lines[2] + "\n" + lines[3]},
+		{"Limits", "Continuity", lines[5]},
+		{"Derivatives", "", lines[7]},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %d: %+v", len(want), len(chunks), chunks)
+	}
+	for i, w := range want {
+		if chunks[i].Chapter != w.chapter || chunks[i].Concept != w.concept || chunks[i].Content != w.content {
+			t.Errorf("chunk %d = {%q %q %q}, want {%q %q %q}", i,
+				chunks[i].Chapter, chunks[i].Concept, chunks[i].Content, w.chapter, w.concept, w.content)
+		}
+		if chunks[i].ChunkIndex != i {
+			t.Errorf("chunk %d has index %d", i, chunks[i].ChunkIndex)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// csvEdge is one row of edges.csv with the line it was read from
+type csvEdge struct {
+	Line   int
+	Source string
+	Target string
+}
+
+func (e csvEdge) String() string {
+	return fmt.Sprintf("line %d: %s -> %s", e.Line, e.Source, e.Target)
+}
+
+// CSVReport lists the defects of a nodes.csv and edges.csv pair that would
+// fail the Neo4j migration or load a bad graph
+type CSVReport struct {
+	Nodes int
+	Edges int
+
+	// OrphanEdges reference a node ID missing from nodes.csv
+	OrphanEdges []csvEdge
+	// DuplicateEdges repeat an earlier source and target pair
+	DuplicateEdges []csvEdge
+	SelfLoops      []csvEdge
+	// Cycles are closed prerequisite chains, each starting and ending with
+	// the same node ID
+	Cycles [][]string
+}
+
+// Valid reports whether the CSVs have no defects
+func (r *CSVReport) Valid() bool {
+	return len(r.OrphanEdges) == 0 && len(r.DuplicateEdges) == 0 && len(r.SelfLoops) == 0 && len(r.Cycles) == 0
+}
+
+// validateCSVGraph checks edges.csv against nodes.csv without touching the
+// database
+func validateCSVGraph(nodesFile, edgesFile string) (*CSVReport, error) {
+	nodeRows, err := readCSVRows(nodesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", nodesFile, err)
+	}
+	edgeRows, err := readCSVRows(edgesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", edgesFile, err)
+	}
+
+	nodeIDs := make([]string, 0, len(nodeRows))
+	for _, row := range nodeRows {
+		nodeIDs = append(nodeIDs, row[0])
+	}
+	edges := make([]csvEdge, 0, len(edgeRows))
+	for i, row := range edgeRows {
+		edges = append(edges, csvEdge{Line: i + 2, Source: row[0], Target: row[1]})
+	}
+
+	return checkCSVGraph(nodeIDs, edges), nil
+}
+
+func checkCSVGraph(nodeIDs []string, edges []csvEdge) *CSVReport {
+	report := &CSVReport{Nodes: len(nodeIDs), Edges: len(edges)}
+
+	known := make(map[string]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		known[id] = true
+	}
+
+	// Only edges that would load cleanly take part in cycle detection
+	adjacency := make(map[string][]string)
+	seen := make(map[[2]string]bool, len(edges))
+	for _, edge := range edges {
+		switch {
+		case !known[edge.Source] || !known[edge.Target]:
+			report.OrphanEdges = append(report.OrphanEdges, edge)
+		case edge.Source == edge.Target:
+			report.SelfLoops = append(report.SelfLoops, edge)
+		case seen[[2]string{edge.Source, edge.Target}]:
+			report.DuplicateEdges = append(report.DuplicateEdges, edge)
+		default:
+			seen[[2]string{edge.Source, edge.Target}] = true
+			adjacency[edge.Source] = append(adjacency[edge.Source], edge.Target)
+		}
+	}
+
+	report.Cycles = findCycles(nodeIDs, adjacency)
+	return report
+}
+
+// findCycles walks the graph depth first and reports the cycle closed by
+// every back edge, visiting nodes in sorted order so reports are stable
+func findCycles(nodeIDs []string, adjacency map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+
+	sorted := append([]string(nil), nodeIDs...)
+	sort.Strings(sorted)
+
+	state := make(map[string]int, len(sorted))
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = onStack
+		stack = append(stack, id)
+		for _, next := range adjacency[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case onStack:
+				start := len(stack) - 1
+				for stack[start] != next {
+					start--
+				}
+				cycle := append([]string(nil), stack[start:]...)
+				cycles = append(cycles, append(cycle, next))
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	for _, id := range sorted {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// runCSVValidation prints the report of the migration CSVs and fails when
+// they have defects
+func runCSVValidation(nodesFile, edgesFile string) error {
+	report, err := validateCSVGraph(nodesFile, edgesFile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔍 Checked %d nodes and %d edges\n", report.Nodes, report.Edges)
+	printEdges("Orphan edges (unknown node ID)", report.OrphanEdges)
+	printEdges("Duplicate edges", report.DuplicateEdges)
+	printEdges("Self-loops", report.SelfLoops)
+	if len(report.Cycles) > 0 {
+		fmt.Printf("  ❌ Cycles: %d\n", len(report.Cycles))
+		for _, cycle := range report.Cycles {
+			fmt.Printf("     %s\n", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if !report.Valid() {
+		return fmt.Errorf("%s has %d orphan, %d duplicate and %d self-loop edges and %d cycles",
+			edgesFile, len(report.OrphanEdges), len(report.DuplicateEdges), len(report.SelfLoops), len(report.Cycles))
+	}
+	fmt.Println("  ✓ No orphan, duplicate or self-loop edges and no cycles")
+	return nil
+}
+
+func printEdges(title string, edges []csvEdge) {
+	if len(edges) == 0 {
+		return
+	}
+	fmt.Printf("  ❌ %s: %d\n", title, len(edges))
+	for _, edge := range edges {
+		fmt.Printf("     %s\n", edge)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const sampleNodesCSV = "node_id,concept_name,description\n" +
+	"func_basics,Basic Functions,Function notation\n" +
+	"limits,Limits,Approaching a value\n" +
+	"derivatives,Derivatives,Rate of change\n"
+
+func writeSampleCSVs(t *testing.T, edges string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	nodesFile := filepath.Join(dir, "nodes.csv")
+	edgesFile := filepath.Join(dir, "edges.csv")
+	if err := os.WriteFile(nodesFile, []byte(sampleNodesCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(edgesFile, []byte("source_id,target_id,relationship_type\n"+edges), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return nodesFile, edgesFile
+}
+
+func TestValidateCSVGraphAcceptsCleanData(t *testing.T) {
+	nodesFile, edgesFile := writeSampleCSVs(t,
+		"func_basics,limits,prerequisite_for\n"+
+			"limits,derivatives,prerequisite_for\n"+
+			"func_basics,derivatives,prerequisite_for\n")
+
+	report, err := validateCSVGraph(nodesFile, edgesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid() || report.Nodes != 3 || report.Edges != 3 {
+		t.Errorf("expected a clean report of 3 nodes and 3 edges, got %+v", report)
+	}
+	if err := runCSVValidation(nodesFile, edgesFile); err != nil {
+		t.Errorf("expected clean data to pass, got %v", err)
+	}
+}
+
+func TestValidateCSVGraphReportsDefects(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges string
+		check func(*CSVReport) bool
+	}{
+		{
+			name:  "orphan edge",
+			edges: "limits,integrals,prerequisite_for\n",
+			check: func(r *CSVReport) bool {
+				return reflect.DeepEqual(r.OrphanEdges, []csvEdge{{Line: 2, Source: "limits", Target: "integrals"}})
+			},
+		},
+		{
+			name:  "duplicate edge",
+			edges: "limits,derivatives,prerequisite_for\n limits , derivatives ,prerequisite_for\n",
+			check: func(r *CSVReport) bool {
+				return reflect.DeepEqual(r.DuplicateEdges, []csvEdge{{Line: 3, Source: "limits", Target: "derivatives"}})
+			},
+		},
+		{
+			name:  "self-loop",
+			edges: "limits,limits,prerequisite_for\n",
+			check: func(r *CSVReport) bool {
+				return reflect.DeepEqual(r.SelfLoops, []csvEdge{{Line: 2, Source: "limits", Target: "limits"}})
+			},
+		},
+		{
+			name: "cycle",
+			edges: "func_basics,limits,prerequisite_for\n" +
+				"limits,derivatives,prerequisite_for\n" +
+				"derivatives,func_basics,prerequisite_for\n",
+			check: func(r *CSVReport) bool {
+				return reflect.DeepEqual(r.Cycles, [][]string{{"derivatives", "func_basics", "limits", "derivatives"}})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodesFile, edgesFile := writeSampleCSVs(t, tt.edges)
+
+			report, err := validateCSVGraph(nodesFile, edgesFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Valid() || !tt.check(report) {
+				t.Errorf("expected the %s to be reported, got %+v", tt.name, report)
+			}
+			if err := runCSVValidation(nodesFile, edgesFile); err == nil {
+				t.Error("expected validation to fail")
+			}
+		})
+	}
+}
+
+func TestValidateCSVGraphRejectsMalformedRows(t *testing.T) {
+	nodesFile, edgesFile := writeSampleCSVs(t, "limits,derivatives\n")
+	if _, err := validateCSVGraph(nodesFile, edgesFile); err == nil {
+		t.Error("expected a row without a relationship type to be rejected")
+	}
+}
@@ -80,12 +80,12 @@ func (s *Server) Start() error {
 
 	// Configure HTTP server
 	s.httpServer = &http.Server{
-		Addr:           fmt.Sprintf("%s:%d", s.config.Server.Host, s.config.Server.Port),
-		Handler:        router,
-		ReadTimeout:    s.config.Server.ReadTimeout,
-		WriteTimeout:   s.config.Server.WriteTimeout,
-		IdleTimeout:    s.config.Server.IdleTimeout,
-		MaxHeaderBytes: int(s.config.Server.MaxBodySize),
+		Addr:         fmt.Sprintf("%s:%d", s.config.Server.Host, s.config.Server.Port),
+		Handler:      router,
+		ReadTimeout:  s.config.Server.ReadTimeout,
+		WriteTimeout: s.config.Server.WriteTimeout,
+		IdleTimeout:  s.config.Server.IdleTimeout,
+		// Request bodies are capped by the BodyLimit middleware
 	}
 
 	// Health check before starting
@@ -93,6 +93,15 @@ func (s *Server) Start() error {
 	if err := s.performStartupHealthCheck(); err != nil {
 		return fmt.Errorf("startup health check failed: %w", err)
 	}
+	s.performStartupSelfCheck()
+
+	// Start periodic maintenance jobs registered by the container and the
+	// persistent scrape queue workers, if enabled
+	s.container.Scheduler().Start()
+	s.container.ScrapeQueue().Start()
+
+	// Warm the concept autocomplete index so the first keystrokes are fast
+	go s.warmConceptIndex()
 
 	// Start server in a goroutine
 	go func() {
@@ -112,6 +121,17 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// warmConceptIndex builds the autocomplete index in the background. On
+// failure the first autocomplete request builds it instead.
+func (s *Server) warmConceptIndex() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := s.container.QueryService().RefreshConceptIndex(ctx); err != nil {
+		s.logger.Warn("Failed to warm concept autocomplete index", zap.Error(err))
+	}
+}
+
 func (s *Server) performStartupHealthCheck() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -137,6 +157,44 @@ func (s *Server) performStartupHealthCheck() error {
 	return nil
 }
 
+// performStartupSelfCheck verifies the dependencies hold usable data and logs
+// a readiness summary. Failures never stop startup: when the self-check is
+// required /ready reports unavailable and a scheduled job rechecks until it
+// passes.
+func (s *Server) performStartupSelfCheck() {
+	s.logger.Info("Running startup self-check...")
+	report := s.container.SelfCheck(context.Background())
+
+	for _, result := range report.Results {
+		if result.Passed {
+			s.logger.Info("Self-check passed",
+				zap.String("check", result.Name),
+				zap.Duration("duration", result.Duration))
+		} else {
+			s.logger.Error("Self-check failed",
+				zap.String("check", result.Name),
+				zap.String("error", result.Error),
+				zap.Duration("duration", result.Duration))
+		}
+	}
+
+	readiness := s.container.Readiness()
+	summary := []zap.Field{
+		zap.Int("checks", len(report.Results)),
+		zap.Strings("failed", report.Failed()),
+		zap.Bool("required", readiness.Required()),
+		zap.Bool("ready", readiness.Ready()),
+	}
+	switch {
+	case report.Passed:
+		s.logger.Info("Startup self-check passed, ready to serve", summary...)
+	case readiness.Required():
+		s.logger.Error("Startup self-check failed, not ready until it passes", summary...)
+	default:
+		s.logger.Warn("Startup self-check failed, serving anyway", summary...)
+	}
+}
+
 func (s *Server) WaitForShutdown() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
@@ -148,27 +206,14 @@ func (s *Server) WaitForShutdown() {
 }
 
 func (s *Server) Shutdown() {
-	s.logger.Info("Starting graceful shutdown...")
+	s.logger.Info("Starting graceful shutdown...",
+		zap.Duration("timeout", s.config.Server.ShutdownTimeout))
 
 	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Server.ShutdownTimeout)
 	defer cancel()
 
-	// Shutdown HTTP server
-	s.logger.Info("Shutting down HTTP server...")
-	if err := s.httpServer.Shutdown(ctx); err != nil {
-		s.logger.Error("HTTP server shutdown error", zap.Error(err))
-	} else {
-		s.logger.Info("HTTP server shutdown completed")
-	}
-
-	// Shutdown application container
-	s.logger.Info("Shutting down application services...")
-	if err := s.container.Shutdown(ctx); err != nil {
-		s.logger.Error("Container shutdown error", zap.Error(err))
-	} else {
-		s.logger.Info("Container shutdown completed")
-	}
+	runShutdownSteps(ctx, s.logger, s.shutdownSteps())
 
 	// Final log sync
 	s.logger.Info("Graceful shutdown completed")
@@ -176,3 +221,53 @@ func (s *Server) Shutdown() {
 
 	s.logger.Info("Server stopped")
 }
+
+// shutdownStep is one stage of the graceful shutdown sequence
+type shutdownStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// shutdownSteps returns the shutdown sequence in order: stop accepting new
+// requests and wait for in-flight ones, stop scheduled jobs, drain background
+// tasks, then close database clients once nothing can use them anymore.
+func (s *Server) shutdownSteps() []shutdownStep {
+	return []shutdownStep{
+		{
+			name: "HTTP server",
+			run:  s.httpServer.Shutdown,
+		},
+		{
+			name: "scheduled jobs",
+			run:  s.container.Scheduler().Stop,
+		},
+		{
+			name: "background tasks",
+			run: func(ctx context.Context) error {
+				if pending := s.container.DrainBackgroundTasks(ctx); len(pending) > 0 {
+					return fmt.Errorf("%d background tasks still running: %v", len(pending), pending)
+				}
+				return nil
+			},
+		},
+		{
+			name: "application services",
+			run:  s.container.Shutdown,
+		},
+	}
+}
+
+// runShutdownSteps runs every step in order. A failing or timed-out step is
+// logged and does not stop later steps, so clients are always closed.
+func runShutdownSteps(ctx context.Context, log *zap.Logger, steps []shutdownStep) {
+	for _, step := range steps {
+		log.Info("Shutting down " + step.name + "...")
+		if err := step.run(ctx); err != nil {
+			log.Error("Shutdown step did not complete cleanly",
+				zap.String("step", step.name),
+				zap.Error(err))
+			continue
+		}
+		log.Info("Shutdown step completed", zap.String("step", step.name))
+	}
+}
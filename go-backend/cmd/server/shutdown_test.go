@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRunShutdownStepsOrder(t *testing.T) {
+	var order []string
+	record := func(name string, err error) shutdownStep {
+		return shutdownStep{name: name, run: func(context.Context) error {
+			order = append(order, name)
+			return err
+		}}
+	}
+
+	steps := []shutdownStep{
+		record("http", nil),
+		record("background", errors.New("2 background tasks still running")),
+		record("databases", nil),
+	}
+	runShutdownSteps(context.Background(), zap.NewNop(), steps)
+
+	want := []string{"http", "background", "databases"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("shutdown order = %v, want %v", order, want)
+	}
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mathprereq/internal/domain/services"
@@ -10,14 +13,30 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	defaultUnknownConceptsLimit = 50
+	maxUnknownConceptsLimit     = 500
+
+	defaultDescriptionBackfillLimit = 20
+	maxDescriptionBackfillLimit     = 100
+
+	defaultCalibrationDays       = 30
+	defaultCalibrationMinQueries = 10
+
+	defaultAliasSuggestionDays       = 30
+	defaultAliasSuggestionMinQueries = 5
+)
+
 type AdminHandler struct {
 	queryService services.QueryService
+	staleAfter   time.Duration
 	logger       *zap.Logger
 }
 
-func NewAdminHandler(queryService services.QueryService, logger *zap.Logger) *AdminHandler {
+func NewAdminHandler(queryService services.QueryService, staleAfter time.Duration, logger *zap.Logger) *AdminHandler {
 	return &AdminHandler{
 		queryService: queryService,
+		staleAfter:   staleAfter,
 		logger:       logger,
 	}
 }
@@ -31,15 +50,59 @@ func (h *AdminHandler) GetPendingConcepts(c *gin.Context) {
 	concepts, err := h.queryService.GetPendingConcepts(c.Request.Context(), limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get pending concepts", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pending concepts"})
+		respondError(c, http.StatusInternalServerError, "Failed to get pending concepts")
+		return
+	}
+
+	respond(c, http.StatusOK, concepts)
+}
+
+// GetStagedConcept returns a staged concept with its prerequisite matches
+// GET /api/v1/admin/staged-concepts/:id
+func (h *AdminHandler) GetStagedConcept(c *gin.Context) {
+	stagedID := c.Param("id")
+
+	concept, err := h.queryService.GetStagedConcept(c.Request.Context(), stagedID)
+	if err != nil {
+		h.logger.Error("Failed to get staged concept",
+			zap.String("staged_id", stagedID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get staged concept")
+		return
+	}
+	if concept == nil {
+		respondError(c, http.StatusNotFound, "Staged concept not found")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    concepts,
-		"total":   len(concepts),
-	})
+	respond(c, http.StatusOK, concept)
+}
+
+// PreviewApproval returns the concept and prerequisite relationships
+// approving a staged concept would create, without creating them
+// GET /api/v1/admin/staged-concepts/:id/approval-preview
+func (h *AdminHandler) PreviewApproval(c *gin.Context) {
+	stagedID := c.Param("id")
+
+	preview, err := h.queryService.PreviewStagedConceptApproval(c.Request.Context(), stagedID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to preview approval"
+		switch {
+		case strings.Contains(err.Error(), "staged concept not found"):
+			status, message = http.StatusNotFound, "Staged concept not found"
+		case strings.Contains(err.Error(), "already been reviewed"):
+			status, message = http.StatusConflict, "Concept has already been reviewed"
+		}
+
+		h.logger.Error("Failed to preview staged concept approval",
+			zap.String("staged_id", stagedID),
+			zap.Error(err))
+		respondError(c, status, message)
+		return
+	}
+
+	respond(c, http.StatusOK, preview)
 }
 
 // GetStagedConceptStats returns statistics about staged concepts
@@ -48,14 +111,212 @@ func (h *AdminHandler) GetStagedConceptStats(c *gin.Context) {
 	stats, err := h.queryService.GetStagedConceptStats(c.Request.Context())
 	if err != nil {
 		h.logger.Error("Failed to get staged concept stats", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get stats"})
+		respondError(c, http.StatusInternalServerError, "Failed to get stats")
+		return
+	}
+
+	respond(c, http.StatusOK, stats)
+}
+
+// GetStaleConcepts reports pending concepts that have waited too long for review
+// GET /api/v1/admin/staged-concepts/stale?max_age_days=14
+func (h *AdminHandler) GetStaleConcepts(c *gin.Context) {
+	maxAge := h.staleAfter
+	if days := c.Query("max_age_days"); days != "" {
+		parsed, err := strconv.ParseFloat(days, 64)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "max_age_days must be a positive number")
+			return
+		}
+		maxAge = time.Duration(parsed * float64(24*time.Hour))
+	}
+
+	stats, err := h.queryService.GetStaleStagedConcepts(c.Request.Context(), maxAge)
+	if err != nil {
+		h.logger.Error("Failed to get stale staged concepts", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get stale concepts")
+		return
+	}
+
+	respond(c, http.StatusOK, stats)
+}
+
+// GetUnknownConcepts lists concepts identified in queries that are missing
+// from the graph and not yet staged, with how often they were mentioned
+// GET /api/v1/admin/unknown-concepts?limit=50
+func (h *AdminHandler) GetUnknownConcepts(c *gin.Context) {
+	limit := defaultUnknownConceptsLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxUnknownConceptsLimit {
+		limit = maxUnknownConceptsLimit
+	}
+
+	concepts, err := h.queryService.GetUnknownConcepts(c.Request.Context(), limit)
+	if err != nil {
+		h.logger.Error("Failed to get unknown concepts", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get unknown concepts")
+		return
+	}
+
+	respond(c, http.StatusOK, concepts)
+}
+
+// GetDifficultyCalibration suggests concept difficulty changes from how often
+// queries about each concept were answered successfully. Nothing is applied.
+// GET /api/v1/admin/difficulty-calibration?days=30&min_queries=10
+func (h *AdminHandler) GetDifficultyCalibration(c *gin.Context) {
+	days := defaultCalibrationDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "days must be a positive integer")
+			return
+		}
+		days = parsed
+	}
+	minQueries := defaultCalibrationMinQueries
+	if raw := c.Query("min_queries"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "min_queries must be a positive integer")
+			return
+		}
+		minQueries = parsed
+	}
+
+	calibration, err := h.queryService.GetDifficultyCalibration(c.Request.Context(),
+		time.Duration(days)*24*time.Hour, minQueries)
+	if err != nil {
+		h.logger.Error("Failed to compute difficulty calibration", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to compute difficulty calibration")
+		return
+	}
+
+	respond(c, http.StatusOK, calibration)
+}
+
+// GetConceptAliasSuggestions suggests aliases from terms students use for a
+// concept without naming it. Nothing is added.
+// GET /api/v1/admin/concept-aliases/suggestions?days=30&min_queries=5
+func (h *AdminHandler) GetConceptAliasSuggestions(c *gin.Context) {
+	days := defaultAliasSuggestionDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "days must be a positive integer")
+			return
+		}
+		days = parsed
+	}
+	minQueries := defaultAliasSuggestionMinQueries
+	if raw := c.Query("min_queries"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "min_queries must be a positive integer")
+			return
+		}
+		minQueries = parsed
+	}
+
+	suggestions, err := h.queryService.SuggestConceptAliases(c.Request.Context(),
+		time.Duration(days)*24*time.Hour, minQueries)
+	if err != nil {
+		h.logger.Error("Failed to compute concept alias suggestions", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to compute concept alias suggestions")
+		return
+	}
+
+	respond(c, http.StatusOK, suggestions)
+}
+
+// GetConceptAliases lists every concept alias
+// GET /api/v1/admin/concept-aliases
+func (h *AdminHandler) GetConceptAliases(c *gin.Context) {
+	aliases, err := h.queryService.ListConceptAliases(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list concept aliases", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to list concept aliases")
+		return
+	}
+
+	respond(c, http.StatusOK, aliases)
+}
+
+type AddConceptAliasRequest struct {
+	ConceptID string `json:"concept_id" binding:"required"`
+	Alias     string `json:"alias" binding:"required,max=100"`
+	AddedBy   string `json:"added_by"`
+}
+
+// AddConceptAlias adds an alias to a concept, typically an accepted
+// suggestion posted back as is
+// POST /api/v1/admin/concept-aliases
+func (h *AdminHandler) AddConceptAlias(c *gin.Context) {
+	var req AddConceptAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, bindErrorStatus(err), err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Alias) == "" {
+		respondError(c, http.StatusBadRequest, "alias must not be blank")
+		return
+	}
+
+	alias, err := h.queryService.AddConceptAlias(c.Request.Context(), req.ConceptID, req.Alias, req.AddedBy)
+	if err != nil {
+		h.logger.Error("Failed to add concept alias",
+			zap.String("concept_id", req.ConceptID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to add concept alias")
+		return
+	}
+	if alias == nil {
+		respondError(c, http.StatusNotFound, "Concept not found")
+		return
+	}
+
+	h.logger.Info("Concept alias added",
+		zap.String("alias", alias.Alias),
+		zap.String("concept_id", alias.ConceptID))
+
+	respond(c, http.StatusCreated, alias)
+}
+
+// GetExperiment describes the running A/B experiment and its split
+// GET /api/v1/admin/experiment
+func (h *AdminHandler) GetExperiment(c *gin.Context) {
+	experiment := h.queryService.GetExperiment(c.Request.Context())
+	if experiment == nil {
+		respondError(c, http.StatusNotFound, "No experiment is running")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"stats":   stats,
-	})
+	respond(c, http.StatusOK, experiment)
+}
+
+// GetExperimentResults compares success, fallback rate and latency across
+// an experiment's variants; name defaults to the running experiment
+// GET /api/v1/admin/experiment/results?name=explanation-style
+func (h *AdminHandler) GetExperimentResults(c *gin.Context) {
+	results, err := h.queryService.GetExperimentResults(c.Request.Context(), c.Query("name"))
+	if err != nil {
+		h.logger.Error("Failed to get experiment results", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get experiment results")
+		return
+	}
+	if results == nil {
+		respondError(c, http.StatusNotFound, "No experiment is running; pass name to read a past one")
+		return
+	}
+
+	respond(c, http.StatusOK, results)
 }
 
 type ReviewConceptRequest struct {
@@ -74,7 +335,7 @@ func (h *AdminHandler) ReviewStagedConcept(c *gin.Context) {
 
 	var req ReviewConceptRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, bindErrorStatus(err), err.Error())
 		return
 	}
 
@@ -102,7 +363,7 @@ func (h *AdminHandler) ReviewStagedConcept(c *gin.Context) {
 
 	case "merge":
 		if req.ExistingConceptID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "existing_concept_id required for merge action"})
+			respondError(c, http.StatusBadRequest, "existing_concept_id required for merge action")
 			return
 		}
 		err = h.queryService.MergeStagedConcept(
@@ -115,7 +376,7 @@ func (h *AdminHandler) ReviewStagedConcept(c *gin.Context) {
 		message = "Concept merged with existing concept"
 
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action"})
+		respondError(c, http.StatusBadRequest, "Invalid action")
 		return
 	}
 
@@ -124,7 +385,7 @@ func (h *AdminHandler) ReviewStagedConcept(c *gin.Context) {
 			zap.String("staged_id", stagedID),
 			zap.String("action", req.Action),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -133,8 +394,170 @@ func (h *AdminHandler) ReviewStagedConcept(c *gin.Context) {
 		zap.String("action", req.Action),
 		zap.String("reviewer", req.ReviewerID))
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": message,
-	})
+	respond(c, http.StatusOK, gin.H{"message": message})
+}
+
+// GenerateConceptDescriptions generates descriptions for concepts that have
+// none. With dry_run=true the proposals are returned without being applied.
+// POST /api/v1/admin/concept-descriptions/generate?limit=20&dry_run=true
+func (h *AdminHandler) GenerateConceptDescriptions(c *gin.Context) {
+	limit := defaultDescriptionBackfillLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxDescriptionBackfillLimit {
+		limit = maxDescriptionBackfillLimit
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "dry_run must be true or false")
+		return
+	}
+
+	result, err := h.queryService.BackfillConceptDescriptions(c.Request.Context(), limit, dryRun)
+	if err != nil {
+		h.logger.Error("Failed to generate concept descriptions", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to generate concept descriptions")
+		return
+	}
+
+	respond(c, http.StatusOK, result)
+}
+
+// GetExplanationVersions lists the stored explanation versions of a concept
+// GET /api/v1/admin/explanations/:concept/versions
+func (h *AdminHandler) GetExplanationVersions(c *gin.Context) {
+	concept := c.Param("concept")
+
+	versions, err := h.queryService.ListExplanationVersions(c.Request.Context(), concept)
+	if err != nil {
+		h.logger.Error("Failed to list explanation versions",
+			zap.String("concept", concept),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to list explanation versions")
+		return
+	}
+
+	respond(c, http.StatusOK, versions)
+}
+
+// ActivateExplanationVersion serves a chosen explanation version for a concept
+// POST /api/v1/admin/explanations/:concept/versions/:version/activate
+func (h *AdminHandler) ActivateExplanationVersion(c *gin.Context) {
+	concept := c.Param("concept")
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		respondError(c, http.StatusBadRequest, "version must be a positive integer")
+		return
+	}
+
+	activated, err := h.queryService.ActivateExplanationVersion(c.Request.Context(), concept, version)
+	if err != nil {
+		h.logger.Error("Failed to activate explanation version",
+			zap.String("concept", concept),
+			zap.Int("version", version),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to activate explanation version")
+		return
+	}
+	if activated == nil {
+		respondError(c, http.StatusNotFound, "Explanation version not found")
+		return
+	}
+
+	h.logger.Info("Explanation version activated",
+		zap.String("concept", concept),
+		zap.Int("version", version))
+
+	respond(c, http.StatusOK, activated)
+}
+
+// RefreshPrerequisiteClosures recomputes every concept's precomputed
+// transitive prerequisite set
+// POST /api/v1/admin/prerequisite-closures/refresh
+func (h *AdminHandler) RefreshPrerequisiteClosures(c *gin.Context) {
+	count, err := h.queryService.RefreshPrerequisiteClosures(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to refresh prerequisite closures", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to refresh prerequisite closures")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"concepts": count})
+}
+
+type PrerequisiteWeightRequest struct {
+	ConceptID      string  `json:"concept_id" binding:"required"`
+	PrerequisiteID string  `json:"prerequisite_id" binding:"required"`
+	Weight         float64 `json:"weight" binding:"gt=0,lte=1"`
+}
+
+// SetPrerequisiteWeight sets how essential a prerequisite is to a concept,
+// above 0 and at most 1; learning paths mark light ones as helpful
+// PUT /api/v1/admin/prerequisites/weight
+func (h *AdminHandler) SetPrerequisiteWeight(c *gin.Context) {
+	var req PrerequisiteWeightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, bindErrorStatus(err), err.Error())
+		return
+	}
+
+	err := h.queryService.SetPrerequisiteWeight(c.Request.Context(), req.ConceptID, req.PrerequisiteID, req.Weight)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			respondError(c, http.StatusNotFound, "Prerequisite relationship not found")
+			return
+		}
+		h.logger.Error("Failed to set prerequisite weight",
+			zap.String("concept_id", req.ConceptID),
+			zap.String("prerequisite_id", req.PrerequisiteID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to set prerequisite weight")
+		return
+	}
+
+	respond(c, http.StatusOK, req)
+}
+
+type MergeConceptsRequest struct {
+	SourceID string `json:"source_id" binding:"required"`
+	TargetID string `json:"target_id" binding:"required"`
+}
+
+// MergeConcepts folds a duplicate concept into another: its prerequisite
+// edges, resources and aliases move to the target, its versions, profile
+// and path snapshots are dropped, and it is deleted
+// POST /api/v1/admin/concepts/merge
+func (h *AdminHandler) MergeConcepts(c *gin.Context) {
+	var req MergeConceptsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, bindErrorStatus(err), err.Error())
+		return
+	}
+
+	result, err := h.queryService.MergeConcepts(c.Request.Context(), req.SourceID, req.TargetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMergeIntoItself):
+			respondError(c, http.StatusBadRequest, "source_id and target_id must differ")
+			return
+		case errors.Is(err, services.ErrConceptNotFound):
+			respondError(c, http.StatusNotFound, "Concept not found")
+			return
+		}
+		h.logger.Error("Failed to merge concepts",
+			zap.String("source_id", req.SourceID),
+			zap.String("target_id", req.TargetID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to merge concepts")
+		return
+	}
+
+	respond(c, http.StatusOK, result)
 }
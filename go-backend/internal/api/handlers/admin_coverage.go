@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// defaultCoverageCertainty is how close a chunk must be to a concept's name
+// to count as related when the request does not say
+const defaultCoverageCertainty = 0.8
+
+// GetConceptCoverage reports how many textbook chunks support each graph
+// concept and which textbook concepts the graph lacks. semantic=false skips
+// counting semantically related chunks, which takes a search per concept.
+// GET /api/v1/admin/coverage?semantic=true&min_certainty=0.8
+func (h *AdminHandler) GetConceptCoverage(c *gin.Context) {
+	semantic, err := strconv.ParseBool(c.DefaultQuery("semantic", "true"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "semantic must be true or false")
+		return
+	}
+	minCertainty := defaultCoverageCertainty
+	if raw := c.Query("min_certainty"); raw != "" {
+		minCertainty, err = strconv.ParseFloat(raw, 64)
+		if err != nil || minCertainty <= 0 || minCertainty > 1 {
+			respondError(c, http.StatusBadRequest, "min_certainty must be a number above 0 and at most 1")
+			return
+		}
+	}
+	if !semantic {
+		minCertainty = 0
+	}
+
+	report, err := h.queryService.GetConceptCoverage(c.Request.Context(), minCertainty)
+	if err != nil {
+		h.logger.Error("Failed to build coverage report", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to build coverage report")
+		return
+	}
+
+	respond(c, http.StatusOK, report)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ValidatePrerequisiteEdge reports whether an edge making prerequisite a
+// prerequisite of source could be added: both concepts must exist, and the
+// edge must be neither a duplicate nor close a cycle. Nothing is written.
+// GET /api/v1/admin/edges/validate?source=derivatives&prerequisite=limits
+func (h *AdminHandler) ValidatePrerequisiteEdge(c *gin.Context) {
+	source := strings.TrimSpace(c.Query("source"))
+	prerequisite := strings.TrimSpace(c.Query("prerequisite"))
+	if source == "" || prerequisite == "" {
+		respondError(c, http.StatusBadRequest, "source and prerequisite concept IDs are required")
+		return
+	}
+
+	verdict, err := h.queryService.ValidatePrerequisiteEdge(c.Request.Context(), source, prerequisite)
+	if err != nil {
+		h.logger.Error("Failed to validate prerequisite edge",
+			zap.String("source", source),
+			zap.String("prerequisite", prerequisite),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to validate edge")
+		return
+	}
+
+	respond(c, http.StatusOK, verdict)
+}
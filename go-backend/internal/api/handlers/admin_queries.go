@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultQueryListLimit = 50
+	maxQueryListLimit     = 200
+)
+
+// ListQueries lists stored queries of every user, newest first. The number
+// of matches across all pages is sent in X-Total-Count.
+// GET /api/v1/admin/queries?status=failed&since=2025-01-01&until=2025-01-31&user_id=u1&limit=50&offset=0
+func (h *AdminHandler) ListQueries(c *gin.Context) {
+	filter, err := parseQueryListFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	queries, total, err := h.queryService.ListQueries(c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not available") {
+			status = http.StatusServiceUnavailable
+		}
+		h.logger.Error("Failed to list queries", zap.Error(err))
+		respondError(c, status, "Failed to list queries")
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	respond(c, http.StatusOK, queries)
+}
+
+// parseQueryListFilter reads the listing filters from the query string.
+// Dates are RFC 3339 times or YYYY-MM-DD days; an until day is inclusive.
+func parseQueryListFilter(c *gin.Context) (repositories.QueryListFilter, error) {
+	filter := repositories.QueryListFilter{
+		UserID: strings.TrimSpace(c.Query("user_id")),
+		Limit:  defaultQueryListLimit,
+	}
+
+	switch status := c.Query("status"); status {
+	case "", repositories.QueryListSuccess, repositories.QueryListFallback, repositories.QueryListFailed:
+		filter.Status = status
+	default:
+		return filter, fmt.Errorf("status must be one of %s, %s or %s",
+			repositories.QueryListSuccess, repositories.QueryListFallback, repositories.QueryListFailed)
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxQueryListLimit {
+			return filter, fmt.Errorf("limit must be an integer between 1 and %d", maxQueryListLimit)
+		}
+		filter.Limit = parsed
+	}
+	if raw := c.Query("offset"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return filter, fmt.Errorf("offset must be an integer of at least 0")
+		}
+		filter.Offset = parsed
+	}
+
+	var err error
+	if filter.Since, err = parseListTime(c.Query("since"), false); err != nil {
+		return filter, fmt.Errorf("since %v", err)
+	}
+	if filter.Until, err = parseListTime(c.Query("until"), true); err != nil {
+		return filter, fmt.Errorf("until %v", err)
+	}
+	if filter.Since != nil && filter.Until != nil && !filter.Since.Before(*filter.Until) {
+		return filter, fmt.Errorf("since must be before until")
+	}
+
+	return filter, nil
+}
+
+// parseListTime parses an RFC 3339 time or a day. With endOfDay a day
+// means the start of the next day, so the whole day is included.
+func parseListTime(raw string, endOfDay bool) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	day, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, fmt.Errorf("must be an RFC 3339 time or a YYYY-MM-DD date")
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+	return &day, nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/domain/repositories"
+)
+
+func parseQueryListFilterFrom(t *testing.T, query string) (repositories.QueryListFilter, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/admin/queries?"+query, nil)
+	return parseQueryListFilter(c)
+}
+
+func TestParseQueryListFilter(t *testing.T) {
+	filter, err := parseQueryListFilterFrom(t,
+		"status=failed&since=2025-01-01&until=2025-01-31&user_id=u1&limit=20&offset=40")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filter.Status != repositories.QueryListFailed || filter.UserID != "u1" {
+		t.Errorf("unexpected filters %+v", filter)
+	}
+	if filter.Limit != 20 || filter.Offset != 40 {
+		t.Errorf("unexpected page limit=%d offset=%d", filter.Limit, filter.Offset)
+	}
+	// The until day is included in full
+	if !filter.Since.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+		!filter.Until.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected range %v - %v", filter.Since, filter.Until)
+	}
+}
+
+func TestParseQueryListFilterDefaults(t *testing.T) {
+	filter, err := parseQueryListFilterFrom(t, "since=2025-01-01T12:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.UserID != "" || filter.Status != "" || filter.Until != nil || filter.Limit != defaultQueryListLimit {
+		t.Errorf("expected every user and status, got %+v", filter)
+	}
+	if !filter.Since.Equal(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected since %v", filter.Since)
+	}
+}
+
+func TestParseQueryListFilterRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantError string
+	}{
+		{"status=cached", "status must be one of success, fallback or failed"},
+		{"limit=0", "limit must be an integer between 1 and 200"},
+		{"offset=-5", "offset must be an integer of at least 0"},
+		{"since=yesterday", "since must be an RFC 3339 time or a YYYY-MM-DD date"},
+		{"since=2025-02-01&until=2025-01-01", "since must be before until"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseQueryListFilterFrom(t, tt.query)
+		if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+			t.Errorf("%s: expected error %q, got %v", tt.query, tt.wantError, err)
+		}
+	}
+}
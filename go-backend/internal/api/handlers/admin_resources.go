@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/data/scraper"
+	"go.uber.org/zap"
+)
+
+type PinResourceRequest struct {
+	Pinned *bool `json:"pinned" binding:"required"`
+	// Order ranks pinned resources of a concept, lowest first
+	Order int `json:"order" binding:"min=0"`
+}
+
+// PinResource pins a resource so it is listed before scraped ones for its
+// concept, regardless of quality score, or unpins it
+// PUT /api/v1/admin/resources/:id/pin
+func (h *AdminHandler) PinResource(c *gin.Context) {
+	var req PinResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, bindErrorStatus(err), err.Error())
+		return
+	}
+
+	resourceID := c.Param("id")
+	resource, err := h.queryService.PinResource(c.Request.Context(), resourceID, *req.Pinned, req.Order)
+	if errors.Is(err, scraper.ErrInvalidResourceID) {
+		respondError(c, http.StatusBadRequest, "Invalid resource ID")
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not available") {
+			status = http.StatusServiceUnavailable
+		}
+		h.logger.Error("Failed to pin resource",
+			zap.String("resource_id", resourceID),
+			zap.Error(err))
+		respondError(c, status, "Failed to pin resource")
+		return
+	}
+	if resource == nil {
+		respondError(c, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	h.logger.Info("Resource pin updated",
+		zap.String("resource_id", resourceID),
+		zap.String("concept_id", resource.ConceptID),
+		zap.Bool("pinned", resource.Pinned),
+		zap.Int("order", resource.PinOrder))
+
+	respond(c, http.StatusOK, resource)
+}
+
+// Videos enriched per backfill request by default and at most
+const (
+	defaultVideoBackfillLimit = 50
+	maxVideoBackfillLimit     = 200
+)
+
+// EnrichVideoMetadata fetches the thumbnail, duration, channel and publish
+// date of stored videos whose metadata was never checked
+// POST /api/v1/admin/resources/enrich-videos?limit=50
+func (h *AdminHandler) EnrichVideoMetadata(c *gin.Context) {
+	limit := defaultVideoBackfillLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxVideoBackfillLimit {
+		limit = maxVideoBackfillLimit
+	}
+
+	result, err := h.queryService.BackfillVideoMetadata(c.Request.Context(), limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not available") {
+			status = http.StatusServiceUnavailable
+		}
+		h.logger.Error("Failed to enrich video metadata", zap.Error(err))
+		respondError(c, status, "Failed to enrich video metadata")
+		return
+	}
+
+	respond(c, http.StatusOK, result)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bindStrictJSON decodes the request body like ShouldBindJSON but rejects
+// unknown fields, so misspelled field names fail instead of being ignored
+func bindStrictJSON(c *gin.Context, obj interface{}) error {
+	if c.Request.Body == nil {
+		return fmt.Errorf("request body is required")
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("request body is required")
+		}
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("request body must contain a single JSON object")
+	}
+
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// bindErrorStatus maps a binding error to 413 when the body exceeded the
+// size limit and 400 otherwise
+func bindErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/middleware"
+	"github.com/mathprereq/internal/api/models"
+)
+
+func newBindingRouter(maxBytes int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.BodyLimit(maxBytes))
+	router.POST("/strict", func(c *gin.Context) {
+		var req models.QueryRequest
+		if err := bindStrictJSON(c, &req); err != nil {
+			c.JSON(bindErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, req)
+	})
+	return router
+}
+
+func TestBindStrictJSON(t *testing.T) {
+	router := newBindingRouter(256)
+
+	tests := []struct {
+		name          string
+		body          string
+		unknownLength bool
+		wantStatus    int
+		wantError     string
+	}{
+		{"valid", `{"question":"What is a limit?","language":"es"}`, false, http.StatusOK, ""},
+		{"unknown field", `{"question":"What is a limit?","langauge":"es"}`, false, http.StatusBadRequest, "unknown field"},
+		{"missing body", ``, false, http.StatusBadRequest, "request body is required"},
+		{"trailing data", `{"question":"What is a limit?"}{}`, false, http.StatusBadRequest, "single JSON object"},
+		{"oversized", `{"question":"` + strings.Repeat("x", 300) + `"}`, true, http.StatusRequestEntityTooLarge, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/strict", strings.NewReader(tt.body))
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantError != "" && !strings.Contains(rec.Body.String(), tt.wantError) {
+				t.Errorf("expected error containing %q, got %s", tt.wantError, rec.Body.String())
+			}
+		})
+	}
+}
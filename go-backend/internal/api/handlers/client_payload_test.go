@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/container"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/data/scraper"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// stubContainer serves a stub query service; other methods are unused
+type stubContainer struct {
+	container.Container
+	queryService services.QueryService
+}
+
+func (c *stubContainer) QueryService() services.QueryService { return c.queryService }
+
+type payloadQueryService struct {
+	services.QueryService
+	resourceLimit int
+	resourceCalls int
+}
+
+func (s *payloadQueryService) ProcessQuery(ctx context.Context, req *services.QueryRequest) (*services.QueryResult, error) {
+	return &services.QueryResult{
+		Query:       entities.NewQuery(req.UserID, req.Question, req.RequestID),
+		Explanation: "A derivative is a rate of change.",
+		Status:      "complete",
+	}, nil
+}
+
+func (s *payloadQueryService) SmartConceptQuery(ctx context.Context, conceptName, userID, requestID string) (*services.QueryResult, error) {
+	return &services.QueryResult{
+		IdentifiedConcepts: []string{conceptName},
+		PrerequisitePath:   []types.Concept{{ID: "limits", Name: "Limits"}},
+		Status:             "complete",
+	}, nil
+}
+
+func (s *payloadQueryService) GetResourcesForConcepts(ctx context.Context, conceptNames []string, limit int) ([]scraper.EducationalResource, error) {
+	s.resourceCalls++
+	s.resourceLimit = limit
+	return nil, nil
+}
+
+// TestHandlersAcceptClientPayloads posts the request bodies the web clients
+// in client/ and frontend/mathprereq/ send
+func TestHandlersAcceptClientPayloads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name, path, body  string
+		wantResourceCalls int
+		wantResourceLimit int
+		wantPathConcepts  bool
+	}{
+		{"query with context", "/query",
+			`{"question":"What is a derivative?","context":"calculus homework","user_id":"2f1e6c7a-3b4d-4e5f-8a9b-0c1d2e3f4a5b"}`,
+			0, 0, false},
+		{"concept query with client defaults", "/concept-query",
+			`{"concept_name":"derivatives","user_id":"student-1","include_resources":true,"include_learning_path":true,"max_resources":10}`,
+			1, 10, true},
+		{"concept query without resources or path", "/concept-query",
+			`{"concept_name":"derivatives","include_resources":false,"include_learning_path":false,"max_resources":5}`,
+			0, 0, false},
+		{"concept query with a smaller resource limit", "/concept-query",
+			`{"concept_name":"derivatives","max_resources":3}`,
+			1, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryService := &payloadQueryService{}
+			h := NewHandler(&stubContainer{queryService: queryService}, config.QueryBatchConfig{}, config.QueryInputConfig{MaxQuestionLength: 500}, config.ModelOverrideConfig{}, zap.NewNop())
+			router := gin.New()
+			router.POST("/query", h.ProcessQuery)
+			router.POST("/concept-query", h.SmartConceptQuery)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Request-ID", "req-00000001")
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if queryService.resourceCalls != tt.wantResourceCalls || queryService.resourceLimit != tt.wantResourceLimit {
+				t.Errorf("resource lookups = %d with limit %d, want %d with limit %d",
+					queryService.resourceCalls, queryService.resourceLimit, tt.wantResourceCalls, tt.wantResourceLimit)
+			}
+			if tt.path == "/concept-query" {
+				if got := strings.Contains(w.Body.String(), `"Limits"`); got != tt.wantPathConcepts {
+					t.Errorf("learning path included = %v, want %v: %s", got, tt.wantPathConcepts, w.Body.String())
+				}
+			}
+		})
+	}
+}
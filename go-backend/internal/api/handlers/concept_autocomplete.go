@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 25
+	maxAutocompleteQuery     = 100
+)
+
+// AutocompleteConcepts suggests concepts whose name starts with q, served
+// from an in-memory index so it is cheap enough to call on every keystroke
+// GET /api/v1/concepts/autocomplete?q=de&limit=10
+func (h *Handler) AutocompleteConcepts(c *gin.Context) {
+	prefix, limit, err := parseAutocompleteParams(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matches, err := h.container.QueryService().AutocompleteConcepts(c.Request.Context(), prefix, limit)
+	if err != nil {
+		h.logger.Error("Failed to autocomplete concepts", zap.String("q", prefix), zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to load concepts")
+		return
+	}
+
+	respond(c, http.StatusOK, matches)
+}
+
+// parseAutocompleteParams reads the prefix and result bound from the query string
+func parseAutocompleteParams(c *gin.Context) (string, int, error) {
+	prefix := strings.TrimSpace(c.Query("q"))
+	if prefix == "" {
+		return "", 0, fmt.Errorf("q is required")
+	}
+	if len(prefix) > maxAutocompleteQuery {
+		return "", 0, fmt.Errorf("q must be at most %d characters", maxAutocompleteQuery)
+	}
+
+	limit := defaultAutocompleteLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxAutocompleteLimit {
+			return "", 0, fmt.Errorf("limit must be an integer between 1 and %d", maxAutocompleteLimit)
+		}
+		limit = parsed
+	}
+	return prefix, limit, nil
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mathprereq/internal/api/models"
+)
+
+func conceptDetailWithPrerequisites(ids ...string) models.ConceptDetailResponse {
+	response := models.ConceptDetailResponse{Concept: &models.ConceptInfo{ID: "derivatives", Type: "target"}}
+	for _, id := range ids {
+		response.Prerequisites = append(response.Prerequisites, models.ConceptInfo{ID: id, Type: "prerequisite"})
+	}
+	return response
+}
+
+func TestMarkLearnedPrerequisitesWithSomeKnown(t *testing.T) {
+	response := conceptDetailWithPrerequisites("limits", "functions", "slopes")
+
+	markLearnedPrerequisites(&response, []string{"limits", " slopes", "algebra"})
+
+	want := map[string]bool{"limits": true, "functions": false, "slopes": true}
+	for _, prereq := range response.Prerequisites {
+		if prereq.Learned == nil || *prereq.Learned != want[prereq.ID] {
+			t.Errorf("expected %s learned=%v, got %v", prereq.ID, want[prereq.ID], prereq.Learned)
+		}
+	}
+	if response.ReadyToLearn == nil || *response.ReadyToLearn {
+		t.Errorf("expected not ready with functions unlearned, got %v", response.ReadyToLearn)
+	}
+}
+
+func TestMarkLearnedPrerequisitesWithAllKnown(t *testing.T) {
+	response := conceptDetailWithPrerequisites("limits", "functions")
+
+	markLearnedPrerequisites(&response, []string{"functions", "limits"})
+
+	if response.ReadyToLearn == nil || !*response.ReadyToLearn {
+		t.Errorf("expected ready to learn, got %v", response.ReadyToLearn)
+	}
+}
+
+func TestConceptDetailWithoutKnownConceptsOmitsProgress(t *testing.T) {
+	body, err := json.Marshal(conceptDetailWithPrerequisites("limits"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["ready_to_learn"]; ok {
+		t.Error("expected no ready_to_learn for an anonymous request")
+	}
+	prereq := fields["prerequisites"].([]interface{})[0].(map[string]interface{})
+	if _, ok := prereq["learned"]; ok {
+		t.Error("expected no learned flag for an anonymous request")
+	}
+}
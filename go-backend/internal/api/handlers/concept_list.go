@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+const maxConceptListLimit = 500
+
+// ListConcepts lists concepts, optionally filtered and paginated. The number
+// of matches across all pages is sent in X-Total-Count.
+// GET /api/v1/concepts?category=calculus&min_difficulty=2&max_difficulty=4&has_resources=true&search=limit&limit=50&offset=0
+func (h *Handler) ListConcepts(c *gin.Context) {
+	filter, err := parseConceptFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	concepts, total, err := h.container.QueryService().ListConcepts(c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not available") {
+			status = http.StatusServiceUnavailable
+		}
+		h.logger.Error("Failed to list concepts", zap.Error(err))
+		respondError(c, status, err.Error())
+		return
+	}
+
+	h.logger.Info("Retrieved concepts for listing",
+		zap.Int("returned", len(concepts)),
+		zap.Int("total", total))
+
+	response := make([]models.ConceptInfo, len(concepts))
+	for i, concept := range concepts {
+		response[i] = models.ConceptInfo{
+			ID:          concept.ID,
+			Name:        concept.Name,
+			Description: concept.Description,
+			Type:        "concept",
+			Category:    concept.Category,
+			Difficulty:  concept.Difficulty,
+		}
+	}
+
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	respond(c, http.StatusOK, response)
+}
+
+// parseConceptFilter reads the listing filters from the query string.
+// Without a limit every match is returned.
+func parseConceptFilter(c *gin.Context) (repositories.ConceptFilter, error) {
+	filter := repositories.ConceptFilter{
+		Category: strings.TrimSpace(c.Query("category")),
+		Search:   strings.TrimSpace(c.Query("search")),
+	}
+
+	ints := []struct {
+		name  string
+		value *int
+		min   int
+		max   int
+	}{
+		{"min_difficulty", &filter.MinDifficulty, 1, 10},
+		{"max_difficulty", &filter.MaxDifficulty, 1, 10},
+		{"limit", &filter.Limit, 1, maxConceptListLimit},
+		{"offset", &filter.Offset, 0, -1},
+	}
+	for _, param := range ints {
+		raw := c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < param.min || (param.max >= 0 && parsed > param.max) {
+			if param.max < 0 {
+				return filter, fmt.Errorf("%s must be an integer of at least %d", param.name, param.min)
+			}
+			return filter, fmt.Errorf("%s must be an integer between %d and %d", param.name, param.min, param.max)
+		}
+		*param.value = parsed
+	}
+	if filter.MinDifficulty > 0 && filter.MaxDifficulty > 0 && filter.MinDifficulty > filter.MaxDifficulty {
+		return filter, fmt.Errorf("min_difficulty must not exceed max_difficulty")
+	}
+
+	if raw := c.Query("has_resources"); raw != "" {
+		hasResources, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, fmt.Errorf("has_resources must be true or false")
+		}
+		filter.HasResources = &hasResources
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/domain/repositories"
+)
+
+func parseConceptFilterFrom(t *testing.T, query string) (repositories.ConceptFilter, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/concepts?"+query, nil)
+	return parseConceptFilter(c)
+}
+
+func TestParseConceptFilter(t *testing.T) {
+	filter, err := parseConceptFilterFrom(t,
+		"category=calculus&min_difficulty=2&max_difficulty=6&has_resources=false&search=%20limit%20&limit=25&offset=50")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filter.Category != "calculus" || filter.Search != "limit" {
+		t.Errorf("unexpected text filters %+v", filter)
+	}
+	if filter.MinDifficulty != 2 || filter.MaxDifficulty != 6 {
+		t.Errorf("unexpected difficulty range %d-%d", filter.MinDifficulty, filter.MaxDifficulty)
+	}
+	if filter.HasResources == nil || *filter.HasResources {
+		t.Errorf("expected has_resources=false, got %v", filter.HasResources)
+	}
+	if filter.Limit != 25 || filter.Offset != 50 {
+		t.Errorf("unexpected page limit=%d offset=%d", filter.Limit, filter.Offset)
+	}
+}
+
+func TestParseConceptFilterDefaultsToEverything(t *testing.T) {
+	filter, err := parseConceptFilterFrom(t, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Limit != 0 || filter.HasResources != nil || filter.MinDifficulty != 0 {
+		t.Errorf("expected an empty filter, got %+v", filter)
+	}
+}
+
+func TestParseConceptFilterRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantError string
+	}{
+		{"min_difficulty=0", "min_difficulty must be an integer between 1 and 10"},
+		{"max_difficulty=eleven", "max_difficulty must be an integer between 1 and 10"},
+		{"min_difficulty=7&max_difficulty=3", "min_difficulty must not exceed max_difficulty"},
+		{"limit=501", "limit must be an integer between 1 and 500"},
+		{"offset=-1", "offset must be an integer of at least 0"},
+		{"has_resources=maybe", "has_resources must be true or false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := parseConceptFilterFrom(t, tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestParseAutocompleteParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(query string) (string, int, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/concepts/autocomplete?"+query, nil)
+		return parseAutocompleteParams(c)
+	}
+
+	prefix, limit, err := parse("q=%20de%20")
+	if err != nil || prefix != "de" || limit != defaultAutocompleteLimit {
+		t.Errorf("got %q, %d, %v; want de, %d", prefix, limit, err, defaultAutocompleteLimit)
+	}
+	if _, limit, err := parse("q=de&limit=5"); err != nil || limit != 5 {
+		t.Errorf("got limit %d, %v; want 5", limit, err)
+	}
+	for _, query := range []string{"", "q=%20", "q=de&limit=0", "q=de&limit=26", "q=" + strings.Repeat("a", 101)} {
+		if _, _, err := parse(query); err == nil {
+			t.Errorf("expected %q to be rejected", query)
+		}
+	}
+}
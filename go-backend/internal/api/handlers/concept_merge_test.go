@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+type mergeQueryService struct {
+	services.QueryService
+	err error
+}
+
+func (s *mergeQueryService) MergeConcepts(ctx context.Context, sourceID, targetID string) (*types.ConceptMergeResult, error) {
+	return nil, s.err
+}
+
+func TestMergeConceptsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"same concept", services.ErrMergeIntoItself, http.StatusBadRequest, "must differ"},
+		{"unknown concept", fmt.Errorf("%w: limit or limits", services.ErrConceptNotFound), http.StatusNotFound, "Concept not found"},
+		{"graph failure", errors.New("failed to merge concepts: connection refused"), http.StatusInternalServerError, "Failed to merge concepts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdminHandler(&mergeQueryService{err: tt.err}, time.Hour, zap.NewNop())
+			router := gin.New()
+			router.POST("/concepts/merge", h.MergeConcepts)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/concepts/merge", strings.NewReader(`{"source_id":"limit","target_id":"limits"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("expected %d with %q, got %d: %s", tt.wantStatus, tt.wantBody, w.Code, w.Body.String())
+			}
+			if strings.Contains(w.Body.String(), "connection refused") {
+				t.Errorf("internal error leaked to the client: %s", w.Body.String())
+			}
+		})
+	}
+}
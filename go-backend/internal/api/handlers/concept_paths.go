@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+	"go.uber.org/zap"
+)
+
+// maxPathConcepts caps how many concepts one paths request may ask for
+const maxPathConcepts = 100
+
+// GetConceptPaths returns the prerequisite paths of several concepts, with
+// concepts shared between paths listed once
+// POST /api/v1/concepts/paths
+func (h *Handler) GetConceptPaths(c *gin.Context) {
+	var req models.ConceptPathsRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		respondError(c, bindErrorStatus(err), err.Error())
+		return
+	}
+
+	conceptIDs := uniqueConceptIDs(req.ConceptIDs)
+	if len(conceptIDs) == 0 {
+		respondError(c, http.StatusBadRequest, "At least one concept ID is required")
+		return
+	}
+	if len(conceptIDs) > maxPathConcepts {
+		respondError(c, http.StatusBadRequest,
+			fmt.Sprintf("At most %d concepts may be requested, got %d", maxPathConcepts, len(conceptIDs)))
+		return
+	}
+
+	paths, err := h.container.QueryService().GetConceptPaths(c.Request.Context(), conceptIDs)
+	if err != nil {
+		h.logger.Error("Failed to get concept paths",
+			zap.Int("concepts", len(conceptIDs)),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get concept paths")
+		return
+	}
+
+	respond(c, http.StatusOK, paths)
+}
+
+// uniqueConceptIDs trims concept IDs and drops blanks and repeats, keeping
+// the request order
+func uniqueConceptIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
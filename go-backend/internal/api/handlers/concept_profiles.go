@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// GetConceptProfile returns a concept's definition, formulas, worked examples,
+// common mistakes and applications as separate sections
+// GET /api/v1/concepts/:id/profile?refresh=true
+func (h *Handler) GetConceptProfile(c *gin.Context) {
+	conceptID := c.Param("id")
+	refresh := c.Query("refresh") == "true"
+
+	profile, err := h.container.QueryService().GetConceptProfile(c.Request.Context(), conceptID, refresh)
+	if errors.Is(err, services.ErrConceptNotFound) {
+		respondError(c, http.StatusNotFound, "Concept not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get concept profile",
+			zap.String("concept_id", conceptID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get concept profile")
+		return
+	}
+
+	respond(c, http.StatusOK, profile)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRoadmapNextSteps = 5
+	maxRoadmapNextSteps     = 20
+)
+
+// GetConceptRoadmap returns a concept's prerequisites in learning order,
+// the concept itself and the concepts it leads to directly
+// GET /api/v1/concepts/:id/roadmap?next=5
+func (h *Handler) GetConceptRoadmap(c *gin.Context) {
+	conceptID := c.Param("id")
+
+	next := defaultRoadmapNextSteps
+	if raw := c.Query("next"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 || parsed > maxRoadmapNextSteps {
+			respondError(c, http.StatusBadRequest, "next must be an integer between 0 and "+strconv.Itoa(maxRoadmapNextSteps))
+			return
+		}
+		next = parsed
+	}
+
+	roadmap, err := h.container.QueryService().GetConceptRoadmap(c.Request.Context(), conceptID, next)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to get concept roadmap"
+		if strings.Contains(err.Error(), "concept not found") {
+			status = http.StatusNotFound
+			message = "Concept not found"
+		}
+
+		h.logger.Warn("Failed to get concept roadmap",
+			zap.String("concept_id", conceptID),
+			zap.Error(err))
+		respondError(c, status, message)
+		return
+	}
+
+	respond(c, http.StatusOK, roadmap)
+}
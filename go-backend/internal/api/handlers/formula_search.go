@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultFormulaSearchLimit = 20
+	maxFormulaSearchLimit     = 100
+)
+
+// SearchFormulas lists the formulas extracted from ingested textbooks for a
+// concept, given by name or ID
+// GET /api/v1/formulas/search?concept=integration_by_parts&limit=20
+func (h *Handler) SearchFormulas(c *gin.Context) {
+	concept, limit, err := parseFormulaSearchParams(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	formulas, err := h.container.QueryService().SearchFormulas(c.Request.Context(), concept, limit)
+	if err != nil {
+		h.logger.Error("Failed to search formulas", zap.String("concept", concept), zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to search formulas")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"concept":  concept,
+		"formulas": formulas,
+		"count":    len(formulas),
+	})
+}
+
+// parseFormulaSearchParams reads the concept and result bound from the query string
+func parseFormulaSearchParams(c *gin.Context) (string, int, error) {
+	concept := strings.TrimSpace(c.Query("concept"))
+	if concept == "" {
+		return "", 0, fmt.Errorf("concept is required")
+	}
+
+	limit := defaultFormulaSearchLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxFormulaSearchLimit {
+			return "", 0, fmt.Errorf("limit must be an integer between 1 and %d", maxFormulaSearchLimit)
+		}
+		limit = parsed
+	}
+	return concept, limit, nil
+}
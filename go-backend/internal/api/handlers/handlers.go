@@ -1,35 +1,48 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/mathprereq/internal/api/models"
+	"github.com/mathprereq/internal/arithmetic"
 	"github.com/mathprereq/internal/container"
+	"github.com/mathprereq/internal/core/config"
 	"github.com/mathprereq/internal/data/scraper"
 	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
 	container container.Container
-	validator *validator.Validate
-	logger    *zap.Logger
-	startTime time.Time
+	batchCfg  config.QueryBatchConfig
+	inputCfg  config.QueryInputConfig
+	// overrideToken authenticates per-request model overrides; empty
+	// ignores them
+	overrideToken string
+	validator     *validator.Validate
+	logger        *zap.Logger
+	startTime     time.Time
 }
 
-func NewHandler(container container.Container, logger *zap.Logger) *Handler {
+func NewHandler(container container.Container, batchCfg config.QueryBatchConfig, inputCfg config.QueryInputConfig, overrideCfg config.ModelOverrideConfig, logger *zap.Logger) *Handler {
 	validator := validator.New()
 
 	return &Handler{
-		container: container,
-		validator: validator,
-		logger:    logger,
-		startTime: time.Now(),
+		container:     container,
+		batchCfg:      batchCfg,
+		inputCfg:      inputCfg,
+		overrideToken: overrideCfg.Token,
+		validator:     validator,
+		logger:        logger,
+		startTime:     time.Now(),
 	}
 }
 
@@ -38,81 +51,68 @@ func (h *Handler) ProcessQuery(c *gin.Context) {
 	start := time.Now()
 
 	var req models.QueryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindStrictJSON(c, &req); err != nil {
 		h.logger.Warn("Invalid request", zap.Error(err), zap.String("request_id", requestID))
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      err.Error(),
-			"success":    false,
-			"request_id": requestID,
-		})
+		respondError(c, bindErrorStatus(err), err.Error())
 		return
 	}
 
+	req.Question = cleanText(req.Question)
 	if err := h.validator.Struct(&req); err != nil {
 		h.logger.Warn("Validation failed", zap.Error(err), zap.String("request_id", requestID))
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Validation failed: " + err.Error(),
-			"success":    false,
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+	if message := h.questionLengthError("Question", req.Question); message != "" {
+		h.logger.Warn("Question too long", zap.String("request_id", requestID))
+		respondError(c, http.StatusBadRequest, message)
+		return
+	}
+
+	language, ok := services.NormalizeLanguage(req.Language)
+	if !ok {
+		respondErrorWithData(c, http.StatusBadRequest,
+			fmt.Sprintf("Unsupported language %q", req.Language),
+			gin.H{"supported_languages": services.SupportedLanguageCodes()})
 		return
 	}
 
 	h.logger.Info("Processing query",
-		zap.String("query", req.Question[:min(len(req.Question), 100)]),
+		zap.String("query", logPreview(req.Question, 100)),
+		zap.String("language", language),
 		zap.String("request_id", requestID))
 
 	// Use container's QueryService instead of undefined orchestrator
 	result, err := h.container.QueryService().ProcessQuery(c.Request.Context(), &services.QueryRequest{
-		UserID:    req.UserID,
-		Question:  req.Question,
-		RequestID: requestID,
+		UserID:          req.UserID,
+		Question:        req.Question,
+		RequestID:       requestID,
+		IncludeVisuals:  req.IncludeVisuals,
+		Language:        language,
+		BypassCache:     req.BypassCache,
+		RetrievalSource: req.RetrievalSource,
+		SessionID:       req.SessionID,
+		ModelOverride:   h.modelOverride(c, req.Model),
 	})
 	processingTime := time.Since(start)
 
-	if err != nil {
-		h.logger.Error("Query processing failed", zap.Error(err))
-		errorMsg := err.Error()
-		response := models.QueryResponse{
-			Success:            false,
-			Query:              req.Question,
-			IdentifiedConcepts: []string{},
-			LearningPath:       models.LearningPath{Concepts: []models.ConceptInfo{}, TotalConcepts: 0, PathType: "prerequisite_path"},
-			Explanation:        "I apologize, but I encountered an error while processing your question. Please try again or rephrase your question.",
-			RetrievedContext:   []string{},
-			ProcessingTime:     processingTime,
-			ErrorMessage:       &errorMsg,
-		}
-
-		h.logger.Info("Returning error response", zap.Any("response", response))
-		c.JSON(http.StatusOK, response) // Keep 200 status for frontend compatibility
+	if errors.Is(err, services.ErrModelOverrideNotAllowed) {
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("Model %q may not be used for overrides", req.Model))
 		return
 	}
-
-	// Convert result to response format
-	concepts := make([]models.ConceptInfo, len(result.PrerequisitePath))
-	for i, concept := range result.PrerequisitePath {
-		concepts[i] = models.ConceptInfo{
-			ID:          concept.ID,
-			Name:        concept.Name,
-			Description: concept.Description,
-			Type:        concept.Type,
+	if err != nil {
+		h.logger.Error("Query processing failed",
+			zap.Error(err),
+			zap.Duration("processing_time", processingTime),
+			zap.String("request_id", requestID))
+		if respondIfServiceBusy(c, err) {
+			return
 		}
+		respondError(c, http.StatusInternalServerError, services.QueryFailedMessage)
+		return
 	}
 
-	response := models.QueryResponse{
-		Success:            true,
-		Query:              req.Question,
-		IdentifiedConcepts: result.IdentifiedConcepts,
-		LearningPath: models.LearningPath{
-			Concepts:      concepts,
-			TotalConcepts: len(concepts),
-			PathType:      "prerequisite_path",
-		},
-		Explanation:      result.Explanation,
-		RetrievedContext: result.RetrievedContext,
-		ProcessingTime:   processingTime,
-	}
+	response := newQueryResponse(req.Question, result, processingTime)
 
 	h.logger.Info("Query processed successfully",
 		zap.Duration("processing_time", processingTime),
@@ -127,27 +127,63 @@ func (h *Handler) ProcessQuery(c *gin.Context) {
 	c.Header("X-Processing-Time", processingTime.String())
 	c.Header("X-Explanation-Length", fmt.Sprintf("%d", len(result.Explanation)))
 
-	// Add warning header if explanation might be incomplete
-	if len(result.Explanation) < 500 {
+	// Add warning header if explanation is templated or might be incomplete
+	if result.Fallback {
+		c.Header("X-Response-Warning", "fallback-explanation")
+	} else if result.ArithmeticCheck != nil && result.ArithmeticCheck.Status == arithmetic.StatusFlagged {
+		c.Header("X-Response-Warning", "arithmetic-flagged")
+	} else if len(result.Explanation) < 500 {
 		c.Header("X-Response-Warning", "explanation-may-be-incomplete")
 	}
 
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, response)
 }
 
+// GetQueryVisual serves a generated plot as a PNG image
+// GET /api/v1/queries/:id/visuals/:n
+func (h *Handler) GetQueryVisual(c *gin.Context) {
+	queryID := c.Param("id")
+
+	index, err := strconv.Atoi(c.Param("n"))
+	if err != nil || index < 0 {
+		respondError(c, http.StatusBadRequest, "Visual index must be a non-negative integer")
+		return
+	}
+
+	visual, err := h.container.QueryService().GetVisualAid(c.Request.Context(), queryID, index)
+	if err != nil {
+		h.logger.Error("Failed to get visual aid",
+			zap.String("query_id", queryID),
+			zap.Int("index", index),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get visual")
+		return
+	}
+	if visual == nil {
+		respondError(c, http.StatusNotFound, "Visual not found")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "image/png", visual.ImagePNG)
+}
+
+// GetConceptDetail returns a concept with its direct prerequisites and the
+// concepts it leads to. When the request names the learner's known concepts,
+// each prerequisite is marked learned or not, along with whether the learner
+// is ready for the concept.
 func (h *Handler) GetConceptDetail(c *gin.Context) {
 	requestID := getRequestID(c)
 
-	// Get concept ID from URL parameter instead of JSON body
-	conceptID := c.Param("id")
+	var req models.ConceptDetailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, bindErrorStatus(err), "Invalid request format: "+err.Error())
+		return
+	}
+	conceptID := strings.TrimSpace(req.ConceptID)
 	if conceptID == "" {
-		h.logger.Warn("Missing concept ID parameter", zap.String("request_id", requestID))
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Success:   false,
-			Error:     "Concept ID parameter is required",
-			RequestID: requestID,
-			Timestamp: time.Now(),
-		})
+		h.logger.Warn("Missing concept ID", zap.String("request_id", requestID))
+		respondError(c, http.StatusBadRequest, "concept_id is required")
 		return
 	}
 
@@ -156,16 +192,7 @@ func (h *Handler) GetConceptDetail(c *gin.Context) {
 	result, err := h.container.QueryService().GetConceptDetail(c.Request.Context(), conceptID)
 	if err != nil {
 		h.logger.Error("Failed to get concept detail", zap.Error(err))
-		errorMsg := err.Error()
-		response := models.ConceptDetailResponse{
-			Success:             false,
-			Prerequisites:       []models.ConceptInfo{},
-			LeadsTo:             []models.ConceptInfo{},
-			DetailedExplanation: "",
-			RequestID:           requestID,
-			ErrorMessage:        &errorMsg,
-		}
-		c.JSON(http.StatusOK, response)
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -192,7 +219,6 @@ func (h *Handler) GetConceptDetail(c *gin.Context) {
 	}
 
 	response := models.ConceptDetailResponse{
-		Success: true,
 		Concept: &models.ConceptInfo{
 			ID:          result.Concept.ID,
 			Name:        result.Concept.Name,
@@ -203,38 +229,28 @@ func (h *Handler) GetConceptDetail(c *gin.Context) {
 		LeadsTo:             leadsTo,
 		DetailedExplanation: result.DetailedExplanation,
 	}
+	if req.KnownConcepts != nil {
+		markLearnedPrerequisites(&response, req.KnownConcepts)
+	}
 
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, response)
 }
 
-func (h *Handler) ListConcepts(c *gin.Context) {
-	concepts, err := h.container.QueryService().GetAllConcepts(c.Request.Context())
-	if err != nil {
-		h.logger.Error("Failed to list concepts", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// markLearnedPrerequisites flags each prerequisite the learner knows, and
+// the concept ready to learn when they know all of them
+func markLearnedPrerequisites(response *models.ConceptDetailResponse, knownConceptIDs []string) {
+	known := make(map[string]bool, len(knownConceptIDs))
+	for _, id := range knownConceptIDs {
+		known[strings.TrimSpace(id)] = true
 	}
 
-	h.logger.Info("Retrieved concepts for listing", zap.Int("total_concepts", len(concepts)))
-	for i, concept := range concepts {
-		if i < 5 { // Log first 5 for debugging
-			h.logger.Info("Concept in database",
-				zap.String("id", concept.ID),
-				zap.String("name", concept.Name))
-		}
-	}
-
-	response := make([]models.ConceptInfo, len(concepts))
-	for i, concept := range concepts {
-		response[i] = models.ConceptInfo{
-			ID:          concept.ID,
-			Name:        concept.Name,
-			Description: concept.Description,
-			Type:        "concept",
-		}
+	ready := true
+	for i := range response.Prerequisites {
+		learned := known[response.Prerequisites[i].ID]
+		response.Prerequisites[i].Learned = &learned
+		ready = ready && learned
 	}
-
-	c.JSON(http.StatusOK, response)
+	response.ReadyToLearn = &ready
 }
 
 // HealthCheck provides comprehensive health check
@@ -254,27 +270,41 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 
 	// Check if this is a detailed health check
 	if c.Request.URL.Path == "/api/v1/health-detailed" {
-		c.JSON(http.StatusOK, gin.H{
-			"status":    systemHealth,
-			"timestamp": time.Now().UTC(),
-			"uptime":    time.Since(h.startTime).String(),
-			"version":   "1.0.0",
-			"services":  healthStatus,
+		respond(c, http.StatusOK, gin.H{
+			"status":   systemHealth,
+			"uptime":   time.Since(h.startTime).String(),
+			"version":  "1.0.0",
+			"services": healthStatus,
 		})
 		return
 	}
 
 	// Simple health check
-	statusCode := http.StatusOK
+	health := gin.H{
+		"status": systemHealth,
+		"uptime": time.Since(h.startTime).String(),
+	}
 	if systemHealth == "degraded" {
-		statusCode = http.StatusServiceUnavailable
+		respondErrorWithData(c, http.StatusServiceUnavailable, "One or more services are unhealthy", health)
+		return
 	}
+	respond(c, http.StatusOK, health)
+}
 
-	c.JSON(statusCode, gin.H{
-		"status":    systemHealth,
-		"timestamp": time.Now().UTC(),
-		"uptime":    time.Since(h.startTime).String(),
-	})
+// Readiness reports whether the instance may take traffic. It stays 503
+// while a required startup self-check has not passed.
+func (h *Handler) Readiness(c *gin.Context) {
+	gate := h.container.Readiness()
+	ready := gin.H{
+		"ready":    gate.Ready(),
+		"required": gate.Required(),
+		"report":   gate.Report(),
+	}
+	if !gate.Ready() {
+		respondErrorWithData(c, http.StatusServiceUnavailable, "Startup self-check has not passed", ready)
+		return
+	}
+	respond(c, http.StatusOK, ready)
 }
 
 // SmartConceptQuery handles concept queries with MongoDB cache checking
@@ -285,25 +315,22 @@ func (h *Handler) SmartConceptQuery(c *gin.Context) {
 	h.logger.Info("Smart concept query started", zap.String("request_id", requestID))
 
 	var req models.ConceptQueryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindStrictJSON(c, &req); err != nil {
 		h.logger.Warn("Invalid concept query request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success":    false,
-			"message":    "Invalid request format",
-			"error":      err.Error(),
-			"request_id": requestID,
-		})
+		respondError(c, bindErrorStatus(err), "Invalid request format: "+err.Error())
 		return
 	}
 
-	// Validate concept name
-	conceptName := strings.TrimSpace(req.ConceptName)
+	// Validate concept name; it is a single line, so all whitespace collapses
+	conceptName := strings.Join(strings.Fields(cleanText(req.ConceptName)), " ")
 	if conceptName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success":    false,
-			"message":    "Concept name is required",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusBadRequest, "Concept name is required")
+		return
+	}
+	req.ConceptName = conceptName
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Warn("Validation failed", zap.Error(err), zap.String("request_id", requestID))
+		respondError(c, http.StatusBadRequest, "Validation failed: "+err.Error())
 		return
 	}
 
@@ -328,54 +355,47 @@ func (h *Handler) SmartConceptQuery(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Smart concept query failed",
 			zap.String("concept", conceptName),
-			zap.Error(err))
-
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success":         false,
-			"message":         "Failed to process concept query",
-			"concept_name":    conceptName,
-			"error":           err.Error(),
-			"request_id":      requestID,
-			"processing_time": time.Since(startTime).String(),
-		})
+			zap.Error(err),
+			zap.Duration("processing_time", time.Since(startTime)))
+		if respondIfServiceBusy(c, err) {
+			return
+		}
+		respondErrorWithData(c, http.StatusInternalServerError,
+			"Failed to process concept query: "+err.Error(),
+			gin.H{"concept_name": conceptName})
 		return
 	}
 
-	// Determine source (cache vs fresh processing)
-	source := "processed"
-	var cacheAge *time.Duration
-
-	// If query was completed very quickly (< 1 second), it was likely from cache
-	if result.ProcessingTime < time.Second {
-		source = "cache"
-		if result.Query != nil {
-			age := time.Since(result.Query.Timestamp)
-			cacheAge = &age
-		}
+	// The service reports which branch produced the result
+	source := result.Source
+	if source == "" {
+		source = services.QuerySourceProcessed
 	}
+	cacheAge := result.CacheAge
 
 	// Convert prerequisite path
 	var learningPath models.LearningPath
-	for _, concept := range result.PrerequisitePath {
-		learningPath.Concepts = append(learningPath.Concepts, models.ConceptInfo{
-			ID:          concept.ID,
-			Name:        concept.Name,
-			Description: concept.Description,
-			Type:        concept.Type,
-		})
+	if req.IncludeLearningPath == nil || *req.IncludeLearningPath {
+		for _, concept := range result.PrerequisitePath {
+			learningPath.Concepts = append(learningPath.Concepts, pathConceptInfo(concept))
+		}
+		learningPath.TotalConcepts = len(learningPath.Concepts)
 	}
-	learningPath.TotalConcepts = len(learningPath.Concepts)
 
 	// Get educational resources if available
 	var educationalResources []scraper.EducationalResource
 	resourcesMessage := ""
 
-	if len(result.IdentifiedConcepts) > 0 {
+	maxResources := req.MaxResources
+	if maxResources == 0 {
+		maxResources = 10
+	}
+	if len(result.IdentifiedConcepts) > 0 && (req.IncludeResources == nil || *req.IncludeResources) {
 		// Try to get existing resources
 		resources, err := h.container.QueryService().GetResourcesForConcepts(
 			c.Request.Context(),
 			result.IdentifiedConcepts,
-			10,
+			maxResources,
 		)
 		if err == nil && len(resources) > 0 {
 			educationalResources = resources
@@ -387,17 +407,19 @@ func (h *Handler) SmartConceptQuery(c *gin.Context) {
 
 	// Build response
 	response := models.ConceptQueryResponse{
-		Success:              true,
+		Status:               result.Status,
 		ConceptName:          conceptName,
 		Source:               source,
 		IdentifiedConcepts:   result.IdentifiedConcepts,
 		LearningPath:         learningPath,
 		Explanation:          result.Explanation,
+		Fallback:             result.Fallback,
+		ModelProfile:         result.ModelProfile,
+		ArithmeticCheck:      result.ArithmeticCheck,
+		SourcesUsed:          result.SourcesUsed,
 		RetrievedContext:     result.RetrievedContext,
 		ProcessingTime:       time.Since(startTime),
 		CacheAge:             cacheAge,
-		RequestID:            requestID,
-		Timestamp:            time.Now(),
 		EducationalResources: educationalResources,
 		ResourcesMessage:     resourcesMessage,
 	}
@@ -408,5 +430,88 @@ func (h *Handler) SmartConceptQuery(c *gin.Context) {
 		zap.Duration("processing_time", response.ProcessingTime),
 		zap.String("request_id", requestID))
 
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, response)
+}
+
+// pathConceptInfo converts a learning path concept, labelling weighted
+// prerequisites must_know or helpful
+func pathConceptInfo(concept types.Concept) models.ConceptInfo {
+	return models.ConceptInfo{
+		ID:          concept.ID,
+		Name:        concept.Name,
+		Description: concept.Description,
+		Type:        concept.Type,
+		Weight:      concept.Weight,
+		Importance:  types.PrerequisiteImportance(concept.Weight),
+	}
+}
+
+// newQueryResponse converts a pipeline result to the /query response format
+func newQueryResponse(question string, result *services.QueryResult, processingTime time.Duration) models.QueryResponse {
+	concepts := make([]models.ConceptInfo, len(result.PrerequisitePath))
+	for i, concept := range result.PrerequisitePath {
+		concepts[i] = pathConceptInfo(concept)
+	}
+
+	visualAids := make([]models.VisualAidInfo, len(result.VisualAids))
+	for i, visual := range result.VisualAids {
+		visualAids[i] = models.VisualAidInfo{
+			Title:      visual.Title,
+			Expression: visual.Expression,
+			URL:        visual.URL,
+		}
+	}
+
+	source := result.Source
+	if source == "" {
+		source = services.QuerySourceProcessed
+	}
+
+	// An LLM-suggested path is labelled as such, never as the curated one
+	var suggestedPath *models.LearningPath
+	if len(result.SuggestedPath) > 0 {
+		suggested := make([]models.ConceptInfo, len(result.SuggestedPath))
+		for i, concept := range result.SuggestedPath {
+			suggested[i] = models.ConceptInfo{
+				Name:        concept.Name,
+				Description: concept.Description,
+				Type:        concept.Type,
+			}
+		}
+		suggestedPath = &models.LearningPath{
+			Concepts:      suggested,
+			TotalConcepts: len(suggested),
+			PathType:      "llm_suggested",
+		}
+	}
+
+	// Queries outside the analytics sample are not stored, so their ID
+	// would only lead to 404s
+	queryID := result.Query.ID
+	if result.Unsampled {
+		queryID = ""
+	}
+
+	return models.QueryResponse{
+		Status:             result.Status,
+		QueryID:            queryID,
+		Query:              question,
+		IdentifiedConcepts: result.IdentifiedConcepts,
+		LearningPath: models.LearningPath{
+			Concepts:      concepts,
+			TotalConcepts: len(concepts),
+			PathType:      "prerequisite_path",
+		},
+		SuggestedPath:    suggestedPath,
+		Explanation:      result.Explanation,
+		Fallback:         result.Fallback,
+		ModelProfile:     result.ModelProfile,
+		ArithmeticCheck:  result.ArithmeticCheck,
+		SourcesUsed:      result.SourcesUsed,
+		RetrievedContext: result.RetrievedContext,
+		ProcessingTime:   processingTime,
+		Source:           source,
+		CacheAge:         result.CacheAge,
+		VisualAids:       visualAids,
+	}
 }
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// cleanText strips control and invisible formatting characters, which
+// include bidi overrides and zero-width spaces, and normalizes whitespace:
+// runs of spaces and tabs become one space, lines are trimmed and blank
+// lines dropped. Line breaks are kept since they separate steps of math.
+func cleanText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		case r == utf8.RuneError, unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			// dropped
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// questionLengthError describes a cleaned question or problem longer than
+// the configured limit, or "" when it fits
+func (h *Handler) questionLengthError(field, text string) string {
+	length := utf8.RuneCountInString(text)
+	if length <= h.inputCfg.MaxQuestionLength {
+		return ""
+	}
+	return fmt.Sprintf("%s is too long: %d characters, the limit is %d", field, length, h.inputCfg.MaxQuestionLength)
+}
+
+// logPreview shortens text for logging without splitting a character
+func logPreview(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max])
+}
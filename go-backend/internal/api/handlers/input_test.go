@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/core/config"
+	"go.uber.org/zap"
+)
+
+func TestCleanText(t *testing.T) {
+	tests := map[string]string{
+		"  What is\ta   limit?  ":                "What is a limit?",
+		"Solve:\r\n  2x + 3 = 7\r\n\r\n\r\n x=?": "Solve:\n2x + 3 = 7\nx=?",
+		"lim\x00it\x1b[31m of\u200b f":           "limit[31m of f",
+		"evil\u202edcba":                         "evildcba",
+		"√(x²) ≤ |x|":                            "√(x²) ≤ |x|",
+		"bad \xff byte":                          "bad byte",
+		"\n\t  \n":                               "",
+	}
+	for input, want := range tests {
+		if got := cleanText(input); got != want {
+			t.Errorf("cleanText(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestQuestionLengthErrorAtBoundary(t *testing.T) {
+	h := &Handler{inputCfg: config.QueryInputConfig{MaxQuestionLength: 10}}
+
+	if message := h.questionLengthError("Question", strings.Repeat("a", 10)); message != "" {
+		t.Errorf("expected a question at the limit accepted, got %q", message)
+	}
+	// Characters are counted, not bytes
+	if message := h.questionLengthError("Question", strings.Repeat("∫", 10)); message != "" {
+		t.Errorf("expected multi-byte characters counted once, got %q", message)
+	}
+	message := h.questionLengthError("Question", strings.Repeat("a", 11))
+	if message != "Question is too long: 11 characters, the limit is 10" {
+		t.Errorf("unexpected message %q", message)
+	}
+}
+
+func TestLogPreviewKeepsWholeCharacters(t *testing.T) {
+	if got := logPreview("∫∫∫∫", 2); got != "∫∫" {
+		t.Errorf("logPreview() = %q", got)
+	}
+	if got := logPreview("short", 100); got != "short" {
+		t.Errorf("logPreview() = %q", got)
+	}
+}
+
+func TestQueryEndpointsRejectInvalidInputBeforeProcessing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// No container: requests must be rejected before reaching the service
+	h := NewHandler(nil, config.QueryBatchConfig{MaxSize: 5}, config.QueryInputConfig{MaxQuestionLength: 20}, config.ModelOverrideConfig{}, zap.NewNop())
+	router := gin.New()
+	router.POST("/query", h.ProcessQuery)
+	router.POST("/explain-mistake", h.ExplainMistake)
+	router.POST("/concept-query", h.SmartConceptQuery)
+
+	tests := []struct {
+		name, path, body, wantError string
+	}{
+		{"question over the limit", "/query", `{"question":"` + strings.Repeat("x", 21) + `"}`, "Question is too long: 21 characters"},
+		{"question short once cleaned", "/query", `{"question":"\u0000a\u200b\t "}`, "Validation failed"},
+		{"problem over the limit", "/explain-mistake", `{"problem":"` + strings.Repeat("x", 21) + `","attempt":"x=1"}`, "Problem is too long"},
+		{"concept name only control characters", "/concept-query", `{"concept_name":"\u0007\u200b"}`, "Concept name is required"},
+		{"concept name over the limit", "/concept-query", `{"concept_name":"` + strings.Repeat("x", 101) + `"}`, "Validation failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.wantError) {
+				t.Errorf("expected 400 with %q, got %d: %s", tt.wantError, w.Code, w.Body.String())
+			}
+		})
+	}
+}
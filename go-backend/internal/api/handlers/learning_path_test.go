@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+)
+
+func TestNewQueryResponseLabelsPrerequisiteImportance(t *testing.T) {
+	result := &services.QueryResult{
+		Query: entities.NewQuery("", "What is a derivative?", ""),
+		PrerequisitePath: []types.Concept{
+			{ID: "limits", Type: "prerequisite", Weight: 0.9},
+			{ID: "sequences", Type: "prerequisite", Weight: 0.3},
+			// Stored before paths carried weights
+			{ID: "functions", Type: "prerequisite"},
+			{ID: "derivatives", Type: "target"},
+		},
+	}
+
+	response := newQueryResponse("What is a derivative?", result, time.Second)
+
+	want := map[string]struct {
+		weight     float64
+		importance string
+	}{
+		"limits":      {0.9, types.ImportanceMustKnow},
+		"sequences":   {0.3, types.ImportanceHelpful},
+		"functions":   {0, ""},
+		"derivatives": {0, ""},
+	}
+	for _, concept := range response.LearningPath.Concepts {
+		w := want[concept.ID]
+		if concept.Weight != w.weight || concept.Importance != w.importance {
+			t.Errorf("%s = %v/%q, want %v/%q", concept.ID, concept.Weight, concept.Importance, w.weight, w.importance)
+		}
+	}
+}
+
+func TestNewQueryResponseOmitsUnsampledQueryID(t *testing.T) {
+	result := &services.QueryResult{Query: entities.NewQuery("", "What is a derivative?", "")}
+	if response := newQueryResponse("What is a derivative?", result, time.Second); response.QueryID != result.Query.ID {
+		t.Errorf("QueryID = %q, want the stored query's ID", response.QueryID)
+	}
+
+	result.Unsampled = true
+	if response := newQueryResponse("What is a derivative?", result, time.Second); response.QueryID != "" {
+		t.Errorf("QueryID = %q, want none for an unstored query", response.QueryID)
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// ExplainMistake pinpoints where a student's attempt at a problem goes wrong
+// and how to do it correctly
+// POST /api/v1/explain-mistake
+func (h *Handler) ExplainMistake(c *gin.Context) {
+	requestID := getRequestID(c)
+	start := time.Now()
+
+	var req models.ExplainMistakeRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		h.logger.Warn("Invalid explain-mistake request", zap.Error(err), zap.String("request_id", requestID))
+		respondError(c, bindErrorStatus(err), err.Error())
+		return
+	}
+
+	req.Problem, req.Attempt = cleanText(req.Problem), cleanText(req.Attempt)
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Warn("Validation failed", zap.Error(err), zap.String("request_id", requestID))
+		respondError(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+	if message := h.questionLengthError("Problem", req.Problem); message != "" {
+		respondError(c, http.StatusBadRequest, message)
+		return
+	}
+
+	language, ok := services.NormalizeLanguage(req.Language)
+	if !ok {
+		respondErrorWithData(c, http.StatusBadRequest,
+			fmt.Sprintf("Unsupported language %q", req.Language),
+			gin.H{"supported_languages": services.SupportedLanguageCodes()})
+		return
+	}
+
+	result, err := h.container.QueryService().ExplainMistake(c.Request.Context(), &services.MistakeRequest{
+		UserID:    req.UserID,
+		Problem:   req.Problem,
+		Attempt:   req.Attempt,
+		RequestID: requestID,
+		Language:  language,
+	})
+	processingTime := time.Since(start)
+
+	if err != nil {
+		h.logger.Error("Mistake explanation failed",
+			zap.Error(err),
+			zap.Duration("processing_time", processingTime),
+			zap.String("request_id", requestID))
+		if respondIfServiceBusy(c, err) {
+			return
+		}
+		respondError(c, http.StatusInternalServerError,
+			"Failed to explain the mistake. Please try again.")
+		return
+	}
+
+	concepts := make([]models.ConceptInfo, len(result.PrerequisitePath))
+	for i, concept := range result.PrerequisitePath {
+		concepts[i] = models.ConceptInfo{
+			ID:          concept.ID,
+			Name:        concept.Name,
+			Description: concept.Description,
+			Type:        concept.Type,
+		}
+	}
+
+	c.Header("X-Processing-Time", processingTime.String())
+	if !result.Explanation.Structured {
+		c.Header("X-Response-Warning", "unstructured-explanation")
+	}
+
+	// As for /query, unstored queries get no ID
+	queryID := result.Query.ID
+	if result.Unsampled {
+		queryID = ""
+	}
+
+	respond(c, http.StatusOK, models.ExplainMistakeResponse{
+		QueryID:            queryID,
+		Problem:            req.Problem,
+		IdentifiedConcepts: result.IdentifiedConcepts,
+		LearningPath: models.LearningPath{
+			Concepts:      concepts,
+			TotalConcepts: len(concepts),
+			PathType:      "prerequisite_path",
+		},
+		Explanation:      result.Explanation,
+		RetrievedContext: result.RetrievedContext,
+		ProcessingTime:   processingTime,
+	})
+}
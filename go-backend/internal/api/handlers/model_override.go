@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// modelOverrideHeader carries the token that lets testers override the model
+const modelOverrideHeader = "X-LLM-Override-Token"
+
+// modelOverride returns the requested model when the request is
+// authenticated for overrides. Overrides from other requests are ignored
+// rather than rejected, so the question is still answered as usual.
+func (h *Handler) modelOverride(c *gin.Context, model string) string {
+	if model == "" {
+		return ""
+	}
+	token := c.GetHeader(modelOverrideHeader)
+	if h.overrideToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.overrideToken)) != 1 {
+		h.logger.Info("Ignoring model override from unauthenticated request",
+			zap.String("model", model),
+			zap.String("request_id", getRequestID(c)))
+		return ""
+	}
+	return model
+}
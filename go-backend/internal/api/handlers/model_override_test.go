@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestModelOverrideRequiresToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name, configured, sent, want string
+	}{
+		{"valid token", "secret", "secret", "gemini-2.5-pro"},
+		{"wrong token", "secret", "guess", ""},
+		{"missing token", "secret", "", ""},
+		{"overrides disabled", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{overrideToken: tt.configured, logger: zap.NewNop()}
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/query", nil)
+			if tt.sent != "" {
+				c.Request.Header.Set(modelOverrideHeader, tt.sent)
+			}
+
+			if got := h.modelOverride(c, "gemini-2.5-pro"); got != tt.want {
+				t.Errorf("modelOverride() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNeighborhoodDepth = 2
+	maxNeighborhoodDepth     = 4
+	maxNeighborhoodNodes     = 150
+)
+
+// GetConceptNeighborhood returns the prerequisites and dependents within
+// depth hops of a concept as nodes and directed edges
+// GET /api/v1/concepts/:id/neighborhood?depth=2
+func (h *Handler) GetConceptNeighborhood(c *gin.Context) {
+	conceptID := c.Param("id")
+
+	depth := defaultNeighborhoodDepth
+	if raw := c.Query("depth"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxNeighborhoodDepth {
+			respondError(c, http.StatusBadRequest, "depth must be an integer between 1 and "+strconv.Itoa(maxNeighborhoodDepth))
+			return
+		}
+		depth = parsed
+	}
+
+	neighborhood, err := h.container.QueryService().GetConceptNeighborhood(c.Request.Context(), conceptID, depth, maxNeighborhoodNodes)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to get concept neighborhood"
+		if strings.Contains(err.Error(), "concept not found") {
+			status = http.StatusNotFound
+			message = "Concept not found"
+		}
+
+		h.logger.Warn("Failed to get concept neighborhood",
+			zap.String("concept_id", conceptID),
+			zap.Int("depth", depth),
+			zap.Error(err))
+		respondError(c, status, message)
+		return
+	}
+
+	respond(c, http.StatusOK, neighborhood)
+}
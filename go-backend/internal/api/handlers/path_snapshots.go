@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+type capturePathSnapshotRequest struct {
+	Label string `json:"label"`
+}
+
+// CapturePathSnapshot stores the concept's current prerequisite path as a baseline
+// POST /api/v1/concepts/:id/path-snapshots
+func (h *Handler) CapturePathSnapshot(c *gin.Context) {
+	conceptID := c.Param("id")
+
+	var req capturePathSnapshotRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, bindErrorStatus(err), err.Error())
+			return
+		}
+	}
+
+	snapshot, err := h.container.QueryService().CapturePathSnapshot(c.Request.Context(), conceptID, req.Label)
+	if err != nil {
+		h.logger.Error("Failed to capture path snapshot",
+			zap.String("concept_id", conceptID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to capture path snapshot")
+		return
+	}
+
+	respond(c, http.StatusCreated, snapshot)
+}
+
+// GetConceptPathDiff compares the current prerequisite path against a snapshot
+// GET /api/v1/concepts/:id/path-diff?baseline=<snapshot id>
+func (h *Handler) GetConceptPathDiff(c *gin.Context) {
+	conceptID := c.Param("id")
+
+	diff, err := h.container.QueryService().DiffConceptPath(c.Request.Context(), conceptID, c.Query("baseline"))
+	if errors.Is(err, services.ErrBaselineSnapshotNotFound) {
+		respondError(c, http.StatusNotFound, "No baseline snapshot found for this concept")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to diff concept path",
+			zap.String("concept_id", conceptID),
+			zap.String("request_id", getRequestID(c)),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to diff concept path")
+		return
+	}
+
+	respond(c, http.StatusOK, diff)
+}
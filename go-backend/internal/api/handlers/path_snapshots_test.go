@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+type diffQueryService struct {
+	services.QueryService
+	err error
+}
+
+func (s *diffQueryService) DiffConceptPath(ctx context.Context, conceptID, baselineID string) (*entities.PathDiff, error) {
+	return nil, s.err
+}
+
+func TestGetConceptPathDiffErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"no baseline", fmt.Errorf("%w for concept limits", services.ErrBaselineSnapshotNotFound), http.StatusNotFound, "No baseline snapshot"},
+		{"storage failure", errors.New("failed to load baseline snapshot: connection refused"), http.StatusInternalServerError, "Failed to diff concept path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&stubContainer{queryService: &diffQueryService{err: tt.err}}, config.QueryBatchConfig{}, config.QueryInputConfig{}, config.ModelOverrideConfig{}, zap.NewNop())
+			router := gin.New()
+			router.GET("/concepts/:id/path-diff", h.GetConceptPathDiff)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/concepts/limits/path-diff", nil))
+
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("expected %d with %q, got %d: %s", tt.wantStatus, tt.wantBody, w.Code, w.Body.String())
+			}
+			if strings.Contains(w.Body.String(), "connection refused") {
+				t.Errorf("internal error leaked to the client: %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// ProcessQueryBatch answers several questions in one call. Questions run
+// through the regular pipeline with bounded concurrency; invalid questions
+// and questions that fail or outlast the batch timeout get a per-item error
+// instead of failing the whole batch.
+// POST /api/v1/query/batch
+func (h *Handler) ProcessQueryBatch(c *gin.Context) {
+	requestID := getRequestID(c)
+	start := time.Now()
+
+	var req models.BatchQueryRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		h.logger.Warn("Invalid batch query request", zap.Error(err), zap.String("request_id", requestID))
+		respondError(c, bindErrorStatus(err), err.Error())
+		return
+	}
+
+	if len(req.Questions) == 0 {
+		respondError(c, http.StatusBadRequest, "At least one question is required")
+		return
+	}
+	if len(req.Questions) > h.batchCfg.MaxSize {
+		respondError(c, http.StatusBadRequest,
+			fmt.Sprintf("A batch may contain at most %d questions, got %d", h.batchCfg.MaxSize, len(req.Questions)))
+		return
+	}
+
+	items := make([]models.BatchQueryItem, len(req.Questions))
+	var valid []*services.QueryRequest
+	var validIndex []int
+	for i, question := range req.Questions {
+		question.Question = cleanText(question.Question)
+		items[i] = models.BatchQueryItem{Index: i, Question: question.Question}
+		if err := h.validator.Struct(&question); err != nil {
+			items[i].Error = "Validation failed: " + err.Error()
+			continue
+		}
+		if message := h.questionLengthError("Question", question.Question); message != "" {
+			items[i].Error = message
+			continue
+		}
+		if _, ok := services.NormalizeLanguage(question.Language); !ok {
+			items[i].Error = fmt.Sprintf("Unsupported language %q", question.Language)
+			continue
+		}
+		valid = append(valid, &services.QueryRequest{
+			UserID:          question.UserID,
+			Question:        question.Question,
+			RequestID:       requestID,
+			IncludeVisuals:  question.IncludeVisuals,
+			Language:        question.Language,
+			BypassCache:     question.BypassCache,
+			RetrievalSource: question.RetrievalSource,
+			SessionID:       question.SessionID,
+		})
+		validIndex = append(validIndex, i)
+	}
+
+	h.logger.Info("Processing query batch",
+		zap.Int("questions", len(req.Questions)),
+		zap.Int("valid", len(valid)),
+		zap.String("request_id", requestID))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.batchCfg.Timeout)
+	defer cancel()
+
+	results := h.container.QueryService().ProcessQueryBatch(ctx, valid, h.batchCfg.Concurrency)
+	for j, result := range results {
+		item := &items[validIndex[j]]
+		if result.DuplicateOf != nil {
+			duplicateOf := validIndex[*result.DuplicateOf]
+			item.DuplicateOf = &duplicateOf
+		}
+		// The service reports internal failures with the generic message
+		if result.Error != "" {
+			item.Error = result.Error
+			continue
+		}
+		response := newQueryResponse(item.Question, result.Result, result.Result.ProcessingTime)
+		item.Response = &response
+	}
+
+	failed := 0
+	for _, item := range items {
+		if item.Error != "" {
+			failed++
+		}
+	}
+
+	processingTime := time.Since(start)
+	h.logger.Info("Query batch processed",
+		zap.Int("questions", len(items)),
+		zap.Int("failed", failed),
+		zap.Duration("processing_time", processingTime),
+		zap.String("request_id", requestID))
+
+	respond(c, http.StatusOK, models.BatchQueryResponse{
+		Results:        items,
+		Total:          len(items),
+		Succeeded:      len(items) - failed,
+		Failed:         failed,
+		ProcessingTime: processingTime,
+	})
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetQueryContext returns the vector chunks retrieved for a processed query
+// with their certainty scores, sources and chapters
+// GET /api/v1/query/:id/context
+func (h *Handler) GetQueryContext(c *gin.Context) {
+	queryID := c.Param("id")
+
+	queryContext, err := h.container.QueryService().GetQueryContext(c.Request.Context(), queryID)
+	if err != nil {
+		h.logger.Error("Failed to get query context",
+			zap.String("query_id", queryID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get query context")
+		return
+	}
+	if queryContext == nil {
+		respondError(c, http.StatusNotFound, "Query not found")
+		return
+	}
+
+	respond(c, http.StatusOK, queryContext)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetQueryGraph returns a processed query's prerequisite path as nodes and
+// links for force-directed graph layouts, with the target concept flagged
+// GET /api/v1/query/:id/graph
+func (h *Handler) GetQueryGraph(c *gin.Context) {
+	queryID := c.Param("id")
+
+	graph, err := h.container.QueryService().GetQueryGraph(c.Request.Context(), queryID)
+	if err != nil {
+		h.logger.Error("Failed to get query graph",
+			zap.String("query_id", queryID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get query graph")
+		return
+	}
+	if graph == nil {
+		respondError(c, http.StatusNotFound, "Query not found")
+		return
+	}
+
+	respond(c, http.StatusOK, graph)
+}
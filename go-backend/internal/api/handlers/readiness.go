@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// GetConceptReadiness reports which prerequisites of a concept, at any depth,
+// are not among the concepts the learner already knows
+// GET /api/v1/concepts/:id/readiness?known=limits,functions
+func (h *Handler) GetConceptReadiness(c *gin.Context) {
+	conceptID := c.Param("id")
+
+	readiness, err := h.container.QueryService().CheckReadiness(c.Request.Context(), conceptID, knownConcepts(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to check readiness"
+		if errors.Is(err, services.ErrConceptNotFound) {
+			status = http.StatusNotFound
+			message = "Concept not found"
+		}
+
+		h.logger.Warn("Failed to check concept readiness",
+			zap.String("concept_id", conceptID),
+			zap.Error(err))
+		respondError(c, status, message)
+		return
+	}
+
+	respond(c, http.StatusOK, readiness)
+}
+
+// knownConcepts reads the comma-separated concept IDs of the known parameter
+func knownConcepts(c *gin.Context) []string {
+	var known []string
+	for _, id := range strings.Split(c.Query("known"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			known = append(known, id)
+		}
+	}
+	return known
+}
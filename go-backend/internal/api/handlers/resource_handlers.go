@@ -29,13 +29,11 @@ type BatchResourceRequest struct {
 	Limit        int      `json:"limit,omitempty"`
 }
 
-// ResourceResponse represents a resource response
+// ResourceResponse is the data of a resource lookup response
 type ResourceResponse struct {
-	Success    bool                          `json:"success"`
 	Message    string                        `json:"message"`
 	Resources  []scraper.EducationalResource `json:"resources,omitempty"`
 	TotalFound int                           `json:"total_found,omitempty"`
-	RequestID  string                        `json:"request_id"`
 }
 
 // ResourceManager manages scraper instances and connections
@@ -153,17 +151,6 @@ func (h *Handler) getResourceManager() *ResourceManager {
 	return resourceManager
 }
 
-// getRequestID safely extracts request ID from context
-func getRequestID(c *gin.Context) string {
-	if requestID := c.GetString("request_id"); requestID != "" {
-		return requestID
-	}
-	if requestID := c.GetHeader("X-Request-ID"); requestID != "" {
-		return requestID
-	}
-	return "unknown"
-}
-
 // preprocessConceptName normalizes concept names for better processing
 func preprocessConceptName(concept string) string {
 	// Decode URL encoding
@@ -182,17 +169,20 @@ func preprocessConceptName(concept string) string {
 	return normalized
 }
 
-// FindResourcesForConcept handles POST /api/v1/resources/find/:concept
+// FindResourcesForConcept handles POST /api/v1/resources/find/:concept.
+// With dry_run=true it waits for the scrape and returns the resources it
+// would store, without storing them, so curators can vet sources first.
 func (h *Handler) FindResourcesForConcept(c *gin.Context) {
 	requestID := getRequestID(c)
 	concept := c.Param("concept")
 
 	if concept == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success":    false,
-			"message":    "Concept parameter is required",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusBadRequest, "Concept parameter is required")
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "dry_run must be true or false")
 		return
 	}
 
@@ -208,11 +198,7 @@ func (h *Handler) FindResourcesForConcept(c *gin.Context) {
 	manager := h.getResourceManager()
 	if manager == nil || manager.scraper == nil {
 		h.logger.Error("Resource manager not available")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success":    false,
-			"message":    "Resource service not available",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusInternalServerError, "Resource service not available")
 		return
 	}
 
@@ -220,6 +206,24 @@ func (h *Handler) FindResourcesForConcept(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 55*time.Second)
 	defer cancel()
 
+	if dryRun {
+		candidates, err := manager.scraper.PreviewResourcesForConcepts(ctx, []string{concept})
+		if err != nil {
+			h.logger.Error("Failed to preview resources",
+				zap.Error(err),
+				zap.String("concept", concept),
+				zap.String("request_id", requestID))
+			respondError(c, http.StatusInternalServerError, "Failed to preview resources")
+			return
+		}
+		respond(c, http.StatusOK, ResourceResponse{
+			Message:    "Dry run: these resources would be stored; nothing was saved.",
+			Resources:  candidates,
+			TotalFound: len(candidates),
+		})
+		return
+	}
+
 	// Start scraping asynchronously
 	go func() {
 		manager.mutex.Lock()
@@ -250,12 +254,10 @@ func (h *Handler) FindResourcesForConcept(c *gin.Context) {
 		zap.Int("immediate_resources", len(resources)),
 		zap.String("request_id", requestID))
 
-	c.JSON(http.StatusOK, ResourceResponse{
-		Success:    true,
+	respond(c, http.StatusOK, ResourceResponse{
 		Message:    "Resource finding initiated. Check back in a few minutes for more results.",
 		Resources:  resources,
 		TotalFound: len(resources),
-		RequestID:  requestID,
 	})
 }
 
@@ -265,11 +267,7 @@ func (h *Handler) GetResourcesForConcept(c *gin.Context) {
 	concept := c.Param("concept")
 
 	if concept == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success":    false,
-			"message":    "Concept parameter is required",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusBadRequest, "Concept parameter is required")
 		return
 	}
 
@@ -294,11 +292,7 @@ func (h *Handler) GetResourcesForConcept(c *gin.Context) {
 	manager := h.getResourceManager()
 	if manager == nil || manager.scraper == nil {
 		h.logger.Error("Resource manager not available")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success":    false,
-			"message":    "Resource service not available",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusInternalServerError, "Resource service not available")
 		return
 	}
 
@@ -310,11 +304,7 @@ func (h *Handler) GetResourcesForConcept(c *gin.Context) {
 		generateConceptID(concept), limit)
 	if err != nil {
 		h.logger.Error("Failed to get resources", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success":    false,
-			"message":    "Failed to retrieve resources",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve resources")
 		return
 	}
 
@@ -323,12 +313,10 @@ func (h *Handler) GetResourcesForConcept(c *gin.Context) {
 		zap.Int("count", len(resources)),
 		zap.String("request_id", requestID))
 
-	c.JSON(http.StatusOK, ResourceResponse{
-		Success:    true,
+	respond(c, http.StatusOK, ResourceResponse{
 		Message:    "Resources retrieved successfully",
 		Resources:  resources,
 		TotalFound: len(resources),
-		RequestID:  requestID,
 	})
 }
 
@@ -357,12 +345,10 @@ func (h *Handler) ListResources(c *gin.Context) {
 		zap.String("request_id", requestID))
 
 	// TODO: Implement pagination logic with MongoDB aggregation
-	c.JSON(http.StatusOK, gin.H{
-		"success":    true,
-		"message":    "Resource listing feature coming soon",
-		"page":       page,
-		"limit":      limit,
-		"request_id": requestID,
+	respond(c, http.StatusOK, gin.H{
+		"message": "Resource listing feature coming soon",
+		"page":    page,
+		"limit":   limit,
 	})
 }
 
@@ -376,11 +362,7 @@ func (h *Handler) GetResourceStats(c *gin.Context) {
 	manager := h.getResourceManager()
 	if manager == nil || manager.scraper == nil {
 		h.logger.Error("Resource manager not available")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success":    false,
-			"message":    "Resource service not available",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusInternalServerError, "Resource service not available")
 		return
 	}
 
@@ -391,11 +373,7 @@ func (h *Handler) GetResourceStats(c *gin.Context) {
 	stats, err := manager.scraper.GetResourceStats(c.Request.Context())
 	if err != nil {
 		h.logger.Error("Failed to get resource statistics", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success":    false,
-			"message":    "Failed to retrieve resource statistics",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusInternalServerError, "Failed to retrieve resource statistics")
 		return
 	}
 
@@ -403,11 +381,9 @@ func (h *Handler) GetResourceStats(c *gin.Context) {
 		zap.Any("stats", stats),
 		zap.String("request_id", requestID))
 
-	c.JSON(http.StatusOK, gin.H{
-		"success":    true,
-		"message":    "Resource statistics retrieved successfully",
-		"stats":      stats,
-		"request_id": requestID,
+	respond(c, http.StatusOK, gin.H{
+		"message": "Resource statistics retrieved successfully",
+		"stats":   stats,
 	})
 }
 
@@ -418,29 +394,17 @@ func (h *Handler) FindResourcesForConcepts(c *gin.Context) {
 	var req BatchResourceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("Invalid batch resource request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success":    false,
-			"message":    "Invalid request format",
-			"error":      err.Error(),
-			"request_id": requestID,
-		})
+		respondError(c, bindErrorStatus(err), "Invalid request format: "+err.Error())
 		return
 	}
 
+	// Trim, drop blanks and duplicates before spending scraping effort
+	req.ConceptNames = normalizeConceptNames(req.ConceptNames, maxBatchConcepts)
 	if len(req.ConceptNames) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success":    false,
-			"message":    "At least one concept name is required",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusBadRequest, "At least one valid concept name is required")
 		return
 	}
 
-	// Limit batch size
-	if len(req.ConceptNames) > 10 {
-		req.ConceptNames = req.ConceptNames[:10]
-	}
-
 	h.logger.Info("Finding resources for multiple concepts",
 		zap.Strings("concepts", req.ConceptNames),
 		zap.String("request_id", requestID))
@@ -449,11 +413,7 @@ func (h *Handler) FindResourcesForConcepts(c *gin.Context) {
 	manager := h.getResourceManager()
 	if manager == nil || manager.scraper == nil {
 		h.logger.Error("Resource manager not available")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success":    false,
-			"message":    "Resource service not available",
-			"request_id": requestID,
-		})
+		respondError(c, http.StatusInternalServerError, "Resource service not available")
 		return
 	}
 
@@ -476,14 +436,41 @@ func (h *Handler) FindResourcesForConcepts(c *gin.Context) {
 		zap.Strings("concepts", req.ConceptNames),
 		zap.String("request_id", requestID))
 
-	c.JSON(http.StatusAccepted, gin.H{
-		"success":        true,
+	respond(c, http.StatusAccepted, gin.H{
 		"message":        "Batch resource finding initiated. This may take several minutes to complete.",
 		"concepts_count": len(req.ConceptNames),
-		"request_id":     requestID,
+		"concepts":       req.ConceptNames,
 	})
 }
 
+// maxBatchConcepts caps how many concepts a single batch request scrapes
+const maxBatchConcepts = 10
+
+// normalizeConceptNames cleans a batch of concept names: whitespace is
+// normalized, blanks are dropped and names mapping to the same concept ID are
+// deduplicated (case-insensitively), keeping the first spelling. At most max
+// names are returned.
+func normalizeConceptNames(names []string, max int) []string {
+	cleaned := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		name = preprocessConceptName(name)
+		id := generateConceptID(name)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		cleaned = append(cleaned, name)
+
+		if len(cleaned) == max {
+			break
+		}
+	}
+
+	return cleaned
+}
+
 // Helper function to generate concept ID (same as scraper)
 func generateConceptID(conceptName string) string {
 	id := strings.ToLower(conceptName)
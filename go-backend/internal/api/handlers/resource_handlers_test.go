@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeConceptNames(t *testing.T) {
+	input := []string{
+		"  Derivatives ",
+		"",
+		"   ",
+		"derivatives",
+		"Chain   Rule",
+		"chain-rule",
+		"LIMITS",
+		"limits",
+		"???",
+		"Integration%20by%20Parts",
+	}
+
+	got := normalizeConceptNames(input, 10)
+	want := []string{"Derivatives", "Chain Rule", "LIMITS", "Integration by Parts"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeConceptNames() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeConceptNamesCapsAfterCleaning(t *testing.T) {
+	input := []string{"", "a", "A", "b", "c"}
+
+	got := normalizeConceptNames(input, 2)
+	want := []string{"a", "b"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeConceptNames() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeConceptNamesRejectsAllBlank(t *testing.T) {
+	if got := normalizeConceptNames([]string{"", "  ", "!!"}, 10); len(got) != 0 {
+		t.Errorf("expected no valid names, got %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+	"github.com/mathprereq/internal/core/llm"
+)
+
+// serviceBusyMessage is shown when the LLM quota is exhausted
+const serviceBusyMessage = "The tutoring service is busy right now. Please try again in a moment."
+
+// respond writes data wrapped in the success envelope
+func respond(c *gin.Context, status int, data interface{}) {
+	c.JSON(status, models.NewSuccessResponse(getRequestID(c), data))
+}
+
+// respondError writes message in the error envelope
+func respondError(c *gin.Context, status int, message string) {
+	c.JSON(status, models.NewErrorResponse(getRequestID(c), message, nil))
+}
+
+// respondErrorWithData writes an error envelope whose data carries details
+// that help the client fix the request
+func respondErrorWithData(c *gin.Context, status int, message string, data interface{}) {
+	c.JSON(status, models.NewErrorResponse(getRequestID(c), message, data))
+}
+
+// respondIfServiceBusy answers 503 with a Retry-After header when err comes
+// from an exhausted LLM quota, and reports whether it did
+func respondIfServiceBusy(c *gin.Context, err error) bool {
+	var quotaErr *llm.QuotaError
+	if !errors.As(err, &quotaErr) {
+		return false
+	}
+	if quotaErr.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(quotaErr.RetryAfter.Seconds()))))
+	}
+	respondError(c, http.StatusServiceUnavailable, serviceBusyMessage)
+	return true
+}
+
+// getRequestID safely extracts request ID from context
+func getRequestID(c *gin.Context) string {
+	if requestID := c.GetString("request_id"); requestID != "" {
+		return requestID
+	}
+	if requestID := c.GetHeader("X-Request-ID"); requestID != "" {
+		return requestID
+	}
+	return "unknown"
+}
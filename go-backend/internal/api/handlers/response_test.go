@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+	"github.com/mathprereq/internal/core/llm"
+)
+
+func decodeEnvelope(t *testing.T, rec *httptest.ResponseRecorder) map[string]json.RawMessage {
+	t.Helper()
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not a JSON object: %v (%s)", err, rec.Body.String())
+	}
+	return body
+}
+
+func envelopeKeys(body map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(body))
+	for key := range body {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestResponseEnvelopeShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("request_id", "req-123")
+		c.Next()
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		respond(c, http.StatusOK, gin.H{"answer": 42})
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		respondErrorWithData(c, http.StatusBadRequest, "bad language", gin.H{"supported_languages": []string{"en"}})
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		respondError(c, http.StatusNotFound, "not found")
+	})
+
+	tests := []struct {
+		path        string
+		wantStatus  int
+		wantKeys    []string
+		wantSuccess string
+		wantError   string
+	}{
+		{"/ok", http.StatusOK, []string{"data", "request_id", "success", "timestamp"}, "true", ""},
+		{"/fail", http.StatusBadRequest, []string{"data", "error", "request_id", "success", "timestamp"}, "false", `"bad language"`},
+		{"/missing", http.StatusNotFound, []string{"error", "request_id", "success", "timestamp"}, "false", `"not found"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := decodeEnvelope(t, rec)
+			if keys := envelopeKeys(body); !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if got := string(body["success"]); got != tt.wantSuccess {
+				t.Errorf("success = %s, want %s", got, tt.wantSuccess)
+			}
+			if got := string(body["request_id"]); got != `"req-123"` {
+				t.Errorf("request_id = %s, want \"req-123\"", got)
+			}
+			if tt.wantError != "" && string(body["error"]) != tt.wantError {
+				t.Errorf("error = %s, want %s", body["error"], tt.wantError)
+			}
+		})
+	}
+}
+
+func TestConceptQueryRequestBindsUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/concept-query",
+		strings.NewReader(`{"concept_name":"derivatives","user_id":"student-42"}`))
+
+	var req models.ConceptQueryRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		t.Fatalf("expected user_id to be accepted, got %v", err)
+	}
+	if req.UserID != "student-42" {
+		t.Errorf("UserID = %q, want student-42", req.UserID)
+	}
+}
+
+func TestRespondIfServiceBusy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/query", nil)
+	err := fmt.Errorf("failed to process query: %w",
+		&llm.QuotaError{RetryAfter: 1500 * time.Millisecond, Err: errors.New("429")})
+	if !respondIfServiceBusy(c, err) {
+		t.Fatal("expected quota error to be answered")
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	if respondIfServiceBusy(c, errors.New("neo4j down")) {
+		t.Error("expected other errors to be left to the caller")
+	}
+}
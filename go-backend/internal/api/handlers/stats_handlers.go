@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultConceptPairsLimit = 20
+	maxConceptPairsLimit     = 100
+
+	defaultPopularConceptsLimit = 20
+	maxPopularConceptsLimit     = 100
+
+	defaultPerformanceWindowHours = 24
+	maxPerformanceWindowHours     = 30 * 24
+)
+
+// GetConceptPairs returns concept pairs most often identified in the same
+// query, hinting at commonly confused or naturally paired concepts
+// GET /api/v1/stats/concept-pairs?limit=20
+func (h *Handler) GetConceptPairs(c *gin.Context) {
+	limit := defaultConceptPairsLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxConceptPairsLimit {
+		limit = maxConceptPairsLimit
+	}
+
+	pairs, err := h.container.QueryService().GetConceptPairs(c.Request.Context(), limit)
+	if err != nil {
+		h.logger.Error("Failed to get concept pairs", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get concept pairs")
+		return
+	}
+
+	respond(c, http.StatusOK, pairs)
+}
+
+// GetPopularConcepts returns the concepts identified in the most queries.
+// half_life ranks them by recency instead, a query counting half as much for
+// every half_life since it was asked; zero or absent keeps all-time counts.
+// A half_life below a millisecond is rejected.
+// GET /api/v1/stats/popular-concepts?limit=20&half_life=168h
+func (h *Handler) GetPopularConcepts(c *gin.Context) {
+	limit := defaultPopularConceptsLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(parsed, maxPopularConceptsLimit)
+	}
+	var halfLife time.Duration
+	if raw := c.Query("half_life"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < 0 || (parsed > 0 && parsed < time.Millisecond) {
+			respondError(c, http.StatusBadRequest, "half_life must be 0 or a duration of at least 1ms such as 168h")
+			return
+		}
+		halfLife = parsed
+	}
+
+	concepts, err := h.container.QueryService().GetPopularConcepts(c.Request.Context(), limit, halfLife)
+	if err != nil {
+		h.logger.Error("Failed to get popular concepts", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get popular concepts")
+		return
+	}
+	if concepts == nil {
+		concepts = []repositories.ConceptPopularity{}
+	}
+
+	respond(c, http.StatusOK, concepts)
+}
+
+// GetPerformanceStats returns p50/p95/p99 durations per pipeline step and
+// per data source over the last hours, slowest first
+// GET /api/v1/stats/performance?hours=24
+func (h *Handler) GetPerformanceStats(c *gin.Context) {
+	hours := defaultPerformanceWindowHours
+	if raw := c.Query("hours"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, "hours must be a positive integer")
+			return
+		}
+		hours = min(parsed, maxPerformanceWindowHours)
+	}
+
+	stats, err := h.container.QueryService().GetPerformanceStats(c.Request.Context(), time.Duration(hours)*time.Hour)
+	if err != nil {
+		h.logger.Error("Failed to get performance stats", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get performance stats")
+		return
+	}
+
+	respond(c, http.StatusOK, stats)
+}
+
+// GetSystemStats returns knowledge graph, vector store and query store
+// counts. Subsystems that are down are reported unavailable with the system
+// health degraded rather than failing the request.
+// GET /api/v1/stats/system
+func (h *Handler) GetSystemStats(c *gin.Context) {
+	stats, err := h.container.QueryService().GetSystemStats(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get system stats", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to get system stats")
+		return
+	}
+
+	respond(c, http.StatusOK, stats)
+}
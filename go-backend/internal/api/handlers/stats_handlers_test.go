@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestGetPopularConceptsRejectsInvalidHalfLife(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{logger: zap.NewNop()}
+
+	for _, halfLife := range []string{"-1h", "soon", "500us", "1ns"} {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/stats/popular-concepts?half_life="+halfLife, nil)
+
+		h.GetPopularConcepts(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("half_life=%s answered %d, want 400", halfLife, rec.Code)
+		}
+	}
+}
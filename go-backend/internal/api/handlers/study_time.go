@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetConceptStudyTime estimates how long it takes to learn a concept and
+// the prerequisites on its path the learner does not already know
+// GET /api/v1/concepts/:id/study-time?known=limits,functions
+func (h *Handler) GetConceptStudyTime(c *gin.Context) {
+	conceptID := c.Param("id")
+
+	estimate, err := h.container.QueryService().EstimateStudyTime(c.Request.Context(), conceptID, knownConcepts(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to estimate study time"
+		if strings.Contains(err.Error(), "concept not found") {
+			status = http.StatusNotFound
+			message = "Concept not found"
+		}
+
+		h.logger.Warn("Failed to estimate concept study time",
+			zap.String("concept_id", conceptID),
+			zap.Error(err))
+		respondError(c, status, message)
+		return
+	}
+
+	respond(c, http.StatusOK, estimate)
+}
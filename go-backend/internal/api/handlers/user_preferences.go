@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+	"github.com/mathprereq/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+// GetUserPreferences returns the explanation level and style applied to a
+// user's queries; both are empty when the user has set none
+// GET /api/v1/users/:id/preferences
+func (h *Handler) GetUserPreferences(c *gin.Context) {
+	userID := c.Param("id")
+
+	preferences, err := h.container.QueryService().GetUserPreferences(c.Request.Context(), userID)
+	if err != nil {
+		h.respondPreferencesError(c, userID, err)
+		return
+	}
+
+	respond(c, http.StatusOK, models.UserPreferencesResponse{UserID: userID, Preferences: *preferences})
+}
+
+// UpdateUserPreferences replaces a user's explanation preferences
+// PUT /api/v1/users/:id/preferences
+// {"explanation_level": "beginner", "explanation_style": "step_by_step"}
+func (h *Handler) UpdateUserPreferences(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req entities.UserPreferences
+	if err := bindStrictJSON(c, &req); err != nil {
+		respondError(c, bindErrorStatus(err), "Invalid request format: "+err.Error())
+		return
+	}
+
+	preferences, err := h.container.QueryService().UpdateUserPreferences(c.Request.Context(), userID, req)
+	if err != nil {
+		h.respondPreferencesError(c, userID, err)
+		return
+	}
+
+	respond(c, http.StatusOK, models.UserPreferencesResponse{UserID: userID, Preferences: *preferences})
+}
+
+func (h *Handler) respondPreferencesError(c *gin.Context, userID string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "unsupported"), strings.Contains(err.Error(), "required"):
+		respondError(c, http.StatusBadRequest, err.Error())
+	case strings.Contains(err.Error(), "not available"):
+		respondError(c, http.StatusServiceUnavailable, err.Error())
+	default:
+		h.logger.Error("Failed to access user preferences",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "Failed to access user preferences")
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+)
+
+// AdminTokenHeader carries the token that authenticates admin requests
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminAuth rejects requests without token in the X-Admin-Token header with
+// 401. With no token configured, admin routes are open when allowOpen is
+// set, as in development, and refused with 403 otherwise.
+func AdminAuth(token string, allowOpen bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			if allowOpen {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, models.NewErrorResponse(
+				c.GetString("request_id"), "Admin endpoints are disabled; set ADMIN_TOKEN to enable them", nil))
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(AdminTokenHeader)), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.NewErrorResponse(
+				c.GetString("request_id"), "Admin token required", nil))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		allowOpen bool
+		header    string
+		want      int
+	}{
+		{"valid token", "s3cret", false, "s3cret", http.StatusOK},
+		{"missing token", "s3cret", true, "", http.StatusUnauthorized},
+		{"wrong token", "s3cret", false, "guess", http.StatusUnauthorized},
+		{"unconfigured outside development", "", false, "", http.StatusForbidden},
+		{"unconfigured in development", "", true, "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			admin := router.Group("/api/v1/admin", AdminAuth(tt.token, tt.allowOpen))
+			admin.GET("/queries", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/queries", nil)
+			if tt.header != "" {
+				req.Header.Set(AdminTokenHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathprereq/internal/api/models"
+)
+
+// BodyLimit caps request bodies at maxBytes. Requests that declare a larger
+// Content-Length are rejected with 413 up front; for the rest the body is
+// wrapped in http.MaxBytesReader so reads past the limit fail and handlers
+// can answer 413 themselves.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, models.NewErrorResponse(
+				c.GetString("request_id"), "Request body too large", gin.H{"max_bytes": maxBytes}))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
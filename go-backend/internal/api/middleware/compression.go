@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Compress gzips responses of at least minSize bytes for clients sending
+// Accept-Encoding: gzip. Smaller bodies are sent as-is. Server-sent event
+// streams and responses that flush before reaching minSize are never
+// compressed, so streaming keeps working.
+func Compress(minSize, level int) gin.HandlerFunc {
+	pool := sync.Pool{
+		New: func() interface{} {
+			gz, err := gzip.NewWriterLevel(nil, level)
+			if err != nil {
+				gz = gzip.NewWriter(nil)
+			}
+			return gz
+		},
+	}
+
+	return func(c *gin.Context) {
+		if !acceptsGzip(c.Request) {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, minSize: minSize, pool: &pool}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		c.Next()
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+		return false
+	}
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// errResponseFinished is returned for writes after the middleware finished
+// the response, e.g. by a handler still running after its request timed out
+var errResponseFinished = errors.New("response already finished")
+
+// compressWriter buffers the body until it knows whether it is worth
+// compressing, then either streams through gzip or passes bytes through.
+// A handler that outlives its timeout can write concurrently with finish, so
+// mu guards the state and writes after finish are refused; the gzip writer
+// is back in the pool by then, serving another response.
+type compressWriter struct {
+	gin.ResponseWriter
+	minSize int
+	pool    *sync.Pool
+
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	gz       *gzip.Writer
+	decided  bool
+	finished bool
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.finished {
+		return 0, errResponseFinished
+	}
+
+	if !w.decided {
+		if !w.compressible() {
+			w.passThrough()
+		} else {
+			w.buf.Write(data)
+			if w.buf.Len() >= w.minSize {
+				if err := w.startGzip(); err != nil {
+					return 0, err
+				}
+			}
+			return len(data), nil
+		}
+	}
+
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends whatever is buffered; a response flushed before reaching the
+// size threshold is treated as a stream and left uncompressed
+func (w *compressWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.finished {
+		return
+	}
+
+	if !w.decided {
+		w.passThrough()
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// compressible reports whether the response may be gzipped based on headers
+// set so far
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	return !strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+}
+
+func (w *compressWriter) startGzip() error {
+	w.decided = true
+
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+
+	w.gz = w.pool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+
+	_, err := w.gz.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) passThrough() {
+	w.decided = true
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// finish writes any small buffered body uncompressed and closes the gzip stream
+func (w *compressWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.finished = true
+
+	if !w.decided {
+		w.passThrough()
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCompressionRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Compress(1024, gzip.DefaultCompression))
+
+	router.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"explanation": strings.Repeat("derivatives ", 500)})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			c.Writer.WriteString("data: " + strings.Repeat("x", 600) + "\n\n")
+			c.Writer.Flush()
+		}
+	})
+	return router
+}
+
+func request(router *gin.Engine, path string, acceptGzip bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptGzip {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompressLargeResponse(t *testing.T) {
+	rec := request(newCompressionRouter(), "/large", true)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got headers %v", rec.Header())
+	}
+
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "derivatives derivatives") {
+		t.Errorf("unexpected decompressed body %.80q", body)
+	}
+	if len(body) <= rec.Body.Len() {
+		t.Errorf("compressed body (%d bytes) is not smaller than original (%d bytes)", rec.Body.Len(), len(body))
+	}
+}
+
+func TestCompressSkipsSmallResponse(t *testing.T) {
+	rec := request(newCompressionRouter(), "/small", true)
+
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("small response should not be compressed")
+	}
+	if rec.Body.String() != `{"success":true}` {
+		t.Errorf("unexpected body %q", rec.Body.String())
+	}
+}
+
+func TestCompressSkipsEventStream(t *testing.T) {
+	rec := request(newCompressionRouter(), "/stream", true)
+
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("event stream should not be compressed")
+	}
+	if strings.Count(rec.Body.String(), "data: ") != 3 {
+		t.Errorf("expected 3 events in body")
+	}
+	if !rec.Flushed {
+		t.Error("expected stream to be flushed")
+	}
+}
+
+func TestCompressRespectsAcceptEncoding(t *testing.T) {
+	rec := request(newCompressionRouter(), "/large", false)
+
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("response should not be compressed without Accept-Encoding: gzip")
+	}
+}
+
+func TestCompressWriterRefusesWritesAfterFinish(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	pool := &sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+	writer := &compressWriter{ResponseWriter: c.Writer, minSize: 16, pool: pool}
+
+	body := strings.Repeat("derivatives ", 10)
+	if _, err := writer.WriteString(body); err != nil {
+		t.Fatal(err)
+	}
+
+	// A handler still running after its timeout keeps writing while the
+	// middleware finishes the response
+	late := make(chan error, 1)
+	go func() {
+		for {
+			if _, err := writer.WriteString("late"); err != nil {
+				late <- err
+				return
+			}
+		}
+	}()
+	writer.finish()
+
+	if err := <-late; !errors.Is(err, errResponseFinished) {
+		t.Errorf("late write error = %v, want errResponseFinished", err)
+	}
+	writer.Flush()
+
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("response is not a complete gzip stream: %v", err)
+	}
+	if !strings.HasPrefix(string(got), body) || strings.TrimLeft(strings.TrimPrefix(string(got), body), "late") != "" {
+		t.Errorf("unexpected body %.80q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(allowedOrigins []string, allowCredentials bool, maxAge time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(allowedOrigins, allowCredentials, maxAge))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestCORSAllowOrigin(t *testing.T) {
+	tests := []struct {
+		name             string
+		allowedOrigins   []string
+		allowCredentials bool
+		origin           string
+		wantOrigin       string
+		wantCredentials  string
+	}{
+		{"listed origin", []string{"https://app.mathprereq.com"}, true, "https://app.mathprereq.com", "https://app.mathprereq.com", "true"},
+		{"unlisted origin", []string{"https://app.mathprereq.com"}, true, "https://evil.example", "", "true"},
+		{"wildcard without credentials", []string{"*"}, false, "https://evil.example", "*", ""},
+		{"wildcard with credentials reflects origin", []string{"*"}, true, "https://partner.example", "https://partner.example", "true"},
+		{"no origin", []string{"*"}, true, "", "", "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newCORSRouter(tt.allowedOrigins, tt.allowCredentials, time.Hour)
+
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			gotOrigin := rec.Header().Get("Access-Control-Allow-Origin")
+			if gotOrigin != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", gotOrigin, tt.wantOrigin)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+			if gotOrigin == "*" && tt.allowCredentials {
+				t.Error("wildcard origin sent together with credentials")
+			}
+			wantVary := "Origin"
+			if tt.allowedOrigins[0] == "*" && !tt.allowCredentials {
+				wantVary = ""
+			}
+			if got := rec.Header().Get("Vary"); got != wantVary {
+				t.Errorf("Vary = %q, want %q", got, wantVary)
+			}
+		})
+	}
+}
+
+func TestCORSPreflightMaxAge(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		want   string
+	}{
+		{"two hours", 2 * time.Hour, "7200"},
+		{"disabled", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newCORSRouter([]string{"https://app.mathprereq.com"}, true, tt.maxAge)
+
+			req := httptest.NewRequest(http.MethodOptions, "/ping", nil)
+			req.Header.Set("Origin", "https://app.mathprereq.com")
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusNoContent {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+			}
+			if got := rec.Header().Get("Access-Control-Max-Age"); got != tt.want {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCORSVaryKeptWithCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS([]string{"https://app.mathprereq.com"}, true, time.Hour))
+	router.Use(Compress(1, -1))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// A same-origin request, without an Origin header
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	vary := rec.Header().Values("Vary")
+	if len(vary) != 2 || vary[0] != "Origin" || vary[1] != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Origin and Accept-Encoding", vary)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"math/rand"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/mathprereq/internal/api/models"
 	"go.uber.org/zap"
 )
 
@@ -97,19 +99,14 @@ func Timeout(duration time.Duration) gin.HandlerFunc {
 		case <-ctx.Done():
 			// Context cancelled (timeout or client disconnect)
 			if ctx.Err() == context.DeadlineExceeded {
-				c.AbortWithStatusJSON(http.StatusRequestTimeout, gin.H{
-					"success":    false,
-					"error":      "Request timeout - the operation took too long",
-					"request_id": c.GetString("request_id"),
-					"timeout":    duration.String(),
-					"message":    "Please try again or simplify your query",
-				})
+				c.AbortWithStatusJSON(http.StatusRequestTimeout, models.NewErrorResponse(
+					c.GetString("request_id"),
+					"Request timeout - the operation took too long. Please try again or simplify your query",
+					gin.H{"timeout": duration.String()},
+				))
 			} else if ctx.Err() == context.Canceled {
-				c.AbortWithStatusJSON(http.StatusRequestTimeout, gin.H{
-					"success":    false,
-					"error":      "Request cancelled",
-					"request_id": c.GetString("request_id"),
-				})
+				c.AbortWithStatusJSON(http.StatusRequestTimeout, models.NewErrorResponse(
+					c.GetString("request_id"), "Request cancelled", nil))
 			}
 			return
 		}
@@ -132,43 +129,49 @@ func Recovery(logger *zap.Logger) gin.HandlerFunc {
 			zap.String("stack", stack))
 
 		// Return structured error response
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"success":    false,
-			"error":      "Internal server error",
-			"request_id": requestID,
-		})
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.NewErrorResponse(
+			requestID, "Internal server error", nil))
 	})
 }
 
-// CORS middleware for cross-origin requests
-func CORS() gin.HandlerFunc {
+// CORS middleware for cross-origin requests. allowedOrigins may contain "*".
+// Browsers reject a wildcard Allow-Origin on credentialed requests, so with
+// allowCredentials the request's origin is echoed back instead. In debug mode
+// every origin is allowed. maxAge sets how long preflight responses are cached.
+func CORS(allowedOrigins []string, allowCredentials bool, maxAge time.Duration) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[origin] = true
+	}
+	wildcard := allowed["*"]
+	maxAgeSeconds := strconv.Itoa(int(maxAge / time.Second))
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
-		// Allow specific origins or all origins in development
-		allowedOrigins := []string{
-			"http://localhost:3000",
-			"http://localhost:3001",
-			"https://mathprereq.com",
-			"https://app.mathprereq.com",
+		// Unless every origin gets "*", the response depends on Origin, even
+		// for requests without one, so caches must not share it across origins
+		if !wildcard || allowCredentials || gin.Mode() == gin.DebugMode {
+			c.Writer.Header().Add("Vary", "Origin")
 		}
 
-		allowed := false
-		for _, allowedOrigin := range allowedOrigins {
-			if origin == allowedOrigin {
-				allowed = true
-				break
-			}
-		}
-
-		if allowed || gin.Mode() == gin.DebugMode {
+		switch {
+		case origin == "":
+			// Not a cross-origin request
+		case allowed[origin] || gin.Mode() == gin.DebugMode || (wildcard && allowCredentials):
 			c.Header("Access-Control-Allow-Origin", origin)
+		case wildcard:
+			c.Header("Access-Control-Allow-Origin", "*")
 		}
 
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Max-Age", "86400")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID, X-LLM-Override-Token, X-Admin-Token")
+		if allowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		if maxAge > 0 {
+			c.Header("Access-Control-Max-Age", maxAgeSeconds)
+		}
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -3,58 +3,185 @@ package models
 import (
 	"time"
 
+	"github.com/mathprereq/internal/arithmetic"
 	"github.com/mathprereq/internal/data/scraper"
+	"github.com/mathprereq/internal/domain/entities"
 )
 
-type ErrorResponse struct {
-	Success   bool      `json:"success"`
-	Error     string    `json:"error"`
-	RequestID string    `json:"request_id"`
-	Timestamp time.Time `json:"timestamp"`
+// Response is the envelope every JSON API response is wrapped in. Data holds
+// the endpoint's payload; Error is set instead when Success is false.
+type Response struct {
+	Success   bool        `json:"success"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	RequestID string      `json:"request_id"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// NewSuccessResponse wraps data in a successful envelope
+func NewSuccessResponse(requestID string, data interface{}) Response {
+	return Response{
+		Success:   true,
+		Data:      data,
+		RequestID: requestID,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// NewErrorResponse builds a failed envelope; data may carry details that
+// help the client recover, such as the accepted values of a field
+func NewErrorResponse(requestID, message string, data interface{}) Response {
+	return Response{
+		Success:   false,
+		Data:      data,
+		Error:     message,
+		RequestID: requestID,
+		Timestamp: time.Now().UTC(),
+	}
 }
 
 type QueryRequest struct {
-	UserID   string `json:"user_id,omitempty" validate:"omitempty,uuid"`
-	Question string `json:"question" validate:"required,min=3,max=1000"`
+	UserID string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	// Question may be at most QUERY_MAX_QUESTION_LENGTH characters long
+	Question string `json:"question" validate:"required,min=3"`
+
+	// IncludeVisuals requests generated plots for functions in the explanation
+	IncludeVisuals bool `json:"include_visuals,omitempty"`
+
+	// Language is the ISO 639-1 code for the explanation, e.g. "es"; defaults to English
+	Language string `json:"language,omitempty"`
+
+	// BypassCache skips the repeated-question cache and always answers fresh
+	BypassCache bool `json:"bypass_cache,omitempty"`
+
+	// RetrievalSource picks the context searched: textbook chunks, concept
+	// descriptions, or both; defaults to the server setting
+	RetrievalSource string `json:"retrieval_source,omitempty" validate:"omitempty,oneof=textbook concepts both"`
+
+	// SessionID keeps anonymous users in one experiment variant across queries
+	SessionID string `json:"session_id,omitempty" validate:"omitempty,max=128"`
+
+	// Model explains this question with an allowed override model instead
+	// of the routed one. It is ignored unless the request carries the
+	// X-LLM-Override-Token header.
+	Model string `json:"model,omitempty" validate:"omitempty,max=100"`
+
+	// Context is sent by the web clients alongside the question. It is
+	// accepted so strict binding does not reject them, and ignored.
+	Context string `json:"context,omitempty"`
 }
 
 type QueryResponse struct {
-	Success            bool          `json:"success"`
-	Query              string        `json:"query"`
-	IdentifiedConcepts []string      `json:"identified_concepts"`
-	LearningPath       LearningPath  `json:"learning_path"`
-	Explanation        string        `json:"explanation"`
-	RetrievedContext   []string      `json:"retrieved_context,omitempty"`
-	ProcessingTime     time.Duration `json:"processing_time"`
-	ErrorMessage       *string       `json:"error_message,omitempty"`
-	RequestID          string        `json:"request_id,omitempty"`
-	Timestamp          time.Time     `json:"timestamp"`
+	// Status is ok, no_concepts_identified, concepts_not_in_graph or degraded
+	Status             string                 `json:"status"`
+	QueryID            string                 `json:"query_id,omitempty"`
+	Query              string                 `json:"query"`
+	IdentifiedConcepts []string               `json:"identified_concepts"`
+	LearningPath       LearningPath           `json:"learning_path"`
+	SuggestedPath      *LearningPath          `json:"suggested_path,omitempty"` // LLM-suggested, only when learning_path is empty
+	Explanation        string                 `json:"explanation"`
+	Fallback           bool                   `json:"fallback,omitempty"`         // Explanation is a template, the LLM was unavailable
+	ModelProfile       string                 `json:"model_profile,omitempty"`    // "fast" or "deep" model routing
+	ArithmeticCheck    *arithmetic.Report     `json:"arithmetic_check,omitempty"` // verified or flagged numeric steps
+	SourcesUsed        []entities.CitedSource `json:"sources_used,omitempty"`     // context chunks the explanation cited
+	RetrievedContext   []string               `json:"retrieved_context,omitempty"`
+	ProcessingTime     time.Duration          `json:"processing_time"`
+	Source             string                 `json:"source"`              // "cache" or "processed"
+	CacheAge           *time.Duration         `json:"cache_age,omitempty"` // set for cached answers
 
 	// Educational resources found for the concepts
 	EducationalResources []scraper.EducationalResource `json:"educational_resources,omitempty"`
 	ResourcesMessage     string                        `json:"resources_message,omitempty"`
+
+	// Generated plots, only present when include_visuals was requested
+	VisualAids []VisualAidInfo `json:"visual_aids,omitempty"`
+}
+
+// ExplainMistakeRequest is a problem and the student's incorrect attempt at it
+type ExplainMistakeRequest struct {
+	UserID string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	// Problem may be at most QUERY_MAX_QUESTION_LENGTH characters long
+	Problem string `json:"problem" validate:"required,min=3"`
+	Attempt string `json:"attempt" validate:"required,min=1,max=4000"`
+
+	// Language is the ISO 639-1 code for the explanation, e.g. "es"; defaults to English
+	Language string `json:"language,omitempty"`
+}
+
+// ExplainMistakeResponse pinpoints where the attempt goes wrong
+type ExplainMistakeResponse struct {
+	QueryID            string                       `json:"query_id,omitempty"`
+	Problem            string                       `json:"problem"`
+	IdentifiedConcepts []string                     `json:"identified_concepts"`
+	LearningPath       LearningPath                 `json:"learning_path"`
+	Explanation        *entities.MistakeExplanation `json:"explanation"`
+	RetrievedContext   []string                     `json:"retrieved_context,omitempty"`
+	ProcessingTime     time.Duration                `json:"processing_time"`
+}
+
+// ConceptPathsRequest asks for the prerequisite paths of several concepts
+type ConceptPathsRequest struct {
+	ConceptIDs []string `json:"concept_ids" binding:"required"`
+}
+
+// BatchQueryRequest submits several questions to be answered in one call
+type BatchQueryRequest struct {
+	Questions []QueryRequest `json:"questions"`
+}
+
+// BatchQueryItem is the answer to one question of a batch, in request order.
+// Either Response or Error is set.
+type BatchQueryItem struct {
+	Index       int            `json:"index"`
+	Question    string         `json:"question"`
+	Response    *QueryResponse `json:"response,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	DuplicateOf *int           `json:"duplicate_of,omitempty"` // index of the identical question answered first
+}
+
+type BatchQueryResponse struct {
+	Results        []BatchQueryItem `json:"results"`
+	Total          int              `json:"total"`
+	Succeeded      int              `json:"succeeded"`
+	Failed         int              `json:"failed"`
+	ProcessingTime time.Duration    `json:"processing_time"`
+}
+
+type VisualAidInfo struct {
+	Title      string `json:"title"`
+	Expression string `json:"expression"`
+	URL        string `json:"url"`
 }
 
 // ConceptQueryRequest represents a smart concept query request
 type ConceptQueryRequest struct {
 	ConceptName string `json:"concept_name" binding:"required" validate:"required,min=2,max=100"`
-	UserID      string `json:"user_iD,omitempty" validate:"max=50"`
+	UserID      string `json:"user_id,omitempty" validate:"max=50"`
+
+	// IncludeResources and IncludeLearningPath default to true; false leaves
+	// the educational resources or learning path out of the response
+	IncludeResources    *bool `json:"include_resources,omitempty"`
+	IncludeLearningPath *bool `json:"include_learning_path,omitempty"`
+
+	// MaxResources caps the educational resources returned; defaults to 10
+	MaxResources int `json:"max_resources,omitempty" validate:"omitempty,min=1,max=50"`
 }
 
 // ConceptQueryResponse represents the response for concept queries
 type ConceptQueryResponse struct {
-	Success            bool           `json:"success"`
-	ConceptName        string         `json:"concept_name"`
-	Source             string         `json:"source"` // "cache" or "processed"
-	IdentifiedConcepts []string       `json:"identified_concepts"`
-	LearningPath       LearningPath   `json:"learning_path"`
-	Explanation        string         `json:"explanation"`
-	RetrievedContext   []string       `json:"retrieved_context,omitempty"`
-	ProcessingTime     time.Duration  `json:"processing_time"`
-	CacheAge           *time.Duration `json:"cache_age,omitempty"` // How old the cached data is
-	ErrorMessage       *string        `json:"error_message,omitempty"`
-	RequestID          string         `json:"request_id"`
-	Timestamp          time.Time      `json:"timestamp"`
+	Status             string                 `json:"status"` // same values as QueryResponse.Status
+	ConceptName        string                 `json:"concept_name"`
+	Source             string                 `json:"source"` // "cache" or "processed"
+	IdentifiedConcepts []string               `json:"identified_concepts"`
+	LearningPath       LearningPath           `json:"learning_path"`
+	Explanation        string                 `json:"explanation"`
+	Fallback           bool                   `json:"fallback,omitempty"`         // Explanation is a template, the LLM was unavailable
+	ModelProfile       string                 `json:"model_profile,omitempty"`    // "fast" or "deep" model routing
+	ArithmeticCheck    *arithmetic.Report     `json:"arithmetic_check,omitempty"` // verified or flagged numeric steps
+	SourcesUsed        []entities.CitedSource `json:"sources_used,omitempty"`     // context chunks the explanation cited
+	RetrievedContext   []string               `json:"retrieved_context,omitempty"`
+	ProcessingTime     time.Duration          `json:"processing_time"`
+	CacheAge           *time.Duration         `json:"cache_age,omitempty"` // How old the cached data is
 
 	// Educational resources
 	EducationalResources []scraper.EducationalResource `json:"educational_resources,omitempty"`
@@ -63,16 +190,19 @@ type ConceptQueryResponse struct {
 
 type ConceptDetailRequest struct {
 	ConceptID string `json:"concept_id" validate:"required"`
+	// KnownConcepts are the IDs of concepts the learner has already
+	// learned; when given, the response reports which prerequisites are met
+	KnownConcepts []string `json:"known_concepts,omitempty"`
 }
 
 type ConceptDetailResponse struct {
-	Success             bool          `json:"success"`
 	Concept             *ConceptInfo  `json:"concept,omitempty"`
 	Prerequisites       []ConceptInfo `json:"prerequisites"`
 	LeadsTo             []ConceptInfo `json:"leads_to"`
 	DetailedExplanation string        `json:"detailed_explanation"`
-	RequestID           string        `json:"request_id"`
-	ErrorMessage        *string       `json:"error_message,omitempty"`
+	// ReadyToLearn is set when the request named known concepts, true when
+	// every prerequisite is among them
+	ReadyToLearn *bool `json:"ready_to_learn,omitempty"`
 }
 
 type ConceptInfo struct {
@@ -80,6 +210,16 @@ type ConceptInfo struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Type        string `json:"type"`
+	Category    string `json:"category,omitempty"`
+	Difficulty  int    `json:"difficulty,omitempty"`
+	// Learned is set on prerequisites when the learner's known concepts
+	// were given
+	Learned *bool `json:"learned,omitempty"`
+
+	// Weight and Importance are set on learning path prerequisites: how
+	// essential they are to the targets, labelled must_know or helpful
+	Weight     float64 `json:"weight,omitempty"`
+	Importance string  `json:"importance,omitempty"`
 }
 
 type LearningPath struct {
@@ -87,3 +227,9 @@ type LearningPath struct {
 	TotalConcepts int           `json:"total_concepts"`
 	PathType      string        `json:"path_type"`
 }
+
+// UserPreferencesResponse is a user's stored explanation preferences
+type UserPreferencesResponse struct {
+	UserID      string                   `json:"user_id"`
+	Preferences entities.UserPreferences `json:"preferences"`
+}
@@ -1,14 +1,19 @@
 package routes
 
 import (
+	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mathprereq/internal/api/handlers"
 	"github.com/mathprereq/internal/api/middleware"
+	"github.com/mathprereq/internal/api/models"
 	"github.com/mathprereq/internal/container"
 	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
@@ -28,84 +33,315 @@ func SetupRoutes(
 	router.Use(middleware.RequestID())
 	router.Use(middleware.RequestLogger(logger))
 	router.Use(middleware.Recovery(logger))
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORS(cfg.Server.CORSAllowedOrigins, cfg.Server.CORSAllowCredentials, cfg.Server.CORSMaxAge))
 	router.Use(middleware.SecurityHeaders())
-	router.Use(middleware.Timeout(50 * time.Second))
+	router.Use(middleware.Compress(cfg.Server.CompressionMinSize, cfg.Server.CompressionLevel))
+	router.Use(middleware.BodyLimit(cfg.Server.MaxBodySize))
+	timeout := routeTimeouts(cfg.Server.RouteTimeouts)
+	longRunning := useGlobalTimeout(router, timeout("global"))
 
 	// Initialize handlers
-	handler := handlers.NewHandler(container, logger)
-	adminHandler := handlers.NewAdminHandler(container.QueryService(), logger)
+	handler := handlers.NewHandler(container, cfg.QueryBatch, cfg.QueryInput, cfg.LLM.Override, logger)
+	adminHandler := handlers.NewAdminHandler(container.QueryService(), cfg.Staging.StaleAfter, logger)
 
 	// Health checks (no timeout)
 	router.GET("/health", handler.HealthCheck)
 	router.GET("/api/v1/health", handler.HealthCheck)
 	router.GET("/api/v1/health-detailed", handler.HealthCheck)
+	router.GET("/ready", handler.Readiness)
+	router.GET("/api/v1/ready", handler.Readiness)
+
+	// Prometheus metrics
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewStagedConceptCollector(
+		container.QueryService().GetStaleStagedConcepts,
+		cfg.Staging.StaleAfter,
+		logger,
+	))
+	registry.MustRegister(metrics.NewSchedulerCollector(container.Scheduler().Stats))
+	registry.MustRegister(metrics.NewWorkerPoolCollector(container.ScrapePool().Stats))
+	if queue := container.ScrapeQueue(); queue != nil {
+		registry.MustRegister(metrics.NewScrapeQueueCollector(queue.Stats, logger))
+	}
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(registry, promhttp.HandlerOpts{})))
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Query processing
 		v1.POST("/query",
-			middleware.Timeout(45*time.Second),
+			timeout("query"),
 			handler.ProcessQuery)
 
+		// Several questions at once, answered with bounded concurrency. Its
+		// timeout follows QUERY_BATCH_TIMEOUT, which may exceed the global one.
+		longRunning.POST("/query/batch",
+			middleware.Timeout(cfg.QueryBatch.Timeout+5*time.Second),
+			handler.ProcessQueryBatch)
+
+		// Where a student's attempt at a problem goes wrong
+		v1.POST("/explain-mistake",
+			timeout("explain_mistake"),
+			handler.ExplainMistake)
+
+		// Generated plots for a processed query
+		v1.GET("/queries/:id/visuals/:n",
+			timeout("query_visual"),
+			handler.GetQueryVisual)
+
+		// Prerequisite path of a processed query as a node-link graph
+		v1.GET("/query/:id/graph",
+			timeout("query_graph"),
+			handler.GetQueryGraph)
+
+		// Retrieved context chunks of a processed query, with scores
+		v1.GET("/query/:id/context",
+			timeout("query_context"),
+			handler.GetQueryContext)
+
 		// Concept operations
 		v1.POST("/concept-detail",
-			middleware.Timeout(15*time.Second),
+			timeout("concept_detail"),
 			handler.GetConceptDetail)
 
 		v1.GET("/concepts",
-			middleware.Timeout(30*time.Second),
+			timeout("concepts"),
 			handler.ListConcepts)
 
+		// Concept name suggestions for search boxes, from an in-memory index
+		v1.GET("/concepts/autocomplete",
+			timeout("concept_autocomplete"),
+			handler.AutocompleteConcepts)
+
+		// Key formulas of a concept, extracted from textbooks at ingestion
+		v1.GET("/formulas/search",
+			timeout("formula_search"),
+			handler.SearchFormulas)
+
+		// Local prerequisite/dependent subgraph for graph widgets
+		v1.GET("/concepts/:id/neighborhood",
+			timeout("concept_neighborhood"),
+			handler.GetConceptNeighborhood)
+
+		// Structured concept profile (definition, examples, common mistakes).
+		// Generating one may outlast the global timeout.
+		longRunning.GET("/concepts/:id/profile",
+			timeout("concept_profile"),
+			handler.GetConceptProfile)
+
+		// Missing prerequisites of a concept, from precomputed sets
+		v1.GET("/concepts/:id/readiness",
+			timeout("concept_readiness"),
+			handler.GetConceptReadiness)
+
+		// Rough time to work through a concept's prerequisite path
+		v1.GET("/concepts/:id/study-time",
+			timeout("concept_study_time"),
+			handler.GetConceptStudyTime)
+
+		// Prerequisites in learning order plus the concepts a concept leads to
+		v1.GET("/concepts/:id/roadmap",
+			timeout("concept_roadmap"),
+			handler.GetConceptRoadmap)
+
+		// Prerequisite paths of many concepts at once, for curriculum maps
+		v1.POST("/concepts/paths",
+			timeout("concept_paths"),
+			handler.GetConceptPaths)
+
+		// Explanation level and style applied to all of a user's queries
+		v1.GET("/users/:id/preferences",
+			timeout("user_preferences"),
+			handler.GetUserPreferences)
+
+		v1.PUT("/users/:id/preferences",
+			timeout("user_preferences"),
+			handler.UpdateUserPreferences)
+
+		// Curriculum change tracking
+		v1.POST("/concepts/:id/path-snapshots",
+			timeout("path_snapshot"),
+			handler.CapturePathSnapshot)
+
+		v1.GET("/concepts/:id/path-diff",
+			timeout("path_diff"),
+			handler.GetConceptPathDiff)
+
 		// Learning Resources (New Feature)
 		resources := v1.Group("/resources")
 		{
 			// Find and scrape resources for a concept (triggered by button click)
 			resources.POST("/find/:concept",
-				middleware.Timeout(60*time.Second), // Longer timeout for scraping
+				timeout("resources_find"), // Longer timeout for scraping
 				handler.FindResourcesForConcept)
 
 			// Get stored resources for a concept
 			resources.GET("/concept/:concept",
-				middleware.Timeout(15*time.Second),
+				timeout("resources_by_concept"),
 				handler.GetResourcesForConcept)
 
 			// Get all resources with pagination and filtering
 			resources.GET("/",
-				middleware.Timeout(30*time.Second),
+				timeout("resources_list"),
 				handler.ListResources)
 
 			// Get resource statistics and analytics
 			resources.GET("/stats",
-				middleware.Timeout(15*time.Second),
+				timeout("resources_stats"),
 				handler.GetResourceStats)
 
 			// Bulk find resources for multiple concepts
 			resources.POST("/find-batch",
-				middleware.Timeout(120*time.Second), // Extended for batch operations
+				timeout("resources_find_batch"), // Extended for batch operations
 				handler.FindResourcesForConcepts)
 		}
 
+		// Query log insights
+		stats := v1.Group("/stats")
+		{
+			// Concepts frequently identified together in one query
+			stats.GET("/concept-pairs",
+				timeout("stats_concept_pairs"),
+				handler.GetConceptPairs)
+
+			// Most asked-about concepts, optionally favoring recent queries
+			stats.GET("/popular-concepts",
+				timeout("stats_popular_concepts"),
+				handler.GetPopularConcepts)
+
+			// Latency percentiles per pipeline step and data source
+			stats.GET("/performance",
+				timeout("stats_performance"),
+				handler.GetPerformanceStats)
+
+			// Graph, vector store and query store counts and health
+			stats.GET("/system",
+				timeout("stats_system"),
+				handler.GetSystemStats)
+		}
+
 		// Admin routes for concept staging
-		admin := v1.Group("/admin")
+		// Admin routes need the admin token, except in development without one
+		adminAuth := middleware.AdminAuth(cfg.Server.AdminToken, cfg.Server.Environment == "development")
+		admin := v1.Group("/admin", adminAuth)
+		longRunningAdmin := longRunning.Group("/admin", adminAuth)
 		{
 			admin.GET("/staged-concepts/pending",
-				middleware.Timeout(30*time.Second),
+				timeout("admin_staged_pending"),
 				adminHandler.GetPendingConcepts)
 
 			admin.GET("/staged-concepts/stats",
-				middleware.Timeout(15*time.Second),
+				timeout("admin_staged_stats"),
 				adminHandler.GetStagedConceptStats)
 
+			admin.GET("/staged-concepts/stale",
+				timeout("admin_staged_stale"),
+				adminHandler.GetStaleConcepts)
+
+			admin.GET("/staged-concepts/:id",
+				timeout("admin_staged_concept"),
+				adminHandler.GetStagedConcept)
+
+			// What approval would create, without creating it
+			admin.GET("/staged-concepts/:id/approval-preview",
+				timeout("admin_approval_preview"),
+				adminHandler.PreviewApproval)
+
 			admin.POST("/staged-concepts/:id/review",
-				middleware.Timeout(30*time.Second),
+				timeout("admin_review"),
 				adminHandler.ReviewStagedConcept)
+
+			// Concepts mentioned in queries but missing from the graph
+			admin.GET("/unknown-concepts",
+				timeout("admin_unknown_concepts"),
+				adminHandler.GetUnknownConcepts)
+
+			// Textbook support per graph concept and textbook concepts the graph lacks
+			longRunningAdmin.GET("/coverage",
+				timeout("admin_coverage"),
+				adminHandler.GetConceptCoverage)
+
+			// Difficulty changes suggested by query success rates
+			admin.GET("/difficulty-calibration",
+				timeout("admin_difficulty"),
+				adminHandler.GetDifficultyCalibration)
+
+			// Concept aliases, with suggestions mined from queries
+			admin.GET("/concept-aliases",
+				timeout("admin_aliases"),
+				adminHandler.GetConceptAliases)
+
+			admin.GET("/concept-aliases/suggestions",
+				timeout("admin_alias_suggestions"),
+				adminHandler.GetConceptAliasSuggestions)
+
+			admin.POST("/concept-aliases",
+				timeout("admin_add_alias"),
+				adminHandler.AddConceptAlias)
+
+			// Fold a duplicate concept into another
+			admin.POST("/concepts/merge",
+				timeout("admin_merge_concepts"),
+				adminHandler.MergeConcepts)
+
+			// Check a prerequisite edge before adding it by hand
+			admin.GET("/edges/validate",
+				timeout("admin_validate_edge"),
+				adminHandler.ValidatePrerequisiteEdge)
+
+			// Stored queries of every user, for reviewing answers
+			admin.GET("/queries",
+				timeout("admin_queries"),
+				adminHandler.ListQueries)
+
+			// Curated resources listed before scraped ones
+			admin.PUT("/resources/:id/pin",
+				timeout("admin_pin_resource"),
+				adminHandler.PinResource)
+
+			// Thumbnails, durations and channels of stored videos
+			longRunningAdmin.POST("/resources/enrich-videos",
+				timeout("admin_enrich_videos"),
+				adminHandler.EnrichVideoMetadata)
+
+			// A/B experiment on explanation prompts and models
+			admin.GET("/experiment",
+				timeout("admin_experiment"),
+				adminHandler.GetExperiment)
+
+			admin.GET("/experiment/results",
+				timeout("admin_experiment_results"),
+				adminHandler.GetExperimentResults)
+
+			// Fill in blank concept descriptions with generated ones
+			longRunningAdmin.POST("/concept-descriptions/generate",
+				timeout("admin_descriptions"),
+				adminHandler.GenerateConceptDescriptions)
+
+			// Explanation versions of a concept, with rollback
+			admin.GET("/explanations/:concept/versions",
+				timeout("admin_explanation_versions"),
+				adminHandler.GetExplanationVersions)
+
+			admin.POST("/explanations/:concept/versions/:version/activate",
+				timeout("admin_activate_explanation"),
+				adminHandler.ActivateExplanationVersion)
+
+			// Rebuild every concept's transitive prerequisite set
+			longRunningAdmin.POST("/prerequisite-closures/refresh",
+				timeout("admin_closures_refresh"),
+				adminHandler.RefreshPrerequisiteClosures)
+
+			// How essential a prerequisite is to a concept
+			admin.PUT("/prerequisites/weight",
+				timeout("admin_prerequisite_weight"),
+				adminHandler.SetPrerequisiteWeight)
 		}
 
-		// Smart concept query - checks MongoDB first, then processes if needed
-		v1.POST("/concept-query",
-			middleware.Timeout(3*time.Minute),
+		// Smart concept query - checks MongoDB first, then processes if needed.
+		// Processing may outlast the global timeout.
+		longRunning.POST("/concept-query",
+			timeout("concept_query"),
 			handler.SmartConceptQuery)
 	}
 
@@ -119,30 +355,33 @@ func SetupRoutes(
 				sanitizedCfg.MongoDB.URI = maskSensitive(cfg.MongoDB.URI)
 				sanitizedCfg.Neo4j.Password = "***"
 				sanitizedCfg.LLM.APIKey = "***"
+				sanitizedCfg.LLM.Override.Token = "***"
+				sanitizedCfg.Server.AdminToken = "***"
 				sanitizedCfg.Weaviate.APIKey = "***"
-				c.JSON(200, sanitizedCfg)
+				c.JSON(http.StatusOK, models.NewSuccessResponse(c.GetString("request_id"), sanitizedCfg))
 			})
 
 			debug.GET("/health-check", func(c *gin.Context) {
 				health := container.HealthCheck(c.Request.Context())
-				c.JSON(200, gin.H{
+				c.JSON(http.StatusOK, models.NewSuccessResponse(c.GetString("request_id"), gin.H{
 					"health_status": health,
 					"all_healthy":   allHealthy(health),
-				})
+				}))
 			})
 
-			// New debug endpoint for cached concepts
+			// Most recent stored queries of every user; see /api/v1/admin/queries
+			// for filtering and pagination
 			debug.GET("/cached-concepts", func(c *gin.Context) {
 				limit := 20 // Default limit
 				if limitStr := c.Query("limit"); limitStr != "" {
 					if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-						limit = parsedLimit
+						limit = min(parsedLimit, 100)
 					}
 				}
 
 				queries, err := container.QueryService().GetCachedConcepts(c.Request.Context(), limit)
 				if err != nil {
-					c.JSON(500, gin.H{"error": err.Error()})
+					c.JSON(http.StatusInternalServerError, models.NewErrorResponse(c.GetString("request_id"), err.Error(), nil))
 					return
 				}
 
@@ -159,12 +398,11 @@ func SetupRoutes(
 					}
 				}
 
-				c.JSON(200, gin.H{
+				c.JSON(http.StatusOK, models.NewSuccessResponse(c.GetString("request_id"), gin.H{
 					"cached_concepts": simplified,
 					"total_count":     len(simplified),
 					"limit":           limit,
-					"timestamp":       time.Now(),
-				})
+				}))
 			})
 		}
 	}
@@ -172,6 +410,31 @@ func SetupRoutes(
 	return router
 }
 
+// useGlobalTimeout adds the global timeout to router and returns an
+// /api/v1 group without it, for long-running jobs bounded by their own
+// route timeout only. The group is taken first so it doesn't inherit it.
+func useGlobalTimeout(router *gin.Engine, global gin.HandlerFunc) *gin.RouterGroup {
+	longRunning := router.Group("/api/v1")
+	router.Use(global)
+	return longRunning
+}
+
+// routeTimeouts returns the timeout middleware of a named route, using the
+// default for routes missing from configured
+func routeTimeouts(configured map[string]time.Duration) func(name string) gin.HandlerFunc {
+	defaults := config.DefaultRouteTimeouts()
+	return func(name string) gin.HandlerFunc {
+		duration, ok := configured[name]
+		if !ok {
+			duration, ok = defaults[name]
+		}
+		if !ok {
+			panic("no timeout defined for route " + name)
+		}
+		return middleware.Timeout(duration)
+	}
+}
+
 func maskSensitive(uri string) string {
 	// Simple masking for URIs containing credentials
 	if len(uri) > 20 {
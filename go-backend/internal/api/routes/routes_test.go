@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// remainingTime answers with how long the request context has left
+func remainingTime(c *gin.Context) {
+	deadline, ok := c.Request.Context().Deadline()
+	if !ok {
+		c.String(http.StatusOK, "none")
+		return
+	}
+	c.String(http.StatusOK, time.Until(deadline).String())
+}
+
+func routeDeadline(t *testing.T, timeouts map[string]time.Duration, route string) time.Duration {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", routeTimeouts(timeouts)(route), remainingTime)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	remaining, err := time.ParseDuration(w.Body.String())
+	if err != nil {
+		t.Fatalf("expected a deadline on the request, got %q", w.Body.String())
+	}
+	return remaining
+}
+
+func TestRouteTimeoutUsesConfiguredValue(t *testing.T) {
+	remaining := routeDeadline(t, map[string]time.Duration{"query": 90 * time.Second}, "query")
+	if remaining <= 85*time.Second || remaining > 90*time.Second {
+		t.Errorf("expected the configured 90s timeout, %s remained", remaining)
+	}
+}
+
+func TestRouteTimeoutFallsBackToDefault(t *testing.T) {
+	remaining := routeDeadline(t, nil, "concept_autocomplete")
+	if remaining <= 0 || remaining > 5*time.Second {
+		t.Errorf("expected the default 5s timeout, %s remained", remaining)
+	}
+}
+
+func TestRouteTimeoutUnknownRoutePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a route without a timeout to panic")
+		}
+	}()
+	routeTimeouts(nil)("no_such_route")
+}
+
+func TestLongRunningRoutesSkipGlobalTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	timeout := routeTimeouts(map[string]time.Duration{"global": 5 * time.Second})
+	router := gin.New()
+	longRunning := useGlobalTimeout(router, timeout("global"))
+	longRunning.POST("/admin/job", timeout("admin_descriptions"), remainingTime)
+	router.POST("/api/v1/query", remainingTime)
+
+	deadline := func(path string) time.Duration {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		remaining, err := time.ParseDuration(w.Body.String())
+		if err != nil {
+			t.Fatalf("expected a deadline on %s, got %q", path, w.Body.String())
+		}
+		return remaining
+	}
+
+	if remaining := deadline("/api/v1/admin/job"); remaining <= 5*time.Second {
+		t.Errorf("expected the long-running route to outlive the global timeout, %s remained", remaining)
+	}
+	if remaining := deadline("/api/v1/query"); remaining > 5*time.Second {
+		t.Errorf("expected other routes to keep the global timeout, %s remained", remaining)
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"math/rand"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/entities"
+)
+
+// analyticsSampler decides which queries are stored as full documents
+type analyticsSampler struct {
+	sampleRate          float64
+	alwaysStoreFailures bool
+	random              func() float64
+}
+
+func newAnalyticsSampler(cfg config.AnalyticsConfig) *analyticsSampler {
+	return &analyticsSampler{
+		sampleRate:          cfg.SampleRate,
+		alwaysStoreFailures: cfg.AlwaysStoreFailures,
+		random:              rand.Float64,
+	}
+}
+
+// shouldStore reports whether the query should be written in full
+func (a *analyticsSampler) shouldStore(query *entities.Query) bool {
+	// Overridden queries are evaluations, which need the full record
+	if query.ModelOverride != "" {
+		return true
+	}
+	if !query.Success && a.alwaysStoreFailures {
+		return true
+	}
+	if a.sampleRate >= 1 {
+		return true
+	}
+	if a.sampleRate <= 0 {
+		return false
+	}
+	return a.random() < a.sampleRate
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+func TestAnalyticsSamplerHonoursSampleRate(t *testing.T) {
+	sampler := newAnalyticsSampler(config.AnalyticsConfig{SampleRate: 0.25})
+	sampler.random = rand.New(rand.NewSource(42)).Float64
+
+	const total = 10000
+	stored := 0
+	for i := 0; i < total; i++ {
+		if sampler.shouldStore(&entities.Query{Success: true}) {
+			stored++
+		}
+	}
+
+	if rate := float64(stored) / total; rate < 0.23 || rate > 0.27 {
+		t.Errorf("expected ~25%% of queries stored, got %.3f", rate)
+	}
+}
+
+func TestAnalyticsSamplerAlwaysStoresFailures(t *testing.T) {
+	sampler := newAnalyticsSampler(config.AnalyticsConfig{SampleRate: 0, AlwaysStoreFailures: true})
+
+	if !sampler.shouldStore(&entities.Query{Success: false}) {
+		t.Error("expected failed query to be stored")
+	}
+	if sampler.shouldStore(&entities.Query{Success: true}) {
+		t.Error("expected successful query to be skipped at sample rate 0")
+	}
+
+	sampler.alwaysStoreFailures = false
+	if sampler.shouldStore(&entities.Query{Success: false}) {
+		t.Error("expected failed query to be sampled when AlwaysStoreFailures is off")
+	}
+}
+
+func TestAnalyticsSamplerFullRateStoresEverything(t *testing.T) {
+	sampler := newAnalyticsSampler(config.AnalyticsConfig{SampleRate: 1})
+	sampler.random = func() float64 { return 0.999 }
+
+	if !sampler.shouldStore(&entities.Query{Success: true}) {
+		t.Error("expected every query stored at sample rate 1")
+	}
+}
+
+// countingQueryRepo counts the queries left out of the sample
+type countingQueryRepo struct {
+	savingQueryRepo
+	unsampled int
+}
+
+func (r *countingQueryRepo) RecordUnsampled(ctx context.Context, query *entities.Query) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unsampled++
+	return nil
+}
+
+func TestProcessQueryFlagsUnsampledQueries(t *testing.T) {
+	for _, rate := range []float64{0, 1} {
+		tasks := background.NewTasks()
+		queries := &countingQueryRepo{}
+		svc := &queryService{
+			conceptRepo: &pathConceptRepo{},
+			queryRepo:   queries,
+			vectorRepo:  &stubVectorRepo{},
+			llmClient:   &recordingLLM{concepts: []string{"derivatives"}},
+			sampler:     &analyticsSampler{sampleRate: rate},
+			tasks:       tasks,
+			logger:      zap.NewNop(),
+		}
+
+		result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a derivative?"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		drainTasks(t, tasks)
+
+		stored := len(queries.saved) == 1 && queries.unsampled == 0
+		if rate == 0 && (stored || !result.Unsampled) {
+			t.Errorf("at rate 0: stored=%v unsampled=%v, want the query flagged and not stored", stored, result.Unsampled)
+		}
+		if rate == 1 && (!stored || result.Unsampled) {
+			t.Errorf("at rate 1: stored=%v unsampled=%v, want the query stored", stored, result.Unsampled)
+		}
+	}
+}
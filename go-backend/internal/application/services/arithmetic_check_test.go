@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/arithmetic"
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+func TestProcessQueryArithmeticCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		wantStatus string
+	}{
+		{"disabled", false, ""},
+		{"enabled", true, arithmetic.StatusFlagged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := background.NewTasks()
+			queries := &savingQueryRepo{}
+			svc := &queryService{
+				conceptRepo: &pathConceptRepo{},
+				queryRepo:   queries,
+				vectorRepo:  &stubVectorRepo{},
+				llmClient: &recordingLLM{
+					concepts:    []string{"chain rule"},
+					explanation: map[string]string{"English": "At x = 2: 3 * 2^2 = 12, then 12 + 4 = 15."},
+				},
+				verifyArithmetic: tt.enabled,
+				sampler:          &analyticsSampler{sampleRate: 1},
+				tasks:            tasks,
+				logger:           zap.NewNop(),
+			}
+
+			result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "Differentiate x^3 + 4x at x = 2"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			drainTasks(t, tasks)
+
+			if !tt.enabled {
+				if result.ArithmeticCheck != nil {
+					t.Errorf("ArithmeticCheck = %+v, want nil when disabled", result.ArithmeticCheck)
+				}
+				return
+			}
+			if result.ArithmeticCheck == nil || result.ArithmeticCheck.Status != tt.wantStatus {
+				t.Fatalf("ArithmeticCheck = %+v, want status %q", result.ArithmeticCheck, tt.wantStatus)
+			}
+			if issues := result.ArithmeticCheck.Issues; len(issues) != 1 || issues[0].Step != "12 + 4 = 15" {
+				t.Errorf("Issues = %+v, want the 12 + 4 = 15 step", issues)
+			}
+			if queries.saved[0].Response.ArithmeticCheck == nil {
+				t.Error("arithmetic check was not stored with the query")
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github.com/mathprereq/internal/citations"
+	"github.com/mathprereq/internal/domain/entities"
+)
+
+// citedSources maps the [Context n] markers in an explanation to the chunks
+// it was given, numbered from 1 in retrieval order as the prompt lists them.
+// Markers naming context the model never saw are ignored, so an explanation
+// without citations, or with only invalid ones, has no sources.
+func citedSources(explanation string, chunks []entities.ContextChunk) []entities.CitedSource {
+	var sources []entities.CitedSource
+	for _, n := range citations.Markers(explanation, len(chunks)) {
+		chunk := chunks[n-1]
+		sources = append(sources, entities.CitedSource{
+			Marker:      n,
+			Source:      chunk.Source,
+			Chapter:     chunk.Chapter,
+			Concept:     chunk.Concept,
+			SourceClass: chunk.SourceClass,
+			Score:       chunk.Score,
+		})
+	}
+	return sources
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+func TestCitedSources(t *testing.T) {
+	chunks := []entities.ContextChunk{
+		{Content: "limits", Source: "Calculus Vol 1", Chapter: "2.1", Concept: "limits", Score: 0.9},
+		{Content: "chain rule", Source: "Calculus Vol 1", Chapter: "3.6", Concept: "chain rule", Score: 0.8},
+		{Content: "power rule", Source: "Stewart", Chapter: "3.1", Concept: "power rule", Score: 0.7},
+	}
+
+	tests := []struct {
+		name        string
+		explanation string
+		want        []int
+	}{
+		{"cited", "Differentiate the outside first [Context 2], using the power rule [Context 3, 2].", []int{2, 3}},
+		{"invalid only", "As shown in [Context 7].", nil},
+		{"no citations", "Differentiate the outside first.", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources := citedSources(tt.explanation, chunks)
+			var markers []int
+			for _, source := range sources {
+				markers = append(markers, source.Marker)
+				chunk := chunks[source.Marker-1]
+				if source.Source != chunk.Source || source.Chapter != chunk.Chapter || source.Concept != chunk.Concept {
+					t.Errorf("source %d = %+v, want chunk %+v", source.Marker, source, chunk)
+				}
+			}
+			if !reflect.DeepEqual(markers, tt.want) {
+				t.Errorf("markers = %v, want %v", markers, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessQuerySourcesUsed(t *testing.T) {
+	question := "How do I differentiate sin(x^2)?"
+	tasks := background.NewTasks()
+	queries := &savingQueryRepo{}
+	svc := &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   queries,
+		vectorRepo: &stubVectorRepo{results: map[string][]types.VectorResult{question: {
+			{Content: "The derivative of sin is cos", Score: 0.9, Metadata: map[string]interface{}{"source": "Calculus Vol 1", "chapter": "3.5"}},
+			{Content: "The chain rule", Score: 0.8, Metadata: map[string]interface{}{"source": "Calculus Vol 1", "chapter": "3.6"}},
+		}}},
+		llmClient: &recordingLLM{
+			concepts:    []string{"chain rule"},
+			explanation: map[string]string{"English": "Apply the chain rule [Context 2]: cos(x^2) * 2x."},
+		},
+		sampler: &analyticsSampler{sampleRate: 1},
+		tasks:   tasks,
+		logger:  zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: question})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	want := []entities.CitedSource{{Marker: 2, Source: "Calculus Vol 1", Chapter: "3.6", Score: 0.8}}
+	if !reflect.DeepEqual(result.SourcesUsed, want) {
+		t.Errorf("SourcesUsed = %+v, want %+v", result.SourcesUsed, want)
+	}
+	if !reflect.DeepEqual(queries.saved[0].Response.SourcesUsed, want) {
+		t.Errorf("stored SourcesUsed = %+v, want %+v", queries.saved[0].Response.SourcesUsed, want)
+	}
+}
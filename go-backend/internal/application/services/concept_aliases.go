@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// aliasMaxWords is the longest phrase considered as an alias
+	aliasMaxWords = 3
+
+	// aliasMinConfidence is the share of queries containing a term that
+	// must identify the same concept before the term is suggested for it
+	aliasMinConfidence = 0.6
+
+	// aliasQueryLimit caps how many recent queries one suggestion run reads
+	aliasQueryLimit = 5000
+)
+
+// aliasStopWords never start or end a suggested alias: articles,
+// prepositions and the words students use to ask for help
+var aliasStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true,
+	"to": true, "in": true, "on": true, "at": true, "by": true, "for": true,
+	"with": true, "from": true, "into": true, "about": true, "between": true,
+	"is": true, "are": true, "was": true, "be": true, "do": true, "does": true,
+	"did": true, "how": true, "what": true, "why": true, "when": true,
+	"where": true, "which": true, "who": true, "can": true, "could": true,
+	"should": true, "would": true, "will": true, "i": true, "me": true,
+	"my": true, "we": true, "you": true, "your": true, "it": true, "its": true,
+	"this": true, "that": true, "these": true, "those": true, "if": true,
+	"then": true, "than": true, "not": true, "no": true, "so": true, "as": true,
+	"please": true, "help": true, "explain": true, "understand": true,
+	"solve": true, "find": true, "use": true, "using": true, "get": true,
+	"need": true, "want": true, "know": true, "mean": true, "means": true,
+}
+
+// SuggestConceptAliases mines recent questions for terms students use
+// instead of a concept's name. A term is suggested for a graph concept when
+// at least minQueries questions contained it and identified the concept
+// without naming it, and most questions containing the term did. Terms that
+// are already aliases or concept names are skipped.
+func (s *queryService) SuggestConceptAliases(ctx context.Context, window time.Duration, minQueries int) (*services.AliasSuggestions, error) {
+	since := time.Now().Add(-window)
+	queries, err := s.queryRepo.GetQueryConcepts(ctx, since, aliasQueryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get query concepts: %w", err)
+	}
+
+	concepts, err := s.conceptRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph concepts: %w", err)
+	}
+
+	known := make(map[string]bool)
+	if s.aliasRepo != nil {
+		aliases, err := s.aliasRepo.FindAll(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get concept aliases: %w", err)
+		}
+		for _, alias := range aliases {
+			known[alias.Alias] = true
+		}
+	}
+
+	suggestions := &services.AliasSuggestions{
+		Since:          since,
+		MinQueries:     minQueries,
+		QueriesScanned: len(queries),
+		Suggestions:    mineConceptAliases(queries, concepts, known, minQueries),
+	}
+
+	s.logger.Info("Computed concept alias suggestions",
+		zap.Int("queries_scanned", suggestions.QueriesScanned),
+		zap.Int("suggestions", len(suggestions.Suggestions)))
+
+	return suggestions, nil
+}
+
+// AddConceptAlias adds an alias to a concept, returning nil if the concept
+// does not exist. The autocomplete index is rebuilt so the alias finds the
+// concept right away.
+func (s *queryService) AddConceptAlias(ctx context.Context, conceptID, alias, addedBy string) (*entities.ConceptAlias, error) {
+	if s.aliasRepo == nil {
+		return nil, fmt.Errorf("concept aliases are not available")
+	}
+	if entities.ConceptAliasKey(alias) == "" {
+		return nil, fmt.Errorf("alias is empty")
+	}
+
+	concept, err := s.conceptRepo.FindByID(ctx, conceptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find concept: %w", err)
+	}
+	if concept == nil {
+		return nil, nil
+	}
+
+	added := entities.NewConceptAlias(alias, concept.ID, concept.Name, addedBy)
+	if err := s.aliasRepo.Save(ctx, added); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.RefreshConceptIndex(ctx); err != nil {
+		s.logger.Warn("Failed to refresh concept index after adding alias",
+			zap.String("alias", added.Alias),
+			zap.Error(err))
+	}
+	return added, nil
+}
+
+// ListConceptAliases returns every concept alias
+func (s *queryService) ListConceptAliases(ctx context.Context) ([]*entities.ConceptAlias, error) {
+	if s.aliasRepo == nil {
+		return []*entities.ConceptAlias{}, nil
+	}
+	return s.aliasRepo.FindAll(ctx)
+}
+
+// conceptAliasesByID groups stored aliases by concept for the autocomplete
+// index and context retrieval. Aliases are optional there, so failures only
+// log.
+func (s *queryService) conceptAliasesByID(ctx context.Context) map[string][]string {
+	if s.aliasRepo == nil {
+		return nil
+	}
+	aliases, err := s.aliasRepo.FindAll(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load concept aliases", zap.Error(err))
+		return nil
+	}
+	byID := make(map[string][]string)
+	for _, alias := range aliases {
+		byID[alias.ConceptID] = append(byID[alias.ConceptID], alias.Alias)
+	}
+	return byID
+}
+
+// mineConceptAliases pairs the terms of each query with the graph concepts
+// identified in it, skipping concepts the query already names, and keeps
+// the pairs seen in at least minQueries queries with enough confidence.
+// When one suggested term extends another for the same concept, only the
+// shorter one is kept.
+func mineConceptAliases(queries []repositories.QueryConcepts, concepts []types.Concept, known map[string]bool, minQueries int) []services.AliasSuggestion {
+	byMention := make(map[string]int, len(concepts)*2)
+	names := make([][]string, len(concepts))
+	for i, concept := range concepts {
+		byMention[normalizeMention(concept.Name)] = i
+		byMention[normalizeMention(concept.ID)] = i
+		names[i] = aliasWords(concept.Name)
+	}
+
+	type pair struct {
+		term    string
+		concept int
+	}
+	termQueries := make(map[string]int)
+	pairQueries := make(map[pair]int)
+	for _, query := range queries {
+		words := aliasWords(query.Text)
+		terms := aliasTerms(words)
+		for term := range terms {
+			termQueries[term]++
+		}
+
+		seen := make(map[int]bool)
+		for _, mention := range query.Concepts {
+			i, ok := byMention[normalizeMention(mention)]
+			if !ok || seen[i] {
+				continue
+			}
+			seen[i] = true
+			if containsPhrase(words, names[i]) {
+				continue
+			}
+			for term := range terms {
+				pairQueries[pair{term, i}]++
+			}
+		}
+	}
+
+	byConcept := make(map[int][]services.AliasSuggestion)
+	for p, count := range pairQueries {
+		if count < minQueries || known[p.term] {
+			continue
+		}
+		if _, isConcept := byMention[p.term]; isConcept {
+			continue
+		}
+		if containsPhrase(names[p.concept], strings.Fields(p.term)) {
+			continue
+		}
+		confidence := float64(count) / float64(termQueries[p.term])
+		if confidence < aliasMinConfidence {
+			continue
+		}
+		byConcept[p.concept] = append(byConcept[p.concept], services.AliasSuggestion{
+			Alias:       p.term,
+			ConceptID:   concepts[p.concept].ID,
+			ConceptName: concepts[p.concept].Name,
+			Queries:     count,
+			Confidence:  confidence,
+		})
+	}
+
+	suggestions := []services.AliasSuggestion{}
+	for _, candidates := range byConcept {
+		for _, candidate := range candidates {
+			if !extendsSuggestion(candidate.Alias, candidates) {
+				suggestions = append(suggestions, candidate)
+			}
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Queries != b.Queries {
+			return a.Queries > b.Queries
+		}
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		if a.Alias != b.Alias {
+			return a.Alias < b.Alias
+		}
+		return a.ConceptID < b.ConceptID
+	})
+	return suggestions
+}
+
+// aliasWords lowercases text and splits it into words, keeping inner
+// hyphens and apostrophes so "u-sub" and "l'hopital" stay whole
+func aliasWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '\''
+	})
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if word := strings.Trim(field, "-'"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// aliasTerms returns the distinct phrases of up to aliasMaxWords words that
+// could be an alias: not starting or ending with a stop word, not just a
+// number, and at least three characters long
+func aliasTerms(words []string) map[string]bool {
+	terms := make(map[string]bool)
+	for start := range words {
+		for end := start + 1; end <= len(words) && end-start <= aliasMaxWords; end++ {
+			phrase := words[start:end]
+			if aliasStopWords[phrase[0]] || aliasStopWords[phrase[len(phrase)-1]] {
+				continue
+			}
+			term := strings.Join(phrase, " ")
+			if utf8.RuneCountInString(term) < 3 || isNumber(term) {
+				continue
+			}
+			terms[term] = true
+		}
+	}
+	return terms
+}
+
+// containsPhrase reports whether phrase appears as consecutive words
+func containsPhrase(words, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for start := 0; start+len(phrase) <= len(words); start++ {
+		match := true
+		for j, word := range phrase {
+			if words[start+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// extendsSuggestion reports whether term contains another suggested term
+func extendsSuggestion(term string, candidates []services.AliasSuggestion) bool {
+	words := strings.Fields(term)
+	for _, other := range candidates {
+		if other.Alias != term && containsPhrase(words, strings.Fields(other.Alias)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumber(term string) bool {
+	for _, r := range term {
+		if !unicode.IsDigit(r) && r != ' ' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/autocomplete"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+type stubQueryConceptsRepo struct {
+	repositories.QueryRepository
+	queries []repositories.QueryConcepts
+}
+
+func (r *stubQueryConceptsRepo) GetQueryConcepts(ctx context.Context, since time.Time, limit int) ([]repositories.QueryConcepts, error) {
+	return r.queries, nil
+}
+
+type findableConceptRepo struct {
+	stubAllConceptRepo
+}
+
+func (r *findableConceptRepo) FindByID(ctx context.Context, id string) (*types.Concept, error) {
+	for i := range r.concepts {
+		if r.concepts[i].ID == id {
+			return &r.concepts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+type memoryAliasRepo struct {
+	aliases []*entities.ConceptAlias
+}
+
+func (r *memoryAliasRepo) Save(ctx context.Context, alias *entities.ConceptAlias) error {
+	r.aliases = append(r.aliases, alias)
+	return nil
+}
+
+func (r *memoryAliasRepo) FindAll(ctx context.Context) ([]*entities.ConceptAlias, error) {
+	return r.aliases, nil
+}
+
+func aliasTestService() *queryService {
+	asked := func(text string, concepts ...string) repositories.QueryConcepts {
+		return repositories.QueryConcepts{Text: text, Concepts: concepts}
+	}
+	return &queryService{
+		queryRepo: &stubQueryConceptsRepo{queries: []repositories.QueryConcepts{
+			asked("How do I use IBP on x sin x?", "Integration by Parts"),
+			asked("ibp for x e^x", "integration by parts"),
+			asked("IBP with ln x", "integration_by_parts", "Logarithms"),
+			asked("when to use ibp twice", "Integration by Parts"),
+			asked("integration by parts of x cos x", "Integration by Parts"),
+			asked("derivative of nested functions", "Chain Rule"),
+			asked("derivative of nested functions like sin(x^2)", "chain rule"),
+			asked("nested functions derivative", "Chain Rule"),
+			asked("what is the chain rule", "Chain Rule"),
+			asked("limits of functions at infinity", "Limits"),
+			asked("limits of functions at zero", "Limits"),
+			asked("one-sided limits of functions", "Limits"),
+			asked("derivative of a polynomial", "Power Rule"),
+			asked("derivative of a cubic polynomial", "Power Rule"),
+			asked("derivative of a quadratic polynomial", "Power Rule"),
+		}},
+		conceptRepo: &findableConceptRepo{stubAllConceptRepo{concepts: []types.Concept{
+			{ID: "integration_by_parts", Name: "Integration by Parts"},
+			{ID: "chain_rule", Name: "Chain Rule"},
+			{ID: "limits", Name: "Limits"},
+		}}},
+		aliasRepo:    &memoryAliasRepo{},
+		conceptIndex: autocomplete.NewIndex(),
+		logger:       zap.NewNop(),
+	}
+}
+
+func suggestedAliases(t *testing.T, svc *queryService) []string {
+	t.Helper()
+	suggestions, err := svc.SuggestConceptAliases(context.Background(), 30*24*time.Hour, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggestions.QueriesScanned != 15 {
+		t.Errorf("expected 15 queries scanned, got %d", suggestions.QueriesScanned)
+	}
+	got := make([]string, len(suggestions.Suggestions))
+	for i, s := range suggestions.Suggestions {
+		got[i] = s.Alias + " -> " + s.ConceptID
+	}
+	return got
+}
+
+func TestSuggestConceptAliasesMinesUnnamedTerms(t *testing.T) {
+	svc := aliasTestService()
+
+	// "functions" and "derivative" are shared with other concepts, and
+	// "nested functions" only extends "nested"
+	got := suggestedAliases(t, svc)
+	want := []string{"ibp -> integration_by_parts", "nested -> chain_rule"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAddConceptAliasStopsSuggestingIt(t *testing.T) {
+	svc := aliasTestService()
+
+	alias, err := svc.AddConceptAlias(context.Background(), "integration_by_parts", "  IBP ", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alias.Alias != "ibp" || alias.ConceptName != "Integration by Parts" {
+		t.Fatalf("unexpected alias %+v", alias)
+	}
+
+	if got := suggestedAliases(t, svc); !reflect.DeepEqual(got, []string{"nested -> chain_rule"}) {
+		t.Errorf("expected the added alias to no longer be suggested, got %v", got)
+	}
+	if got := autocompleteNames(t, svc, "ib"); !reflect.DeepEqual(got, []string{"Integration by Parts"}) {
+		t.Errorf("expected autocomplete to find the concept by its alias, got %v", got)
+	}
+}
+
+func TestAddConceptAliasUnknownConcept(t *testing.T) {
+	svc := aliasTestService()
+	alias, err := svc.AddConceptAlias(context.Background(), "stokes_theorem", "stokes", "")
+	if err != nil || alias != nil {
+		t.Fatalf("expected no alias for a concept missing from the graph, got %+v, %v", alias, err)
+	}
+}
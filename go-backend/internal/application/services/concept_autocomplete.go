@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/autocomplete"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// AutocompleteConcepts suggests concepts whose name starts with prefix from
+// the in-memory index, building it first if it was never warmed
+func (s *queryService) AutocompleteConcepts(ctx context.Context, prefix string, limit int) ([]autocomplete.Entry, error) {
+	if !s.conceptIndex.Ready() {
+		if _, err := s.RefreshConceptIndex(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s.conceptIndex.Match(prefix, limit), nil
+}
+
+// RefreshConceptIndex rebuilds the autocomplete index from the graph and
+// the concept aliases, picking up concepts changed outside the app. It
+// returns the number indexed.
+func (s *queryService) RefreshConceptIndex(ctx context.Context) (int, error) {
+	s.conceptIndexMu.Lock()
+	defer s.conceptIndexMu.Unlock()
+
+	concepts, err := s.conceptRepo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load concepts: %w", err)
+	}
+
+	aliases := s.conceptAliasesByID(ctx)
+	entries := make([]autocomplete.Entry, len(concepts))
+	for i, concept := range concepts {
+		entries[i] = conceptEntry(concept)
+		entries[i].Aliases = aliases[concept.ID]
+	}
+	s.conceptIndex.Rebuild(entries)
+
+	s.logger.Info("Rebuilt concept autocomplete index",
+		zap.Int("concepts", s.conceptIndex.Len()))
+	return s.conceptIndex.Len(), nil
+}
+
+func conceptEntry(concept types.Concept) autocomplete.Entry {
+	return autocomplete.Entry{ID: concept.ID, Name: concept.Name}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mathprereq/internal/autocomplete"
+	"github.com/mathprereq/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+func autocompleteNames(t *testing.T, svc *queryService, prefix string) []string {
+	t.Helper()
+	matches, err := svc.AutocompleteConcepts(context.Background(), prefix, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m.Name
+	}
+	return names
+}
+
+func TestAutocompleteConceptsIncludesApprovedConcepts(t *testing.T) {
+	staged := entities.NewStagedConcept("Series", "Sums of sequences", "q1", "", "",
+		[]string{"sequences"}, 3, "calculus", "")
+	graph := &approvingConceptRepo{graphConceptRepo: &graphConceptRepo{ids: []string{"limits", "sequences"}}}
+	svc := &queryService{
+		conceptRepo:       graph,
+		stagedConceptRepo: &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{staged}},
+		conceptIndex:      autocomplete.NewIndex(),
+		logger:            zap.NewNop(),
+	}
+
+	if got := autocompleteNames(t, svc, "se"); !reflect.DeepEqual(got, []string{"sequences"}) {
+		t.Fatalf("expected the index to be built on first use, got %v", got)
+	}
+
+	if err := svc.ApproveStagedConcept(context.Background(), staged.ID, "reviewer", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := autocompleteNames(t, svc, "se"); !reflect.DeepEqual(got, []string{"Series", "sequences"}) {
+		t.Errorf("expected the approved concept to be suggested, got %v", got)
+	}
+}
+
+func TestRefreshConceptIndexPicksUpGraphChanges(t *testing.T) {
+	graph := &graphConceptRepo{ids: []string{"limits"}}
+	svc := &queryService{conceptRepo: graph, conceptIndex: autocomplete.NewIndex(), logger: zap.NewNop()}
+
+	if got := autocompleteNames(t, svc, "der"); len(got) != 0 {
+		t.Fatalf("expected no matches yet, got %v", got)
+	}
+
+	graph.ids = append(graph.ids, "derivatives")
+	count, err := svc.RefreshConceptIndex(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 indexed concepts, got %d", count)
+	}
+	if got := autocompleteNames(t, svc, "der"); !reflect.DeepEqual(got, []string{"derivatives"}) {
+		t.Errorf("expected the new concept after refresh, got %v", got)
+	}
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+// conceptCacheKey returns the key concept queries are cached under. A name
+// the graph or a stored alias resolves is keyed by its concept ID, so every
+// name of the concept shares one entry; any other name is keyed by itself.
+// Both go through entities.ConceptKey, so "derivative" keys the same whether
+// or not the graph is reachable.
+func (s *queryService) conceptCacheKey(ctx context.Context, conceptName string) string {
+	if concept, err := s.conceptRepo.FindByName(ctx, conceptName); err == nil && concept != nil {
+		return entities.ConceptKey(concept.ID)
+	}
+
+	if s.aliasRepo != nil {
+		aliases, err := s.aliasRepo.FindAll(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to load concept aliases for cache key", zap.Error(err))
+		}
+		key := entities.ConceptAliasKey(conceptName)
+		for _, alias := range aliases {
+			if alias.Alias == key {
+				return entities.ConceptKey(alias.ConceptID)
+			}
+		}
+	}
+
+	return entities.ConceptKey(conceptName)
+}
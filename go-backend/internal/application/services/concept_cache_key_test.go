@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+)
+
+// keyedQueryRepo serves saved concept queries by concept key
+type keyedQueryRepo struct {
+	cachingQueryRepo
+}
+
+func (r *keyedQueryRepo) FindByConceptKey(ctx context.Context, conceptKey string) (*entities.Query, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.saved) - 1; i >= 0; i-- {
+		if query := r.saved[i]; query.ConceptKey == conceptKey && query.Success && query.Preferences == nil {
+			return query, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestSmartConceptQueryNameVariantsShareCacheEntry(t *testing.T) {
+	tasks := background.NewTasks()
+	repo := &keyedQueryRepo{}
+	svc := newSmartQueryService(&repo.cachingQueryRepo, tasks)
+	svc.queryRepo = repo
+
+	first, err := svc.SmartConceptQuery(context.Background(), "Derivatives", "", "req-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+	if first.Source != services.QuerySourceProcessed || first.Query.ConceptKey != "derivative" {
+		t.Fatalf("expected a fresh answer stored under key %q, got source %q and key %q", "derivative", first.Source, first.Query.ConceptKey)
+	}
+
+	for _, variant := range []string{"derivative", "derivatives", " DERIVATIVES "} {
+		result, err := svc.SmartConceptQuery(context.Background(), variant, "", "req-2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		drainTasks(t, tasks)
+		if result.Source != services.QuerySourceCache || result.Query.ID != first.Query.ID {
+			t.Errorf("%q: expected the cached answer %s, got %s from %q", variant, first.Query.ID, result.Query.ID, result.Source)
+		}
+	}
+	if len(repo.saved) != 1 {
+		t.Errorf("expected one stored answer for all variants, got %d", len(repo.saved))
+	}
+}
+
+func TestConceptCacheKeyResolvesGraphNamesAndAliases(t *testing.T) {
+	svc := newSmartQueryService(&cachingQueryRepo{}, background.NewTasks())
+	svc.conceptRepo = &namedConceptRepo{concepts: map[string]*types.Concept{
+		"Integration by Parts": {ID: "integration_by_parts", Name: "Integration by Parts"},
+	}}
+	svc.aliasRepo = &memoryAliasRepo{aliases: []*entities.ConceptAlias{
+		entities.NewConceptAlias("ibp", "integration_by_parts", "Integration by Parts", ""),
+	}}
+
+	want := entities.ConceptKey("integration_by_parts")
+	for _, name := range []string{"Integration by Parts", "IBP", "integration by parts"} {
+		if got := svc.conceptCacheKey(context.Background(), name); got != want {
+			t.Errorf("conceptCacheKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if got := svc.conceptCacheKey(context.Background(), "Limits"); got != "limit" {
+		t.Errorf("expected an unknown concept keyed by its name, got %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// coverageNearLimit caps the related chunks counted per concept; past it a
+// concept is well covered and the exact number does not matter
+const coverageNearLimit = 100
+
+// GetConceptCoverage reports how many textbook chunks support each graph
+// concept and which tagged concepts are missing from the graph. Chunk tags
+// match a concept by its ID, name or an alias, ignoring naming variants.
+// With minCertainty above zero, chunks semantically that close to each
+// concept's name are counted as well.
+func (s *queryService) GetConceptCoverage(ctx context.Context, minCertainty float64) (*services.CoverageReport, error) {
+	concepts, err := s.conceptRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph concepts: %w", err)
+	}
+	tags, err := s.vectorRepo.CountChunksByConcept(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count textbook chunks: %w", err)
+	}
+
+	conceptByKey := make(map[string]string, len(concepts)*2)
+	for _, concept := range concepts {
+		conceptByKey[entities.ConceptKey(concept.ID)] = concept.ID
+		conceptByKey[entities.ConceptKey(concept.Name)] = concept.ID
+	}
+	if s.aliasRepo != nil {
+		aliases, err := s.aliasRepo.FindAll(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to load concept aliases for coverage", zap.Error(err))
+		}
+		for _, alias := range aliases {
+			key := entities.ConceptKey(alias.Alias)
+			if _, taken := conceptByKey[key]; !taken && conceptByKey[entities.ConceptKey(alias.ConceptID)] != "" {
+				conceptByKey[key] = alias.ConceptID
+			}
+		}
+	}
+
+	report := &services.CoverageReport{
+		GraphConcepts: len(concepts),
+		Concepts:      make([]services.ConceptCoverage, 0, len(concepts)),
+	}
+	tagged := make(map[string]int, len(concepts))
+	missing := make(map[string]*services.TextbookConcept)
+	for tag, count := range tags {
+		key := entities.ConceptKey(tag)
+		if key == "" {
+			report.UntaggedChunks += count
+			continue
+		}
+		report.TaggedChunks += count
+		if id, ok := conceptByKey[key]; ok {
+			tagged[id] += count
+			continue
+		}
+		// Tags are free text, so variants of one missing concept are merged
+		// under the first of them alphabetically
+		tag = strings.TrimSpace(tag)
+		if entry, ok := missing[key]; ok {
+			entry.Chunks += count
+			if tag < entry.Concept {
+				entry.Concept = tag
+			}
+			continue
+		}
+		missing[key] = &services.TextbookConcept{Concept: tag, Chunks: count}
+	}
+
+	for _, concept := range concepts {
+		coverage := services.ConceptCoverage{
+			ConceptID:    concept.ID,
+			ConceptName:  concept.Name,
+			TaggedChunks: tagged[concept.ID],
+		}
+		if minCertainty > 0 {
+			related, err := s.vectorRepo.CountChunksNear(ctx, concept.Name, minCertainty, coverageNearLimit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count chunks near %q: %w", concept.Name, err)
+			}
+			coverage.RelatedChunks = related
+		}
+		coverage.Supported = coverage.TaggedChunks > 0 || coverage.RelatedChunks > 0
+		if !coverage.Supported {
+			report.Unsupported++
+		}
+		report.Concepts = append(report.Concepts, coverage)
+	}
+	if minCertainty > 0 {
+		report.MinCertainty = minCertainty
+	}
+	sort.Slice(report.Concepts, func(i, j int) bool {
+		a, b := report.Concepts[i], report.Concepts[j]
+		if a.Supported != b.Supported {
+			return !a.Supported
+		}
+		if totalA, totalB := a.TaggedChunks+a.RelatedChunks, b.TaggedChunks+b.RelatedChunks; totalA != totalB {
+			return totalA < totalB
+		}
+		return a.ConceptName < b.ConceptName
+	})
+
+	report.MissingFromGraph = make([]services.TextbookConcept, 0, len(missing))
+	for _, entry := range missing {
+		report.MissingFromGraph = append(report.MissingFromGraph, *entry)
+	}
+	sort.Slice(report.MissingFromGraph, func(i, j int) bool {
+		a, b := report.MissingFromGraph[i], report.MissingFromGraph[j]
+		if a.Chunks != b.Chunks {
+			return a.Chunks > b.Chunks
+		}
+		return a.Concept < b.Concept
+	})
+
+	s.logger.Info("Built concept coverage report",
+		zap.Int("graph_concepts", report.GraphConcepts),
+		zap.Int("unsupported", report.Unsupported),
+		zap.Int("missing_from_graph", len(report.MissingFromGraph)))
+	return report, nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// coverageVectorRepo serves fixed chunk tag counts and near counts by term
+type coverageVectorRepo struct {
+	repositories.VectorRepository
+	tags      map[string]int
+	near      map[string]int
+	nearCalls int
+	certainty float64
+}
+
+func (r *coverageVectorRepo) CountChunksByConcept(ctx context.Context) (map[string]int, error) {
+	return r.tags, nil
+}
+
+func (r *coverageVectorRepo) CountChunksNear(ctx context.Context, term string, certainty float64, maxChunks int) (int, error) {
+	r.nearCalls++
+	r.certainty = certainty
+	return r.near[term], nil
+}
+
+func coverageTestService(vector *coverageVectorRepo) *queryService {
+	return &queryService{
+		conceptRepo: &stubAllConceptRepo{concepts: []types.Concept{
+			{ID: "limits", Name: "Limits"},
+			{ID: "derivatives", Name: "Derivatives"},
+			{ID: "chain_rule", Name: "Chain Rule"},
+			{ID: "integrals", Name: "Integrals"},
+		}},
+		vectorRepo: vector,
+		aliasRepo: &memoryAliasRepo{aliases: []*entities.ConceptAlias{
+			{Alias: "antiderivative", ConceptID: "integrals"},
+		}},
+		logger: zap.NewNop(),
+	}
+}
+
+func TestGetConceptCoverageComparesTagsWithGraph(t *testing.T) {
+	vector := &coverageVectorRepo{tags: map[string]int{
+		"limits":          12,
+		"Derivative":      5,
+		"derivatives":     3,
+		"antiderivatives": 2,
+		"Taylor series":   4,
+		"taylor-series":   1,
+		"Vectors":         2,
+		"":                6,
+	}}
+
+	report, err := coverageTestService(vector).GetConceptCoverage(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vector.nearCalls != 0 {
+		t.Errorf("expected no semantic counts without a certainty, got %d", vector.nearCalls)
+	}
+	if report.GraphConcepts != 4 || report.TaggedChunks != 29 || report.UntaggedChunks != 6 {
+		t.Errorf("unexpected totals %+v", report)
+	}
+
+	byID := make(map[string]int)
+	for _, coverage := range report.Concepts {
+		byID[coverage.ConceptID] = coverage.TaggedChunks
+	}
+	if byID["limits"] != 12 || byID["derivatives"] != 8 || byID["integrals"] != 2 || byID["chain_rule"] != 0 {
+		t.Errorf("expected tags matched by ID, name variant and alias, got %v", byID)
+	}
+	if report.Unsupported != 1 || report.Concepts[0].ConceptID != "chain_rule" || report.Concepts[0].Supported {
+		t.Errorf("expected the unsupported concept flagged and listed first, got %+v", report.Concepts)
+	}
+	if report.Concepts[1].ConceptID != "integrals" {
+		t.Errorf("expected the least covered concept next, got %+v", report.Concepts[1])
+	}
+
+	missing := report.MissingFromGraph
+	if len(missing) != 2 || missing[0].Concept != "Taylor series" || missing[0].Chunks != 5 || missing[1].Concept != "Vectors" {
+		t.Errorf("expected textbook concepts absent from the graph, variants merged, got %+v", missing)
+	}
+}
+
+func TestGetConceptCoverageCountsSemanticMatches(t *testing.T) {
+	vector := &coverageVectorRepo{
+		tags: map[string]int{"limits": 12},
+		near: map[string]int{"Chain Rule": 3, "Limits": 9},
+	}
+
+	report, err := coverageTestService(vector).GetConceptCoverage(context.Background(), 0.8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vector.nearCalls != 4 || vector.certainty != 0.8 || report.MinCertainty != 0.8 {
+		t.Errorf("expected every concept counted near its name at 0.8, got %d calls at %v", vector.nearCalls, vector.certainty)
+	}
+
+	supported := make(map[string]bool)
+	for _, coverage := range report.Concepts {
+		supported[coverage.ConceptID] = coverage.Supported
+		if coverage.ConceptID == "chain_rule" && coverage.RelatedChunks != 3 {
+			t.Errorf("expected 3 related chunks for the chain rule, got %+v", coverage)
+		}
+	}
+	if !supported["chain_rule"] || supported["derivatives"] || supported["integrals"] || report.Unsupported != 2 {
+		t.Errorf("expected semantic matches to count as support, got %v", supported)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// descriptionContextChunks is how many textbook chunks ground each generated description
+const descriptionContextChunks = 3
+
+// BackfillConceptDescriptions generates descriptions for up to limit concepts
+// whose description is blank. A dry run only returns the proposals; otherwise
+// each one is written to the graph unless a description was set meanwhile.
+func (s *queryService) BackfillConceptDescriptions(ctx context.Context, limit int, dryRun bool) (*services.DescriptionBackfillResult, error) {
+	concepts, err := s.conceptRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph concepts: %w", err)
+	}
+
+	missing := conceptsWithoutDescription(concepts)
+	result := &services.DescriptionBackfillResult{
+		DryRun:    dryRun,
+		Proposals: []services.ConceptDescriptionProposal{},
+	}
+	if len(missing) > limit {
+		result.Remaining = len(missing) - limit
+		missing = missing[:limit]
+	}
+
+	for _, concept := range missing {
+		if ctx.Err() != nil {
+			result.Remaining += len(missing) - len(result.Proposals)
+			break
+		}
+		result.Proposals = append(result.Proposals, s.proposeDescription(ctx, concept, dryRun))
+	}
+
+	s.logger.Info("Concept description backfill finished",
+		zap.Bool("dry_run", dryRun),
+		zap.Int("proposals", len(result.Proposals)),
+		zap.Int("remaining", result.Remaining))
+
+	return result, nil
+}
+
+// proposeDescription generates one description and applies it unless dryRun is set
+func (s *queryService) proposeDescription(ctx context.Context, concept types.Concept, dryRun bool) services.ConceptDescriptionProposal {
+	proposal := services.ConceptDescriptionProposal{
+		ConceptID:   concept.ID,
+		ConceptName: concept.Name,
+	}
+
+	var chunks []string
+	results, err := s.vectorRepo.Search(ctx, concept.Name, descriptionContextChunks)
+	if err != nil {
+		s.logger.Warn("Vector search failed, generating description without context",
+			zap.String("concept_id", concept.ID),
+			zap.Error(err))
+	}
+	for _, result := range results {
+		chunks = append(chunks, result.Content)
+	}
+
+	description, err := s.llmClient.GenerateConceptDescription(ctx, concept.Name, chunks)
+	if err != nil {
+		s.logger.Error("Failed to generate concept description",
+			zap.String("concept_id", concept.ID),
+			zap.Error(err))
+		proposal.Error = err.Error()
+		return proposal
+	}
+	proposal.Description = description
+
+	if dryRun {
+		return proposal
+	}
+
+	updated, err := s.conceptRepo.UpdateDescription(ctx, concept.ID, description)
+	switch {
+	case err != nil:
+		proposal.Error = err.Error()
+	case !updated:
+		proposal.Error = "concept already has a description"
+	default:
+		proposal.Applied = true
+	}
+	return proposal
+}
+
+// conceptsWithoutDescription returns the concepts with a blank description, by name
+func conceptsWithoutDescription(concepts []types.Concept) []types.Concept {
+	var missing []types.Concept
+	for _, concept := range concepts {
+		if strings.TrimSpace(concept.Description) == "" {
+			missing = append(missing, concept)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].Name < missing[j].Name
+	})
+	return missing
+}
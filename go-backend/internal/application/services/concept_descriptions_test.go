@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// describingConceptRepo serves a fixed graph and records description updates
+type describingConceptRepo struct {
+	repositories.ConceptRepository
+	concepts []types.Concept
+	updated  map[string]string
+}
+
+func (r *describingConceptRepo) GetAll(ctx context.Context) ([]types.Concept, error) {
+	return r.concepts, nil
+}
+
+func (r *describingConceptRepo) UpdateDescription(ctx context.Context, conceptID, description string) (bool, error) {
+	if r.updated == nil {
+		r.updated = map[string]string{}
+	}
+	r.updated[conceptID] = description
+	return true, nil
+}
+
+// describingLLM writes a canned description per concept, failing for unknown ones
+type describingLLM struct {
+	recordingLLM
+	descriptions map[string]string
+	contexts     map[string][]string
+}
+
+func (l *describingLLM) GenerateConceptDescription(ctx context.Context, conceptName string, contextChunks []string) (string, error) {
+	if l.contexts == nil {
+		l.contexts = map[string][]string{}
+	}
+	l.contexts[conceptName] = contextChunks
+	description, ok := l.descriptions[conceptName]
+	if !ok {
+		return "", errors.New("quota exceeded")
+	}
+	return description, nil
+}
+
+func newDescriptionTestService(repo *describingConceptRepo, llm *describingLLM) *queryService {
+	return &queryService{
+		conceptRepo: repo,
+		vectorRepo: &stubVectorRepo{results: map[string][]types.VectorResult{
+			"Limits": {{Content: "A limit is the value a function approaches."}},
+		}},
+		llmClient: llm,
+		logger:    zap.NewNop(),
+	}
+}
+
+func TestConceptsWithoutDescription(t *testing.T) {
+	concepts := []types.Concept{
+		{ID: "limits", Name: "Limits"},
+		{ID: "derivatives", Name: "Derivatives", Description: "Rate of change"},
+		{ID: "functions", Name: "Functions", Description: "  \n"},
+	}
+
+	missing := conceptsWithoutDescription(concepts)
+	if len(missing) != 2 || missing[0].ID != "functions" || missing[1].ID != "limits" {
+		t.Errorf("conceptsWithoutDescription() = %+v, want functions and limits", missing)
+	}
+}
+
+func TestBackfillConceptDescriptionsDryRun(t *testing.T) {
+	repo := &describingConceptRepo{concepts: []types.Concept{
+		{ID: "limits", Name: "Limits"},
+		{ID: "derivatives", Name: "Derivatives", Description: "Rate of change"},
+	}}
+	llm := &describingLLM{descriptions: map[string]string{"Limits": "The value a function approaches."}}
+
+	result, err := newDescriptionTestService(repo, llm).BackfillConceptDescriptions(context.Background(), 10, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Proposals) != 1 || result.Proposals[0].Description != "The value a function approaches." {
+		t.Fatalf("Proposals = %+v", result.Proposals)
+	}
+	if result.Proposals[0].Applied || len(repo.updated) != 0 {
+		t.Errorf("dry run applied descriptions: %v", repo.updated)
+	}
+	if got := llm.contexts["Limits"]; len(got) != 1 || got[0] != "A limit is the value a function approaches." {
+		t.Errorf("description not grounded in retrieved context: %v", got)
+	}
+}
+
+func TestBackfillConceptDescriptionsApplies(t *testing.T) {
+	repo := &describingConceptRepo{concepts: []types.Concept{
+		{ID: "limits", Name: "Limits"},
+		{ID: "integrals", Name: "Integrals"},
+		{ID: "series", Name: "Series"},
+	}}
+	llm := &describingLLM{descriptions: map[string]string{"Limits": "The value a function approaches."}}
+
+	result, err := newDescriptionTestService(repo, llm).BackfillConceptDescriptions(context.Background(), 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Remaining != 1 || len(result.Proposals) != 2 {
+		t.Fatalf("result = %+v, want 2 proposals and 1 remaining", result)
+	}
+	integrals, limits := result.Proposals[0], result.Proposals[1]
+	if integrals.Applied || integrals.Error == "" {
+		t.Errorf("failed generation should be reported, got %+v", integrals)
+	}
+	if !limits.Applied || repo.updated["limits"] != "The value a function approaches." {
+		t.Errorf("limits not applied: %+v, updates %v", limits, repo.updated)
+	}
+	if _, ok := repo.updated["integrals"]; ok {
+		t.Error("failed generation should not update the graph")
+	}
+}
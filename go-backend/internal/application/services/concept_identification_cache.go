@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+// identifyConcepts returns the concepts in a question. With the
+// identification cache on, a question whose QuestionKey was identified
+// within the TTL reuses those concepts instead of asking the LLM; fresh
+// non-empty identifications are cached in the background. bypass skips the
+// lookup but still refreshes the entry. Cache failures are logged and
+// treated as misses.
+func (s *queryService) identifyConcepts(ctx context.Context, query *entities.Query, bypass bool) ([]string, error) {
+	caching := s.questionCache.IdentifyEnabled && s.identificationRepo != nil && query.QuestionKey != ""
+
+	if caching && !bypass {
+		stepStart := time.Now()
+		cached, err := s.identificationRepo.FindByQuestionKey(ctx, query.QuestionKey)
+		if err != nil {
+			s.logger.Warn("Concept identification cache lookup failed, asking the LLM", zap.Error(err))
+		} else if cached != nil {
+			query.AddProcessingStep("identify_concepts_cached", time.Since(stepStart), true, nil)
+			s.logger.Info("Reusing cached concept identification",
+				zap.String("query_id", query.ID),
+				zap.Strings("concepts", cached.Concepts))
+			return cached.Concepts, nil
+		}
+	}
+
+	stepStart := time.Now()
+	concepts, err := s.llmClient.IdentifyConcepts(ctx, query.Text)
+	query.AddProcessingStep("identify_concepts", time.Since(stepStart), err == nil, err)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty identification is more likely a miss than a fact about the
+	// question, so it is asked again next time
+	if caching && len(concepts) > 0 {
+		now := time.Now()
+		identification := &entities.ConceptIdentification{
+			QuestionKey: query.QuestionKey,
+			Concepts:    concepts,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(s.questionCache.IdentifyTTL),
+		}
+		s.tasks.Go("cache_concept_identification", func() {
+			if err := s.identificationRepo.Save(context.Background(), identification); err != nil {
+				s.logger.Warn("Failed to cache concept identification", zap.Error(err))
+			}
+		})
+	}
+
+	return concepts, nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+)
+
+// identifyCountingLLM counts concept identification calls
+type identifyCountingLLM struct {
+	*recordingLLM
+	identified int
+}
+
+func (l *identifyCountingLLM) IdentifyConcepts(ctx context.Context, query string) ([]string, error) {
+	l.identified++
+	return l.recordingLLM.IdentifyConcepts(ctx, query)
+}
+
+type memoryIdentificationRepo struct {
+	mu      sync.Mutex
+	entries map[string]*entities.ConceptIdentification
+}
+
+func (r *memoryIdentificationRepo) Save(ctx context.Context, identification *entities.ConceptIdentification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[identification.QuestionKey] = identification
+	return nil
+}
+
+func (r *memoryIdentificationRepo) FindByQuestionKey(ctx context.Context, questionKey string) (*entities.ConceptIdentification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := r.entries[questionKey]
+	if entry == nil || !entry.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+func newIdentificationCacheService(enabled bool) (*queryService, *identifyCountingLLM, *memoryIdentificationRepo, *background.Tasks) {
+	tasks := background.NewTasks()
+	svc := newSmartQueryService(&cachingQueryRepo{}, tasks)
+	llm := &identifyCountingLLM{recordingLLM: svc.llmClient.(*recordingLLM)}
+	repo := &memoryIdentificationRepo{entries: map[string]*entities.ConceptIdentification{}}
+	svc.llmClient = llm
+	svc.identificationRepo = repo
+	svc.questionCache = config.QueryCacheConfig{IdentifyEnabled: enabled, IdentifyTTL: time.Hour}
+	return svc, llm, repo, tasks
+}
+
+func TestRepeatedQuestionSkipsConceptIdentification(t *testing.T) {
+	svc, llm, repo, tasks := newIdentificationCacheService(true)
+	ctx := context.Background()
+
+	if _, err := svc.ProcessQuery(ctx, &services.QueryRequest{Question: "What is a limit?"}); err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+	if entry := repo.entries[entities.QuestionKey("What is a limit?")]; entry == nil || entry.Concepts[0] != "limits" {
+		t.Fatalf("expected the identification cached, got %+v", repo.entries)
+	}
+
+	result, err := svc.ProcessQuery(ctx, &services.QueryRequest{Question: "what is a  LIMIT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if llm.identified != 1 {
+		t.Errorf("expected one LLM identification for a repeated question, got %d", llm.identified)
+	}
+	if len(result.IdentifiedConcepts) != 1 || result.IdentifiedConcepts[0] != "limits" {
+		t.Errorf("expected the cached concepts, got %v", result.IdentifiedConcepts)
+	}
+	if steps := result.Query.Metadata.ProcessingSteps; len(steps) == 0 || steps[0].Name != "identify_concepts_cached" {
+		t.Errorf("expected the cached identification recorded as its own step, got %+v", steps)
+	}
+	if len(llm.requests) != 2 {
+		t.Errorf("expected both explanations generated fresh, got %d", len(llm.requests))
+	}
+}
+
+func TestConceptIdentificationCacheBypassAndDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	svc, llm, _, tasks := newIdentificationCacheService(true)
+	for _, bypass := range []bool{false, true} {
+		if _, err := svc.ProcessQuery(ctx, &services.QueryRequest{Question: "What is a limit?", BypassCache: bypass}); err != nil {
+			t.Fatal(err)
+		}
+		drainTasks(t, tasks)
+	}
+	if llm.identified != 2 {
+		t.Errorf("expected bypass_cache to ask the LLM again, got %d identifications", llm.identified)
+	}
+
+	svc, llm, repo, tasks := newIdentificationCacheService(false)
+	for i := 0; i < 2; i++ {
+		if _, err := svc.ProcessQuery(ctx, &services.QueryRequest{Question: "What is a limit?"}); err != nil {
+			t.Fatal(err)
+		}
+		drainTasks(t, tasks)
+	}
+	if llm.identified != 2 || len(repo.entries) != 0 {
+		t.Errorf("expected no caching while disabled, got %d identifications and %d entries", llm.identified, len(repo.entries))
+	}
+}
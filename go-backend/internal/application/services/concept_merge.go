@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// MergeConcepts folds a duplicate concept into another. The graph merge
+// rewires the source's prerequisite edges to the target and deletes the
+// source; then the source's resources and aliases move to the target, its
+// name becomes an alias of the target and its description vector is
+// removed. Its explanation versions, profile and path snapshots describe
+// the source alone and are deleted rather than moved. Those follow-ups do
+// not undo the merge when they fail and are reported as warnings instead.
+func (s *queryService) MergeConcepts(ctx context.Context, sourceID, targetID string) (*types.ConceptMergeResult, error) {
+	if sourceID == targetID {
+		return nil, services.ErrMergeIntoItself
+	}
+
+	source, err := s.conceptRepo.FindByID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.conceptRepo.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil || target == nil {
+		return nil, fmt.Errorf("%w: %s or %s", services.ErrConceptNotFound, sourceID, targetID)
+	}
+
+	result, err := s.conceptRepo.MergeConcepts(ctx, source.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	warn := func(step string, err error) {
+		s.logger.Warn("Concept merge step failed",
+			zap.String("step", step),
+			zap.String("source_id", source.ID),
+			zap.String("target_id", target.ID),
+			zap.Error(err))
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", step, err))
+	}
+
+	// Resources are keyed by concept name, not graph ID
+	if s.resourceScraper != nil {
+		from, to := s.generateConceptID(source.Name), s.generateConceptID(target.Name)
+		if from != to {
+			moved, err := s.resourceScraper.MoveResources(ctx, from, to, target.Name)
+			if err != nil {
+				warn("move resources", err)
+			}
+			result.ResourcesMoved = moved
+		}
+	}
+
+	moved, err := s.moveConceptAliases(ctx, source, target)
+	if err != nil {
+		warn("move aliases", err)
+	}
+	result.AliasesMoved = moved
+
+	if s.vectorRepo != nil {
+		if err := s.vectorRepo.DeleteConceptVector(ctx, source.ID); err != nil {
+			warn("delete concept vector", err)
+		}
+	}
+
+	// Explanation versions are keyed by normalized name, which the target
+	// may share; its own versions are kept then
+	if s.explanationRepo != nil && normalizeMention(source.Name) != normalizeMention(target.Name) {
+		if _, err := s.explanationRepo.DeleteByConcept(ctx, normalizeMention(source.Name)); err != nil {
+			warn("delete explanation versions", err)
+		}
+	}
+	if s.conceptProfileRepo != nil {
+		if err := s.conceptProfileRepo.Delete(ctx, source.ID); err != nil {
+			warn("delete concept profile", err)
+		}
+	}
+	if s.pathSnapshotRepo != nil {
+		if _, err := s.pathSnapshotRepo.DeleteByConcept(ctx, source.ID); err != nil {
+			warn("delete path snapshots", err)
+		}
+	}
+
+	if _, err := s.RefreshConceptIndex(ctx); err != nil {
+		warn("refresh concept index", err)
+	}
+	s.invalidatePrerequisiteClosures()
+
+	s.logger.Info("Concepts merged",
+		zap.String("source_id", source.ID),
+		zap.String("target_id", target.ID),
+		zap.Int("edges_moved", len(result.EdgesMoved)),
+		zap.Int("edges_skipped", len(result.EdgesSkipped)),
+		zap.Int64("resources_moved", result.ResourcesMoved),
+		zap.Int("warnings", len(result.Warnings)))
+
+	return result, nil
+}
+
+// moveConceptAliases points the source's aliases at the target and adds the
+// source's name as one, so terms that found the duplicate find the target.
+// It returns how many existing aliases were moved.
+func (s *queryService) moveConceptAliases(ctx context.Context, source, target *types.Concept) (int, error) {
+	if s.aliasRepo == nil {
+		return 0, nil
+	}
+
+	aliases, err := s.aliasRepo.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, alias := range aliases {
+		if alias.ConceptID != source.ID {
+			continue
+		}
+		alias.ConceptID = target.ID
+		alias.ConceptName = target.Name
+		if err := s.aliasRepo.Save(ctx, alias); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+
+	if entities.ConceptAliasKey(source.Name) != entities.ConceptAliasKey(target.Name) {
+		if err := s.aliasRepo.Save(ctx, entities.NewConceptAlias(source.Name, target.ID, target.Name, "concept merge")); err != nil {
+			return moved, err
+		}
+	}
+	return moved, nil
+}
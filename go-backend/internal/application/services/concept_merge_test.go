@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/autocomplete"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+type mergingConceptRepo struct {
+	findableConceptRepo
+	merged [][2]string
+}
+
+func (r *mergingConceptRepo) MergeConcepts(ctx context.Context, sourceID, targetID string) (*types.ConceptMergeResult, error) {
+	r.merged = append(r.merged, [2]string{sourceID, targetID})
+	return &types.ConceptMergeResult{
+		SourceID:   sourceID,
+		TargetID:   targetID,
+		EdgesMoved: []types.NeighborhoodEdge{{From: "functions", To: targetID, Type: "PREREQUISITE_FOR"}},
+	}, nil
+}
+
+// keyedAliasRepo replaces aliases by key, as the Mongo repository does
+type keyedAliasRepo struct {
+	aliases map[string]*entities.ConceptAlias
+}
+
+func (r *keyedAliasRepo) Save(ctx context.Context, alias *entities.ConceptAlias) error {
+	r.aliases[alias.Alias] = alias
+	return nil
+}
+
+func (r *keyedAliasRepo) FindAll(ctx context.Context) ([]*entities.ConceptAlias, error) {
+	all := make([]*entities.ConceptAlias, 0, len(r.aliases))
+	for _, alias := range r.aliases {
+		all = append(all, alias)
+	}
+	return all, nil
+}
+
+type deletingVectorRepo struct {
+	repositories.VectorRepository
+	deleted []string
+	err     error
+}
+
+func (r *deletingVectorRepo) DeleteConceptVector(ctx context.Context, conceptID string) error {
+	r.deleted = append(r.deleted, conceptID)
+	return r.err
+}
+
+// memSnapshotRepo keeps path snapshots in memory
+type memSnapshotRepo struct {
+	repositories.PathSnapshotRepository
+	snapshots []*entities.PathSnapshot
+}
+
+func (r *memSnapshotRepo) DeleteByConcept(ctx context.Context, conceptID string) (int64, error) {
+	kept := r.snapshots[:0]
+	for _, snapshot := range r.snapshots {
+		if snapshot.ConceptID != conceptID {
+			kept = append(kept, snapshot)
+		}
+	}
+	deleted := int64(len(r.snapshots) - len(kept))
+	r.snapshots = kept
+	return deleted, nil
+}
+
+func mergeTestService() (*queryService, *mergingConceptRepo, *keyedAliasRepo, *deletingVectorRepo) {
+	conceptRepo := &mergingConceptRepo{}
+	conceptRepo.concepts = []types.Concept{
+		{ID: "limit_dup", Name: "Limit"},
+		{ID: "limits", Name: "Limits"},
+	}
+	aliasRepo := &keyedAliasRepo{aliases: map[string]*entities.ConceptAlias{
+		"lim":  entities.NewConceptAlias("lim", "limit_dup", "Limit", ""),
+		"ibp":  entities.NewConceptAlias("ibp", "integration_by_parts", "Integration by Parts", ""),
+		"lims": entities.NewConceptAlias("lims", "limits", "Limits", ""),
+	}}
+	vectorRepo := &deletingVectorRepo{}
+	return &queryService{
+		conceptRepo:  conceptRepo,
+		aliasRepo:    aliasRepo,
+		vectorRepo:   vectorRepo,
+		conceptIndex: autocomplete.NewIndex(),
+		logger:       zap.NewNop(),
+	}, conceptRepo, aliasRepo, vectorRepo
+}
+
+func TestMergeConcepts(t *testing.T) {
+	svc, conceptRepo, aliasRepo, vectorRepo := mergeTestService()
+
+	result, err := svc.MergeConcepts(context.Background(), "limit_dup", "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conceptRepo.merged) != 1 || conceptRepo.merged[0] != [2]string{"limit_dup", "limits"} {
+		t.Errorf("expected the graph merge of limit_dup into limits, got %v", conceptRepo.merged)
+	}
+	if len(result.EdgesMoved) != 1 || len(result.Warnings) != 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if result.AliasesMoved != 1 {
+		t.Errorf("expected 1 alias moved, got %d", result.AliasesMoved)
+	}
+	for key, want := range map[string]string{"lim": "limits", "limit": "limits", "ibp": "integration_by_parts", "lims": "limits"} {
+		alias := aliasRepo.aliases[key]
+		if alias == nil || alias.ConceptID != want {
+			t.Errorf("alias %q = %+v, want it on %s", key, alias, want)
+		}
+	}
+
+	if len(vectorRepo.deleted) != 1 || vectorRepo.deleted[0] != "limit_dup" {
+		t.Errorf("expected the duplicate's vector deleted, got %v", vectorRepo.deleted)
+	}
+}
+
+func TestMergeConceptsReportsFollowUpFailures(t *testing.T) {
+	svc, _, _, vectorRepo := mergeTestService()
+	vectorRepo.err = fmt.Errorf("weaviate down")
+
+	result, err := svc.MergeConcepts(context.Background(), "limit_dup", "limits")
+	if err != nil {
+		t.Fatalf("expected the merge to stand, got %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "weaviate down") {
+		t.Errorf("expected a warning for the vector, got %v", result.Warnings)
+	}
+}
+
+func TestMergeConceptsRejectsUnknownOrSameConcept(t *testing.T) {
+	svc, conceptRepo, _, _ := mergeTestService()
+
+	if _, err := svc.MergeConcepts(context.Background(), "limits", "limits"); !errors.Is(err, services.ErrMergeIntoItself) {
+		t.Errorf("expected merging a concept into itself to fail, got %v", err)
+	}
+	if _, err := svc.MergeConcepts(context.Background(), "missing", "limits"); !errors.Is(err, services.ErrConceptNotFound) {
+		t.Errorf("expected concept not found, got %v", err)
+	}
+	if len(conceptRepo.merged) != 0 {
+		t.Errorf("expected no graph change, got %v", conceptRepo.merged)
+	}
+}
+
+func TestMergeConceptsDropsSourceOnlyData(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := mergeTestService()
+	versions := &memExplanationRepo{versions: []*entities.ExplanationVersion{
+		{Concept: "limit", Version: 1},
+		{Concept: "limits", Version: 1},
+	}}
+	profiles := &memProfileRepo{profiles: map[string]*entities.ConceptProfile{
+		"limit_dup": {ConceptID: "limit_dup"},
+		"limits":    {ConceptID: "limits"},
+	}}
+	snapshots := &memSnapshotRepo{snapshots: []*entities.PathSnapshot{
+		{ConceptID: "limit_dup"},
+		{ConceptID: "limits"},
+	}}
+	svc.explanationRepo = versions
+	svc.conceptProfileRepo = profiles
+	svc.pathSnapshotRepo = snapshots
+
+	result, err := svc.MergeConcepts(ctx, "limit_dup", "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings %v", result.Warnings)
+	}
+
+	if len(versions.versions) != 1 || versions.versions[0].Concept != "limits" {
+		t.Errorf("expected only the target's explanation versions, got %+v", versions.versions)
+	}
+	if _, ok := profiles.profiles["limit_dup"]; ok || profiles.profiles["limits"] == nil {
+		t.Errorf("expected only the target's profile, got %v", profiles.profiles)
+	}
+	if len(snapshots.snapshots) != 1 || snapshots.snapshots[0].ConceptID != "limits" {
+		t.Errorf("expected only the target's path snapshots, got %+v", snapshots.snapshots)
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+)
+
+// GetConceptPaths returns the prerequisite paths of several concepts from a
+// single graph query, listing concepts shared between paths once
+func (s *queryService) GetConceptPaths(ctx context.Context, conceptIDs []string) (*services.ConceptPaths, error) {
+	paths, err := s.conceptRepo.FindPrerequisitePaths(ctx, conceptIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prerequisite paths: %w", err)
+	}
+	return buildConceptPaths(conceptIDs, paths), nil
+}
+
+// buildConceptPaths flattens paths into shared concepts and per-concept
+// steps, in the order the concepts were requested. Type and Weight depend on
+// the path, so they are cleared on the shared concepts and kept on steps.
+func buildConceptPaths(conceptIDs []string, paths map[string][]types.Concept) *services.ConceptPaths {
+	result := &services.ConceptPaths{
+		Concepts: []types.Concept{},
+		Paths:    []services.ConceptPath{},
+	}
+	seen := make(map[string]bool)
+
+	for _, id := range conceptIDs {
+		path, ok := paths[id]
+		if !ok {
+			result.NotFound = append(result.NotFound, id)
+			continue
+		}
+
+		conceptPath := services.ConceptPath{ConceptID: id, Steps: make([]services.PathStep, len(path))}
+		for i, concept := range path {
+			step := services.PathStep{ConceptID: concept.ID}
+			if concept.ID != id {
+				step.Weight = concept.Weight
+				step.Importance = types.PrerequisiteImportance(concept.Weight)
+			}
+			conceptPath.Steps[i] = step
+
+			if !seen[concept.ID] {
+				seen[concept.ID] = true
+				concept.Type, concept.Weight = "", 0
+				result.Concepts = append(result.Concepts, concept)
+			}
+		}
+		result.Paths = append(result.Paths, conceptPath)
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// pathGraphRepo answers individual and batched path queries from the same
+// stored paths, counting batched calls
+type pathGraphRepo struct {
+	repositories.ConceptRepository
+	paths        map[string][]types.Concept
+	batchedCalls int
+}
+
+func (r *pathGraphRepo) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
+	return r.paths[targetConcepts[0]], nil
+}
+
+func (r *pathGraphRepo) FindPrerequisitePaths(ctx context.Context, conceptIDs []string) (map[string][]types.Concept, error) {
+	r.batchedCalls++
+	found := make(map[string][]types.Concept)
+	for _, id := range conceptIDs {
+		if path, ok := r.paths[id]; ok {
+			found[id] = path
+		}
+	}
+	return found, nil
+}
+
+func pathPrerequisite(id string, weight float64) types.Concept {
+	return types.Concept{ID: id, Name: id, Type: "prerequisite", Weight: weight}
+}
+
+func pathTarget(id string) types.Concept {
+	return types.Concept{ID: id, Name: id, Type: "target"}
+}
+
+func TestGetConceptPathsMatchesIndividualPaths(t *testing.T) {
+	repo := &pathGraphRepo{paths: map[string][]types.Concept{
+		"derivatives": {pathPrerequisite("functions", 0.8), pathPrerequisite("limits", 1), pathTarget("derivatives")},
+		"continuity":  {pathPrerequisite("functions", 0.3), pathPrerequisite("limits", 0.6), pathTarget("continuity")},
+		"limits":      {pathPrerequisite("functions", 1), pathTarget("limits")},
+	}}
+	svc := &queryService{conceptRepo: repo, logger: zap.NewNop()}
+
+	ids := []string{"derivatives", "continuity", "limits", "topology"}
+	result, err := svc.GetConceptPaths(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.batchedCalls != 1 {
+		t.Errorf("expected one batched graph query, got %d", repo.batchedCalls)
+	}
+
+	// Shared prerequisites are listed once
+	if len(result.Concepts) != 4 {
+		t.Errorf("expected 4 distinct concepts, got %+v", result.Concepts)
+	}
+	concepts := make(map[string]types.Concept)
+	for _, concept := range result.Concepts {
+		concepts[concept.ID] = concept
+	}
+	if len(result.NotFound) != 1 || result.NotFound[0] != "topology" {
+		t.Errorf("expected topology not found, got %v", result.NotFound)
+	}
+
+	if len(result.Paths) != 3 {
+		t.Fatalf("expected 3 paths, got %d", len(result.Paths))
+	}
+	for i, path := range result.Paths {
+		if path.ConceptID != ids[i] {
+			t.Errorf("path %d: expected %s in request order, got %s", i, ids[i], path.ConceptID)
+		}
+
+		individual, _ := repo.FindPrerequisitePath(context.Background(), []string{path.ConceptID})
+		if len(path.Steps) != len(individual) {
+			t.Fatalf("%s: expected %d steps, got %d", path.ConceptID, len(individual), len(path.Steps))
+		}
+		for j, step := range path.Steps {
+			want := individual[j]
+			if _, ok := concepts[step.ConceptID]; !ok || step.ConceptID != want.ID || step.Weight != want.Weight {
+				t.Errorf("%s step %d = %+v, want %s weighted %v", path.ConceptID, j, step, want.ID, want.Weight)
+			}
+			if step.Importance != types.PrerequisiteImportance(want.Weight) {
+				t.Errorf("%s step %d: unexpected importance %q", path.ConceptID, j, step.Importance)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// GetConceptProfile returns the structured profile of a concept, generating
+// and storing it on first request or when refresh is set
+func (s *queryService) GetConceptProfile(ctx context.Context, conceptID string, refresh bool) (*entities.ConceptProfile, error) {
+	if s.conceptProfileRepo != nil && !refresh {
+		cached, err := s.conceptProfileRepo.FindByConceptID(ctx, conceptID)
+		if err != nil {
+			s.logger.Warn("Failed to load cached concept profile",
+				zap.String("concept_id", conceptID),
+				zap.Error(err))
+		} else if cached != nil {
+			return cached, nil
+		}
+	}
+
+	concept, err := s.conceptRepo.FindByID(ctx, conceptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find concept: %w", err)
+	}
+	if concept == nil {
+		return nil, fmt.Errorf("%w: %s", services.ErrConceptNotFound, conceptID)
+	}
+
+	profile, err := s.llmClient.GenerateConceptProfile(ctx, concept.Name)
+	if err != nil {
+		return nil, err
+	}
+	profile.ConceptID = concept.ID
+	profile.ConceptName = concept.Name
+	profile.LLMModel = s.llmClient.Model()
+	profile.GeneratedAt = time.Now()
+
+	// An unparsed reply is served once but not stored, so the next request
+	// tries again instead of getting the raw text from the cache
+	if s.conceptProfileRepo != nil && profile.Structured {
+		if err := s.conceptProfileRepo.Save(ctx, profile); err != nil {
+			// Still serve the profile; it will be regenerated next time
+			s.logger.Error("Failed to store concept profile",
+				zap.String("concept_id", conceptID),
+				zap.Error(err))
+		}
+	}
+
+	return profile, nil
+}
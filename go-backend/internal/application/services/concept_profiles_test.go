@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// profileLLM returns a fixed profile and counts generations
+type profileLLM struct {
+	recordingLLM
+	structured  bool
+	generations int
+}
+
+func (l *profileLLM) GenerateConceptProfile(ctx context.Context, conceptName string) (*entities.ConceptProfile, error) {
+	l.generations++
+	if !l.structured {
+		return &entities.ConceptProfile{RawText: "Limits describe approaching values."}, nil
+	}
+	return &entities.ConceptProfile{Definition: "A limit is the value a function approaches.", Structured: true}, nil
+}
+
+// memProfileRepo keeps concept profiles in memory
+type memProfileRepo struct {
+	profiles map[string]*entities.ConceptProfile
+}
+
+func (r *memProfileRepo) Save(ctx context.Context, profile *entities.ConceptProfile) error {
+	r.profiles[profile.ConceptID] = profile
+	return nil
+}
+
+func (r *memProfileRepo) FindByConceptID(ctx context.Context, conceptID string) (*entities.ConceptProfile, error) {
+	return r.profiles[conceptID], nil
+}
+
+func (r *memProfileRepo) Delete(ctx context.Context, conceptID string) error {
+	delete(r.profiles, conceptID)
+	return nil
+}
+
+func newProfileService(llm *profileLLM, profiles *memProfileRepo) *queryService {
+	return &queryService{
+		conceptRepo: &stubConceptRepo{concepts: map[string]*types.Concept{
+			"Limits": {ID: "limits", Name: "Limits"},
+		}},
+		conceptProfileRepo: profiles,
+		llmClient:          llm,
+		logger:             zap.NewNop(),
+	}
+}
+
+func TestGetConceptProfileStoresOnlyStructuredProfiles(t *testing.T) {
+	ctx := context.Background()
+
+	for _, structured := range []bool{true, false} {
+		llm := &profileLLM{structured: structured}
+		profiles := &memProfileRepo{profiles: map[string]*entities.ConceptProfile{}}
+		svc := newProfileService(llm, profiles)
+
+		for i := 0; i < 2; i++ {
+			profile, err := svc.GetConceptProfile(ctx, "limits", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if profile.ConceptName != "Limits" || profile.Structured != structured {
+				t.Errorf("profile = %+v", profile)
+			}
+		}
+
+		wantGenerations := 1
+		if !structured {
+			wantGenerations = 2
+		}
+		if llm.generations != wantGenerations {
+			t.Errorf("structured %v: generated %d times, want %d", structured, llm.generations, wantGenerations)
+		}
+		if _, stored := profiles.profiles["limits"]; stored != structured {
+			t.Errorf("structured %v: stored %v", structured, stored)
+		}
+	}
+}
+
+func TestGetConceptProfileUnknownConcept(t *testing.T) {
+	llm := &profileLLM{structured: true}
+	svc := newProfileService(llm, &memProfileRepo{profiles: map[string]*entities.ConceptProfile{}})
+
+	_, err := svc.GetConceptProfile(context.Background(), "no-such-concept", false)
+	if !errors.Is(err, services.ErrConceptNotFound) {
+		t.Errorf("GetConceptProfile() error = %v, want ErrConceptNotFound", err)
+	}
+	if llm.generations != 0 {
+		t.Errorf("generated a profile for an unknown concept")
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// conceptRetrievalTerms returns the nearText concepts a concept explanation
+// is grounded in: the concept name, then its category, description and
+// aliases when the concept is in the graph. Lookups are best effort; a
+// concept that cannot be found is searched by name alone.
+func (s *queryService) conceptRetrievalTerms(ctx context.Context, conceptName string) []string {
+	terms := []string{conceptName}
+
+	concept, err := s.conceptRepo.FindByName(ctx, conceptName)
+	if err != nil || concept == nil {
+		s.logger.Debug("Concept not found in graph, retrieving context by name only",
+			zap.String("concept", conceptName),
+			zap.Error(err))
+		return terms
+	}
+
+	terms = append(terms, concept.Category, concept.Description)
+	terms = append(terms, s.conceptAliasesByID(ctx)[concept.ID]...)
+	return uniqueTerms(terms)
+}
+
+// uniqueTerms drops blank terms and repeats that differ only in case or spacing
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		key := strings.Join(strings.Fields(strings.ToLower(term)), " ")
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, term)
+	}
+	return unique
+}
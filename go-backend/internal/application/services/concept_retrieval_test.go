@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+)
+
+// termsVectorRepo records what each textbook search was near
+type termsVectorRepo struct {
+	repositories.VectorRepository
+	texts  []string
+	terms  [][]string
+	limits []int
+}
+
+func (r *termsVectorRepo) Search(ctx context.Context, query string, limit int) ([]types.VectorResult, error) {
+	r.texts = append(r.texts, query)
+	r.limits = append(r.limits, limit)
+	return nil, nil
+}
+
+func (r *termsVectorRepo) SearchByConcepts(ctx context.Context, concepts []string, limit int) ([]types.VectorResult, error) {
+	r.terms = append(r.terms, concepts)
+	r.limits = append(r.limits, limit)
+	return nil, nil
+}
+
+// namedConceptRepo finds graph concepts by name
+type namedConceptRepo struct {
+	pathConceptRepo
+	concepts map[string]*types.Concept
+}
+
+func (r *namedConceptRepo) FindByName(ctx context.Context, name string) (*types.Concept, error) {
+	if concept, ok := r.concepts[name]; ok {
+		return concept, nil
+	}
+	return nil, fmt.Errorf("concept not found: %s", name)
+}
+
+func newConceptRetrievalService(vectors *termsVectorRepo, tasks *background.Tasks) *queryService {
+	svc := newSmartQueryService(&cachingQueryRepo{}, tasks)
+	svc.vectorRepo = vectors
+	svc.conceptQueryChunks = 7
+	svc.conceptRepo = &namedConceptRepo{concepts: map[string]*types.Concept{
+		"Limits": {ID: "limits", Name: "Limits", Category: "calculus", Description: "The value a function approaches"},
+	}}
+	svc.aliasRepo = &memoryAliasRepo{aliases: []*entities.ConceptAlias{
+		entities.NewConceptAlias("lim", "limits", "Limits", ""),
+		entities.NewConceptAlias("limit", "limits", "Limits", ""),
+		entities.NewConceptAlias("ibp", "integration_by_parts", "Integration by Parts", ""),
+	}}
+	return svc
+}
+
+func TestSmartConceptQueryRetrievesNearConceptTerms(t *testing.T) {
+	vectors := &termsVectorRepo{}
+	tasks := background.NewTasks()
+	svc := newConceptRetrievalService(vectors, tasks)
+
+	if _, err := svc.SmartConceptQuery(context.Background(), "Limits", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if len(vectors.texts) != 0 {
+		t.Errorf("expected no search near the instruction prompt, got %q", vectors.texts)
+	}
+	want := []string{"Limits", "calculus", "The value a function approaches", "lim", "limit"}
+	if len(vectors.terms) != 1 || !reflect.DeepEqual(vectors.terms[0], want) {
+		t.Errorf("expected a search near %v, got %v", want, vectors.terms)
+	}
+	if len(vectors.limits) != 1 || vectors.limits[0] != 7 {
+		t.Errorf("expected the configured 7 chunks, got %v", vectors.limits)
+	}
+}
+
+func TestConceptRetrievalTermsWithoutGraphConcept(t *testing.T) {
+	svc := newConceptRetrievalService(&termsVectorRepo{}, background.NewTasks())
+
+	if got := svc.conceptRetrievalTerms(context.Background(), "Fourier Series"); !reflect.DeepEqual(got, []string{"Fourier Series"}) {
+		t.Errorf("expected an unknown concept searched by name, got %v", got)
+	}
+}
+
+func TestUniqueTerms(t *testing.T) {
+	got := uniqueTerms([]string{"Limits", " ", "limits ", "Calculus", "", "calculus"})
+	if !reflect.DeepEqual(got, []string{"Limits", "Calculus"}) {
+		t.Errorf("unexpected terms %v", got)
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+)
+
+// Roadmap concept types
+const (
+	RoadmapPrerequisite = "prerequisite"
+	RoadmapTarget       = "target"
+	RoadmapNext         = "next"
+)
+
+// GetConceptRoadmap returns a concept's prerequisite path ordered so every
+// prerequisite comes after its own prerequisites, the concept, and up to
+// nextLimit concepts it is a direct prerequisite for
+func (s *queryService) GetConceptRoadmap(ctx context.Context, conceptID string, nextLimit int) (*services.ConceptRoadmap, error) {
+	path, err := s.conceptRepo.FindPrerequisitePath(ctx, []string{conceptID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prerequisite path: %w", err)
+	}
+
+	roadmap := &services.ConceptRoadmap{Upstream: []types.Concept{}, NextSteps: []types.Concept{}}
+	found := false
+	var prerequisites []types.Concept
+	for _, concept := range path {
+		if concept.Type == RoadmapTarget && !found {
+			roadmap.Target = concept
+			found = true
+			continue
+		}
+		prerequisites = append(prerequisites, concept)
+	}
+	if !found {
+		return nil, fmt.Errorf("concept not found: %s", conceptID)
+	}
+
+	if len(prerequisites) > 0 {
+		ids := make([]string, len(prerequisites))
+		for i, concept := range prerequisites {
+			ids[i] = concept.ID
+		}
+		edges, err := s.conceptRepo.GetEdgesAmong(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prerequisite edges: %w", err)
+		}
+		roadmap.Upstream = orderPrerequisites(prerequisites, edges)
+	}
+	for i := range roadmap.Upstream {
+		roadmap.Upstream[i].Type = RoadmapPrerequisite
+	}
+
+	next, err := s.conceptRepo.FindDependentConcepts(ctx, roadmap.Target.ID, nextLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find next concepts: %w", err)
+	}
+	for _, concept := range next {
+		concept.Type = RoadmapNext
+		roadmap.NextSteps = append(roadmap.NextSteps, concept)
+	}
+
+	return roadmap, nil
+}
+
+// orderPrerequisites sorts concepts so each comes after the concepts it
+// depends on. Among concepts that are ready at the same time the given
+// order is kept; concepts caught in a cycle follow in the given order.
+func orderPrerequisites(concepts []types.Concept, edges []types.NeighborhoodEdge) []types.Concept {
+	index := make(map[string]int, len(concepts))
+	for i, concept := range concepts {
+		index[concept.ID] = i
+	}
+	waiting := make([]int, len(concepts))
+	unlocks := make([][]int, len(concepts))
+	for _, edge := range edges {
+		from, okFrom := index[edge.From]
+		to, okTo := index[edge.To]
+		if !okFrom || !okTo || from == to {
+			continue
+		}
+		waiting[to]++
+		unlocks[from] = append(unlocks[from], to)
+	}
+
+	ordered := make([]types.Concept, 0, len(concepts))
+	placed := make([]bool, len(concepts))
+	for len(ordered) < len(concepts) {
+		next := -1
+		for i := range concepts {
+			if !placed[i] && waiting[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			// A cycle: release the first unplaced concept
+			for i := range concepts {
+				if !placed[i] {
+					next = i
+					break
+				}
+			}
+		}
+		placed[next] = true
+		ordered = append(ordered, concepts[next])
+		for _, to := range unlocks[next] {
+			waiting[to]--
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// roadmapConceptRepo serves a fixed path, the edges among it and dependents
+type roadmapConceptRepo struct {
+	repositories.ConceptRepository
+	path       []types.Concept
+	edges      []types.NeighborhoodEdge
+	dependents []types.Concept
+	nextLimit  int
+}
+
+func (r *roadmapConceptRepo) FindPrerequisitePath(ctx context.Context, targets []string) ([]types.Concept, error) {
+	return r.path, nil
+}
+
+func (r *roadmapConceptRepo) GetEdgesAmong(ctx context.Context, ids []string) ([]types.NeighborhoodEdge, error) {
+	return r.edges, nil
+}
+
+func (r *roadmapConceptRepo) FindDependentConcepts(ctx context.Context, conceptID string, limit int) ([]types.Concept, error) {
+	r.nextLimit = limit
+	return r.dependents, nil
+}
+
+func roadmapIDs(concepts []types.Concept) string {
+	ids := make([]string, len(concepts))
+	for i, concept := range concepts {
+		ids[i] = concept.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestGetConceptRoadmapOrdersBothDirections(t *testing.T) {
+	// The path comes back by name; algebra must precede functions, which
+	// precedes limits
+	repo := &roadmapConceptRepo{
+		path: []types.Concept{
+			{ID: "algebra", Name: "Algebra", Type: "prerequisite"},
+			{ID: "functions", Name: "Functions", Type: "prerequisite"},
+			{ID: "limits", Name: "Limits", Type: "prerequisite"},
+			{ID: "derivatives", Name: "Derivatives", Type: "target"},
+		},
+		edges: []types.NeighborhoodEdge{
+			{From: "limits", To: "derivatives"},
+			{From: "functions", To: "limits"},
+			{From: "algebra", To: "functions"},
+		},
+		dependents: []types.Concept{
+			{ID: "chain_rule", Name: "Chain Rule", Weight: 1},
+			{ID: "integrals", Name: "Integrals", Weight: 0.6},
+		},
+	}
+	// Put the path out of dependency order to check it is reordered
+	repo.path[0], repo.path[2] = repo.path[2], repo.path[0]
+	svc := &queryService{conceptRepo: repo, logger: zap.NewNop()}
+
+	roadmap, err := svc.GetConceptRoadmap(context.Background(), "derivatives", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roadmap.Target.ID != "derivatives" || roadmap.Target.Type != RoadmapTarget {
+		t.Errorf("expected derivatives marked as the target, got %+v", roadmap.Target)
+	}
+	if got := roadmapIDs(roadmap.Upstream); got != "algebra,functions,limits" {
+		t.Errorf("expected prerequisites in learning order, got %s", got)
+	}
+	for _, concept := range roadmap.Upstream {
+		if concept.Type != RoadmapPrerequisite {
+			t.Errorf("expected %s marked as a prerequisite, got %q", concept.ID, concept.Type)
+		}
+	}
+	if got := roadmapIDs(roadmap.NextSteps); got != "chain_rule,integrals" {
+		t.Errorf("expected next steps in graph order, got %s", got)
+	}
+	if roadmap.NextSteps[0].Type != RoadmapNext || repo.nextLimit != 5 {
+		t.Errorf("expected next steps marked and limited, got %+v with limit %d", roadmap.NextSteps[0], repo.nextLimit)
+	}
+}
+
+func TestGetConceptRoadmapUnknownConcept(t *testing.T) {
+	svc := &queryService{conceptRepo: &roadmapConceptRepo{}, logger: zap.NewNop()}
+
+	_, err := svc.GetConceptRoadmap(context.Background(), "topology", 5)
+	if err == nil || !strings.Contains(err.Error(), "concept not found") {
+		t.Errorf("expected concept not found, got %v", err)
+	}
+}
+
+func TestOrderPrerequisitesKeepsOrderAndBreaksCycles(t *testing.T) {
+	concepts := []types.Concept{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	edges := []types.NeighborhoodEdge{
+		{From: "c", To: "a"},
+		{From: "a", To: "b"},
+		{From: "b", To: "a"},
+	}
+
+	if got := roadmapIDs(orderPrerequisites(concepts, edges)); got != "c,d,a,b" {
+		t.Errorf("expected c,d,a,b, got %s", got)
+	}
+}
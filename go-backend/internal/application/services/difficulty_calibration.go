@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+const (
+	// calibrationPriorWeight is how many baseline-rate queries each concept's
+	// success rate is blended with, so a handful of failures on a rarely
+	// asked concept does not swing its difficulty
+	calibrationPriorWeight = 5.0
+
+	// calibrationScale turns a success-rate gap into difficulty steps: 10
+	// points below the baseline suggests one step harder
+	calibrationScale = 10.0
+
+	// maxCalibrationStep caps how far one calibration moves a difficulty
+	maxCalibrationStep = 3
+
+	// defaultConceptDifficulty stands in for concepts never given one
+	defaultConceptDifficulty = 5
+)
+
+// GetDifficultyCalibration compares the success rate of queries that
+// identified each graph concept with the rate across all queries and
+// suggests raising the difficulty of concepts that fail more often, and
+// lowering it for concepts that are answered more reliably. Concepts seen in
+// fewer than minQueries queries are skipped. Suggestions are sorted by the
+// size of the change, then by query count.
+func (s *queryService) GetDifficultyCalibration(ctx context.Context, window time.Duration, minQueries int) (*services.DifficultyCalibration, error) {
+	since := time.Now().Add(-window)
+	outcomes, err := s.queryRepo.GetConceptOutcomes(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get concept outcomes: %w", err)
+	}
+
+	graphConcepts, err := s.conceptRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph concepts: %w", err)
+	}
+	byMention := make(map[string]int, len(graphConcepts)*2)
+	for i, concept := range graphConcepts {
+		byMention[normalizeMention(concept.Name)] = i
+		byMention[normalizeMention(concept.ID)] = i
+	}
+
+	// Fold name and ID mentions of the same concept together, and take the
+	// baseline over every identified concept, known to the graph or not
+	type tally struct{ queries, successful int64 }
+	tallies := make(map[int]*tally)
+	var total tally
+	for _, outcome := range outcomes {
+		total.queries += outcome.Queries
+		total.successful += outcome.Successful
+
+		i, ok := byMention[normalizeMention(outcome.ConceptName)]
+		if !ok {
+			continue
+		}
+		t, ok := tallies[i]
+		if !ok {
+			t = &tally{}
+			tallies[i] = t
+		}
+		t.queries += outcome.Queries
+		t.successful += outcome.Successful
+	}
+
+	calibration := &services.DifficultyCalibration{
+		Since:       since,
+		MinQueries:  minQueries,
+		Suggestions: []services.DifficultySuggestion{},
+	}
+	if total.queries == 0 {
+		return calibration, nil
+	}
+	baseline := float64(total.successful) / float64(total.queries)
+	calibration.BaselineSuccess = baseline
+
+	for i, t := range tallies {
+		if t.queries < int64(minQueries) {
+			continue
+		}
+		calibration.ConceptsScored++
+
+		concept := graphConcepts[i]
+		current := concept.Difficulty
+		if current <= 0 {
+			current = defaultConceptDifficulty
+		}
+		smoothed := (float64(t.successful) + calibrationPriorWeight*baseline) /
+			(float64(t.queries) + calibrationPriorWeight)
+		suggested := calibratedDifficulty(current, baseline-smoothed)
+		if suggested == current {
+			continue
+		}
+
+		calibration.Suggestions = append(calibration.Suggestions, services.DifficultySuggestion{
+			ConceptID:           concept.ID,
+			ConceptName:         concept.Name,
+			CurrentDifficulty:   current,
+			SuggestedDifficulty: suggested,
+			Queries:             t.queries,
+			SuccessRate:         float64(t.successful) / float64(t.queries),
+		})
+	}
+
+	sort.Slice(calibration.Suggestions, func(i, j int) bool {
+		a, b := calibration.Suggestions[i], calibration.Suggestions[j]
+		da := math.Abs(float64(a.SuggestedDifficulty - a.CurrentDifficulty))
+		db := math.Abs(float64(b.SuggestedDifficulty - b.CurrentDifficulty))
+		if da != db {
+			return da > db
+		}
+		if a.Queries != b.Queries {
+			return a.Queries > b.Queries
+		}
+		return a.ConceptID < b.ConceptID
+	})
+
+	s.logger.Info("Computed difficulty calibration",
+		zap.Float64("baseline_success_rate", baseline),
+		zap.Int("concepts_scored", calibration.ConceptsScored),
+		zap.Int("suggestions", len(calibration.Suggestions)))
+
+	return calibration, nil
+}
+
+// calibratedDifficulty moves a difficulty by the success-rate gap, where a
+// positive gap means the concept fails more often than the baseline
+func calibratedDifficulty(current int, gap float64) int {
+	step := int(math.Round(gap * calibrationScale))
+	if step > maxCalibrationStep {
+		step = maxCalibrationStep
+	}
+	if step < -maxCalibrationStep {
+		step = -maxCalibrationStep
+	}
+	suggested := current + step
+	if suggested < 1 {
+		return 1
+	}
+	if suggested > 10 {
+		return 10
+	}
+	return suggested
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+type stubOutcomeQueryRepo struct {
+	repositories.QueryRepository
+	outcomes []repositories.ConceptOutcome
+}
+
+func (r *stubOutcomeQueryRepo) GetConceptOutcomes(ctx context.Context, since time.Time) ([]repositories.ConceptOutcome, error) {
+	return r.outcomes, nil
+}
+
+func TestGetDifficultyCalibration(t *testing.T) {
+	svc := &queryService{
+		queryRepo: &stubOutcomeQueryRepo{outcomes: []repositories.ConceptOutcome{
+			{ConceptName: "limits", Queries: 1000, Successful: 850},
+			{ConceptName: "derivatives", Queries: 1000, Successful: 850},
+			{ConceptName: "Laplace Transform", Queries: 30, Successful: 9},
+			{ConceptName: "laplace_transform", Queries: 10, Successful: 3},
+			{ConceptName: "fractions", Queries: 40, Successful: 40},
+			{ConceptName: "stokes theorem", Queries: 3, Successful: 0},
+			{ConceptName: "not in graph", Queries: 20, Successful: 17},
+		}},
+		conceptRepo: &stubAllConceptRepo{concepts: []types.Concept{
+			{ID: "limits", Name: "Limits", Difficulty: 5},
+			{ID: "derivatives", Name: "Derivatives", Difficulty: 5},
+			{ID: "laplace_transform", Name: "Laplace Transform", Difficulty: 6},
+			{ID: "fractions", Name: "Fractions", Difficulty: 3},
+			{ID: "stokes_theorem", Name: "Stokes Theorem", Difficulty: 8},
+		}},
+		logger: zap.NewNop(),
+	}
+
+	calibration, err := svc.GetDifficultyCalibration(context.Background(), 30*24*time.Hour, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calibration.ConceptsScored != 4 {
+		t.Errorf("expected 4 concepts scored, got %d", calibration.ConceptsScored)
+	}
+	if len(calibration.Suggestions) != 2 {
+		t.Fatalf("expected 2 suggestions, got %+v", calibration.Suggestions)
+	}
+
+	harder := calibration.Suggestions[0]
+	if harder.ConceptID != "laplace_transform" || harder.Queries != 40 {
+		t.Fatalf("expected the low-success concept first with merged counts, got %+v", harder)
+	}
+	if harder.SuggestedDifficulty != 9 {
+		t.Errorf("expected low success to raise difficulty 6 -> 9, got %d", harder.SuggestedDifficulty)
+	}
+
+	easier := calibration.Suggestions[1]
+	if easier.ConceptID != "fractions" || easier.SuggestedDifficulty != 2 {
+		t.Errorf("expected high success to lower fractions to 2, got %+v", easier)
+	}
+}
+
+func TestCalibratedDifficultyBounds(t *testing.T) {
+	tests := []struct {
+		current int
+		gap     float64
+		want    int
+	}{
+		{5, 0.04, 5},
+		{5, 0.12, 6},
+		{5, 0.9, 8},
+		{9, 0.3, 10},
+		{2, -0.3, 1},
+	}
+	for _, tt := range tests {
+		if got := calibratedDifficulty(tt.current, tt.gap); got != tt.want {
+			t.Errorf("calibratedDifficulty(%d, %v) = %d, want %d", tt.current, tt.gap, got, tt.want)
+		}
+	}
+}
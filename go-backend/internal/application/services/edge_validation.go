@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/services"
+)
+
+// ValidatePrerequisiteEdge checks a proposed edge making prerequisiteID a
+// prerequisite of sourceID. Both concepts are looked up in one batch; the
+// duplicate and cycle checks need both to exist and are skipped otherwise.
+// Nothing is written.
+func (s *queryService) ValidatePrerequisiteEdge(ctx context.Context, sourceID, prerequisiteID string) (*services.EdgeValidation, error) {
+	verdict := &services.EdgeValidation{SourceID: sourceID, PrerequisiteID: prerequisiteID}
+
+	found, err := s.conceptRepo.FindPrerequisitePaths(ctx, []string{sourceID, prerequisiteID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up concepts: %w", err)
+	}
+	_, verdict.SourceExists = found[sourceID]
+	_, verdict.PrerequisiteExists = found[prerequisiteID]
+	if !verdict.SourceExists {
+		verdict.Reasons = append(verdict.Reasons, fmt.Sprintf("concept %s is not in the graph", sourceID))
+	}
+	if !verdict.PrerequisiteExists && prerequisiteID != sourceID {
+		verdict.Reasons = append(verdict.Reasons, fmt.Sprintf("concept %s is not in the graph", prerequisiteID))
+	}
+	if !verdict.SourceExists || !verdict.PrerequisiteExists {
+		return verdict, nil
+	}
+
+	edges, err := s.conceptRepo.GetEdgesAmong(ctx, []string{sourceID, prerequisiteID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up existing edges: %w", err)
+	}
+	for _, edge := range edges {
+		if edge.From == prerequisiteID && edge.To == sourceID {
+			verdict.Duplicate = true
+			verdict.Reasons = append(verdict.Reasons, fmt.Sprintf("%s is already a prerequisite of %s", prerequisiteID, sourceID))
+			break
+		}
+	}
+
+	if verdict.CreatesCycle = s.closesCycle(ctx, sourceID, prerequisiteID); verdict.CreatesCycle {
+		if sourceID == prerequisiteID {
+			verdict.Reasons = append(verdict.Reasons, "a concept cannot be its own prerequisite")
+		} else {
+			verdict.Reasons = append(verdict.Reasons, fmt.Sprintf("%s is already upstream of %s, so the edge would close a cycle", sourceID, prerequisiteID))
+		}
+	}
+
+	verdict.Valid = len(verdict.Reasons) == 0
+	return verdict, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// edgeGraphRepo is a small graph of concepts and prerequisite edges, From
+// being the prerequisite of To
+type edgeGraphRepo struct {
+	repositories.ConceptRepository
+	ids   []string
+	edges []types.NeighborhoodEdge
+}
+
+func (r *edgeGraphRepo) has(id string) bool {
+	for _, known := range r.ids {
+		if known == id {
+			return true
+		}
+	}
+	return false
+}
+
+// upstream returns every concept reachable backwards from id
+func (r *edgeGraphRepo) upstream(id string, seen map[string]bool) {
+	for _, edge := range r.edges {
+		if edge.To == id && !seen[edge.From] {
+			seen[edge.From] = true
+			r.upstream(edge.From, seen)
+		}
+	}
+}
+
+func (r *edgeGraphRepo) FindPrerequisitePath(ctx context.Context, targets []string) ([]types.Concept, error) {
+	if len(targets) == 0 || !r.has(targets[0]) {
+		return nil, nil
+	}
+	seen := map[string]bool{}
+	r.upstream(targets[0], seen)
+	var path []types.Concept
+	for id := range seen {
+		path = append(path, types.Concept{ID: id, Type: "prerequisite"})
+	}
+	return append(path, types.Concept{ID: targets[0], Type: "target"}), nil
+}
+
+func (r *edgeGraphRepo) FindPrerequisitePaths(ctx context.Context, ids []string) (map[string][]types.Concept, error) {
+	paths := map[string][]types.Concept{}
+	for _, id := range ids {
+		if r.has(id) {
+			paths[id], _ = r.FindPrerequisitePath(ctx, []string{id})
+		}
+	}
+	return paths, nil
+}
+
+func (r *edgeGraphRepo) GetEdgesAmong(ctx context.Context, ids []string) ([]types.NeighborhoodEdge, error) {
+	var edges []types.NeighborhoodEdge
+	for _, edge := range r.edges {
+		if (edge.From == ids[0] || edge.From == ids[1]) && (edge.To == ids[0] || edge.To == ids[1]) {
+			edges = append(edges, edge)
+		}
+	}
+	return edges, nil
+}
+
+func TestValidatePrerequisiteEdge(t *testing.T) {
+	repo := &edgeGraphRepo{
+		ids: []string{"functions", "limits", "derivatives", "integrals"},
+		edges: []types.NeighborhoodEdge{
+			{From: "functions", To: "limits"},
+			{From: "limits", To: "derivatives"},
+		},
+	}
+	svc := &queryService{conceptRepo: repo, logger: zap.NewNop()}
+
+	tests := []struct {
+		name         string
+		source       string
+		prerequisite string
+		wantValid    bool
+		wantExists   [2]bool
+		wantDup      bool
+		wantCycle    bool
+		wantReasons  int
+	}{
+		{"valid", "integrals", "derivatives", true, [2]bool{true, true}, false, false, 0},
+		{"missing source", "series", "limits", false, [2]bool{false, true}, false, false, 1},
+		{"both missing", "series", "sequences", false, [2]bool{false, false}, false, false, 2},
+		{"duplicate", "derivatives", "limits", false, [2]bool{true, true}, true, false, 1},
+		{"cycle", "functions", "derivatives", false, [2]bool{true, true}, false, true, 1},
+		{"self", "limits", "limits", false, [2]bool{true, true}, false, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := svc.ValidatePrerequisiteEdge(context.Background(), tt.source, tt.prerequisite)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if verdict.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v (reasons %v)", verdict.Valid, tt.wantValid, verdict.Reasons)
+			}
+			if got := [2]bool{verdict.SourceExists, verdict.PrerequisiteExists}; got != tt.wantExists {
+				t.Errorf("exists = %v, want %v", got, tt.wantExists)
+			}
+			if verdict.Duplicate != tt.wantDup || verdict.CreatesCycle != tt.wantCycle {
+				t.Errorf("Duplicate = %v, CreatesCycle = %v, want %v, %v", verdict.Duplicate, verdict.CreatesCycle, tt.wantDup, tt.wantCycle)
+			}
+			if len(verdict.Reasons) != tt.wantReasons {
+				t.Errorf("Reasons = %v, want %d", verdict.Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+var errExplanationVersionsUnavailable = errors.New("explanation versions are not available without MongoDB")
+
+// maxExplanationVersionAttempts bounds the retries when concurrent writers
+// race for a concept's next version number
+const maxExplanationVersionAttempts = 3
+
+// ListExplanationVersions returns every stored explanation of a concept, newest first
+func (s *queryService) ListExplanationVersions(ctx context.Context, conceptName string) ([]*entities.ExplanationVersion, error) {
+	if s.explanationRepo == nil {
+		return nil, errExplanationVersionsUnavailable
+	}
+	return s.explanationRepo.FindByConcept(ctx, normalizeMention(conceptName))
+}
+
+// ActivateExplanationVersion makes an older or newer explanation the one
+// served for a concept. It stays active until an admin activates another
+// version, even when the explanation is regenerated. A missing version
+// yields nil.
+func (s *queryService) ActivateExplanationVersion(ctx context.Context, conceptName string, version int) (*entities.ExplanationVersion, error) {
+	if s.explanationRepo == nil {
+		return nil, errExplanationVersionsUnavailable
+	}
+
+	concept := normalizeMention(conceptName)
+	found, err := s.explanationRepo.Activate(ctx, concept, version, true)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	versions, err := s.explanationRepo.FindByConcept(ctx, concept)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// recordExplanationVersion stores a freshly generated concept explanation as
+// a new version. It becomes active unless an admin pinned another version.
+// Failures are logged; the explanation is still served.
+func (s *queryService) recordExplanationVersion(ctx context.Context, conceptName string, query *entities.Query) {
+	if s.explanationRepo == nil || query == nil || query.Response.Explanation == "" {
+		return
+	}
+
+	concept := normalizeMention(conceptName)
+
+	// Another writer may take the next version number first; the unique
+	// index rejects the duplicate and the number is recomputed
+	var version *entities.ExplanationVersion
+	pinned := false
+	for attempt := 1; ; attempt++ {
+		versions, err := s.explanationRepo.FindByConcept(ctx, concept)
+		if err != nil {
+			s.logger.Warn("Failed to load explanation versions",
+				zap.String("concept", concept),
+				zap.Error(err))
+			return
+		}
+
+		next := 1
+		pinned = false
+		for _, v := range versions {
+			if v.Version >= next {
+				next = v.Version + 1
+			}
+			if v.Active && v.Pinned {
+				pinned = true
+			}
+		}
+
+		version = &entities.ExplanationVersion{
+			ID:          uuid.New().String(),
+			Concept:     concept,
+			Version:     next,
+			QueryID:     query.ID,
+			Explanation: query.Response.Explanation,
+			LLMProvider: query.Response.LLMProvider,
+			LLMModel:    query.Response.LLMModel,
+			CreatedAt:   time.Now(),
+		}
+		err = s.explanationRepo.Save(ctx, version)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, repositories.ErrExplanationVersionExists) || attempt == maxExplanationVersionAttempts {
+			s.logger.Warn("Failed to save explanation version",
+				zap.String("concept", concept),
+				zap.Int("attempts", attempt),
+				zap.Error(err))
+			return
+		}
+	}
+
+	if pinned {
+		return
+	}
+	if _, err := s.explanationRepo.Activate(ctx, concept, version.Version, false); err != nil {
+		s.logger.Warn("Failed to activate explanation version",
+			zap.String("concept", concept),
+			zap.Int("version", version.Version),
+			zap.Error(err))
+	}
+}
+
+// applyActiveExplanation serves the concept's active explanation version in
+// place of the one on the query, when a version is active
+func (s *queryService) applyActiveExplanation(ctx context.Context, conceptName string, result *services.QueryResult) {
+	if s.explanationRepo == nil {
+		return
+	}
+
+	versions, err := s.explanationRepo.FindByConcept(ctx, normalizeMention(conceptName))
+	if err != nil {
+		s.logger.Warn("Failed to load explanation versions",
+			zap.String("concept", conceptName),
+			zap.Error(err))
+		return
+	}
+	for _, v := range versions {
+		if v.Active {
+			result.Explanation = v.Explanation
+			return
+		}
+	}
+}
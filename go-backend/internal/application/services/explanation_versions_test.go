@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+)
+
+// memExplanationRepo keeps explanation versions in memory. Like the unique
+// index, it rejects a version number its concept already has.
+type memExplanationRepo struct {
+	mu       sync.Mutex
+	versions []*entities.ExplanationVersion
+
+	// beforeSave runs once before the next save, outside the lock
+	beforeSave func()
+}
+
+func (r *memExplanationRepo) Save(ctx context.Context, version *entities.ExplanationVersion) error {
+	if hook := r.beforeSave; hook != nil {
+		r.beforeSave = nil
+		hook()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range r.versions {
+		if v.Concept == version.Concept && v.Version == version.Version {
+			return repositories.ErrExplanationVersionExists
+		}
+	}
+	r.versions = append(r.versions, version)
+	return nil
+}
+
+func (r *memExplanationRepo) FindByConcept(ctx context.Context, concept string) ([]*entities.ExplanationVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*entities.ExplanationVersion
+	for _, v := range r.versions {
+		if v.Concept == concept {
+			copied := *v
+			found = append(found, &copied)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Version > found[j].Version })
+	return found, nil
+}
+
+func (r *memExplanationRepo) Activate(ctx context.Context, concept string, version int, pinned bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := false
+	for _, v := range r.versions {
+		if v.Concept == concept && v.Version == version {
+			found = true
+		}
+	}
+	if !found {
+		return false, nil
+	}
+	for _, v := range r.versions {
+		if v.Concept == concept {
+			v.Active = v.Version == version
+			v.Pinned = v.Active && pinned
+		}
+	}
+	return true, nil
+}
+
+func (r *memExplanationRepo) DeleteByConcept(ctx context.Context, concept string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.versions[:0]
+	for _, v := range r.versions {
+		if v.Concept != concept {
+			kept = append(kept, v)
+		}
+	}
+	deleted := int64(len(r.versions) - len(kept))
+	r.versions = kept
+	return deleted, nil
+}
+
+func TestExplanationVersionsActivateOlderVersion(t *testing.T) {
+	ctx := context.Background()
+	tasks := background.NewTasks()
+	queryRepo := &cachingQueryRepo{}
+	versions := &memExplanationRepo{}
+	svc := newSmartQueryService(queryRepo, tasks)
+	svc.explanationRepo = versions
+	llm := svc.llmClient.(*recordingLLM)
+
+	generate := func(explanation string) string {
+		t.Helper()
+		llm.explanation["English"] = explanation
+		result, err := svc.SmartConceptQuery(ctx, "Limits", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		drainTasks(t, tasks)
+		return result.Explanation
+	}
+
+	generate("first explanation")
+	if got := generate("second explanation"); got != "second explanation" {
+		t.Errorf("newest version should be served, got %q", got)
+	}
+
+	listed, err := svc.ListExplanationVersions(ctx, "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || listed[0].Version != 2 || !listed[0].Active || listed[1].Active {
+		t.Fatalf("unexpected versions: %+v", listed)
+	}
+	if listed[1].LLMModel != "stub" || listed[1].Explanation != "first explanation" {
+		t.Errorf("version 1 = %+v", listed[1])
+	}
+
+	activated, err := svc.ActivateExplanationVersion(ctx, "Limits", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if activated == nil || activated.Version != 1 || !activated.Active || !activated.Pinned {
+		t.Fatalf("activated = %+v", activated)
+	}
+
+	// Cached concept queries now serve the rolled-back explanation
+	cached := entities.NewQuery("", "Explain limits", "")
+	cached.Response.Explanation = "second explanation"
+	queryRepo.cached = map[string]*entities.Query{"Limits": cached}
+	result, err := svc.SmartConceptQuery(ctx, "Limits", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+	if result.Explanation != "first explanation" {
+		t.Errorf("cached query served %q, want the activated version", result.Explanation)
+	}
+
+	// A regenerated explanation is kept but does not replace a pinned version
+	cached.Timestamp = time.Now().Add(-60 * 24 * time.Hour)
+	if got := generate("third explanation"); got != "first explanation" {
+		t.Errorf("regeneration replaced the pinned version, served %q", got)
+	}
+	listed, _ = svc.ListExplanationVersions(ctx, "limits")
+	if len(listed) != 3 || listed[0].Active {
+		t.Errorf("expected an inactive third version, got %+v", listed)
+	}
+}
+
+func TestActivateMissingExplanationVersion(t *testing.T) {
+	svc := newSmartQueryService(&cachingQueryRepo{}, background.NewTasks())
+	svc.explanationRepo = &memExplanationRepo{}
+
+	activated, err := svc.ActivateExplanationVersion(context.Background(), "limits", 3)
+	if err != nil || activated != nil {
+		t.Errorf("ActivateExplanationVersion() = %+v, %v; want nil, nil", activated, err)
+	}
+}
+
+func TestRecordExplanationVersionRetriesTakenNumber(t *testing.T) {
+	ctx := context.Background()
+	versions := &memExplanationRepo{}
+	svc := newSmartQueryService(&cachingQueryRepo{}, background.NewTasks())
+	svc.explanationRepo = versions
+
+	// Another writer records version 1 between the lookup and the save
+	versions.beforeSave = func() {
+		if err := versions.Save(ctx, &entities.ExplanationVersion{Concept: "limits", Version: 1, Active: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	query := entities.NewQuery("", "Explain limits", "")
+	query.Response.Explanation = "concurrent explanation"
+	svc.recordExplanationVersion(ctx, "Limits", query)
+
+	listed, err := svc.ListExplanationVersions(ctx, "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || listed[0].Version != 2 || listed[0].Explanation != "concurrent explanation" {
+		t.Fatalf("expected the explanation saved as version 2, got %+v", listed)
+	}
+	if !listed[0].Active || listed[1].Active {
+		t.Errorf("expected only version 2 active, got %+v", listed)
+	}
+}
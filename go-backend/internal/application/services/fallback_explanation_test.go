@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/fallback"
+	"go.uber.org/zap"
+)
+
+// unavailableLLM identifies concepts but cannot generate explanations
+type unavailableLLM struct {
+	recordingLLM
+}
+
+func (l *unavailableLLM) GenerateExplanation(ctx context.Context, req ExplanationRequest) (string, error) {
+	return "", errors.New("provider unavailable")
+}
+
+func TestProcessQueryServesFallbackWhenLLMFails(t *testing.T) {
+	renderer, err := fallback.NewRenderer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queryRepo := &savingQueryRepo{}
+	tasks := background.NewTasks()
+	svc := &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   queryRepo,
+		vectorRepo:  &stubVectorRepo{},
+		llmClient:   &unavailableLLM{recordingLLM{concepts: []string{"derivatives"}}},
+		fallback:    renderer,
+		sampler:     &analyticsSampler{sampleRate: 1},
+		tasks:       tasks,
+		logger:      zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a derivative?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if pending := tasks.Wait(ctx); len(pending) > 0 {
+		t.Fatalf("background tasks did not finish: %v", pending)
+	}
+
+	if !result.Fallback {
+		t.Error("expected result to be marked as fallback")
+	}
+	if !strings.Contains(result.Explanation, "calculus outline") {
+		t.Errorf("expected the calculus template, got:\n%s", result.Explanation)
+	}
+
+	if len(queryRepo.saved) != 1 {
+		t.Fatalf("expected the query to be saved once, got %d", len(queryRepo.saved))
+	}
+	saved := queryRepo.saved[0]
+	if saved.Success || !saved.Response.Fallback {
+		t.Errorf("fallback query should be saved as unsuccessful and marked fallback, got success=%v fallback=%v",
+			saved.Success, saved.Response.Fallback)
+	}
+}
+
+func TestProcessQueryFailsWithoutFallbackRenderer(t *testing.T) {
+	svc := &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   &savingQueryRepo{},
+		vectorRepo:  &stubVectorRepo{},
+		llmClient:   &unavailableLLM{recordingLLM{concepts: []string{"derivatives"}}},
+		sampler:     &analyticsSampler{sampleRate: 1},
+		tasks:       background.NewTasks(),
+		logger:      zap.NewNop(),
+	}
+
+	if _, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a derivative?"}); err == nil {
+		t.Fatal("expected an error when the LLM fails and no fallback is configured")
+	}
+}
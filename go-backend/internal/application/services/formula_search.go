@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/entities"
+)
+
+// SearchFormulas returns the formulas extracted from ingested sources that
+// cover a concept, given by name or ID
+func (s *queryService) SearchFormulas(ctx context.Context, concept string, limit int) ([]*entities.Formula, error) {
+	if s.formulaRepo == nil {
+		return nil, fmt.Errorf("formula storage not available")
+	}
+
+	key := entities.FormulaConceptKey(concept)
+	if key == "" {
+		return []*entities.Formula{}, nil
+	}
+
+	formulas, err := s.formulaRepo.FindByConcept(ctx, key, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search formulas: %w", err)
+	}
+	return formulas, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+)
+
+type keyFormulaRepo struct {
+	repositories.FormulaRepository
+	keys []string
+}
+
+func (r *keyFormulaRepo) FindByConcept(ctx context.Context, conceptKey string, limit int) ([]*entities.Formula, error) {
+	r.keys = append(r.keys, conceptKey)
+	return []*entities.Formula{{Expression: "∫u dv = uv - ∫v du"}}, nil
+}
+
+func TestSearchFormulasNormalizesConcept(t *testing.T) {
+	repo := &keyFormulaRepo{}
+	svc := &queryService{formulaRepo: repo}
+
+	for _, concept := range []string{"Integration by Parts", "integration_by_parts", " integration-by-parts "} {
+		formulas, err := svc.SearchFormulas(context.Background(), concept, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(formulas) != 1 {
+			t.Errorf("SearchFormulas(%q) returned %d formulas", concept, len(formulas))
+		}
+	}
+	for _, key := range repo.keys {
+		if key != "integration by parts" {
+			t.Errorf("looked up key %q, want %q", key, "integration by parts")
+		}
+	}
+}
+
+func TestSearchFormulasWithoutStorage(t *testing.T) {
+	svc := &queryService{}
+	if _, err := svc.SearchFormulas(context.Background(), "limits", 5); err == nil {
+		t.Error("expected an error when formula storage is not configured")
+	}
+}
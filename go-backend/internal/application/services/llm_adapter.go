@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/mathprereq/internal/core/llm"
+	"github.com/mathprereq/internal/domain/entities"
 )
 
 // LLMAdapter adapts the core LLM client to the service interface
@@ -25,6 +26,12 @@ func (a *LLMAdapter) GenerateExplanation(ctx context.Context, req ExplanationReq
 		Query:            req.Query,
 		PrerequisitePath: req.PrerequisitePath,
 		ContextChunks:    req.ContextChunks,
+		Language:         req.Language,
+		Profile:          req.Profile,
+		PromptVariant:    req.PromptVariant,
+		Model:            req.Model,
+		Level:            req.Level,
+		Style:            req.Style,
 	}
 	return a.client.GenerateExplanation(ctx, llmReq)
 }
@@ -45,6 +52,82 @@ func (a *LLMAdapter) AnalyzeNewConcept(ctx context.Context, conceptName string,
 		SuggestedCategory:   analysis.SuggestedCategory,
 		Reasoning:           analysis.Reasoning,
 		IsLikelyNewConcept:  analysis.IsLikelyNewConcept,
+		Confidence:          analysis.Confidence,
+	}, nil
+}
+
+func (a *LLMAdapter) SuggestPrerequisitePath(ctx context.Context, query string, concepts []string) ([]SuggestedPrerequisite, error) {
+	suggestions, err := a.client.SuggestPrerequisitePath(ctx, query, concepts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SuggestedPrerequisite, len(suggestions))
+	for i, suggestion := range suggestions {
+		result[i] = SuggestedPrerequisite(suggestion)
+	}
+	return result, nil
+}
+
+func (a *LLMAdapter) ExtractPlotFunctions(ctx context.Context, explanation string, maxPlots int) ([]PlotSpec, error) {
+	specs, err := a.client.ExtractPlotFunctions(ctx, explanation, maxPlots)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PlotSpec, len(specs))
+	for i, spec := range specs {
+		result[i] = PlotSpec(spec)
+	}
+	return result, nil
+}
+
+func (a *LLMAdapter) GenerateConceptProfile(ctx context.Context, conceptName string) (*entities.ConceptProfile, error) {
+	profile, err := a.client.GenerateConceptProfile(ctx, conceptName)
+	if err != nil {
+		return nil, err
+	}
+
+	examples := make([]entities.WorkedExample, len(profile.WorkedExamples))
+	for i, example := range profile.WorkedExamples {
+		examples[i] = entities.WorkedExample(example)
+	}
+
+	return &entities.ConceptProfile{
+		Definition:     profile.Definition,
+		KeyFormulas:    profile.KeyFormulas,
+		WorkedExamples: examples,
+		CommonMistakes: profile.CommonMistakes,
+		Applications:   profile.Applications,
+		Structured:     profile.Structured,
+		RawText:        profile.RawText,
+	}, nil
+}
+
+func (a *LLMAdapter) GenerateConceptDescription(ctx context.Context, conceptName string, contextChunks []string) (string, error) {
+	return a.client.GenerateConceptDescription(ctx, conceptName, contextChunks)
+}
+
+func (a *LLMAdapter) ExplainMistake(ctx context.Context, req MistakeRequest) (*entities.MistakeExplanation, error) {
+	explanation, err := a.client.ExplainMistake(ctx, llm.MistakeRequest{
+		Problem:       req.Problem,
+		Attempt:       req.Attempt,
+		Concepts:      req.Concepts,
+		ContextChunks: req.ContextChunks,
+		Language:      req.Language,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.MistakeExplanation{
+		ErrorStep:        explanation.ErrorStep,
+		ErrorType:        explanation.ErrorType,
+		WhatWentWrong:    explanation.WhatWentWrong,
+		CorrectApproach:  explanation.CorrectApproach,
+		ConceptsToReview: explanation.ConceptsToReview,
+		Structured:       explanation.Structured,
+		RawText:          explanation.RawText,
 	}, nil
 }
 
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// recordingLLM returns canned English concepts and answers in the requested language
+type recordingLLM struct {
+	LLMClient
+	concepts    []string
+	explanation map[string]string
+	requests    []ExplanationRequest
+}
+
+func (l *recordingLLM) IdentifyConcepts(ctx context.Context, query string) ([]string, error) {
+	return l.concepts, nil
+}
+
+func (l *recordingLLM) GenerateExplanation(ctx context.Context, req ExplanationRequest) (string, error) {
+	l.requests = append(l.requests, req)
+	return l.explanation[req.Language], nil
+}
+
+func (l *recordingLLM) Provider() string { return "stub" }
+func (l *recordingLLM) Model() string    { return "stub" }
+
+type pathConceptRepo struct {
+	repositories.ConceptRepository
+	mu     sync.Mutex
+	lookup [][]string
+}
+
+func (r *pathConceptRepo) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookup = append(r.lookup, targetConcepts)
+	return []types.Concept{{ID: "derivatives", Name: "Derivatives"}}, nil
+}
+
+func (r *pathConceptRepo) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return true, nil
+}
+
+func (r *pathConceptRepo) FindByName(ctx context.Context, name string) (*types.Concept, error) {
+	return nil, nil
+}
+
+type savingQueryRepo struct {
+	repositories.QueryRepository
+	mu    sync.Mutex
+	saved []*entities.Query
+}
+
+func (r *savingQueryRepo) Save(ctx context.Context, query *entities.Query) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = append(r.saved, query)
+	return nil
+}
+
+func TestProcessQueryLocalizesExplanationOnly(t *testing.T) {
+	llm := &recordingLLM{
+		concepts: []string{"derivatives", "trigonometric functions"},
+		explanation: map[string]string{
+			"Spanish": "La derivada de sen(x) es cos(x).",
+		},
+	}
+	conceptRepo := &pathConceptRepo{}
+	queryRepo := &savingQueryRepo{}
+	tasks := background.NewTasks()
+	svc := &queryService{
+		conceptRepo: conceptRepo,
+		queryRepo:   queryRepo,
+		vectorRepo:  &stubVectorRepo{},
+		llmClient:   llm,
+		sampler:     &analyticsSampler{sampleRate: 1},
+		tasks:       tasks,
+		logger:      zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{
+		Question: "¿Cómo calculo la derivada de sen(x)?",
+		Language: "ES",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if pending := tasks.Wait(ctx); len(pending) > 0 {
+		t.Fatalf("background tasks did not finish: %v", pending)
+	}
+
+	if result.Explanation != "La derivada de sen(x) es cos(x)." {
+		t.Errorf("expected Spanish explanation, got %q", result.Explanation)
+	}
+	if len(llm.requests) != 1 || llm.requests[0].Language != "Spanish" {
+		t.Errorf("expected explanation requested in Spanish, got %+v", llm.requests)
+	}
+	if len(conceptRepo.lookup) != 1 || !reflect.DeepEqual(conceptRepo.lookup[0], llm.concepts) {
+		t.Errorf("expected graph lookup with English concepts, got %v", conceptRepo.lookup)
+	}
+	if len(queryRepo.saved) != 1 || queryRepo.saved[0].Language != "es" {
+		t.Errorf("expected query stored with language es, got %+v", queryRepo.saved)
+	}
+}
+
+func TestProcessQueryRejectsUnsupportedLanguage(t *testing.T) {
+	svc := &queryService{logger: zap.NewNop()}
+	_, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{
+		Question: "What is a limit?",
+		Language: "xx",
+	})
+	if err == nil {
+		t.Fatal("expected error for unsupported language")
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// mistakeContextChunks is how much textbook context backs a mistake explanation
+const mistakeContextChunks = 5
+
+// ExplainMistake pinpoints where a student's attempt at a problem goes wrong.
+// Concepts are identified from the problem alone so a wrong attempt cannot
+// steer retrieval; the result is stored as a query of kind mistake.
+func (s *queryService) ExplainMistake(ctx context.Context, req *services.MistakeRequest) (*services.MistakeResult, error) {
+	startTime := time.Now()
+
+	language, ok := services.NormalizeLanguage(req.Language)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", services.ErrUnsupportedLanguage, req.Language)
+	}
+
+	query := entities.NewQuery(req.UserID, req.Problem, req.RequestID)
+	query.Kind = entities.QueryKindMistake
+	query.Attempt = req.Attempt
+	query.Language = language
+
+	s.logger.Info("Explaining mistake",
+		zap.String("query_id", query.ID),
+		zap.String("problem", req.Problem[:min(len(req.Problem), 100)]),
+		zap.String("language", language))
+
+	result, err := s.explainMistakePipeline(ctx, query)
+
+	query.MarkCompleted(err == nil, err)
+	stored := s.saveQueryAsync(ctx, query)
+
+	if err != nil {
+		s.logger.Error("Mistake explanation failed",
+			zap.String("query_id", query.ID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to explain mistake: %w", err)
+	}
+
+	result.RequestID = req.RequestID
+	result.Unsampled = !stored
+	result.ProcessingTime = time.Since(startTime)
+
+	s.logger.Info("Mistake explained successfully",
+		zap.String("query_id", query.ID),
+		zap.String("error_type", result.Explanation.ErrorType),
+		zap.Duration("processing_time", result.ProcessingTime))
+
+	return result, nil
+}
+
+func (s *queryService) explainMistakePipeline(ctx context.Context, query *entities.Query) (*services.MistakeResult, error) {
+	result := &services.MistakeResult{Query: query}
+
+	// Step 1: Identify the concepts the problem exercises
+	stepStart := time.Now()
+	conceptNames, err := s.llmClient.IdentifyConcepts(ctx, query.Text)
+	query.AddProcessingStep("identify_concepts", time.Since(stepStart), err == nil, err)
+	if err != nil {
+		return nil, fmt.Errorf("concept identification failed: %w", err)
+	}
+
+	query.IdentifiedConcepts = conceptNames
+	result.IdentifiedConcepts = conceptNames
+
+	// Step 2: Prerequisites only enrich the answer, so a lookup failure is tolerated
+	stepStart = time.Now()
+	prereqPath, err := s.conceptRepo.FindPrerequisitePath(ctx, conceptNames)
+	query.AddProcessingStep("find_prerequisites", time.Since(stepStart), err == nil, err)
+	if err != nil {
+		s.logger.Warn("Prerequisite lookup failed for mistake explanation", zap.Error(err))
+		prereqPath = []types.Concept{}
+	}
+
+	query.PrerequisitePath = prereqPath
+	result.PrerequisitePath = prereqPath
+
+	// Step 3: Vector search
+	stepStart = time.Now()
+	vectorResults, err := s.searchContext(ctx, query.Text, s.searchTerms(conceptNames), mistakeContextChunks, "")
+	query.AddProcessingStep("vector_search", time.Since(stepStart), err == nil, err)
+	if err != nil {
+		s.logger.Warn("Vector search failed", zap.Error(err))
+		vectorResults = []types.VectorResult{}
+	}
+
+	context := make([]string, len(vectorResults))
+	for i, vr := range vectorResults {
+		context[i] = vr.Content
+	}
+	result.RetrievedContext = context
+	chunks := entities.NewContextChunks(vectorResults)
+
+	// Step 4: Ask the LLM where the attempt goes wrong
+	stepStart = time.Now()
+	explanation, err := s.llmClient.ExplainMistake(ctx, MistakeRequest{
+		Problem:       query.Text,
+		Attempt:       query.Attempt,
+		Concepts:      conceptNames,
+		ContextChunks: context,
+		Language:      services.SupportedLanguages[query.Language],
+	})
+	query.AddProcessingStep("explain_mistake", time.Since(stepStart), err == nil, err)
+	if err != nil {
+		return nil, fmt.Errorf("mistake explanation failed: %w", err)
+	}
+
+	query.Response = entities.QueryResponse{
+		Explanation:      explanation.Text(),
+		RetrievedContext: context,
+		ContextChunks:    chunks,
+		LLMProvider:      s.llmClient.Provider(),
+		LLMModel:         s.llmClient.Model(),
+		Mistake:          explanation,
+	}
+	result.Explanation = explanation
+
+	return result, nil
+}
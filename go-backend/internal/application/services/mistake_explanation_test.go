@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// mistakeLLM records what concepts were identified from and explains a canned mistake
+type mistakeLLM struct {
+	LLMClient
+	identifiedFrom []string
+	requests       []MistakeRequest
+	explanation    *entities.MistakeExplanation
+	err            error
+}
+
+func (l *mistakeLLM) IdentifyConcepts(ctx context.Context, query string) ([]string, error) {
+	l.identifiedFrom = append(l.identifiedFrom, query)
+	return []string{"Linear Equations"}, nil
+}
+
+func (l *mistakeLLM) ExplainMistake(ctx context.Context, req MistakeRequest) (*entities.MistakeExplanation, error) {
+	l.requests = append(l.requests, req)
+	return l.explanation, l.err
+}
+
+func (l *mistakeLLM) Provider() string { return "stub" }
+func (l *mistakeLLM) Model() string    { return "stub" }
+
+func newMistakeService(llm *mistakeLLM, queryRepo *savingQueryRepo, tasks *background.Tasks) *queryService {
+	return &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   queryRepo,
+		vectorRepo: &stubVectorRepo{results: map[string][]types.VectorResult{
+			"Solve 2x + 3 = 7": {{Content: "To isolate x, undo addition by subtracting."}},
+		}},
+		llmClient: llm,
+		sampler:   &analyticsSampler{sampleRate: 1},
+		tasks:     tasks,
+		logger:    zap.NewNop(),
+	}
+}
+
+func TestExplainMistakeIdentifiesConceptsFromProblem(t *testing.T) {
+	llm := &mistakeLLM{explanation: &entities.MistakeExplanation{
+		ErrorStep:        "2x = 7 + 3 = 10",
+		ErrorType:        "arithmetic",
+		WhatWentWrong:    "You added 3 instead of subtracting it.",
+		CorrectApproach:  "2x = 4, so x = 2.",
+		ConceptsToReview: []string{"Linear Equations"},
+		Structured:       true,
+	}}
+	queryRepo := &savingQueryRepo{}
+	tasks := background.NewTasks()
+	svc := newMistakeService(llm, queryRepo, tasks)
+
+	result, err := svc.ExplainMistake(context.Background(), &services.MistakeRequest{
+		Problem: "Solve 2x + 3 = 7",
+		Attempt: "2x = 7 + 3 = 10, so x = 5",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if len(llm.identifiedFrom) != 1 || llm.identifiedFrom[0] != "Solve 2x + 3 = 7" {
+		t.Errorf("expected concepts identified from the problem only, got %q", llm.identifiedFrom)
+	}
+	if len(llm.requests) != 1 {
+		t.Fatalf("expected one mistake explanation request, got %d", len(llm.requests))
+	}
+	req := llm.requests[0]
+	if req.Attempt != "2x = 7 + 3 = 10, so x = 5" || len(req.ContextChunks) != 1 || req.Language != "English" {
+		t.Errorf("unexpected explanation request %+v", req)
+	}
+
+	if result.Explanation.ErrorType != "arithmetic" || result.Explanation.ErrorStep != "2x = 7 + 3 = 10" {
+		t.Errorf("expected structured explanation, got %+v", result.Explanation)
+	}
+	if len(result.PrerequisitePath) != 1 {
+		t.Errorf("expected prerequisite path, got %+v", result.PrerequisitePath)
+	}
+
+	if len(queryRepo.saved) != 1 {
+		t.Fatalf("expected the mistake stored as a query, got %d", len(queryRepo.saved))
+	}
+	saved := queryRepo.saved[0]
+	if saved.Kind != entities.QueryKindMistake || saved.Attempt != req.Attempt || !saved.Success {
+		t.Errorf("unexpected stored query %+v", saved)
+	}
+	if saved.Response.Mistake != result.Explanation || saved.Response.Explanation == "" {
+		t.Errorf("expected stored response to carry the explanation, got %+v", saved.Response)
+	}
+}
+
+func TestExplainMistakeStoresFailures(t *testing.T) {
+	llm := &mistakeLLM{err: errors.New("quota exceeded")}
+	queryRepo := &savingQueryRepo{}
+	tasks := background.NewTasks()
+	svc := newMistakeService(llm, queryRepo, tasks)
+
+	_, err := svc.ExplainMistake(context.Background(), &services.MistakeRequest{
+		Problem: "Solve 2x + 3 = 7",
+		Attempt: "x = 5",
+	})
+	if err == nil {
+		t.Fatal("expected error when the LLM fails")
+	}
+	drainTasks(t, tasks)
+
+	if len(queryRepo.saved) != 1 || queryRepo.saved[0].Success {
+		t.Errorf("expected a failed query to be stored, got %+v", queryRepo.saved)
+	}
+}
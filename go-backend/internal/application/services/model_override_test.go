@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/services"
+)
+
+func newOverrideService(t *testing.T, tasks *background.Tasks) (*queryService, *cachingQueryRepo) {
+	t.Helper()
+	repo := &cachingQueryRepo{}
+	svc := newSmartQueryService(repo, tasks)
+	svc.overrideModels = newOverrideModels(config.LLMConfig{
+		Model:    "gemini-2.5-flash",
+		Override: config.ModelOverrideConfig{Token: "secret"},
+		Experiment: config.ExperimentConfig{
+			Models: map[string]string{"pro": "gemini-2.5-pro"},
+		},
+	})
+	svc.experiment = newQueryExperiment(config.ExperimentConfig{Name: "models", Split: map[string]int{"control": 1}})
+	return svc, repo
+}
+
+func TestProcessQueryHonorsAllowedModelOverride(t *testing.T) {
+	tasks := background.NewTasks()
+	svc, repo := newOverrideService(t, tasks)
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{
+		Question:      "What is a limit?",
+		ModelOverride: "gemini-2.5-pro",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	llm := svc.llmClient.(*recordingLLM)
+	if len(llm.requests) != 1 || llm.requests[0].Model != "gemini-2.5-pro" {
+		t.Fatalf("expected the explanation generated with the override, got %+v", llm.requests)
+	}
+	if len(repo.saved) != 1 {
+		t.Fatalf("expected the query stored, got %d", len(repo.saved))
+	}
+	stored := repo.saved[0]
+	if stored.ModelOverride != "gemini-2.5-pro" || stored.Response.LLMModel != "gemini-2.5-pro" {
+		t.Errorf("expected the override recorded, got override %q and model %q", stored.ModelOverride, stored.Response.LLMModel)
+	}
+	if stored.Experiment != nil || result.Query.Experiment != nil {
+		t.Errorf("expected an overridden query kept out of the experiment, got %+v", stored.Experiment)
+	}
+}
+
+func TestProcessQueryRejectsDisallowedModelOverride(t *testing.T) {
+	tasks := background.NewTasks()
+	svc, repo := newOverrideService(t, tasks)
+
+	_, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{
+		Question:      "What is a limit?",
+		ModelOverride: "some-unvetted-model",
+	})
+	drainTasks(t, tasks)
+
+	if !errors.Is(err, services.ErrModelOverrideNotAllowed) {
+		t.Fatalf("expected ErrModelOverrideNotAllowed, got %v", err)
+	}
+	if llm := svc.llmClient.(*recordingLLM); len(llm.requests) != 0 {
+		t.Error("expected no explanation generated for a rejected override")
+	}
+	if len(repo.saved) != 0 {
+		t.Error("expected a rejected override not stored")
+	}
+}
+
+func TestModelOverridesDisabledWithoutToken(t *testing.T) {
+	tasks := background.NewTasks()
+	svc := newSmartQueryService(&cachingQueryRepo{}, tasks)
+	svc.overrideModels = newOverrideModels(config.LLMConfig{
+		Model:    "gemini-2.5-flash",
+		Override: config.ModelOverrideConfig{Models: []string{"gemini-2.5-pro"}},
+	})
+
+	_, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{
+		Question:      "What is a limit?",
+		ModelOverride: "gemini-2.5-pro",
+	})
+	drainTasks(t, tasks)
+	if !errors.Is(err, services.ErrModelOverrideNotAllowed) {
+		t.Errorf("expected overrides refused without a token configured, got %v", err)
+	}
+}
+
+func TestOverrideModelsDefaultToConfiguredModels(t *testing.T) {
+	cfg := config.LLMConfig{
+		Model:       "gemini-2.5-flash",
+		DeepProfile: config.ModelProfile{Model: "gemini-2.5-pro"},
+		Experiment:  config.ExperimentConfig{Models: map[string]string{"b": "gemini-2.5-flash"}},
+	}
+	got := cfg.OverrideModels()
+	if len(got) != 2 || got[0] != "gemini-2.5-flash" || got[1] != "gemini-2.5-pro" {
+		t.Errorf("expected the configured models, got %v", got)
+	}
+
+	cfg.Override.Models = []string{" gemini-1.5-pro ", ""}
+	if got := cfg.OverrideModels(); len(got) != 1 || got[0] != "gemini-1.5-pro" {
+		t.Errorf("expected the explicit allowlist to replace them, got %v", got)
+	}
+}
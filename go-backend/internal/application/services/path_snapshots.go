@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// CapturePathSnapshot stores the current prerequisite path of a concept as a baseline
+func (s *queryService) CapturePathSnapshot(ctx context.Context, conceptID, label string) (*entities.PathSnapshot, error) {
+	if s.pathSnapshotRepo == nil {
+		return nil, fmt.Errorf("path snapshot storage not available")
+	}
+
+	snapshot, err := s.currentPathSnapshot(ctx, conceptID, label)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pathSnapshotRepo.Save(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to save path snapshot: %w", err)
+	}
+
+	s.logger.Info("Captured prerequisite path snapshot",
+		zap.String("concept_id", conceptID),
+		zap.String("snapshot_id", snapshot.ID),
+		zap.Int("prerequisites", len(snapshot.Prerequisites)))
+
+	return snapshot, nil
+}
+
+// DiffConceptPath compares the live prerequisite path with a baseline snapshot.
+// When baselineID is empty the concept's latest snapshot is used.
+func (s *queryService) DiffConceptPath(ctx context.Context, conceptID, baselineID string) (*entities.PathDiff, error) {
+	if s.pathSnapshotRepo == nil {
+		return nil, fmt.Errorf("path snapshot storage not available")
+	}
+
+	var baseline *entities.PathSnapshot
+	var err error
+	if baselineID != "" {
+		baseline, err = s.pathSnapshotRepo.FindByID(ctx, baselineID)
+	} else {
+		baseline, err = s.pathSnapshotRepo.FindLatestByConcept(ctx, conceptID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline snapshot: %w", err)
+	}
+	if baseline == nil {
+		return nil, fmt.Errorf("%w for concept %s", services.ErrBaselineSnapshotNotFound, conceptID)
+	}
+	if baseline.ConceptID != conceptID {
+		return nil, fmt.Errorf("%w: snapshot %s belongs to concept %s", services.ErrBaselineSnapshotNotFound, baseline.ID, baseline.ConceptID)
+	}
+
+	current, err := s.currentPathSnapshot(ctx, conceptID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.DiffPathSnapshots(baseline, current), nil
+}
+
+// currentPathSnapshot builds an unsaved snapshot from the live knowledge graph
+func (s *queryService) currentPathSnapshot(ctx context.Context, conceptID, label string) (*entities.PathSnapshot, error) {
+	path, err := s.conceptRepo.FindPrerequisitePath(ctx, []string{conceptID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prerequisite path: %w", err)
+	}
+
+	prerequisites := []entities.SnapshotConcept{}
+	for _, concept := range path {
+		if concept.Type == "target" {
+			continue
+		}
+		prerequisites = append(prerequisites, entities.SnapshotConcept{ID: concept.ID, Name: concept.Name})
+	}
+
+	return entities.NewPathSnapshot(conceptID, label, prerequisites), nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// SuggestedPrerequisiteType marks concepts of an LLM-suggested path that
+// come before its targets; they have no graph ID
+const SuggestedPrerequisiteType = "suggested_prerequisite"
+
+// suggestPrerequisitePath asks the LLM to order prerequisites for concepts
+// the graph has no path for, ending the path with the concepts themselves.
+// It returns nil when suggestions are off or the LLM fails; a suggestion is
+// never worth failing the query over.
+func (s *queryService) suggestPrerequisitePath(ctx context.Context, query *entities.Query, conceptNames []string) []types.Concept {
+	if !s.pathSuggestions || len(conceptNames) == 0 {
+		return nil
+	}
+
+	stepStart := time.Now()
+	suggestions, err := s.llmClient.SuggestPrerequisitePath(ctx, query.Text, conceptNames)
+	query.AddProcessingStep("suggest_prerequisites", time.Since(stepStart), err == nil, err)
+	if err != nil {
+		s.logger.Warn("Failed to suggest a prerequisite path",
+			zap.String("query_id", query.ID),
+			zap.Strings("concepts", conceptNames),
+			zap.Error(err))
+		return nil
+	}
+
+	path := make([]types.Concept, 0, len(suggestions)+len(conceptNames))
+	for _, suggestion := range suggestions {
+		path = append(path, types.Concept{
+			Name:        suggestion.Name,
+			Description: suggestion.Description,
+			Type:        SuggestedPrerequisiteType,
+		})
+	}
+	for _, name := range conceptNames {
+		path = append(path, types.Concept{Name: name, Type: "target"})
+	}
+	return path
+}
+
+// suggestedPrerequisiteNames returns the prerequisites of a suggested path
+func suggestedPrerequisiteNames(path []types.Concept) []string {
+	var names []string
+	for _, concept := range path {
+		if concept.Type == SuggestedPrerequisiteType {
+			names = append(names, concept.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// suggestingLLM suggests a fixed prerequisite path and stages every concept
+type suggestingLLM struct {
+	recordingLLM
+	suggested [][]string
+}
+
+func (l *suggestingLLM) SuggestPrerequisitePath(ctx context.Context, query string, concepts []string) ([]SuggestedPrerequisite, error) {
+	l.suggested = append(l.suggested, concepts)
+	return []SuggestedPrerequisite{
+		{Name: "Partial Derivatives", Description: "Rates of change along one variable"},
+		{Name: "Matrices"},
+	}, nil
+}
+
+func (l *suggestingLLM) AnalyzeNewConcept(ctx context.Context, conceptName string, queryContext string) (*NewConceptAnalysis, error) {
+	return &NewConceptAnalysis{ConceptName: conceptName, IsLikelyNewConcept: true, Confidence: 0.8}, nil
+}
+
+// uncuratedConceptRepo has neither the concepts nor a path to them
+type uncuratedConceptRepo struct {
+	repositories.ConceptRepository
+}
+
+func (r *uncuratedConceptRepo) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
+	return nil, nil
+}
+
+func (r *uncuratedConceptRepo) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return false, nil
+}
+
+// savingStagedConceptRepo keeps staged concepts in memory
+type savingStagedConceptRepo struct {
+	*memoryStagedConceptRepo
+}
+
+func (r savingStagedConceptRepo) Save(ctx context.Context, concept *entities.StagedConcept) error {
+	r.concepts = append(r.concepts, concept)
+	return nil
+}
+
+func (r savingStagedConceptRepo) Update(ctx context.Context, concept *entities.StagedConcept) error {
+	return nil
+}
+
+func newPathSuggestionService(llm LLMClient, conceptRepo repositories.ConceptRepository, staged *memoryStagedConceptRepo, tasks *background.Tasks) *queryService {
+	return &queryService{
+		conceptRepo:       conceptRepo,
+		queryRepo:         &savingQueryRepo{},
+		vectorRepo:        &stubVectorRepo{},
+		stagedConceptRepo: savingStagedConceptRepo{staged},
+		llmClient:         llm,
+		sampler:           &analyticsSampler{sampleRate: 1},
+		pathSuggestions:   true,
+		tasks:             tasks,
+		logger:            zap.NewNop(),
+	}
+}
+
+func TestProcessQuerySuggestsPathWhenGraphHasNone(t *testing.T) {
+	llm := &suggestingLLM{recordingLLM: recordingLLM{
+		concepts:    []string{"Jacobian"},
+		explanation: map[string]string{"English": "The Jacobian collects partial derivatives."},
+	}}
+	staged := &memoryStagedConceptRepo{}
+	tasks := background.NewTasks()
+	svc := newPathSuggestionService(llm, &uncuratedConceptRepo{}, staged, tasks)
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a Jacobian?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if len(result.PrerequisitePath) != 0 {
+		t.Errorf("expected the curated path to stay empty, got %+v", result.PrerequisitePath)
+	}
+	if result.Status != services.QueryStatusConceptsNotFound {
+		t.Errorf("Status = %q, want %q", result.Status, services.QueryStatusConceptsNotFound)
+	}
+
+	want := []types.Concept{
+		{Name: "Partial Derivatives", Description: "Rates of change along one variable", Type: SuggestedPrerequisiteType},
+		{Name: "Matrices", Type: SuggestedPrerequisiteType},
+		{Name: "Jacobian", Type: "target"},
+	}
+	if !reflect.DeepEqual(result.SuggestedPath, want) {
+		t.Errorf("SuggestedPath = %+v, want %+v", result.SuggestedPath, want)
+	}
+	if !reflect.DeepEqual(result.Query.SuggestedPath, want) {
+		t.Error("expected the suggested path stored with the query")
+	}
+	if len(llm.requests) != 1 || !reflect.DeepEqual(llm.requests[0].PrerequisitePath, want) {
+		t.Errorf("expected the explanation guided by the suggested path, got %+v", llm.requests)
+	}
+
+	if len(staged.concepts) != 1 {
+		t.Fatalf("expected the concept staged, got %d", len(staged.concepts))
+	}
+	if got := staged.concepts[0].PathSuggestedPrerequisites; !reflect.DeepEqual(got, []string{"Partial Derivatives", "Matrices"}) {
+		t.Errorf("expected the suggested prerequisites staged as candidate edges, got %v", got)
+	}
+}
+
+func TestProcessQueryAddsSuggestionsToStagedConcept(t *testing.T) {
+	llm := &suggestingLLM{recordingLLM: recordingLLM{
+		concepts:    []string{"Jacobian"},
+		explanation: map[string]string{"English": "The Jacobian collects partial derivatives."},
+	}}
+	existing := entities.NewStagedConcept("jacobian", "", "q1", "", "", nil, 6, "calculus", "")
+	existing.PathSuggestedPrerequisites = []string{"matrices"}
+	staged := &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{existing}}
+	tasks := background.NewTasks()
+	svc := newPathSuggestionService(llm, &uncuratedConceptRepo{}, staged, tasks)
+
+	if _, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a Jacobian?"}); err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if got := existing.PathSuggestedPrerequisites; !reflect.DeepEqual(got, []string{"matrices", "Partial Derivatives"}) {
+		t.Errorf("expected the new suggestion added once, got %v", got)
+	}
+	if existing.OccurrenceCount != 2 {
+		t.Errorf("expected the occurrence counted, got %d", existing.OccurrenceCount)
+	}
+}
+
+func TestProcessQueryDoesNotSuggestPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		conceptRepo repositories.ConceptRepository
+		enabled     bool
+	}{
+		{"curated path found", &pathConceptRepo{}, true},
+		{"suggestions disabled", &uncuratedConceptRepo{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &suggestingLLM{recordingLLM: recordingLLM{
+				concepts:    []string{"derivatives"},
+				explanation: map[string]string{"English": "A derivative measures change."},
+			}}
+			tasks := background.NewTasks()
+			svc := newPathSuggestionService(llm, tt.conceptRepo, &memoryStagedConceptRepo{}, tasks)
+			svc.pathSuggestions = tt.enabled
+
+			result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a derivative?"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			drainTasks(t, tasks)
+
+			if len(llm.suggested) != 0 || result.SuggestedPath != nil {
+				t.Errorf("expected no suggested path, got %+v", result.SuggestedPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+)
+
+// GetPerformanceStats reports p50/p95/p99 durations of each pipeline step,
+// and of each data source across its steps, for queries in the last window
+func (s *queryService) GetPerformanceStats(ctx context.Context, window time.Duration) (*repositories.PerformanceStats, error) {
+	until := time.Now()
+	since := until.Add(-window)
+
+	steps, err := s.queryRepo.GetStepDurations(ctx, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load step timings: %w", err)
+	}
+
+	stats := &repositories.PerformanceStats{
+		Since:   since,
+		Until:   until,
+		Steps:   []repositories.TimingPercentiles{},
+		Sources: []repositories.TimingPercentiles{},
+	}
+
+	bySource := map[string][]time.Duration{}
+	for _, step := range steps {
+		if len(step.Durations) == 0 {
+			continue
+		}
+		source := entities.StepSource(step.Step)
+		bySource[source] = append(bySource[source], step.Durations...)
+
+		timing := timingPercentiles(step.Step, step.Durations)
+		timing.Source = source
+		stats.Steps = append(stats.Steps, timing)
+	}
+	for source, durations := range bySource {
+		slices.Sort(durations)
+		stats.Sources = append(stats.Sources, timingPercentiles(source, durations))
+	}
+
+	sortSlowestFirst(stats.Steps)
+	sortSlowestFirst(stats.Sources)
+	return stats, nil
+}
+
+// timingPercentiles summarizes durations, which must be sorted ascending
+func timingPercentiles(name string, sorted []time.Duration) repositories.TimingPercentiles {
+	return repositories.TimingPercentiles{
+		Name:  name,
+		Count: len(sorted),
+		P50Ms: milliseconds(percentile(sorted, 50)),
+		P95Ms: milliseconds(percentile(sorted, 95)),
+		P99Ms: milliseconds(percentile(sorted, 99)),
+		MaxMs: milliseconds(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile returns the nearest-rank pth percentile of ascending durations:
+// the smallest value at least p percent of the samples do not exceed
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func sortSlowestFirst(timings []repositories.TimingPercentiles) {
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].P95Ms != timings[j].P95Ms {
+			return timings[i].P95Ms > timings[j].P95Ms
+		}
+		return timings[i].Name < timings[j].Name
+	})
+}
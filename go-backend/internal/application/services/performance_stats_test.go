@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+type timingQueryRepo struct {
+	repositories.QueryRepository
+	steps        []repositories.StepDurations
+	since, until time.Time
+}
+
+func (r *timingQueryRepo) GetStepDurations(ctx context.Context, since, until time.Time) ([]repositories.StepDurations, error) {
+	r.since, r.until = since, until
+	return r.steps, nil
+}
+
+// millisRange returns 1ms..n ms, ascending
+func millisRange(n int) []time.Duration {
+	durations := make([]time.Duration, n)
+	for i := range durations {
+		durations[i] = time.Duration(i+1) * time.Millisecond
+	}
+	return durations
+}
+
+func TestPercentileNearestRank(t *testing.T) {
+	hundred := millisRange(100)
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{"median of 100", hundred, 50, 50 * time.Millisecond},
+		{"p95 of 100", hundred, 95, 95 * time.Millisecond},
+		{"p99 of 100", hundred, 99, 99 * time.Millisecond},
+		{"p99 of 10 is the max", millisRange(10), 99, 10 * time.Millisecond},
+		{"median of 5", millisRange(5), 50, 3 * time.Millisecond},
+		{"single sample", millisRange(1), 50, time.Millisecond},
+		{"no samples", nil, 95, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPerformanceStats(t *testing.T) {
+	// Neo4j is usually fast but one call in ten takes seconds; the two LLM
+	// steps share a source
+	slowTail := make([]time.Duration, 10)
+	for i := range slowTail {
+		slowTail[i] = time.Duration(i+1) * time.Second
+	}
+	repo := &timingQueryRepo{steps: []repositories.StepDurations{
+		{Step: "find_prerequisites", Durations: append(millisRange(90), slowTail...)},
+		{Step: "generate_explanation", Durations: []time.Duration{800 * time.Millisecond, 900 * time.Millisecond, time.Second}},
+		{Step: "identify_concepts", Durations: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}},
+		{Step: "vector_search", Durations: nil},
+	}}
+	svc := &queryService{queryRepo: repo, logger: zap.NewNop()}
+
+	stats, err := svc.GetPerformanceStats(context.Background(), 6*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if window := repo.until.Sub(repo.since); window != 6*time.Hour {
+		t.Errorf("queried a %v window, want 6h", window)
+	}
+
+	if len(stats.Steps) != 3 {
+		t.Fatalf("expected steps without samples to be left out, got %+v", stats.Steps)
+	}
+	neo4j := stats.Steps[0]
+	if neo4j.Name != "find_prerequisites" || neo4j.Source != "neo4j" {
+		t.Fatalf("expected the slow Neo4j step first, got %+v", stats.Steps)
+	}
+	if neo4j.Count != 100 || neo4j.P50Ms != 50 || neo4j.P95Ms != 5000 || neo4j.P99Ms != 9000 || neo4j.MaxMs != 10000 {
+		t.Errorf("unexpected Neo4j percentiles %+v", neo4j)
+	}
+
+	var llm *repositories.TimingPercentiles
+	for i := range stats.Sources {
+		if stats.Sources[i].Name == "llm" {
+			llm = &stats.Sources[i]
+		}
+	}
+	if llm == nil {
+		t.Fatalf("expected an llm source, got %+v", stats.Sources)
+	}
+	// 100, 200, 800, 900, 1000 ms merged across both LLM steps
+	if llm.Count != 5 || llm.P50Ms != 800 || llm.P99Ms != 1000 {
+		t.Errorf("unexpected LLM source percentiles %+v", llm)
+	}
+	if len(stats.Sources) != 2 {
+		t.Errorf("expected llm and neo4j sources, got %+v", stats.Sources)
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// RefreshPrerequisiteClosures recomputes every concept's transitive
+// prerequisite set from the graph's edges and stores them, returning how many
+// concepts were stored
+func (s *queryService) RefreshPrerequisiteClosures(ctx context.Context) (int, error) {
+	if s.closureRepo == nil {
+		return 0, fmt.Errorf("prerequisite closure storage not available")
+	}
+
+	concepts, err := s.conceptRepo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load concepts: %w", err)
+	}
+	edges, err := s.conceptRepo.GetPrerequisiteEdges(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load prerequisite edges: %w", err)
+	}
+
+	conceptIDs := make([]string, len(concepts))
+	for i, concept := range concepts {
+		conceptIDs[i] = concept.ID
+	}
+
+	computedAt := time.Now()
+	sets := computePrerequisiteClosures(conceptIDs, edges)
+	closures := make([]*entities.PrerequisiteClosure, 0, len(sets))
+	for conceptID, prerequisiteIDs := range sets {
+		closures = append(closures, &entities.PrerequisiteClosure{
+			ConceptID:       conceptID,
+			PrerequisiteIDs: prerequisiteIDs,
+			ComputedAt:      computedAt,
+		})
+	}
+
+	if err := s.closureRepo.ReplaceAll(ctx, closures, computedAt); err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("Prerequisite closures refreshed",
+		zap.Int("concepts", len(closures)),
+		zap.Int("edges", len(edges)),
+		zap.Duration("duration", time.Since(computedAt)))
+
+	return len(closures), nil
+}
+
+// invalidatePrerequisiteClosures recomputes the stored closures in the
+// background after the graph's edges changed. Until it finishes, readiness
+// checks still see the previous sets.
+func (s *queryService) invalidatePrerequisiteClosures() {
+	if s.closureRepo == nil {
+		return
+	}
+	s.tasks.Go("refresh_prerequisite_closures", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		if _, err := s.RefreshPrerequisiteClosures(ctx); err != nil {
+			s.logger.Error("Failed to refresh prerequisite closures", zap.Error(err))
+		}
+	})
+}
+
+// CheckReadiness reports which prerequisites of a concept, at any depth, are
+// missing from the concepts a learner already knows
+func (s *queryService) CheckReadiness(ctx context.Context, conceptID string, knownConceptIDs []string) (*services.ReadinessResult, error) {
+	prerequisiteIDs, cached, err := s.prerequisiteClosure(ctx, conceptID)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(knownConceptIDs))
+	for _, id := range knownConceptIDs {
+		known[id] = true
+	}
+
+	missing := []string{}
+	for _, id := range prerequisiteIDs {
+		if !known[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	return &services.ReadinessResult{
+		ConceptID:     conceptID,
+		Ready:         len(missing) == 0,
+		Prerequisites: len(prerequisiteIDs),
+		Missing:       missing,
+		Precomputed:   cached,
+	}, nil
+}
+
+// prerequisiteClosure returns a concept's stored prerequisite set, falling
+// back to a live path traversal when none has been computed for it yet
+func (s *queryService) prerequisiteClosure(ctx context.Context, conceptID string) ([]string, bool, error) {
+	if s.closureRepo != nil {
+		closure, err := s.closureRepo.FindByConceptID(ctx, conceptID)
+		if err != nil {
+			s.logger.Warn("Failed to load prerequisite closure, traversing the graph",
+				zap.String("concept_id", conceptID),
+				zap.Error(err))
+		} else if closure != nil {
+			return closure.PrerequisiteIDs, true, nil
+		}
+	}
+
+	path, err := s.conceptRepo.FindPrerequisitePath(ctx, []string{conceptID})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find prerequisite path: %w", err)
+	}
+	if len(path) == 0 {
+		// A concept without prerequisites has an empty path too
+		concept, err := s.conceptRepo.FindByID(ctx, conceptID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to find concept: %w", err)
+		}
+		if concept == nil {
+			return nil, false, fmt.Errorf("%w: %s", services.ErrConceptNotFound, conceptID)
+		}
+	}
+
+	prerequisiteIDs := []string{}
+	for _, concept := range path {
+		if concept.Type == "prerequisite" {
+			prerequisiteIDs = append(prerequisiteIDs, concept.ID)
+		}
+	}
+	sort.Strings(prerequisiteIDs)
+	return prerequisiteIDs, false, nil
+}
+
+// computePrerequisiteClosures returns, for every concept, the sorted IDs of
+// all concepts with a prerequisite path to it. Each concept is walked
+// upstream independently, so cycles in the graph are harmless.
+func computePrerequisiteClosures(conceptIDs []string, edges []types.NeighborhoodEdge) map[string][]string {
+	upstream := make(map[string][]string)
+	for _, edge := range edges {
+		upstream[edge.To] = append(upstream[edge.To], edge.From)
+	}
+
+	closures := make(map[string][]string, len(conceptIDs))
+	for _, conceptID := range conceptIDs {
+		seen := map[string]bool{conceptID: true}
+		stack := []string{conceptID}
+		prerequisiteIDs := []string{}
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, prereqID := range upstream[current] {
+				if seen[prereqID] {
+					continue
+				}
+				seen[prereqID] = true
+				prerequisiteIDs = append(prerequisiteIDs, prereqID)
+				stack = append(stack, prereqID)
+			}
+		}
+		sort.Strings(prerequisiteIDs)
+		closures[conceptID] = prerequisiteIDs
+	}
+	return closures
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// graphConceptRepo is a prerequisite graph whose path lookup walks the edges
+// live, the way the Cypher traversal does: a concept without prerequisites
+// has an empty path, like an unknown one
+type graphConceptRepo struct {
+	repositories.ConceptRepository
+	mu    sync.Mutex
+	ids   []string
+	edges []types.NeighborhoodEdge
+}
+
+func (r *graphConceptRepo) addEdge(from, to string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.edges = append(r.edges, types.NeighborhoodEdge{From: from, To: to, Type: "PREREQUISITE_FOR"})
+}
+
+func (r *graphConceptRepo) FindByID(ctx context.Context, id string) (*types.Concept, error) {
+	if !slices.Contains(r.ids, id) {
+		return nil, nil
+	}
+	return &types.Concept{ID: id, Name: id}, nil
+}
+
+func (r *graphConceptRepo) GetAll(ctx context.Context) ([]types.Concept, error) {
+	concepts := make([]types.Concept, len(r.ids))
+	for i, id := range r.ids {
+		concepts[i] = types.Concept{ID: id, Name: id}
+	}
+	return concepts, nil
+}
+
+func (r *graphConceptRepo) GetPrerequisiteEdges(ctx context.Context) ([]types.NeighborhoodEdge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.edges), nil
+}
+
+func (r *graphConceptRepo) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
+	target := targetConcepts[0]
+	if !slices.Contains(r.ids, target) {
+		return nil, nil
+	}
+
+	found := map[string]bool{}
+	var walk func(id string)
+	walk = func(id string) {
+		for _, edge := range r.edges {
+			if edge.To == id && !found[edge.From] {
+				found[edge.From] = true
+				walk(edge.From)
+			}
+		}
+	}
+	walk(target)
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	path := []types.Concept{{ID: target, Type: "target"}}
+	for id := range found {
+		if id != target {
+			path = append(path, types.Concept{ID: id, Type: "prerequisite"})
+		}
+	}
+	return path, nil
+}
+
+type memClosureRepo struct {
+	mu       sync.Mutex
+	closures map[string]*entities.PrerequisiteClosure
+}
+
+func (r *memClosureRepo) ReplaceAll(ctx context.Context, closures []*entities.PrerequisiteClosure, computedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closures = make(map[string]*entities.PrerequisiteClosure, len(closures))
+	for _, closure := range closures {
+		r.closures[closure.ConceptID] = closure
+	}
+	return nil
+}
+
+func (r *memClosureRepo) FindByConceptID(ctx context.Context, conceptID string) (*entities.PrerequisiteClosure, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closures[conceptID], nil
+}
+
+// newClosureGraph builds functions -> limits -> derivatives -> integrals with
+// a shortcut from functions to integrals, plus a two-concept cycle
+func newClosureGraph() *graphConceptRepo {
+	graph := &graphConceptRepo{ids: []string{"functions", "limits", "derivatives", "integrals", "sets", "loop_a", "loop_b"}}
+	graph.addEdge("functions", "limits")
+	graph.addEdge("limits", "derivatives")
+	graph.addEdge("derivatives", "integrals")
+	graph.addEdge("functions", "integrals")
+	graph.addEdge("sets", "functions")
+	graph.addEdge("loop_a", "loop_b")
+	graph.addEdge("loop_b", "loop_a")
+	return graph
+}
+
+func TestPrerequisiteClosuresMatchLiveTraversal(t *testing.T) {
+	graph := newClosureGraph()
+	precomputed := &queryService{conceptRepo: graph, closureRepo: &memClosureRepo{}, logger: zap.NewNop()}
+	live := &queryService{conceptRepo: graph, logger: zap.NewNop()}
+
+	count, err := precomputed.RefreshPrerequisiteClosures(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != len(graph.ids) {
+		t.Errorf("stored %d closures, want %d", count, len(graph.ids))
+	}
+
+	for _, id := range graph.ids {
+		want, err := live.CheckReadiness(context.Background(), id, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := precomputed.CheckReadiness(context.Background(), id, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !got.Precomputed || want.Precomputed {
+			t.Errorf("%s: precomputed = %v/%v, want true/false", id, got.Precomputed, want.Precomputed)
+		}
+		if !slices.Equal(got.Missing, want.Missing) {
+			t.Errorf("%s: precomputed prerequisites %v, live traversal %v", id, got.Missing, want.Missing)
+		}
+	}
+}
+
+func TestCheckReadinessReportsMissingPrerequisites(t *testing.T) {
+	svc := &queryService{conceptRepo: newClosureGraph(), closureRepo: &memClosureRepo{}, logger: zap.NewNop()}
+	if _, err := svc.RefreshPrerequisiteClosures(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.CheckReadiness(context.Background(), "integrals", []string{"sets", "limits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Ready || result.Prerequisites != 4 || !slices.Equal(result.Missing, []string{"derivatives", "functions"}) {
+		t.Errorf("readiness = %+v, want 4 prerequisites with derivatives and functions missing", result)
+	}
+
+	result, err = svc.CheckReadiness(context.Background(), "limits", []string{"sets", "functions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Ready {
+		t.Errorf("readiness = %+v, want ready", result)
+	}
+
+	if _, err := svc.CheckReadiness(context.Background(), "topology", nil); !errors.Is(err, services.ErrConceptNotFound) {
+		t.Errorf("CheckReadiness() error = %v, want ErrConceptNotFound for an unknown concept", err)
+	}
+}
+
+func TestCheckReadinessConceptWithoutPrerequisites(t *testing.T) {
+	graph := newClosureGraph()
+	for _, svc := range []*queryService{
+		{conceptRepo: graph, logger: zap.NewNop()},
+		{conceptRepo: graph, closureRepo: &memClosureRepo{}, logger: zap.NewNop()},
+	} {
+		// "sets" is a root of the graph; no closures are stored yet, so
+		// both services traverse the graph
+		result, err := svc.CheckReadiness(context.Background(), "sets", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Ready || result.Prerequisites != 0 || len(result.Missing) != 0 {
+			t.Errorf("readiness = %+v, want ready with no prerequisites", result)
+		}
+	}
+}
+
+func TestInvalidatePrerequisiteClosuresPicksUpNewEdges(t *testing.T) {
+	graph := newClosureGraph()
+	tasks := background.NewTasks()
+	svc := &queryService{conceptRepo: graph, closureRepo: &memClosureRepo{}, tasks: tasks, logger: zap.NewNop()}
+	if _, err := svc.RefreshPrerequisiteClosures(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	graph.addEdge("loop_a", "limits")
+	svc.invalidatePrerequisiteClosures()
+	drainTasks(t, tasks)
+
+	result, err := svc.CheckReadiness(context.Background(), "derivatives", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"functions", "limits", "loop_a", "loop_b", "sets"}
+	if !slices.Equal(result.Missing, want) {
+		t.Errorf("prerequisites after new edge = %v, want %v", result.Missing, want)
+	}
+}
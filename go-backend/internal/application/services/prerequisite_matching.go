@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+// prereqMatchCandidates is how many nearest concepts are considered per suggestion
+const prereqMatchCandidates = 3
+
+// matchSuggestedPrerequisites maps free-text prerequisite suggestions onto
+// existing concepts by similarity to their name and description in the
+// concept vector class. Every suggestion gets an entry;
+// unmatched ones carry no concept ID so reviewers can see what was missed.
+func (s *queryService) matchSuggestedPrerequisites(ctx context.Context, suggestions []string) []entities.PrerequisiteMatch {
+	if len(suggestions) == 0 || s.vectorRepo == nil {
+		return nil
+	}
+
+	matches := make([]entities.PrerequisiteMatch, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		match := entities.PrerequisiteMatch{Suggestion: suggestion}
+
+		results, err := s.vectorRepo.SearchConceptVectors(ctx, []string{suggestion}, prereqMatchCandidates)
+		if err != nil {
+			s.logger.Warn("Failed to search for prerequisite match",
+				zap.String("suggestion", suggestion),
+				zap.Error(err))
+			matches = append(matches, match)
+			continue
+		}
+
+		for _, result := range results {
+			conceptID, _ := result.Metadata["concept_id"].(string)
+			if conceptID == "" || result.Score < s.prereqThreshold {
+				continue
+			}
+
+			// The vector may outlive a concept removed from the graph
+			concept, err := s.conceptRepo.FindByID(ctx, conceptID)
+			if err != nil || concept == nil {
+				continue
+			}
+
+			if result.Score > match.Similarity {
+				match.MatchedConceptID = concept.ID
+				match.MatchedConceptName = concept.Name
+				match.Similarity = result.Score
+			}
+		}
+
+		if match.MatchedConceptID != "" {
+			s.logger.Debug("Matched suggested prerequisite to existing concept",
+				zap.String("suggestion", suggestion),
+				zap.String("concept_id", match.MatchedConceptID),
+				zap.Float64("similarity", match.Similarity))
+		}
+		matches = append(matches, match)
+	}
+
+	return matches
+}
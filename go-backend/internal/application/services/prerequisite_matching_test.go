@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+type stubVectorRepo struct {
+	repositories.VectorRepository
+	results  map[string][]types.VectorResult
+	concepts map[string][]types.VectorResult
+}
+
+func (r *stubVectorRepo) Search(ctx context.Context, query string, limit int) ([]types.VectorResult, error) {
+	return r.results[query], nil
+}
+
+func (r *stubVectorRepo) SearchByConcepts(ctx context.Context, concepts []string, limit int) ([]types.VectorResult, error) {
+	return nil, nil
+}
+
+func (r *stubVectorRepo) SearchConceptVectors(ctx context.Context, terms []string, limit int) ([]types.VectorResult, error) {
+	return r.concepts[strings.Join(terms, ",")], nil
+}
+
+type stubConceptRepo struct {
+	repositories.ConceptRepository
+	concepts map[string]*types.Concept
+}
+
+func (r *stubConceptRepo) FindByName(ctx context.Context, name string) (*types.Concept, error) {
+	if c, ok := r.concepts[name]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("concept not found: %s", name)
+}
+
+func (r *stubConceptRepo) FindByID(ctx context.Context, id string) (*types.Concept, error) {
+	for _, c := range r.concepts {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+// conceptHit is a concept vector search result for the graph concept id
+func conceptHit(id string, score float64) types.VectorResult {
+	return types.VectorResult{Score: score, Metadata: map[string]interface{}{"concept_id": id, "concept": id}}
+}
+
+func TestMatchSuggestedPrerequisites(t *testing.T) {
+	svc := &queryService{
+		vectorRepo: &stubVectorRepo{
+			concepts: map[string][]types.VectorResult{
+				"Differentiation": {conceptHit("derivatives", 0.82), conceptHit("derivatives", 0.91)},
+				"Basic algebra":   {conceptHit("algebra", 0.55)},
+				"Set theory":      {conceptHit("set_theory", 0.95)},
+			},
+			// Textbook chunks are not searched for matches
+			results: map[string][]types.VectorResult{
+				"Topology": {{Score: 0.99, Metadata: map[string]interface{}{"concept": "Algebra"}}},
+			},
+		},
+		conceptRepo: &stubConceptRepo{concepts: map[string]*types.Concept{
+			"Derivatives": {ID: "derivatives", Name: "Derivatives"},
+			"Algebra":     {ID: "algebra", Name: "Algebra"},
+		}},
+		prereqThreshold: 0.8,
+		logger:          zap.NewNop(),
+	}
+
+	matches := svc.matchSuggestedPrerequisites(context.Background(),
+		[]string{"Differentiation", "Basic algebra", "Set theory", "Topology"})
+
+	if len(matches) != 4 {
+		t.Fatalf("expected an entry per suggestion, got %d", len(matches))
+	}
+
+	if m := matches[0]; m.MatchedConceptID != "derivatives" || m.Similarity != 0.91 {
+		t.Errorf("expected Differentiation to match derivatives at 0.91, got %+v", m)
+	}
+	// Below threshold, a concept no longer in the graph and no concept
+	// results stay unmatched
+	for _, m := range matches[1:] {
+		if m.MatchedConceptID != "" {
+			t.Errorf("expected %q to stay unmatched, got %+v", m.Suggestion, m)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// SetPrerequisiteWeight changes how essential prerequisiteID is to
+// conceptID. The weight must be above 0 and at most 1; the relationship
+// must already exist.
+func (s *queryService) SetPrerequisiteWeight(ctx context.Context, conceptID, prerequisiteID string, weight float64) error {
+	if weight <= 0 || weight > 1 {
+		return fmt.Errorf("weight must be above 0 and at most 1, got %g", weight)
+	}
+
+	updated, err := s.conceptRepo.SetPrerequisiteWeight(ctx, conceptID, prerequisiteID, weight)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("prerequisite relationship not found: %s -> %s", prerequisiteID, conceptID)
+	}
+
+	s.logger.Info("Prerequisite weight updated",
+		zap.String("concept_id", conceptID),
+		zap.String("prerequisite_id", prerequisiteID),
+		zap.Float64("weight", weight))
+	return nil
+}
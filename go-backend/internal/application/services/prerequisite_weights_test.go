@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// weightedConceptRepo stores prerequisite edge weights keyed by
+// "prerequisite->concept" and returns a weighted learning path
+type weightedConceptRepo struct {
+	repositories.ConceptRepository
+	weights map[string]float64
+	path    []types.Concept
+}
+
+func (r *weightedConceptRepo) SetPrerequisiteWeight(ctx context.Context, conceptID, prerequisiteID string, weight float64) (bool, error) {
+	key := prerequisiteID + "->" + conceptID
+	if _, ok := r.weights[key]; !ok {
+		return false, nil
+	}
+	r.weights[key] = weight
+	return true, nil
+}
+
+func (r *weightedConceptRepo) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
+	return r.path, nil
+}
+
+func (r *weightedConceptRepo) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return true, nil
+}
+
+func TestSetPrerequisiteWeight(t *testing.T) {
+	repo := &weightedConceptRepo{weights: map[string]float64{"limits->derivatives": 1}}
+	svc := &queryService{conceptRepo: repo, logger: zap.NewNop()}
+	ctx := context.Background()
+
+	if err := svc.SetPrerequisiteWeight(ctx, "derivatives", "limits", 0.4); err != nil {
+		t.Fatal(err)
+	}
+	if repo.weights["limits->derivatives"] != 0.4 {
+		t.Errorf("expected the weight stored, got %v", repo.weights["limits->derivatives"])
+	}
+
+	for _, weight := range []float64{0, -0.5, 1.5} {
+		if err := svc.SetPrerequisiteWeight(ctx, "derivatives", "limits", weight); err == nil {
+			t.Errorf("expected weight %v rejected", weight)
+		}
+	}
+	if repo.weights["limits->derivatives"] != 0.4 {
+		t.Errorf("rejected weights must not be stored, got %v", repo.weights["limits->derivatives"])
+	}
+
+	err := svc.SetPrerequisiteWeight(ctx, "derivatives", "algebra", 0.5)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected a not found error for a missing relationship, got %v", err)
+	}
+}
+
+func TestProcessQueryKeepsPrerequisiteWeights(t *testing.T) {
+	queryRepo := &savingQueryRepo{}
+	tasks := background.NewTasks()
+	svc := &queryService{
+		conceptRepo: &weightedConceptRepo{path: []types.Concept{
+			{ID: "limits", Name: "Limits", Type: "prerequisite", Weight: 1},
+			{ID: "sequences", Name: "Sequences", Type: "prerequisite", Weight: 0.3},
+			{ID: "derivatives", Name: "Derivatives", Type: "target"},
+		}},
+		queryRepo:  queryRepo,
+		vectorRepo: &stubVectorRepo{},
+		llmClient:  &recordingLLM{concepts: []string{"derivatives"}},
+		sampler:    &analyticsSampler{sampleRate: 1},
+		tasks:      tasks,
+		logger:     zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a derivative?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	assertWeights := func(source string, path []types.Concept) {
+		t.Helper()
+		want := map[string]float64{"limits": 1, "sequences": 0.3, "derivatives": 0}
+		if len(path) != len(want) {
+			t.Fatalf("%s: expected %d path concepts, got %+v", source, len(want), path)
+		}
+		for _, concept := range path {
+			if concept.Weight != want[concept.ID] {
+				t.Errorf("%s: expected %s weight %v, got %v", source, concept.ID, want[concept.ID], concept.Weight)
+			}
+		}
+	}
+	assertWeights("result", result.PrerequisitePath)
+
+	var saved *entities.Query
+	queryRepo.mu.Lock()
+	if len(queryRepo.saved) > 0 {
+		saved = queryRepo.saved[0]
+	}
+	queryRepo.mu.Unlock()
+	if saved == nil {
+		t.Fatal("expected the query to be saved")
+	}
+	assertWeights("stored query", saved.PrerequisitePath)
+}
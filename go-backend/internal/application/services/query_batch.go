@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// ProcessQueryBatch runs each request through the query pipeline, at most
+// concurrency at a time. Identical questions are processed once and later
+// copies point at the first. Requests not started before ctx ends fail with
+// its error, so items come back in request order whether or not they ran.
+func (s *queryService) ProcessQueryBatch(ctx context.Context, reqs []*services.QueryRequest, concurrency int) []services.BatchQueryItem {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	items := make([]services.BatchQueryItem, len(reqs))
+	firstByKey := make(map[string]int, len(reqs))
+	var unique []int
+	for i, req := range reqs {
+		items[i].Index = i
+		key := batchDedupKey(req)
+		if first, ok := firstByKey[key]; ok {
+			duplicateOf := first
+			items[i].DuplicateOf = &duplicateOf
+			continue
+		}
+		firstByKey[key] = i
+		unique = append(unique, i)
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, i := range unique {
+		if err := acquire(ctx, sem); err != nil {
+			items[i].Error = s.batchItemError(i, reqs[i], err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := s.ProcessQuery(ctx, reqs[i])
+			if err != nil {
+				items[i].Error = s.batchItemError(i, reqs[i], err)
+				return
+			}
+			items[i].Result = result
+		}(i)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i := range items {
+		if items[i].DuplicateOf != nil {
+			first := items[*items[i].DuplicateOf]
+			items[i].Result, items[i].Error = first.Result, first.Error
+		}
+		if items[i].Error != "" {
+			failed++
+		}
+	}
+
+	s.logger.Info("Query batch processed",
+		zap.Int("questions", len(reqs)),
+		zap.Int("unique", len(unique)),
+		zap.Int("failed", failed),
+		zap.Int("concurrency", concurrency))
+
+	return items
+}
+
+// batchItemError is the error shown for a failed batch question. Only an
+// unsupported language is reported as is; anything else is logged and
+// replaced with the generic message.
+func (s *queryService) batchItemError(index int, req *services.QueryRequest, err error) string {
+	if errors.Is(err, services.ErrUnsupportedLanguage) {
+		return err.Error()
+	}
+	s.logger.Warn("Batch question failed",
+		zap.Int("index", index),
+		zap.String("request_id", req.RequestID),
+		zap.Error(err))
+	return services.QueryFailedMessage
+}
+
+// acquire takes a slot in sem, failing once ctx is done even if a slot is free
+func acquire(ctx context.Context, sem chan struct{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// batchDedupKey identifies requests that would produce the same answer:
+// the question compared case- and whitespace-insensitively, in the same
+// language, with the same options and context source
+func batchDedupKey(req *services.QueryRequest) string {
+	question := strings.ToLower(strings.Join(strings.Fields(req.Question), " "))
+	language, _ := services.NormalizeLanguage(req.Language)
+	flag := func(set bool) string {
+		if set {
+			return "1"
+		}
+		return "0"
+	}
+	return strings.Join([]string{
+		question, language, flag(req.IncludeVisuals), flag(req.BypassCache), req.RetrievalSource, req.UserID,
+	}, "\x00")
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// slowLLM takes a moment per query and records how many run at once
+type slowLLM struct {
+	recordingLLM
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	questions   []string
+}
+
+func (l *slowLLM) IdentifyConcepts(ctx context.Context, query string) ([]string, error) {
+	l.mu.Lock()
+	l.inFlight++
+	l.maxInFlight = max(l.maxInFlight, l.inFlight)
+	l.questions = append(l.questions, query)
+	l.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	l.mu.Lock()
+	l.inFlight--
+	l.mu.Unlock()
+	return []string{"derivatives"}, nil
+}
+
+func (l *slowLLM) GenerateExplanation(ctx context.Context, req ExplanationRequest) (string, error) {
+	return "Explanation in " + req.Language, nil
+}
+
+func newBatchQueryService(llm LLMClient, tasks *background.Tasks) *queryService {
+	return &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   &savingQueryRepo{},
+		vectorRepo:  &stubVectorRepo{},
+		llmClient:   llm,
+		sampler:     &analyticsSampler{sampleRate: 1},
+		tasks:       tasks,
+		logger:      zap.NewNop(),
+	}
+}
+
+func TestProcessQueryBatchMixed(t *testing.T) {
+	llm := &slowLLM{}
+	tasks := background.NewTasks()
+	svc := newBatchQueryService(llm, tasks)
+
+	reqs := []*services.QueryRequest{
+		{Question: "What is a derivative?"},
+		{Question: "what is a   DERIVATIVE?"},
+		{Question: "Explain limits"},
+		{Question: "Explain the chain rule"},
+		{Question: "Explain integrals", Language: "xx"},
+		{Question: "Explain series"},
+		{Question: "Explain limits", Language: "es"},
+	}
+
+	items := svc.ProcessQueryBatch(context.Background(), reqs, 2)
+	drainTasks(t, tasks)
+
+	if len(items) != len(reqs) {
+		t.Fatalf("got %d items, want %d", len(items), len(reqs))
+	}
+	for i, item := range items {
+		if item.Index != i {
+			t.Errorf("item %d has index %d", i, item.Index)
+		}
+		wantErr := i == 4
+		if (item.Error != "") != wantErr || (item.Result == nil) != wantErr {
+			t.Errorf("item %d: error %q, result %v; want error: %v", i, item.Error, item.Result != nil, wantErr)
+		}
+	}
+
+	if items[1].DuplicateOf == nil || *items[1].DuplicateOf != 0 {
+		t.Errorf("item 1 DuplicateOf = %v, want 0", items[1].DuplicateOf)
+	}
+	if items[1].Result != items[0].Result {
+		t.Error("duplicate question did not share the first answer")
+	}
+	if items[6].DuplicateOf != nil {
+		t.Error("same question in another language was treated as a duplicate")
+	}
+	if !strings.Contains(items[4].Error, "unsupported language") {
+		t.Errorf("item 4 error = %q, want unsupported language", items[4].Error)
+	}
+	if items[6].Result.Explanation != "Explanation in Spanish" {
+		t.Errorf("item 6 explanation = %q", items[6].Result.Explanation)
+	}
+
+	// Five distinct valid questions reach the pipeline, never more than two at once
+	if len(llm.questions) != 5 {
+		t.Errorf("pipeline ran %d times, want 5: %v", len(llm.questions), llm.questions)
+	}
+	if llm.maxInFlight > 2 {
+		t.Errorf("%d questions ran at once, want at most 2", llm.maxInFlight)
+	}
+}
+
+func TestProcessQueryBatchTimedOut(t *testing.T) {
+	llm := &slowLLM{}
+	svc := newBatchQueryService(llm, background.NewTasks())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	items := svc.ProcessQueryBatch(ctx, []*services.QueryRequest{
+		{Question: "What is a derivative?"},
+		{Question: "Explain limits"},
+	}, 2)
+
+	for i, item := range items {
+		if item.Result != nil || item.Error != services.QueryFailedMessage {
+			t.Errorf("item %d: result %v, error %q; want the generic message", i, item.Result != nil, item.Error)
+		}
+	}
+	if len(llm.questions) != 0 {
+		t.Errorf("pipeline ran %d times after the batch timed out", len(llm.questions))
+	}
+}
+
+func TestProcessQueryBatchHidesInternalErrors(t *testing.T) {
+	svc := newBatchQueryService(&slowLLM{}, background.NewTasks())
+
+	items := svc.ProcessQueryBatch(context.Background(), []*services.QueryRequest{
+		{Question: "What is a derivative?", RetrievalSource: "bogus"},
+		{Question: "Explain limits", Language: "xx"},
+	}, 2)
+
+	if items[0].Error != services.QueryFailedMessage {
+		t.Errorf("item 0 error = %q, want the generic message", items[0].Error)
+	}
+	if items[1].Error != `unsupported language: "xx"` {
+		t.Errorf("item 1 error = %q, want the unsupported language", items[1].Error)
+	}
+}
+
+func TestBatchDedupKey(t *testing.T) {
+	base := services.QueryRequest{Question: "What is a derivative?"}
+	same := base
+	same.Question = "  what is a   DERIVATIVE? "
+	if batchDedupKey(&base) != batchDedupKey(&same) {
+		t.Error("case and spacing differences should not tell questions apart")
+	}
+
+	variants := map[string]func(*services.QueryRequest){
+		"language":         func(r *services.QueryRequest) { r.Language = "es" },
+		"include_visuals":  func(r *services.QueryRequest) { r.IncludeVisuals = true },
+		"bypass_cache":     func(r *services.QueryRequest) { r.BypassCache = true },
+		"retrieval_source": func(r *services.QueryRequest) { r.RetrievalSource = "concepts" },
+		"user_id":          func(r *services.QueryRequest) { r.UserID = "u1" },
+	}
+	for name, vary := range variants {
+		req := base
+		vary(&req)
+		if batchDedupKey(&req) == batchDedupKey(&base) {
+			t.Errorf("requests differing in %s share a key", name)
+		}
+	}
+}
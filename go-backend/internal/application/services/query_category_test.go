@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// categorizedConceptRepo returns a path whose concepts carry graph categories
+type categorizedConceptRepo struct {
+	repositories.ConceptRepository
+}
+
+func (r *categorizedConceptRepo) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
+	return []types.Concept{
+		{ID: "functions", Name: "Functions", Category: "algebra"},
+		{ID: "limits", Name: "Limits", Category: "calculus"},
+		{ID: "derivatives", Name: "Derivatives", Category: "calculus"},
+	}, nil
+}
+
+func (r *categorizedConceptRepo) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return true, nil
+}
+
+func TestProcessQueryTagsCategory(t *testing.T) {
+	queryRepo := &savingQueryRepo{}
+	tasks := background.NewTasks()
+	svc := &queryService{
+		conceptRepo: &categorizedConceptRepo{},
+		queryRepo:   queryRepo,
+		vectorRepo:  &stubVectorRepo{},
+		llmClient: &recordingLLM{
+			concepts:    []string{"derivatives"},
+			explanation: map[string]string{"English": "A derivative measures change."},
+		},
+		sampler: &analyticsSampler{sampleRate: 1},
+		tasks:   tasks,
+		logger:  zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a derivative?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if result.Query.Category != "calculus" {
+		t.Errorf("Category = %q, want calculus", result.Query.Category)
+	}
+	if len(queryRepo.saved) != 1 || queryRepo.saved[0].Category != "calculus" {
+		t.Errorf("saved query not tagged calculus: %+v", queryRepo.saved)
+	}
+}
+
+func TestProcessQueryLeavesUnidentifiedQueryUncategorized(t *testing.T) {
+	tasks := background.NewTasks()
+	svc := &queryService{
+		conceptRepo: &categorizedConceptRepo{},
+		queryRepo:   &savingQueryRepo{},
+		vectorRepo:  &stubVectorRepo{},
+		llmClient:   &recordingLLM{explanation: map[string]string{"English": "Hello"}},
+		sampler:     &analyticsSampler{sampleRate: 1},
+		tasks:       tasks,
+		logger:      zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "Hi there"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if result.Query.Category != "" {
+		t.Errorf("Category = %q, want empty", result.Query.Category)
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+)
+
+// GetQueryContext returns the context chunks retrieved for a stored query,
+// best match first, as they were offered to the LLM. Queries stored before
+// chunks were kept with scores return their context text unscored. It
+// returns nil when the query does not exist.
+func (s *queryService) GetQueryContext(ctx context.Context, queryID string) (*services.QueryContext, error) {
+	query, err := s.queryRepo.FindByID(ctx, queryID)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return nil, nil
+	}
+
+	result := &services.QueryContext{
+		QueryID: query.ID,
+		Scored:  true,
+		Chunks:  query.Response.ContextChunks,
+	}
+	if len(result.Chunks) == 0 && len(query.Response.RetrievedContext) > 0 {
+		result.Scored = false
+		result.Chunks = make([]entities.ContextChunk, len(query.Response.RetrievedContext))
+		for i, content := range query.Response.RetrievedContext {
+			result.Chunks[i] = entities.ContextChunk{Content: content}
+		}
+	}
+	if result.Chunks == nil {
+		result.Chunks = []entities.ContextChunk{}
+	}
+	return result, nil
+}
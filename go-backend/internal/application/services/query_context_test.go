@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+func TestProcessQueryStoresContextChunks(t *testing.T) {
+	vectorRepo := &sourceVectorRepo{textbook: []types.VectorResult{
+		{
+			Content:  "The chain rule differentiates compositions.",
+			Score:    0.92,
+			Metadata: map[string]interface{}{"source": "Calculus Vol 1", "chapter": "3.6", "concept": "chain rule", "source_class": "MathChunk"},
+		},
+		{Content: "Derivatives measure rates of change.", Score: 0.71},
+	}}
+	queryRepo := &savingQueryRepo{}
+	tasks := background.NewTasks()
+	svc := &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   queryRepo,
+		vectorRepo:  vectorRepo,
+		llmClient: &recordingLLM{
+			concepts:    []string{"chain rule"},
+			explanation: map[string]string{"English": "Differentiate the outer function first."},
+		},
+		sampler: &analyticsSampler{sampleRate: 1},
+		tasks:   tasks,
+		logger:  zap.NewNop(),
+	}
+
+	if _, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "How does the chain rule work?"}); err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if len(queryRepo.saved) != 1 {
+		t.Fatalf("expected the query saved, got %d", len(queryRepo.saved))
+	}
+	stored := queryRepo.saved[0]
+	chunks := stored.Response.ContextChunks
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 stored chunks, got %+v", chunks)
+	}
+	want := entities.ContextChunk{
+		Content:     "The chain rule differentiates compositions.",
+		Score:       0.92,
+		Source:      "Calculus Vol 1",
+		Chapter:     "3.6",
+		Concept:     "chain rule",
+		SourceClass: "MathChunk",
+	}
+	if chunks[0] != want {
+		t.Errorf("chunk 0 = %+v, want %+v", chunks[0], want)
+	}
+	if chunks[1].Score != 0.71 || chunks[1].Source != "" {
+		t.Errorf("unexpected chunk 1 %+v", chunks[1])
+	}
+
+	svc.queryRepo = &storedQueryRepo{queries: map[string]*entities.Query{stored.ID: stored}}
+	queryContext, err := svc.GetQueryContext(context.Background(), stored.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !queryContext.Scored || queryContext.QueryID != stored.ID || len(queryContext.Chunks) != 2 || queryContext.Chunks[0] != want {
+		t.Errorf("GetQueryContext() = %+v", queryContext)
+	}
+}
+
+func TestGetQueryContextOfOlderQuery(t *testing.T) {
+	older := entities.NewQuery("", "What is a limit?", "")
+	older.Response.RetrievedContext = []string{"A limit describes...", "Continuity means..."}
+	svc := &queryService{queryRepo: &storedQueryRepo{queries: map[string]*entities.Query{older.ID: older}}}
+
+	queryContext, err := svc.GetQueryContext(context.Background(), older.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryContext.Scored || len(queryContext.Chunks) != 2 || queryContext.Chunks[1].Content != "Continuity means..." {
+		t.Errorf("expected the text unscored, got %+v", queryContext)
+	}
+
+	missing, err := svc.GetQueryContext(context.Background(), "missing")
+	if err != nil || missing != nil {
+		t.Errorf("expected nil for a missing query, got %+v, %v", missing, err)
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/services"
+)
+
+// experimentVariant is one arm of an explanation experiment
+type experimentVariant struct {
+	name   string
+	weight int
+	// prompt and model override the explanation prompt and the routed
+	// model; empty keeps them
+	prompt string
+	model  string
+}
+
+// queryExperiment assigns queries to variants by weight. Assignment hashes
+// the experiment name with the user or session, so a user keeps the same
+// variant for the whole experiment and a new experiment reshuffles users.
+type queryExperiment struct {
+	name     string
+	variants []experimentVariant
+	total    int
+}
+
+// newQueryExperiment returns nil when no experiment is configured
+func newQueryExperiment(cfg config.ExperimentConfig) *queryExperiment {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	e := &queryExperiment{name: cfg.Name}
+	for _, name := range cfg.Variants() {
+		weight := cfg.Split[name]
+		if weight <= 0 {
+			continue
+		}
+		e.variants = append(e.variants, experimentVariant{
+			name:   name,
+			weight: weight,
+			prompt: cfg.Prompts[name],
+			model:  cfg.Models[name],
+		})
+		e.total += weight
+	}
+	if e.total == 0 {
+		return nil
+	}
+	return e
+}
+
+// assign picks the variant of unit, the user or session a query came from
+func (e *queryExperiment) assign(unit string) experimentVariant {
+	h := fnv.New64a()
+	h.Write([]byte(e.name + "/" + unit))
+	bucket := int(h.Sum64() % uint64(e.total))
+
+	for _, v := range e.variants {
+		if bucket < v.weight {
+			return v
+		}
+		bucket -= v.weight
+	}
+	return e.variants[len(e.variants)-1]
+}
+
+// variant returns the variant a query was assigned to, if it is still part
+// of the experiment
+func (e *queryExperiment) variant(name string) (experimentVariant, bool) {
+	if e == nil {
+		return experimentVariant{}, false
+	}
+	for _, v := range e.variants {
+		if v.name == name {
+			return v, true
+		}
+	}
+	return experimentVariant{}, false
+}
+
+// experimentUnit is what a query is assigned by: its user, else its
+// session, else the query itself so anonymous queries are split evenly
+func experimentUnit(userID, sessionID, queryID string) string {
+	switch {
+	case userID != "":
+		return "user:" + userID
+	case sessionID != "":
+		return "session:" + sessionID
+	}
+	return "query:" + queryID
+}
+
+// GetExperiment describes the running experiment, or nil when there is none
+func (s *queryService) GetExperiment(ctx context.Context) *services.Experiment {
+	if s.experiment == nil {
+		return nil
+	}
+
+	experiment := &services.Experiment{Name: s.experiment.name}
+	for _, v := range s.experiment.variants {
+		experiment.Variants = append(experiment.Variants, services.ExperimentVariant{
+			Name:          v.name,
+			Weight:        v.weight,
+			Share:         float64(v.weight) / float64(s.experiment.total),
+			PromptVariant: v.prompt,
+			Model:         v.model,
+		})
+	}
+	return experiment
+}
+
+// GetExperimentResults compares the variants of an experiment by success,
+// fallback rate and latency. An empty name means the running experiment;
+// past experiments can be read as long as their queries are stored.
+func (s *queryService) GetExperimentResults(ctx context.Context, name string) (*services.ExperimentResults, error) {
+	running := s.experiment != nil && (name == "" || name == s.experiment.name)
+	if name == "" {
+		if s.experiment == nil {
+			return nil, nil
+		}
+		name = s.experiment.name
+	}
+	if s.queryRepo == nil {
+		return nil, fmt.Errorf("query store is not available")
+	}
+
+	outcomes, err := s.queryRepo.GetExperimentOutcomes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &services.ExperimentResults{
+		Name:     name,
+		Running:  running,
+		Variants: make([]services.ExperimentVariantResult, len(outcomes)),
+	}
+	for i, outcome := range outcomes {
+		result := services.ExperimentVariantResult{ExperimentOutcome: outcome}
+		if outcome.Queries > 0 {
+			result.SuccessRate = float64(outcome.Successful) / float64(outcome.Queries)
+			result.FallbackRate = float64(outcome.Fallbacks) / float64(outcome.Queries)
+		}
+		results.Variants[i] = result
+		results.Queries += outcome.Queries
+	}
+	return results, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+func testExperiment() *queryExperiment {
+	return newQueryExperiment(config.ExperimentConfig{
+		Name:    "explanation-style",
+		Split:   map[string]int{"control": 3, "concise": 1, "paused": 0},
+		Prompts: map[string]string{"concise": "concise"},
+		Models:  map[string]string{"concise": "gemini-2.5-flash-lite"},
+	})
+}
+
+func TestExperimentAssignmentFollowsSplit(t *testing.T) {
+	e := testExperiment()
+
+	counts := map[string]int{}
+	const users = 8000
+	for i := 0; i < users; i++ {
+		unit := experimentUnit(fmt.Sprintf("user-%d", i), "", "")
+		v := e.assign(unit)
+		if again := e.assign(unit); again.name != v.name {
+			t.Fatalf("expected %s to keep variant %s, got %s", unit, v.name, again.name)
+		}
+		counts[v.name]++
+	}
+
+	if counts["paused"] != 0 {
+		t.Errorf("expected a zero-weight variant to get no users, got %d", counts["paused"])
+	}
+	if share := float64(counts["concise"]) / users; math.Abs(share-0.25) > 0.03 {
+		t.Errorf("expected about a quarter of users on concise, got %.3f (%v)", share, counts)
+	}
+}
+
+func TestExperimentUnitPrefersUserThenSession(t *testing.T) {
+	if got := experimentUnit("u1", "s1", "q1"); got != "user:u1" {
+		t.Errorf("expected the user to decide, got %q", got)
+	}
+	if got := experimentUnit("", "s1", "q1"); got != "session:s1" {
+		t.Errorf("expected the session to decide, got %q", got)
+	}
+	if got := experimentUnit("", "", "q1"); got != "query:q1" {
+		t.Errorf("expected the query to decide, got %q", got)
+	}
+}
+
+func TestNewQueryExperimentDisabled(t *testing.T) {
+	if e := newQueryExperiment(config.ExperimentConfig{}); e != nil {
+		t.Errorf("expected no experiment without a name, got %+v", e)
+	}
+	if e := newQueryExperiment(config.ExperimentConfig{Name: "x", Split: map[string]int{"a": 0}}); e != nil {
+		t.Errorf("expected no experiment without a weighted variant, got %+v", e)
+	}
+}
+
+// sessionOn finds a session the experiment assigns to variant
+func sessionOn(t *testing.T, e *queryExperiment, variant string) string {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		session := fmt.Sprintf("session-%d", i)
+		if e.assign(experimentUnit("", session, "")).name == variant {
+			return session
+		}
+	}
+	t.Fatalf("no session assigned to %s", variant)
+	return ""
+}
+
+func TestProcessQueryTagsExperimentVariant(t *testing.T) {
+	llm := &recordingLLM{
+		concepts:    []string{"derivatives"},
+		explanation: map[string]string{"English": "The derivative measures change."},
+	}
+	queryRepo := &savingQueryRepo{}
+	tasks := background.NewTasks()
+	svc := &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   queryRepo,
+		vectorRepo:  &stubVectorRepo{},
+		llmClient:   llm,
+		sampler:     &analyticsSampler{sampleRate: 1},
+		experiment:  testExperiment(),
+		tasks:       tasks,
+		logger:      zap.NewNop(),
+	}
+
+	for _, variant := range []string{"concise", "control"} {
+		_, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{
+			Question:  "What is a derivative?",
+			SessionID: sessionOn(t, svc.experiment, variant),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if pending := tasks.Wait(ctx); len(pending) > 0 {
+		t.Fatalf("background tasks did not finish: %v", pending)
+	}
+
+	if len(llm.requests) != 2 {
+		t.Fatalf("expected two explanations, got %d", len(llm.requests))
+	}
+	if req := llm.requests[0]; req.PromptVariant != "concise" || req.Model != "gemini-2.5-flash-lite" {
+		t.Errorf("expected the concise prompt and model, got %+v", req)
+	}
+	if req := llm.requests[1]; req.PromptVariant != "" || req.Model != "" {
+		t.Errorf("expected control to keep the defaults, got %+v", req)
+	}
+
+	variants := map[string]string{}
+	for _, q := range queryRepo.saved {
+		if q.Experiment == nil || q.Experiment.Name != "explanation-style" {
+			t.Fatalf("expected the query tagged with the experiment, got %+v", q.Experiment)
+		}
+		variants[q.Experiment.Variant] = q.Response.LLMModel
+	}
+	if len(variants) != 2 || variants["concise"] != "gemini-2.5-flash-lite" || variants["control"] != "stub" {
+		t.Errorf("expected both variants stored with their models, got %v", variants)
+	}
+}
+
+type experimentOutcomeRepo struct {
+	repositories.QueryRepository
+	outcomes []repositories.ExperimentOutcome
+}
+
+func (r *experimentOutcomeRepo) GetExperimentOutcomes(ctx context.Context, experiment string) ([]repositories.ExperimentOutcome, error) {
+	return r.outcomes, nil
+}
+
+func TestGetExperimentResults(t *testing.T) {
+	svc := &queryService{
+		queryRepo: &experimentOutcomeRepo{outcomes: []repositories.ExperimentOutcome{
+			{Variant: "concise", Queries: 40, Successful: 30, Fallbacks: 4, AvgProcessingMs: 2100},
+			{Variant: "control", Queries: 120, Successful: 108, Fallbacks: 6, AvgProcessingMs: 3400},
+		}},
+		experiment: testExperiment(),
+		logger:     zap.NewNop(),
+	}
+
+	results, err := svc.GetExperimentResults(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Name != "explanation-style" || !results.Running || results.Queries != 160 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if v := results.Variants[0]; v.SuccessRate != 0.75 || v.FallbackRate != 0.1 {
+		t.Errorf("unexpected concise rates %+v", v)
+	}
+
+	past, err := svc.GetExperimentResults(context.Background(), "prompt-wording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if past.Running {
+		t.Error("expected a past experiment not to be reported as running")
+	}
+
+	svc.experiment = nil
+	if results, err := svc.GetExperimentResults(context.Background(), ""); err != nil || results != nil {
+		t.Errorf("expected no results without a running experiment, got %+v, %v", results, err)
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/types"
+)
+
+// GetQueryGraph returns the prerequisite path stored with a processed query
+// as nodes and the prerequisite edges among them. It returns nil when the
+// query does not exist.
+func (s *queryService) GetQueryGraph(ctx context.Context, queryID string) (*types.PathGraph, error) {
+	query, err := s.queryRepo.FindByID(ctx, queryID)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(query.PrerequisitePath))
+	for _, concept := range query.PrerequisitePath {
+		ids = append(ids, concept.ID)
+	}
+
+	edges, err := s.conceptRepo.GetEdgesAmong(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get path edges: %w", err)
+	}
+
+	return buildPathGraph(query.ID, query.PrerequisitePath, edges), nil
+}
+
+// buildPathGraph turns a prerequisite path into graph nodes and keeps only
+// edges between distinct nodes of the path
+func buildPathGraph(queryID string, path []types.Concept, edges []types.NeighborhoodEdge) *types.PathGraph {
+	graph := &types.PathGraph{
+		QueryID: queryID,
+		Nodes:   make([]types.PathGraphNode, 0, len(path)),
+		Links:   make([]types.PathGraphLink, 0, len(edges)),
+	}
+
+	inPath := make(map[string]bool, len(path))
+	for _, concept := range path {
+		if concept.ID == "" || inPath[concept.ID] {
+			continue
+		}
+		inPath[concept.ID] = true
+
+		nodeType := "prerequisite"
+		if concept.Type == "target" {
+			nodeType = "target"
+		}
+		graph.Nodes = append(graph.Nodes, types.PathGraphNode{
+			ID:          concept.ID,
+			Name:        concept.Name,
+			Description: concept.Description,
+			Type:        nodeType,
+			IsTarget:    nodeType == "target",
+		})
+	}
+
+	seen := make(map[types.NeighborhoodEdge]bool, len(edges))
+	for _, edge := range edges {
+		if !inPath[edge.From] || !inPath[edge.To] || edge.From == edge.To || seen[edge] {
+			continue
+		}
+		seen[edge] = true
+		graph.Links = append(graph.Links, types.PathGraphLink{
+			Source: edge.From,
+			Target: edge.To,
+			Type:   edge.Type,
+			Weight: edge.Weight,
+		})
+	}
+
+	return graph
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+)
+
+type storedQueryRepo struct {
+	repositories.QueryRepository
+	queries map[string]*entities.Query
+}
+
+func (r *storedQueryRepo) FindByID(ctx context.Context, id string) (*entities.Query, error) {
+	return r.queries[id], nil
+}
+
+type edgeConceptRepo struct {
+	repositories.ConceptRepository
+	edges []types.NeighborhoodEdge
+	asked []string
+}
+
+func (r *edgeConceptRepo) GetEdgesAmong(ctx context.Context, conceptIDs []string) ([]types.NeighborhoodEdge, error) {
+	r.asked = conceptIDs
+	return r.edges, nil
+}
+
+func TestGetQueryGraph(t *testing.T) {
+	query := entities.NewQuery("", "How do I integrate by parts?", "")
+	query.PrerequisitePath = []types.Concept{
+		{ID: "derivatives", Name: "Derivatives", Type: "prerequisite"},
+		{ID: "integration", Name: "Integration", Type: "prerequisite"},
+		{ID: "integration_by_parts", Name: "Integration by Parts", Type: "target"},
+	}
+
+	conceptRepo := &edgeConceptRepo{edges: []types.NeighborhoodEdge{
+		{From: "derivatives", To: "integration", Type: "PREREQUISITE_FOR"},
+		{From: "integration", To: "integration_by_parts", Type: "PREREQUISITE_FOR", Weight: 0.6},
+		{From: "integration", To: "integration_by_parts", Type: "PREREQUISITE_FOR", Weight: 0.6},
+		{From: "limits", To: "derivatives", Type: "PREREQUISITE_FOR"},
+	}}
+	svc := &queryService{
+		queryRepo:   &storedQueryRepo{queries: map[string]*entities.Query{query.ID: query}},
+		conceptRepo: conceptRepo,
+	}
+
+	graph, err := svc.GetQueryGraph(context.Background(), query.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conceptRepo.asked) != 3 {
+		t.Errorf("expected edges requested for the 3 path concepts, got %v", conceptRepo.asked)
+	}
+
+	if len(graph.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %+v", graph.Nodes)
+	}
+	targets := 0
+	for _, node := range graph.Nodes {
+		if node.IsTarget {
+			targets++
+			if node.ID != "integration_by_parts" || node.Type != "target" {
+				t.Errorf("unexpected target node %+v", node)
+			}
+		}
+	}
+	if targets != 1 {
+		t.Errorf("expected exactly one target node, got %d", targets)
+	}
+
+	want := []types.PathGraphLink{
+		{Source: "derivatives", Target: "integration", Type: "PREREQUISITE_FOR"},
+		{Source: "integration", Target: "integration_by_parts", Type: "PREREQUISITE_FOR", Weight: 0.6},
+	}
+	if len(graph.Links) != len(want) {
+		t.Fatalf("expected %d links, got %+v", len(want), graph.Links)
+	}
+	for i := range want {
+		if graph.Links[i] != want[i] {
+			t.Errorf("link %d = %+v, want %+v", i, graph.Links[i], want[i])
+		}
+	}
+}
+
+func TestGetQueryGraphMissingQuery(t *testing.T) {
+	svc := &queryService{queryRepo: &storedQueryRepo{}}
+	graph, err := svc.GetQueryGraph(context.Background(), "missing")
+	if err != nil || graph != nil {
+		t.Errorf("expected nil graph and error for a missing query, got %v, %v", graph, err)
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+)
+
+// ListQueries lists stored queries of every user for review
+func (s *queryService) ListQueries(ctx context.Context, filter repositories.QueryListFilter) ([]services.QuerySummary, int, error) {
+	if s.queryRepo == nil {
+		return nil, 0, fmt.Errorf("query store is not available")
+	}
+
+	queries, total, err := s.queryRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list queries: %w", err)
+	}
+
+	summaries := make([]services.QuerySummary, len(queries))
+	for i, query := range queries {
+		summaries[i] = summarizeQuery(query)
+	}
+	return summaries, total, nil
+}
+
+func summarizeQuery(query *entities.Query) services.QuerySummary {
+	return services.QuerySummary{
+		ID:                 query.ID,
+		UserID:             query.UserID,
+		Text:               query.Text,
+		IdentifiedConcepts: query.IdentifiedConcepts,
+		Status:             queryListStatus(query),
+		Timestamp:          query.Timestamp,
+		ProcessingTimeMs:   query.ProcessingTimeMs,
+		ExplanationLength:  len(query.Response.Explanation),
+		PrerequisiteCount:  len(query.PrerequisitePath),
+	}
+}
+
+// queryListStatus is the outcome a query is filtered by, see QueryList*
+func queryListStatus(query *entities.Query) string {
+	switch {
+	case !query.Success:
+		return repositories.QueryListFailed
+	case query.Response.Fallback:
+		return repositories.QueryListFallback
+	default:
+		return repositories.QueryListSuccess
+	}
+}
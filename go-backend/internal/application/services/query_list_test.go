@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+// listingQueryRepo applies a listing filter's user and status to stored queries
+type listingQueryRepo struct {
+	repositories.QueryRepository
+	queries []*entities.Query
+	filters []repositories.QueryListFilter
+}
+
+func (r *listingQueryRepo) List(ctx context.Context, filter repositories.QueryListFilter) ([]*entities.Query, int, error) {
+	r.filters = append(r.filters, filter)
+
+	var matches []*entities.Query
+	for _, q := range r.queries {
+		if filter.UserID != "" && q.UserID != filter.UserID {
+			continue
+		}
+		if filter.Status != "" && queryListStatus(q) != filter.Status {
+			continue
+		}
+		matches = append(matches, q)
+	}
+	total := len(matches)
+	if filter.Limit > 0 && len(matches) > filter.Limit {
+		matches = matches[:filter.Limit]
+	}
+	return matches, total, nil
+}
+
+func storedQuery(id, userID string, success, fallback bool) *entities.Query {
+	q := entities.NewQuery(userID, "What is "+id+"?", "")
+	q.ID = id
+	q.Success = success
+	q.Response.Fallback = fallback
+	q.Response.Explanation = "An explanation."
+	return q
+}
+
+func testListingRepo() *listingQueryRepo {
+	return &listingQueryRepo{queries: []*entities.Query{
+		storedQuery("limits", "u1", true, false),
+		storedQuery("series", "", true, true),
+		storedQuery("vectors", "u2", false, false),
+		storedQuery("matrices", "", true, false),
+	}}
+}
+
+func TestListQueriesCoversEveryUser(t *testing.T) {
+	svc := &queryService{queryRepo: testListingRepo(), logger: zap.NewNop()}
+
+	summaries, total, err := svc.ListQueries(context.Background(), repositories.QueryListFilter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || len(summaries) != 4 {
+		t.Fatalf("expected anonymous and signed-in queries, got %d of %d", len(summaries), total)
+	}
+
+	want := map[string]string{
+		"limits":   repositories.QueryListSuccess,
+		"series":   repositories.QueryListFallback,
+		"vectors":  repositories.QueryListFailed,
+		"matrices": repositories.QueryListSuccess,
+	}
+	for _, s := range summaries {
+		if s.Status != want[s.ID] {
+			t.Errorf("%s: expected status %s, got %s", s.ID, want[s.ID], s.Status)
+		}
+		if s.ExplanationLength != len("An explanation.") {
+			t.Errorf("%s: unexpected explanation length %d", s.ID, s.ExplanationLength)
+		}
+	}
+}
+
+func TestListQueriesFiltersByStatus(t *testing.T) {
+	svc := &queryService{queryRepo: testListingRepo(), logger: zap.NewNop()}
+
+	summaries, total, err := svc.ListQueries(context.Background(), repositories.QueryListFilter{Status: repositories.QueryListFailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || summaries[0].ID != "vectors" || summaries[0].UserID != "u2" {
+		t.Errorf("expected only the failed query, got %+v", summaries)
+	}
+}
+
+func TestGetCachedConceptsListsAllUsers(t *testing.T) {
+	repo := testListingRepo()
+	svc := &queryService{queryRepo: repo, logger: zap.NewNop()}
+
+	queries, err := svc.GetCachedConcepts(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 3 {
+		t.Errorf("expected the limit of 3 queries across users, got %d", len(queries))
+	}
+	if f := repo.filters[0]; f.UserID != "" || f.Limit != 3 {
+		t.Errorf("expected an unfiltered listing, got %+v", f)
+	}
+}
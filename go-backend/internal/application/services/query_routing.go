@@ -0,0 +1,100 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mathprereq/internal/core/config"
+)
+
+// defaultContextChunks is how many textbook chunks ground an explanation
+// when no router is configured
+const defaultContextChunks = 5
+
+// problemSolvingKeywords mark queries that ask for a worked solution or
+// proof rather than an explanation of what a concept is
+var problemSolvingKeywords = []string{
+	"solve", "prove", "show that", "step by step", "step-by-step",
+	"evaluate", "calculate", "compute", "simplify", "optimize", "maximize", "minimize",
+}
+
+// mathExpression matches formulas written into the query, e.g. x^2 or y = 3x
+var mathExpression = regexp.MustCompile(`[\^=∫√]|\d\s*[+\-*/]\s*\w|d/dx`)
+
+// queryRouter picks the model profile a query is explained with
+type queryRouter struct {
+	enabled bool
+	fast    config.ModelProfile
+	deep    config.ModelProfile
+}
+
+func newQueryRouter(cfg config.LLMConfig) *queryRouter {
+	return &queryRouter{
+		enabled: cfg.RoutingEnabled,
+		fast:    cfg.FastProfile,
+		deep:    cfg.DeepProfile,
+	}
+}
+
+// route returns the profile name for a query and its settings. Without a
+// router no profile is named and the LLM client's defaults apply.
+func (r *queryRouter) route(text string, conceptNames []string) (string, config.ModelProfile) {
+	if r == nil {
+		return "", config.ModelProfile{ContextChunks: defaultContextChunks}
+	}
+	if !r.enabled || classifyQuery(text, conceptNames) == config.ProfileDeep {
+		return config.ProfileDeep, r.deep
+	}
+	return config.ProfileFast, r.fast
+}
+
+// classifyQuery estimates how much work a query needs from its length, how
+// many concepts it spans and whether it asks to solve a problem. A
+// single-concept "what is" question is fast; multi-concept problems are deep.
+func classifyQuery(text string, conceptNames []string) string {
+	score := 0
+
+	switch words := len(strings.Fields(text)); {
+	case words > 40:
+		score += 2
+	case words > 20:
+		score++
+	}
+
+	switch {
+	case len(conceptNames) >= 3:
+		score += 2
+	case len(conceptNames) == 2:
+		score++
+	}
+
+	lower := strings.ToLower(text)
+	for _, keyword := range problemSolvingKeywords {
+		if strings.Contains(lower, keyword) {
+			score += 2
+			break
+		}
+	}
+
+	if mathExpression.MatchString(text) {
+		score++
+	}
+
+	if score >= 2 {
+		return config.ProfileDeep
+	}
+	return config.ProfileFast
+}
+
+// newOverrideModels returns the allowed override models, or nil when
+// overrides are disabled
+func newOverrideModels(cfg config.LLMConfig) map[string]bool {
+	if cfg.Override.Token == "" {
+		return nil
+	}
+	allowed := make(map[string]bool)
+	for _, model := range cfg.OverrideModels() {
+		allowed[model] = true
+	}
+	return allowed
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+func TestClassifyQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		concepts []string
+		want     string
+	}{
+		{"definition", "What is a derivative?", []string{"derivatives"}, config.ProfileFast},
+		{"two concepts", "How are limits related to derivatives?", []string{"limits", "derivatives"}, config.ProfileFast},
+		{"problem", "Solve x^2 - 4 = 0", []string{"quadratic equations"}, config.ProfileDeep},
+		{"multi-concept proof", "Prove that the derivative of sin(x) is cos(x) using limits", []string{"derivatives", "limits", "trigonometric functions"}, config.ProfileDeep},
+		{"long multi-concept question", "I keep getting confused about when to use the chain rule versus the product rule because both of them seem to apply when a function is built out of other functions and I never know which one comes first", []string{"chain rule", "product rule"}, config.ProfileDeep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyQuery(tt.text, tt.concepts); got != tt.want {
+				t.Errorf("classifyQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryRouterDisabledRoutesDeep(t *testing.T) {
+	router := newQueryRouter(config.LLMConfig{
+		FastProfile: config.ModelProfile{Model: "fast-model"},
+		DeepProfile: config.ModelProfile{Model: "deep-model"},
+	})
+
+	name, profile := router.route("What is a derivative?", []string{"derivatives"})
+	if name != config.ProfileDeep || profile.Model != "deep-model" {
+		t.Errorf("route() = %q, %q; want %q, %q", name, profile.Model, config.ProfileDeep, "deep-model")
+	}
+}
+
+func TestProcessQueryRoutesModelProfile(t *testing.T) {
+	vectors := &recordingVectorRepo{}
+	llm := &recordingLLM{
+		concepts:    []string{"derivatives"},
+		explanation: map[string]string{"English": "A derivative measures change."},
+	}
+	tasks := background.NewTasks()
+	queries := &savingQueryRepo{}
+	svc := &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   queries,
+		vectorRepo:  vectors,
+		llmClient:   llm,
+		router: newQueryRouter(config.LLMConfig{
+			RoutingEnabled: true,
+			FastProfile:    config.ModelProfile{Model: "fast-model", ContextChunks: 3},
+			DeepProfile:    config.ModelProfile{Model: "deep-model", ContextChunks: 5},
+		}),
+		sampler: &analyticsSampler{sampleRate: 1},
+		tasks:   tasks,
+		logger:  zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a derivative?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if result.ModelProfile != config.ProfileFast {
+		t.Errorf("ModelProfile = %q, want %q", result.ModelProfile, config.ProfileFast)
+	}
+	if vectors.limit != 3 {
+		t.Errorf("vector search limit = %d, want 3", vectors.limit)
+	}
+	if len(llm.requests) != 1 || llm.requests[0].Profile != config.ProfileFast {
+		t.Errorf("explanation requests = %+v, want one with profile %q", llm.requests, config.ProfileFast)
+	}
+	if len(queries.saved) != 1 || queries.saved[0].Response.LLMModel != "fast-model" {
+		t.Errorf("saved query model not recorded as fast-model")
+	}
+}
@@ -3,29 +3,76 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/url"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/mathprereq/internal/arithmetic"
+	"github.com/mathprereq/internal/autocomplete"
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/core/config"
 	"github.com/mathprereq/internal/data/scraper"
 	"github.com/mathprereq/internal/domain/entities"
 	"github.com/mathprereq/internal/domain/repositories"
 	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/fallback"
 	"github.com/mathprereq/internal/mailer"
 	"github.com/mathprereq/internal/types"
+	"github.com/mathprereq/internal/visuals"
 	"go.uber.org/zap"
 )
 
+// maxVisualAids caps how many plots are rendered for one explanation
+const maxVisualAids = 3
+
 type queryService struct {
-	conceptRepo       repositories.ConceptRepository
-	queryRepo         repositories.QueryRepository
-	vectorRepo        repositories.VectorRepository
-	stagedConceptRepo repositories.StagedConceptRepository
-	llmClient         LLMClient
-	resourceScraper   *scraper.EducationalWebScraper
-	mailer            *mailer.Mailer
-	adminEmail        string
-	logger            *zap.Logger
+	conceptRepo        repositories.ConceptRepository
+	queryRepo          repositories.QueryRepository
+	vectorRepo         repositories.VectorRepository
+	stagedConceptRepo  repositories.StagedConceptRepository
+	visualAidRepo      repositories.VisualAidRepository
+	pathSnapshotRepo   repositories.PathSnapshotRepository
+	conceptProfileRepo repositories.ConceptProfileRepository
+	explanationRepo    repositories.ExplanationVersionRepository
+	closureRepo        repositories.PrerequisiteClosureRepository
+	formulaRepo        repositories.FormulaRepository
+	aliasRepo          repositories.ConceptAliasRepository
+	scrapeJobRepo      repositories.ScrapeJobRepository // nil keeps scrapes on the in-memory pool
+	userProfileRepo    repositories.UserProfileRepository
+	identificationRepo repositories.ConceptIdentificationRepository
+	scrapeMaxAttempts  int
+	conceptIndex       *autocomplete.Index
+	conceptIndexMu     sync.Mutex // serializes index rebuilds
+	llmClient          LLMClient
+	fallback           *fallback.Renderer
+	resourceScraper    *scraper.EducationalWebScraper
+	mailer             *mailer.Mailer
+	adminEmail         string
+	sampler            *analyticsSampler
+	// maxStoredExplanation caps stored explanations; zero stores them in full
+	maxStoredExplanation int
+	prereqThreshold      float64
+	demandHalfLife       time.Duration
+	autoApproval         autoApprovalPolicy
+	staging              config.StagingConfig // review emails and digest
+	questionCache        config.QueryCacheConfig
+	searchByConcepts     bool
+	retrievalSource      string // default context source, see config.RetrievalSource*
+	conceptQueryChunks   int    // context chunks retrieved for concept queries
+	router               *queryRouter
+	experiment           *queryExperiment
+	// overrideModels are the models a request's ModelOverride may name
+	overrideModels   map[string]bool
+	verifyArithmetic bool
+	pathSuggestions  bool
+	tasks            *background.Tasks
+	scrapePool       *background.Pool
+	logger           *zap.Logger
 }
 
 type NewConceptAnalysis struct {
@@ -36,6 +83,7 @@ type NewConceptAnalysis struct {
 	SuggestedCategory   string   `json:"suggested_category"`
 	Reasoning           string   `json:"reasoning"`
 	IsLikelyNewConcept  bool     `json:"is_likely_new_concept"`
+	Confidence          float64  `json:"confidence"`
 }
 
 // LLMClient interface for the service layer
@@ -43,15 +91,54 @@ type LLMClient interface {
 	IdentifyConcepts(ctx context.Context, query string) ([]string, error)
 	GenerateExplanation(ctx context.Context, req ExplanationRequest) (string, error)
 	AnalyzeNewConcept(ctx context.Context, conceptName string, queryContext string) (*NewConceptAnalysis, error)
+	ExtractPlotFunctions(ctx context.Context, explanation string, maxPlots int) ([]PlotSpec, error)
+	GenerateConceptProfile(ctx context.Context, conceptName string) (*entities.ConceptProfile, error)
+	GenerateConceptDescription(ctx context.Context, conceptName string, contextChunks []string) (string, error)
+	ExplainMistake(ctx context.Context, req MistakeRequest) (*entities.MistakeExplanation, error)
+	SuggestPrerequisitePath(ctx context.Context, query string, concepts []string) ([]SuggestedPrerequisite, error)
 	Provider() string
 	Model() string
 	IsHealthy(ctx context.Context) bool
 }
 
+// PlotSpec is a function the LLM suggested plotting for an explanation
+type PlotSpec struct {
+	Title      string  `json:"title"`
+	Expression string  `json:"expression"`
+	XMin       float64 `json:"x_min"`
+	XMax       float64 `json:"x_max"`
+}
+
 type ExplanationRequest struct {
 	Query            string          `json:"query"`
 	PrerequisitePath []types.Concept `json:"prerequisite_path"`
 	ContextChunks    []string        `json:"context_chunks"`
+	Language         string          `json:"language,omitempty"`
+	// Profile names the model profile chosen by routing; empty means the default
+	Profile string `json:"profile,omitempty"`
+	// PromptVariant and Model are set by an experiment variant; empty keeps
+	// the active prompt and the profile's model
+	PromptVariant string `json:"prompt_variant,omitempty"`
+	Model         string `json:"model,omitempty"`
+	// Level and Style come from the asking user's stored preferences
+	Level string `json:"level,omitempty"`
+	Style string `json:"style,omitempty"`
+}
+
+// SuggestedPrerequisite is a concept the LLM suggests learning first when the
+// curated graph has no path
+type SuggestedPrerequisite struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// MistakeRequest asks the LLM where a student's attempt at a problem goes wrong
+type MistakeRequest struct {
+	Problem       string   `json:"problem"`
+	Attempt       string   `json:"attempt"`
+	Concepts      []string `json:"concepts"`
+	ContextChunks []string `json:"context_chunks"`
+	Language      string   `json:"language,omitempty"`
 }
 
 func NewQueryService(
@@ -59,41 +146,130 @@ func NewQueryService(
 	queryRepo repositories.QueryRepository,
 	vectorRepo repositories.VectorRepository,
 	stagedConceptRepo repositories.StagedConceptRepository,
+	visualAidRepo repositories.VisualAidRepository,
+	pathSnapshotRepo repositories.PathSnapshotRepository,
+	conceptProfileRepo repositories.ConceptProfileRepository,
+	explanationRepo repositories.ExplanationVersionRepository,
+	closureRepo repositories.PrerequisiteClosureRepository,
+	formulaRepo repositories.FormulaRepository,
+	aliasRepo repositories.ConceptAliasRepository,
+	scrapeJobRepo repositories.ScrapeJobRepository,
+	userProfileRepo repositories.UserProfileRepository,
+	identificationRepo repositories.ConceptIdentificationRepository,
 	llmClient LLMClient,
+	fallbackRenderer *fallback.Renderer,
 	resourceScraper *scraper.EducationalWebScraper,
 	mailer *mailer.Mailer,
 	adminEmail string,
+	analyticsCfg config.AnalyticsConfig,
+	stagingCfg config.StagingConfig,
+	llmCfg config.LLMConfig,
+	queryCacheCfg config.QueryCacheConfig,
+	scraperCfg config.ScraperConfig,
+	vectorSearchMode string,
+	retrievalSource string,
+	conceptQueryChunks int,
+	tasks *background.Tasks,
+	scrapePool *background.Pool,
 	logger *zap.Logger,
 ) services.QueryService {
 	return &queryService{
-		conceptRepo:       conceptRepo,
-		queryRepo:         queryRepo,
-		vectorRepo:        vectorRepo,
-		stagedConceptRepo: stagedConceptRepo,
-		llmClient:         llmClient,
-		resourceScraper:   resourceScraper,
-		mailer:            mailer,
-		adminEmail:        adminEmail,
-		logger:            logger,
+		conceptRepo:          conceptRepo,
+		queryRepo:            queryRepo,
+		vectorRepo:           vectorRepo,
+		stagedConceptRepo:    stagedConceptRepo,
+		visualAidRepo:        visualAidRepo,
+		pathSnapshotRepo:     pathSnapshotRepo,
+		conceptProfileRepo:   conceptProfileRepo,
+		explanationRepo:      explanationRepo,
+		closureRepo:          closureRepo,
+		formulaRepo:          formulaRepo,
+		aliasRepo:            aliasRepo,
+		scrapeJobRepo:        scrapeJobRepo,
+		userProfileRepo:      userProfileRepo,
+		identificationRepo:   identificationRepo,
+		scrapeMaxAttempts:    scraperCfg.QueueMaxAttempts,
+		conceptIndex:         autocomplete.NewIndex(),
+		llmClient:            llmClient,
+		fallback:             fallbackRenderer,
+		resourceScraper:      resourceScraper,
+		mailer:               mailer,
+		adminEmail:           adminEmail,
+		sampler:              newAnalyticsSampler(analyticsCfg),
+		maxStoredExplanation: analyticsCfg.MaxStoredExplanation,
+		prereqThreshold:      stagingCfg.PrereqMatchThreshold,
+		demandHalfLife:       stagingCfg.DemandHalfLife,
+		autoApproval:         newAutoApprovalPolicy(stagingCfg),
+		staging:              stagingCfg,
+		questionCache:        queryCacheCfg,
+		searchByConcepts:     vectorSearchMode == config.SearchModeConcepts,
+		retrievalSource:      retrievalSource,
+		conceptQueryChunks:   conceptQueryChunks,
+		router:               newQueryRouter(llmCfg),
+		overrideModels:       newOverrideModels(llmCfg),
+		experiment:           newQueryExperiment(llmCfg.Experiment),
+		verifyArithmetic:     llmCfg.VerifyArithmetic,
+		pathSuggestions:      llmCfg.PathSuggestions,
+		tasks:                tasks,
+		scrapePool:           scrapePool,
+		logger:               logger,
 	}
 }
 
 func (s *queryService) ProcessQuery(ctx context.Context, req *services.QueryRequest) (*services.QueryResult, error) {
 	startTime := time.Now()
 
+	language, ok := services.NormalizeLanguage(req.Language)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", services.ErrUnsupportedLanguage, req.Language)
+	}
+	if req.RetrievalSource != "" && !config.ValidRetrievalSource(req.RetrievalSource) {
+		return nil, fmt.Errorf("unsupported retrieval source: %q", req.RetrievalSource)
+	}
+
 	// Create query entity
 	query := entities.NewQuery(req.UserID, req.Question, "")
+	query.Language = language
+	query.QuestionKey = entities.QuestionKey(req.Question)
+	query.ConceptKey = req.ConceptKey
+	if req.ModelOverride != "" {
+		if !s.overrideModels[req.ModelOverride] {
+			return nil, fmt.Errorf("%w: %q", services.ErrModelOverrideNotAllowed, req.ModelOverride)
+		}
+		query.ModelOverride = req.ModelOverride
+	}
+
+	// Answers tailored to a user's preferences are neither served from nor
+	// stored for the question cache
+	query.Preferences = s.explanationPreferences(ctx, req.UserID)
+
+	// Overridden answers are evaluations of one model, so they are kept out
+	// of the cache as well
+	tailored := query.Preferences != nil || query.ModelOverride != ""
+	if result := s.cachedAnswer(ctx, req, query.QuestionKey, language, tailored); result != nil {
+		result.ProcessingTime = time.Since(startTime)
+		return result, nil
+	}
+
+	// Cached answers are not tagged; only freshly generated ones compare
+	// variants. Overridden queries stay out so they cannot skew the results.
+	if s.experiment != nil && query.ModelOverride == "" {
+		variant := s.experiment.assign(experimentUnit(req.UserID, req.SessionID, query.ID))
+		query.Experiment = &entities.QueryExperiment{Name: s.experiment.name, Variant: variant.name}
+	}
 
 	s.logger.Info("Processing query",
 		zap.String("query_id", query.ID),
-		zap.String("question", req.Question[:min(len(req.Question), 100)]))
+		zap.String("question", req.Question[:min(len(req.Question), 100)]),
+		zap.String("language", language))
 
 	// Process through pipeline
-	result, err := s.processQueryPipeline(ctx, query)
+	result, err := s.processQueryPipeline(ctx, query, req)
 
-	// Always save query (success or failure)
-	query.MarkCompleted(err == nil, err)
-	s.saveQueryAsync(ctx, query)
+	// Always save query (success or failure). Fallback explanations count as
+	// failures so they are never served from the cache.
+	query.MarkCompleted(err == nil && !query.Response.Fallback, err)
+	stored := s.saveQueryAsync(ctx, query)
 
 	if err != nil {
 		s.logger.Error("Query processing failed",
@@ -101,6 +277,12 @@ func (s *queryService) ProcessQuery(ctx context.Context, req *services.QueryRequ
 			zap.Error(err))
 		return nil, fmt.Errorf("failed to process query: %w", err)
 	}
+	result.Unsampled = !stored
+
+	// Optional: render plots for functions discussed in the explanation
+	if req.IncludeVisuals {
+		result.VisualAids = s.generateVisualAids(ctx, query.ID, result.Explanation)
+	}
 
 	result.ProcessingTime = time.Since(startTime)
 
@@ -111,13 +293,11 @@ func (s *queryService) ProcessQuery(ctx context.Context, req *services.QueryRequ
 	return result, nil
 }
 
-func (s *queryService) processQueryPipeline(ctx context.Context, query *entities.Query) (*services.QueryResult, error) {
+func (s *queryService) processQueryPipeline(ctx context.Context, query *entities.Query, req *services.QueryRequest) (*services.QueryResult, error) {
 	var result = &services.QueryResult{Query: query}
 
 	// Step 1: Extract concepts
-	stepStart := time.Now()
-	conceptNames, err := s.llmClient.IdentifyConcepts(ctx, query.Text)
-	query.AddProcessingStep("identify_concepts", time.Since(stepStart), err == nil, err)
+	conceptNames, err := s.identifyConcepts(ctx, query, req.BypassCache)
 	if err != nil {
 		return nil, fmt.Errorf("concept identification failed: %w", err)
 	}
@@ -125,29 +305,71 @@ func (s *queryService) processQueryPipeline(ctx context.Context, query *entities
 	query.IdentifiedConcepts = conceptNames
 	result.IdentifiedConcepts = conceptNames
 
-	// Step : Check for new concepts not in the knowledge graph (non-blocking)
-	// Use a background context so this can complete even if the request is cancelled
-	go s.detectAndStageNewConcepts(context.Background(), conceptNames, query)
+	// Check for new concepts not in the knowledge graph (non-blocking) once
+	// any suggested path is known, so it is staged with them. Use a
+	// background context so this can complete even if the request is cancelled
+	stageNewConcepts := func() {
+		s.tasks.Go("stage_new_concepts", func() {
+			s.detectAndStageNewConcepts(context.Background(), conceptNames, query)
+		})
+	}
 
 	// Step 2: Find prerequisite path
-	stepStart = time.Now()
+	stepStart := time.Now()
 	prereqPath, err := s.conceptRepo.FindPrerequisitePath(ctx, conceptNames)
 	query.AddProcessingStep("find_prerequisites", time.Since(stepStart), err == nil, err)
 	if err != nil {
+		stageNewConcepts()
 		return nil, fmt.Errorf("prerequisite path finding failed: %w", err)
 	}
 
 	query.PrerequisitePath = prereqPath
 	result.PrerequisitePath = prereqPath
 
-	// Step 3: Start background resource scraping for concepts (non-blocking)
+	// No curated path: fall back to an LLM-suggested ordering, which is kept
+	// apart from the curated one but guides the explanation
+	explanationPath := prereqPath
+	if len(prereqPath) == 0 {
+		query.SuggestedPath = s.suggestPrerequisitePath(ctx, query, conceptNames)
+		result.SuggestedPath = query.SuggestedPath
+		explanationPath = query.SuggestedPath
+	}
+	stageNewConcepts()
+
+	// Tag the subject category from the concepts already fetched, for analytics
+	if len(conceptNames) > 0 {
+		query.Category = fallback.SelectCategory(prereqPath, conceptNames)
+	}
+
+	// Step 3: Queue background resource scraping for concepts (non-blocking)
 	if s.resourceScraper != nil && len(conceptNames) > 0 {
-		go s.scrapeResourcesAsync(ctx, conceptNames, query.ID)
+		s.scrapeResourcesAsync(ctx, conceptNames, query.ID)
+	}
+
+	// Pick the model profile; it also sets how much context to retrieve
+	profileName, profile := s.router.route(query.Text, conceptNames)
+	var variant experimentVariant
+	if query.Experiment != nil {
+		variant, _ = s.experiment.variant(query.Experiment.Variant)
+	}
+	model := variant.model
+	if query.ModelOverride != "" {
+		model = query.ModelOverride
+	}
+	if model != "" {
+		profile.Model = model
 	}
 
 	// Step 4: Vector search
+	terms, limit := s.searchTerms(conceptNames), profile.ContextChunks
+	if len(req.RetrievalTerms) > 0 {
+		terms = req.RetrievalTerms
+	}
+	if req.ContextChunks > 0 {
+		limit = req.ContextChunks
+	}
 	stepStart = time.Now()
-	vectorResults, err := s.vectorRepo.Search(ctx, query.Text, 5)
+	vectorResults, err := s.searchContext(ctx, query.Text, terms, limit, req.RetrievalSource)
 	query.AddProcessingStep("vector_search", time.Since(stepStart), err == nil, err)
 	if err != nil {
 		s.logger.Warn("Vector search failed", zap.Error(err))
@@ -159,54 +381,349 @@ func (s *queryService) processQueryPipeline(ctx context.Context, query *entities
 		context[i] = vr.Content
 	}
 	result.RetrievedContext = context
+	chunks := entities.NewContextChunks(vectorResults)
 
-	// Step 4: Generate explanation
+	// Step 4: Generate explanation; concepts above stay in English for graph lookup,
+	// only the explanation itself is localized
+	var preferences entities.UserPreferences
+	if query.Preferences != nil {
+		preferences = *query.Preferences
+	}
 	stepStart = time.Now()
 	explanation, err := s.llmClient.GenerateExplanation(ctx, ExplanationRequest{
 		Query:            query.Text,
-		PrerequisitePath: prereqPath,
+		PrerequisitePath: explanationPath,
 		ContextChunks:    context,
+		Language:         services.SupportedLanguages[query.Language],
+		Profile:          profileName,
+		PromptVariant:    variant.prompt,
+		Model:            model,
+		Level:            preferences.ExplanationLevel,
+		Style:            preferences.ExplanationStyle,
 	})
 	query.AddProcessingStep("generate_explanation", time.Since(stepStart), err == nil, err)
 	if err != nil {
-		return nil, fmt.Errorf("explanation generation failed: %w", err)
+		explanation, ok := s.renderFallback(query, conceptNames, prereqPath, err)
+		if !ok {
+			return nil, fmt.Errorf("explanation generation failed: %w", err)
+		}
+
+		query.Response = entities.QueryResponse{
+			Explanation:      explanation,
+			RetrievedContext: context,
+			ContextChunks:    chunks,
+			Fallback:         true,
+		}
+		result.Explanation = explanation
+		result.Fallback = true
+		result.Status = queryStatus(conceptNames, prereqPath, true)
+
+		return result, nil
 	}
 
 	query.Response = entities.QueryResponse{
 		Explanation:      explanation,
 		RetrievedContext: context,
+		ContextChunks:    chunks,
 		LLMProvider:      s.llmClient.Provider(),
-		LLMModel:         s.llmClient.Model(),
+		LLMModel:         s.explanationModel(profile),
+		ModelProfile:     profileName,
+		SourcesUsed:      citedSources(explanation, chunks),
 	}
 	result.Explanation = explanation
+	result.ModelProfile = profileName
+	result.SourcesUsed = query.Response.SourcesUsed
+	result.Status = queryStatus(conceptNames, prereqPath, false)
+
+	// Optional: sanity-check numeric steps of worked examples
+	if s.verifyArithmetic {
+		stepStart = time.Now()
+		report := arithmetic.Check(explanation)
+		query.AddProcessingStep("verify_arithmetic", time.Since(stepStart), true, nil)
+		if report.Status == arithmetic.StatusFlagged {
+			s.logger.Warn("Explanation has arithmetic steps that do not add up",
+				zap.String("query_id", query.ID),
+				zap.Int("issues", len(report.Issues)))
+		}
+		query.Response.ArithmeticCheck = &report
+		result.ArithmeticCheck = &report
+	}
 
 	return result, nil
 }
 
-func (s *queryService) saveQueryAsync(ctx context.Context, query *entities.Query) {
-	go func() {
+// searchTerms returns the identified concepts when search near them is
+// configured, else nil so the question text is searched
+func (s *queryService) searchTerms(conceptNames []string) []string {
+	if !s.searchByConcepts {
+		return nil
+	}
+	return conceptNames
+}
+
+// searchContext retrieves context for the explanation from the requested
+// source, or the configured one when source is empty. Textbook chunks and
+// concept descriptions are searched near terms, each a separate nearText
+// concept, or near the text when there are no terms.
+func (s *queryService) searchContext(ctx context.Context, text string, terms []string, limit int, source string) ([]types.VectorResult, error) {
+	if source == "" {
+		source = s.retrievalSource
+	}
+
+	switch source {
+	case config.RetrievalSourceConcepts:
+		return s.searchConceptContext(ctx, text, terms, limit)
+	case config.RetrievalSourceBoth:
+		textbook, textbookErr := s.searchTextbookContext(ctx, text, terms, limit)
+		concepts, conceptsErr := s.searchConceptContext(ctx, text, terms, limit)
+		if textbookErr != nil && conceptsErr != nil {
+			return nil, fmt.Errorf("textbook search: %v; concept search: %w", textbookErr, conceptsErr)
+		}
+		if textbookErr != nil {
+			s.logger.Warn("Textbook search failed, using concept context only", zap.Error(textbookErr))
+		}
+		if conceptsErr != nil {
+			s.logger.Warn("Concept search failed, using textbook context only", zap.Error(conceptsErr))
+		}
+		return mergeVectorResults(limit, textbook, concepts), nil
+	default:
+		return s.searchTextbookContext(ctx, text, terms, limit)
+	}
+}
+
+func (s *queryService) searchTextbookContext(ctx context.Context, text string, terms []string, limit int) ([]types.VectorResult, error) {
+	if len(terms) > 0 {
+		return s.vectorRepo.SearchByConcepts(ctx, terms, limit)
+	}
+	return s.vectorRepo.Search(ctx, text, limit)
+}
+
+func (s *queryService) searchConceptContext(ctx context.Context, text string, terms []string, limit int) ([]types.VectorResult, error) {
+	if len(terms) == 0 {
+		terms = []string{text}
+	}
+	return s.vectorRepo.SearchConceptVectors(ctx, terms, limit)
+}
+
+// mergeVectorResults combines result lists best score first, dropping
+// repeated content, and keeps at most limit results
+func mergeVectorResults(limit int, lists ...[]types.VectorResult) []types.VectorResult {
+	var merged []types.VectorResult
+	for _, list := range lists {
+		merged = append(merged, list...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+
+	seen := make(map[string]bool, len(merged))
+	results := make([]types.VectorResult, 0, min(len(merged), limit))
+	for _, result := range merged {
+		key := strings.Join(strings.Fields(strings.ToLower(result.Content)), " ")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		results = append(results, result)
+		if len(results) == limit {
+			break
+		}
+	}
+	return results
+}
+
+// explanationModel names the model a profile generates explanations with
+func (s *queryService) explanationModel(profile config.ModelProfile) string {
+	if profile.Model != "" {
+		return profile.Model
+	}
+	return s.llmClient.Model()
+}
+
+// queryStatus classifies a result so clients can tell a full answer apart
+// from one where concepts were missing or the LLM failed
+func queryStatus(conceptNames []string, prereqPath []types.Concept, fallback bool) string {
+	switch {
+	case fallback:
+		return services.QueryStatusDegraded
+	case len(conceptNames) == 0:
+		return services.QueryStatusNoConcepts
+	case len(prereqPath) == 0:
+		return services.QueryStatusConceptsNotFound
+	default:
+		return services.QueryStatusOK
+	}
+}
+
+// renderFallback fills the category template for the query's concepts after
+// the LLM failed to explain them. It reports false when no renderer is
+// configured or rendering fails, leaving the original error to the caller.
+func (s *queryService) renderFallback(query *entities.Query, conceptNames []string, prereqPath []types.Concept, llmErr error) (string, bool) {
+	if s.fallback == nil {
+		return "", false
+	}
+
+	category, explanation, err := s.fallback.Render(query.Text, conceptNames, prereqPath)
+	if err != nil {
+		s.logger.Error("Failed to render fallback explanation",
+			zap.String("query_id", query.ID),
+			zap.Error(err))
+		return "", false
+	}
+
+	s.logger.Warn("Explanation generation failed, serving fallback template",
+		zap.String("query_id", query.ID),
+		zap.String("category", category),
+		zap.Error(llmErr))
+	return explanation, true
+}
+
+// generateVisualAids renders plots for functions in the explanation.
+// Failures are logged and the affected visuals are omitted.
+func (s *queryService) generateVisualAids(ctx context.Context, queryID, explanation string) []*entities.VisualAid {
+	if s.visualAidRepo == nil || explanation == "" {
+		return nil
+	}
+
+	specs, err := s.llmClient.ExtractPlotFunctions(ctx, explanation, maxVisualAids)
+	if err != nil {
+		s.logger.Warn("Failed to extract plot functions, omitting visuals",
+			zap.String("query_id", queryID),
+			zap.Error(err))
+		return nil
+	}
+
+	var visualAids []*entities.VisualAid
+	for _, spec := range specs {
+		xMin, xMax := spec.XMin, spec.XMax
+		if xMin >= xMax {
+			xMin, xMax = visuals.DefaultXMin, visuals.DefaultXMax
+		}
+
+		image, err := visuals.RenderFunctionPlot(spec.Expression, xMin, xMax)
+		if err != nil {
+			s.logger.Warn("Failed to render plot",
+				zap.String("query_id", queryID),
+				zap.String("expression", spec.Expression),
+				zap.Error(err))
+			continue
+		}
+
+		visual := entities.NewVisualAid(queryID, len(visualAids), spec.Title, spec.Expression, xMin, xMax, image)
+		if err := s.visualAidRepo.Save(ctx, visual); err != nil {
+			s.logger.Warn("Failed to store visual aid",
+				zap.String("query_id", queryID),
+				zap.Error(err))
+			continue
+		}
+		visualAids = append(visualAids, visual)
+	}
+
+	return visualAids
+}
+
+// GetVisualAid returns the n-th rendered plot of a query
+func (s *queryService) GetVisualAid(ctx context.Context, queryID string, index int) (*entities.VisualAid, error) {
+	if s.visualAidRepo == nil {
+		return nil, fmt.Errorf("visual aid storage not available")
+	}
+	return s.visualAidRepo.FindByQueryAndIndex(ctx, queryID, index)
+}
+
+// saveQueryAsync stores the query in the background and reports whether it
+// is stored in full. Sampling is decided up front so callers can leave the
+// ID of an unstored query out of their response.
+func (s *queryService) saveQueryAsync(ctx context.Context, query *entities.Query) bool {
+	query = s.storedCopy(query)
+	stored := s.sampler.shouldStore(query)
+	s.tasks.Go("save_query", func() {
 		// Use a new context for the async operation
 		saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
+		// Queries outside the analytics sample only update the aggregate counters
+		if !stored {
+			if err := s.queryRepo.RecordUnsampled(saveCtx, query); err != nil {
+				s.logger.Error("Failed to record unsampled query",
+					zap.Error(err),
+					zap.String("query_id", query.ID))
+			}
+			return
+		}
+
 		if err := s.queryRepo.Save(saveCtx, query); err != nil {
 			s.logger.Error("Failed to save query asynchronously",
 				zap.Error(err),
 				zap.String("query_id", query.ID))
 		}
-	}()
+	})
+	return stored
 }
 
-// scrapeResourcesAsync scrapes educational resources in the background
-func (s *queryService) scrapeResourcesAsync(ctx context.Context, conceptNames []string, queryID string) {
-	s.logger.Info("Starting background resource scraping",
-		zap.String("query_id", queryID),
-		zap.Strings("concepts", conceptNames))
+// storedCopy returns the query as it should be stored: a copy with the
+// explanation trimmed when it exceeds the configured length, leaving the
+// caller's query, and the answer the client gets, whole
+func (s *queryService) storedCopy(query *entities.Query) *entities.Query {
+	explanation, trimmed := entities.TrimExplanation(query.Response.Explanation, s.maxStoredExplanation)
+	if !trimmed {
+		return query
+	}
+	stored := *query
+	stored.Response.Explanation = explanation
+	stored.Response.ExplanationTrimmed = true
+	return &stored
+}
 
-	// Create a background context with timeout for scraping
-	scraperCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
+// submitScrape queues a background scrape on the shared pool so concurrent
+// queries cannot overwhelm the scraper or the sites it reads
+func (s *queryService) submitScrape(name string, fn func(ctx context.Context)) {
+	if !s.scrapePool.Submit(name, fn) {
+		s.logger.Warn("Background scrape queue full, skipping scrape",
+			zap.String("job", name))
+	}
+}
+
+// queueScrape queues a background scrape of concepts. With the persistent
+// queue the job is stored for any instance's workers to claim; otherwise,
+// or when storing it fails, it runs on this instance's pool.
+func (s *queryService) queueScrape(ctx context.Context, source, queryID string, concepts []string) {
+	if s.scrapeJobRepo != nil {
+		job := entities.NewScrapeJob(source, queryID, concepts, s.scrapeMaxAttempts)
+		err := s.scrapeJobRepo.Enqueue(ctx, job)
+		if err == nil {
+			s.logger.Info("Queued background resource scraping",
+				zap.String("job_id", job.ID),
+				zap.String("query_id", queryID),
+				zap.Strings("concepts", concepts))
+			return
+		}
+		s.logger.Warn("Failed to queue scrape job, running it on the pool",
+			zap.String("job", source),
+			zap.Error(err))
+	}
+
+	s.submitScrape(source, func(ctx context.Context) {
+		// Bound each scrape; ctx is cancelled if shutdown gives up waiting
+		scraperCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+
+		if err := s.resourceScraper.ScrapeResourcesForConcepts(scraperCtx, concepts); err != nil {
+			s.logger.Warn("Background resource scraping failed",
+				zap.Error(err),
+				zap.String("job", source),
+				zap.String("query_id", queryID),
+				zap.Strings("concepts", concepts))
+		} else {
+			s.logger.Info("Background resource scraping completed successfully",
+				zap.String("job", source),
+				zap.String("query_id", queryID),
+				zap.Strings("concepts", concepts))
+		}
+	})
+}
 
+// scrapeResourcesAsync queues scraping educational resources for a query's concepts
+func (s *queryService) scrapeResourcesAsync(ctx context.Context, conceptNames []string, queryID string) {
 	// Limit concepts to avoid excessive scraping
 	maxConcepts := 5
 	if len(conceptNames) > maxConcepts {
@@ -216,17 +733,7 @@ func (s *queryService) scrapeResourcesAsync(ctx context.Context, conceptNames []
 			zap.String("query_id", queryID))
 	}
 
-	// Start scraping in background
-	if err := s.resourceScraper.ScrapeResourcesForConcepts(scraperCtx, conceptNames); err != nil {
-		s.logger.Warn("Background resource scraping failed",
-			zap.Error(err),
-			zap.String("query_id", queryID),
-			zap.Strings("concepts", conceptNames))
-	} else {
-		s.logger.Info("Background resource scraping completed successfully",
-			zap.String("query_id", queryID),
-			zap.Strings("concepts", conceptNames))
-	}
+	s.queueScrape(ctx, "scrape_resources", queryID, conceptNames)
 }
 
 // GetResourcesForConcepts retrieves scraped resources for given concepts
@@ -235,8 +742,7 @@ func (s *queryService) GetResourcesForConcepts(ctx context.Context, conceptNames
 		return nil, fmt.Errorf("resource scraper not available")
 	}
 
-	var allResources []scraper.EducationalResource
-
+	groups := make([]conceptResources, 0, len(conceptNames))
 	for _, conceptName := range conceptNames {
 		conceptID := s.generateConceptID(conceptName)
 		resources, err := s.resourceScraper.GetResourcesForConcept(ctx, conceptID, limit)
@@ -246,28 +752,125 @@ func (s *queryService) GetResourcesForConcepts(ctx context.Context, conceptNames
 				zap.Error(err))
 			continue
 		}
-		allResources = append(allResources, resources...)
+		groups = append(groups, conceptResources{concept: conceptName, resources: resources})
+	}
+
+	return mergeConceptResources(groups, limit), nil
+}
+
+// PinResource pins a stored resource so it is listed first for its concept,
+// or unpins it. It returns nil when the resource does not exist.
+func (s *queryService) PinResource(ctx context.Context, resourceID string, pinned bool, order int) (*scraper.EducationalResource, error) {
+	if s.resourceScraper == nil {
+		return nil, fmt.Errorf("resource scraper not available")
+	}
+	return s.resourceScraper.SetResourcePinned(ctx, resourceID, pinned, order)
+}
+
+// BackfillVideoMetadata fetches the thumbnail, duration, channel and publish
+// date of up to limit stored videos that were scraped without them
+func (s *queryService) BackfillVideoMetadata(ctx context.Context, limit int) (*scraper.VideoBackfillResult, error) {
+	if s.resourceScraper == nil {
+		return nil, fmt.Errorf("resource scraper not available")
 	}
+	return s.resourceScraper.BackfillVideoMetadata(ctx, limit)
+}
+
+// conceptResources are the resources stored for one requested concept
+type conceptResources struct {
+	concept   string
+	resources []scraper.EducationalResource
+}
+
+// mergeConceptResources combines per-concept resources into one list with
+// each URL once, keeping its highest-scored copy and recording every concept
+// it was found for. Pinned resources lead by pin order, the rest follow by
+// quality score, and the result is capped at limit.
+func mergeConceptResources(groups []conceptResources, limit int) []scraper.EducationalResource {
+	var merged []scraper.EducationalResource
+	byURL := make(map[string]int)
+
+	for _, group := range groups {
+		for _, resource := range group.resources {
+			key := resourceKey(resource.URL)
+			i, seen := byURL[key]
+			if !seen {
+				resource.RelevantConcepts = []string{group.concept}
+				byURL[key] = len(merged)
+				merged = append(merged, resource)
+				continue
+			}
 
-	// Sort by quality score (descending)
-	for i := 0; i < len(allResources)-1; i++ {
-		for j := 0; j < len(allResources)-i-1; j++ {
-			if allResources[j].QualityScore < allResources[j+1].QualityScore {
-				allResources[j], allResources[j+1] = allResources[j+1], allResources[j]
+			concepts := merged[i].RelevantConcepts
+			if !slices.Contains(concepts, group.concept) {
+				concepts = append(concepts, group.concept)
 			}
+			if resource.QualityScore > merged[i].QualityScore {
+				merged[i] = resource
+			}
+			merged[i].RelevantConcepts = concepts
 		}
 	}
 
-	// Limit total results
-	if len(allResources) > limit {
-		allResources = allResources[:limit]
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Pinned != merged[j].Pinned {
+			return merged[i].Pinned
+		}
+		if merged[i].PinOrder != merged[j].PinOrder {
+			return merged[i].PinOrder < merged[j].PinOrder
+		}
+		if merged[i].QualityScore != merged[j].QualityScore {
+			return merged[i].QualityScore > merged[j].QualityScore
+		}
+		return merged[i].URL < merged[j].URL
+	})
+
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
 	}
+	return merged
+}
 
-	return allResources, nil
+// resourceKey normalizes a URL so trivially different spellings dedupe. Only
+// the scheme and host are case-insensitive; paths and queries such as
+// YouTube video IDs are kept as they are.
+func resourceKey(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.TrimSuffix(rawURL, "/")
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+	parsed.RawPath = strings.TrimSuffix(parsed.RawPath, "/")
+	return parsed.String()
 }
 
 // FindCachedConceptQuery searches for existing queries that match the concept
 func (s *queryService) FindCachedConceptQuery(ctx context.Context, conceptName string) (*entities.Query, error) {
+	return s.findCachedConceptQuery(ctx, conceptName, s.conceptCacheKey(ctx, conceptName))
+}
+
+// findCachedConceptQuery looks the concept up by its cache key, then by name
+// for answers stored before concept keys were recorded
+func (s *queryService) findCachedConceptQuery(ctx context.Context, conceptName, conceptKey string) (*entities.Query, error) {
+	if conceptKey != "" {
+		query, err := s.queryRepo.FindByConceptKey(ctx, conceptKey)
+		if err != nil {
+			s.logger.Warn("Error searching for cached concept by key",
+				zap.String("concept_key", conceptKey),
+				zap.Error(err))
+		} else if query != nil {
+			s.logger.Info("Found cached concept query",
+				zap.String("concept", conceptName),
+				zap.String("concept_key", conceptKey),
+				zap.String("cached_query_id", query.ID),
+				zap.Time("cached_at", query.Timestamp))
+			return query, nil
+		}
+	}
+
 	// Normalize the concept name for better matching
 	normalizedConcept := strings.TrimSpace(strings.ToLower(conceptName))
 
@@ -314,7 +917,8 @@ func (s *queryService) SmartConceptQuery(ctx context.Context, conceptName, userI
 	// Step 1: Try to find cached query for this concept in MongoDB
 	s.logger.Info("Checking MongoDB cache for concept", zap.String("concept", conceptName))
 
-	cachedQuery, err := s.FindCachedConceptQuery(ctx, conceptName)
+	conceptKey := s.conceptCacheKey(ctx, conceptName)
+	cachedQuery, err := s.findCachedConceptQuery(ctx, conceptName, conceptKey)
 	if err != nil {
 		s.logger.Warn("Failed to search MongoDB cache",
 			zap.String("concept", conceptName),
@@ -334,8 +938,8 @@ func (s *queryService) SmartConceptQuery(ctx context.Context, conceptName, userI
 				zap.Time("cached_at", cachedQuery.Timestamp),
 				zap.Duration("cache_age", cacheAge))
 
-			// Start background resource gathering (non-blocking)
-			go s.gatherResourcesInBackground(ctx, conceptName, cachedQuery.IdentifiedConcepts)
+			// Queue background resource gathering (non-blocking)
+			s.gatherResourcesInBackground(ctx, conceptName, cachedQuery.IdentifiedConcepts)
 
 			// Convert cached query to QueryResult
 			result := &services.QueryResult{
@@ -346,7 +950,11 @@ func (s *queryService) SmartConceptQuery(ctx context.Context, conceptName, userI
 				Explanation:        cachedQuery.Response.Explanation,
 				ProcessingTime:     time.Since(startTime),
 				RequestID:          requestID,
+				Source:             services.QuerySourceCache,
+				CacheAge:           &cacheAge,
+				Status:             queryStatus(cachedQuery.IdentifiedConcepts, cachedQuery.PrerequisitePath, cachedQuery.Response.Fallback),
 			}
+			s.applyActiveExplanation(ctx, conceptName, result)
 
 			s.logger.Info("Smart concept query completed from cache",
 				zap.String("concept", conceptName),
@@ -372,10 +980,15 @@ func (s *queryService) SmartConceptQuery(ctx context.Context, conceptName, userI
 	// Use a more specific prompt for better concept explanation
 	conceptQuestion := s.buildConceptQueryPrompt(conceptName)
 
+	// Context is retrieved near the concept itself rather than near the
+	// instruction prompt, which would match any explanatory passage
 	queryReq := &services.QueryRequest{
-		UserID:    userID,
-		Question:  conceptQuestion,
-		RequestID: requestID,
+		UserID:         userID,
+		Question:       conceptQuestion,
+		RequestID:      requestID,
+		RetrievalTerms: s.conceptRetrievalTerms(ctx, conceptName),
+		ContextChunks:  s.conceptQueryChunks,
+		ConceptKey:     conceptKey,
 	}
 
 	// Process the query through the normal pipeline
@@ -386,6 +999,14 @@ func (s *queryService) SmartConceptQuery(ctx context.Context, conceptName, userI
 			zap.Error(err))
 		return nil, fmt.Errorf("failed to process fresh concept query: %w", err)
 	}
+	// The question cache may already have answered the concept prompt
+	if result.Source != services.QuerySourceCache {
+		result.Source = services.QuerySourceProcessed
+		if !result.Fallback {
+			s.recordExplanationVersion(ctx, conceptName, result.Query)
+		}
+	}
+	s.applyActiveExplanation(ctx, conceptName, result)
 
 	s.logger.Info("Smart concept query completed with fresh processing",
 		zap.String("concept", conceptName),
@@ -413,16 +1034,8 @@ Include the following in your explanation:
 Make the explanation educational, detailed, and suitable for students learning this concept.`, conceptName)
 }
 
-// gatherResourcesInBackground starts resource gathering without blocking the response
+// gatherResourcesInBackground queues resource gathering without blocking the response
 func (s *queryService) gatherResourcesInBackground(ctx context.Context, conceptName string, identifiedConcepts []string) {
-	s.logger.Info("Starting background resource gathering",
-		zap.String("concept", conceptName),
-		zap.Strings("identified_concepts", identifiedConcepts))
-
-	// Create a background context with timeout
-	bgCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
-
 	// Use all concepts for resource gathering (both original concept and identified ones)
 	allConcepts := []string{conceptName}
 	allConcepts = append(allConcepts, identifiedConcepts...)
@@ -439,20 +1052,12 @@ func (s *queryService) gatherResourcesInBackground(ctx context.Context, conceptN
 			zap.String("original_concept", conceptName))
 	}
 
-	// Start background scraping
 	if s.resourceScraper != nil {
-		if err := s.resourceScraper.ScrapeResourcesForConcepts(bgCtx, uniqueConcepts); err != nil {
-			s.logger.Warn("Background resource gathering failed",
-				zap.Error(err),
-				zap.String("concept", conceptName),
-				zap.Strings("concepts", uniqueConcepts))
-		} else {
-			s.logger.Info("Background resource gathering completed",
-				zap.String("concept", conceptName),
-				zap.Strings("concepts", uniqueConcepts))
-		}
+		s.queueScrape(ctx, "gather_resources", "", uniqueConcepts)
 	}
-} // generateConceptID creates a standardized concept ID (same logic as scraper)
+}
+
+// generateConceptID creates a standardized concept ID (same logic as scraper)
 func (s *queryService) generateConceptID(conceptName string) string {
 	// Use same logic as scraper to ensure consistency
 	return strings.ToLower(strings.ReplaceAll(conceptName, " ", "_"))
@@ -487,6 +1092,9 @@ func (s *queryService) detectAndStageNewConcepts(ctx context.Context, conceptNam
 	bgCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	// Candidate prerequisite edges for the staged concepts
+	pathPrereqs := suggestedPrerequisiteNames(query.SuggestedPath)
+
 	for _, conceptName := range conceptNames {
 		// Normalize concept name to avoid duplicates with different casing/spacing
 		normalizedConceptName := strings.TrimSpace(strings.ToLower(conceptName))
@@ -518,7 +1126,8 @@ func (s *queryService) detectAndStageNewConcepts(ctx context.Context, conceptNam
 
 		if existing != nil {
 			// Update occurrence count
-			existing.IncrementOccurrence(query.ID)
+			existing.IncrementOccurrence(query.ID, s.demandHalfLife)
+			existing.AddPathSuggestedPrerequisites(pathPrereqs)
 			if err := s.stagedConceptRepo.Update(bgCtx, existing); err != nil {
 				s.logger.Warn("Failed to update staged concept occurrence",
 					zap.String("concept", normalizedConceptName),
@@ -527,6 +1136,7 @@ func (s *queryService) detectAndStageNewConcepts(ctx context.Context, conceptNam
 			s.logger.Info("Incremented occurrence for existing staged concept",
 				zap.String("concept", normalizedConceptName),
 				zap.Int("new_count", existing.OccurrenceCount))
+			s.maybeAutoApprove(bgCtx, existing)
 			continue
 		}
 
@@ -561,6 +1171,9 @@ func (s *queryService) detectAndStageNewConcepts(ctx context.Context, conceptNam
 			analysis.SuggestedCategory,
 			analysis.Reasoning,
 		)
+		staged.LLMConfidence = math.Min(math.Max(analysis.Confidence, 0), 1)
+		staged.PrerequisiteMatches = s.matchSuggestedPrerequisites(bgCtx, analysis.SuggestedPrereqs)
+		staged.AddPathSuggestedPrerequisites(pathPrereqs)
 
 		if err := s.stagedConceptRepo.Save(bgCtx, staged); err != nil {
 			s.logger.Error("Failed to save staged concept",
@@ -575,8 +1188,18 @@ func (s *queryService) detectAndStageNewConcepts(ctx context.Context, conceptNam
 			zap.Int("difficulty", analysis.SuggestedDifficulty),
 			zap.Strings("prerequisites", analysis.SuggestedPrereqs))
 
-		// Send email notification asynchronously using goroutine
-		go s.sendNewConceptNotification(staged, query)
+		// Auto-approved concepts are logged instead of sent for review
+		if s.maybeAutoApprove(bgCtx, staged) {
+			continue
+		}
+
+		// Send email notification asynchronously using goroutine, unless
+		// reviewers only get the digest
+		if s.staging.ConceptEmails {
+			s.tasks.Go("new_concept_notification", func() {
+				s.sendNewConceptNotification(staged, query)
+			})
+		}
 	}
 }
 
@@ -644,6 +1267,10 @@ func (s *queryService) sendNewConceptNotification(staged *entities.StagedConcept
 	}
 }
 
+func (s *queryService) GetStagedConcept(ctx context.Context, stagedID string) (*entities.StagedConcept, error) {
+	return s.stagedConceptRepo.FindByID(ctx, stagedID)
+}
+
 func (s *queryService) GetPendingConcepts(ctx context.Context, limit, offset int) ([]*entities.StagedConcept, error) {
 	return s.stagedConceptRepo.GetPending(ctx, limit, offset)
 }
@@ -661,17 +1288,8 @@ func (s *queryService) ApproveStagedConcept(ctx context.Context, stagedID string
 		return fmt.Errorf("concept has already been reviewed")
 	}
 
-	// Normalize concept ID for consistency
-	conceptID := s.generateConceptID(staged.ConceptName)
-
-	newConcept := types.Concept{
-		ID:            conceptID,
-		Name:          staged.ConceptName,
-		Prerequisites: staged.SuggestedPrerequisites,
-		Difficulty:    staged.SuggestedDifficulty,
-		Category:      staged.SuggestedCategory,
-		Description:   staged.Description,
-	}
+	plan := s.planApproval(ctx, staged)
+	newConcept := plan.Concept
 
 	// Create concept in Neo4j knowledge graph
 	if err := s.conceptRepo.CreateConcept(ctx, &newConcept); err != nil {
@@ -684,41 +1302,30 @@ func (s *queryService) ApproveStagedConcept(ctx context.Context, stagedID string
 			zap.String("concept", newConcept.Name),
 			zap.Strings("prerequisites", staged.SuggestedPrerequisites))
 
-		for _, prereqName := range staged.SuggestedPrerequisites {
-			// Normalize prerequisite ID
-			prereqID := s.generateConceptID(prereqName)
-
-			// Check if prerequisite exists in KG
-			prereqExists, err := s.conceptRepo.ExistsByName(ctx, prereqName)
-			if err != nil {
-				s.logger.Warn("Failed to check prerequisite existence",
-					zap.String("prerequisite", prereqName),
-					zap.Error(err))
-				continue
-			}
-
-			if !prereqExists {
-				s.logger.Warn("Prerequisite concept not found in KG, skipping relationship",
-					zap.String("concept", newConcept.Name),
-					zap.String("prerequisite", prereqName))
+		for _, rel := range plan.Relationships {
+			if rel.Action != services.RelationshipCreate {
 				continue
 			}
 
 			// Create REQUIRES relationship in Neo4j
-			if err := s.conceptRepo.CreatePrerequisiteRelationship(ctx, conceptID, prereqID); err != nil {
+			if err := s.conceptRepo.CreatePrerequisiteRelationship(ctx, newConcept.ID, rel.PrerequisiteID, rel.Weight); err != nil {
 				s.logger.Error("Failed to create prerequisite relationship",
 					zap.String("concept", newConcept.Name),
-					zap.String("prerequisite", prereqName),
+					zap.String("prerequisite", rel.Suggestion),
 					zap.Error(err))
 				// Continue with other relationships even if one fails
 			} else {
 				s.logger.Info("Prerequisite relationship created",
 					zap.String("concept", newConcept.Name),
-					zap.String("prerequisite", prereqName))
+					zap.String("prerequisite", rel.Suggestion))
 			}
 		}
 	}
 
+	// The new concept and its edges change the precomputed prerequisite sets
+	s.invalidatePrerequisiteClosures()
+	s.conceptIndex.Add(conceptEntry(newConcept))
+
 	// Update staged concept status
 	staged.Approve(reviewerID, notes, newConcept.ID)
 	if err := s.stagedConceptRepo.Update(ctx, staged); err != nil {
@@ -798,6 +1405,26 @@ func (s *queryService) GetStagedConceptStats(ctx context.Context) (*repositories
 	return s.stagedConceptRepo.GetStats(ctx)
 }
 
+// GetStaleStagedConcepts reports pending staged concepts older than maxAge
+func (s *queryService) GetStaleStagedConcepts(ctx context.Context, maxAge time.Duration) (*repositories.StaleStagedConceptStats, error) {
+	now := time.Now()
+	stats, err := s.stagedConceptRepo.GetStaleStats(ctx, now.Add(-maxAge))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale staged concepts: %w", err)
+	}
+
+	stats.MaxAgeDays = maxAge.Hours() / 24
+	if stats.OldestPending != nil {
+		stats.OldestAgeSeconds = now.Sub(stats.OldestPending.IdentifiedAt).Seconds()
+	}
+
+	return stats, nil
+}
+
+func (s *queryService) GetConceptNeighborhood(ctx context.Context, conceptID string, depth, maxNodes int) (*types.ConceptNeighborhood, error) {
+	return s.conceptRepo.GetNeighborhood(ctx, conceptID, depth, maxNodes)
+}
+
 func (s *queryService) GetConceptDetail(ctx context.Context, conceptID string) (*types.ConceptDetailResult, error) {
 	return s.conceptRepo.GetConceptDetail(ctx, conceptID)
 }
@@ -806,25 +1433,42 @@ func (s *queryService) GetAllConcepts(ctx context.Context) ([]types.Concept, err
 	return s.conceptRepo.GetAll(ctx)
 }
 
+// ListConcepts returns one page of concepts matching filter and the total
+// number of matches. Resources are stored outside the graph, so filtering on
+// them first resolves which concepts have any.
+func (s *queryService) ListConcepts(ctx context.Context, filter repositories.ConceptFilter) ([]types.Concept, int, error) {
+	if filter.HasResources != nil {
+		if s.resourceScraper == nil {
+			return nil, 0, fmt.Errorf("resource storage not available")
+		}
+		ids, err := s.resourceScraper.ConceptIDsWithResources(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.ResourceConceptIDs = ids
+	}
+	return s.conceptRepo.List(ctx, filter)
+}
+
 func (s *queryService) GetQueryStats(ctx context.Context) (*repositories.QueryStats, error) {
 	return s.queryRepo.GetQueryStats(ctx)
 }
 
-func (s *queryService) GetPopularConcepts(ctx context.Context, limit int) ([]repositories.ConceptPopularity, error) {
-	return s.queryRepo.GetPopularConcepts(ctx, limit)
+func (s *queryService) GetConceptPairs(ctx context.Context, limit int) ([]repositories.ConceptPair, error) {
+	return s.queryRepo.GetConceptPairs(ctx, limit)
 }
 
-func (s *queryService) GetQueryTrends(ctx context.Context, days int) ([]repositories.QueryTrend, error) {
-	return s.queryRepo.GetQueryTrends(ctx, days)
+func (s *queryService) GetPopularConcepts(ctx context.Context, limit int, halfLife time.Duration) ([]repositories.ConceptPopularity, error) {
+	return s.queryRepo.GetPopularConcepts(ctx, limit, halfLife)
 }
 
-func (s *queryService) GetSystemStats(ctx context.Context) (*types.SystemStats, error) {
-	return s.conceptRepo.GetStats(ctx)
+func (s *queryService) GetQueryTrends(ctx context.Context, days int) ([]repositories.QueryTrend, error) {
+	return s.queryRepo.GetQueryTrends(ctx, days)
 }
 
-// GetCachedConcepts returns a list of all cached concept queries for debugging
+// GetCachedConcepts returns the most recent stored queries of every user for debugging
 func (s *queryService) GetCachedConcepts(ctx context.Context, limit int) ([]entities.Query, error) {
-	queries, err := s.queryRepo.FindByUserID(ctx, "", limit)
+	queries, _, err := s.queryRepo.List(ctx, repositories.QueryListFilter{Limit: limit})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cached concepts: %w", err)
 	}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/fallback"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// missingPathConceptRepo knows none of the identified concepts
+type missingPathConceptRepo struct {
+	repositories.ConceptRepository
+}
+
+func (r *missingPathConceptRepo) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
+	return nil, nil
+}
+
+func (r *missingPathConceptRepo) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return true, nil
+}
+
+func TestProcessQueryStatus(t *testing.T) {
+	renderer, err := fallback.NewRenderer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answering := &recordingLLM{
+		concepts:    []string{"derivatives"},
+		explanation: map[string]string{"English": "A derivative measures change."},
+	}
+
+	tests := []struct {
+		name        string
+		llm         LLMClient
+		conceptRepo repositories.ConceptRepository
+		want        string
+	}{
+		{"ok", answering, &pathConceptRepo{}, services.QueryStatusOK},
+		{"no concepts", &recordingLLM{explanation: map[string]string{"English": "Hello"}}, &pathConceptRepo{}, services.QueryStatusNoConcepts},
+		{"concepts not in graph", answering, &missingPathConceptRepo{}, services.QueryStatusConceptsNotFound},
+		{"degraded", &unavailableLLM{recordingLLM{concepts: []string{"derivatives"}}}, &pathConceptRepo{}, services.QueryStatusDegraded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := background.NewTasks()
+			svc := &queryService{
+				conceptRepo: tt.conceptRepo,
+				queryRepo:   &savingQueryRepo{},
+				vectorRepo:  &stubVectorRepo{},
+				llmClient:   tt.llm,
+				fallback:    renderer,
+				sampler:     &analyticsSampler{sampleRate: 1},
+				tasks:       tasks,
+				logger:      zap.NewNop(),
+			}
+
+			result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "What is a derivative?"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			drainTasks(t, tasks)
+
+			if result.Status != tt.want {
+				t.Errorf("Status = %q, want %q", result.Status, tt.want)
+			}
+		})
+	}
+}
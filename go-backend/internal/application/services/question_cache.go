@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// cachedAnswer returns the stored answer to the same normalized question in
+// the same language when the question cache is on and the answer is younger
+// than the TTL. Requests for visuals skip the cache since plots are stored
+// per query, as do requests overriding the retrieval source, whose answer
+// may differ from the cached one, and tailored requests of users with
+// explanation preferences. Lookup failures are logged and treated as misses.
+func (s *queryService) cachedAnswer(ctx context.Context, req *services.QueryRequest, questionKey, language string, tailored bool) *services.QueryResult {
+	if !s.questionCache.Enabled || req.BypassCache || req.IncludeVisuals || req.RetrievalSource != "" || tailored || s.queryRepo == nil {
+		return nil
+	}
+
+	cached, err := s.queryRepo.FindByQuestionKey(ctx, questionKey, language, time.Now().Add(-s.questionCache.TTL))
+	if err != nil {
+		s.logger.Warn("Question cache lookup failed, processing fresh query", zap.Error(err))
+		return nil
+	}
+	if cached == nil {
+		return nil
+	}
+
+	cacheAge := time.Since(cached.Timestamp)
+	s.logger.Info("Answering repeated question from cache",
+		zap.String("cached_query_id", cached.ID),
+		zap.Duration("cache_age", cacheAge))
+
+	return &services.QueryResult{
+		Query:              cached,
+		IdentifiedConcepts: cached.IdentifiedConcepts,
+		PrerequisitePath:   cached.PrerequisitePath,
+		SuggestedPath:      cached.SuggestedPath,
+		Explanation:        cached.Response.Explanation,
+		RetrievedContext:   cached.Response.RetrievedContext,
+		RequestID:          req.RequestID,
+		Source:             services.QuerySourceCache,
+		CacheAge:           &cacheAge,
+		Status:             queryStatus(cached.IdentifiedConcepts, cached.PrerequisitePath, false),
+		ModelProfile:       cached.Response.ModelProfile,
+		ArithmeticCheck:    cached.Response.ArithmeticCheck,
+		SourcesUsed:        cached.Response.SourcesUsed,
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// questionCacheRepo serves FindByQuestionKey from the queries saved so far
+type questionCacheRepo struct {
+	savingQueryRepo
+}
+
+func (r *questionCacheRepo) FindByQuestionKey(ctx context.Context, questionKey, language string, since time.Time) (*entities.Query, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.saved) - 1; i >= 0; i-- {
+		q := r.saved[i]
+		if q.QuestionKey == questionKey && q.Language == language && q.Success && !q.Timestamp.Before(since) {
+			return q, nil
+		}
+	}
+	return nil, nil
+}
+
+func newQuestionCacheService(cache config.QueryCacheConfig) (*queryService, *recordingLLM, *questionCacheRepo, *background.Tasks) {
+	llm := &recordingLLM{
+		concepts:    []string{"limits"},
+		explanation: map[string]string{"English": "A limit describes the value a function approaches."},
+	}
+	queryRepo := &questionCacheRepo{}
+	tasks := background.NewTasks()
+	return &queryService{
+		conceptRepo:   &pathConceptRepo{},
+		queryRepo:     queryRepo,
+		vectorRepo:    &stubVectorRepo{},
+		llmClient:     llm,
+		sampler:       &analyticsSampler{sampleRate: 1},
+		questionCache: cache,
+		tasks:         tasks,
+		logger:        zap.NewNop(),
+	}, llm, queryRepo, tasks
+}
+
+func ask(t *testing.T, svc *queryService, tasks *background.Tasks, req *services.QueryRequest) *services.QueryResult {
+	t.Helper()
+	result, err := svc.ProcessQuery(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+	return result
+}
+
+func TestProcessQueryAnswersRepeatedQuestionFromCache(t *testing.T) {
+	svc, llm, _, tasks := newQuestionCacheService(config.QueryCacheConfig{Enabled: true, TTL: time.Hour})
+
+	first := ask(t, svc, tasks, &services.QueryRequest{Question: "What is a limit?"})
+	second := ask(t, svc, tasks, &services.QueryRequest{Question: "  what IS a limit ", RequestID: "req-2"})
+
+	if len(llm.requests) != 1 {
+		t.Fatalf("expected the repeated question to skip the LLM, got %d explanation calls", len(llm.requests))
+	}
+	if second.Source != services.QuerySourceCache || second.CacheAge == nil {
+		t.Errorf("expected a cached result with its age, got source %q", second.Source)
+	}
+	if second.Explanation != first.Explanation || second.Query.ID != first.Query.ID {
+		t.Errorf("expected the first answer to be served, got %+v", second)
+	}
+	if second.RequestID != "req-2" || second.Status != services.QueryStatusOK {
+		t.Errorf("unexpected cached result metadata: request %q, status %q", second.RequestID, second.Status)
+	}
+}
+
+func TestProcessQueryBypassesQuestionCache(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache config.QueryCacheConfig
+		req   services.QueryRequest
+	}{
+		{"bypass flag", config.QueryCacheConfig{Enabled: true, TTL: time.Hour}, services.QueryRequest{BypassCache: true}},
+		{"cache disabled", config.QueryCacheConfig{TTL: time.Hour}, services.QueryRequest{}},
+		{"other language", config.QueryCacheConfig{Enabled: true, TTL: time.Hour}, services.QueryRequest{Language: "es"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, llm, _, tasks := newQuestionCacheService(tt.cache)
+			llm.explanation["Spanish"] = "Un límite..."
+
+			ask(t, svc, tasks, &services.QueryRequest{Question: "What is a limit?"})
+			req := tt.req
+			req.Question = "What is a limit?"
+			result := ask(t, svc, tasks, &req)
+
+			if len(llm.requests) != 2 {
+				t.Errorf("expected a fresh answer, got %d explanation calls", len(llm.requests))
+			}
+			if result.Source == services.QuerySourceCache {
+				t.Error("expected the result not to come from cache")
+			}
+		})
+	}
+}
+
+func TestProcessQueryIgnoresExpiredAnswers(t *testing.T) {
+	svc, llm, queryRepo, tasks := newQuestionCacheService(config.QueryCacheConfig{Enabled: true, TTL: time.Hour})
+
+	ask(t, svc, tasks, &services.QueryRequest{Question: "What is a limit?"})
+	queryRepo.saved[0].Timestamp = time.Now().Add(-2 * time.Hour)
+	ask(t, svc, tasks, &services.QueryRequest{Question: "What is a limit?"})
+
+	if len(llm.requests) != 2 {
+		t.Errorf("expected an expired answer to be refreshed, got %d explanation calls", len(llm.requests))
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mathprereq/internal/data/scraper"
+)
+
+func TestMergeConceptResourcesDeduplicatesByURL(t *testing.T) {
+	groups := []conceptResources{
+		{concept: "derivatives", resources: []scraper.EducationalResource{
+			{URL: "https://example.com/chain-rule", Title: "Chain rule", QualityScore: 0.6},
+			{URL: "https://example.com/limits", Title: "Limits", QualityScore: 0.7},
+		}},
+		{concept: "chain rule", resources: []scraper.EducationalResource{
+			{URL: "https://Example.com/chain-rule/", Title: "Chain rule (rescored)", QualityScore: 0.9},
+			{URL: "https://example.com/composition", Title: "Composition", QualityScore: 0.5},
+		}},
+		{concept: "limits", resources: []scraper.EducationalResource{
+			{URL: "https://example.com/limits", Title: "Limits (older)", QualityScore: 0.4},
+		}},
+	}
+
+	merged := mergeConceptResources(groups, 10)
+
+	var titles []string
+	for _, resource := range merged {
+		titles = append(titles, resource.Title)
+	}
+	wantTitles := []string{"Chain rule (rescored)", "Limits", "Composition"}
+	if !reflect.DeepEqual(titles, wantTitles) {
+		t.Fatalf("titles = %v, want %v", titles, wantTitles)
+	}
+
+	wantConcepts := [][]string{
+		{"derivatives", "chain rule"},
+		{"derivatives", "limits"},
+		{"chain rule"},
+	}
+	for i, resource := range merged {
+		if !reflect.DeepEqual(resource.RelevantConcepts, wantConcepts[i]) {
+			t.Errorf("%s relevant concepts = %v, want %v", resource.Title, resource.RelevantConcepts, wantConcepts[i])
+		}
+	}
+}
+
+func TestMergeConceptResourcesAppliesLimitAfterDedup(t *testing.T) {
+	groups := []conceptResources{
+		{concept: "a", resources: []scraper.EducationalResource{
+			{URL: "https://example.com/1", QualityScore: 0.9},
+			{URL: "https://example.com/2", QualityScore: 0.8},
+		}},
+		{concept: "b", resources: []scraper.EducationalResource{
+			{URL: "https://example.com/1", QualityScore: 0.9},
+			{URL: "https://example.com/3", QualityScore: 0.7},
+		}},
+	}
+
+	merged := mergeConceptResources(groups, 2)
+	if len(merged) != 2 || merged[0].URL != "https://example.com/1" || merged[1].URL != "https://example.com/2" {
+		t.Fatalf("unexpected merged resources: %+v", merged)
+	}
+}
+
+func TestMergeConceptResourcesListsPinnedFirst(t *testing.T) {
+	groups := []conceptResources{
+		{concept: "derivatives", resources: []scraper.EducationalResource{
+			{URL: "https://example.com/best", Title: "Best scraped", QualityScore: 0.95},
+			{URL: "https://example.com/vetted-2", Title: "Vetted second", QualityScore: 0.3, Pinned: true, PinOrder: 2},
+		}},
+		{concept: "limits", resources: []scraper.EducationalResource{
+			{URL: "https://example.com/vetted-1", Title: "Vetted first", QualityScore: 0.2, Pinned: true, PinOrder: 1},
+			{URL: "https://example.com/good", Title: "Good scraped", QualityScore: 0.8},
+		}},
+	}
+
+	var titles []string
+	for _, resource := range mergeConceptResources(groups, 3) {
+		titles = append(titles, resource.Title)
+	}
+	want := []string{"Vetted first", "Vetted second", "Best scraped"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("titles = %v, want %v", titles, want)
+	}
+}
+
+func TestResourceKey(t *testing.T) {
+	same := [][2]string{
+		{"https://Example.com/chain-rule/", "https://example.com/chain-rule"},
+		{" HTTPS://WWW.YOUTUBE.COM/watch?v=dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+	}
+	for _, urls := range same {
+		if resourceKey(urls[0]) != resourceKey(urls[1]) {
+			t.Errorf("%q and %q should share a key", urls[0], urls[1])
+		}
+	}
+
+	different := [][2]string{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dqw4w9wgxcq"},
+		{"https://example.com/Notes/Limits", "https://example.com/notes/limits"},
+	}
+	for _, urls := range different {
+		if resourceKey(urls[0]) == resourceKey(urls[1]) {
+			t.Errorf("%q and %q differ only in case-sensitive parts but share a key", urls[0], urls[1])
+		}
+	}
+}
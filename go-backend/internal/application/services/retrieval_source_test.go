@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// sourceVectorRepo answers from fixed textbook and concept results and
+// records which class each search went to
+type sourceVectorRepo struct {
+	repositories.VectorRepository
+	textbook []types.VectorResult
+	concepts []types.VectorResult
+	searched []string
+}
+
+func (r *sourceVectorRepo) Search(ctx context.Context, query string, limit int) ([]types.VectorResult, error) {
+	r.searched = append(r.searched, "textbook")
+	return r.textbook, nil
+}
+
+func (r *sourceVectorRepo) SearchByConcepts(ctx context.Context, concepts []string, limit int) ([]types.VectorResult, error) {
+	r.searched = append(r.searched, "textbook")
+	return r.textbook, nil
+}
+
+func (r *sourceVectorRepo) SearchConceptVectors(ctx context.Context, terms []string, limit int) ([]types.VectorResult, error) {
+	r.searched = append(r.searched, "concepts")
+	return r.concepts, nil
+}
+
+func newSourceVectorRepo() *sourceVectorRepo {
+	return &sourceVectorRepo{
+		textbook: []types.VectorResult{
+			{Content: "The chain rule differentiates compositions.", Score: 0.9},
+			{Content: "Limits: the value a function approaches", Score: 0.6},
+		},
+		concepts: []types.VectorResult{
+			{Content: "Chain Rule: derivative of a composite function", Score: 0.8},
+			{Content: "limits:  the value a function approaches", Score: 0.7},
+		},
+	}
+}
+
+func TestSearchContextUsesSelectedSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultSrc   string
+		override     string
+		wantSearched []string
+	}{
+		{"default textbook", config.RetrievalSourceTextbook, "", []string{"textbook"}},
+		{"default concepts", config.RetrievalSourceConcepts, "", []string{"concepts"}},
+		{"override concepts", config.RetrievalSourceTextbook, config.RetrievalSourceConcepts, []string{"concepts"}},
+		{"override textbook", config.RetrievalSourceConcepts, config.RetrievalSourceTextbook, []string{"textbook"}},
+		{"both", config.RetrievalSourceTextbook, config.RetrievalSourceBoth, []string{"textbook", "concepts"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newSourceVectorRepo()
+			svc := &queryService{vectorRepo: repo, retrievalSource: tt.defaultSrc, logger: zap.NewNop()}
+
+			if _, err := svc.searchContext(context.Background(), "chain rule?", []string{"chain rule"}, 5, tt.override); err != nil {
+				t.Fatal(err)
+			}
+			if len(repo.searched) != len(tt.wantSearched) {
+				t.Fatalf("searched %v, want %v", repo.searched, tt.wantSearched)
+			}
+			for i := range tt.wantSearched {
+				if repo.searched[i] != tt.wantSearched[i] {
+					t.Errorf("searched %v, want %v", repo.searched, tt.wantSearched)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchContextBothMergesAndDedupes(t *testing.T) {
+	svc := &queryService{vectorRepo: newSourceVectorRepo(), logger: zap.NewNop()}
+
+	results, err := svc.searchContext(context.Background(), "chain rule?", nil, 3, config.RetrievalSourceBoth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"The chain rule differentiates compositions.",
+		"Chain Rule: derivative of a composite function",
+		"limits:  the value a function approaches",
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %+v", len(want), results)
+	}
+	for i := range want {
+		if results[i].Content != want[i] {
+			t.Errorf("result %d = %q, want %q", i, results[i].Content, want[i])
+		}
+	}
+}
+
+func TestProcessQueryRetrievalSourceOverride(t *testing.T) {
+	tasks := background.NewTasks()
+	repo := newSourceVectorRepo()
+	svc := &queryService{
+		conceptRepo:     &pathConceptRepo{},
+		queryRepo:       &savingQueryRepo{},
+		vectorRepo:      repo,
+		retrievalSource: config.RetrievalSourceTextbook,
+		llmClient: &recordingLLM{
+			concepts:    []string{"chain rule"},
+			explanation: map[string]string{"English": "Differentiate the outer function first."},
+		},
+		sampler: &analyticsSampler{sampleRate: 1},
+		tasks:   tasks,
+		logger:  zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{
+		Question:        "How does the chain rule work?",
+		RetrievalSource: config.RetrievalSourceConcepts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if len(repo.searched) != 1 || repo.searched[0] != "concepts" {
+		t.Errorf("searched %v, want only the concept class", repo.searched)
+	}
+	if len(result.RetrievedContext) != 2 || result.RetrievedContext[0] != repo.concepts[0].Content {
+		t.Errorf("RetrievedContext = %q, want the concept results", result.RetrievedContext)
+	}
+
+	if _, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{
+		Question:        "How does the chain rule work?",
+		RetrievalSource: "wiki",
+	}); err == nil {
+		t.Error("expected an unknown retrieval source to be rejected")
+	}
+}
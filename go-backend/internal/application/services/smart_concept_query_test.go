@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// cachingQueryRepo serves cached concept queries by exact concept name
+type cachingQueryRepo struct {
+	savingQueryRepo
+	cached map[string]*entities.Query
+}
+
+func (r *cachingQueryRepo) FindByConceptName(ctx context.Context, conceptName string) (*entities.Query, error) {
+	return r.cached[conceptName], nil
+}
+
+func (r *cachingQueryRepo) FindByConceptKey(ctx context.Context, conceptKey string) (*entities.Query, error) {
+	return nil, nil
+}
+
+func newSmartQueryService(repo *cachingQueryRepo, tasks *background.Tasks) *queryService {
+	return &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   repo,
+		vectorRepo:  &stubVectorRepo{},
+		llmClient: &recordingLLM{
+			concepts:    []string{"limits"},
+			explanation: map[string]string{"English": "A limit describes..."},
+		},
+		sampler: &analyticsSampler{sampleRate: 1},
+		tasks:   tasks,
+		logger:  zap.NewNop(),
+	}
+}
+
+func drainTasks(t *testing.T, tasks *background.Tasks) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if pending := tasks.Wait(ctx); len(pending) > 0 {
+		t.Fatalf("background tasks did not finish: %v", pending)
+	}
+}
+
+func TestSmartConceptQueryReportsCacheSource(t *testing.T) {
+	cached := entities.NewQuery("", "Explain limits", "")
+	cached.Timestamp = time.Now().Add(-2 * time.Hour)
+	cached.Response.Explanation = "cached explanation"
+
+	tasks := background.NewTasks()
+	svc := newSmartQueryService(&cachingQueryRepo{cached: map[string]*entities.Query{"limits": cached}}, tasks)
+
+	result, err := svc.SmartConceptQuery(context.Background(), "limits", "", "req-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if result.Source != services.QuerySourceCache {
+		t.Errorf("expected source %q, got %q", services.QuerySourceCache, result.Source)
+	}
+	if result.CacheAge == nil || *result.CacheAge < 2*time.Hour || *result.CacheAge > 3*time.Hour {
+		t.Errorf("expected cache age of about 2h, got %v", result.CacheAge)
+	}
+}
+
+func TestSmartConceptQueryReportsProcessedSource(t *testing.T) {
+	tasks := background.NewTasks()
+	svc := newSmartQueryService(&cachingQueryRepo{}, tasks)
+
+	result, err := svc.SmartConceptQuery(context.Background(), "limits", "", "req-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if result.Source != services.QuerySourceProcessed {
+		t.Errorf("expected source %q, got %q", services.QuerySourceProcessed, result.Source)
+	}
+	if result.CacheAge != nil {
+		t.Errorf("expected no cache age for a processed query, got %v", *result.CacheAge)
+	}
+}
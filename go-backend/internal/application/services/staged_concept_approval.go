@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// PreviewStagedConceptApproval reports the concept node and prerequisite
+// relationships approving a staged concept would create, and whether any of
+// them would close a prerequisite cycle. Nothing is written.
+func (s *queryService) PreviewStagedConceptApproval(ctx context.Context, stagedID string) (*services.ApprovalPreview, error) {
+	staged, err := s.stagedConceptRepo.FindByID(ctx, stagedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find staged concept: %w", err)
+	}
+	if staged == nil {
+		return nil, fmt.Errorf("staged concept not found")
+	}
+	if staged.Status != entities.StagedConceptStatusPending {
+		return nil, fmt.Errorf("concept has already been reviewed")
+	}
+
+	return s.previewApproval(ctx, staged)
+}
+
+// previewApproval plans a staged concept's approval and checks the plan
+// against the graph
+func (s *queryService) previewApproval(ctx context.Context, staged *entities.StagedConcept) (*services.ApprovalPreview, error) {
+	preview := s.planApproval(ctx, staged)
+
+	var err error
+	preview.ConceptExists, err = s.conceptRepo.ExistsByName(ctx, staged.ConceptName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check concept existence: %w", err)
+	}
+
+	for i := range preview.Relationships {
+		rel := &preview.Relationships[i]
+		if rel.Action != services.RelationshipCreate {
+			continue
+		}
+		rel.CreatesCycle = s.closesCycle(ctx, preview.Concept.ID, rel.PrerequisiteID)
+		preview.CreatesCycle = preview.CreatesCycle || rel.CreatesCycle
+	}
+
+	return preview, nil
+}
+
+// planApproval builds the concept and the prerequisite relationships
+// ApproveStagedConcept creates for a staged concept. Suggestions that are
+// neither matched during staging nor present in the graph are skipped, and
+// so are matches whose concept has since left the graph.
+func (s *queryService) planApproval(ctx context.Context, staged *entities.StagedConcept) *services.ApprovalPreview {
+	plan := &services.ApprovalPreview{
+		StagedID: staged.ID,
+		Concept: types.Concept{
+			// Normalize concept ID for consistency
+			ID:            s.generateConceptID(staged.ConceptName),
+			Name:          staged.ConceptName,
+			Prerequisites: staged.SuggestedPrerequisites,
+			Difficulty:    staged.SuggestedDifficulty,
+			Category:      staged.SuggestedCategory,
+			Description:   staged.Description,
+		},
+		Relationships: make([]services.PlannedRelationship, 0, len(staged.SuggestedPrerequisites)),
+	}
+
+	for _, prereqName := range staged.SuggestedPrerequisites {
+		rel := services.PlannedRelationship{
+			Suggestion: prereqName,
+			Action:     services.RelationshipCreate,
+			Weight:     types.DefaultPrerequisiteWeight,
+		}
+
+		// Prefer the concept matched by embedding similarity during staging
+		if rel.PrerequisiteID = staged.MatchedPrerequisiteID(prereqName); rel.PrerequisiteID != "" {
+			rel.Matched = true
+			// The matched concept may have been merged or deleted since staging
+			matched, err := s.conceptRepo.FindByID(ctx, rel.PrerequisiteID)
+			switch {
+			case err != nil:
+				s.logger.Warn("Failed to check matched prerequisite existence",
+					zap.String("prerequisite_id", rel.PrerequisiteID),
+					zap.Error(err))
+				rel.Action, rel.Reason = services.RelationshipSkip, "existence check failed"
+			case matched == nil:
+				s.logger.Warn("Matched prerequisite no longer in KG, skipping relationship",
+					zap.String("concept", staged.ConceptName),
+					zap.String("prerequisite_id", rel.PrerequisiteID))
+				rel.Action, rel.Reason = services.RelationshipSkip, "matched prerequisite no longer in graph"
+			}
+			plan.Relationships = append(plan.Relationships, rel)
+			continue
+		}
+
+		rel.PrerequisiteID = s.generateConceptID(prereqName)
+		exists, err := s.conceptRepo.ExistsByName(ctx, prereqName)
+		switch {
+		case err != nil:
+			s.logger.Warn("Failed to check prerequisite existence",
+				zap.String("prerequisite", prereqName),
+				zap.Error(err))
+			rel.Action, rel.Reason = services.RelationshipSkip, "existence check failed"
+		case !exists:
+			s.logger.Warn("Prerequisite concept not found in KG, skipping relationship",
+				zap.String("concept", staged.ConceptName),
+				zap.String("prerequisite", prereqName))
+			rel.Action, rel.Reason = services.RelationshipSkip, "prerequisite not in graph"
+		}
+		plan.Relationships = append(plan.Relationships, rel)
+	}
+
+	return plan
+}
+
+// closesCycle reports whether making prerequisiteID a prerequisite of
+// conceptID would close a cycle, i.e. conceptID is already upstream of it
+func (s *queryService) closesCycle(ctx context.Context, conceptID, prerequisiteID string) bool {
+	if conceptID == prerequisiteID {
+		return true
+	}
+
+	upstream, _, err := s.prerequisiteClosure(ctx, prerequisiteID)
+	if err != nil {
+		s.logger.Warn("Failed to load prerequisite set for cycle check",
+			zap.String("prerequisite_id", prerequisiteID),
+			zap.Error(err))
+		return false
+	}
+	for _, id := range upstream {
+		if id == conceptID {
+			return true
+		}
+	}
+	return false
+}
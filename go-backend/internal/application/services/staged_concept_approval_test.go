@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/autocomplete"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// ExistsByName matches concept IDs, which double as lowercase names in these
+// graphs.
+// CreateConcept and CreatePrerequisiteRelationship are not implemented, so a
+// preview that writes panics.
+func (r *graphConceptRepo) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return slices.Contains(r.ids, strings.ToLower(name)), nil
+}
+
+func (r *memoryStagedConceptRepo) FindByID(ctx context.Context, id string) (*entities.StagedConcept, error) {
+	for _, c := range r.concepts {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func previewApproval(t *testing.T, graph *graphConceptRepo, staged *entities.StagedConcept) *services.ApprovalPreview {
+	t.Helper()
+	svc := &queryService{
+		conceptRepo:       graph,
+		stagedConceptRepo: &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{staged}},
+		logger:            zap.NewNop(),
+	}
+	preview, err := svc.PreviewStagedConceptApproval(context.Background(), staged.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return preview
+}
+
+func TestPreviewApprovalSkipsMissingPrerequisites(t *testing.T) {
+	graph := &graphConceptRepo{ids: []string{"functions", "limits", "real_numbers"}}
+	graph.addEdge("functions", "limits")
+
+	staged := entities.NewStagedConcept("Series", "Sums of sequences", "q1", "", "",
+		[]string{"limits", "sequences", "number line"}, 3, "calculus", "")
+	staged.PrerequisiteMatches = []entities.PrerequisiteMatch{
+		{Suggestion: "number line", MatchedConceptID: "real_numbers", Similarity: 0.9},
+	}
+
+	preview := previewApproval(t, graph, staged)
+
+	if preview.Concept.ID != "series" || preview.Concept.Difficulty != 3 || preview.Concept.Category != "calculus" {
+		t.Errorf("unexpected concept %+v", preview.Concept)
+	}
+	if preview.ConceptExists || preview.CreatesCycle {
+		t.Errorf("expected a new acyclic concept, got %+v", preview)
+	}
+
+	want := []services.PlannedRelationship{
+		{Suggestion: "limits", PrerequisiteID: "limits", Action: services.RelationshipCreate, Weight: 1},
+		{Suggestion: "sequences", PrerequisiteID: "sequences", Action: services.RelationshipSkip, Reason: "prerequisite not in graph", Weight: 1},
+		{Suggestion: "number line", PrerequisiteID: "real_numbers", Matched: true, Action: services.RelationshipCreate, Weight: 1},
+	}
+	if !slices.Equal(preview.Relationships, want) {
+		t.Errorf("relationships = %+v, want %+v", preview.Relationships, want)
+	}
+	if staged.Status != entities.StagedConceptStatusPending {
+		t.Errorf("preview changed the staged concept status to %s", staged.Status)
+	}
+}
+
+func TestPreviewApprovalDetectsCycle(t *testing.T) {
+	// limits -> derivatives -> integrals; staging "limits" again with
+	// integrals as its prerequisite would loop back on itself
+	graph := &graphConceptRepo{ids: []string{"functions", "limits", "derivatives", "integrals"}}
+	graph.addEdge("functions", "limits")
+	graph.addEdge("limits", "derivatives")
+	graph.addEdge("derivatives", "integrals")
+
+	staged := entities.NewStagedConcept("Limits", "", "q1", "", "",
+		[]string{"integrals", "functions"}, 2, "calculus", "")
+
+	preview := previewApproval(t, graph, staged)
+
+	if !preview.ConceptExists {
+		t.Error("expected the duplicate concept to be reported")
+	}
+	if !preview.CreatesCycle {
+		t.Fatal("expected a cycle to be reported")
+	}
+	if rel := preview.Relationships[0]; !rel.CreatesCycle {
+		t.Errorf("expected integrals to close the cycle, got %+v", rel)
+	}
+	if rel := preview.Relationships[1]; rel.CreatesCycle {
+		t.Errorf("functions is already upstream and should not close a cycle, got %+v", rel)
+	}
+}
+
+func TestApproveStagedConceptSkipsVanishedMatch(t *testing.T) {
+	// "real_numbers" was matched during staging and has since been merged away
+	graph := &approvingConceptRepo{graphConceptRepo: &graphConceptRepo{ids: []string{"limits", "sets"}}}
+	staged := entities.NewStagedConcept("Series", "Sums of sequences", "q1", "", "",
+		[]string{"limits", "number line", "set theory"}, 3, "calculus", "")
+	staged.PrerequisiteMatches = []entities.PrerequisiteMatch{
+		{Suggestion: "number line", MatchedConceptID: "real_numbers", Similarity: 0.9},
+		{Suggestion: "set theory", MatchedConceptID: "sets", Similarity: 0.88},
+	}
+	svc := &queryService{
+		conceptRepo:       graph,
+		stagedConceptRepo: &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{staged}},
+		conceptIndex:      autocomplete.NewIndex(),
+		logger:            zap.NewNop(),
+	}
+
+	preview, err := svc.PreviewStagedConceptApproval(context.Background(), staged.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel := preview.Relationships[1]; rel.Action != services.RelationshipSkip || rel.Reason != "matched prerequisite no longer in graph" {
+		t.Errorf("expected the vanished match to be skipped, got %+v", rel)
+	}
+
+	if err := svc.ApproveStagedConcept(context.Background(), staged.ID, "reviewer", ""); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"limits->series", "sets->series"}; !slices.Equal(graph.linked, want) {
+		t.Errorf("linked %v, want %v", graph.linked, want)
+	}
+}
+
+func TestPreviewApprovalRejectsReviewedConcept(t *testing.T) {
+	staged := entities.NewStagedConcept("Series", "", "q1", "", "", nil, 1, "", "")
+	staged.Reject("reviewer", "")
+	svc := &queryService{
+		stagedConceptRepo: &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{staged}},
+		logger:            zap.NewNop(),
+	}
+
+	if _, err := svc.PreviewStagedConceptApproval(context.Background(), staged.ID); err == nil {
+		t.Error("expected an error for an already reviewed concept")
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// autoApprovalReviewer is recorded as the reviewer of auto-approved concepts
+const autoApprovalReviewer = "system:auto-approval"
+
+// autoApprovalPolicy decides which staged concepts are approved without a
+// reviewer. The zero value approves nothing.
+type autoApprovalPolicy struct {
+	enabled              bool
+	minOccurrences       int
+	minConfidence        float64
+	requirePrerequisites bool
+}
+
+func newAutoApprovalPolicy(cfg config.StagingConfig) autoApprovalPolicy {
+	return autoApprovalPolicy{
+		enabled:              cfg.AutoApproveEnabled,
+		minOccurrences:       cfg.AutoApproveMinOccurrences,
+		minConfidence:        cfg.AutoApproveMinConfidence,
+		requirePrerequisites: cfg.AutoApproveRequirePrerequisites,
+	}
+}
+
+// qualifies checks the thresholds that need no graph lookups, returning why
+// the concept does not qualify
+func (p autoApprovalPolicy) qualifies(staged *entities.StagedConcept) (bool, string) {
+	switch {
+	case !p.enabled:
+		return false, "auto-approval is disabled"
+	case staged.Status != entities.StagedConceptStatusPending:
+		return false, "concept has already been reviewed"
+	case staged.OccurrenceCount < p.minOccurrences:
+		return false, fmt.Sprintf("%d occurrences, below %d", staged.OccurrenceCount, p.minOccurrences)
+	case staged.LLMConfidence < p.minConfidence:
+		return false, fmt.Sprintf("confidence %.2f, below %.2f", staged.LLMConfidence, p.minConfidence)
+	}
+	return true, ""
+}
+
+// evaluate decides whether approving as previewed is safe without a
+// reviewer. The reason says why not, or which criteria were met.
+func (p autoApprovalPolicy) evaluate(staged *entities.StagedConcept, preview *services.ApprovalPreview) (bool, string) {
+	if ok, reason := p.qualifies(staged); !ok {
+		return false, reason
+	}
+	if preview.ConceptExists {
+		return false, "a concept with this name is already in the graph"
+	}
+	if preview.CreatesCycle {
+		return false, "approval would create a prerequisite cycle"
+	}
+
+	linked := 0
+	for _, rel := range preview.Relationships {
+		if rel.Action == services.RelationshipCreate {
+			linked++
+		} else if p.requirePrerequisites {
+			return false, fmt.Sprintf("prerequisite %q would be skipped: %s", rel.Suggestion, rel.Reason)
+		}
+	}
+
+	return true, fmt.Sprintf("%d occurrences (min %d), confidence %.2f (min %.2f), %d of %d prerequisites linked, no cycle",
+		staged.OccurrenceCount, p.minOccurrences, staged.LLMConfidence, p.minConfidence,
+		linked, len(preview.Relationships))
+}
+
+// maybeAutoApprove approves a stored staged concept if the policy allows it,
+// recording the criteria it met as the review notes. It reports whether the
+// concept was approved.
+func (s *queryService) maybeAutoApprove(ctx context.Context, staged *entities.StagedConcept) bool {
+	if ok, _ := s.autoApproval.qualifies(staged); !ok {
+		return false
+	}
+
+	preview, err := s.previewApproval(ctx, staged)
+	if err != nil {
+		s.logger.Warn("Failed to preview staged concept for auto-approval",
+			zap.String("staged_id", staged.ID),
+			zap.Error(err))
+		return false
+	}
+
+	ok, reason := s.autoApproval.evaluate(staged, preview)
+	if !ok {
+		s.logger.Info("Staged concept left for review",
+			zap.String("staged_id", staged.ID),
+			zap.String("concept", staged.ConceptName),
+			zap.String("reason", reason))
+		return false
+	}
+
+	notes := "Auto-approved by policy: " + reason
+	if err := s.ApproveStagedConcept(ctx, staged.ID, autoApprovalReviewer, notes); err != nil {
+		s.logger.Error("Failed to auto-approve staged concept",
+			zap.String("staged_id", staged.ID),
+			zap.String("concept", staged.ConceptName),
+			zap.Error(err))
+		return false
+	}
+
+	s.logger.Info("Staged concept auto-approved",
+		zap.String("staged_id", staged.ID),
+		zap.String("concept", staged.ConceptName),
+		zap.String("concept_id", preview.Concept.ID),
+		zap.String("reviewer", autoApprovalReviewer),
+		zap.String("criteria", reason))
+	return true
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+var testAutoApprovalPolicy = autoApprovalPolicy{
+	enabled:              true,
+	minOccurrences:       5,
+	minConfidence:        0.9,
+	requirePrerequisites: true,
+}
+
+func qualifyingStagedConcept() *entities.StagedConcept {
+	c := entities.NewStagedConcept("Series", "", "q1", "", "", []string{"limits", "sequences"}, 4, "calculus", "")
+	c.OccurrenceCount = 6
+	c.LLMConfidence = 0.95
+	return c
+}
+
+func cleanPreview() *services.ApprovalPreview {
+	return &services.ApprovalPreview{
+		Concept: types.Concept{ID: "series"},
+		Relationships: []services.PlannedRelationship{
+			{Suggestion: "limits", PrerequisiteID: "limits", Action: services.RelationshipCreate},
+			{Suggestion: "sequences", PrerequisiteID: "sequences", Action: services.RelationshipCreate},
+		},
+	}
+}
+
+func TestAutoApprovalPolicyGating(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     func(p *autoApprovalPolicy)
+		staged     func(c *entities.StagedConcept)
+		preview    func(p *services.ApprovalPreview)
+		want       bool
+		wantReason string
+	}{
+		{name: "qualifies", want: true, wantReason: "6 occurrences (min 5), confidence 0.95 (min 0.90), 2 of 2 prerequisites linked"},
+		{name: "disabled", policy: func(p *autoApprovalPolicy) { p.enabled = false }, wantReason: "disabled"},
+		{name: "too few occurrences", staged: func(c *entities.StagedConcept) { c.OccurrenceCount = 4 }, wantReason: "4 occurrences, below 5"},
+		{name: "low confidence", staged: func(c *entities.StagedConcept) { c.LLMConfidence = 0.7 }, wantReason: "confidence 0.70, below 0.90"},
+		{name: "already reviewed", staged: func(c *entities.StagedConcept) { c.Reject("reviewer", "") }, wantReason: "already been reviewed"},
+		{name: "duplicate concept", preview: func(p *services.ApprovalPreview) { p.ConceptExists = true }, wantReason: "already in the graph"},
+		{name: "cycle", preview: func(p *services.ApprovalPreview) { p.CreatesCycle = true }, wantReason: "cycle"},
+		{
+			name: "missing prerequisite",
+			preview: func(p *services.ApprovalPreview) {
+				p.Relationships[1].Action, p.Relationships[1].Reason = services.RelationshipSkip, "prerequisite not in graph"
+			},
+			wantReason: `prerequisite "sequences" would be skipped`,
+		},
+		{
+			name:   "missing prerequisite allowed",
+			policy: func(p *autoApprovalPolicy) { p.requirePrerequisites = false },
+			preview: func(p *services.ApprovalPreview) {
+				p.Relationships[1].Action = services.RelationshipSkip
+			},
+			want:       true,
+			wantReason: "1 of 2 prerequisites linked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := testAutoApprovalPolicy
+			staged := qualifyingStagedConcept()
+			preview := cleanPreview()
+			if tt.policy != nil {
+				tt.policy(&policy)
+			}
+			if tt.staged != nil {
+				tt.staged(staged)
+			}
+			if tt.preview != nil {
+				tt.preview(preview)
+			}
+
+			ok, reason := policy.evaluate(staged, preview)
+			if ok != tt.want {
+				t.Errorf("evaluate() = %v (%s), want %v", ok, reason, tt.want)
+			}
+			if !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("reason %q does not mention %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAutoApprovalPolicyOffByDefault(t *testing.T) {
+	if ok, _ := (autoApprovalPolicy{}).evaluate(qualifyingStagedConcept(), cleanPreview()); ok {
+		t.Error("the zero policy must not approve anything")
+	}
+}
+
+// approvingConceptRepo records the concepts and edges approval creates
+type approvingConceptRepo struct {
+	*graphConceptRepo
+	created []string
+	linked  []string
+}
+
+func (r *approvingConceptRepo) CreateConcept(ctx context.Context, concept *types.Concept) error {
+	r.created = append(r.created, concept.ID)
+	return nil
+}
+
+func (r *approvingConceptRepo) CreatePrerequisiteRelationship(ctx context.Context, conceptID, prerequisiteID string, weight float64) error {
+	r.linked = append(r.linked, prerequisiteID+"->"+conceptID)
+	return nil
+}
+
+func (r *memoryStagedConceptRepo) Update(ctx context.Context, concept *entities.StagedConcept) error {
+	return nil
+}
+
+func TestMaybeAutoApprove(t *testing.T) {
+	newService := func(staged *entities.StagedConcept) (*queryService, *approvingConceptRepo) {
+		graph := &approvingConceptRepo{graphConceptRepo: &graphConceptRepo{ids: []string{"limits", "sequences"}}}
+		return &queryService{
+			conceptRepo:       graph,
+			stagedConceptRepo: &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{staged}},
+			autoApproval:      testAutoApprovalPolicy,
+			logger:            zap.NewNop(),
+		}, graph
+	}
+
+	t.Run("approves and records the criteria", func(t *testing.T) {
+		staged := qualifyingStagedConcept()
+		svc, graph := newService(staged)
+
+		if !svc.maybeAutoApprove(context.Background(), staged) {
+			t.Fatal("expected the concept to be auto-approved")
+		}
+		if staged.Status != entities.StagedConceptStatusApproved || staged.ReviewedBy != autoApprovalReviewer {
+			t.Errorf("expected an auditable approval, got status %s by %q", staged.Status, staged.ReviewedBy)
+		}
+		if !strings.HasPrefix(staged.ReviewNotes, "Auto-approved by policy: 6 occurrences") {
+			t.Errorf("unexpected review notes %q", staged.ReviewNotes)
+		}
+		if len(graph.created) != 1 || graph.created[0] != "series" || len(graph.linked) != 2 {
+			t.Errorf("unexpected graph writes: created %v, linked %v", graph.created, graph.linked)
+		}
+	})
+
+	t.Run("leaves concepts with missing prerequisites for review", func(t *testing.T) {
+		staged := qualifyingStagedConcept()
+		staged.SuggestedPrerequisites = append(staged.SuggestedPrerequisites, "topology")
+		svc, graph := newService(staged)
+
+		if svc.maybeAutoApprove(context.Background(), staged) {
+			t.Fatal("expected the concept to be left for review")
+		}
+		if staged.Status != entities.StagedConceptStatusPending || len(graph.created) != 0 {
+			t.Errorf("expected nothing to change, got status %s and created %v", staged.Status, graph.created)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+// demandDecayPageSize bounds how many pending concepts are loaded at a time
+const demandDecayPageSize = 500
+
+// DecayStagedConceptDemand ages every pending concept's demand score to now,
+// so the stored scores compare occurrences on the same clock. It returns how
+// many scores were stored.
+func (s *queryService) DecayStagedConceptDemand(ctx context.Context) (int, error) {
+	if s.demandHalfLife <= 0 {
+		return 0, fmt.Errorf("staged concept demand decay is disabled")
+	}
+
+	now := time.Now()
+	var pending []*entities.StagedConcept
+	for offset := 0; ; offset += demandDecayPageSize {
+		page, err := s.stagedConceptRepo.GetByStatus(ctx, entities.StagedConceptStatusPending, demandDecayPageSize, offset)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load pending staged concepts: %w", err)
+		}
+		pending = append(pending, page...)
+		if len(page) < demandDecayPageSize {
+			break
+		}
+	}
+
+	updated := 0
+	for _, concept := range pending {
+		concept.DecayDemand(now, s.demandHalfLife)
+		stored, err := s.stagedConceptRepo.UpdateDemand(ctx, concept)
+		if err != nil {
+			return updated, err
+		}
+		if stored {
+			updated++
+		}
+	}
+
+	s.logger.Info("Staged concept demand decayed",
+		zap.Int("pending", len(pending)),
+		zap.Int("updated", updated),
+		zap.Duration("half_life", s.demandHalfLife))
+
+	return updated, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+func (r *memoryStagedConceptRepo) GetByStatus(ctx context.Context, status entities.StagedConceptStatus, limit, offset int) ([]*entities.StagedConcept, error) {
+	var matched []*entities.StagedConcept
+	for _, c := range r.concepts {
+		if c.Status == status {
+			matched = append(matched, c)
+		}
+	}
+	if offset >= len(matched) {
+		return nil, nil
+	}
+	matched = matched[offset:]
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+func (r *memoryStagedConceptRepo) UpdateDemand(ctx context.Context, concept *entities.StagedConcept) (bool, error) {
+	return true, nil
+}
+
+// pendingByDemand orders pending concepts the way GetPending does
+func (r *memoryStagedConceptRepo) pendingByDemand() []string {
+	var pending []*entities.StagedConcept
+	for _, c := range r.concepts {
+		if c.Status == entities.StagedConceptStatusPending {
+			pending = append(pending, c)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].DemandScore > pending[j].DemandScore
+	})
+	names := make([]string, len(pending))
+	for i, c := range pending {
+		names[i] = c.ConceptName
+	}
+	return names
+}
+
+// stagedConceptSeen builds a pending concept with count occurrences, all seen
+// ago
+func stagedConceptSeen(name string, count int, ago time.Duration) *entities.StagedConcept {
+	c := stagedConceptAged(name, ago, entities.StagedConceptStatusPending)
+	c.OccurrenceCount = count
+	c.DemandScore = float64(count)
+	c.DemandUpdatedAt = c.IdentifiedAt
+	return c
+}
+
+func TestDecayStagedConceptDemandFavorsRecentOccurrences(t *testing.T) {
+	day := 24 * time.Hour
+	repo := &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{
+		stagedConceptSeen("once popular", 20, 70*day),
+		stagedConceptSeen("trending", 4, 2*day),
+		stagedConceptSeen("reviewed", 50, 1*day),
+	}}
+	repo.concepts[2].Status = entities.StagedConceptStatusApproved
+	svc := &queryService{stagedConceptRepo: repo, demandHalfLife: 14 * day, logger: zap.NewNop()}
+
+	if got := repo.pendingByDemand(); got[0] != "once popular" {
+		t.Fatalf("expected raw counts to favor the old concept before decay, got %v", got)
+	}
+
+	updated, err := svc.DecayStagedConceptDemand(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 2 {
+		t.Errorf("expected 2 pending scores updated, got %d", updated)
+	}
+
+	if got := repo.pendingByDemand(); got[0] != "trending" || got[1] != "once popular" {
+		t.Errorf("expected recent occurrences to outrank old ones, got %v", got)
+	}
+	// 20 occurrences five half-lives ago are worth 20/32
+	if score := repo.concepts[0].DemandScore; score < 0.62 || score > 0.63 {
+		t.Errorf("unexpected decayed score %v", score)
+	}
+	if repo.concepts[2].DemandScore != 50 {
+		t.Errorf("reviewed concepts should not decay, got %v", repo.concepts[2].DemandScore)
+	}
+}
+
+func TestDecayStagedConceptDemandDisabled(t *testing.T) {
+	svc := &queryService{stagedConceptRepo: &memoryStagedConceptRepo{}, logger: zap.NewNop()}
+	if _, err := svc.DecayStagedConceptDemand(context.Background()); err == nil {
+		t.Error("expected an error when no half-life is configured")
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/mathprereq/internal/core/config"
+	"go.uber.org/zap"
+)
+
+var stagedConceptDigestTemplate = filepath.Join("internal", "mailer", "templates", "staged_concepts_digest.tmpl")
+
+// stagedConceptDigest is the data of the staged concept digest email
+type stagedConceptDigest struct {
+	Frequency    string
+	Period       string // covered by NewCount, e.g. "24 hours"
+	GeneratedAt  string
+	PendingCount int64
+	NewCount     int64
+	TopConcepts  []digestConcept
+	Oldest       *digestConcept
+}
+
+type digestConcept struct {
+	Name        string
+	Category    string
+	Occurrences int
+	FirstSeen   string
+	AgeDays     int
+}
+
+// SendStagedConceptDigest emails the admin a summary of pending staged
+// concepts: how many there are, how many arrived since the last digest,
+// the most requested and the oldest. Nothing is sent when none are pending.
+func (s *queryService) SendStagedConceptDigest(ctx context.Context) error {
+	if s.mailer == nil || !s.mailer.IsEnabled() {
+		s.logger.Debug("Mailer not configured or disabled, skipping staged concept digest")
+		return nil
+	}
+	if s.adminEmail == "" {
+		return fmt.Errorf("admin email not configured")
+	}
+
+	digest, err := s.buildStagedConceptDigest(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if digest == nil {
+		s.logger.Info("No pending staged concepts, skipping digest")
+		return nil
+	}
+
+	if err := s.mailer.Send(s.adminEmail, stagedConceptDigestTemplate, digest); err != nil {
+		return fmt.Errorf("failed to send staged concept digest: %w", err)
+	}
+
+	s.logger.Info("Staged concept digest sent",
+		zap.String("admin_email", s.adminEmail),
+		zap.Int64("pending", digest.PendingCount),
+		zap.Int64("new", digest.NewCount))
+	return nil
+}
+
+// buildStagedConceptDigest summarizes pending staged concepts as of now,
+// or returns nil when there are none
+func (s *queryService) buildStagedConceptDigest(ctx context.Context, now time.Time) (*stagedConceptDigest, error) {
+	stats, err := s.stagedConceptRepo.GetStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged concept stats: %w", err)
+	}
+	if stats.PendingCount == 0 {
+		return nil, nil
+	}
+
+	period := s.staging.DigestInterval()
+	earlier, err := s.stagedConceptRepo.GetStaleStats(ctx, now.Add(-period))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count earlier staged concepts: %w", err)
+	}
+	// Every pending concept was identified before now
+	all, err := s.stagedConceptRepo.GetStaleStats(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the oldest staged concept: %w", err)
+	}
+	top, err := s.stagedConceptRepo.GetPending(ctx, s.staging.DigestTopConcepts, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending staged concepts: %w", err)
+	}
+
+	digest := &stagedConceptDigest{
+		Frequency:    s.staging.DigestFrequency,
+		Period:       "24 hours",
+		GeneratedAt:  now.Format("2006-01-02 15:04 MST"),
+		PendingCount: stats.PendingCount,
+		NewCount:     stats.PendingCount - earlier.StaleCount,
+	}
+	if s.staging.DigestFrequency == config.DigestWeekly {
+		digest.Period = "7 days"
+	}
+	for _, staged := range top {
+		digest.TopConcepts = append(digest.TopConcepts, digestConcept{
+			Name:        staged.ConceptName,
+			Category:    staged.SuggestedCategory,
+			Occurrences: staged.OccurrenceCount,
+			FirstSeen:   staged.IdentifiedAt.Format("2006-01-02"),
+		})
+	}
+	if oldest := all.OldestPending; oldest != nil {
+		digest.Oldest = &digestConcept{
+			Name:        oldest.ConceptName,
+			Category:    oldest.SuggestedCategory,
+			Occurrences: oldest.OccurrenceCount,
+			FirstSeen:   oldest.IdentifiedAt.Format("2006-01-02"),
+			AgeDays:     int(now.Sub(oldest.IdentifiedAt).Hours() / 24),
+		}
+	}
+	return digest, nil
+}
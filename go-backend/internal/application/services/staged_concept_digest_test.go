@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/mailer"
+	"go.uber.org/zap"
+)
+
+// digestStagedConceptRepo adds the stats and pending listing the digest reads
+type digestStagedConceptRepo struct {
+	*memoryStagedConceptRepo
+}
+
+func (r digestStagedConceptRepo) GetStats(ctx context.Context) (*repositories.StagedConceptStats, error) {
+	stats := &repositories.StagedConceptStats{}
+	for _, c := range r.concepts {
+		if c.Status == entities.StagedConceptStatusPending {
+			stats.PendingCount++
+		}
+	}
+	return stats, nil
+}
+
+func (r digestStagedConceptRepo) GetPending(ctx context.Context, limit, offset int) ([]*entities.StagedConcept, error) {
+	var pending []*entities.StagedConcept
+	for _, c := range r.concepts {
+		if c.Status == entities.StagedConceptStatusPending {
+			pending = append(pending, c)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].OccurrenceCount > pending[j].OccurrenceCount })
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+func TestBuildStagedConceptDigest(t *testing.T) {
+	day := 24 * time.Hour
+	concepts := []*entities.StagedConcept{
+		stagedConceptAged("Jacobian", 2*time.Hour, entities.StagedConceptStatusPending),
+		stagedConceptAged("Laplace Transform", 3*day, entities.StagedConceptStatusPending),
+		stagedConceptAged("Fourier Series", 20*day, entities.StagedConceptStatusPending),
+		stagedConceptAged("Reviewed", 60*day, entities.StagedConceptStatusApproved),
+	}
+	concepts[0].OccurrenceCount = 2
+	concepts[1].OccurrenceCount = 9
+	concepts[2].OccurrenceCount = 4
+	svc := &queryService{
+		stagedConceptRepo: digestStagedConceptRepo{&memoryStagedConceptRepo{concepts: concepts}},
+		staging:           config.StagingConfig{DigestFrequency: config.DigestWeekly, DigestTopConcepts: 2},
+		logger:            zap.NewNop(),
+	}
+
+	digest, err := svc.buildStagedConceptDigest(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest.PendingCount != 3 || digest.NewCount != 2 || digest.Period != "7 days" {
+		t.Errorf("expected 3 pending, 2 new in 7 days, got %+v", digest)
+	}
+	if len(digest.TopConcepts) != 2 || digest.TopConcepts[0].Name != "Laplace Transform" || digest.TopConcepts[1].Name != "Fourier Series" {
+		t.Errorf("expected the two most requested, got %+v", digest.TopConcepts)
+	}
+	if digest.Oldest == nil || digest.Oldest.Name != "Fourier Series" || digest.Oldest.AgeDays != 20 {
+		t.Errorf("expected Fourier Series oldest at 20 days, got %+v", digest.Oldest)
+	}
+
+	template := filepath.Join("..", "..", "mailer", "templates", "staged_concepts_digest.tmpl")
+	subject, plain, html, err := mailer.Render(template, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "MathPrereq weekly digest: 3 staged concepts awaiting review" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	for _, want := range []string{"New in the last 7 days: 2", "Laplace Transform (9 occurrences", "Oldest pending: Fourier Series, waiting 20 days"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected plain body to contain %q", want)
+		}
+	}
+	if !strings.Contains(html, "<td>Laplace Transform</td>") {
+		t.Error("expected the most requested concepts in the HTML body")
+	}
+}
+
+func TestBuildStagedConceptDigestSkipsWhenNothingPending(t *testing.T) {
+	svc := &queryService{
+		stagedConceptRepo: digestStagedConceptRepo{&memoryStagedConceptRepo{concepts: []*entities.StagedConcept{
+			stagedConceptAged("Reviewed", time.Hour, entities.StagedConceptStatusApproved),
+		}}},
+		staging: config.StagingConfig{DigestFrequency: config.DigestDaily, DigestTopConcepts: 5},
+		logger:  zap.NewNop(),
+	}
+
+	digest, err := svc.buildStagedConceptDigest(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != nil {
+		t.Errorf("expected no digest, got %+v", digest)
+	}
+}
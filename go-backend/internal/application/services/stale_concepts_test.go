@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+// memoryStagedConceptRepo is an in-memory StagedConceptRepository for tests.
+// Methods not overridden panic through the nil embedded interface.
+type memoryStagedConceptRepo struct {
+	repositories.StagedConceptRepository
+	concepts []*entities.StagedConcept
+}
+
+// GetStaleStats mirrors the MongoDB query, which the repositories package
+// tests on its own
+func (r *memoryStagedConceptRepo) GetStaleStats(ctx context.Context, cutoff time.Time) (*repositories.StaleStagedConceptStats, error) {
+	stats := &repositories.StaleStagedConceptStats{}
+	for _, c := range r.concepts {
+		if c.Status != entities.StagedConceptStatusPending {
+			continue
+		}
+		if c.IdentifiedAt.Before(cutoff) {
+			stats.StaleCount++
+		}
+		if stats.OldestPending == nil || c.IdentifiedAt.Before(stats.OldestPending.IdentifiedAt) {
+			stats.OldestPending = c
+		}
+	}
+	return stats, nil
+}
+
+func stagedConceptAged(name string, age time.Duration, status entities.StagedConceptStatus) *entities.StagedConcept {
+	c := entities.NewStagedConcept(name, "", "q", "", "", nil, 1, "", "")
+	c.IdentifiedAt = time.Now().Add(-age)
+	c.Status = status
+	return c
+}
+
+func TestGetStaleStagedConcepts(t *testing.T) {
+	day := 24 * time.Hour
+	repo := &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{
+		stagedConceptAged("fresh", 1*day, entities.StagedConceptStatusPending),
+		stagedConceptAged("recent", 10*day, entities.StagedConceptStatusPending),
+		stagedConceptAged("stale", 20*day, entities.StagedConceptStatusPending),
+		stagedConceptAged("oldest", 30*day, entities.StagedConceptStatusPending),
+		stagedConceptAged("reviewed", 60*day, entities.StagedConceptStatusApproved),
+	}}
+	svc := &queryService{stagedConceptRepo: repo, logger: zap.NewNop()}
+
+	stats, err := svc.GetStaleStagedConcepts(context.Background(), 14*day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.StaleCount != 2 {
+		t.Errorf("expected 2 stale concepts, got %d", stats.StaleCount)
+	}
+	if stats.MaxAgeDays != 14 {
+		t.Errorf("expected max age 14 days, got %v", stats.MaxAgeDays)
+	}
+	if stats.OldestPending == nil || stats.OldestPending.ConceptName != "oldest" {
+		t.Fatalf("expected oldest pending concept, got %+v", stats.OldestPending)
+	}
+	if age := time.Duration(stats.OldestAgeSeconds * float64(time.Second)); age < 30*day || age > 30*day+time.Minute {
+		t.Errorf("unexpected oldest age %v", age)
+	}
+
+	stats, err = svc.GetStaleStagedConcepts(context.Background(), 45*day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.StaleCount != 0 {
+		t.Errorf("expected no stale concepts at 45 days, got %d", stats.StaleCount)
+	}
+}
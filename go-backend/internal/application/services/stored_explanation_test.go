@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+func TestProcessQueryTrimsStoredExplanation(t *testing.T) {
+	explanation := "Start with the chain rule.\n\n" +
+		strings.Repeat("Differentiate the outer function, then multiply by the inner derivative. ", 30) +
+		"\n\nSo the derivative of sin(x^2) is 2x cos(x^2)."
+
+	tasks := background.NewTasks()
+	queries := &savingQueryRepo{}
+	svc := &queryService{
+		conceptRepo: &pathConceptRepo{},
+		queryRepo:   queries,
+		vectorRepo:  &stubVectorRepo{},
+		llmClient: &recordingLLM{
+			concepts:    []string{"chain rule"},
+			explanation: map[string]string{"English": explanation},
+		},
+		maxStoredExplanation: 600,
+		sampler:              &analyticsSampler{sampleRate: 1},
+		tasks:                tasks,
+		logger:               zap.NewNop(),
+	}
+
+	result, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "Differentiate sin(x^2)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainTasks(t, tasks)
+
+	if result.Explanation != explanation {
+		t.Error("the client should get the explanation in full")
+	}
+	if result.Query.Response.ExplanationTrimmed {
+		t.Error("trimming for storage changed the caller's query")
+	}
+
+	stored := queries.saved[0].Response
+	if !stored.ExplanationTrimmed {
+		t.Fatal("stored explanation is not flagged as trimmed")
+	}
+	if n := utf8.RuneCountInString(stored.Explanation); n > 600 {
+		t.Errorf("stored explanation has %d characters, want at most 600", n)
+	}
+	if !strings.HasPrefix(stored.Explanation, "Start with the chain rule.") ||
+		!strings.HasSuffix(stored.Explanation, "is 2x cos(x^2).") {
+		t.Errorf("stored explanation lost its beginning or end: %q", stored.Explanation)
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mathprereq/internal/data/scraper"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// videoStudyFactor scales a video's length to the time spent studying
+	// with it: pausing, rewatching and working the examples
+	videoStudyFactor = 2
+	// studyVideoLimit is how many stored resources are checked per concept
+	// for a video with a usable duration
+	studyVideoLimit = 10
+	// Difficulty heuristic: base minutes plus minutes per difficulty level,
+	// with unrated concepts treated as defaultStudyDifficulty
+	studyBaseMinutes       = 15
+	studyMinutesPerLevel   = 10
+	defaultStudyDifficulty = 5
+	// maxVideoDuration rejects parsed lengths that are surely not one video
+	maxVideoDuration = 10 * time.Hour
+)
+
+// Study time sources
+const (
+	StudyTimeFromVideo      = "video"
+	StudyTimeFromDifficulty = "difficulty"
+)
+
+// EstimateStudyTime sums how long a learner needs for a concept and each
+// prerequisite on its path they do not already know. A concept with a
+// stored video is timed from the video's length; the rest from difficulty.
+func (s *queryService) EstimateStudyTime(ctx context.Context, conceptID string, knownConceptIDs []string) (*services.StudyTimeEstimate, error) {
+	path, err := s.conceptRepo.FindPrerequisitePath(ctx, []string{conceptID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prerequisite path: %w", err)
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("concept not found: %s", conceptID)
+	}
+
+	videos := make(map[string][]scraper.EducationalResource)
+	if s.resourceScraper != nil {
+		for _, concept := range path {
+			resources, err := s.resourceScraper.GetResourcesForConcept(ctx, s.generateConceptID(concept.Name), studyVideoLimit)
+			if err != nil {
+				s.logger.Warn("Failed to get resources for study time, using difficulty",
+					zap.String("concept", concept.Name),
+					zap.Error(err))
+				continue
+			}
+			videos[concept.ID] = resources
+		}
+	}
+
+	return estimateStudyTime(conceptID, path, videos, knownConceptIDs), nil
+}
+
+// estimateStudyTime times each concept of path not in known, in path order
+func estimateStudyTime(conceptID string, path []types.Concept, videos map[string][]scraper.EducationalResource, known []string) *services.StudyTimeEstimate {
+	skip := make(map[string]bool, len(known))
+	for _, id := range known {
+		skip[id] = true
+	}
+
+	estimate := &services.StudyTimeEstimate{
+		ConceptID: conceptID,
+		Concepts:  []services.ConceptStudyTime{},
+	}
+	for _, concept := range path {
+		if skip[concept.ID] && concept.ID != conceptID {
+			estimate.Skipped++
+			continue
+		}
+
+		entry := services.ConceptStudyTime{
+			ConceptID:   concept.ID,
+			ConceptName: concept.Name,
+			Difficulty:  concept.Difficulty,
+		}
+		if length, ok := studyVideoLength(videos[concept.ID]); ok {
+			entry.Minutes = roundMinutes(length * videoStudyFactor)
+			entry.Source = StudyTimeFromVideo
+		} else {
+			entry.Minutes = difficultyStudyMinutes(concept.Difficulty)
+			entry.Source = StudyTimeFromDifficulty
+			estimate.Estimated++
+		}
+		estimate.TotalMinutes += entry.Minutes
+		estimate.Concepts = append(estimate.Concepts, entry)
+	}
+	estimate.Total = formatStudyMinutes(estimate.TotalMinutes)
+	return estimate
+}
+
+// studyVideoLength is the length of the best-scored video with a duration
+// that parses; resources come sorted by quality
+func studyVideoLength(resources []scraper.EducationalResource) (time.Duration, bool) {
+	for _, resource := range resources {
+		if resource.ResourceType != "video" || resource.Duration == nil {
+			continue
+		}
+		if length, ok := parseStudyDuration(*resource.Duration); ok {
+			return length, true
+		}
+	}
+	return 0, false
+}
+
+func difficultyStudyMinutes(difficulty int) int {
+	switch {
+	case difficulty <= 0:
+		difficulty = defaultStudyDifficulty
+	case difficulty > 10:
+		difficulty = 10
+	}
+	return studyBaseMinutes + studyMinutesPerLevel*difficulty
+}
+
+var (
+	clockDuration   = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{2})$`)
+	isoDuration     = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+	durationPart    = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*([a-z]+)`)
+	durationNumeric = regexp.MustCompile(`^\d+(?:\.\d+)?$`)
+)
+
+// parseStudyDuration reads a video length written as a clock ("12:34",
+// "1:02:03"), in ISO 8601 ("PT12M34S"), in words ("12 minutes, 34
+// seconds", "1 hr 5 min"), in Go syntax ("1h5m") or as bare minutes
+func parseStudyDuration(raw string) (time.Duration, bool) {
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		return 0, false
+	}
+
+	var length time.Duration
+	switch {
+	case clockDuration.MatchString(text):
+		m := clockDuration.FindStringSubmatch(text)
+		length = atoiDuration(m[1], time.Hour) + atoiDuration(m[2], time.Minute) + atoiDuration(m[3], time.Second)
+	case isoDuration.MatchString(strings.ToUpper(text)) && len(text) > 2:
+		m := isoDuration.FindStringSubmatch(strings.ToUpper(text))
+		length = atoiDuration(m[1], time.Hour) + atoiDuration(m[2], time.Minute) + atoiDuration(m[3], time.Second)
+	case durationNumeric.MatchString(text):
+		minutes, _ := strconv.ParseFloat(text, 64)
+		length = time.Duration(minutes * float64(time.Minute))
+	default:
+		parsed, ok := parseWordDuration(strings.ToLower(text))
+		if !ok {
+			return 0, false
+		}
+		length = parsed
+	}
+
+	if length <= 0 || length > maxVideoDuration {
+		return 0, false
+	}
+	return length, true
+}
+
+// durationFiller are the words allowed between "<number> <unit>" parts
+var durationFiller = map[string]bool{"and": true, "about": true, "approx": true, "approximately": true, "~": true}
+
+// parseWordDuration sums "<number> <unit>" parts. Unknown units or other
+// words fail the whole string rather than guessing, so "1.2M views" is
+// not read as a minute.
+func parseWordDuration(text string) (time.Duration, bool) {
+	parts := durationPart.FindAllStringSubmatch(text, -1)
+	if len(parts) == 0 {
+		return 0, false
+	}
+	rest := strings.ReplaceAll(durationPart.ReplaceAllString(text, " "), ",", " ")
+	for _, word := range strings.Fields(rest) {
+		if !durationFiller[word] {
+			return 0, false
+		}
+	}
+
+	var length time.Duration
+	for _, part := range parts {
+		value, err := strconv.ParseFloat(part[1], 64)
+		if err != nil {
+			return 0, false
+		}
+		var unit time.Duration
+		switch part[2] {
+		case "h", "hr", "hrs", "hour", "hours":
+			unit = time.Hour
+		case "m", "min", "mins", "minute", "minutes":
+			unit = time.Minute
+		case "s", "sec", "secs", "second", "seconds":
+			unit = time.Second
+		default:
+			return 0, false
+		}
+		length += time.Duration(value * float64(unit))
+	}
+	return length, true
+}
+
+func atoiDuration(value string, unit time.Duration) time.Duration {
+	n, _ := strconv.Atoi(value)
+	return time.Duration(n) * unit
+}
+
+// roundMinutes rounds to whole minutes, counting any started minute
+func roundMinutes(d time.Duration) int {
+	return int((d + time.Minute - 1) / time.Minute)
+}
+
+// formatStudyMinutes writes minutes as e.g. "3h 20m"
+func formatStudyMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
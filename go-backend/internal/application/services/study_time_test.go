@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/data/scraper"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+func TestParseStudyDuration(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want time.Duration
+		ok   bool
+	}{
+		{"12:34", 12*time.Minute + 34*time.Second, true},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, true},
+		{"PT15M30S", 15*time.Minute + 30*time.Second, true},
+		{"pt1h", time.Hour, true},
+		{"12 minutes, 34 seconds", 12*time.Minute + 34*time.Second, true},
+		{"1 hour, 5 minutes", time.Hour + 5*time.Minute, true},
+		{"about 1.5 hrs", 90 * time.Minute, true},
+		{"1h5m", time.Hour + 5*time.Minute, true},
+		{" 20 ", 20 * time.Minute, true},
+		{"", 0, false},
+		{"PT", 0, false},
+		{"1.2M views", 0, false},
+		{"soon", 0, false},
+		{"0:00", 0, false},
+		{"99 hours", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseStudyDuration(tt.raw)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseStudyDuration(%q) = %v, %v; want %v, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func video(duration string) scraper.EducationalResource {
+	return scraper.EducationalResource{ResourceType: "video", Duration: &duration}
+}
+
+func TestEstimateStudyTimeSumsPath(t *testing.T) {
+	path := []types.Concept{
+		{ID: "functions", Name: "Functions", Difficulty: 2, Type: "prerequisite"},
+		{ID: "limits", Name: "Limits", Difficulty: 4, Type: "prerequisite"},
+		{ID: "algebra", Name: "Algebra", Type: "prerequisite"},
+		{ID: "derivatives", Name: "Derivatives", Difficulty: 5, Type: "target"},
+	}
+	videos := map[string][]scraper.EducationalResource{
+		// The first usable video counts: articles and bad durations are skipped
+		"limits": {
+			{ResourceType: "article"},
+			video("live"),
+			video("14:30"),
+			video("45:00"),
+		},
+		"derivatives": {video("PT20M")},
+	}
+
+	estimate := estimateStudyTime("derivatives", path, videos, nil)
+
+	// functions 15+10*2, limits 2*14.5, algebra unrated at difficulty 5,
+	// derivatives 2*20
+	wantMinutes := map[string]int{"functions": 35, "limits": 29, "algebra": 65, "derivatives": 40}
+	for _, entry := range estimate.Concepts {
+		if entry.Minutes != wantMinutes[entry.ConceptID] {
+			t.Errorf("%s: expected %d minutes, got %d", entry.ConceptID, wantMinutes[entry.ConceptID], entry.Minutes)
+		}
+	}
+	if estimate.TotalMinutes != 169 || estimate.Total != "2h 49m" {
+		t.Errorf("expected 169 minutes in total, got %d (%s)", estimate.TotalMinutes, estimate.Total)
+	}
+	if estimate.Estimated != 2 || len(estimate.Concepts) != 4 {
+		t.Errorf("expected two concepts timed by difficulty, got %+v", estimate)
+	}
+	if estimate.Concepts[1].Source != StudyTimeFromVideo || estimate.Concepts[0].Source != StudyTimeFromDifficulty {
+		t.Errorf("unexpected sources %+v", estimate.Concepts)
+	}
+}
+
+func TestEstimateStudyTimeSkipsKnownPrerequisites(t *testing.T) {
+	path := []types.Concept{
+		{ID: "functions", Name: "Functions", Difficulty: 2, Type: "prerequisite"},
+		{ID: "derivatives", Name: "Derivatives", Difficulty: 5, Type: "target"},
+	}
+
+	// The target itself is always counted
+	estimate := estimateStudyTime("derivatives", path, nil, []string{"functions", "derivatives"})
+	if estimate.Skipped != 1 || estimate.TotalMinutes != 65 || estimate.Total != "1h 5m" {
+		t.Errorf("expected only the target counted, got %+v", estimate)
+	}
+}
+
+type emptyPathRepo struct {
+	repositories.ConceptRepository
+}
+
+func (r *emptyPathRepo) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
+	return nil, nil
+}
+
+func TestEstimateStudyTimeUnknownConcept(t *testing.T) {
+	svc := &queryService{conceptRepo: &emptyPathRepo{}, logger: zap.NewNop()}
+	if _, err := svc.EstimateStudyTime(context.Background(), "stokes_theorem", nil); err == nil {
+		t.Fatal("expected an error for a concept missing from the graph")
+	}
+}
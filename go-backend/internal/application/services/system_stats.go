@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	statusHealthy     = "healthy"
+	statusUnknown     = "unknown"
+	statusUnavailable = "unavailable"
+
+	systemHealthy  = "healthy"
+	systemDegraded = "degraded"
+)
+
+// GetSystemStats gathers knowledge graph, vector store and query store
+// stats concurrently. A subsystem that fails is reported unavailable, and
+// one whose stats carry no readable status is reported unknown; either way
+// the rest is still returned with the system health degraded.
+func (s *queryService) GetSystemStats(ctx context.Context) (*types.SystemStats, error) {
+	var (
+		wg         sync.WaitGroup
+		graph      *types.SystemStats
+		graphErr   error
+		vector     map[string]interface{}
+		vectorErr  error
+		queries    *repositories.QueryStats
+		queriesErr error
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		graph, graphErr = s.conceptRepo.GetStats(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		vector, vectorErr = s.vectorRepo.GetStats(ctx)
+	}()
+	if s.queryRepo != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queries, queriesErr = s.queryRepo.GetQueryStats(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for name, err := range map[string]error{
+		"knowledge_graph": graphErr,
+		"vector_store":    vectorErr,
+		"query_store":     queriesErr,
+	} {
+		if err != nil {
+			s.logger.Warn("Failed to get subsystem stats",
+				zap.String("subsystem", name),
+				zap.Error(err))
+		}
+	}
+
+	return buildSystemStats(graph, graphErr, vector, vectorErr, queries, queriesErr), nil
+}
+
+// buildSystemStats combines the stats of each subsystem. A nil result with
+// a nil error means the subsystem is not configured.
+func buildSystemStats(
+	graph *types.SystemStats, graphErr error,
+	vector map[string]interface{}, vectorErr error,
+	queries *repositories.QueryStats, queriesErr error,
+) *types.SystemStats {
+	stats := &types.SystemStats{
+		KnowledgeGraph: statusUnavailable,
+		VectorStore:    statusUnavailable,
+		QueryStore:     statusUnavailable,
+		LLMProvider:    statusUnknown,
+	}
+
+	if graphErr == nil && graph != nil {
+		stats.TotalConcepts = graph.TotalConcepts
+		stats.TotalEdges = graph.TotalEdges
+		stats.KnowledgeGraph = statusOrUnknown(graph.KnowledgeGraph)
+		stats.LLMProvider = statusOrUnknown(graph.LLMProvider)
+	}
+	if vectorErr == nil && vector != nil {
+		stats.TotalChunks = statCount(vector, "total_chunks")
+		stats.VectorStore = statStatus(vector)
+	}
+	if queriesErr == nil && queries != nil {
+		stats.TotalQueries = queries.TotalQueries
+		stats.QueryStore = statusHealthy
+	}
+
+	stats.SystemHealth = systemHealthy
+	for _, status := range []string{stats.KnowledgeGraph, stats.VectorStore, stats.QueryStore} {
+		if status != statusHealthy {
+			stats.SystemHealth = systemDegraded
+		}
+	}
+	return stats
+}
+
+// statStatus reads the status a subsystem reported, or unknown when it is
+// missing or not a string
+func statStatus(stats map[string]interface{}) string {
+	status, _ := stats["status"].(string)
+	return statusOrUnknown(status)
+}
+
+func statusOrUnknown(status string) string {
+	if status == "" {
+		return statusUnknown
+	}
+	return status
+}
+
+// statCount reads a count of any numeric type, or 0 when it is missing or
+// not a number
+func statCount(stats map[string]interface{}, key string) int64 {
+	switch v := stats[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+type statsConceptRepo struct {
+	repositories.ConceptRepository
+	stats *types.SystemStats
+	err   error
+}
+
+func (r *statsConceptRepo) GetStats(ctx context.Context) (*types.SystemStats, error) {
+	return r.stats, r.err
+}
+
+type statsVectorRepo struct {
+	repositories.VectorRepository
+	stats map[string]interface{}
+	err   error
+}
+
+func (r *statsVectorRepo) GetStats(ctx context.Context) (map[string]interface{}, error) {
+	return r.stats, r.err
+}
+
+type statsQueryRepo struct {
+	repositories.QueryRepository
+	stats *repositories.QueryStats
+	err   error
+}
+
+func (r *statsQueryRepo) GetQueryStats(ctx context.Context) (*repositories.QueryStats, error) {
+	return r.stats, r.err
+}
+
+func systemStatsService(vector *statsVectorRepo, queries repositories.QueryRepository) *queryService {
+	return &queryService{
+		conceptRepo: &statsConceptRepo{stats: &types.SystemStats{
+			TotalConcepts:  120,
+			TotalEdges:     300,
+			KnowledgeGraph: "healthy",
+			LLMProvider:    "available",
+		}},
+		vectorRepo: vector,
+		queryRepo:  queries,
+		logger:     zap.NewNop(),
+	}
+}
+
+func TestGetSystemStatsHealthy(t *testing.T) {
+	svc := systemStatsService(
+		&statsVectorRepo{stats: map[string]interface{}{"status": "healthy", "total_chunks": int64(4000)}},
+		&statsQueryRepo{stats: &repositories.QueryStats{TotalQueries: 75}},
+	)
+
+	stats, err := svc.GetSystemStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.SystemHealth != "healthy" {
+		t.Errorf("expected healthy, got %+v", stats)
+	}
+	if stats.TotalConcepts != 120 || stats.TotalChunks != 4000 || stats.TotalQueries != 75 {
+		t.Errorf("expected counts from every subsystem, got %+v", stats)
+	}
+}
+
+func TestGetSystemStatsMalformedStatus(t *testing.T) {
+	malformed := []map[string]interface{}{
+		{"status": 1, "total_chunks": "many"},
+		{"total_chunks": float64(10)},
+		{"status": nil},
+		{},
+	}
+	for _, vectorStats := range malformed {
+		svc := systemStatsService(&statsVectorRepo{stats: vectorStats},
+			&statsQueryRepo{stats: &repositories.QueryStats{}})
+
+		stats, err := svc.GetSystemStats(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.VectorStore != "unknown" || stats.SystemHealth != "degraded" {
+			t.Errorf("stats %v: expected an unknown vector store and degraded health, got %+v", vectorStats, stats)
+		}
+		if stats.TotalConcepts != 120 {
+			t.Errorf("stats %v: expected the graph stats to still be reported, got %+v", vectorStats, stats)
+		}
+	}
+}
+
+func TestGetSystemStatsSubsystemDown(t *testing.T) {
+	svc := systemStatsService(
+		// Weaviate returns a partial map alongside its error
+		&statsVectorRepo{
+			stats: map[string]interface{}{"status": "unhealthy", "total_chunks": int64(0)},
+			err:   errors.New("connection refused"),
+		},
+		&statsQueryRepo{err: errors.New("server selection timeout")},
+	)
+	svc.conceptRepo = &statsConceptRepo{err: errors.New("neo4j unavailable")}
+
+	stats, err := svc.GetSystemStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.KnowledgeGraph != "unavailable" || stats.VectorStore != "unavailable" || stats.QueryStore != "unavailable" {
+		t.Errorf("expected every subsystem unavailable, got %+v", stats)
+	}
+	if stats.SystemHealth != "degraded" {
+		t.Errorf("expected degraded health, got %q", stats.SystemHealth)
+	}
+}
+
+func TestGetSystemStatsWithoutQueryStore(t *testing.T) {
+	svc := systemStatsService(&statsVectorRepo{stats: map[string]interface{}{"status": "healthy"}}, nil)
+
+	stats, err := svc.GetSystemStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.QueryStore != "unavailable" || stats.SystemHealth != "degraded" {
+		t.Errorf("expected a missing query store to degrade health, got %+v", stats)
+	}
+}
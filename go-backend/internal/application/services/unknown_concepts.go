@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+// unknownConceptScanLimit caps how many distinct identified concepts are
+// considered when looking for gaps in the graph
+const unknownConceptScanLimit = 5000
+
+// GetUnknownConcepts lists concept names identified in stored queries that
+// are neither in the knowledge graph nor already staged for review, most
+// frequently mentioned first.
+func (s *queryService) GetUnknownConcepts(ctx context.Context, limit int) ([]repositories.ConceptPopularity, error) {
+	mentions, err := s.queryRepo.GetPopularConcepts(ctx, unknownConceptScanLimit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identified concepts: %w", err)
+	}
+
+	graphConcepts, err := s.conceptRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph concepts: %w", err)
+	}
+	known := make(map[string]bool, len(graphConcepts)*2)
+	for _, concept := range graphConcepts {
+		known[normalizeMention(concept.Name)] = true
+		known[normalizeMention(concept.ID)] = true
+	}
+
+	// Identified concepts are stored as the LLM returned them, so fold
+	// case and spacing variants into one entry
+	counts := make(map[string]*repositories.ConceptPopularity)
+	for _, mention := range mentions {
+		name := normalizeMention(mention.ConceptName)
+		if name == "" || known[name] {
+			continue
+		}
+		if entry, ok := counts[name]; ok {
+			entry.QueryCount += mention.QueryCount
+			continue
+		}
+		counts[name] = &repositories.ConceptPopularity{ConceptName: name, QueryCount: mention.QueryCount}
+	}
+
+	unknown := make([]repositories.ConceptPopularity, 0, len(counts))
+	for _, entry := range counts {
+		unknown = append(unknown, *entry)
+	}
+	sort.Slice(unknown, func(i, j int) bool {
+		if unknown[i].QueryCount != unknown[j].QueryCount {
+			return unknown[i].QueryCount > unknown[j].QueryCount
+		}
+		return unknown[i].ConceptName < unknown[j].ConceptName
+	})
+
+	result := make([]repositories.ConceptPopularity, 0, limit)
+	for _, entry := range unknown {
+		if len(result) >= limit {
+			break
+		}
+		staged, err := s.stagedConceptRepo.FindByConceptName(ctx, entry.ConceptName)
+		if err != nil {
+			s.logger.Warn("Failed to check staged concept",
+				zap.String("concept", entry.ConceptName),
+				zap.Error(err))
+			continue
+		}
+		if staged != nil {
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// normalizeMention matches the normalization detectAndStageNewConcepts
+// applies before checking the graph
+func normalizeMention(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+type stubPopularQueryRepo struct {
+	repositories.QueryRepository
+	popular []repositories.ConceptPopularity
+}
+
+func (r *stubPopularQueryRepo) GetPopularConcepts(ctx context.Context, limit int, halfLife time.Duration) ([]repositories.ConceptPopularity, error) {
+	return r.popular, nil
+}
+
+type stubAllConceptRepo struct {
+	repositories.ConceptRepository
+	concepts []types.Concept
+}
+
+func (r *stubAllConceptRepo) GetAll(ctx context.Context) ([]types.Concept, error) {
+	return r.concepts, nil
+}
+
+func (r *memoryStagedConceptRepo) FindByConceptName(ctx context.Context, conceptName string) (*entities.StagedConcept, error) {
+	for _, c := range r.concepts {
+		if strings.EqualFold(c.ConceptName, conceptName) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestGetUnknownConcepts(t *testing.T) {
+	svc := &queryService{
+		queryRepo: &stubPopularQueryRepo{popular: []repositories.ConceptPopularity{
+			{ConceptName: "derivatives", QueryCount: 40},
+			{ConceptName: "Laplace Transform", QueryCount: 12},
+			{ConceptName: "laplace  transform", QueryCount: 3},
+			{ConceptName: "Fourier Series", QueryCount: 9},
+			{ConceptName: "chain_rule", QueryCount: 8},
+			{ConceptName: "Green's Theorem", QueryCount: 5},
+			{ConceptName: "Stokes Theorem", QueryCount: 2},
+		}},
+		conceptRepo: &stubAllConceptRepo{concepts: []types.Concept{
+			{ID: "derivatives", Name: "Derivatives"},
+			{ID: "chain_rule", Name: "Chain Rule"},
+		}},
+		stagedConceptRepo: &memoryStagedConceptRepo{concepts: []*entities.StagedConcept{
+			{ConceptName: "fourier series", Status: entities.StagedConceptStatusPending},
+		}},
+		logger: zap.NewNop(),
+	}
+
+	unknown, err := svc.GetUnknownConcepts(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []repositories.ConceptPopularity{
+		{ConceptName: "laplace transform", QueryCount: 15},
+		{ConceptName: "green's theorem", QueryCount: 5},
+	}
+	if len(unknown) != len(want) {
+		t.Fatalf("expected %d unknown concepts, got %+v", len(want), unknown)
+	}
+	for i := range want {
+		if unknown[i] != want[i] {
+			t.Errorf("unknown[%d] = %+v, want %+v", i, unknown[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"go.uber.org/zap"
+)
+
+var errUserProfilesUnavailable = errors.New("user profiles are not available without MongoDB")
+
+// GetUserPreferences returns a user's stored explanation preferences, empty
+// when the user has none
+func (s *queryService) GetUserPreferences(ctx context.Context, userID string) (*entities.UserPreferences, error) {
+	if s.userProfileRepo == nil {
+		return nil, errUserProfilesUnavailable
+	}
+
+	profile, err := s.userProfileRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return &entities.UserPreferences{}, nil
+	}
+	return &profile.Preferences, nil
+}
+
+// UpdateUserPreferences stores a user's explanation preferences, replacing
+// the previous ones. Empty preferences restore the default explanation.
+func (s *queryService) UpdateUserPreferences(ctx context.Context, userID string, preferences entities.UserPreferences) (*entities.UserPreferences, error) {
+	if s.userProfileRepo == nil {
+		return nil, errUserProfilesUnavailable
+	}
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	preferences, err := preferences.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &entities.UserProfile{UserID: userID, Preferences: preferences, UpdatedAt: time.Now()}
+	if err := s.userProfileRepo.Save(ctx, profile); err != nil {
+		return nil, err
+	}
+	return &profile.Preferences, nil
+}
+
+// explanationPreferences returns the stored preferences a user's query is
+// explained with, or nil when there is no user or nothing to tailor. Lookup
+// failures are logged and the query gets the default explanation.
+func (s *queryService) explanationPreferences(ctx context.Context, userID string) *entities.UserPreferences {
+	if s.userProfileRepo == nil || userID == "" {
+		return nil
+	}
+
+	profile, err := s.userProfileRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to load user preferences, using the default explanation",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil
+	}
+	if profile == nil || profile.Preferences.IsZero() {
+		return nil
+	}
+	return &profile.Preferences
+}
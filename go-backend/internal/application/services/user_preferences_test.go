@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/mathprereq/internal/domain/services"
+)
+
+type memoryUserProfileRepo struct {
+	repositories.UserProfileRepository
+	profiles map[string]*entities.UserProfile
+}
+
+func (r *memoryUserProfileRepo) Save(ctx context.Context, profile *entities.UserProfile) error {
+	if r.profiles == nil {
+		r.profiles = make(map[string]*entities.UserProfile)
+	}
+	r.profiles[profile.UserID] = profile
+	return nil
+}
+
+func (r *memoryUserProfileRepo) FindByUserID(ctx context.Context, userID string) (*entities.UserProfile, error) {
+	return r.profiles[userID], nil
+}
+
+func TestUpdateUserPreferencesNormalizesAndValidates(t *testing.T) {
+	profiles := &memoryUserProfileRepo{}
+	svc := &queryService{userProfileRepo: profiles}
+
+	saved, err := svc.UpdateUserPreferences(context.Background(), "u1", entities.UserPreferences{
+		ExplanationLevel: " Beginner ",
+		ExplanationStyle: "STEP_BY_STEP",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ExplanationLevel != entities.ExplanationLevelBeginner || saved.ExplanationStyle != entities.ExplanationStyleStepByStep {
+		t.Errorf("expected normalized preferences, got %+v", saved)
+	}
+
+	if _, err := svc.UpdateUserPreferences(context.Background(), "u1", entities.UserPreferences{ExplanationLevel: "expert"}); err == nil {
+		t.Error("expected an unsupported level to be rejected")
+	}
+	if _, err := svc.UpdateUserPreferences(context.Background(), "", entities.UserPreferences{}); err == nil {
+		t.Error("expected a missing user ID to be rejected")
+	}
+
+	got, err := svc.GetUserPreferences(context.Background(), "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *saved {
+		t.Errorf("expected stored preferences %+v, got %+v", saved, got)
+	}
+	if none, _ := svc.GetUserPreferences(context.Background(), "u2"); none == nil || !none.IsZero() {
+		t.Errorf("expected empty preferences for a user without a profile, got %+v", none)
+	}
+}
+
+func TestProcessQueryAppliesStoredPreferences(t *testing.T) {
+	svc, llm, queryRepo, tasks := newQuestionCacheService(config.QueryCacheConfig{Enabled: true, TTL: time.Hour})
+	svc.userProfileRepo = &memoryUserProfileRepo{profiles: map[string]*entities.UserProfile{
+		"u1": {UserID: "u1", Preferences: entities.UserPreferences{
+			ExplanationLevel: entities.ExplanationLevelBeginner,
+			ExplanationStyle: entities.ExplanationStyleIntuitive,
+		}},
+	}}
+
+	ask(t, svc, tasks, &services.QueryRequest{Question: "What is a limit?"})
+	ask(t, svc, tasks, &services.QueryRequest{Question: "What is a limit?", UserID: "u1"})
+
+	if len(llm.requests) != 2 {
+		t.Fatalf("expected the tailored query to skip the cache, got %d explanation calls", len(llm.requests))
+	}
+	if req := llm.requests[0]; req.Level != "" || req.Style != "" {
+		t.Errorf("expected the anonymous query to use the default explanation, got %+v", req)
+	}
+	if req := llm.requests[1]; req.Level != entities.ExplanationLevelBeginner || req.Style != entities.ExplanationStyleIntuitive {
+		t.Errorf("expected the stored preferences in the explanation request, got %+v", req)
+	}
+	if saved := queryRepo.saved[1]; saved.Preferences == nil || saved.Preferences.ExplanationLevel != entities.ExplanationLevelBeginner {
+		t.Errorf("expected the preferences stored with the query, got %+v", saved.Preferences)
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mathprereq/internal/background"
+	"github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/types"
+	"go.uber.org/zap"
+)
+
+// recordingVectorRepo records which search the pipeline ran
+type recordingVectorRepo struct {
+	stubVectorRepo
+	text     string
+	concepts []string
+	limit    int
+}
+
+func (r *recordingVectorRepo) Search(ctx context.Context, query string, limit int) ([]types.VectorResult, error) {
+	r.text = query
+	r.limit = limit
+	return nil, nil
+}
+
+func (r *recordingVectorRepo) SearchByConcepts(ctx context.Context, concepts []string, limit int) ([]types.VectorResult, error) {
+	r.concepts = concepts
+	r.limit = limit
+	return nil, nil
+}
+
+func TestProcessQueryVectorSearchMode(t *testing.T) {
+	tests := []struct {
+		name             string
+		searchByConcepts bool
+		wantText         string
+		wantConcepts     []string
+	}{
+		{"concepts", true, "", []string{"derivatives", "chain rule"}},
+		{"query", false, "How do I differentiate sin(x^2)?", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vectors := &recordingVectorRepo{}
+			tasks := background.NewTasks()
+			svc := &queryService{
+				conceptRepo: &pathConceptRepo{},
+				queryRepo:   &savingQueryRepo{},
+				vectorRepo:  vectors,
+				llmClient: &recordingLLM{
+					concepts:    []string{"derivatives", "chain rule"},
+					explanation: map[string]string{"English": "Use the chain rule."},
+				},
+				searchByConcepts: tt.searchByConcepts,
+				sampler:          &analyticsSampler{sampleRate: 1},
+				tasks:            tasks,
+				logger:           zap.NewNop(),
+			}
+
+			if _, err := svc.ProcessQuery(context.Background(), &services.QueryRequest{Question: "How do I differentiate sin(x^2)?"}); err != nil {
+				t.Fatal(err)
+			}
+			drainTasks(t, tasks)
+
+			if vectors.text != tt.wantText {
+				t.Errorf("Search text = %q, want %q", vectors.text, tt.wantText)
+			}
+			if !slices.Equal(vectors.concepts, tt.wantConcepts) {
+				t.Errorf("SearchByConcepts concepts = %v, want %v", vectors.concepts, tt.wantConcepts)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Package arithmetic sanity-checks the numeric steps of generated
+// explanations. Equalities between plain numeric expressions, such as
+// "3 * (4 + 2) = 18", are evaluated and flagged when the sides disagree.
+// It is a best-effort guard against slips, not symbolic verification: steps
+// involving variables are skipped.
+package arithmetic
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mathprereq/internal/visuals"
+)
+
+// Report statuses
+const (
+	StatusVerified  = "verified"  // every checkable step holds
+	StatusFlagged   = "flagged"   // at least one step is wrong
+	StatusUnchecked = "unchecked" // no plain numeric steps were found
+)
+
+// Report summarizes the numeric steps checked in an explanation
+type Report struct {
+	Status  string  `json:"status" bson:"status"`
+	Checked int     `json:"checked" bson:"checked"`
+	Issues  []Issue `json:"issues,omitempty" bson:"issues,omitempty"`
+}
+
+// Issue is a step whose two sides evaluate to different values
+type Issue struct {
+	Step  string  `json:"step" bson:"step"` // the equality as it was checked
+	Left  float64 `json:"left" bson:"left"`
+	Right float64 `json:"right" bson:"right"`
+}
+
+var (
+	// notation rewrites LaTeX and typographic operators into parser syntax
+	notation = strings.NewReplacer(
+		`\times`, "*", `\cdot`, "*", "×", "*", "·", "*", "∙", "*",
+		`\div`, "/", "÷", "/", "−", "-", `\left`, "", `\right`, "",
+		"$", " ", `\(`, " ", `\)`, " ", `\[`, " ", `\]`, " ",
+	)
+	latexFraction = regexp.MustCompile(`\\[dt]?frac\{([^{}]*)\}\{([^{}]*)\}`)
+
+	// equalityChain matches runs of numeric expressions joined by "="
+	equalityChain = regexp.MustCompile(`[-0-9.()+*/^ \t]+(?:=[-0-9.()+*/^ \t]+)+`)
+
+	// Numbers separated only by a space are a list marker or prose
+	// ("1. 2 + 2"), not implicit multiplication
+	spacedNumbers = regexp.MustCompile(`[0-9.]\s+[0-9.(]`)
+	operator      = regexp.MustCompile(`[-+*/^()]`)
+)
+
+// Check evaluates every plain numeric equality in text
+func Check(text string) Report {
+	report := Report{Status: StatusUnchecked}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = latexFraction.ReplaceAllString(notation.Replace(line), "(($1)/($2))")
+		for _, loc := range equalityChain.FindAllStringIndex(line, -1) {
+			parts, ok := chainParts(line, loc[0], loc[1])
+			if !ok {
+				continue
+			}
+
+			values := make([]float64, len(parts))
+			for i, part := range parts {
+				value, err := visuals.EvaluateConstant(part)
+				if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+					ok = false
+					break
+				}
+				values[i] = value
+			}
+			if !ok {
+				continue
+			}
+
+			for i := 0; i+1 < len(parts); i++ {
+				report.Checked++
+				if !agree(parts[i], values[i], parts[i+1], values[i+1]) {
+					report.Issues = append(report.Issues, Issue{
+						Step:  parts[i] + " = " + parts[i+1],
+						Left:  values[i],
+						Right: values[i+1],
+					})
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(report.Issues) > 0:
+		report.Status = StatusFlagged
+	case report.Checked > 0:
+		report.Status = StatusVerified
+	}
+	return report
+}
+
+// chainParts splits the chain at line[start:end] on "=", rejecting matches
+// that are only the tail or head of a larger expression with variables,
+// e.g. the "+ 3 = 7" of "2x + 3 = 7"
+func chainParts(line string, start, end int) ([]string, bool) {
+	// A chain right after "x =" starts at the "="; the left side is not
+	// numeric. A trailing period ends the sentence.
+	chain := strings.TrimLeft(strings.TrimSpace(line[start:end]), "= ")
+	chain = strings.TrimRight(chain, ". ")
+	start += strings.Index(line[start:end], chain)
+	end = start + len(chain)
+
+	if chain == "" || !(isDigit(chain[0]) || chain[0] == '(') {
+		return nil, false
+	}
+	if start > 0 {
+		prev := line[start-1]
+		if isWordByte(prev) || strings.ContainsRune("_\\.,)}]^", rune(prev)) {
+			return nil, false
+		}
+	}
+	if end < len(line) {
+		next := line[end]
+		if isWordByte(next) || strings.ContainsRune("_^{\\", rune(next)) {
+			return nil, false
+		}
+		// Thousands separator, e.g. the "1" of "= 1,000"
+		if next == ',' && end+1 < len(line) && isDigit(line[end+1]) {
+			return nil, false
+		}
+	}
+	if spacedNumbers.MatchString(chain) {
+		return nil, false
+	}
+
+	parts := strings.Split(chain, "=")
+	if len(parts) < 2 {
+		return nil, false
+	}
+	hasOperator := false
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+		if parts[i] == "" {
+			return nil, false
+		}
+		if operator.MatchString(strings.TrimLeft(parts[i], "-")) {
+			hasOperator = true
+		}
+	}
+	// "3 = 3" states a value rather than computing one
+	return parts, hasOperator
+}
+
+// agree compares two sides, allowing for the rounding of a side written as a
+// decimal such as 3.33 for 10/3
+func agree(leftText string, left float64, rightText string, right float64) bool {
+	tolerance := 1e-9 * math.Max(1, math.Max(math.Abs(left), math.Abs(right)))
+	for _, text := range []string{leftText, rightText} {
+		if decimals, ok := literalDecimals(text); ok && decimals > 0 {
+			tolerance = math.Max(tolerance, 0.5*math.Pow(10, -float64(decimals))+1e-12)
+		}
+	}
+	return math.Abs(left-right) <= tolerance
+}
+
+// literalDecimals reports how many decimal places a plain number is written
+// with, and false if text is not a plain number
+func literalDecimals(text string) (int, bool) {
+	text = strings.TrimPrefix(text, "-")
+	if text == "" {
+		return 0, false
+	}
+	for _, r := range text {
+		if !unicode.IsDigit(r) && r != '.' {
+			return 0, false
+		}
+	}
+	dot := strings.IndexByte(text, '.')
+	if dot < 0 {
+		return 0, true
+	}
+	return len(text) - dot - 1, true
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isWordByte(b byte) bool {
+	return isDigit(b) || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
@@ -0,0 +1,68 @@
+package arithmetic
+
+import (
+	"testing"
+)
+
+func TestCheckFlagsWrongStep(t *testing.T) {
+	explanation := `To find the area of the rectangle:
+1. Multiply the sides: 12 * 7 = 84
+2. Add the border strips: 84 + 2 * 6 = 98
+3. So the total area is 98 square units.`
+
+	report := Check(explanation)
+	if report.Status != StatusFlagged {
+		t.Fatalf("Status = %q, want %q", report.Status, StatusFlagged)
+	}
+	if report.Checked != 2 {
+		t.Errorf("Checked = %d, want 2", report.Checked)
+	}
+	if len(report.Issues) != 1 {
+		t.Fatalf("Issues = %+v, want one", report.Issues)
+	}
+	issue := report.Issues[0]
+	if issue.Step != "84 + 2 * 6 = 98" || issue.Left != 96 || issue.Right != 98 {
+		t.Errorf("Issue = %+v, want 84 + 2 * 6 = 98 with sides 96 and 98", issue)
+	}
+}
+
+func TestCheckVerifiesCorrectSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		checked int
+	}{
+		{"chain", "We get 3 * (4 + 2) = 3 * 6 = 18.", 2},
+		{"after a variable", "Then x = 2^3 - 1 = 7, so x is odd.", 1},
+		{"rounded decimal", "The ratio is 10 / 3 = 3.33", 1},
+		{"latex", `$\frac{1}{2} + \frac{1}{4} = \frac{3}{4}$ and $6 \times 7 = 42$`, 2},
+		{"unicode operators", "12 ÷ 4 = 3 and 2 × 5 − 1 = 9", 2},
+		{"negative result", "3 - 5 = -2", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Check(tt.text)
+			if report.Status != StatusVerified || report.Checked != tt.checked {
+				t.Errorf("Check(%q) = %+v, want verified with %d checked", tt.text, report, tt.checked)
+			}
+		})
+	}
+}
+
+func TestCheckSkipsStepsWithVariables(t *testing.T) {
+	for _, text := range []string{
+		"Solve 2x + 3 = 7 for x.",
+		"Since x^2 = 4, x is 2 or -2.",
+		"f(2) = 5 because f(x) = x + 3",
+		"The derivative of x^3 is 3x^2 = 12 at x = 2",
+		"Population grew to 1,000 + 50 = 1,050",
+		"1. 2 + 2 = 4",
+		"The answer is 5 = 5",
+		"No arithmetic here.",
+	} {
+		if report := Check(text); report.Status != StatusUnchecked || len(report.Issues) > 0 {
+			t.Errorf("Check(%q) = %+v, want unchecked", text, report)
+		}
+	}
+}
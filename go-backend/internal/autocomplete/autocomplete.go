@@ -0,0 +1,216 @@
+// Package autocomplete matches concept names by prefix from an in-memory
+// trie, so search boxes can suggest concepts on every keystroke without a
+// graph query.
+package autocomplete
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Entry is one suggestible concept
+type Entry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Aliases are other terms the concept is found by, such as "ibp"
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// keyed is an entry reachable under one key. Primary keys are the full
+// name; the others are later words of the name, the ID spelled out and
+// aliases.
+type keyed struct {
+	entry   Entry
+	primary bool
+}
+
+type node struct {
+	children map[rune]*node
+	entries  []keyed
+}
+
+// Index is a prefix trie over concept names. It is safe for concurrent use;
+// Rebuild swaps in a whole new trie so readers never see a partial one. A
+// nil *Index ignores Add.
+type Index struct {
+	mu    sync.RWMutex
+	root  *node
+	ids   map[string]bool
+	ready bool
+}
+
+// NewIndex creates an empty index that is not yet Ready
+func NewIndex() *Index {
+	return &Index{root: &node{}, ids: map[string]bool{}}
+}
+
+// Rebuild replaces the index contents with entries
+func (i *Index) Rebuild(entries []Entry) {
+	root := &node{}
+	ids := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.ID == "" || ids[entry.ID] {
+			continue
+		}
+		ids[entry.ID] = true
+		insert(root, entry)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.root, i.ids, i.ready = root, ids, true
+}
+
+// Add indexes one entry, e.g. a concept created since the last Rebuild.
+// Entries already indexed are left alone.
+func (i *Index) Add(entry Entry) {
+	if i == nil || entry.ID == "" {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.ids[entry.ID] {
+		return
+	}
+	i.ids[entry.ID] = true
+	insert(i.root, entry)
+}
+
+// Ready reports whether the index has been built at least once
+func (i *Index) Ready() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.ready
+}
+
+// Len is the number of indexed entries
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.ids)
+}
+
+// Match returns up to limit entries with a key starting with prefix.
+// Names starting with the prefix come first, then shorter names, then
+// alphabetical order.
+func (i *Index) Match(prefix string, limit int) []Entry {
+	prefix = normalize(prefix)
+	if prefix == "" || limit <= 0 {
+		return []Entry{}
+	}
+
+	i.mu.RLock()
+	start := i.root
+	for _, r := range prefix {
+		start = start.children[r]
+		if start == nil {
+			break
+		}
+	}
+	best := map[string]keyed{}
+	if start != nil {
+		collect(start, best)
+	}
+	i.mu.RUnlock()
+
+	found := make([]keyed, 0, len(best))
+	for _, k := range best {
+		found = append(found, k)
+	}
+	sort.Slice(found, func(a, b int) bool {
+		x, y := found[a], found[b]
+		if x.primary != y.primary {
+			return x.primary
+		}
+		if len(x.entry.Name) != len(y.entry.Name) {
+			return len(x.entry.Name) < len(y.entry.Name)
+		}
+		return x.entry.Name < y.entry.Name
+	})
+
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	matches := make([]Entry, len(found))
+	for j, k := range found {
+		matches[j] = k.entry
+	}
+	return matches
+}
+
+// collect gathers every entry below n, keeping the primary key of an entry
+// reachable more than once
+func collect(n *node, best map[string]keyed) {
+	for _, k := range n.entries {
+		if existing, ok := best[k.entry.ID]; !ok || (k.primary && !existing.primary) {
+			best[k.entry.ID] = k
+		}
+	}
+	for _, child := range n.children {
+		collect(child, best)
+	}
+}
+
+func insert(root *node, entry Entry) {
+	for j, key := range keys(entry) {
+		n := root
+		for _, r := range key {
+			if n.children == nil {
+				n.children = map[rune]*node{}
+			}
+			child := n.children[r]
+			if child == nil {
+				child = &node{}
+				n.children[r] = child
+			}
+			n = child
+		}
+		n.entries = append(n.entries, keyed{entry: entry, primary: j == 0})
+	}
+}
+
+// keys lists what an entry can be found by: its name first, then each later
+// word of the name ("rule" finds "Chain Rule"), its ID when that reads
+// differently ("integration_by_parts") and its aliases
+func keys(entry Entry) []string {
+	name := normalize(entry.Name)
+	if name == "" {
+		name = normalize(entry.ID)
+	}
+	result := []string{name}
+	seen := map[string]bool{name: true}
+
+	words := strings.Fields(name)
+	for j := 1; j < len(words); j++ {
+		key := strings.Join(words[j:], " ")
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+
+	if id := normalize(strings.NewReplacer("_", " ", "-", " ").Replace(entry.ID)); id != "" && !seen[id] {
+		seen[id] = true
+		result = append(result, id)
+	}
+
+	for _, alias := range entry.Aliases {
+		if key := normalize(alias); key != "" && !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// normalize lowercases s and collapses runs of spaces and punctuation other
+// than apostrophes into single spaces
+func normalize(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return unicode.IsSpace(r) || (unicode.IsPunct(r) && r != '\'')
+	})
+	return strings.Join(fields, " ")
+}
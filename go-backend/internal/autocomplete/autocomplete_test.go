@@ -0,0 +1,108 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func names(entries []Entry) []string {
+	result := make([]string, len(entries))
+	for i, e := range entries {
+		result[i] = e.Name
+	}
+	return result
+}
+
+func newTestIndex() *Index {
+	index := NewIndex()
+	index.Rebuild([]Entry{
+		{ID: "derivatives", Name: "Derivatives"},
+		{ID: "definite_integrals", Name: "Definite Integrals"},
+		{ID: "determinants", Name: "Determinants"},
+		{ID: "chain_rule", Name: "Chain Rule"},
+		{ID: "integration_by_parts", Name: "Integration by Parts"},
+		{ID: "limits", Name: "Limits"},
+	})
+	return index
+}
+
+func TestMatchByPrefix(t *testing.T) {
+	index := newTestIndex()
+
+	got := names(index.Match("De", 10))
+	want := []string{"Derivatives", "Determinants", "Definite Integrals"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Match(De) = %v, want %v", got, want)
+	}
+
+	if got := names(index.Match("  deri ", 10)); !reflect.DeepEqual(got, []string{"Derivatives"}) {
+		t.Errorf("expected whitespace and case to be ignored, got %v", got)
+	}
+	if got := index.Match("xyz", 10); len(got) != 0 {
+		t.Errorf("expected no matches, got %v", got)
+	}
+}
+
+func TestMatchRanksNamePrefixesBeforeWords(t *testing.T) {
+	index := newTestIndex()
+
+	got := names(index.Match("int", 10))
+	want := []string{"Integration by Parts", "Definite Integrals"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Match(int) = %v, want %v", got, want)
+	}
+	if got := names(index.Match("rule", 10)); !reflect.DeepEqual(got, []string{"Chain Rule"}) {
+		t.Errorf("expected later words to match, got %v", got)
+	}
+}
+
+func TestMatchByAlias(t *testing.T) {
+	index := NewIndex()
+	index.Rebuild([]Entry{
+		{ID: "integration_by_parts", Name: "Integration by Parts", Aliases: []string{"IBP"}},
+		{ID: "limits", Name: "Limits"},
+	})
+
+	if got := names(index.Match("ib", 10)); !reflect.DeepEqual(got, []string{"Integration by Parts"}) {
+		t.Errorf("expected the alias to find its concept, got %v", got)
+	}
+}
+
+func TestMatchBoundsResults(t *testing.T) {
+	index := newTestIndex()
+	if got := index.Match("d", 2); len(got) != 2 {
+		t.Errorf("expected 2 matches, got %v", got)
+	}
+	if got := index.Match("d", 0); len(got) != 0 {
+		t.Errorf("expected no matches for limit 0, got %v", got)
+	}
+}
+
+func TestAddAndRebuild(t *testing.T) {
+	index := newTestIndex()
+	index.Add(Entry{ID: "differential_equations", Name: "Differential Equations"})
+	index.Add(Entry{ID: "derivatives", Name: "Derivatives"})
+
+	if got := names(index.Match("diff", 10)); !reflect.DeepEqual(got, []string{"Differential Equations"}) {
+		t.Errorf("expected added concept to match, got %v", got)
+	}
+	if index.Len() != 7 {
+		t.Errorf("expected duplicates to be ignored, got %d entries", index.Len())
+	}
+
+	index.Rebuild([]Entry{{ID: "limits", Name: "Limits"}})
+	if got := index.Match("d", 10); len(got) != 0 {
+		t.Errorf("expected rebuild to replace the contents, got %v", got)
+	}
+}
+
+func TestNewIndexIsNotReady(t *testing.T) {
+	index := NewIndex()
+	if index.Ready() {
+		t.Error("expected a new index not to be ready")
+	}
+	index.Rebuild(nil)
+	if !index.Ready() {
+		t.Error("expected a rebuilt index to be ready")
+	}
+}
@@ -0,0 +1,206 @@
+package background
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// PoolStats is a snapshot of a worker pool's load
+type PoolStats struct {
+	Name      string
+	Workers   int
+	Queued    int
+	Active    int
+	Completed uint64
+	Dropped   uint64
+}
+
+type poolJob struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// Pool runs submitted jobs on a fixed number of workers, queueing up to a
+// bounded number of jobs. When the queue is full new jobs are dropped rather
+// than blocking the caller. A nil *Pool drops every job.
+type Pool struct {
+	name    string
+	workers int
+	queue   chan poolJob
+	logger  *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex
+	stopped   bool
+	queued    map[string]int
+	active    map[int]string
+	completed uint64
+	dropped   uint64
+}
+
+// NewPool starts workers goroutines consuming a queue of queueSize jobs
+func NewPool(name string, workers, queueSize int, logger *zap.Logger) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		name:    name,
+		workers: workers,
+		queue:   make(chan poolJob, queueSize),
+		logger:  logger.With(zap.String("pool", name)),
+		ctx:     ctx,
+		cancel:  cancel,
+		queued:  make(map[string]int),
+		active:  make(map[int]string),
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work(i)
+	}
+	return p
+}
+
+// Submit queues fn under name and reports whether it was accepted. Jobs are
+// rejected once the queue is full or the pool is stopping. fn receives a
+// context that is cancelled if Stop gives up waiting for it.
+func (p *Pool) Submit(name string, fn func(ctx context.Context)) bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		p.dropped++
+		return false
+	}
+
+	select {
+	case p.queue <- poolJob{name: name, run: fn}:
+		p.queued[name]++
+		return true
+	default:
+		p.dropped++
+		p.logger.Warn("Worker pool queue full, dropping job",
+			zap.String("job", name),
+			zap.Int("queue_size", cap(p.queue)))
+		return false
+	}
+}
+
+func (p *Pool) work(worker int) {
+	defer p.wg.Done()
+
+	for job := range p.queue {
+		p.mu.Lock()
+		if p.queued[job.name]--; p.queued[job.name] == 0 {
+			delete(p.queued, job.name)
+		}
+		p.active[worker] = job.name
+		p.mu.Unlock()
+
+		p.runJob(job)
+
+		p.mu.Lock()
+		delete(p.active, worker)
+		p.completed++
+		p.mu.Unlock()
+	}
+}
+
+func (p *Pool) runJob(job poolJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("Worker pool job panicked",
+				zap.String("job", job.name),
+				zap.Any("panic", r))
+		}
+	}()
+	job.run(p.ctx)
+}
+
+// Stats returns the pool's current queue depth, active jobs and totals
+func (p *Pool) Stats() PoolStats {
+	if p == nil {
+		return PoolStats{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return PoolStats{
+		Name:      p.name,
+		Workers:   p.workers,
+		Queued:    len(p.queue),
+		Active:    len(p.active),
+		Completed: p.completed,
+		Dropped:   p.dropped,
+	}
+}
+
+// Pending returns the names of running and queued jobs
+func (p *Pool) Pending() []string {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var names []string
+	for _, name := range p.active {
+		names = append(names, name)
+	}
+	for name, count := range p.queued {
+		for i := 0; i < count; i++ {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stop rejects new jobs and waits for queued and running jobs to finish. If
+// ctx ends first, the jobs' context is cancelled and the names of jobs still
+// running or queued are returned; otherwise it returns nil.
+func (p *Pool) Stop(ctx context.Context) []string {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		pending := p.Pending()
+		p.cancel()
+		return pending
+	}
+}
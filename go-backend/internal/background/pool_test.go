@@ -0,0 +1,83 @@
+package background
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestPoolBoundsConcurrentJobs(t *testing.T) {
+	pool := NewPool("scrape", 2, 10, zap.NewNop())
+
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		accepted := pool.Submit("scrape_resources", func(ctx context.Context) {
+			defer wg.Done()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		})
+		if !accepted {
+			t.Fatalf("job %d rejected with room in the queue", i)
+		}
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("expected at most 2 concurrent jobs, saw %d", peak)
+	}
+	if pending := pool.Stop(context.Background()); pending != nil {
+		t.Errorf("expected pool to drain, pending: %v", pending)
+	}
+	if stats := pool.Stats(); stats.Completed != 8 || stats.Dropped != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestPoolDropsWhenQueueFull(t *testing.T) {
+	pool := NewPool("scrape", 1, 1, zap.NewNop())
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	pool.Submit("busy", func(ctx context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+
+	if !pool.Submit("queued", func(ctx context.Context) {}) {
+		t.Fatal("expected the first queued job to be accepted")
+	}
+	if pool.Submit("overflow", func(ctx context.Context) {}) {
+		t.Fatal("expected a job beyond the queue size to be dropped")
+	}
+
+	stats := pool.Stats()
+	if stats.Active != 1 || stats.Queued != 1 || stats.Dropped != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if pending := pool.Stop(ctx); !reflect.DeepEqual(pending, []string{"busy", "queued"}) {
+		t.Errorf("pending = %v, want [busy queued]", pending)
+	}
+	close(release)
+
+	if pool.Submit("late", func(ctx context.Context) {}) {
+		t.Error("expected jobs submitted after Stop to be rejected")
+	}
+}
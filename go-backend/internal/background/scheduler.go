@@ -0,0 +1,247 @@
+package background
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job is a periodic maintenance task run by the Scheduler
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// JobStats summarizes a job's runs since the scheduler started
+type JobStats struct {
+	Name         string        `json:"name"`
+	Interval     time.Duration `json:"interval"`
+	Runs         int64         `json:"runs"`
+	Failures     int64         `json:"failures"`
+	Skipped      int64         `json:"skipped"`
+	Running      bool          `json:"running"`
+	LastStarted  time.Time     `json:"last_started,omitempty"`
+	LastDuration time.Duration `json:"last_duration"`
+	LastError    string        `json:"last_error,omitempty"`
+}
+
+type scheduledJob struct {
+	job Job
+
+	mu    sync.Mutex
+	stats JobStats
+}
+
+// Scheduler runs registered jobs at fixed intervals. A run that is still in
+// progress when the next tick arrives causes that tick to be skipped, so a
+// job never overlaps with itself.
+type Scheduler struct {
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	jobs    map[string]*scheduledJob
+	started bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler with no jobs
+func NewScheduler(logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		jobs:   make(map[string]*scheduledJob),
+	}
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job needs a name and a run function")
+	}
+	if job.Interval <= 0 {
+		return fmt.Errorf("job %s: interval must be positive", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("job %s: scheduler already started", job.Name)
+	}
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+
+	s.jobs[job.Name] = &scheduledJob{
+		job:   job,
+		stats: JobStats{Name: job.Name, Interval: job.Interval},
+	}
+	return nil
+}
+
+// Start begins ticking every registered job. The first run of each job
+// happens one interval after Start.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, sj := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, sj)
+	}
+
+	s.logger.Info("Scheduler started", zap.Int("jobs", len(s.jobs)))
+}
+
+// Stop stops scheduling new runs, cancels the context of running jobs and
+// waits for them to return or ctx to end
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduled jobs still running: %v", s.running())
+	}
+}
+
+// Stats returns per-job statistics sorted by job name
+func (s *Scheduler) Stats() []JobStats {
+	s.mu.Lock()
+	jobs := make([]*scheduledJob, 0, len(s.jobs))
+	for _, sj := range s.jobs {
+		jobs = append(jobs, sj)
+	}
+	s.mu.Unlock()
+
+	stats := make([]JobStats, 0, len(jobs))
+	for _, sj := range jobs {
+		sj.mu.Lock()
+		stats = append(stats, sj.stats)
+		sj.mu.Unlock()
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+func (s *Scheduler) running() []string {
+	var names []string
+	for _, stats := range s.Stats() {
+		if stats.Running {
+			names = append(names, stats.Name)
+		}
+	}
+	return names
+}
+
+// loop ticks one job until ctx is cancelled. Each run gets its own
+// goroutine so a slow run shows up as skipped ticks rather than drift.
+func (s *Scheduler) loop(ctx context.Context, sj *scheduledJob) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(sj.job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !sj.tryStart() {
+				s.logger.Warn("Skipping scheduled job, previous run still in progress",
+					zap.String("job", sj.job.Name))
+				continue
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.run(ctx, sj)
+			}()
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, sj *scheduledJob) {
+	start := time.Now()
+	err := runRecovered(ctx, sj.job.Run)
+	duration := time.Since(start)
+
+	sj.finish(duration, err)
+
+	if err != nil {
+		s.logger.Error("Scheduled job failed",
+			zap.String("job", sj.job.Name),
+			zap.Duration("duration", duration),
+			zap.Error(err))
+		return
+	}
+	s.logger.Info("Scheduled job completed",
+		zap.String("job", sj.job.Name),
+		zap.Duration("duration", duration))
+}
+
+// runRecovered turns a panicking job into an error so one bad job cannot
+// take the server down
+func runRecovered(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+// tryStart marks the job running, or counts a skipped tick if it already is
+func (sj *scheduledJob) tryStart() bool {
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	if sj.stats.Running {
+		sj.stats.Skipped++
+		return false
+	}
+	sj.stats.Running = true
+	sj.stats.LastStarted = time.Now()
+	return true
+}
+
+func (sj *scheduledJob) finish(duration time.Duration, err error) {
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	sj.stats.Running = false
+	sj.stats.Runs++
+	sj.stats.LastDuration = duration
+	sj.stats.LastError = ""
+	if err != nil {
+		sj.stats.Failures++
+		sj.stats.LastError = err.Error()
+	}
+}
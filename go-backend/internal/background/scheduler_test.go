@@ -0,0 +1,102 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestSchedulerRunsJobAtInterval(t *testing.T) {
+	s := NewScheduler(zap.NewNop())
+	var runs int32
+	if err := s.Register(Job{
+		Name:     "count",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Start()
+	time.Sleep(75 * time.Millisecond)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := atomic.LoadInt32(&runs)
+	if got < 3 {
+		t.Errorf("expected at least 3 runs in 75ms at a 10ms interval, got %d", got)
+	}
+	stats := s.Stats()
+	if len(stats) != 1 || stats[0].Runs != int64(got) || stats[0].Skipped != 0 {
+		t.Errorf("unexpected stats %+v for %d runs", stats, got)
+	}
+}
+
+func TestSchedulerSkipsOverlappingRuns(t *testing.T) {
+	s := NewScheduler(zap.NewNop())
+	release := make(chan struct{})
+	var active, maxActive, runs int32
+	if err := s.Register(Job{
+		Name:     "slow",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			n := atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			if n > atomic.LoadInt32(&maxActive) {
+				atomic.StoreInt32(&maxActive, n)
+			}
+			atomic.AddInt32(&runs, 1)
+			<-release
+			return errors.New("done")
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Start()
+	time.Sleep(60 * time.Millisecond)
+	close(release)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if atomic.LoadInt32(&maxActive) != 1 {
+		t.Errorf("expected at most one concurrent run, got %d", maxActive)
+	}
+	stats := s.Stats()[0]
+	if stats.Skipped == 0 {
+		t.Errorf("expected skipped ticks while the run was in progress, got %+v", stats)
+	}
+	if stats.Failures != int64(atomic.LoadInt32(&runs)) || stats.LastError != "done" {
+		t.Errorf("expected failures recorded for every run, got %+v", stats)
+	}
+}
+
+func TestSchedulerRegisterValidation(t *testing.T) {
+	s := NewScheduler(zap.NewNop())
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := s.Register(Job{Name: "bad", Run: noop}); err == nil {
+		t.Error("expected error for zero interval")
+	}
+	if err := s.Register(Job{Name: "a", Interval: time.Hour, Run: noop}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register(Job{Name: "a", Interval: time.Hour, Run: noop}); err == nil {
+		t.Error("expected error for duplicate job name")
+	}
+
+	s.Start()
+	defer s.Stop(context.Background())
+	if err := s.Register(Job{Name: "late", Interval: time.Hour, Run: noop}); err == nil {
+		t.Error("expected error registering after Start")
+	}
+}
@@ -0,0 +1,86 @@
+// Package background tracks fire-and-forget goroutines so they can be drained
+// during graceful shutdown, and schedules periodic maintenance jobs.
+package background
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Tasks runs and tracks named background goroutines. A nil *Tasks is valid
+// and simply starts goroutines untracked.
+type Tasks struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	nextID  uint64
+	pending map[uint64]string
+}
+
+// NewTasks creates an empty task tracker
+func NewTasks() *Tasks {
+	return &Tasks{pending: make(map[uint64]string)}
+}
+
+// Go runs fn in a new goroutine and tracks it under name until it returns
+func (t *Tasks) Go(name string, fn func()) {
+	if t == nil {
+		go fn()
+		return
+	}
+
+	t.mu.Lock()
+	t.nextID++
+	id := t.nextID
+	t.pending[id] = name
+	t.wg.Add(1)
+	t.mu.Unlock()
+
+	go func() {
+		defer func() {
+			t.mu.Lock()
+			delete(t.pending, id)
+			t.mu.Unlock()
+			t.wg.Done()
+		}()
+		fn()
+	}()
+}
+
+// Pending returns the names of tasks that are still running
+func (t *Tasks) Pending() []string {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make([]string, 0, len(t.pending))
+	for _, name := range t.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Wait blocks until all tracked tasks finish or ctx ends. It returns the
+// names of tasks still running when ctx ended, or nil if everything drained.
+func (t *Tasks) Wait(ctx context.Context) []string {
+	if t == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return t.Pending()
+	}
+}
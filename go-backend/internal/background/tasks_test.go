@@ -0,0 +1,44 @@
+package background
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWaitDrainsFinishedTasks(t *testing.T) {
+	tasks := NewTasks()
+	finished := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		tasks.Go("work", func() {
+			time.Sleep(5 * time.Millisecond)
+			finished <- struct{}{}
+		})
+	}
+
+	if pending := tasks.Wait(context.Background()); pending != nil {
+		t.Fatalf("expected all tasks drained, still pending: %v", pending)
+	}
+	if len(finished) != 3 {
+		t.Errorf("expected 3 finished tasks, got %d", len(finished))
+	}
+}
+
+func TestWaitReportsPendingAtTimeout(t *testing.T) {
+	tasks := NewTasks()
+	release := make(chan struct{})
+	defer close(release)
+
+	tasks.Go("save_query", func() {})
+	tasks.Go("stage_concepts", func() { <-release })
+	tasks.Go("scrape_resources", func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	want := []string{"scrape_resources", "stage_concepts"}
+	if got := tasks.Wait(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("Wait() pending = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+// Package citations reads the markers explanations use to cite the numbered
+// context chunks they were given, such as "[Context 2]" or "[Context 1, 3]".
+// Markers are matched by their "Context" label so bracketed math like the
+// interval [0, 1] is not mistaken for a citation.
+package citations
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var (
+	// marker is one bracketed citation of one or more context numbers
+	marker = regexp.MustCompile(`(?i)\[\s*context\s+(\d+(?:\s*(?:,|and)\s*(?:context\s+)?\d+)*)\s*\]`)
+	number = regexp.MustCompile(`\d+`)
+)
+
+// Markers returns the context numbers cited in text, in order of first
+// citation and without repeats. Numbers outside 1..chunks name context the
+// model was not given and are dropped; nil means nothing was cited.
+func Markers(text string, chunks int) []int {
+	var cited []int
+	seen := make(map[int]bool)
+	for _, match := range marker.FindAllStringSubmatch(text, -1) {
+		for _, digits := range number.FindAllString(match[1], -1) {
+			n, err := strconv.Atoi(digits)
+			if err != nil || n < 1 || n > chunks || seen[n] {
+				continue
+			}
+			seen[n] = true
+			cited = append(cited, n)
+		}
+	}
+	return cited
+}
@@ -0,0 +1,31 @@
+package citations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarkers(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		chunks int
+		want   []int
+	}{
+		{"single", "A limit describes approach [Context 2].", 3, []int{2}},
+		{"list", "The chain rule [Context 3, 1] composes derivatives.", 3, []int{3, 1}},
+		{"repeated label", "See [Context 1 and Context 2].", 2, []int{1, 2}},
+		{"case and spacing", "As shown [ context 1 ] and again [CONTEXT 1].", 1, []int{1}},
+		{"out of range", "Cited [Context 0] and [Context 4] but also [Context 2].", 3, []int{2}},
+		{"math brackets", "On the interval [0, 1] the vector [2] is constant.", 3, nil},
+		{"no citations", "Derivatives measure rates of change.", 3, nil},
+		{"no context", "Cited [Context 1] without any context.", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markers(tt.text, tt.chunks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Markers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/mathprereq/internal/application/services"
+	"github.com/mathprereq/internal/background"
 	"github.com/mathprereq/internal/core/config"
 	"github.com/mathprereq/internal/core/llm"
 	"github.com/mathprereq/internal/data/mongodb"
@@ -15,8 +16,11 @@ import (
 	"github.com/mathprereq/internal/data/weaviate"
 	"github.com/mathprereq/internal/domain/repositories"
 	domainServices "github.com/mathprereq/internal/domain/services"
+	"github.com/mathprereq/internal/fallback"
 	infrastructurerepos "github.com/mathprereq/internal/infrastructure/repositories"
 	"github.com/mathprereq/internal/mailer"
+	"github.com/mathprereq/internal/scrapequeue"
+	"github.com/mathprereq/internal/selfcheck"
 	"github.com/mathprereq/pkg/logger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
@@ -37,6 +41,27 @@ type Container interface {
 	// Health check for all services
 	HealthCheck(ctx context.Context) map[string]bool
 
+	// DrainBackgroundTasks waits for background work to finish and returns
+	// the names of tasks still running when ctx ends
+	DrainBackgroundTasks(ctx context.Context) []string
+
+	// Scheduler runs periodic maintenance jobs; register jobs before starting it
+	Scheduler() *background.Scheduler
+
+	// ScrapePool runs background resource scrapes with bounded concurrency
+	ScrapePool() *background.Pool
+
+	// ScrapeQueue runs scrapes from the persistent job queue; nil when the
+	// queue is disabled or MongoDB is unavailable
+	ScrapeQueue() *scrapequeue.Worker
+
+	// SelfCheck verifies the dependencies are usable and records the report
+	// in the readiness gate
+	SelfCheck(ctx context.Context) *selfcheck.Report
+
+	// Readiness reports whether the startup self-check allows serving
+	Readiness() *selfcheck.Gate
+
 	// Graceful shutdown
 	Shutdown(ctx context.Context) error
 }
@@ -57,11 +82,39 @@ type AppContainer struct {
 	// Mailer
 	mailer *mailer.Mailer
 
+	// Templated explanations served when the LLM is unavailable
+	fallbackRenderer *fallback.Renderer
+
 	// Repositories
-	conceptRepo       repositories.ConceptRepository
-	queryRepo         repositories.QueryRepository
-	vectorRepo        repositories.VectorRepository
-	stagedConceptRepo repositories.StagedConceptRepository
+	conceptRepo        repositories.ConceptRepository
+	queryRepo          repositories.QueryRepository
+	vectorRepo         repositories.VectorRepository
+	stagedConceptRepo  repositories.StagedConceptRepository
+	visualAidRepo      repositories.VisualAidRepository
+	pathSnapshotRepo   repositories.PathSnapshotRepository
+	conceptProfileRepo repositories.ConceptProfileRepository
+	explanationRepo    repositories.ExplanationVersionRepository
+	closureRepo        repositories.PrerequisiteClosureRepository
+	formulaRepo        repositories.FormulaRepository
+	aliasRepo          repositories.ConceptAliasRepository
+	scrapeJobRepo      repositories.ScrapeJobRepository
+	userProfileRepo    repositories.UserProfileRepository
+	identificationRepo repositories.ConceptIdentificationRepository // nil unless the identification cache is on
+
+	// Background work started by services, drained on shutdown
+	tasks *background.Tasks
+
+	// Periodic maintenance jobs
+	scheduler *background.Scheduler
+
+	// Shared worker pool for background resource scraping
+	scrapePool *background.Pool
+
+	// Workers for the persistent scrape queue, started with the scheduler
+	scrapeQueue *scrapequeue.Worker
+
+	// Startup self-check results gating readiness
+	readiness *selfcheck.Gate
 
 	// Services
 	queryService domainServices.QueryService
@@ -71,8 +124,13 @@ func NewContainer(cfg *config.Config) (Container, error) {
 	logger := logger.MustGetLogger()
 
 	container := &AppContainer{
-		config: cfg,
-		logger: logger,
+		config:    cfg,
+		logger:    logger,
+		tasks:     background.NewTasks(),
+		scheduler: background.NewScheduler(logger),
+		scrapePool: background.NewPool("scrape",
+			cfg.Scraper.BackgroundWorkers, cfg.Scraper.QueueSize, logger),
+		readiness: selfcheck.NewGate(cfg.Server.SelfCheckRequired),
 	}
 
 	if err := container.initializeClients(); err != nil {
@@ -97,6 +155,10 @@ func NewContainer(cfg *config.Config) (Container, error) {
 		return nil, fmt.Errorf("failed to initialize scraper: %w", err)
 	}
 
+	if err := container.registerJobs(); err != nil {
+		return nil, fmt.Errorf("failed to register background jobs: %w", err)
+	}
+
 	logger.Info("Dependency injection container initialized successfully")
 	return container, nil
 }
@@ -121,6 +183,8 @@ func (c *AppContainer) initializeClientsEnhanced() error {
 		Password:       c.config.MongoDB.Password,
 		ConnectTimeout: c.config.MongoDB.ConnectTimeout,
 		QueryTimeout:   30 * time.Second,
+		MaxPoolSize:    uint64(c.config.MongoDB.MaxPoolSize),
+		MinPoolSize:    uint64(c.config.MongoDB.MinPoolSize),
 	}
 
 	// Use the enhanced client that tests write permissions
@@ -191,6 +255,16 @@ func (c *AppContainer) initializeRepositories() error {
 	// Import the actual repository implementations
 	var mongoRepo repositories.QueryRepository
 	var stagedConceptRepo repositories.StagedConceptRepository
+	var visualAidRepo repositories.VisualAidRepository
+	var pathSnapshotRepo repositories.PathSnapshotRepository
+	var conceptProfileRepo repositories.ConceptProfileRepository
+	var explanationRepo repositories.ExplanationVersionRepository
+	var closureRepo repositories.PrerequisiteClosureRepository
+	var formulaRepo repositories.FormulaRepository
+	var aliasRepo repositories.ConceptAliasRepository
+	var scrapeJobRepo repositories.ScrapeJobRepository
+	var userProfileRepo repositories.UserProfileRepository
+	var identificationRepo repositories.ConceptIdentificationRepository
 	if c.mongoClient != nil {
 		// Extract the raw mongo.Client from your wrapper
 		rawMongoClient := c.mongoClient.GetMongoClient()
@@ -201,7 +275,26 @@ func (c *AppContainer) initializeRepositories() error {
 				databaseName = "mathprereq" // default database name
 			}
 			mongoRepo = infrastructurerepos.NewMongoQueryRepository(rawMongoClient, databaseName, c.logger)
+			indexCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := infrastructurerepos.EnsureQueryIndexes(indexCtx, rawMongoClient, databaseName); err != nil {
+				c.logger.Warn("Failed to create indexes for queries", zap.Error(err))
+			}
+			cancel()
 			stagedConceptRepo = infrastructurerepos.NewMongoStagedConceptRepository(rawMongoClient, databaseName, c.logger)
+			visualAidRepo = infrastructurerepos.NewMongoVisualAidRepository(rawMongoClient, databaseName, c.logger)
+			pathSnapshotRepo = infrastructurerepos.NewMongoPathSnapshotRepository(rawMongoClient, databaseName, c.logger)
+			conceptProfileRepo = infrastructurerepos.NewMongoConceptProfileRepository(rawMongoClient, databaseName, c.logger)
+			explanationRepo = infrastructurerepos.NewMongoExplanationVersionRepository(rawMongoClient, databaseName, c.logger)
+			closureRepo = infrastructurerepos.NewMongoPrerequisiteClosureRepository(rawMongoClient, databaseName, c.logger)
+			formulaRepo = infrastructurerepos.NewMongoFormulaRepository(rawMongoClient, databaseName, c.logger)
+			aliasRepo = infrastructurerepos.NewMongoConceptAliasRepository(rawMongoClient, databaseName, c.logger)
+			userProfileRepo = infrastructurerepos.NewMongoUserProfileRepository(rawMongoClient, databaseName, c.logger)
+			if c.config.QueryCache.IdentifyEnabled {
+				identificationRepo = infrastructurerepos.NewMongoConceptIdentificationRepository(rawMongoClient, databaseName, c.logger)
+			}
+			if c.config.Scraper.PersistentQueue {
+				scrapeJobRepo = infrastructurerepos.NewMongoScrapeJobRepository(rawMongoClient, databaseName, c.logger)
+			}
 		} else {
 			c.logger.Warn("Raw MongoDB client is nil, using nil repository")
 		}
@@ -217,6 +310,16 @@ func (c *AppContainer) initializeRepositories() error {
 	c.queryRepo = mongoRepo
 	c.vectorRepo = weaviateRepo
 	c.stagedConceptRepo = stagedConceptRepo
+	c.visualAidRepo = visualAidRepo
+	c.pathSnapshotRepo = pathSnapshotRepo
+	c.conceptProfileRepo = conceptProfileRepo
+	c.explanationRepo = explanationRepo
+	c.closureRepo = closureRepo
+	c.formulaRepo = formulaRepo
+	c.aliasRepo = aliasRepo
+	c.scrapeJobRepo = scrapeJobRepo
+	c.userProfileRepo = userProfileRepo
+	c.identificationRepo = identificationRepo
 
 	c.logger.Info("All repositories initialized successfully")
 	return nil
@@ -254,16 +357,43 @@ func (c *AppContainer) initializeServices() error {
 	// Create LLM adapter
 	llmAdapter := services.NewLLMAdapter(c.llmClient)
 
+	renderer, err := fallback.NewRenderer(c.config.LLM.FallbackTemplatesDir)
+	if err != nil {
+		return fmt.Errorf("failed to load fallback explanation templates: %w", err)
+	}
+	c.fallbackRenderer = renderer
+
 	// Initialize query service with all dependencies (scraper will be added later)
 	c.queryService = services.NewQueryService(
 		c.conceptRepo,
 		c.queryRepo,
 		c.vectorRepo,
 		c.stagedConceptRepo,
+		c.visualAidRepo,
+		c.pathSnapshotRepo,
+		c.conceptProfileRepo,
+		c.explanationRepo,
+		c.closureRepo,
+		c.formulaRepo,
+		c.aliasRepo,
+		c.scrapeJobRepo,
+		c.userProfileRepo,
+		c.identificationRepo,
 		llmAdapter,
+		c.fallbackRenderer,
 		nil,                       // scraper will be set after initialization
 		c.mailer,                  // mailer
 		c.config.Mailer.AdminMail, // admin email
+		c.config.Analytics,
+		c.config.Staging,
+		c.config.LLM,
+		c.config.QueryCache,
+		c.config.Scraper,
+		c.config.Weaviate.SearchMode,
+		c.config.Weaviate.RetrievalSource,
+		c.config.Weaviate.ConceptQueryChunks,
+		c.tasks,
+		c.scrapePool,
 		c.logger,
 	)
 
@@ -302,6 +432,15 @@ func (c *AppContainer) initializeScraper() error {
 
 	c.resourceScraper = resourceScraper
 
+	if c.scrapeJobRepo != nil {
+		c.scrapeQueue = scrapequeue.NewWorker(c.scrapeJobRepo, resourceScraper.ScrapeResourcesForConcepts, scrapequeue.Config{
+			Workers:      c.config.Scraper.BackgroundWorkers,
+			Lease:        c.config.Scraper.QueueLease,
+			PollInterval: c.config.Scraper.QueuePollInterval,
+			RetryBackoff: c.config.Scraper.QueueRetryBackoff,
+		}, c.logger)
+	}
+
 	// Now update the query service with the scraper
 	if err := c.updateQueryServiceWithScraper(); err != nil {
 		return fmt.Errorf("failed to update query service with scraper: %w", err)
@@ -322,10 +461,31 @@ func (c *AppContainer) updateQueryServiceWithScraper() error {
 		c.queryRepo,
 		c.vectorRepo,
 		c.stagedConceptRepo,
+		c.visualAidRepo,
+		c.pathSnapshotRepo,
+		c.conceptProfileRepo,
+		c.explanationRepo,
+		c.closureRepo,
+		c.formulaRepo,
+		c.aliasRepo,
+		c.scrapeJobRepo,
+		c.userProfileRepo,
+		c.identificationRepo,
 		llmAdapter,
+		c.fallbackRenderer,
 		c.resourceScraper,
 		c.mailer,
 		c.config.Mailer.AdminMail,
+		c.config.Analytics,
+		c.config.Staging,
+		c.config.LLM,
+		c.config.QueryCache,
+		c.config.Scraper,
+		c.config.Weaviate.SearchMode,
+		c.config.Weaviate.RetrievalSource,
+		c.config.Weaviate.ConceptQueryChunks,
+		c.tasks,
+		c.scrapePool,
 		c.logger,
 	)
 
@@ -333,6 +493,79 @@ func (c *AppContainer) updateQueryServiceWithScraper() error {
 	return nil
 }
 
+// registerJobs schedules periodic maintenance. Jobs look the query service up
+// on each run since it is rebuilt once the scraper is ready.
+func (c *AppContainer) registerJobs() error {
+	if c.closureRepo == nil {
+		c.logger.Info("MongoDB not available, prerequisite closures will not be precomputed")
+	} else if err := c.scheduler.Register(background.Job{
+		Name:     "refresh_prerequisite_closures",
+		Interval: c.config.Neo4j.ClosureRefreshInterval,
+		Run: func(ctx context.Context) error {
+			_, err := c.queryService.RefreshPrerequisiteClosures(ctx)
+			return err
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := c.scheduler.Register(background.Job{
+		Name:     "refresh_concept_index",
+		Interval: c.config.Neo4j.ConceptIndexRefreshInterval,
+		Run: func(ctx context.Context) error {
+			_, err := c.queryService.RefreshConceptIndex(ctx)
+			return err
+		},
+	}); err != nil {
+		return err
+	}
+
+	if c.config.Staging.DemandHalfLife > 0 && c.stagedConceptRepo != nil {
+		if err := c.scheduler.Register(background.Job{
+			Name:     "decay_staged_concept_demand",
+			Interval: c.config.Staging.DemandDecayInterval,
+			Run: func(ctx context.Context) error {
+				_, err := c.queryService.DecayStagedConceptDemand(ctx)
+				return err
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Staging.DigestEnabled && c.stagedConceptRepo != nil {
+		if err := c.scheduler.Register(background.Job{
+			Name:     "staged_concept_digest",
+			Interval: c.config.Staging.DigestInterval(),
+			Run:      c.queryService.SendStagedConceptDigest,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// A required self-check that failed at startup is retried until it
+	// passes, so the instance becomes ready without a restart once the
+	// migration runs or the LLM recovers
+	if c.readiness.Required() {
+		if err := c.scheduler.Register(background.Job{
+			Name:     "startup_self_check",
+			Interval: c.config.Server.SelfCheckRetryInterval,
+			Run: func(ctx context.Context) error {
+				if c.readiness.Ready() {
+					return nil
+				}
+				if report := c.SelfCheck(ctx); !report.Passed {
+					return fmt.Errorf("self-check failed: %s", strings.Join(report.Failed(), ", "))
+				}
+				return nil
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Service accessors
 func (c *AppContainer) QueryService() domainServices.QueryService {
 	return c.queryService
@@ -392,7 +625,45 @@ func (c *AppContainer) HealthCheck(ctx context.Context) map[string]bool {
 	return health
 }
 
-// Graceful shutdown
+// SelfCheck runs the startup checks and records the report in the
+// readiness gate
+func (c *AppContainer) SelfCheck(ctx context.Context) *selfcheck.Report {
+	checks := []selfcheck.Check{
+		selfcheck.GraphNotEmpty(c.conceptRepo),
+		selfcheck.VectorClass(c.weaviateClient, c.weaviateClient.ClassName(), weaviate.Vectorizer),
+		selfcheck.VectorClass(c.weaviateClient, c.weaviateClient.ConceptClassName(), weaviate.Vectorizer),
+		selfcheck.LLMResponds(c.llmClient),
+	}
+	report := selfcheck.Run(ctx, checks, c.config.Server.SelfCheckTimeout)
+	c.readiness.Record(report)
+	return report
+}
+
+func (c *AppContainer) Readiness() *selfcheck.Gate {
+	return c.readiness
+}
+
+func (c *AppContainer) Scheduler() *background.Scheduler {
+	return c.scheduler
+}
+
+func (c *AppContainer) ScrapePool() *background.Pool {
+	return c.scrapePool
+}
+
+func (c *AppContainer) ScrapeQueue() *scrapequeue.Worker {
+	return c.scrapeQueue
+}
+
+// Graceful shutdown. Tracked tasks are drained first since they may still
+// queue scrapes, then the scrape pool finishes its queue and queue workers
+// finish their current jobs; jobs not yet claimed stay stored.
+func (c *AppContainer) DrainBackgroundTasks(ctx context.Context) []string {
+	pending := c.tasks.Wait(ctx)
+	pending = append(pending, c.scrapePool.Stop(ctx)...)
+	return append(pending, c.scrapeQueue.Stop(ctx)...)
+}
+
 func (c *AppContainer) Shutdown(ctx context.Context) error {
 	c.logger.Info("Starting graceful shutdown of container")
 
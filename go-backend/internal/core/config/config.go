@@ -2,20 +2,28 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	MongoDB  MongoDBConfig  `mapstructure:"mongodb"`
-	Neo4j    Neo4jConfig    `mapstructure:"neo4j"`
-	Weaviate WeaviateConfig `mapstructure:"weaviate"`
-	LLM      LLMConfig      `mapstructure:"llm"`
-	Scraper  ScraperConfig  `mapstructure:"scraper"`
-	Mailer   MailerConfig   `mapstructure:"mailer"`
-	Logging  LoggingConfig  `mapstructure:"logging"`
+	Server     ServerConfig     `mapstructure:"server"`
+	MongoDB    MongoDBConfig    `mapstructure:"mongodb"`
+	Neo4j      Neo4jConfig      `mapstructure:"neo4j"`
+	Weaviate   WeaviateConfig   `mapstructure:"weaviate"`
+	LLM        LLMConfig        `mapstructure:"llm"`
+	Scraper    ScraperConfig    `mapstructure:"scraper"`
+	Mailer     MailerConfig     `mapstructure:"mailer"`
+	Staging    StagingConfig    `mapstructure:"staging"`
+	Analytics  AnalyticsConfig  `mapstructure:"analytics"`
+	QueryBatch QueryBatchConfig `mapstructure:"query_batch"`
+	QueryCache QueryCacheConfig `mapstructure:"query_cache"`
+	QueryInput QueryInputConfig `mapstructure:"query_input"`
+	Logging    LoggingConfig    `mapstructure:"logging"`
 }
 
 type ServerConfig struct {
@@ -25,8 +33,28 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
-	MaxBodySize  int64         `mapstructure:"max_body_size"`
-	RateLimit    int           `mapstructure:"rate_limit"` // requests per minute
+	MaxBodySize  int64         `mapstructure:"max_body_size"` // request body limit in bytes
+	RateLimit    int           `mapstructure:"rate_limit"`    // requests per minute
+	// ShutdownTimeout bounds the whole graceful shutdown sequence
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// Responses of at least CompressionMinSize bytes are gzipped at CompressionLevel
+	CompressionMinSize int `mapstructure:"compression_min_size"`
+	CompressionLevel   int `mapstructure:"compression_level"`
+	// CORSAllowedOrigins may contain "*" to allow any origin
+	CORSAllowedOrigins   []string      `mapstructure:"cors_allowed_origins"`
+	CORSAllowCredentials bool          `mapstructure:"cors_allow_credentials"`
+	CORSMaxAge           time.Duration `mapstructure:"cors_max_age"` // how long browsers cache preflight responses
+	// SelfCheckRequired keeps /ready failing until the startup self-check
+	// passes, rechecking every SelfCheckRetryInterval
+	SelfCheckRequired      bool          `mapstructure:"self_check_required"`
+	SelfCheckTimeout       time.Duration `mapstructure:"self_check_timeout"` // per check
+	SelfCheckRetryInterval time.Duration `mapstructure:"self_check_retry_interval"`
+	// RouteTimeouts bounds each named route; see DefaultRouteTimeouts
+	RouteTimeouts map[string]time.Duration `mapstructure:"route_timeouts"`
+	// AdminToken authenticates /api/v1/admin requests, sent in the
+	// X-Admin-Token header. Empty leaves admin routes open in development
+	// and disables them elsewhere.
+	AdminToken string `mapstructure:"admin_token"`
 }
 
 type MongoDBConfig struct {
@@ -44,7 +72,23 @@ type Neo4jConfig struct {
 	URI      string `mapstructure:"uri"`
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
+	// Database names the database sessions use; empty uses the user's home
+	// database
 	Database string `mapstructure:"database"`
+	// MatchStrategy controls how concept names are resolved to graph nodes:
+	// exact, prefix, contains or fulltext
+	MatchStrategy string `mapstructure:"match_strategy"`
+	// MatchCandidates caps the concepts a broad term is compared against;
+	// the best-ranked ones are kept, so exact matches are never cut off
+	MatchCandidates int `mapstructure:"match_candidates"`
+	// MaxPoolSize caps open connections per host; 100 is the driver default
+	MaxPoolSize int `mapstructure:"max_pool_size"`
+	// ClosureRefreshInterval is how often the precomputed transitive
+	// prerequisite sets are rebuilt to pick up edges added outside the app
+	ClosureRefreshInterval time.Duration `mapstructure:"closure_refresh_interval"`
+	// ConceptIndexRefreshInterval is how often the in-memory autocomplete
+	// index is rebuilt to pick up concepts changed outside the app
+	ConceptIndexRefreshInterval time.Duration `mapstructure:"concept_index_refresh_interval"`
 }
 
 type WeaviateConfig struct {
@@ -53,6 +97,45 @@ type WeaviateConfig struct {
 	Headers   map[string]string `mapstructure:"headers"`
 	APIKey    string            `mapstructure:"api_key"`
 	ClassName string            `mapstructure:"class_name"`
+	// OperationTimeout bounds each search or batch attempt
+	OperationTimeout time.Duration `mapstructure:"operation_timeout"`
+	// MaxRetries is how many times a transient failure is retried
+	MaxRetries   int           `mapstructure:"max_retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	// SearchMode selects what query processing sends to nearText: "concepts"
+	// uses the identified concepts, "query" the raw question text
+	SearchMode string `mapstructure:"search_mode"`
+	// ConceptClassName is the class holding one vector per graph concept,
+	// built from its name and description
+	ConceptClassName string `mapstructure:"concept_class_name"`
+	// RetrievalSource is where explanation context comes from by default:
+	// textbook chunks, concept descriptions, or both
+	RetrievalSource string `mapstructure:"retrieval_source"`
+	// ConceptQueryChunks is how many chunks ground a concept explanation,
+	// retrieved near the concept's name, category, description and aliases
+	ConceptQueryChunks int `mapstructure:"concept_query_chunks"`
+}
+
+// Weaviate search modes
+const (
+	SearchModeConcepts = "concepts"
+	SearchModeQuery    = "query"
+)
+
+// Retrieval sources for explanation context
+const (
+	RetrievalSourceTextbook = "textbook"
+	RetrievalSourceConcepts = "concepts"
+	RetrievalSourceBoth     = "both"
+)
+
+// ValidRetrievalSource reports whether source names a retrieval source
+func ValidRetrievalSource(source string) bool {
+	switch source {
+	case RetrievalSourceTextbook, RetrievalSourceConcepts, RetrievalSourceBoth:
+		return true
+	}
+	return false
 }
 
 type LLMConfig struct {
@@ -63,6 +146,104 @@ type LLMConfig struct {
 	MaxTokens   int               `mapstructure:"max_tokens"`
 	Temperature float64           `mapstructure:"temperature"`
 	Headers     map[string]string `mapstructure:"headers"`
+	// MaxConcurrent caps in-flight LLM API calls across all requests
+	MaxConcurrent int `mapstructure:"max_concurrent"`
+	// FallbackTemplatesDir holds <category>.tmpl files that override the
+	// built-in explanations served when the LLM is unavailable
+	FallbackTemplatesDir string `mapstructure:"fallback_templates_dir"`
+	// PromptsDir holds prompt templates that override the built-in ones or
+	// add variants; PromptVariants picks the variant of each operation
+	PromptsDir     string            `mapstructure:"prompts_dir"`
+	PromptVariants map[string]string `mapstructure:"prompt_variants"`
+	// RoutingEnabled sends simple queries to FastProfile and complex ones to
+	// DeepProfile; when off every query uses DeepProfile
+	RoutingEnabled bool         `mapstructure:"routing_enabled"`
+	FastProfile    ModelProfile `mapstructure:"fast_profile"`
+	DeepProfile    ModelProfile `mapstructure:"deep_profile"`
+	// PathSuggestions asks the LLM for a prerequisite ordering when none of
+	// a query's concepts are in the graph. The suggestion is returned apart
+	// from the curated path and staged for review.
+	PathSuggestions bool `mapstructure:"path_suggestions"`
+	// VerifyArithmetic evaluates plain numeric steps in generated
+	// explanations and flags ones that do not add up
+	VerifyArithmetic bool `mapstructure:"verify_arithmetic"`
+	// LogPrompts logs the full prompt and raw response of every LLM call at
+	// debug level, with secrets and personal data redacted. For prompt
+	// debugging only; refused in production.
+	LogPrompts bool `mapstructure:"log_prompts"`
+	// QuotaMaxRetries is how often a quota (429) rejection is retried after
+	// waiting the delay Gemini asks for, or QuotaBackoff doubling when it
+	// gives none. Waits longer than QuotaMaxWait fail fast instead.
+	QuotaMaxRetries int           `mapstructure:"quota_max_retries"`
+	QuotaMaxWait    time.Duration `mapstructure:"quota_max_wait"`
+	QuotaBackoff    time.Duration `mapstructure:"quota_backoff"`
+	// Experiment splits explanations between prompt or model variants
+	Experiment ExperimentConfig `mapstructure:"experiment"`
+	// TokenBudget caps an explanation's prompt and answer together. The
+	// answer always keeps MaxTokens and gets whatever the prompt leaves
+	// beyond that; context chunks that do not fit are dropped, weakest
+	// first. Zero disables it.
+	TokenBudget int `mapstructure:"token_budget"`
+	// Override lets authenticated testers pick the explanation model of a
+	// single query to compare outputs
+	Override ModelOverrideConfig `mapstructure:"override"`
+}
+
+// ModelOverrideConfig gates per-request model overrides
+type ModelOverrideConfig struct {
+	// Token authenticates overriding requests, sent in the
+	// X-LLM-Override-Token header; empty disables overrides
+	Token string `mapstructure:"token"`
+	// Models a request may pick; empty allows the models configured elsewhere
+	Models []string `mapstructure:"models"`
+}
+
+// OverrideModels returns the models a request may override to: the
+// configured allowlist, or else LLM_MODEL and the profile and experiment
+// models, sorted
+func (c LLMConfig) OverrideModels() []string {
+	candidates := c.Override.Models
+	if len(candidates) == 0 {
+		candidates = []string{c.Model, c.FastProfile.Model, c.DeepProfile.Model}
+		for _, model := range c.Experiment.Models {
+			candidates = append(candidates, model)
+		}
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	models := make([]string, 0, len(candidates))
+	for _, model := range candidates {
+		if model = strings.TrimSpace(model); model != "" && !seen[model] {
+			seen[model] = true
+			models = append(models, model)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
+// ModelProfile tunes explanation generation for one class of query
+type ModelProfile struct {
+	// Model overrides LLM_MODEL when set
+	Model       string        `mapstructure:"model"`
+	Temperature float64       `mapstructure:"temperature"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+	// ContextChunks is how many textbook chunks ground the explanation
+	ContextChunks int `mapstructure:"context_chunks"`
+}
+
+// Model profiles queries are routed to
+const (
+	ProfileFast = "fast"
+	ProfileDeep = "deep"
+)
+
+// Profile returns the named model profile, defaulting to the deep one
+func (c LLMConfig) Profile(name string) ModelProfile {
+	if name == ProfileFast {
+		return c.FastProfile
+	}
+	return c.DeepProfile
 }
 
 type ScraperConfig struct {
@@ -70,6 +251,22 @@ type ScraperConfig struct {
 	RateLimit     int    `mapstructure:"rate_limit"` // seconds between requests
 	UserAgent     string `mapstructure:"user_agent"`
 	Timeout       int    `mapstructure:"timeout"` // seconds
+	// BackgroundWorkers caps background scrape jobs running at once across
+	// all queries; up to QueueSize more wait and further ones are dropped
+	BackgroundWorkers int `mapstructure:"background_workers"`
+	QueueSize         int `mapstructure:"queue_size"`
+	// PersistentQueue stores scrape requests in MongoDB instead of the
+	// in-memory pool, so they survive restarts and are shared by every
+	// instance; BackgroundWorkers then sets the workers per instance
+	PersistentQueue bool `mapstructure:"persistent_queue"`
+	// QueueLease is how long a worker holds a job before another may take it
+	// over; it also bounds each attempt
+	QueueLease        time.Duration `mapstructure:"queue_lease"`
+	QueuePollInterval time.Duration `mapstructure:"queue_poll_interval"`
+	QueueMaxAttempts  int           `mapstructure:"queue_max_attempts"`
+	// QueueRetryBackoff is the delay before the first retry, doubled on each
+	// further attempt
+	QueueRetryBackoff time.Duration `mapstructure:"queue_retry_backoff"`
 }
 
 type MailerConfig struct {
@@ -82,6 +279,89 @@ type MailerConfig struct {
 	Enabled   bool   `mapstructure:"enabled"`
 }
 
+type StagingConfig struct {
+	StaleAfter time.Duration `mapstructure:"stale_after"` // pending concepts older than this are stale
+	// PrereqMatchThreshold is the minimum similarity (0-1) for mapping a
+	// suggested prerequisite onto an existing concept
+	PrereqMatchThreshold float64 `mapstructure:"prereq_match_threshold"`
+	// DemandHalfLife is how long until an occurrence counts half as much
+	// toward a pending concept's review priority; zero disables decay
+	DemandHalfLife      time.Duration `mapstructure:"demand_half_life"`
+	DemandDecayInterval time.Duration `mapstructure:"demand_decay_interval"` // how often stored scores are aged
+
+	// Auto-approval of staged concepts that clear every threshold; off by
+	// default. Concepts that would duplicate an existing concept or close a
+	// prerequisite cycle are never auto-approved.
+	AutoApproveEnabled              bool    `mapstructure:"auto_approve_enabled"`
+	AutoApproveMinOccurrences       int     `mapstructure:"auto_approve_min_occurrences"`
+	AutoApproveMinConfidence        float64 `mapstructure:"auto_approve_min_confidence"` // LLM confidence, 0-1
+	AutoApproveRequirePrerequisites bool    `mapstructure:"auto_approve_require_prerequisites"`
+	// ConceptEmails mails the admin about each newly staged concept. The
+	// digest summarizes pending concepts daily or weekly instead, listing
+	// the DigestTopConcepts most requested; either can be used alone.
+	ConceptEmails     bool   `mapstructure:"concept_emails"`
+	DigestEnabled     bool   `mapstructure:"digest_enabled"`
+	DigestFrequency   string `mapstructure:"digest_frequency"` // daily or weekly
+	DigestTopConcepts int    `mapstructure:"digest_top_concepts"`
+}
+
+// Staged concept digest frequencies
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestInterval is how often the staged concept digest is sent, or zero
+// for an unknown frequency
+func (c StagingConfig) DigestInterval() time.Duration {
+	switch c.DigestFrequency {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// AnalyticsConfig controls how many full query records are written to MongoDB.
+// Queries that are not sampled only bump lightweight daily counters, so
+// aggregate stats stay accurate.
+type AnalyticsConfig struct {
+	SampleRate          float64 `mapstructure:"sample_rate"`           // fraction of queries stored in full (0-1)
+	AlwaysStoreFailures bool    `mapstructure:"always_store_failures"` // failed queries are stored regardless of sampling
+	// MaxStoredExplanation caps the characters of an explanation stored with
+	// its query; longer ones lose their middle. Zero stores them in full.
+	MaxStoredExplanation int `mapstructure:"max_stored_explanation"`
+}
+
+// minStoredExplanation keeps a trimmed explanation long enough to hold both
+// its introduction and its conclusion
+const minStoredExplanation = 500
+
+// QueryBatchConfig bounds POST /api/v1/query/batch
+type QueryBatchConfig struct {
+	MaxSize     int           `mapstructure:"max_size"`    // questions per batch
+	Concurrency int           `mapstructure:"concurrency"` // questions processed at once
+	Timeout     time.Duration `mapstructure:"timeout"`     // for the whole batch
+}
+
+// QueryCacheConfig lets POST /api/v1/query answer a repeated question from
+// the stored answer to the same normalized question
+type QueryCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"` // how old a cached answer may be
+	// IdentifyEnabled caches the concepts identified in each normalized
+	// question for IdentifyTTL, independently of the answer cache
+	IdentifyEnabled bool          `mapstructure:"identify_enabled"`
+	IdentifyTTL     time.Duration `mapstructure:"identify_ttl"`
+}
+
+// QueryInputConfig bounds the questions clients submit, after control
+// characters are stripped and whitespace is normalized
+type QueryInputConfig struct {
+	MaxQuestionLength int `mapstructure:"max_question_length"` // in characters
+}
+
 type LoggingConfig struct {
 	Level      string `mapstructure:"level"`
 	Format     string `mapstructure:"format"` // json or console
@@ -123,14 +403,32 @@ func LoadConfig() (*Config, error) {
 	}
 	config := &Config{
 		Server: ServerConfig{
-			Environment:  getEnvString("ENVIRONMENT", "development"),
-			Port:         getEnvInt("PORT", 8080),
-			Host:         getEnvString("HOST", "0.0.0.0"),
-			ReadTimeout:  getEnvDuration("READ_TIMEOUT", "30s"),
-			WriteTimeout: getEnvDuration("WRITE_TIMEOUT", "30s"),
-			IdleTimeout:  getEnvDuration("IDLE_TIMEOUT", "120s"),
-			MaxBodySize:  getEnvInt64("MAX_BODY_SIZE", 10*1024*1024), // 10MB
-			RateLimit:    getEnvInt("RATE_LIMIT", 100),               // 100 requests per minute
+			Environment:        getEnvString("ENVIRONMENT", "development"),
+			Port:               getEnvInt("PORT", 8080),
+			Host:               getEnvString("HOST", "0.0.0.0"),
+			ReadTimeout:        getEnvDuration("READ_TIMEOUT", "30s"),
+			WriteTimeout:       getEnvDuration("WRITE_TIMEOUT", "30s"),
+			IdleTimeout:        getEnvDuration("IDLE_TIMEOUT", "120s"),
+			MaxBodySize:        getEnvInt64("MAX_BODY_SIZE", 64*1024), // 64KB
+			RateLimit:          getEnvInt("RATE_LIMIT", 100),          // 100 requests per minute
+			ShutdownTimeout:    getEnvDuration("SHUTDOWN_TIMEOUT", "30s"),
+			CompressionMinSize: getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+			CompressionLevel:   getEnvInt("COMPRESSION_LEVEL", -1), // gzip.DefaultCompression
+			CORSAllowedOrigins: getEnvStringSlice("CORS_ALLOWED_ORIGINS", []string{
+				"http://localhost:3000",
+				"http://localhost:3001",
+				"https://mathprereq.com",
+				"https://app.mathprereq.com",
+			}),
+			CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", true),
+			CORSMaxAge:           getEnvDuration("CORS_MAX_AGE", "24h"),
+
+			SelfCheckRequired:      getEnvBool("STARTUP_SELF_CHECK_REQUIRED", false),
+			SelfCheckTimeout:       getEnvDuration("STARTUP_SELF_CHECK_TIMEOUT", "15s"),
+			SelfCheckRetryInterval: getEnvDuration("STARTUP_SELF_CHECK_RETRY_INTERVAL", "30s"),
+
+			RouteTimeouts: getEnvRouteTimeouts("ROUTE_TIMEOUTS"),
+			AdminToken:    getEnvString("ADMIN_TOKEN", ""),
 		},
 		MongoDB: MongoDBConfig{
 			URI:            buildMongoDBURI(),
@@ -139,14 +437,19 @@ func LoadConfig() (*Config, error) {
 			Password:       getEnvString("MONGODB_PASSWORD", "password123"),
 			AuthSource:     getEnvString("MONGODB_AUTH_SOURCE", "admin"),
 			ConnectTimeout: getEnvDuration("MONGODB_CONNECT_TIMEOUT", "10s"),
-			MaxPoolSize:    getEnvInt("MONGODB_MAX_POOL_SIZE", 100),
-			MinPoolSize:    getEnvInt("MONGODB_MIN_POOL_SIZE", 5),
+			MaxPoolSize:    getEnvInt("MONGODB_MAX_POOL_SIZE", 10),
+			MinPoolSize:    getEnvInt("MONGODB_MIN_POOL_SIZE", 2),
 		},
 		Neo4j: Neo4jConfig{
-			URI:      getEnvString("NEO4J_URI", "neo4j://localhost:7687"),
-			Username: getEnvString("NEO4J_USERNAME", "neo4j"),
-			Password: getEnvString("NEO4J_PASSWORD", "password123"),
-			Database: getEnvString("NEO4J_DATABASE", "neo4j"),
+			URI:                         getEnvString("NEO4J_URI", "neo4j://localhost:7687"),
+			Username:                    getEnvString("NEO4J_USERNAME", "neo4j"),
+			Password:                    getEnvString("NEO4J_PASSWORD", "password123"),
+			Database:                    getEnvString("NEO4J_DATABASE", ""),
+			MatchStrategy:               getEnvString("NEO4J_CONCEPT_MATCH_STRATEGY", "contains"),
+			MatchCandidates:             getEnvInt("NEO4J_CONCEPT_MATCH_CANDIDATES", 50),
+			MaxPoolSize:                 getEnvInt("NEO4J_MAX_POOL_SIZE", 100),
+			ClosureRefreshInterval:      getEnvDuration("NEO4J_CLOSURE_REFRESH_INTERVAL", "1h"),
+			ConceptIndexRefreshInterval: getEnvDuration("NEO4J_CONCEPT_INDEX_REFRESH_INTERVAL", "10m"),
 		},
 		Weaviate: WeaviateConfig{
 			Host:      weaviateHost,
@@ -154,21 +457,71 @@ func LoadConfig() (*Config, error) {
 			APIKey:    getEnvString("WEAVIATE_API_KEY", ""),
 			ClassName: getEnvString("WEAVIATE_CLASS_NAME", "MathChunk"),
 			Headers:   weaviateHeaders,
+
+			OperationTimeout: getEnvDuration("WEAVIATE_OPERATION_TIMEOUT", "10s"),
+			MaxRetries:       getEnvInt("WEAVIATE_MAX_RETRIES", 2),
+			RetryBackoff:     getEnvDuration("WEAVIATE_RETRY_BACKOFF", "200ms"),
+			SearchMode:       getEnvString("WEAVIATE_SEARCH_MODE", SearchModeConcepts),
+			ConceptClassName: getEnvString("WEAVIATE_CONCEPT_CLASS_NAME", "MathConcept"),
+			RetrievalSource:  getEnvString("WEAVIATE_RETRIEVAL_SOURCE", RetrievalSourceTextbook),
+
+			ConceptQueryChunks: getEnvInt("WEAVIATE_CONCEPT_QUERY_CHUNKS", 5),
 		},
 		LLM: LLMConfig{
-			Provider:    getEnvString("LLM_PROVIDER", "gemini"),
-			APIKey:      getEnvString("LLM_API_KEY", ""),
-			Model:       getEnvString("LLM_MODEL", ""),
-			BaseURL:     getEnvString("LLM_BASE_URL", ""),
-			MaxTokens:   getEnvInt("LLM_MAX_TOKENS", 2000),
-			Temperature: getEnvFloat64("LLM_TEMPERATURE", 0.7),
-			Headers:     make(map[string]string),
+			Provider:             getEnvString("LLM_PROVIDER", "gemini"),
+			APIKey:               getEnvString("LLM_API_KEY", ""),
+			Model:                getEnvString("LLM_MODEL", ""),
+			BaseURL:              getEnvString("LLM_BASE_URL", ""),
+			MaxTokens:            getEnvInt("LLM_MAX_TOKENS", 2000),
+			Temperature:          getEnvFloat64("LLM_TEMPERATURE", 0.7),
+			Headers:              make(map[string]string),
+			MaxConcurrent:        getEnvInt("LLM_MAX_CONCURRENT", 4),
+			FallbackTemplatesDir: getEnvString("LLM_FALLBACK_TEMPLATES_DIR", ""),
+			PromptsDir:           getEnvString("LLM_PROMPTS_DIR", ""),
+			PromptVariants:       getEnvStringMap("LLM_PROMPT_VARIANTS"),
+			RoutingEnabled:       getEnvBool("LLM_ROUTING_ENABLED", true),
+			PathSuggestions:      getEnvBool("LLM_PATH_SUGGESTIONS", true),
+			VerifyArithmetic:     getEnvBool("LLM_VERIFY_ARITHMETIC", false),
+			LogPrompts:           getEnvBool("LLM_LOG_PROMPTS", false),
+			QuotaMaxRetries:      getEnvInt("LLM_QUOTA_MAX_RETRIES", 1),
+			QuotaMaxWait:         getEnvDuration("LLM_QUOTA_MAX_WAIT", "20s"),
+			QuotaBackoff:         getEnvDuration("LLM_QUOTA_BACKOFF", "5s"),
+			TokenBudget:          getEnvInt("LLM_TOKEN_BUDGET", 16000),
+			FastProfile: ModelProfile{
+				Model:         getEnvString("LLM_FAST_MODEL", "gemini-2.5-flash-lite"),
+				Temperature:   getEnvFloat64("LLM_FAST_TEMPERATURE", 0.3),
+				Timeout:       getEnvDuration("LLM_FAST_TIMEOUT", "60s"),
+				ContextChunks: getEnvInt("LLM_FAST_CONTEXT_CHUNKS", 3),
+			},
+			DeepProfile: ModelProfile{
+				Model:         getEnvString("LLM_DEEP_MODEL", ""),
+				Temperature:   getEnvFloat64("LLM_DEEP_TEMPERATURE", 0.3),
+				Timeout:       getEnvDuration("LLM_DEEP_TIMEOUT", "180s"),
+				ContextChunks: getEnvInt("LLM_DEEP_CONTEXT_CHUNKS", 5),
+			},
+			Experiment: ExperimentConfig{
+				Name:    getEnvString("LLM_EXPERIMENT_NAME", ""),
+				Split:   getEnvExperimentSplit("LLM_EXPERIMENT_SPLIT"),
+				Prompts: getEnvStringMap("LLM_EXPERIMENT_PROMPTS"),
+				Models:  getEnvStringMap("LLM_EXPERIMENT_MODELS"),
+			},
+			Override: ModelOverrideConfig{
+				Token:  getEnvString("LLM_OVERRIDE_TOKEN", ""),
+				Models: getEnvStringSlice("LLM_OVERRIDE_MODELS", nil),
+			},
 		},
 		Scraper: ScraperConfig{
-			MaxConcurrent: getEnvInt("SCRAPER_MAX_CONCURRENT", 5),
-			RateLimit:     getEnvInt("SCRAPER_RATE_LIMIT", 2),
-			UserAgent:     getEnvString("SCRAPER_USER_AGENT", "MathPrereq-Bot/1.0"),
-			Timeout:       getEnvInt("SCRAPER_TIMEOUT", 30),
+			MaxConcurrent:     getEnvInt("SCRAPER_MAX_CONCURRENT", 5),
+			RateLimit:         getEnvInt("SCRAPER_RATE_LIMIT", 2),
+			UserAgent:         getEnvString("SCRAPER_USER_AGENT", "MathPrereq-Bot/1.0"),
+			Timeout:           getEnvInt("SCRAPER_TIMEOUT", 30),
+			BackgroundWorkers: getEnvInt("SCRAPER_BACKGROUND_WORKERS", 2),
+			QueueSize:         getEnvInt("SCRAPER_QUEUE_SIZE", 50),
+			PersistentQueue:   getEnvBool("SCRAPER_PERSISTENT_QUEUE", false),
+			QueueLease:        getEnvDuration("SCRAPER_QUEUE_LEASE", "3m"),
+			QueuePollInterval: getEnvDuration("SCRAPER_QUEUE_POLL_INTERVAL", "5s"),
+			QueueMaxAttempts:  getEnvInt("SCRAPER_QUEUE_MAX_ATTEMPTS", 3),
+			QueueRetryBackoff: getEnvDuration("SCRAPER_QUEUE_RETRY_BACKOFF", "1m"),
 		},
 		Mailer: MailerConfig{
 			Host:      getEnvString("MAILER_HOST", "smtp.gmail.com"),
@@ -179,6 +532,40 @@ func LoadConfig() (*Config, error) {
 			AdminMail: getEnvString("MAILER_ADMIN_MAIL", "admin@mathprereq.com"),
 			Enabled:   getEnvBool("MAILER_ENABLED", false),
 		},
+		Staging: StagingConfig{
+			StaleAfter:                      getEnvDuration("STAGED_CONCEPT_STALE_AFTER", "336h"), // 14 days
+			PrereqMatchThreshold:            getEnvFloat64("STAGED_PREREQ_MATCH_THRESHOLD", 0.8),
+			DemandHalfLife:                  getEnvDuration("STAGED_DEMAND_HALF_LIFE", "0s"),
+			DemandDecayInterval:             getEnvDuration("STAGED_DEMAND_DECAY_INTERVAL", "1h"),
+			AutoApproveEnabled:              getEnvBool("STAGED_AUTO_APPROVE_ENABLED", false),
+			AutoApproveMinOccurrences:       getEnvInt("STAGED_AUTO_APPROVE_MIN_OCCURRENCES", 10),
+			AutoApproveMinConfidence:        getEnvFloat64("STAGED_AUTO_APPROVE_MIN_CONFIDENCE", 0.9),
+			AutoApproveRequirePrerequisites: getEnvBool("STAGED_AUTO_APPROVE_REQUIRE_PREREQUISITES", true),
+			ConceptEmails:                   getEnvBool("STAGED_CONCEPT_EMAILS", true),
+			DigestEnabled:                   getEnvBool("STAGED_DIGEST_ENABLED", false),
+			DigestFrequency:                 getEnvString("STAGED_DIGEST_FREQUENCY", DigestDaily),
+			DigestTopConcepts:               getEnvInt("STAGED_DIGEST_TOP_CONCEPTS", 10),
+		},
+		Analytics: AnalyticsConfig{
+			SampleRate:           getEnvFloat64("ANALYTICS_SAMPLE_RATE", 1.0),
+			AlwaysStoreFailures:  getEnvBool("ANALYTICS_ALWAYS_STORE_FAILURES", true),
+			MaxStoredExplanation: getEnvInt("ANALYTICS_MAX_STORED_EXPLANATION", 0),
+		},
+		QueryBatch: QueryBatchConfig{
+			MaxSize:     getEnvInt("QUERY_BATCH_MAX_SIZE", 20),
+			Concurrency: getEnvInt("QUERY_BATCH_CONCURRENCY", 4),
+			Timeout:     getEnvDuration("QUERY_BATCH_TIMEOUT", "45s"),
+		},
+		QueryCache: QueryCacheConfig{
+			Enabled: getEnvBool("QUERY_CACHE_ENABLED", false),
+			TTL:     getEnvDuration("QUERY_CACHE_TTL", "24h"),
+
+			IdentifyEnabled: getEnvBool("QUERY_IDENTIFY_CACHE_ENABLED", false),
+			IdentifyTTL:     getEnvDuration("QUERY_IDENTIFY_CACHE_TTL", "168h"),
+		},
+		QueryInput: QueryInputConfig{
+			MaxQuestionLength: getEnvInt("QUERY_MAX_QUESTION_LENGTH", 1000),
+		},
 		Logging: LoggingConfig{
 			Level:      getEnvString("LOG_LEVEL", "info"),
 			Format:     getEnvString("LOG_FORMAT", "json"),
@@ -203,9 +590,211 @@ func validateConfig(cfg *Config) error {
 	if cfg.Weaviate.Host == "" {
 		return fmt.Errorf("WEAVIATE_HOST is required")
 	}
+	switch cfg.Neo4j.MatchStrategy {
+	case "exact", "prefix", "contains", "fulltext":
+	default:
+		return fmt.Errorf("NEO4J_CONCEPT_MATCH_STRATEGY must be exact, prefix, contains or fulltext, got %q", cfg.Neo4j.MatchStrategy)
+	}
+	if cfg.Neo4j.MatchCandidates <= 0 {
+		return fmt.Errorf("NEO4J_CONCEPT_MATCH_CANDIDATES must be positive, got %d", cfg.Neo4j.MatchCandidates)
+	}
+	if cfg.Neo4j.MaxPoolSize <= 0 {
+		return fmt.Errorf("NEO4J_MAX_POOL_SIZE must be positive, got %d", cfg.Neo4j.MaxPoolSize)
+	}
+	if cfg.Neo4j.ClosureRefreshInterval <= 0 {
+		return fmt.Errorf("NEO4J_CLOSURE_REFRESH_INTERVAL must be positive")
+	}
+	if cfg.Neo4j.ConceptIndexRefreshInterval <= 0 {
+		return fmt.Errorf("NEO4J_CONCEPT_INDEX_REFRESH_INTERVAL must be positive")
+	}
+	if cfg.MongoDB.MaxPoolSize <= 0 {
+		return fmt.Errorf("MONGODB_MAX_POOL_SIZE must be positive, got %d", cfg.MongoDB.MaxPoolSize)
+	}
+	if cfg.MongoDB.MinPoolSize < 0 || cfg.MongoDB.MinPoolSize > cfg.MongoDB.MaxPoolSize {
+		return fmt.Errorf("MONGODB_MIN_POOL_SIZE must be between 0 and MONGODB_MAX_POOL_SIZE (%d), got %d",
+			cfg.MongoDB.MaxPoolSize, cfg.MongoDB.MinPoolSize)
+	}
 	if cfg.Server.Port <= 0 || cfg.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", cfg.Server.Port)
 	}
+	if cfg.Server.ShutdownTimeout <= 0 {
+		return fmt.Errorf("SHUTDOWN_TIMEOUT must be positive")
+	}
+	if cfg.Server.SelfCheckTimeout <= 0 {
+		return fmt.Errorf("STARTUP_SELF_CHECK_TIMEOUT must be positive")
+	}
+	if cfg.Server.SelfCheckRequired && cfg.Server.SelfCheckRetryInterval <= 0 {
+		return fmt.Errorf("STARTUP_SELF_CHECK_RETRY_INTERVAL must be positive when STARTUP_SELF_CHECK_REQUIRED is set")
+	}
+	if err := validateRouteTimeouts(cfg.Server.RouteTimeouts); err != nil {
+		return err
+	}
+	if cfg.Server.MaxBodySize <= 0 {
+		return fmt.Errorf("MAX_BODY_SIZE must be positive, got %d", cfg.Server.MaxBodySize)
+	}
+	if cfg.Server.CompressionLevel < -2 || cfg.Server.CompressionLevel > 9 {
+		return fmt.Errorf("COMPRESSION_LEVEL must be between -2 and 9, got %d", cfg.Server.CompressionLevel)
+	}
+	if err := validateCORSOrigins(cfg.Server.CORSAllowedOrigins); err != nil {
+		return err
+	}
+	// Browsers cap preflight caching at 24h (Firefox) or less
+	if cfg.Server.CORSMaxAge < 0 || cfg.Server.CORSMaxAge > 24*time.Hour {
+		return fmt.Errorf("CORS_MAX_AGE must be between 0 and 24h, got %s", cfg.Server.CORSMaxAge)
+	}
+	if cfg.Server.CORSMaxAge%time.Second != 0 {
+		return fmt.Errorf("CORS_MAX_AGE must be whole seconds, got %s", cfg.Server.CORSMaxAge)
+	}
+	if cfg.Weaviate.MaxRetries < 0 {
+		return fmt.Errorf("WEAVIATE_MAX_RETRIES must not be negative, got %d", cfg.Weaviate.MaxRetries)
+	}
+	if cfg.Weaviate.OperationTimeout <= 0 {
+		return fmt.Errorf("WEAVIATE_OPERATION_TIMEOUT must be positive")
+	}
+	switch cfg.Weaviate.SearchMode {
+	case SearchModeConcepts, SearchModeQuery:
+	default:
+		return fmt.Errorf("WEAVIATE_SEARCH_MODE must be concepts or query, got %q", cfg.Weaviate.SearchMode)
+	}
+	if !ValidRetrievalSource(cfg.Weaviate.RetrievalSource) {
+		return fmt.Errorf("WEAVIATE_RETRIEVAL_SOURCE must be textbook, concepts or both, got %q", cfg.Weaviate.RetrievalSource)
+	}
+	if cfg.Weaviate.ConceptQueryChunks <= 0 {
+		return fmt.Errorf("WEAVIATE_CONCEPT_QUERY_CHUNKS must be positive, got %d", cfg.Weaviate.ConceptQueryChunks)
+	}
+	if cfg.Weaviate.ConceptClassName == cfg.Weaviate.ClassName {
+		return fmt.Errorf("WEAVIATE_CONCEPT_CLASS_NAME must differ from WEAVIATE_CLASS_NAME")
+	}
+	if cfg.LLM.MaxConcurrent <= 0 {
+		return fmt.Errorf("LLM_MAX_CONCURRENT must be positive, got %d", cfg.LLM.MaxConcurrent)
+	}
+	if cfg.LLM.QuotaMaxRetries < 0 {
+		return fmt.Errorf("LLM_QUOTA_MAX_RETRIES must not be negative, got %d", cfg.LLM.QuotaMaxRetries)
+	}
+	if cfg.LLM.QuotaMaxWait <= 0 {
+		return fmt.Errorf("LLM_QUOTA_MAX_WAIT must be positive")
+	}
+	if cfg.LLM.QuotaBackoff <= 0 {
+		return fmt.Errorf("LLM_QUOTA_BACKOFF must be positive")
+	}
+	if cfg.LLM.TokenBudget < 0 {
+		return fmt.Errorf("LLM_TOKEN_BUDGET must not be negative, got %d", cfg.LLM.TokenBudget)
+	}
+	if cfg.LLM.TokenBudget > 0 && cfg.LLM.TokenBudget <= cfg.LLM.MaxTokens {
+		return fmt.Errorf("LLM_TOKEN_BUDGET (%d) must exceed LLM_MAX_TOKENS (%d)", cfg.LLM.TokenBudget, cfg.LLM.MaxTokens)
+	}
+	for _, p := range []struct {
+		prefix  string
+		profile ModelProfile
+	}{{"LLM_FAST", cfg.LLM.FastProfile}, {"LLM_DEEP", cfg.LLM.DeepProfile}} {
+		prefix, profile := p.prefix, p.profile
+		if profile.Timeout <= 0 {
+			return fmt.Errorf("%s_TIMEOUT must be positive", prefix)
+		}
+		if profile.ContextChunks <= 0 {
+			return fmt.Errorf("%s_CONTEXT_CHUNKS must be positive, got %d", prefix, profile.ContextChunks)
+		}
+		if profile.Temperature < 0 || profile.Temperature > 2 {
+			return fmt.Errorf("%s_TEMPERATURE must be between 0 and 2, got %v", prefix, profile.Temperature)
+		}
+	}
+	if cfg.LLM.LogPrompts && cfg.Server.Environment == "production" {
+		return fmt.Errorf("LLM_LOG_PROMPTS must not be enabled when ENVIRONMENT=production")
+	}
+	if err := validateExperiment(cfg.LLM.Experiment); err != nil {
+		return err
+	}
+	if cfg.Scraper.BackgroundWorkers <= 0 {
+		return fmt.Errorf("SCRAPER_BACKGROUND_WORKERS must be positive, got %d", cfg.Scraper.BackgroundWorkers)
+	}
+	if cfg.Scraper.QueueSize < 0 {
+		return fmt.Errorf("SCRAPER_QUEUE_SIZE must not be negative, got %d", cfg.Scraper.QueueSize)
+	}
+	if cfg.Scraper.PersistentQueue {
+		if cfg.Scraper.QueueLease <= 0 {
+			return fmt.Errorf("SCRAPER_QUEUE_LEASE must be positive")
+		}
+		if cfg.Scraper.QueuePollInterval <= 0 {
+			return fmt.Errorf("SCRAPER_QUEUE_POLL_INTERVAL must be positive")
+		}
+		if cfg.Scraper.QueueMaxAttempts <= 0 {
+			return fmt.Errorf("SCRAPER_QUEUE_MAX_ATTEMPTS must be positive, got %d", cfg.Scraper.QueueMaxAttempts)
+		}
+		if cfg.Scraper.QueueRetryBackoff < 0 {
+			return fmt.Errorf("SCRAPER_QUEUE_RETRY_BACKOFF must not be negative")
+		}
+	}
+	if cfg.Staging.StaleAfter <= 0 {
+		return fmt.Errorf("STAGED_CONCEPT_STALE_AFTER must be positive")
+	}
+	if cfg.Staging.PrereqMatchThreshold < 0 || cfg.Staging.PrereqMatchThreshold > 1 {
+		return fmt.Errorf("STAGED_PREREQ_MATCH_THRESHOLD must be between 0 and 1, got %v", cfg.Staging.PrereqMatchThreshold)
+	}
+	if cfg.Staging.DemandHalfLife < 0 {
+		return fmt.Errorf("STAGED_DEMAND_HALF_LIFE must not be negative")
+	}
+	if cfg.Staging.DemandHalfLife > 0 && cfg.Staging.DemandDecayInterval <= 0 {
+		return fmt.Errorf("STAGED_DEMAND_DECAY_INTERVAL must be positive when decay is enabled")
+	}
+	if cfg.Staging.AutoApproveEnabled {
+		if cfg.Staging.AutoApproveMinOccurrences < 1 {
+			return fmt.Errorf("STAGED_AUTO_APPROVE_MIN_OCCURRENCES must be at least 1, got %d", cfg.Staging.AutoApproveMinOccurrences)
+		}
+		if cfg.Staging.AutoApproveMinConfidence < 0 || cfg.Staging.AutoApproveMinConfidence > 1 {
+			return fmt.Errorf("STAGED_AUTO_APPROVE_MIN_CONFIDENCE must be between 0 and 1, got %v", cfg.Staging.AutoApproveMinConfidence)
+		}
+	}
+	if cfg.Staging.DigestEnabled {
+		if cfg.Staging.DigestInterval() == 0 {
+			return fmt.Errorf("STAGED_DIGEST_FREQUENCY must be daily or weekly, got %q", cfg.Staging.DigestFrequency)
+		}
+		if cfg.Staging.DigestTopConcepts <= 0 {
+			return fmt.Errorf("STAGED_DIGEST_TOP_CONCEPTS must be positive, got %d", cfg.Staging.DigestTopConcepts)
+		}
+	}
+	if cfg.Analytics.SampleRate < 0 || cfg.Analytics.SampleRate > 1 {
+		return fmt.Errorf("ANALYTICS_SAMPLE_RATE must be between 0 and 1, got %v", cfg.Analytics.SampleRate)
+	}
+	if maxLen := cfg.Analytics.MaxStoredExplanation; maxLen != 0 && maxLen < minStoredExplanation {
+		return fmt.Errorf("ANALYTICS_MAX_STORED_EXPLANATION must be 0 or at least %d, got %d", minStoredExplanation, maxLen)
+	}
+	if cfg.QueryBatch.MaxSize <= 0 {
+		return fmt.Errorf("QUERY_BATCH_MAX_SIZE must be positive, got %d", cfg.QueryBatch.MaxSize)
+	}
+	if cfg.QueryBatch.Concurrency <= 0 {
+		return fmt.Errorf("QUERY_BATCH_CONCURRENCY must be positive, got %d", cfg.QueryBatch.Concurrency)
+	}
+	if cfg.QueryBatch.Timeout <= 0 {
+		return fmt.Errorf("QUERY_BATCH_TIMEOUT must be positive")
+	}
+	if cfg.QueryCache.Enabled && cfg.QueryCache.TTL <= 0 {
+		return fmt.Errorf("QUERY_CACHE_TTL must be positive when QUERY_CACHE_ENABLED is set")
+	}
+	if cfg.QueryCache.IdentifyEnabled && cfg.QueryCache.IdentifyTTL <= 0 {
+		return fmt.Errorf("QUERY_IDENTIFY_CACHE_TTL must be positive when QUERY_IDENTIFY_CACHE_ENABLED is set")
+	}
+	if cfg.QueryInput.MaxQuestionLength < 3 {
+		return fmt.Errorf("QUERY_MAX_QUESTION_LENGTH must be at least 3, got %d", cfg.QueryInput.MaxQuestionLength)
+	}
+	return nil
+}
+
+// validateCORSOrigins accepts "*" or scheme://host[:port] origins, which is
+// the exact form browsers send in the Origin header
+func validateCORSOrigins(origins []string) error {
+	if len(origins) == 0 {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS must list at least one origin")
+	}
+	for _, origin := range origins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+			return fmt.Errorf("CORS_ALLOWED_ORIGINS entry %q must be \"*\" or scheme://host[:port] without a trailing slash", origin)
+		}
+	}
 	return nil
 }
 
@@ -217,6 +806,33 @@ func getEnvString(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvStringSlice splits a comma-separated variable, dropping blank entries
+func getEnvStringSlice(key string, defaultValue []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
+// getEnvStringMap parses comma-separated key=value pairs, dropping entries
+// without a key
+func getEnvStringMap(key string) map[string]string {
+	values := make(map[string]string)
+	for _, entry := range getEnvStringSlice(key, nil) {
+		name, value, _ := strings.Cut(entry, "=")
+		if name = strings.TrimSpace(name); name != "" {
+			values[name] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if parsed, err := strconv.Atoi(value); err == nil {
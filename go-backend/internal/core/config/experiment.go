@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ExperimentConfig splits explanation requests between variants that use a
+// different prompt template or model. An empty Name disables the experiment.
+type ExperimentConfig struct {
+	Name string `mapstructure:"name"`
+	// Split is the relative weight of each variant; a weight of 0 pauses it
+	Split map[string]int `mapstructure:"split"`
+	// Prompts maps a variant to the explanation prompt variant it uses;
+	// variants left out use the active one
+	Prompts map[string]string `mapstructure:"prompts"`
+	// Models maps a variant to the model it explains with; variants left
+	// out use the model their query is routed to
+	Models map[string]string `mapstructure:"models"`
+}
+
+// Enabled reports whether queries are being split between variants
+func (c ExperimentConfig) Enabled() bool {
+	return c.Name != ""
+}
+
+// Variants returns the variant names in a stable order
+func (c ExperimentConfig) Variants() []string {
+	names := make([]string, 0, len(c.Split))
+	for name := range c.Split {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getEnvExperimentSplit parses comma-separated variant=weight pairs.
+// Weights that do not parse are kept as -1 so validation reports them.
+func getEnvExperimentSplit(key string) map[string]int {
+	split := make(map[string]int)
+	for name, value := range getEnvStringMap(key) {
+		weight, err := strconv.Atoi(value)
+		if err != nil {
+			weight = -1
+		}
+		split[name] = weight
+	}
+	return split
+}
+
+// validateExperiment requires an enabled experiment to have a non-negative
+// weight per variant, at least one variant in use, and prompts and models
+// only for variants of the split
+func validateExperiment(cfg ExperimentConfig) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	total := 0
+	for _, name := range cfg.Variants() {
+		if cfg.Split[name] < 0 {
+			return fmt.Errorf("LLM_EXPERIMENT_SPLIT %s must be a non-negative integer weight", name)
+		}
+		total += cfg.Split[name]
+	}
+	if total == 0 {
+		return fmt.Errorf("LLM_EXPERIMENT_SPLIT must give at least one variant a positive weight")
+	}
+
+	for key, variants := range map[string]map[string]string{
+		"LLM_EXPERIMENT_PROMPTS": cfg.Prompts,
+		"LLM_EXPERIMENT_MODELS":  cfg.Models,
+	} {
+		for name := range variants {
+			if _, ok := cfg.Split[name]; !ok {
+				return fmt.Errorf("%s names variant %q, which is not in LLM_EXPERIMENT_SPLIT", key, name)
+			}
+		}
+	}
+	return nil
+}
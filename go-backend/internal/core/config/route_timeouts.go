@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultRouteTimeouts are the request timeouts of each named route.
+// "global" bounds every request except long-running admin jobs, concept
+// profiles, concept queries and the batch query route, so other routes can only time out later than it if
+// "global" is raised too. The batch query route is not listed; its timeout
+// follows QUERY_BATCH_TIMEOUT.
+func DefaultRouteTimeouts() map[string]time.Duration {
+	return map[string]time.Duration{
+		"global": 50 * time.Second,
+
+		"query":                45 * time.Second,
+		"explain_mistake":      45 * time.Second,
+		"query_visual":         15 * time.Second,
+		"query_graph":          15 * time.Second,
+		"query_context":        10 * time.Second,
+		"concept_query":        3 * time.Minute,
+		"concept_detail":       15 * time.Second,
+		"concepts":             30 * time.Second,
+		"concept_autocomplete": 5 * time.Second,
+		"formula_search":       10 * time.Second,
+		"concept_neighborhood": 30 * time.Second,
+		"concept_profile":      2 * time.Minute,
+		"concept_readiness":    15 * time.Second,
+		"concept_study_time":   30 * time.Second,
+		"concept_paths":        30 * time.Second,
+		"concept_roadmap":      30 * time.Second,
+		"path_snapshot":        30 * time.Second,
+		"path_diff":            30 * time.Second,
+		"user_preferences":     10 * time.Second,
+
+		"resources_find":       60 * time.Second,
+		"resources_find_batch": 120 * time.Second,
+		"resources_by_concept": 15 * time.Second,
+		"resources_list":       30 * time.Second,
+		"resources_stats":      15 * time.Second,
+
+		"stats_concept_pairs":    30 * time.Second,
+		"stats_popular_concepts": 30 * time.Second,
+		"stats_performance":      30 * time.Second,
+		"stats_system":           15 * time.Second,
+
+		"admin_staged_pending":       30 * time.Second,
+		"admin_staged_stats":         15 * time.Second,
+		"admin_staged_stale":         15 * time.Second,
+		"admin_staged_concept":       15 * time.Second,
+		"admin_approval_preview":     30 * time.Second,
+		"admin_review":               30 * time.Second,
+		"admin_unknown_concepts":     30 * time.Second,
+		"admin_coverage":             2 * time.Minute,
+		"admin_difficulty":           30 * time.Second,
+		"admin_aliases":              15 * time.Second,
+		"admin_alias_suggestions":    30 * time.Second,
+		"admin_add_alias":            30 * time.Second,
+		"admin_merge_concepts":       60 * time.Second,
+		"admin_validate_edge":        30 * time.Second,
+		"admin_queries":              10 * time.Second,
+		"admin_pin_resource":         10 * time.Second,
+		"admin_enrich_videos":        5 * time.Minute,
+		"admin_experiment":           5 * time.Second,
+		"admin_experiment_results":   30 * time.Second,
+		"admin_descriptions":         5 * time.Minute,
+		"admin_explanation_versions": 15 * time.Second,
+		"admin_activate_explanation": 15 * time.Second,
+		"admin_closures_refresh":     2 * time.Minute,
+		"admin_prerequisite_weight":  15 * time.Second,
+	}
+}
+
+// getEnvRouteTimeouts overlays the comma-separated name=duration pairs of
+// key on the defaults. Entries that do not parse are kept as zero so
+// validation reports them instead of silently using the default.
+func getEnvRouteTimeouts(key string) map[string]time.Duration {
+	timeouts := DefaultRouteTimeouts()
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(entry, "=")
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			duration = 0
+		}
+		timeouts[strings.TrimSpace(name)] = duration
+	}
+	return timeouts
+}
+
+// validateRouteTimeouts rejects unknown route names, which are most likely
+// typos, and timeouts that are not positive
+func validateRouteTimeouts(timeouts map[string]time.Duration) error {
+	known := DefaultRouteTimeouts()
+	names := make([]string, 0, len(timeouts))
+	for name := range timeouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := known[name]; !ok {
+			return fmt.Errorf("ROUTE_TIMEOUTS names unknown route %q", name)
+		}
+		if timeouts[name] <= 0 {
+			return fmt.Errorf("ROUTE_TIMEOUTS %s must be a positive duration", name)
+		}
+	}
+	return nil
+}
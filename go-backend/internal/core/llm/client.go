@@ -4,14 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/core/llm/prompts"
 	"github.com/mathprereq/internal/types"
 	"github.com/mathprereq/pkg/logger"
 	"go.uber.org/zap"
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/genai"
 )
 
@@ -22,20 +25,42 @@ type Client struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 	logger      *zap.Logger
+	// limiter bounds concurrent Gemini calls across all requests
+	limiter *semaphore.Weighted
+	// quota decides how long to wait out rate-limit rejections
+	quota quotaPolicy
+	// prompts renders the prompt of each operation
+	prompts *prompts.Registry
+	// promptLog logs every call's prompts when LLM_LOG_PROMPTS is on
+	promptLog *promptLogger
 }
 
 // Default configuration constants
 const (
-	DefaultModel      = "gemini-2.5-flash"
-	DefaultMaxTokens  = 2000
-	DefaultTimeout    = 180 * time.Second
-	HealthCheckPrompt = "Respond with 'OK' to confirm you are working."
+	DefaultModel     = "gemini-2.5-flash"
+	DefaultMaxTokens = 2000
+	DefaultTimeout   = 180 * time.Second
+	// DefaultMaxConcurrent is used when LLMConfig.MaxConcurrent is unset
+	DefaultMaxConcurrent = 4
+	HealthCheckPrompt    = "Respond with 'OK' to confirm you are working."
 )
 
 type ExplanationRequest struct {
 	Query            string          `json:"query"`
 	PrerequisitePath []types.Concept `json:"prerequisite_path"`
 	ContextChunks    []string        `json:"context_chunks"`
+	// Language is the name of the language to answer in; empty means English
+	Language string `json:"language,omitempty"`
+	// Profile names the model profile to answer with; empty means deep
+	Profile string `json:"profile,omitempty"`
+	// PromptVariant and Model override the active explanation prompt and
+	// the profile's model, for experiments
+	PromptVariant string `json:"prompt_variant,omitempty"`
+	Model         string `json:"model,omitempty"`
+	// Level and Style are the asking user's explanation preferences;
+	// empty keeps the default explanation
+	Level string `json:"level,omitempty"`
+	Style string `json:"style,omitempty"`
 }
 
 // NewConceptAnalysis represents the analysis of a potentially new concept
@@ -47,6 +72,8 @@ type NewConceptAnalysis struct {
 	SuggestedCategory   string   `json:"suggested_category"`
 	Reasoning           string   `json:"reasoning"`
 	IsLikelyNewConcept  bool     `json:"is_likely_new_concept"`
+	// Confidence (0-1) is how sure the model is the concept belongs in the graph
+	Confidence float64 `json:"confidence"`
 }
 
 func NewClient(cfg config.LLMConfig) (*Client, error) {
@@ -76,50 +103,59 @@ func NewClient(cfg config.LLMConfig) (*Client, error) {
 	// Initialize Gemini client with proper configuration
 	genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
 		APIKey: apiKey,
+		// Capture Retry-After on 429s, which genai errors do not carry
+		HTTPClient: &http.Client{Transport: &retryAfterTransport{base: http.DefaultTransport}},
 	})
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("failed to initialize Gemini client: %w", err)
 	}
 
+	promptRegistry, err := prompts.NewRegistry(cfg.PromptsDir, cfg.PromptVariants)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load prompt templates: %w", err)
+	}
+
+	for variant, name := range cfg.Experiment.Prompts {
+		if !promptRegistry.HasVariant(prompts.Explanation, name) {
+			cancel()
+			return nil, fmt.Errorf("experiment variant %q uses unknown explanation prompt %q", variant, name)
+		}
+	}
+
+	maxConcurrent := cfg.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrent
+	}
+
 	client := &Client{
 		genaiClient: genaiClient,
 		config:      cfg,
 		ctx:         ctx,
 		cancel:      cancel,
 		logger:      logger,
+		limiter:     semaphore.NewWeighted(int64(maxConcurrent)),
+		quota:       newQuotaPolicy(cfg),
+		prompts:     promptRegistry,
+		promptLog:   newPromptLogger(cfg.LogPrompts, logger, apiKey),
 	}
 
 	logger.Info("Gemini LLM client initialized successfully",
 		zap.String("model", cfg.Model),
-		zap.String("provider", "gemini"))
+		zap.String("provider", "gemini"),
+		zap.Int("max_concurrent", maxConcurrent))
 
 	return client, nil
 }
 
 func (c *Client) IdentifyConcepts(ctx context.Context, query string) ([]string, error) {
-	systemPrompt := `You are an expert in mathematics education. Your task is to identify the key mathematical concepts mentioned in a student's query.
-
-Rules:
-1. Extract only the core mathematical concepts (not general words)
-2. Return concepts that would appear in a calculus curriculum
-3. Format as a comma-separated list
-4. Be precise and use standard mathematical terminology
-5. Focus on concepts that would have prerequisite relationships
-
-Examples:
-Query: "I don't understand how to find the derivative of x^2"
-Response: derivatives, power rule
-
-Query: "What is integration by parts and when do I use it?"
-Response: integration, integration by parts
-
-Query: "I'm confused about limits and continuity"
-Response: limits, continuity`
-
-	userPrompt := fmt.Sprintf("Student query: '%s'\n\nIdentified concepts:", query)
+	prompt, err := c.prompts.Render(prompts.IdentifyConcepts, map[string]string{"Query": query})
+	if err != nil {
+		return nil, err
+	}
 
-	response, err := c.callGemini(ctx, systemPrompt, userPrompt, 0.1)
+	response, err := c.callGemini(ctx, prompt.System, prompt.User, 0.1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to identify concepts: %w", err)
 	}
@@ -137,6 +173,47 @@ Response: limits, continuity`
 	return cleanedConcepts, nil
 }
 
+// languageInstruction asks for the explanation in language while keeping
+// mathematical notation standard. English needs no instruction.
+func languageInstruction(language string) string {
+	if language == "" || strings.EqualFold(language, "English") {
+		return ""
+	}
+	return fmt.Sprintf(`
+
+LANGUAGE: Write the entire explanation in %s. Keep mathematical notation, formulas and symbols in standard form (e.g. f'(x), ∫, lim), and you may give the English name of a concept in parentheses the first time it appears.`, language)
+}
+
+// Audience and approach asked for by each explanation preference
+var (
+	levelInstructions = map[string]string{
+		"beginner":     "The student is a beginner: avoid jargon, define every term and prerequisite you use, and prefer small concrete examples.",
+		"intermediate": "The student knows the basics: briefly recall prerequisites rather than teaching them from scratch.",
+		"advanced":     "The student is advanced: be precise and rigorous, skip elementary steps and mention edge cases and generalizations.",
+	}
+	styleInstructions = map[string]string{
+		"concise":      "Keep the explanation short and to the point.",
+		"step_by_step": "Work through the problem in explicit numbered steps, justifying each one.",
+		"intuitive":    "Lead with intuition, pictures and analogies before the formal argument.",
+	}
+)
+
+// preferenceInstruction adapts the explanation to the user's preferred level
+// and style. No preference needs no instruction.
+func preferenceInstruction(level, style string) string {
+	var parts []string
+	if text, ok := levelInstructions[level]; ok {
+		parts = append(parts, text)
+	}
+	if text, ok := styleInstructions[style]; ok {
+		parts = append(parts, text)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "\n\nSTUDENT PREFERENCES: " + strings.Join(parts, " ")
+}
+
 func (c *Client) GenerateExplanation(ctx context.Context, req ExplanationRequest) (string, error) {
 	// Format prerequisite path
 	pathText := ""
@@ -149,47 +226,44 @@ func (c *Client) GenerateExplanation(ctx context.Context, req ExplanationRequest
 	}
 
 	// Format context chunks
-	contextText := ""
-	if len(req.ContextChunks) > 0 {
-		contextParts := make([]string, len(req.ContextChunks))
-		for i, chunk := range req.ContextChunks {
-			contextParts[i] = fmt.Sprintf("Context %d: %s", i+1, chunk)
-		}
-		contextText = strings.Join(contextParts, "\n\n")
+	contextParts := make([]string, len(req.ContextChunks))
+	for i, chunk := range req.ContextChunks {
+		contextParts[i] = fmt.Sprintf("Context %d: %s", i+1, chunk)
 	}
 
-	systemPrompt := `You are an expert mathematics tutor specializing in calculus. Your goal is to provide clear, complete, educational explanations that help students understand mathematical concepts and their prerequisites.
-
-Guidelines:
-1. Start with the fundamental concepts and build up logically
-2. Explain WHY prerequisites are needed, not just WHAT they are
-3. Use clear, accessible language but maintain mathematical accuracy
-4. Include specific step-by-step solutions with calculations
-5. Address the student's specific question directly
-6. Always provide a COMPLETE explanation - do not truncate your response
-7. Use the provided context and learning path to ground your explanation
-8. End with a clear conclusion or final answer
-
-IMPORTANT: Provide a complete, thorough explanation. Do not stop mid-sentence or leave the explanation incomplete.`
-
-	userPrompt := fmt.Sprintf(`Student Question: %s
-
-%sRelevant Course Material:
-%s
-
-Please provide a complete, educational explanation that:
-1. Addresses the student's question directly
-2. Explains any necessary prerequisite concepts
-3. Shows step-by-step solution with calculations
-4. Shows how the concepts connect to each other
-5. Provides the final numerical answer if applicable
-6. Includes practical guidance for learning
+	render := func(contextParts []string) (prompts.Prompt, error) {
+		return c.prompts.RenderVariant(prompts.Explanation, req.PromptVariant, map[string]string{
+			"Query":                 req.Query,
+			"LearningPath":          pathText,
+			"Context":               strings.Join(contextParts, "\n\n"),
+			"LanguageInstruction":   languageInstruction(req.Language),
+			"PreferenceInstruction": preferenceInstruction(req.Level, req.Style),
+		})
+	}
 
-Make sure to provide a COMPLETE response that fully answers the question.
+	// Size the answer from what the prompt leaves of the token budget,
+	// dropping the weakest context first when it does not fit
+	bare, err := render(nil)
+	if err != nil {
+		return "", err
+	}
+	kept, maxOutput := c.tokenBudget().fitContext(estimateTokens(bare.System+bare.User), contextParts)
+	if len(kept) < len(contextParts) {
+		c.logger.Info("Trimmed context chunks to fit the token budget",
+			zap.Int("chunks", len(contextParts)),
+			zap.Int("kept", len(kept)))
+	}
 
-Explanation:`, req.Query, pathText, contextText)
+	prompt, err := render(kept)
+	if err != nil {
+		return "", err
+	}
 
-	response, err := c.callGemini(ctx, systemPrompt, userPrompt, 0.3)
+	model, temperature, timeout := c.explanationSettings(req.Profile)
+	if req.Model != "" {
+		model = req.Model
+	}
+	response, err := c.generate(ctx, model, timeout, maxOutput, prompt.System, prompt.User, temperature, "")
 	if err != nil {
 		return "", fmt.Errorf("failed to generate explanation: %w", err)
 	}
@@ -201,6 +275,21 @@ Explanation:`, req.Query, pathText, contextText)
 	return response, nil
 }
 
+// explanationSettings returns the model, temperature and timeout of a
+// profile, keeping the defaults for anything the profile leaves unset
+func (c *Client) explanationSettings(profileName string) (string, float32, time.Duration) {
+	profile := c.config.Profile(profileName)
+
+	model := profile.Model
+	if model == "" {
+		model = c.Model()
+	}
+	if profile.Timeout <= 0 {
+		return model, 0.3, DefaultTimeout
+	}
+	return model, float32(profile.Temperature), profile.Timeout
+}
+
 func (c *Client) Provider() string {
 	return "gemini"
 }
@@ -226,31 +315,63 @@ func (c *Client) IsHealthy(ctx context.Context) bool {
 }
 
 func (c *Client) callGemini(ctx context.Context, systemPrompt, userPrompt string, temperature float32) (string, error) {
-	// Use configured model or fallback
-	model := c.config.Model
-	if model == "" {
-		model = DefaultModel
+	return c.generateContent(ctx, systemPrompt, userPrompt, temperature, "")
+}
+
+// callGeminiJSON asks Gemini to answer with a JSON document only
+func (c *Client) callGeminiJSON(ctx context.Context, systemPrompt, userPrompt string, temperature float32) (string, error) {
+	return c.generateContent(ctx, systemPrompt, userPrompt, temperature, "application/json")
+}
+
+func (c *Client) generateContent(ctx context.Context, systemPrompt, userPrompt string, temperature float32, responseMIMEType string) (string, error) {
+	return c.generate(ctx, c.Model(), DefaultTimeout, c.maxTokens(), systemPrompt, userPrompt, temperature, responseMIMEType)
+}
+
+// maxTokens is the answer length every call allows for
+func (c *Client) maxTokens() int {
+	if c.config.MaxTokens <= 0 {
+		return DefaultMaxTokens
 	}
+	return c.config.MaxTokens
+}
+
+// tokenBudget reserves maxTokens for explanation answers within the
+// configured total
+func (c *Client) tokenBudget() tokenBudget {
+	return tokenBudget{total: c.config.TokenBudget, answer: c.maxTokens()}
+}
+
+// generate calls model, giving up after timeout, letting the answer run to
+// maxTokens
+func (c *Client) generate(ctx context.Context, model string, timeout time.Duration, maxTokens int, systemPrompt, userPrompt string, temperature float32, responseMIMEType string) (result string, err error) {
+	start := time.Now()
+	defer func() {
+		c.promptLog.log(model, systemPrompt, userPrompt, result, time.Since(start), err)
+	}()
 
 	// Create the full prompt combining system and user prompts
 	fullPrompt := systemPrompt + "\n\n" + userPrompt
 
-	// Create generation config with proper validation
-	maxTokens := c.config.MaxTokens
-	if maxTokens <= 0 {
-		maxTokens = DefaultMaxTokens
-	}
-
 	config := &genai.GenerateContentConfig{
-		Temperature:     &temperature,
-		MaxOutputTokens: int32(maxTokens),
+		Temperature:      &temperature,
+		MaxOutputTokens:  int32(maxTokens),
+		ResponseMIMEType: responseMIMEType,
 	}
 
-	// Generate content with timeout
-	timeoutCtx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	// Generate content with timeout; waiting for a call slot counts against it
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	resp, err := c.genaiClient.Models.GenerateContent(timeoutCtx, model, genai.Text(fullPrompt), config)
+	// Quota rejections are retried only when the wait fits; the call slot is
+	// released while waiting
+	var resp *genai.GenerateContentResponse
+	err = c.quota.do(timeoutCtx, c.logger, func(ctx context.Context) error {
+		return c.withCallSlot(ctx, func(ctx context.Context) error {
+			var callErr error
+			resp, callErr = c.genaiClient.Models.GenerateContent(ctx, model, genai.Text(fullPrompt), config)
+			return callErr
+		})
+	})
 	if err != nil {
 		return "", fmt.Errorf("Gemini API call failed: %w", err)
 	}
@@ -277,7 +398,7 @@ func (c *Client) callGemini(ctx context.Context, systemPrompt, userPrompt string
 		}
 	}
 
-	result := strings.TrimSpace(content.String())
+	result = strings.TrimSpace(content.String())
 	if result == "" {
 		return "", fmt.Errorf("no text content in Gemini response")
 	}
@@ -285,6 +406,19 @@ func (c *Client) callGemini(ctx context.Context, systemPrompt, userPrompt string
 	return result, nil
 }
 
+// withCallSlot runs fn once a slot in the global LLM semaphore is free.
+// It gives up if ctx ends while waiting.
+func (c *Client) withCallSlot(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.limiter == nil {
+		return fn(ctx)
+	}
+	if err := c.limiter.Acquire(ctx, 1); err != nil {
+		return fmt.Errorf("timed out waiting for LLM call slot: %w", err)
+	}
+	defer c.limiter.Release(1)
+	return fn(ctx)
+}
+
 func (c *Client) isResponseTruncated(response string) bool {
 	if len(response) == 0 {
 		return true
@@ -314,46 +448,20 @@ func (c *Client) isResponseTruncated(response string) bool {
 	return false
 }
 
-const newConceptAnalysisPrompt = `You are an expert mathematics educator analyzing whether a concept should be added to a foundational mathematics knowledge graph.
-
-Given a concept name and the context in which it appeared, determine:
-1. Whether this is a legitimate mathematical concept worthy of inclusion
-2. Its prerequisites (what students must know first)
-3. Its difficulty level (1-10 scale)
-4. Its category (e.g., algebra, calculus, geometry, etc.)
-5. A clear description suitable for students
-
-Respond with ONLY a JSON object in this exact format:
-{
-  "concept_name": "standardized name for the concept",
-  "description": "clear, educational description",
-  "suggested_prerequisites": ["prerequisite1", "prerequisite2"],
-  "suggested_difficulty": 5,
-  "suggested_category": "calculus",
-  "reasoning": "why this concept should/should not be added",
-  "is_likely_new_concept": true
-}
-
-Guidelines:
-- Set is_likely_new_concept to false if it's just a problem-solving technique, not a concept
-- Set is_likely_new_concept to false if it's too specific or not foundational
-- Set is_likely_new_concept to false if it's a variation of an existing concept
-- Difficulty: 1=basic arithmetic, 5=high school calculus, 10=advanced mathematics
-- Prerequisites should be fundamental concepts students MUST know first
-- Use standard mathematical terminology
-
-Concept to analyze: "%s"
-Context (from student's query): "%s"
-`
-
 func (c *Client) AnalyzeNewConcept(ctx context.Context, conceptName string, queryContext string) (*NewConceptAnalysis, error) {
 	c.logger.Info("Analyzing potential new concept",
 		zap.String("concept", conceptName),
 		zap.String("provider", c.config.Provider))
 
-	prompt := fmt.Sprintf(newConceptAnalysisPrompt, conceptName, queryContext)
+	prompt, err := c.prompts.Render(prompts.NewConceptAnalysis, map[string]string{
+		"ConceptName":  conceptName,
+		"QueryContext": queryContext,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	response, err := c.callGemini(ctx, "", prompt, 0.1)
+	response, err := c.callGemini(ctx, prompt.System, prompt.User, 0.1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze concept: %w", err)
 	}
@@ -382,6 +490,61 @@ func (c *Client) AnalyzeNewConcept(ctx context.Context, conceptName string, quer
 	return &analysis, nil
 }
 
+// PlotSpec describes a single-variable function worth plotting
+type PlotSpec struct {
+	Title      string  `json:"title"`
+	Expression string  `json:"expression"`
+	XMin       float64 `json:"x_min"`
+	XMax       float64 `json:"x_max"`
+}
+
+const plotExtractionPrompt = `You are helping illustrate a mathematics explanation with graphs.
+
+Read the explanation below and list up to %d functions of x that would help a student if plotted.
+
+Respond with ONLY a JSON array in this exact format:
+[
+  {"title": "Parabola from the example", "expression": "x^2 - 4*x + 3", "x_min": -2, "x_max": 6}
+]
+
+Rules:
+- Use only x as the variable, numbers, + - * / ^, parentheses, and sin, cos, tan, exp, ln, log, sqrt, abs
+- Do not include "y =" or "f(x) =" in the expression
+- Choose an x range that shows the interesting behaviour (roots, extrema, asymptotes)
+- Return [] if the explanation has no plottable function
+
+Explanation:
+%s
+`
+
+// ExtractPlotFunctions asks the LLM for plottable functions in an explanation
+func (c *Client) ExtractPlotFunctions(ctx context.Context, explanation string, maxPlots int) ([]PlotSpec, error) {
+	prompt := fmt.Sprintf(plotExtractionPrompt, maxPlots, explanation)
+
+	response, err := c.callGemini(ctx, "", prompt, 0.1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract plot functions: %w", err)
+	}
+
+	cleanedResponse := strings.TrimSpace(response)
+	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```json")
+	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```")
+	cleanedResponse = strings.TrimSuffix(cleanedResponse, "```")
+	cleanedResponse = strings.TrimSpace(cleanedResponse)
+
+	var specs []PlotSpec
+	if err := json.Unmarshal([]byte(cleanedResponse), &specs); err != nil {
+		return nil, fmt.Errorf("failed to parse plot functions: %w", err)
+	}
+
+	if len(specs) > maxPlots {
+		specs = specs[:maxPlots]
+	}
+
+	c.logger.Info("Extracted plot functions", zap.Int("count", len(specs)))
+	return specs, nil
+}
+
 // Close gracefully shuts down the client
 func (c *Client) Close() error {
 	c.logger.Info("Closing Gemini LLM client")
@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+func TestWithCallSlotBoundsConcurrency(t *testing.T) {
+	const limit = 3
+	c := &Client{limiter: semaphore.NewWeighted(limit)}
+
+	var inFlight, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := c.withCallSlot(context.Background(), func(ctx context.Context) error {
+				n := atomic.AddInt32(&inFlight, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(2 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > limit {
+		t.Errorf("expected at most %d concurrent calls, saw %d", limit, peak)
+	}
+	if peak == 0 {
+		t.Error("expected calls to run")
+	}
+}
+
+func TestWithCallSlotHonoursContext(t *testing.T) {
+	c := &Client{limiter: semaphore.NewWeighted(1)}
+	if err := c.limiter.Acquire(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	defer c.limiter.Release(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	called := false
+	err := c.withCallSlot(ctx, func(context.Context) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected error while all slots are taken")
+	}
+	if called {
+		t.Error("fn must not run without a slot")
+	}
+}
+
+func TestLanguageInstruction(t *testing.T) {
+	if got := languageInstruction(""); got != "" {
+		t.Errorf("expected no instruction for default language, got %q", got)
+	}
+	if got := languageInstruction("English"); got != "" {
+		t.Errorf("expected no instruction for English, got %q", got)
+	}
+	if got := languageInstruction("Spanish"); !strings.Contains(got, "entire explanation in Spanish") {
+		t.Errorf("expected Spanish instruction, got %q", got)
+	}
+}
+
+func TestPreferenceInstruction(t *testing.T) {
+	if got := preferenceInstruction("", ""); got != "" {
+		t.Errorf("expected no instruction without preferences, got %q", got)
+	}
+	if got := preferenceInstruction("expert", "poetic"); got != "" {
+		t.Errorf("expected unknown preferences to be ignored, got %q", got)
+	}
+	got := preferenceInstruction("beginner", "step_by_step")
+	if !strings.Contains(got, "STUDENT PREFERENCES") || !strings.Contains(got, "beginner") || !strings.Contains(got, "numbered steps") {
+		t.Errorf("expected level and style instructions, got %q", got)
+	}
+}
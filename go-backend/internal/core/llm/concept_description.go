@@ -0,0 +1,31 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const conceptDescriptionPrompt = `You are an expert mathematics educator filling in a knowledge graph entry for the concept "%s".
+
+Write a description of 1-2 sentences that defines the concept precisely and says what it is used for. Respond with the description only, without a heading, quotes or markdown.%s`
+
+// GenerateConceptDescription asks the LLM for a short description of a
+// concept, grounded in any textbook excerpts retrieved for it
+func (c *Client) GenerateConceptDescription(ctx context.Context, conceptName string, contextChunks []string) (string, error) {
+	grounding := ""
+	if len(contextChunks) > 0 {
+		grounding = "\n\nBase the description on these textbook excerpts where they are relevant:\n" + strings.Join(contextChunks, "\n---\n")
+	}
+
+	response, err := c.callGemini(ctx, "", fmt.Sprintf(conceptDescriptionPrompt, conceptName, grounding), 0.2)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate concept description: %w", err)
+	}
+
+	description := strings.Trim(strings.TrimSpace(response), `"`)
+	if description == "" {
+		return "", fmt.Errorf("empty description generated for %s", conceptName)
+	}
+	return description, nil
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// WorkedExample is a solved problem illustrating a concept
+type WorkedExample struct {
+	Problem  string `json:"problem"`
+	Solution string `json:"solution"`
+}
+
+// ConceptProfile is a sectioned description of a concept. When the LLM
+// response cannot be parsed, Structured is false and RawText holds the reply.
+type ConceptProfile struct {
+	Definition     string          `json:"definition"`
+	KeyFormulas    []string        `json:"key_formulas"`
+	WorkedExamples []WorkedExample `json:"worked_examples"`
+	CommonMistakes []string        `json:"common_mistakes"`
+	Applications   []string        `json:"applications"`
+	Structured     bool            `json:"-"`
+	RawText        string          `json:"-"`
+}
+
+const conceptProfilePrompt = `You are an expert mathematics educator writing a study card for the concept "%s".
+
+Respond with ONLY a JSON object in this exact format:
+{
+  "definition": "A precise, student-friendly definition",
+  "key_formulas": ["Formula or theorem statement"],
+  "worked_examples": [
+    {"problem": "A short problem", "solution": "Step-by-step solution"}
+  ],
+  "common_mistakes": ["A mistake students make and how to avoid it"],
+  "applications": ["Where this concept is used"]
+}
+
+Rules:
+- Give 2-3 worked examples and 2-4 common mistakes
+- Use an empty array when a section does not apply (e.g. no formulas)
+- Write formulas in plain text or LaTeX`
+
+// GenerateConceptProfile asks the LLM for a structured profile of a concept.
+// If the reply is not valid JSON the raw text is returned as a fallback.
+func (c *Client) GenerateConceptProfile(ctx context.Context, conceptName string) (*ConceptProfile, error) {
+	prompt := fmt.Sprintf(conceptProfilePrompt, conceptName)
+
+	response, err := c.callGeminiJSON(ctx, "", prompt, 0.3)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate concept profile: %w", err)
+	}
+
+	profile := parseConceptProfile(response)
+	if !profile.Structured {
+		c.logger.Warn("Concept profile was not valid JSON, falling back to raw text",
+			zap.String("concept", conceptName))
+	}
+	return profile, nil
+}
+
+// parseConceptProfile parses a JSON profile, tolerating markdown code fences.
+// Unparseable or empty replies yield an unstructured profile with RawText set.
+func parseConceptProfile(response string) *ConceptProfile {
+	cleanedResponse := strings.TrimSpace(response)
+	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```json")
+	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```")
+	cleanedResponse = strings.TrimSuffix(cleanedResponse, "```")
+	cleanedResponse = strings.TrimSpace(cleanedResponse)
+
+	var profile ConceptProfile
+	if err := json.Unmarshal([]byte(cleanedResponse), &profile); err != nil || profile.Definition == "" {
+		return &ConceptProfile{RawText: strings.TrimSpace(response)}
+	}
+
+	profile.Structured = true
+	return &profile
+}
@@ -0,0 +1,44 @@
+package llm
+
+import "testing"
+
+func TestParseConceptProfile(t *testing.T) {
+	response := "```json\n" + `{
+  "definition": "The derivative measures instantaneous rate of change.",
+  "key_formulas": ["f'(x) = lim h->0 (f(x+h) - f(x))/h"],
+  "worked_examples": [{"problem": "Differentiate x^2", "solution": "2x"}],
+  "common_mistakes": ["Forgetting the chain rule"],
+  "applications": ["Velocity"]
+}` + "\n```"
+
+	profile := parseConceptProfile(response)
+
+	if !profile.Structured {
+		t.Fatalf("expected structured profile, got raw %q", profile.RawText)
+	}
+	if profile.Definition == "" || len(profile.KeyFormulas) != 1 || len(profile.Applications) != 1 {
+		t.Errorf("unexpected profile %+v", profile)
+	}
+	if len(profile.WorkedExamples) != 1 || profile.WorkedExamples[0].Solution != "2x" {
+		t.Errorf("unexpected worked examples %+v", profile.WorkedExamples)
+	}
+	if len(profile.CommonMistakes) != 1 {
+		t.Errorf("unexpected common mistakes %+v", profile.CommonMistakes)
+	}
+}
+
+func TestParseConceptProfileFallsBackToRawText(t *testing.T) {
+	for _, response := range []string{
+		"A derivative is the rate of change of a function.",
+		`{"definition": "truncated`,
+		`{"applications": ["Velocity"]}`,
+	} {
+		profile := parseConceptProfile(response)
+		if profile.Structured {
+			t.Errorf("expected fallback for %q", response)
+		}
+		if profile.RawText != response {
+			t.Errorf("expected raw text %q, got %q", response, profile.RawText)
+		}
+	}
+}
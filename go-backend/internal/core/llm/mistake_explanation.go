@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Mistake error types
+const (
+	MistakeConceptual = "conceptual" // misunderstood or misapplied concept
+	MistakeProcedural = "procedural" // wrong method or a skipped step
+	MistakeArithmetic = "arithmetic" // calculation slip
+	MistakeNotation   = "notation"   // right idea written incorrectly
+	MistakeNone       = "none"       // the attempt is correct
+)
+
+// MistakeRequest is a problem and a student's attempt at it
+type MistakeRequest struct {
+	Problem       string
+	Attempt       string
+	Concepts      []string
+	ContextChunks []string
+	Language      string
+}
+
+// MistakeExplanation pinpoints where an attempt goes wrong. When the LLM
+// reply cannot be parsed, Structured is false and RawText holds the reply.
+type MistakeExplanation struct {
+	ErrorStep        string   `json:"error_step"`
+	ErrorType        string   `json:"error_type"`
+	WhatWentWrong    string   `json:"what_went_wrong"`
+	CorrectApproach  string   `json:"correct_approach"`
+	ConceptsToReview []string `json:"concepts_to_review"`
+	Structured       bool     `json:"-"`
+	RawText          string   `json:"-"`
+}
+
+const mistakeExplanationPrompt = `You are a patient mathematics tutor reviewing a student's work.
+
+Problem:
+%s
+
+Student's attempt:
+%s
+
+%sRelevant course material:
+%s
+
+Find the FIRST step where the attempt goes wrong and explain it.
+
+Respond with ONLY a JSON object in this exact format:
+{
+  "error_step": "The step from the attempt where it goes wrong, quoted exactly",
+  "error_type": "conceptual",
+  "what_went_wrong": "Why that step is wrong, addressed to the student",
+  "correct_approach": "How to do that step correctly, then the rest of the solution step by step",
+  "concepts_to_review": ["Concept the student should revisit"]
+}
+
+Rules:
+- error_type is one of: conceptual, procedural, arithmetic, notation, none
+- If the attempt is correct, use error_type "none", leave error_step empty and confirm the answer in correct_approach
+- Do not invent mistakes; quote error_step from the attempt
+- Keep the tone encouraging`
+
+// ExplainMistake asks the LLM where a student's attempt at a problem goes
+// wrong. If the reply is not valid JSON the raw text is returned instead.
+func (c *Client) ExplainMistake(ctx context.Context, req MistakeRequest) (*MistakeExplanation, error) {
+	conceptsText := ""
+	if len(req.Concepts) > 0 {
+		conceptsText = fmt.Sprintf("Concepts involved: %s\n\n", strings.Join(req.Concepts, ", "))
+	}
+
+	contextText := "(none)"
+	if len(req.ContextChunks) > 0 {
+		parts := make([]string, len(req.ContextChunks))
+		for i, chunk := range req.ContextChunks {
+			parts[i] = fmt.Sprintf("Context %d: %s", i+1, chunk)
+		}
+		contextText = strings.Join(parts, "\n\n")
+	}
+
+	prompt := fmt.Sprintf(mistakeExplanationPrompt, req.Problem, req.Attempt, conceptsText, contextText) +
+		languageInstruction(req.Language)
+
+	response, err := c.callGeminiJSON(ctx, "", prompt, 0.2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain mistake: %w", err)
+	}
+
+	explanation := parseMistakeExplanation(response)
+	if !explanation.Structured {
+		c.logger.Warn("Mistake explanation was not valid JSON, falling back to raw text")
+		return explanation, nil
+	}
+
+	c.logger.Info("Explained student mistake",
+		zap.String("error_type", explanation.ErrorType),
+		zap.Int("concepts_to_review", len(explanation.ConceptsToReview)))
+	return explanation, nil
+}
+
+// parseMistakeExplanation parses a JSON explanation, tolerating markdown code
+// fences. Unparseable replies, or ones that explain nothing, yield an
+// unstructured explanation with RawText set.
+func parseMistakeExplanation(response string) *MistakeExplanation {
+	cleanedResponse := strings.TrimSpace(response)
+	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```json")
+	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```")
+	cleanedResponse = strings.TrimSuffix(cleanedResponse, "```")
+	cleanedResponse = strings.TrimSpace(cleanedResponse)
+
+	var explanation MistakeExplanation
+	if err := json.Unmarshal([]byte(cleanedResponse), &explanation); err != nil ||
+		(explanation.WhatWentWrong == "" && explanation.CorrectApproach == "") {
+		return &MistakeExplanation{RawText: strings.TrimSpace(response)}
+	}
+
+	switch explanation.ErrorType {
+	case MistakeConceptual, MistakeProcedural, MistakeArithmetic, MistakeNotation, MistakeNone:
+	default:
+		explanation.ErrorType = MistakeConceptual
+	}
+	if explanation.ConceptsToReview == nil {
+		explanation.ConceptsToReview = []string{}
+	}
+	explanation.Structured = true
+	return &explanation
+}
@@ -0,0 +1,55 @@
+package llm
+
+import "testing"
+
+func TestParseMistakeExplanation(t *testing.T) {
+	response := "```json\n" + `{
+  "error_step": "2x + 3 = 7 so 2x = 10",
+  "error_type": "arithmetic",
+  "what_went_wrong": "You added 3 to both sides instead of subtracting it.",
+  "correct_approach": "Subtract 3: 2x = 4, so x = 2.",
+  "concepts_to_review": ["Linear Equations"]
+}` + "\n```"
+
+	explanation := parseMistakeExplanation(response)
+
+	if !explanation.Structured {
+		t.Fatalf("expected structured explanation, got raw %q", explanation.RawText)
+	}
+	if explanation.ErrorType != MistakeArithmetic || explanation.ErrorStep == "" {
+		t.Errorf("unexpected explanation %+v", explanation)
+	}
+	if len(explanation.ConceptsToReview) != 1 || explanation.ConceptsToReview[0] != "Linear Equations" {
+		t.Errorf("unexpected concepts to review %+v", explanation.ConceptsToReview)
+	}
+}
+
+func TestParseMistakeExplanationNormalizesErrorType(t *testing.T) {
+	explanation := parseMistakeExplanation(`{"error_type": "typo", "what_went_wrong": "Sign error."}`)
+
+	if !explanation.Structured {
+		t.Fatalf("expected structured explanation, got raw %q", explanation.RawText)
+	}
+	if explanation.ErrorType != MistakeConceptual {
+		t.Errorf("expected unknown error type to become %q, got %q", MistakeConceptual, explanation.ErrorType)
+	}
+	if explanation.ConceptsToReview == nil {
+		t.Error("expected an empty concepts_to_review slice, got nil")
+	}
+}
+
+func TestParseMistakeExplanationFallsBackToRawText(t *testing.T) {
+	for _, response := range []string{
+		"You added 3 instead of subtracting it.",
+		`{"error_step": "truncated`,
+		`{"error_type": "arithmetic"}`,
+	} {
+		explanation := parseMistakeExplanation(response)
+		if explanation.Structured {
+			t.Errorf("expected fallback for %q", response)
+		}
+		if explanation.RawText != response {
+			t.Errorf("expected raw text %q, got %q", response, explanation.RawText)
+		}
+	}
+}
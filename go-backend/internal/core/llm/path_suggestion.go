@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// maxSuggestedPrerequisites bounds a suggested path so a rambling reply
+// cannot flood the response or the review queue
+const maxSuggestedPrerequisites = 8
+
+// SuggestedPrerequisite is a concept the LLM thinks should be learned first
+type SuggestedPrerequisite struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+const prerequisitePathPrompt = `You are an expert mathematics educator. A student asked: %q
+
+It involves these concepts, which are not yet in our curated prerequisite graph: %s
+
+List the prerequisite concepts the student should learn first, ordered from the most foundational to the one needed last.
+
+Respond with ONLY a JSON object in this exact format:
+{
+  "prerequisites": [
+    {"name": "Concept name", "description": "One sentence on why it is needed"}
+  ]
+}
+
+Rules:
+- Give at most %d prerequisites, using standard concept names
+- Do not list the concepts above themselves
+- Use an empty array when no prerequisites are needed`
+
+// SuggestPrerequisitePath asks the LLM for a prerequisite ordering of
+// concepts the curated graph does not know, most foundational first
+func (c *Client) SuggestPrerequisitePath(ctx context.Context, query string, concepts []string) ([]SuggestedPrerequisite, error) {
+	prompt := fmt.Sprintf(prerequisitePathPrompt, query, strings.Join(concepts, ", "), maxSuggestedPrerequisites)
+
+	response, err := c.callGeminiJSON(ctx, "", prompt, 0.2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest prerequisites: %w", err)
+	}
+
+	suggestions, err := parseSuggestedPrerequisites(response, concepts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Suggested prerequisite path",
+		zap.Strings("concepts", concepts),
+		zap.Int("prerequisites", len(suggestions)))
+	return suggestions, nil
+}
+
+// parseSuggestedPrerequisites parses a JSON suggestion, tolerating markdown
+// code fences. Blank, repeated and target concepts are dropped and the list
+// is capped at maxSuggestedPrerequisites.
+func parseSuggestedPrerequisites(response string, concepts []string) ([]SuggestedPrerequisite, error) {
+	cleanedResponse := strings.TrimSpace(response)
+	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```json")
+	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```")
+	cleanedResponse = strings.TrimSuffix(cleanedResponse, "```")
+	cleanedResponse = strings.TrimSpace(cleanedResponse)
+
+	var parsed struct {
+		Prerequisites []SuggestedPrerequisite `json:"prerequisites"`
+	}
+	if err := json.Unmarshal([]byte(cleanedResponse), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse suggested prerequisites: %w", err)
+	}
+
+	seen := make(map[string]bool, len(concepts))
+	for _, concept := range concepts {
+		seen[strings.ToLower(strings.TrimSpace(concept))] = true
+	}
+
+	suggestions := []SuggestedPrerequisite{}
+	for _, suggestion := range parsed.Prerequisites {
+		suggestion.Name = strings.TrimSpace(suggestion.Name)
+		key := strings.ToLower(suggestion.Name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		suggestion.Description = strings.TrimSpace(suggestion.Description)
+		suggestions = append(suggestions, suggestion)
+		if len(suggestions) == maxSuggestedPrerequisites {
+			break
+		}
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,52 @@
+package llm
+
+import "testing"
+
+func TestParseSuggestedPrerequisites(t *testing.T) {
+	response := "```json\n" + `{
+  "prerequisites": [
+    {"name": " Functions ", "description": "Maps inputs to outputs"},
+    {"name": "limits", "description": "Repeated with other casing"},
+    {"name": "Limits", "description": "Approaching a value"},
+    {"name": "Jacobian Matrix", "description": "The concept itself"},
+    {"name": "", "description": "Blank"}
+  ]
+}` + "\n```"
+
+	suggestions, err := parseSuggestedPrerequisites(response, []string{"Jacobian matrix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(suggestions) != 2 {
+		t.Fatalf("expected 2 suggestions, got %+v", suggestions)
+	}
+	if suggestions[0].Name != "Functions" || suggestions[1].Name != "limits" {
+		t.Errorf("expected Functions then limits in the given order, got %+v", suggestions)
+	}
+}
+
+func TestParseSuggestedPrerequisitesCapsLength(t *testing.T) {
+	response := `{"prerequisites": [`
+	for i := 0; i < maxSuggestedPrerequisites+3; i++ {
+		if i > 0 {
+			response += ","
+		}
+		response += `{"name": "Concept ` + string(rune('A'+i)) + `"}`
+	}
+	response += `]}`
+
+	suggestions, err := parseSuggestedPrerequisites(response, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != maxSuggestedPrerequisites {
+		t.Errorf("expected %d suggestions, got %d", maxSuggestedPrerequisites, len(suggestions))
+	}
+}
+
+func TestParseSuggestedPrerequisitesRejectsInvalidJSON(t *testing.T) {
+	if _, err := parseSuggestedPrerequisites("Start with functions, then limits.", nil); err == nil {
+		t.Error("expected an error for a non-JSON reply")
+	}
+}
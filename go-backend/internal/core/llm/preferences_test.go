@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/core/llm/prompts"
+	"go.uber.org/zap"
+	"google.golang.org/genai"
+)
+
+func TestGenerateExplanationAppliesPreferences(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(raw))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(geminiOK))
+	}))
+	defer server.Close()
+
+	genaiClient, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      "test-key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := prompts.NewRegistry("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{
+		genaiClient: genaiClient,
+		logger:      zap.NewNop(),
+		prompts:     registry,
+		config:      config.LLMConfig{MaxTokens: 1000},
+	}
+
+	for _, req := range []ExplanationRequest{
+		{Query: "What is a derivative?"},
+		{Query: "What is a derivative?", Level: "beginner", Style: "intuitive"},
+	} {
+		if _, err := client.GenerateExplanation(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected two explanation calls, got %d", len(bodies))
+	}
+	if strings.Contains(bodies[0], "STUDENT PREFERENCES") {
+		t.Error("expected the default prompt without preferences")
+	}
+	if !strings.Contains(bodies[1], "STUDENT PREFERENCES") ||
+		!strings.Contains(bodies[1], levelInstructions["beginner"]) ||
+		!strings.Contains(bodies[1], styleInstructions["intuitive"]) {
+		t.Errorf("expected the level and style instructions in the prompt, got %s", bodies[1])
+	}
+}
@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Patterns of data that must not reach the logs with a prompt
+var redactions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[email]"},
+	{regexp.MustCompile(`AIza[0-9A-Za-z_-]{35}`), "[api-key]"},
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{16,}`), "[api-key]"},
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`), "Bearer [token]"},
+	// Card and account numbers; math rarely needs 13 or more digits in a row
+	{regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), "[number]"},
+}
+
+// promptLogger logs LLM prompts and responses for debugging. A nil
+// *promptLogger logs nothing, which is how prompt logging is switched off.
+type promptLogger struct {
+	logger *zap.Logger
+	// secrets are literal values, such as the API key, always redacted
+	secrets []string
+}
+
+// newPromptLogger returns nil unless enabled
+func newPromptLogger(enabled bool, logger *zap.Logger, secrets ...string) *promptLogger {
+	if !enabled {
+		return nil
+	}
+
+	l := &promptLogger{logger: logger.With(zap.String("component", "llm_prompts"))}
+	for _, secret := range secrets {
+		if secret != "" {
+			l.secrets = append(l.secrets, secret)
+		}
+	}
+	if !logger.Core().Enabled(zap.DebugLevel) {
+		logger.Warn("LLM prompt logging is on but debug logs are off; set LOG_LEVEL=debug to see prompts")
+	} else {
+		logger.Warn("LLM prompt logging is on; full prompts and responses are written to the logs")
+	}
+	return l
+}
+
+// log records one call's prompts and its raw response or error
+func (l *promptLogger) log(model, systemPrompt, userPrompt, response string, elapsed time.Duration, err error) {
+	if l == nil || !l.logger.Core().Enabled(zap.DebugLevel) {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("model", model),
+		zap.String("system_prompt", l.redact(systemPrompt)),
+		zap.String("user_prompt", l.redact(userPrompt)),
+		zap.String("response", l.redact(response)),
+		zap.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		fields = append(fields, zap.String("error", l.redact(err.Error())))
+	}
+	l.logger.Debug("LLM call", fields...)
+}
+
+func (l *promptLogger) redact(text string) string {
+	for _, secret := range l.secrets {
+		text = strings.ReplaceAll(text, secret, "[secret]")
+	}
+	for _, r := range redactions {
+		text = r.pattern.ReplaceAllString(text, r.replacement)
+	}
+	return text
+}
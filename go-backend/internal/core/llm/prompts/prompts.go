@@ -0,0 +1,294 @@
+// Package prompts renders the LLM prompts of each operation from text
+// templates, so they can be tuned or A/B tested without recompiling.
+//
+// Templates are named <operation>[.<variant>].<part>.tmpl, where part is
+// system or user. The embedded set is the default variant of every
+// operation; a prompts directory can replace those files or add variants.
+package prompts
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Operation is an LLM call with its own prompt
+type Operation string
+
+const (
+	IdentifyConcepts   Operation = "identify_concepts"
+	Explanation        Operation = "explanation"
+	NewConceptAnalysis Operation = "new_concept_analysis"
+)
+
+// DefaultVariant is the variant used for operations given none
+const DefaultVariant = "default"
+
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// placeholders lists what each operation's templates may reference; the
+// required ones must appear in the system or user template of every variant
+var placeholders = map[Operation]struct {
+	required []string
+	optional []string
+}{
+	IdentifyConcepts:   {required: []string{"Query"}},
+	Explanation:        {required: []string{"Query", "Context", "LanguageInstruction"}, optional: []string{"LearningPath", "PreferenceInstruction"}},
+	NewConceptAnalysis: {required: []string{"ConceptName", "QueryContext"}},
+}
+
+// Prompt is a rendered prompt; System may be empty
+type Prompt struct {
+	System string
+	User   string
+}
+
+// variant is one version of an operation's prompt
+type variant struct {
+	system *template.Template
+	user   *template.Template
+}
+
+// Registry holds every prompt variant and which one each operation uses
+type Registry struct {
+	variants map[Operation]map[string]*variant
+	active   map[Operation]string
+}
+
+// NewRegistry loads the embedded templates, then any in dir, which replace
+// or add to them. active picks a variant per operation; operations left out
+// use DefaultVariant. Every variant is validated, not only the active ones.
+func NewRegistry(dir string, active map[string]string) (*Registry, error) {
+	r := &Registry{
+		variants: make(map[Operation]map[string]*variant),
+		active:   make(map[Operation]string),
+	}
+
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(embedded); err != nil {
+		return nil, fmt.Errorf("failed to load embedded prompt templates: %w", err)
+	}
+	if dir != "" {
+		if err := r.load(os.DirFS(dir)); err != nil {
+			return nil, fmt.Errorf("failed to load prompt templates from %s: %w", dir, err)
+		}
+	}
+
+	for op, variants := range r.variants {
+		for name, v := range variants {
+			if err := validate(op, v); err != nil {
+				return nil, fmt.Errorf("%s prompt %q: %w", op, name, err)
+			}
+		}
+	}
+
+	for op := range placeholders {
+		r.active[op] = DefaultVariant
+	}
+	for op, name := range active {
+		if _, ok := placeholders[Operation(op)]; !ok {
+			return nil, fmt.Errorf("unknown prompt operation %q", op)
+		}
+		if _, ok := r.variants[Operation(op)][name]; !ok {
+			return nil, fmt.Errorf("%s has no prompt variant %q", op, name)
+		}
+		r.active[Operation(op)] = name
+	}
+	return r, nil
+}
+
+func (r *Registry) load(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		op, name, part, err := parseFileName(filepath.Base(file))
+		if err != nil {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(file).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		if r.variants[op] == nil {
+			r.variants[op] = make(map[string]*variant)
+		}
+		v := r.variants[op][name]
+		if v == nil {
+			v = &variant{}
+			r.variants[op][name] = v
+		}
+		if part == "system" {
+			v.system = tmpl
+		} else {
+			v.user = tmpl
+		}
+	}
+	return nil
+}
+
+// parseFileName splits <operation>[.<variant>].<part>.tmpl
+func parseFileName(file string) (Operation, string, string, error) {
+	parts := strings.Split(strings.TrimSuffix(file, ".tmpl"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", "", "", fmt.Errorf("%s: want <operation>[.<variant>].<system|user>.tmpl", file)
+	}
+	op, part, name := Operation(parts[0]), parts[len(parts)-1], DefaultVariant
+	if len(parts) == 3 {
+		name = parts[1]
+	}
+	if _, ok := placeholders[op]; !ok {
+		return "", "", "", fmt.Errorf("%s: unknown prompt operation %q", file, op)
+	}
+	if part != "system" && part != "user" {
+		return "", "", "", fmt.Errorf("%s: part must be system or user, got %q", file, part)
+	}
+	return op, name, part, nil
+}
+
+// validate checks a variant has a user template, references every required
+// placeholder and nothing the operation does not provide
+func validate(op Operation, v *variant) error {
+	if v.user == nil {
+		return fmt.Errorf("missing user template")
+	}
+
+	used := make(map[string]bool)
+	for _, tmpl := range []*template.Template{v.system, v.user} {
+		if tmpl != nil {
+			collectFields(tmpl.Tree.Root, used)
+		}
+	}
+
+	spec := placeholders[op]
+	allowed := make(map[string]bool)
+	for _, name := range append(spec.required, spec.optional...) {
+		allowed[name] = true
+	}
+	for name := range used {
+		if !allowed[name] {
+			return fmt.Errorf("unknown placeholder {{.%s}}", name)
+		}
+	}
+	for _, name := range spec.required {
+		if !used[name] {
+			return fmt.Errorf("missing required placeholder {{.%s}}", name)
+		}
+	}
+	return nil
+}
+
+// collectFields records the top-level fields a template references
+func collectFields(node parse.Node, fields map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, fields)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, fields)
+	case *parse.IfNode:
+		collectFields(n.Pipe, fields)
+		collectFields(n.List, fields)
+		collectFields(n.ElseList, fields)
+	case *parse.RangeNode:
+		collectFields(n.Pipe, fields)
+		collectFields(n.List, fields)
+		collectFields(n.ElseList, fields)
+	case *parse.WithNode:
+		collectFields(n.Pipe, fields)
+		collectFields(n.List, fields)
+		collectFields(n.ElseList, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectFields(arg, fields)
+		}
+	case *parse.FieldNode:
+		fields[n.Ident[0]] = true
+	}
+}
+
+// Render fills in the active variant of an operation's prompt
+func (r *Registry) Render(op Operation, data map[string]string) (Prompt, error) {
+	return r.RenderVariant(op, "", data)
+}
+
+// RenderVariant fills in the named variant of an operation's prompt, or the
+// active one when name is empty
+func (r *Registry) RenderVariant(op Operation, name string, data map[string]string) (Prompt, error) {
+	if name == "" {
+		name = r.active[op]
+	}
+	v, ok := r.variants[op][name]
+	if !ok {
+		return Prompt{}, fmt.Errorf("no %s prompt variant %q", op, name)
+	}
+
+	var prompt Prompt
+	var err error
+	if v.system != nil {
+		if prompt.System, err = execute(v.system, data); err != nil {
+			return Prompt{}, fmt.Errorf("failed to render %s system prompt: %w", op, err)
+		}
+	}
+	if prompt.User, err = execute(v.user, data); err != nil {
+		return Prompt{}, fmt.Errorf("failed to render %s user prompt: %w", op, err)
+	}
+	return prompt, nil
+}
+
+func execute(tmpl *template.Template, data map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Active returns the variant an operation renders with
+func (r *Registry) Active(op Operation) string {
+	return r.active[op]
+}
+
+// HasVariant reports whether an operation has a variant of the given name
+func (r *Registry) HasVariant(op Operation, name string) bool {
+	_, ok := r.variants[op][name]
+	return ok
+}
+
+// Variants lists the loaded variants of an operation
+func (r *Registry) Variants(op Operation) []string {
+	names := make([]string, 0, len(r.variants[op]))
+	for name := range r.variants[op] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
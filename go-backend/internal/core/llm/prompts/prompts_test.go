@@ -0,0 +1,167 @@
+package prompts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultsRender(t *testing.T) {
+	r, err := NewRegistry("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prompt, err := r.Render(IdentifyConcepts, map[string]string{"Query": "what is a limit?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt.User != "Student query: 'what is a limit?'\n\nIdentified concepts:" {
+		t.Errorf("unexpected user prompt %q", prompt.User)
+	}
+	if !strings.HasPrefix(prompt.System, "You are an expert in mathematics education.") {
+		t.Errorf("unexpected system prompt %q", prompt.System)
+	}
+
+	prompt, err = r.Render(Explanation, map[string]string{
+		"Query":                 "Differentiate x^2",
+		"LearningPath":          "Learning path: Limits → Derivatives\n\n",
+		"Context":               "Context 1: the power rule",
+		"LanguageInstruction":   "\n\nLANGUAGE: Write the entire explanation in Spanish.",
+		"PreferenceInstruction": "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(prompt.User, "Student Question: Differentiate x^2\n\nLearning path: Limits → Derivatives\n\nRelevant Course Material:\nContext 1: the power rule\n") {
+		t.Errorf("unexpected user prompt %q", prompt.User)
+	}
+	if !strings.HasSuffix(prompt.System, "leave the explanation incomplete.\n\nLANGUAGE: Write the entire explanation in Spanish.") {
+		t.Errorf("expected the language instruction to end the system prompt, got %q", prompt.System)
+	}
+
+	prompt, err = r.Render(NewConceptAnalysis, map[string]string{
+		"ConceptName":  "Taylor Series",
+		"QueryContext": "approximate sin x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt.System != "" {
+		t.Errorf("expected no system prompt, got %q", prompt.System)
+	}
+	if !strings.HasSuffix(prompt.User, "Concept to analyze: \"Taylor Series\"\nContext (from student's query): \"approximate sin x\"") {
+		t.Errorf("unexpected user prompt ending %q", prompt.User)
+	}
+	start, end := strings.Index(prompt.User, "{"), strings.Index(prompt.User, "}")
+	var example map[string]interface{}
+	if err := json.Unmarshal([]byte(prompt.User[start:end+1]), &example); err != nil {
+		t.Errorf("expected the example response to stay valid JSON: %v", err)
+	}
+}
+
+func TestRenderMissingData(t *testing.T) {
+	r, err := NewRegistry("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Render(IdentifyConcepts, map[string]string{}); err == nil {
+		t.Error("expected an error when a placeholder has no value")
+	}
+}
+
+func TestDirOverridesAndAddsVariants(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "identify_concepts.user.tmpl", "Concepts in: {{.Query}}")
+	writeTemplate(t, dir, "explanation.concise.system.tmpl", "Be brief.{{.LanguageInstruction}}")
+	writeTemplate(t, dir, "explanation.concise.user.tmpl", "{{.Query}}\n{{if .LearningPath}}{{.LearningPath}}{{end}}{{.Context}}")
+
+	r, err := NewRegistry(dir, map[string]string{"explanation": "concise"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prompt, err := r.Render(IdentifyConcepts, map[string]string{"Query": "limits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt.User != "Concepts in: limits" {
+		t.Errorf("expected the override, got %q", prompt.User)
+	}
+	if !strings.HasPrefix(prompt.System, "You are an expert in mathematics education.") {
+		t.Errorf("expected the built-in system prompt to remain, got %q", prompt.System)
+	}
+
+	if r.Active(Explanation) != "concise" || r.Active(IdentifyConcepts) != DefaultVariant {
+		t.Errorf("unexpected active variants %q, %q", r.Active(Explanation), r.Active(IdentifyConcepts))
+	}
+	if got := r.Variants(Explanation); len(got) != 2 || got[0] != "concise" || got[1] != DefaultVariant {
+		t.Errorf("expected concise and default variants, got %v", got)
+	}
+	prompt, err = r.Render(Explanation, map[string]string{
+		"Query": "q", "LearningPath": "", "Context": "c", "LanguageInstruction": "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt.System != "Be brief." || prompt.User != "q\nc" {
+		t.Errorf("expected the concise variant, got %+v", prompt)
+	}
+}
+
+func TestNewRegistryRejectsInvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		active  map[string]string
+		wantErr string
+	}{
+		{
+			name:    "missing required placeholder",
+			files:   map[string]string{"explanation.user.tmpl": "{{.Query}} {{.LanguageInstruction}}"},
+			wantErr: "missing required placeholder {{.Context}}",
+		},
+		{
+			name:    "unknown placeholder",
+			files:   map[string]string{"identify_concepts.user.tmpl": "{{.Query}} {{.Student}}"},
+			wantErr: "unknown placeholder {{.Student}}",
+		},
+		{
+			name:    "variant without user template",
+			files:   map[string]string{"identify_concepts.terse.system.tmpl": "{{.Query}}"},
+			wantErr: "missing user template",
+		},
+		{
+			name:    "unknown operation",
+			files:   map[string]string{"summarize.user.tmpl": "{{.Query}}"},
+			wantErr: "unknown prompt operation",
+		},
+		{
+			name:    "unknown variant",
+			active:  map[string]string{"explanation": "concise"},
+			wantErr: `no prompt variant "concise"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeTemplate(t, dir, name, content)
+			}
+			_, err := NewRegistry(dir, tt.active)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mathprereq/internal/core/config"
+	"go.uber.org/zap"
+	"google.golang.org/genai"
+)
+
+// ErrQuotaExceeded is returned when Gemini keeps rejecting calls for quota or
+// rate-limit reasons. Callers should tell users the service is busy rather
+// than report a generic failure.
+var ErrQuotaExceeded = errors.New("LLM quota exceeded")
+
+// Defaults used when the config leaves quota settings unset
+const (
+	DefaultQuotaBackoff = 5 * time.Second
+	DefaultQuotaMaxWait = 20 * time.Second
+)
+
+// QuotaError is a quota rejection that outlasted the retries. RetryAfter is
+// how long Gemini asked callers to wait, zero when it did not say.
+type QuotaError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *QuotaError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v (retry after %s): %v", ErrQuotaExceeded, e.RetryAfter, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", ErrQuotaExceeded, e.Err)
+}
+
+func (e *QuotaError) Is(target error) bool { return target == ErrQuotaExceeded }
+
+func (e *QuotaError) Unwrap() error { return e.Err }
+
+// quotaPolicy decides whether a quota rejection is worth waiting out. Delays
+// longer than maxWait are not waited for, so one busy minute cannot stall a
+// request; the caller gets a QuotaError instead.
+type quotaPolicy struct {
+	maxRetries int
+	maxWait    time.Duration
+	backoff    time.Duration
+}
+
+func newQuotaPolicy(cfg config.LLMConfig) quotaPolicy {
+	policy := quotaPolicy{
+		maxRetries: cfg.QuotaMaxRetries,
+		maxWait:    cfg.QuotaMaxWait,
+		backoff:    cfg.QuotaBackoff,
+	}
+	if policy.maxWait <= 0 {
+		policy.maxWait = DefaultQuotaMaxWait
+	}
+	if policy.backoff <= 0 {
+		policy.backoff = DefaultQuotaBackoff
+	}
+	return policy
+}
+
+// do runs fn, retrying quota rejections after the delay Gemini asked for, or
+// an exponential backoff when it gave none
+func (p quotaPolicy) do(ctx context.Context, logger *zap.Logger, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		hint := &retryAfterHint{}
+		err := fn(context.WithValue(ctx, retryAfterKey{}, hint))
+		if err == nil || !isQuotaError(err) {
+			return err
+		}
+
+		delay := hint.get()
+		if delay <= 0 {
+			delay = retryInfoDelay(err)
+		}
+		quotaErr := &QuotaError{RetryAfter: delay, Err: err}
+		if delay <= 0 {
+			delay = p.backoff << attempt
+		}
+
+		if attempt >= p.maxRetries || delay > p.maxWait || !fitsDeadline(ctx, delay) {
+			return quotaErr
+		}
+
+		logger.Warn("Gemini quota exceeded, waiting before retry",
+			zap.Int("attempt", attempt+1),
+			zap.Duration("delay", delay))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return quotaErr
+		case <-timer.C:
+		}
+	}
+}
+
+// fitsDeadline reports whether waiting delay still leaves ctx time to retry
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > delay
+}
+
+// isQuotaError reports whether Gemini rejected the call for quota or rate limits
+func isQuotaError(err error) bool {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Status == "RESOURCE_EXHAUSTED"
+	}
+	return false
+}
+
+// retryInfoDelay reads the google.rpc.RetryInfo delay Gemini attaches to
+// quota errors, e.g. {"@type": ".../google.rpc.RetryInfo", "retryDelay": "37s"}
+func retryInfoDelay(err error) time.Duration {
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) {
+		return 0
+	}
+	for _, detail := range apiErr.Details {
+		kind, _ := detail["@type"].(string)
+		if !strings.HasSuffix(kind, "google.rpc.RetryInfo") {
+			continue
+		}
+		if raw, ok := detail["retryDelay"].(string); ok {
+			if delay, err := time.ParseDuration(raw); err == nil && delay > 0 {
+				return delay
+			}
+		}
+	}
+	return 0
+}
+
+type retryAfterKey struct{}
+
+// retryAfterHint carries a Retry-After header from the HTTP transport back to
+// the call that received it; the genai client does not expose headers
+type retryAfterHint struct {
+	mu    sync.Mutex
+	delay time.Duration
+}
+
+func (h *retryAfterHint) set(delay time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.delay = delay
+}
+
+func (h *retryAfterHint) get() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.delay
+}
+
+// retryAfterTransport records the Retry-After header of 429 responses on the
+// request's retryAfterHint
+type retryAfterTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	if hint, ok := req.Context().Value(retryAfterKey{}).(*retryAfterHint); ok {
+		if delay := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); delay > 0 {
+			hint.set(delay)
+		}
+	}
+	return resp, nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return at.Sub(now)
+	}
+	return 0
+}
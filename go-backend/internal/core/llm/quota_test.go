@@ -0,0 +1,151 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/core/config"
+	"go.uber.org/zap"
+	"google.golang.org/genai"
+)
+
+const (
+	geminiOK        = `{"candidates": [{"content": {"role": "model", "parts": [{"text": "OK"}]}}]}`
+	geminiQuota     = `{"error": {"code": 429, "message": "Resource has been exhausted", "status": "RESOURCE_EXHAUSTED"}}`
+	geminiRetryInfo = `{"error": {"code": 429, "message": "Quota exceeded", "status": "RESOURCE_EXHAUSTED",
+  "details": [{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "0.01s"}]}}`
+)
+
+// newTestClient points a client at a fake Gemini server that answers the
+// n-th call (from 1) with handle(n)
+func newTestClient(t *testing.T, policy quotaPolicy, handle func(w http.ResponseWriter, n int32)) (*Client, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		handle(w, atomic.AddInt32(&calls, 1))
+	}))
+	t.Cleanup(server.Close)
+
+	genaiClient, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      "test-key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPClient:  &http.Client{Transport: &retryAfterTransport{base: http.DefaultTransport}},
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{genaiClient: genaiClient, logger: zap.NewNop(), quota: policy}, &calls
+}
+
+func TestCallGeminiHonoursRetryAfter(t *testing.T) {
+	client, calls := newTestClient(t, quotaPolicy{maxRetries: 1, maxWait: 5 * time.Second, backoff: time.Millisecond},
+		func(w http.ResponseWriter, n int32) {
+			if n == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(geminiQuota))
+				return
+			}
+			w.Write([]byte(geminiOK))
+		})
+
+	start := time.Now()
+	response, err := client.callGemini(context.Background(), "", "ping", 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response != "OK" {
+		t.Errorf("expected OK, got %q", response)
+	}
+	if *calls != 2 {
+		t.Errorf("expected one retry, got %d calls", *calls)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait the 1s Retry-After, retried after %s", elapsed)
+	}
+}
+
+func TestCallGeminiFailsFastOnLongRetryAfter(t *testing.T) {
+	client, calls := newTestClient(t, quotaPolicy{maxRetries: 3, maxWait: 20 * time.Second, backoff: time.Millisecond},
+		func(w http.ResponseWriter, n int32) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(geminiQuota))
+		})
+
+	_, err := client.callGemini(context.Background(), "", "ping", 0.1)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || quotaErr.RetryAfter != time.Minute {
+		t.Errorf("expected retry after 1m, got %+v", quotaErr)
+	}
+	if *calls != 1 {
+		t.Errorf("expected no retry beyond the max wait, got %d calls", *calls)
+	}
+}
+
+func TestCallGeminiUsesRetryInfoWithoutHeader(t *testing.T) {
+	client, calls := newTestClient(t, quotaPolicy{maxRetries: 2, maxWait: time.Second, backoff: time.Millisecond},
+		func(w http.ResponseWriter, n int32) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(geminiRetryInfo))
+		})
+
+	_, err := client.callGemini(context.Background(), "", "ping", 0.1)
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expected a quota error, got %v", err)
+	}
+	if quotaErr.RetryAfter != 10*time.Millisecond {
+		t.Errorf("expected the RetryInfo delay of 10ms, got %s", quotaErr.RetryAfter)
+	}
+	if *calls != 3 {
+		t.Errorf("expected the call and two retries, got %d calls", *calls)
+	}
+}
+
+func TestCallGeminiDoesNotRetryOtherErrors(t *testing.T) {
+	client, calls := newTestClient(t, quotaPolicy{maxRetries: 3, maxWait: time.Second, backoff: time.Millisecond},
+		func(w http.ResponseWriter, n int32) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": 400, "message": "bad prompt", "status": "INVALID_ARGUMENT"}}`))
+		})
+
+	_, err := client.callGemini(context.Background(), "", "ping", 0.1)
+	if err == nil || errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected a plain API error, got %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("expected no retries, got %d calls", *calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for value, want := range map[string]time.Duration{
+		"30":                            30 * time.Second,
+		"Thu, 01 Jan 2026 12:00:45 GMT": 45 * time.Second,
+		"":                              0,
+		"soon":                          0,
+	} {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestNewQuotaPolicyDefaults(t *testing.T) {
+	policy := newQuotaPolicy(config.LLMConfig{QuotaMaxRetries: 2})
+	if policy.maxRetries != 2 || policy.maxWait != DefaultQuotaMaxWait || policy.backoff != DefaultQuotaBackoff {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+}
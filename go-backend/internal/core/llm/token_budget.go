@@ -0,0 +1,52 @@
+package llm
+
+import "unicode/utf8"
+
+// charsPerToken approximates Gemini's tokenizer on English and math text;
+// counting exactly would cost an API round trip per call
+const charsPerToken = 4
+
+// budgetHeadroomPercent of the token budget is left unused to absorb the
+// estimate's error
+const budgetHeadroomPercent = 10
+
+// estimateTokens returns roughly how many tokens text takes
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// tokenBudget sizes an explanation call so the answer has room to finish
+type tokenBudget struct {
+	// total caps prompt and answer together; zero disables the budget
+	total int
+	// answer is always reserved for the answer
+	answer int
+}
+
+// fitContext keeps the leading context chunks whose text fits beside the
+// fixed part of the prompt and the answer reserve. Chunks are ordered best
+// match first, so the lowest-scoring ones are dropped. maxOutput is the
+// room left for the answer, never less than the reserve.
+func (b tokenBudget) fitContext(fixedTokens int, chunks []string) (kept []string, maxOutput int) {
+	if b.total <= 0 {
+		return chunks, b.answer
+	}
+
+	usable := b.total - b.total*budgetHeadroomPercent/100
+	room := usable - fixedTokens - b.answer
+	used := 0
+	for _, chunk := range chunks {
+		cost := estimateTokens(chunk)
+		if used+cost > room {
+			break
+		}
+		used += cost
+		kept = append(kept, chunk)
+	}
+
+	maxOutput = usable - fixedTokens - used
+	if maxOutput < b.answer {
+		maxOutput = b.answer
+	}
+	return kept, maxOutput
+}
@@ -0,0 +1,134 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/core/config"
+	"github.com/mathprereq/internal/core/llm/prompts"
+	"go.uber.org/zap"
+	"google.golang.org/genai"
+)
+
+func TestFitContextKeepsEverythingWithinBudget(t *testing.T) {
+	chunks := []string{strings.Repeat("a", 400), strings.Repeat("b", 400)}
+
+	kept, maxOutput := tokenBudget{total: 10000, answer: 2000}.fitContext(500, chunks)
+	if len(kept) != 2 {
+		t.Fatalf("expected both chunks kept, got %d", len(kept))
+	}
+	// 9000 usable - 500 fixed - 200 context
+	if maxOutput != 8300 {
+		t.Errorf("expected the answer to get the 8300 tokens left, got %d", maxOutput)
+	}
+}
+
+func TestFitContextDropsLowestScoringChunks(t *testing.T) {
+	// 1000 tokens each, best match first
+	chunks := []string{strings.Repeat("a", 4000), strings.Repeat("b", 4000), strings.Repeat("c", 4000)}
+
+	// 9000 usable - 5000 fixed - 2000 answer leaves room for two chunks
+	kept, maxOutput := tokenBudget{total: 10000, answer: 2000}.fitContext(5000, chunks)
+	if len(kept) != 2 || kept[0] != chunks[0] || kept[1] != chunks[1] {
+		t.Fatalf("expected the two best chunks kept, got %d", len(kept))
+	}
+	if maxOutput != 2000 {
+		t.Errorf("expected the answer reserve, got %d", maxOutput)
+	}
+}
+
+func TestFitContextKeepsAnswerReserveWhenPromptIsTooLarge(t *testing.T) {
+	kept, maxOutput := tokenBudget{total: 10000, answer: 2000}.fitContext(20000, []string{"short"})
+	if len(kept) != 0 {
+		t.Errorf("expected all context dropped, got %d chunks", len(kept))
+	}
+	if maxOutput != 2000 {
+		t.Errorf("expected the answer reserve, got %d", maxOutput)
+	}
+}
+
+func TestFitContextDisabled(t *testing.T) {
+	chunks := []string{strings.Repeat("a", 1_000_000)}
+
+	kept, maxOutput := tokenBudget{answer: 2000}.fitContext(5000, chunks)
+	if len(kept) != 1 || maxOutput != 2000 {
+		t.Errorf("expected no trimming and the fixed answer length, got %d chunks and %d tokens", len(kept), maxOutput)
+	}
+}
+
+func TestGenerateExplanationTrimsLargeContextToBudget(t *testing.T) {
+	var body struct {
+		Contents []struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"contents"`
+		GenerationConfig struct {
+			MaxOutputTokens int `json:"maxOutputTokens"`
+		} `json:"generationConfig"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("unexpected request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(geminiOK))
+	}))
+	defer server.Close()
+
+	genaiClient, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      "test-key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := prompts.NewRegistry("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{
+		genaiClient: genaiClient,
+		logger:      zap.NewNop(),
+		prompts:     registry,
+		config:      config.LLMConfig{MaxTokens: 1000, TokenBudget: 8000},
+	}
+
+	// About 2500 tokens each; the 7200 usable tokens fit two beside the answer
+	chunks := []string{
+		"best " + strings.Repeat("x", 10000),
+		"good " + strings.Repeat("y", 10000),
+		"weak " + strings.Repeat("z", 10000),
+	}
+	if _, err := client.GenerateExplanation(context.Background(), ExplanationRequest{
+		Query:         "What is a derivative?",
+		ContextChunks: chunks,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var prompt strings.Builder
+	for _, content := range body.Contents {
+		for _, part := range content.Parts {
+			prompt.WriteString(part.Text)
+		}
+	}
+	if !strings.Contains(prompt.String(), "best ") || !strings.Contains(prompt.String(), "good ") {
+		t.Error("expected the two best chunks in the prompt")
+	}
+	if strings.Contains(prompt.String(), "weak ") {
+		t.Error("expected the lowest-scoring chunk trimmed from the prompt")
+	}
+
+	maxOutput := body.GenerationConfig.MaxOutputTokens
+	if maxOutput < 1000 || maxOutput+estimateTokens(prompt.String()) > 8000 {
+		t.Errorf("expected the answer sized to the budget's remainder, got %d tokens", maxOutput)
+	}
+}
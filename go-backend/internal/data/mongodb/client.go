@@ -22,6 +22,30 @@ type Config struct {
 	Password       string        `yaml:"password" env:"MONGODB_PASSWORD"`
 	ConnectTimeout time.Duration `yaml:"connect_timeout"`
 	QueryTimeout   time.Duration `yaml:"query_timeout"`
+	MaxPoolSize    uint64        `yaml:"max_pool_size" env:"MONGODB_MAX_POOL_SIZE"`
+	MinPoolSize    uint64        `yaml:"min_pool_size" env:"MONGODB_MIN_POOL_SIZE"`
+}
+
+// Connection pool defaults used when Config leaves the sizes unset
+const (
+	DefaultMaxPoolSize uint64 = 10
+	DefaultMinPoolSize uint64 = 2
+)
+
+// withPoolSize applies the configured connection pool bounds to opts
+func withPoolSize(opts *options.ClientOptions, config Config) *options.ClientOptions {
+	maxPool := config.MaxPoolSize
+	if maxPool == 0 {
+		maxPool = DefaultMaxPoolSize
+	}
+	minPool := config.MinPoolSize
+	if minPool == 0 {
+		minPool = DefaultMinPoolSize
+	}
+	if minPool > maxPool {
+		minPool = maxPool
+	}
+	return opts.SetMaxPoolSize(maxPool).SetMinPoolSize(minPool)
 }
 
 // Client wraps MongoDB client with additional functionality
@@ -67,14 +91,15 @@ func NewClient(config Config) (*Client, error) {
 	clientOptions = clientOptions.
 		SetConnectTimeout(config.ConnectTimeout).
 		SetServerSelectionTimeout(config.ConnectTimeout).
-		SetSocketTimeout(config.QueryTimeout).
-		SetMaxPoolSize(10).
-		SetMinPoolSize(2)
+		SetSocketTimeout(config.QueryTimeout)
+	clientOptions = withPoolSize(clientOptions, config)
 
 	logger.Info("Creating MongoDB client",
 		zap.String("uri", config.URI),
 		zap.String("database", config.Database),
-		zap.Duration("connect_timeout", config.ConnectTimeout))
+		zap.Duration("connect_timeout", config.ConnectTimeout),
+		zap.Uint64p("max_pool_size", clientOptions.MaxPoolSize),
+		zap.Uint64p("min_pool_size", clientOptions.MinPoolSize))
 
 	// Connect to MongoDB
 	mongoClient, err := mongo.Connect(context.Background(), clientOptions)
@@ -161,15 +186,16 @@ func NewClientWithAuthTest(config Config) (*Client, error) {
 	clientOptions = clientOptions.
 		SetConnectTimeout(config.ConnectTimeout).
 		SetServerSelectionTimeout(config.ConnectTimeout).
-		SetSocketTimeout(config.QueryTimeout).
-		SetMaxPoolSize(10).
-		SetMinPoolSize(2)
+		SetSocketTimeout(config.QueryTimeout)
+	clientOptions = withPoolSize(clientOptions, config)
 
 	// Create MongoDB client
 	logger.Info("Creating MongoDB client",
 		zap.String("uri", maskConnectionString(config.URI)),
 		zap.String("database", config.Database),
-		zap.Duration("connect_timeout", config.ConnectTimeout))
+		zap.Duration("connect_timeout", config.ConnectTimeout),
+		zap.Uint64p("max_pool_size", clientOptions.MaxPoolSize),
+		zap.Uint64p("min_pool_size", clientOptions.MinPoolSize))
 
 	mongoClient, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -0,0 +1,31 @@
+package mongodb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestWithPoolSize(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           Config
+		wantMax, wantMin uint64
+	}{
+		{"configured", Config{MaxPoolSize: 50, MinPoolSize: 5}, 50, 5},
+		{"defaults", Config{}, DefaultMaxPoolSize, DefaultMinPoolSize},
+		{"min capped at max", Config{MaxPoolSize: 1}, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := withPoolSize(options.Client(), tt.config)
+			if opts.MaxPoolSize == nil || *opts.MaxPoolSize != tt.wantMax {
+				t.Errorf("MaxPoolSize = %v, want %d", opts.MaxPoolSize, tt.wantMax)
+			}
+			if opts.MinPoolSize == nil || *opts.MinPoolSize != tt.wantMin {
+				t.Errorf("MinPoolSize = %v, want %d", opts.MinPoolSize, tt.wantMin)
+			}
+		})
+	}
+}
@@ -2,24 +2,38 @@ package neo4j
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/mathprereq/internal/core/config"
 	"github.com/mathprereq/pkg/logger"
 	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+	driverconfig "github.com/neo4j/neo4j-go-driver/v6/neo4j/config"
 	"go.uber.org/zap"
 )
 
+// ErrConceptNotFound is returned for a concept ID or name not in the graph
+var ErrConceptNotFound = errors.New("concept not found")
+
 type Client struct {
-	driver neo4j.Driver
-	logger *zap.Logger
+	driver        neo4j.Driver
+	logger        *zap.Logger
+	matchStrategy MatchStrategy
+	// matchCandidates caps the candidates a term is ranked against
+	matchCandidates int
+	// database is the database sessions run against; empty uses the user's
+	// home database
+	database string
 }
 
 type Concept struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Type        string `json:"type"`
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Category    string  `json:"category,omitempty"`
+	Difficulty  int     `json:"difficulty,omitempty"`
+	Type        string  `json:"type"`
+	Weight      float64 `json:"weight,omitempty"` // path weight of a prerequisite
 }
 
 type PrerequisitePathResult struct {
@@ -33,12 +47,28 @@ type ConceptDetailResult struct {
 	DetailedExplanation string    `json:"detailed_explanation"`
 }
 
+// withPoolSize sets the driver's connection pool size; zero keeps the driver default
+func withPoolSize(maxPoolSize int) func(*driverconfig.Config) {
+	return func(c *driverconfig.Config) {
+		if maxPoolSize > 0 {
+			c.MaxConnectionPoolSize = maxPoolSize
+		}
+	}
+}
+
+// sessionConfig returns the settings for a session in the given access
+// mode on the configured database
+func (c *Client) sessionConfig(mode neo4j.AccessMode) neo4j.SessionConfig {
+	return neo4j.SessionConfig{AccessMode: mode, DatabaseName: c.database}
+}
+
 func NewClient(cfg config.Neo4jConfig) (*Client, error) {
 	logger := logger.MustGetLogger()
 
 	driver, err := neo4j.NewDriver(
 		cfg.URI,
 		neo4j.BasicAuth(cfg.Username, cfg.Password, ""),
+		withPoolSize(cfg.MaxPoolSize),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Neo4j driver: %w", err)
@@ -50,55 +80,56 @@ func NewClient(cfg config.Neo4jConfig) (*Client, error) {
 		return nil, fmt.Errorf("failed to verify Neo4j connectivity: %w", err)
 	}
 
-	logger.Info("Connected to Neo4j", zap.String("uri", cfg.URI))
+	logger.Info("Connected to Neo4j",
+		zap.String("uri", cfg.URI),
+		zap.String("database", cfg.Database),
+		zap.Int("max_pool_size", cfg.MaxPoolSize))
+
+	client := &Client{
+		driver:          driver,
+		logger:          logger,
+		matchStrategy:   MatchStrategy(cfg.MatchStrategy),
+		matchCandidates: cfg.MatchCandidates,
+		database:        cfg.Database,
+	}
+	if client.matchStrategy == "" {
+		client.matchStrategy = MatchContains
+	}
+	if client.matchCandidates <= 0 {
+		client.matchCandidates = defaultMatchCandidates
+	}
 
-	return &Client{
-		driver: driver,
-		logger: logger,
-	}, nil
+	if client.matchStrategy == MatchFulltext {
+		if err := client.ensureFulltextIndex(ctx); err != nil {
+			logger.Warn("Failed to create concept full-text index", zap.Error(err))
+		}
+	}
+
+	logger.Info("Concept match strategy configured",
+		zap.String("strategy", string(client.matchStrategy)),
+		zap.Int("candidates", client.matchCandidates))
+
+	return client, nil
 }
 
 func (c *Client) FindConceptID(ctx context.Context, conceptName string) (*string, error) {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
 	defer session.Close(ctx)
 
-	query := `
-		MATCH (c:Concept)
-		WHERE toLower(c.name) CONTAINS toLower($conceptName) 
-		   OR toLower(c.id) = toLower($conceptName)
-		RETURN c.id as id
-		LIMIT 1
-	`
-	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
-		record, err := tx.Run(ctx, query, map[string]interface{}{
-			"conceptName": conceptName,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		if record.Next(ctx) {
-			id, _ := record.Record().Get("id")
-			idStr := toString(id)
-			return &idStr, nil
-		}
-
-		return nil, nil
-	})
-
+	match, err := c.matchConcept(ctx, session, conceptName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find concept ID: %w", err)
 	}
 
-	if result == nil {
+	if match == nil {
 		return nil, nil
 	}
 
-	return result.(*string), nil
+	return &match.ID, nil
 }
 
 func (c *Client) GetAllConcepts(ctx context.Context) ([]Concept, error) {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
 	defer session.Close(ctx)
 
 	query := `
@@ -145,7 +176,7 @@ func (c *Client) FindPrerequisitePath(ctx context.Context, targetConcepts []stri
 		return []Concept{}, nil
 	}
 
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
 	defer session.Close(ctx)
 
 	var targetIDs []string
@@ -165,15 +196,18 @@ func (c *Client) FindPrerequisitePath(ctx context.Context, targetConcepts []stri
 		return []Concept{}, nil
 	}
 
+	// A prerequisite's weight is its strongest chain of edge weights to a
+	// target; edges stored before weights existed count as fully essential
 	query := `
 		MATCH path = (prerequisite:Concept)-[:PREREQUISITE_FOR*]->(target:Concept)
 		WHERE target.id IN $targetIDs
-		WITH prerequisite, target, length(path) as pathLength
-		ORDER BY pathLength
-		WITH COLLECT(DISTINCT prerequisite) as prerequisites, COLLECT(DISTINCT target) as targets
-		UNWIND (prerequisites + targets) as concept
-		RETURN DISTINCT concept.id as id, concept.name as name, 
-		       concept.description as description,
+		WITH prerequisite, target,
+		     reduce(w = 1.0, r IN relationships(path) | w * coalesce(r.weight, 1.0)) as pathWeight
+		WITH COLLECT({concept: prerequisite, weight: pathWeight}) + COLLECT(DISTINCT {concept: target, weight: null}) as entries
+		UNWIND entries as entry
+		WITH entry.concept as concept, max(entry.weight) as weight
+		RETURN concept.id as id, concept.name as name,
+		       concept.description as description, concept.category as category, weight,
 		       CASE WHEN concept.id IN $targetIDs THEN 'target' ELSE 'prerequisite' END as type
 		ORDER BY 
 		  CASE WHEN concept.id IN $targetIDs THEN 1 ELSE 0 END,
@@ -194,14 +228,20 @@ func (c *Client) FindPrerequisitePath(ctx context.Context, targetConcepts []stri
 			id, _ := record.Get("id")
 			name, _ := record.Get("name")
 			description, _ := record.Get("description")
+			category, _ := record.Get("category")
 			conceptType, _ := record.Get("type")
+			weight, _ := record.Get("weight")
 
 			concept := Concept{
 				ID:          toString(id),
 				Name:        toString(name),
 				Description: toString(description),
+				Category:    toString(category),
 				Type:        toString(conceptType),
 			}
+			if concept.Type == "prerequisite" {
+				concept.Weight = toWeight(weight)
+			}
 			concepts = append(concepts, concept)
 		}
 		return concepts, nil
@@ -217,7 +257,7 @@ func (c *Client) FindPrerequisitePath(ctx context.Context, targetConcepts []stri
 }
 
 func (c *Client) GetConceptInfo(ctx context.Context, conceptID string) (*ConceptDetailResult, error) {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
 	defer session.Close(ctx)
 
 	// Modified query to handle both ID and name lookups
@@ -243,7 +283,7 @@ func (c *Client) GetConceptInfo(ctx context.Context, conceptID string) (*Concept
 			c.logger.Warn("Concept not found",
 				zap.String("search_term", conceptID),
 				zap.String("suggestion", "Try searching by concept ID (e.g., 'func_basics') or exact name"))
-			return nil, fmt.Errorf("concept not found: %s", conceptID)
+			return nil, fmt.Errorf("%w: %s", ErrConceptNotFound, conceptID)
 		}
 
 		rec := record.Record()
@@ -308,7 +348,7 @@ func (c *Client) GetConceptInfo(ctx context.Context, conceptID string) (*Concept
 }
 
 func (c *Client) GetStats(ctx context.Context) (map[string]interface{}, error) {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
 	defer session.Close(ctx)
 
 	query := `
@@ -353,7 +393,7 @@ func (c *Client) GetStats(ctx context.Context) (map[string]interface{}, error) {
 }
 
 func (c *Client) IsHealthy(ctx context.Context) bool {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
 	defer session.Close(ctx)
 
 	_, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
@@ -372,7 +412,7 @@ func (c *Client) Close() error {
 }
 
 func (c *Client) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeWrite))
 	defer session.Close(ctx)
 
 	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
@@ -419,3 +459,26 @@ func toString(value interface{}) string {
 	}
 	return fmt.Sprintf("%v", value)
 }
+
+// toWeight reads a prerequisite edge or path weight, treating a missing one
+// as fully essential
+func toWeight(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	}
+	return 1.0
+}
+
+// toInt reads a Cypher integer or float, returning 0 for anything else
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
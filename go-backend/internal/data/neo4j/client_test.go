@@ -0,0 +1,82 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+	driverconfig "github.com/neo4j/neo4j-go-driver/v6/neo4j/config"
+	"go.uber.org/zap"
+)
+
+func TestWithPoolSize(t *testing.T) {
+	cfg := &driverconfig.Config{MaxConnectionPoolSize: 100}
+	withPoolSize(25)(cfg)
+	if cfg.MaxConnectionPoolSize != 25 {
+		t.Errorf("MaxConnectionPoolSize = %d, want 25", cfg.MaxConnectionPoolSize)
+	}
+
+	withPoolSize(0)(cfg)
+	if cfg.MaxConnectionPoolSize != 25 {
+		t.Errorf("zero pool size should keep the existing value, got %d", cfg.MaxConnectionPoolSize)
+	}
+}
+
+// recordingDriver records the config of each session it opens; its sessions
+// fail every transaction
+type recordingDriver struct {
+	neo4j.Driver
+	sessions []neo4j.SessionConfig
+}
+
+func (d *recordingDriver) NewSession(ctx context.Context, config neo4j.SessionConfig) neo4j.Session {
+	d.sessions = append(d.sessions, config)
+	return failingSession{}
+}
+
+func (d *recordingDriver) ExecuteQueryBookmarkManager() neo4j.BookmarkManager { return nil }
+
+type failingSession struct {
+	neo4j.Session
+}
+
+func (failingSession) ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (any, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (failingSession) ExecuteWrite(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (any, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (failingSession) Close(ctx context.Context) error { return nil }
+
+func TestSessionsUseConfiguredDatabase(t *testing.T) {
+	for _, database := range []string{"", "mathgraph"} {
+		driver := &recordingDriver{}
+		client := &Client{driver: driver, logger: zap.NewNop(), database: database}
+
+		ctx := context.Background()
+		client.GetAllConcepts(ctx)
+		client.FindPrerequisitePaths(ctx, []string{"derivatives"})
+		client.MergeConcepts(ctx, "limit", "limits")
+		// neo4j.ExecuteQuery runs through session methods the fake can't
+		// implement; the session it opens is recorded before that
+		func() {
+			defer func() { recover() }()
+			client.ensureFulltextIndex(ctx)
+		}()
+
+		if len(driver.sessions) != 4 {
+			t.Fatalf("opened %d sessions, want 4", len(driver.sessions))
+		}
+		for _, session := range driver.sessions {
+			if session.DatabaseName != database {
+				t.Errorf("DatabaseName = %q, want %q", session.DatabaseName, database)
+			}
+		}
+		if driver.sessions[2].AccessMode != neo4j.AccessModeWrite {
+			t.Errorf("merge AccessMode = %v, want write", driver.sessions[2].AccessMode)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+)
+
+// ConceptListFilter narrows a concept listing. Zero values leave a field
+// unfiltered, and a zero Limit returns every match.
+type ConceptListFilter struct {
+	Category      string
+	MinDifficulty int
+	MaxDifficulty int
+	// Search matches a case-insensitive substring of the name or description
+	Search string
+	// HasResources keeps concepts whose ID is (true) or is not (false) in
+	// ResourceConceptIDs; resources are stored outside the graph
+	HasResources       *bool
+	ResourceConceptIDs []string
+	Limit              int
+	Offset             int
+}
+
+// conceptListWhere builds the WHERE clause and parameters for a filter
+func conceptListWhere(filter ConceptListFilter) (string, map[string]interface{}) {
+	var conditions []string
+	params := map[string]interface{}{}
+
+	if filter.Category != "" {
+		conditions = append(conditions, "toLower(c.category) = toLower($category)")
+		params["category"] = filter.Category
+	}
+	if filter.MinDifficulty > 0 {
+		conditions = append(conditions, "c.difficulty >= $minDifficulty")
+		params["minDifficulty"] = filter.MinDifficulty
+	}
+	if filter.MaxDifficulty > 0 {
+		conditions = append(conditions, "c.difficulty <= $maxDifficulty")
+		params["maxDifficulty"] = filter.MaxDifficulty
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		conditions = append(conditions,
+			"(toLower(c.name) CONTAINS toLower($search) OR toLower(coalesce(c.description, '')) CONTAINS toLower($search))")
+		params["search"] = search
+	}
+	if filter.HasResources != nil {
+		condition := "c.id IN $resourceConceptIds"
+		if !*filter.HasResources {
+			condition = "NOT " + condition
+		}
+		conditions = append(conditions, condition)
+		params["resourceConceptIds"] = nonNilStrings(filter.ResourceConceptIDs)
+	}
+
+	if len(conditions) == 0 {
+		return "", params
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), params
+}
+
+// nonNilStrings keeps an empty list from being sent as null, which IN
+// treats as unknown rather than empty
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// ListConcepts returns one page of concepts matching filter, ordered by
+// name, with the number of matches across all pages
+func (c *Client) ListConcepts(ctx context.Context, filter ConceptListFilter) ([]Concept, int, error) {
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
+	defer session.Close(ctx)
+
+	where, params := conceptListWhere(filter)
+	countQuery := fmt.Sprintf(`
+		MATCH (c:Concept)
+		%s
+		RETURN count(c) AS total
+	`, where)
+
+	pageQuery := fmt.Sprintf(`
+		MATCH (c:Concept)
+		%s
+		RETURN c.id AS id, c.name AS name, c.description AS description,
+		       c.category AS category, c.difficulty AS difficulty
+		ORDER BY c.name
+		SKIP $offset
+	`, where)
+	params["offset"] = filter.Offset
+	if filter.Limit > 0 {
+		pageQuery += "LIMIT $limit"
+		params["limit"] = filter.Limit
+	}
+
+	type page struct {
+		concepts []Concept
+		total    int
+	}
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		countResult, err := tx.Run(ctx, countQuery, params)
+		if err != nil {
+			return nil, err
+		}
+		countRecord, err := countResult.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		total, _ := countRecord.Get("total")
+
+		records, err := tx.Run(ctx, pageQuery, params)
+		if err != nil {
+			return nil, err
+		}
+
+		concepts := []Concept{}
+		for records.Next(ctx) {
+			record := records.Record()
+
+			id, _ := record.Get("id")
+			name, _ := record.Get("name")
+			description, _ := record.Get("description")
+			category, _ := record.Get("category")
+			difficulty, _ := record.Get("difficulty")
+
+			concepts = append(concepts, Concept{
+				ID:          toString(id),
+				Name:        toString(name),
+				Description: toString(description),
+				Category:    toString(category),
+				Difficulty:  toInt(difficulty),
+				Type:        "concept",
+			})
+		}
+		if err := records.Err(); err != nil {
+			return nil, err
+		}
+
+		return page{concepts: concepts, total: toInt(total)}, nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list concepts: %w", err)
+	}
+
+	listed := result.(page)
+	return listed.concepts, listed.total, nil
+}
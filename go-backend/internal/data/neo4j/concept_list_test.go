@@ -0,0 +1,83 @@
+package neo4j
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConceptListWhere(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name       string
+		filter     ConceptListFilter
+		conditions []string
+		params     []string
+	}{
+		{"no filter", ConceptListFilter{Limit: 20}, nil, nil},
+		{"category", ConceptListFilter{Category: "Calculus"},
+			[]string{"toLower(c.category) = toLower($category)"}, []string{"category"}},
+		{"difficulty range", ConceptListFilter{MinDifficulty: 2, MaxDifficulty: 5},
+			[]string{"c.difficulty >= $minDifficulty", "c.difficulty <= $maxDifficulty"},
+			[]string{"maxDifficulty", "minDifficulty"}},
+		{"min difficulty only", ConceptListFilter{MinDifficulty: 7},
+			[]string{"c.difficulty >= $minDifficulty"}, []string{"minDifficulty"}},
+		{"search", ConceptListFilter{Search: "  limit "},
+			[]string{"(toLower(c.name) CONTAINS toLower($search) OR toLower(coalesce(c.description, '')) CONTAINS toLower($search))"},
+			[]string{"search"}},
+		{"blank search is ignored", ConceptListFilter{Search: "   "}, nil, nil},
+		{"has resources", ConceptListFilter{HasResources: &yes, ResourceConceptIDs: []string{"limits"}},
+			[]string{"c.id IN $resourceConceptIds"}, []string{"resourceConceptIds"}},
+		{"without resources", ConceptListFilter{HasResources: &no},
+			[]string{"NOT c.id IN $resourceConceptIds"}, []string{"resourceConceptIds"}},
+		{"combined", ConceptListFilter{Category: "algebra", MaxDifficulty: 3, Search: "equation", HasResources: &yes},
+			[]string{
+				"toLower(c.category) = toLower($category)",
+				"c.difficulty <= $maxDifficulty",
+				"(toLower(c.name) CONTAINS toLower($search) OR toLower(coalesce(c.description, '')) CONTAINS toLower($search))",
+				"c.id IN $resourceConceptIds",
+			},
+			[]string{"category", "maxDifficulty", "resourceConceptIds", "search"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, params := conceptListWhere(tt.filter)
+
+			want := ""
+			if len(tt.conditions) > 0 {
+				want = "WHERE " + strings.Join(tt.conditions, " AND ")
+			}
+			if where != want {
+				t.Errorf("where = %q, want %q", where, want)
+			}
+
+			var names []string
+			for name := range params {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.params) {
+				t.Errorf("params = %v, want %v", names, tt.params)
+			}
+		})
+	}
+}
+
+func TestConceptListWhereSendsEmptyResourceList(t *testing.T) {
+	yes := true
+	_, params := conceptListWhere(ConceptListFilter{HasResources: &yes})
+
+	ids, ok := params["resourceConceptIds"].([]string)
+	if !ok || ids == nil {
+		t.Fatalf("expected an empty, non-nil list, got %#v", params["resourceConceptIds"])
+	}
+}
+
+func TestConceptListWhereTrimsSearch(t *testing.T) {
+	_, params := conceptListWhere(ConceptListFilter{Search: "  derivative  "})
+	if params["search"] != "derivative" {
+		t.Errorf("search = %q, want trimmed", params["search"])
+	}
+}
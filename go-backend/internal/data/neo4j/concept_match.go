@@ -0,0 +1,220 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+	"go.uber.org/zap"
+)
+
+// MatchStrategy controls how a concept name from a query is resolved to a node
+type MatchStrategy string
+
+const (
+	// MatchExact accepts only a case-insensitive name or ID match
+	MatchExact MatchStrategy = "exact"
+	// MatchPrefix also accepts names starting with the term
+	MatchPrefix MatchStrategy = "prefix"
+	// MatchContains also accepts names containing the term
+	MatchContains MatchStrategy = "contains"
+	// MatchFulltext ranks concepts with the Neo4j full-text index on name and description
+	MatchFulltext MatchStrategy = "fulltext"
+)
+
+const (
+	conceptFulltextIndex = "concept_text"
+	// defaultMatchCandidates is used when Neo4jConfig.MatchCandidates is unset
+	defaultMatchCandidates = 50
+)
+
+// conceptCandidatesQuery fetches the concepts the broadest non-fulltext
+// strategy could accept; selectConceptMatch then applies the strategy.
+// Candidates are ranked like selectConceptMatch ranks them before the
+// limit: exact matches, then prefix matches, then the rest, each by name
+// length and then name. A broad term such as "series" cannot push its
+// exact match out behind dozens of names that merely contain it.
+const conceptCandidatesQuery = `
+	MATCH (c:Concept)
+	WHERE toLower(c.name) CONTAINS toLower($conceptName)
+	   OR toLower(c.id) = toLower($conceptName)
+	WITH c, toLower(trim(c.name)) AS lowerName, toLower(trim($conceptName)) AS term
+	RETURN c.id as id, c.name as name
+	ORDER BY CASE
+	           WHEN lowerName = term OR toLower(c.id) = term THEN 0
+	           WHEN lowerName STARTS WITH term THEN 1
+	           ELSE 2
+	         END, size(c.name), lowerName, c.id
+	LIMIT $limit
+`
+
+const conceptFulltextQuery = `
+	CALL db.index.fulltext.queryNodes($index, $query) YIELD node, score
+	RETURN node.id as id, node.name as name
+	ORDER BY score DESC
+	LIMIT 1
+`
+
+// ensureFulltextIndex creates the full-text index used by MatchFulltext
+func (c *Client) ensureFulltextIndex(ctx context.Context) error {
+	query := fmt.Sprintf(
+		"CREATE FULLTEXT INDEX %s IF NOT EXISTS FOR (c:Concept) ON EACH [c.name, c.description]",
+		conceptFulltextIndex)
+	_, err := neo4j.ExecuteQuery(ctx, c.driver, query, nil, neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(c.database))
+	return err
+}
+
+// matchConcept resolves a concept name with the client's match strategy.
+// It returns nil when nothing matches.
+func (c *Client) matchConcept(ctx context.Context, session neo4j.SessionWithContext, conceptName string) (*Concept, error) {
+	if c.matchStrategy == MatchFulltext {
+		concept, err := c.fulltextMatch(ctx, session, conceptName)
+		if err == nil {
+			return concept, nil
+		}
+		c.logger.Warn("Full-text concept match failed, falling back to contains",
+			zap.String("concept", conceptName),
+			zap.Error(err))
+		return c.candidateMatch(ctx, session, conceptName, MatchContains)
+	}
+	return c.candidateMatch(ctx, session, conceptName, c.matchStrategy)
+}
+
+func (c *Client) candidateMatch(ctx context.Context, session neo4j.SessionWithContext, conceptName string, strategy MatchStrategy) (*Concept, error) {
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		records, err := tx.Run(ctx, conceptCandidatesQuery, map[string]interface{}{
+			"conceptName": conceptName,
+			"limit":       c.matchCandidates,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var candidates []Concept
+		for records.Next(ctx) {
+			id, _ := records.Record().Get("id")
+			name, _ := records.Record().Get("name")
+			candidates = append(candidates, Concept{ID: toString(id), Name: toString(name)})
+		}
+		return candidates, records.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := result.([]Concept)
+	match := selectConceptMatch(strategy, conceptName, candidates)
+	if match != nil {
+		c.logger.Debug("Concept matched",
+			zap.String("term", conceptName),
+			zap.String("strategy", string(strategy)),
+			zap.String("concept_id", match.ID),
+			zap.Int("candidates", len(candidates)))
+	}
+	return match, nil
+}
+
+func (c *Client) fulltextMatch(ctx context.Context, session neo4j.SessionWithContext, conceptName string) (*Concept, error) {
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		records, err := tx.Run(ctx, conceptFulltextQuery, map[string]interface{}{
+			"index": conceptFulltextIndex,
+			"query": escapeLucene(conceptName),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if !records.Next(ctx) {
+			return (*Concept)(nil), records.Err()
+		}
+		id, _ := records.Record().Get("id")
+		name, _ := records.Record().Get("name")
+		return &Concept{ID: toString(id), Name: toString(name)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	match := result.(*Concept)
+	if match != nil {
+		c.logger.Debug("Concept matched",
+			zap.String("term", conceptName),
+			zap.String("strategy", string(MatchFulltext)),
+			zap.String("concept_id", match.ID))
+	}
+	return match, nil
+}
+
+// selectConceptMatch picks the best candidate for term under strategy.
+// Exact name or ID matches always win, then names starting with the term,
+// then names containing it; ties go to the shortest name.
+func selectConceptMatch(strategy MatchStrategy, term string, candidates []Concept) *Concept {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return nil
+	}
+
+	const (
+		rankExact = iota
+		rankPrefix
+		rankContains
+		rankNone
+	)
+	rank := func(concept Concept) int {
+		name := strings.ToLower(strings.TrimSpace(concept.Name))
+		switch {
+		case name == term || strings.ToLower(concept.ID) == term:
+			return rankExact
+		case strings.HasPrefix(name, term):
+			return rankPrefix
+		case strings.Contains(name, term):
+			return rankContains
+		}
+		return rankNone
+	}
+
+	maxRank := rankExact
+	switch strategy {
+	case MatchPrefix:
+		maxRank = rankPrefix
+	case MatchContains, MatchFulltext:
+		maxRank = rankContains
+	}
+
+	ranked := make([]Concept, 0, len(candidates))
+	for _, candidate := range candidates {
+		if rank(candidate) <= maxRank {
+			ranked = append(ranked, candidate)
+		}
+	}
+	if len(ranked) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ri, rj := rank(ranked[i]), rank(ranked[j])
+		if ri != rj {
+			return ri < rj
+		}
+		if len(ranked[i].Name) != len(ranked[j].Name) {
+			return len(ranked[i].Name) < len(ranked[j].Name)
+		}
+		return strings.ToLower(ranked[i].Name) < strings.ToLower(ranked[j].Name)
+	})
+	return &ranked[0]
+}
+
+// escapeLucene escapes Lucene query syntax so user text is searched literally
+func escapeLucene(term string) string {
+	var b strings.Builder
+	for _, r := range term {
+		if strings.ContainsRune(`+-&|!(){}[]^"~*?:\/`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package neo4j
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSelectConceptMatchStrategies(t *testing.T) {
+	candidates := []Concept{
+		{ID: "integral_test", Name: "Integral Test"},
+		{ID: "definite_integral", Name: "Definite Integral"},
+		{ID: "integral", Name: "Integral"},
+		{ID: "integration_by_parts", Name: "Integration by Parts"},
+	}
+
+	tests := []struct {
+		strategy MatchStrategy
+		term     string
+		want     string
+	}{
+		{MatchExact, "integral", "integral"},
+		{MatchExact, "Integral Test", "integral_test"},
+		{MatchExact, "integr", ""},
+		{MatchPrefix, "integral", "integral"},
+		{MatchPrefix, "integr", "integral"},
+		{MatchPrefix, "definite", "definite_integral"},
+		{MatchPrefix, "parts", ""},
+		{MatchContains, "integral", "integral"},
+		{MatchContains, "parts", "integration_by_parts"},
+		{MatchContains, "  DEFINITE_INTEGRAL ", "definite_integral"},
+		{MatchContains, "", ""},
+	}
+
+	for _, tt := range tests {
+		got := selectConceptMatch(tt.strategy, tt.term, candidates)
+		gotID := ""
+		if got != nil {
+			gotID = got.ID
+		}
+		if gotID != tt.want {
+			t.Errorf("selectConceptMatch(%s, %q) = %q, want %q", tt.strategy, tt.term, gotID, tt.want)
+		}
+	}
+}
+
+func TestSelectConceptMatchLimitsBroadTerm(t *testing.T) {
+	// A broad term contained in many names resolves to one concept, the
+	// exact match, however many candidates contain it
+	var candidates []Concept
+	for i := 0; i < 40; i++ {
+		candidates = append(candidates, Concept{ID: fmt.Sprintf("series_%d", i), Name: fmt.Sprintf("Power Series %d", i)})
+	}
+	candidates = append(candidates,
+		Concept{ID: "series_convergence", Name: "Series Convergence"},
+		Concept{ID: "series", Name: "Series"})
+
+	if got := selectConceptMatch(MatchContains, "series", candidates); got == nil || got.ID != "series" {
+		t.Errorf("expected the exact match, got %+v", got)
+	}
+	if got := selectConceptMatch(MatchContains, "series conv", candidates); got == nil || got.ID != "series_convergence" {
+		t.Errorf("expected the prefix match over contains matches, got %+v", got)
+	}
+}
+
+func TestSelectConceptMatchBreaksTiesByName(t *testing.T) {
+	// Equally ranked names of equal length resolve by name, as the
+	// candidate query orders them, not by the order they arrive in
+	candidates := []Concept{
+		{ID: "limit_rules", Name: "Limit Rules"},
+		{ID: "limit_laws", Name: "Limit Laws!"},
+	}
+	if got := selectConceptMatch(MatchPrefix, "limit", candidates); got == nil || got.ID != "limit_laws" {
+		t.Errorf("expected the first name alphabetically, got %+v", got)
+	}
+}
+
+func TestConceptCandidatesQueryRanksBeforeLimit(t *testing.T) {
+	// Candidates must be ordered exact, prefix, contains before the cap is
+	// applied, or the best match can be cut off for a broad term
+	order := strings.Index(conceptCandidatesQuery, "ORDER BY")
+	limit := strings.Index(conceptCandidatesQuery, "LIMIT $limit")
+	if order < 0 || limit < 0 || order > limit {
+		t.Fatalf("expected ORDER BY before LIMIT in:\n%s", conceptCandidatesQuery)
+	}
+	for _, rank := range []string{"lowerName = term OR toLower(c.id) = term THEN 0", "lowerName STARTS WITH term THEN 1", "END, size(c.name), lowerName, c.id"} {
+		if !strings.Contains(conceptCandidatesQuery, rank) {
+			t.Errorf("expected the candidate query to rank %q", rank)
+		}
+	}
+}
+
+func TestEscapeLucene(t *testing.T) {
+	got := escapeLucene(`f(x) + g:x*`)
+	want := `f\(x\) \+ g\:x\*`
+	if got != want {
+		t.Errorf("escapeLucene() = %q, want %q", got, want)
+	}
+}
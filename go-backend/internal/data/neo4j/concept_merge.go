@@ -0,0 +1,237 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+	"go.uber.org/zap"
+)
+
+// Reasons an edge of a merged concept is not moved to the target
+const (
+	SkipSelfLoop  = "self_loop" // the edge joined source and target
+	SkipDuplicate = "duplicate" // target already has the relationship
+	SkipCycle     = "cycle"     // the edge would close a prerequisite cycle
+)
+
+// ConceptMerge reports how a duplicate concept was folded into another
+type ConceptMerge struct {
+	SourceID string
+	TargetID string
+	Moved    []NeighborhoodEdge
+	Skipped  []SkippedEdge
+}
+
+// SkippedEdge is an edge of the merged concept that was dropped
+type SkippedEdge struct {
+	Edge   NeighborhoodEdge
+	Reason string
+}
+
+const (
+	mergeConceptsExistQuery = `
+	MATCH (c:Concept)
+	WHERE c.id IN [$sourceID, $targetID]
+	RETURN count(DISTINCT c.id) AS found
+`
+	// Edges of both kinds touching the source, in stored direction
+	mergeSourceEdgesQuery = `
+	MATCH (s:Concept {id: $sourceID})-[r:PREREQUISITE_FOR|REQUIRES]-(:Concept)
+	RETURN startNode(r).id AS from, endNode(r).id AS to, type(r) AS type, r.weight AS weight
+`
+	mergeGraphEdgesQuery = `
+	MATCH (a:Concept)-[r:PREREQUISITE_FOR|REQUIRES]->(b:Concept)
+	RETURN a.id AS from, b.id AS to, type(r) AS type, r.weight AS weight
+`
+	// Relationship types cannot be parameters, so there is one query per type
+	mergePrerequisiteForQuery = `
+	MATCH (a:Concept {id: $from}), (b:Concept {id: $to})
+	MERGE (a)-[r:PREREQUISITE_FOR]->(b)
+	ON CREATE SET r.weight = $weight
+`
+	mergeRequiresQuery = `
+	MATCH (a:Concept {id: $from}), (b:Concept {id: $to})
+	MERGE (a)-[r:REQUIRES]->(b)
+	ON CREATE SET r.weight = $weight
+`
+	mergeDeleteSourceQuery = `
+	MATCH (s:Concept {id: $sourceID})
+	DETACH DELETE s
+`
+)
+
+// MergeConcepts folds the source concept into the target in one
+// transaction: source's prerequisite edges are rewired to target, except
+// ones that would duplicate an edge or close a cycle, and source is deleted.
+func (c *Client) MergeConcepts(ctx context.Context, sourceID, targetID string) (*ConceptMerge, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("cannot merge a concept into itself")
+	}
+
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeWrite))
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		params := map[string]interface{}{"sourceID": sourceID, "targetID": targetID}
+
+		records, err := tx.Run(ctx, mergeConceptsExistQuery, params)
+		if err != nil {
+			return nil, err
+		}
+		record, err := records.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if found, _ := record.Get("found"); toInt(found) != 2 {
+			return nil, fmt.Errorf("concept not found: %s or %s", sourceID, targetID)
+		}
+
+		sourceEdges, err := collectEdges(ctx, tx, mergeSourceEdgesQuery, params)
+		if err != nil {
+			return nil, err
+		}
+		graphEdges, err := collectEdges(ctx, tx, mergeGraphEdgesQuery, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		merge := planConceptMerge(sourceID, targetID, sourceEdges, graphEdges)
+		for _, edge := range merge.Moved {
+			query := mergePrerequisiteForQuery
+			if edge.Type == "REQUIRES" {
+				query = mergeRequiresQuery
+			}
+			if _, err := tx.Run(ctx, query, map[string]interface{}{"from": edge.From, "to": edge.To, "weight": edge.Weight}); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := tx.Run(ctx, mergeDeleteSourceQuery, params); err != nil {
+			return nil, err
+		}
+		return merge, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge concepts: %w", err)
+	}
+
+	merge := result.(*ConceptMerge)
+	c.logger.Info("Merged concepts",
+		zap.String("source_id", sourceID),
+		zap.String("target_id", targetID),
+		zap.Int("edges_moved", len(merge.Moved)),
+		zap.Int("edges_skipped", len(merge.Skipped)))
+
+	return merge, nil
+}
+
+func collectEdges(ctx context.Context, tx neo4j.ManagedTransaction, query string, params map[string]interface{}) ([]NeighborhoodEdge, error) {
+	records, err := tx.Run(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var edges []NeighborhoodEdge
+	for records.Next(ctx) {
+		rec := records.Record()
+		from, _ := rec.Get("from")
+		to, _ := rec.Get("to")
+		relType, _ := rec.Get("type")
+		weight, _ := rec.Get("weight")
+		edges = append(edges, NeighborhoodEdge{From: toString(from), To: toString(to), Type: toString(relType), Weight: toWeight(weight)})
+	}
+	return edges, records.Err()
+}
+
+// planConceptMerge decides which of source's edges move to target, keeping
+// their type, direction and weight. graphEdges are every prerequisite edge
+// in the graph. PREREQUISITE_FOR points from the prerequisite and REQUIRES
+// towards it, so both kinds are compared as prerequisite-before-concept
+// pairs.
+func planConceptMerge(sourceID, targetID string, sourceEdges, graphEdges []NeighborhoodEdge) *ConceptMerge {
+	merge := &ConceptMerge{SourceID: sourceID, TargetID: targetID}
+
+	// Prerequisite order of the graph without source
+	next := make(map[string][]string)
+	pairs := make(map[[2]string]bool)
+	for _, edge := range graphEdges {
+		if edge.From == sourceID || edge.To == sourceID {
+			continue
+		}
+		before, after := prerequisiteOrder(edge)
+		next[before] = append(next[before], after)
+		pairs[[2]string{before, after}] = true
+	}
+
+	edges := append([]NeighborhoodEdge(nil), sourceEdges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		if edges[i].To != edges[j].To {
+			return edges[i].To < edges[j].To
+		}
+		return edges[i].Type < edges[j].Type
+	})
+
+	for _, edge := range edges {
+		rewired := edge
+		if rewired.From == sourceID {
+			rewired.From = targetID
+		}
+		if rewired.To == sourceID {
+			rewired.To = targetID
+		}
+
+		before, after := prerequisiteOrder(rewired)
+		var reason string
+		switch {
+		case before == after:
+			reason = SkipSelfLoop
+		case pairs[[2]string{before, after}]:
+			reason = SkipDuplicate
+		case reaches(next, after, before):
+			reason = SkipCycle
+		}
+		if reason != "" {
+			merge.Skipped = append(merge.Skipped, SkippedEdge{Edge: edge, Reason: reason})
+			continue
+		}
+
+		next[before] = append(next[before], after)
+		pairs[[2]string{before, after}] = true
+		merge.Moved = append(merge.Moved, rewired)
+	}
+
+	return merge
+}
+
+// prerequisiteOrder returns the prerequisite and the concept that needs it
+func prerequisiteOrder(edge NeighborhoodEdge) (before, after string) {
+	if edge.Type == "REQUIRES" {
+		return edge.To, edge.From
+	}
+	return edge.From, edge.To
+}
+
+// reaches reports whether to can be reached from from along next
+func reaches(next map[string][]string, from, to string) bool {
+	seen := map[string]bool{from: true}
+	stack := []string{from}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == to {
+			return true
+		}
+		for _, n := range next[node] {
+			if !seen[n] {
+				seen[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package neo4j
+
+import (
+	"reflect"
+	"testing"
+)
+
+func requires(concept, prerequisite string) NeighborhoodEdge {
+	return NeighborhoodEdge{From: concept, To: prerequisite, Type: "REQUIRES"}
+}
+
+func TestPlanConceptMergeMovesEdges(t *testing.T) {
+	// "limit" is a duplicate of "limits"
+	sourceEdges := []NeighborhoodEdge{
+		edge("functions", "limit"),
+		edge("limit", "derivatives"),
+		requires("continuity", "limit"),
+	}
+	graphEdges := append([]NeighborhoodEdge{
+		edge("algebra", "functions"),
+		edge("algebra", "limits"),
+	}, sourceEdges...)
+
+	merge := planConceptMerge("limit", "limits", sourceEdges, graphEdges)
+
+	want := []NeighborhoodEdge{
+		requires("continuity", "limits"),
+		edge("functions", "limits"),
+		edge("limits", "derivatives"),
+	}
+	if !reflect.DeepEqual(merge.Moved, want) {
+		t.Errorf("moved %v, want %v", merge.Moved, want)
+	}
+	if len(merge.Skipped) != 0 {
+		t.Errorf("expected nothing skipped, got %v", merge.Skipped)
+	}
+}
+
+func TestPlanConceptMergeSkipsDuplicatesAndSelfLoops(t *testing.T) {
+	sourceEdges := []NeighborhoodEdge{
+		edge("functions", "limit"),
+		// Same prerequisite pair as target's REQUIRES edge below
+		edge("limit", "derivatives"),
+		edge("limit", "limits"),
+	}
+	graphEdges := append([]NeighborhoodEdge{
+		edge("functions", "limits"),
+		requires("derivatives", "limits"),
+	}, sourceEdges...)
+
+	merge := planConceptMerge("limit", "limits", sourceEdges, graphEdges)
+
+	if len(merge.Moved) != 0 {
+		t.Errorf("expected no edge moved, got %v", merge.Moved)
+	}
+	reasons := map[NeighborhoodEdge]string{}
+	for _, skipped := range merge.Skipped {
+		reasons[skipped.Edge] = skipped.Reason
+	}
+	want := map[NeighborhoodEdge]string{
+		edge("functions", "limit"):   SkipDuplicate,
+		edge("limit", "derivatives"): SkipDuplicate,
+		edge("limit", "limits"):      SkipSelfLoop,
+	}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Errorf("skipped %v, want %v", reasons, want)
+	}
+}
+
+func TestPlanConceptMergeAvoidsCycles(t *testing.T) {
+	// Target comes before sequences, so sequences cannot become its prerequisite
+	sourceEdges := []NeighborhoodEdge{
+		edge("sequences", "limit"),
+		edge("limit", "series"),
+	}
+	graphEdges := append([]NeighborhoodEdge{
+		requires("sequences", "limits"),
+		edge("series", "convergence"),
+	}, sourceEdges...)
+
+	merge := planConceptMerge("limit", "limits", sourceEdges, graphEdges)
+
+	if !reflect.DeepEqual(merge.Moved, []NeighborhoodEdge{edge("limits", "series")}) {
+		t.Errorf("moved %v", merge.Moved)
+	}
+	if len(merge.Skipped) != 1 || merge.Skipped[0].Edge != edge("sequences", "limit") || merge.Skipped[0].Reason != SkipCycle {
+		t.Errorf("expected the sequences edge skipped as a cycle, got %v", merge.Skipped)
+	}
+}
+
+func TestPlanConceptMergeChecksEdgesMovedEarlier(t *testing.T) {
+	// Each edge is fine alone, but together they would form
+	// limits -> calculus -> analysis -> limits
+	sourceEdges := []NeighborhoodEdge{
+		edge("analysis", "limit"),
+		edge("limit", "calculus"),
+	}
+	graphEdges := append([]NeighborhoodEdge{edge("calculus", "analysis")}, sourceEdges...)
+
+	merge := planConceptMerge("limit", "limits", sourceEdges, graphEdges)
+
+	if len(merge.Moved) != 1 || len(merge.Skipped) != 1 || merge.Skipped[0].Reason != SkipCycle {
+		t.Errorf("expected one edge moved and one skipped as a cycle, got moved %v, skipped %v", merge.Moved, merge.Skipped)
+	}
+}
+
+func TestPlanConceptMergeKeepsEdgeWeights(t *testing.T) {
+	helpful := edge("sequences", "limit")
+	helpful.Weight = 0.4
+	sourceEdges := []NeighborhoodEdge{helpful}
+
+	merge := planConceptMerge("limit", "limits", sourceEdges, sourceEdges)
+
+	if len(merge.Moved) != 1 || merge.Moved[0].To != "limits" || merge.Moved[0].Weight != 0.4 {
+		t.Errorf("expected the edge moved with its weight, got %v", merge.Moved)
+	}
+}
@@ -0,0 +1,123 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+	"go.uber.org/zap"
+)
+
+// prerequisitePathsQuery finds the prerequisites of every target in one
+// round trip. Each row is a target and one of its prerequisites, weighted
+// like FindPrerequisitePath; a target without prerequisites gets one row
+// with a null prerequisite.
+const prerequisitePathsQuery = `
+	UNWIND $targetIDs AS targetID
+	MATCH (target:Concept {id: targetID})
+	OPTIONAL MATCH path = (prerequisite:Concept)-[:PREREQUISITE_FOR*]->(target)
+	WITH target, prerequisite,
+	     max(reduce(w = 1.0, r IN relationships(path) | w * coalesce(r.weight, 1.0))) as weight
+	RETURN target.id as target_id, target.name as target_name,
+	       target.description as target_description, target.category as target_category,
+	       prerequisite.id as id, prerequisite.name as name,
+	       prerequisite.description as description, prerequisite.category as category, weight
+`
+
+// pathRow is one row of prerequisitePathsQuery; Prerequisite is nil for a
+// target without prerequisites
+type pathRow struct {
+	Target       Concept
+	Prerequisite *Concept
+}
+
+// FindPrerequisitePaths returns the prerequisite path of each target
+// concept ID, keyed by ID, with a single query. Each path lists the same
+// concepts in the same order as FindPrerequisitePath would for that target
+// alone. IDs that are not in the graph are left out.
+func (c *Client) FindPrerequisitePaths(ctx context.Context, targetIDs []string) (map[string][]Concept, error) {
+	if len(targetIDs) == 0 {
+		return map[string][]Concept{}, nil
+	}
+
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		records, err := tx.Run(ctx, prerequisitePathsQuery, map[string]interface{}{
+			"targetIDs": targetIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var rows []pathRow
+		for records.Next(ctx) {
+			record := records.Record()
+			get := func(key string) string {
+				value, _ := record.Get(key)
+				return toString(value)
+			}
+
+			row := pathRow{Target: Concept{
+				ID:          get("target_id"),
+				Name:        get("target_name"),
+				Description: get("target_description"),
+				Category:    get("target_category"),
+			}}
+			if id, _ := record.Get("id"); id != nil {
+				weight, _ := record.Get("weight")
+				row.Prerequisite = &Concept{
+					ID:          toString(id),
+					Name:        get("name"),
+					Description: get("description"),
+					Category:    get("category"),
+					Weight:      toWeight(weight),
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows, records.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prerequisite paths: %w", err)
+	}
+
+	paths := groupPathRows(result.([]pathRow))
+	c.logger.Info("Found learning paths",
+		zap.Int("targets", len(targetIDs)),
+		zap.Int("found", len(paths)))
+	return paths, nil
+}
+
+// groupPathRows builds each target's path from its rows: prerequisites by
+// name, then the target, as FindPrerequisitePath orders them. A target
+// reached through a cycle is not listed as its own prerequisite.
+func groupPathRows(rows []pathRow) map[string][]Concept {
+	targets := make(map[string]Concept)
+	prerequisites := make(map[string][]Concept)
+	for _, row := range rows {
+		targets[row.Target.ID] = row.Target
+		if row.Prerequisite == nil || row.Prerequisite.ID == row.Target.ID {
+			continue
+		}
+		prerequisite := *row.Prerequisite
+		prerequisite.Type = "prerequisite"
+		prerequisites[row.Target.ID] = append(prerequisites[row.Target.ID], prerequisite)
+	}
+
+	paths := make(map[string][]Concept, len(targets))
+	for id, target := range targets {
+		path := prerequisites[id]
+		sort.SliceStable(path, func(i, j int) bool {
+			if path[i].Name != path[j].Name {
+				return path[i].Name < path[j].Name
+			}
+			return path[i].ID < path[j].ID
+		})
+		target.Type = "target"
+		paths[id] = append(path, target)
+	}
+	return paths
+}
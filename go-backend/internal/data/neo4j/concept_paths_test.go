@@ -0,0 +1,76 @@
+package neo4j
+
+import (
+	"reflect"
+	"testing"
+)
+
+// prerequisiteRow is a row of a target and one prerequisite with a path weight
+func prerequisiteRow(target, prerequisite string, weight float64) pathRow {
+	p := concept(prerequisite)
+	p.Weight = weight
+	return pathRow{Target: concept(target), Prerequisite: &p}
+}
+
+func TestGroupPathRowsMatchesIndividualPaths(t *testing.T) {
+	// algebra -> functions -> limits -> derivatives, limits -> continuity
+	byTarget := map[string][]pathRow{
+		"derivatives": {
+			prerequisiteRow("derivatives", "limits", 1),
+			prerequisiteRow("derivatives", "algebra", 0.4),
+			prerequisiteRow("derivatives", "functions", 0.8),
+		},
+		"continuity": {
+			prerequisiteRow("continuity", "limits", 0.6),
+			prerequisiteRow("continuity", "functions", 0.6),
+			prerequisiteRow("continuity", "algebra", 0.3),
+		},
+		"algebra": {{Target: concept("algebra")}},
+	}
+
+	// The batched query returns the rows of all targets interleaved
+	var batched []pathRow
+	for i := 0; i < 3; i++ {
+		for _, target := range []string{"derivatives", "continuity", "algebra"} {
+			if rows := byTarget[target]; i < len(rows) {
+				batched = append(batched, rows[i])
+			}
+		}
+	}
+
+	paths := groupPathRows(batched)
+	if len(paths) != 3 {
+		t.Fatalf("expected a path per target, got %v", paths)
+	}
+	for target, rows := range byTarget {
+		individual := groupPathRows(rows)[target]
+		if !reflect.DeepEqual(paths[target], individual) {
+			t.Errorf("%s: batched path %+v differs from individual %+v", target, paths[target], individual)
+		}
+	}
+
+	var order []string
+	for _, c := range paths["derivatives"] {
+		order = append(order, c.ID+"/"+c.Type)
+	}
+	want := []string{"algebra/prerequisite", "functions/prerequisite", "limits/prerequisite", "derivatives/target"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("expected prerequisites by name then the target, got %v", order)
+	}
+	if paths["continuity"][2].Weight != 0.6 || paths["derivatives"][0].Weight != 0.4 {
+		t.Error("expected each target to keep its own path weights")
+	}
+	if len(paths["algebra"]) != 1 || paths["algebra"][0].Type != "target" {
+		t.Errorf("expected a target without prerequisites to be its own path, got %+v", paths["algebra"])
+	}
+}
+
+func TestGroupPathRowsSkipsCycleBackToTarget(t *testing.T) {
+	paths := groupPathRows([]pathRow{
+		prerequisiteRow("limits", "limits", 1),
+		prerequisiteRow("limits", "functions", 1),
+	})
+	if len(paths["limits"]) != 2 || paths["limits"][1].ID != "limits" || paths["limits"][1].Type != "target" {
+		t.Errorf("expected the target listed once, got %+v", paths["limits"])
+	}
+}
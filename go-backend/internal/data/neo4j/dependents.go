@@ -0,0 +1,59 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+)
+
+// dependentConceptsQuery finds the concepts a concept is a direct
+// prerequisite for, most essential first
+const dependentConceptsQuery = `
+	MATCH (:Concept {id: $conceptID})-[r:PREREQUISITE_FOR]->(next:Concept)
+	WITH next, max(coalesce(r.weight, 1.0)) as weight
+	RETURN next.id as id, next.name as name, next.description as description,
+	       next.category as category, weight
+	ORDER BY weight DESC, next.name
+	LIMIT $limit
+`
+
+// FindDependentConcepts returns up to limit concepts that list the concept
+// as a direct prerequisite, with the weight of that edge
+func (c *Client) FindDependentConcepts(ctx context.Context, conceptID string, limit int) ([]Concept, error) {
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		records, err := tx.Run(ctx, dependentConceptsQuery, map[string]interface{}{
+			"conceptID": conceptID,
+			"limit":     limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		concepts := []Concept{}
+		for records.Next(ctx) {
+			record := records.Record()
+			get := func(key string) string {
+				value, _ := record.Get(key)
+				return toString(value)
+			}
+			weight, _ := record.Get("weight")
+			concepts = append(concepts, Concept{
+				ID:          get("id"),
+				Name:        get("name"),
+				Description: get("description"),
+				Category:    get("category"),
+				Weight:      toWeight(weight),
+			})
+		}
+		return concepts, records.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependent concepts: %w", err)
+	}
+
+	return result.([]Concept), nil
+}
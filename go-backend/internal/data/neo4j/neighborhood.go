@@ -0,0 +1,307 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+	"go.uber.org/zap"
+)
+
+// Neighborhood directions relative to the center concept
+const (
+	DirectionCenter     = "center"
+	DirectionUpstream   = "upstream"   // prerequisites of the center
+	DirectionDownstream = "downstream" // concepts that build on the center
+)
+
+// NeighborhoodNode is a concept within N hops of the center concept
+type NeighborhoodNode struct {
+	Concept
+	Direction string `json:"direction"`
+	Distance  int    `json:"distance"`
+}
+
+// NeighborhoodEdge is a directed relationship between two neighborhood nodes
+type NeighborhoodEdge struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Type   string  `json:"type"`
+	Weight float64 `json:"weight"`
+}
+
+// Neighborhood is the subgraph around a concept in both directions
+type Neighborhood struct {
+	CenterID  string             `json:"center_id"`
+	Depth     int                `json:"depth"`
+	Nodes     []NeighborhoodNode `json:"nodes"`
+	Edges     []NeighborhoodEdge `json:"edges"`
+	Truncated bool               `json:"truncated"`
+}
+
+// graphPath is a path returned by the neighborhood query, ordered from
+// its start node to its end node
+type graphPath struct {
+	Nodes []Concept
+	Edges []NeighborhoodEdge
+}
+
+// The variable-length bound cannot be a parameter, so depth is formatted in
+// after validation
+const neighborhoodQuery = `
+	MATCH (c:Concept)
+	WHERE c.id = $conceptId OR c.name = $conceptId
+	WITH c LIMIT 1
+	OPTIONAL MATCH up = (:Concept)-[:PREREQUISITE_FOR*1..%[1]d]->(c)
+	WITH c, collect(up) AS upPaths
+	OPTIONAL MATCH down = (c)-[:PREREQUISITE_FOR*1..%[1]d]->(:Concept)
+	WITH c, upPaths, collect(down) AS downPaths
+	RETURN {id: c.id, name: c.name, description: c.description} AS center,
+	       [p IN upPaths | {
+	           nodes: [n IN nodes(p) | {id: n.id, name: n.name, description: n.description}],
+	           edges: [r IN relationships(p) | {from: startNode(r).id, to: endNode(r).id, type: type(r), weight: r.weight}]
+	       }] AS upstream,
+	       [p IN downPaths | {
+	           nodes: [n IN nodes(p) | {id: n.id, name: n.name, description: n.description}],
+	           edges: [r IN relationships(p) | {from: startNode(r).id, to: endNode(r).id, type: type(r), weight: r.weight}]
+	       }] AS downstream
+`
+
+// GetConceptNeighborhood returns concepts and edges within depth hops of a
+// concept, both upstream and downstream, keeping at most maxNodes nodes
+func (c *Client) GetConceptNeighborhood(ctx context.Context, conceptID string, depth, maxNodes int) (*Neighborhood, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("depth must be at least 1, got %d", depth)
+	}
+
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		records, err := tx.Run(ctx, fmt.Sprintf(neighborhoodQuery, depth), map[string]interface{}{
+			"conceptId": conceptID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if !records.Next(ctx) {
+			return nil, fmt.Errorf("concept not found: %s", conceptID)
+		}
+
+		rec := records.Record()
+		centerRaw, _ := rec.Get("center")
+		upstreamRaw, _ := rec.Get("upstream")
+		downstreamRaw, _ := rec.Get("downstream")
+
+		center := toConcept(centerRaw)
+		return buildNeighborhood(center, toGraphPaths(upstreamRaw), toGraphPaths(downstreamRaw), depth, maxNodes), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get concept neighborhood: %w", err)
+	}
+
+	neighborhood := result.(*Neighborhood)
+	c.logger.Debug("Concept neighborhood loaded",
+		zap.String("concept_id", conceptID),
+		zap.Int("depth", depth),
+		zap.Int("nodes", len(neighborhood.Nodes)),
+		zap.Int("edges", len(neighborhood.Edges)),
+		zap.Bool("truncated", neighborhood.Truncated))
+
+	return neighborhood, nil
+}
+
+// buildNeighborhood merges upstream paths (ending at the center) and
+// downstream paths (starting at it) into deduplicated nodes and edges. Nodes
+// keep their shortest distance; when over maxNodes the farthest are dropped
+// along with their edges.
+func buildNeighborhood(center Concept, upstream, downstream []graphPath, depth, maxNodes int) *Neighborhood {
+	center.Type = "target"
+	nodes := map[string]*NeighborhoodNode{
+		center.ID: {Concept: center, Direction: DirectionCenter},
+	}
+	var edges []NeighborhoodEdge
+	seenEdges := make(map[NeighborhoodEdge]bool)
+
+	addNode := func(concept Concept, direction string, distance int) {
+		if existing, ok := nodes[concept.ID]; ok {
+			if distance < existing.Distance {
+				existing.Distance = distance
+			}
+			return
+		}
+		if direction == DirectionUpstream {
+			concept.Type = "prerequisite"
+		} else {
+			concept.Type = "next_concept"
+		}
+		nodes[concept.ID] = &NeighborhoodNode{Concept: concept, Direction: direction, Distance: distance}
+	}
+	addEdges := func(pathEdges []NeighborhoodEdge) {
+		for _, edge := range pathEdges {
+			if !seenEdges[edge] {
+				seenEdges[edge] = true
+				edges = append(edges, edge)
+			}
+		}
+	}
+
+	for _, path := range upstream {
+		last := len(path.Nodes) - 1
+		for i, concept := range path.Nodes {
+			addNode(concept, DirectionUpstream, last-i)
+		}
+		addEdges(path.Edges)
+	}
+	for _, path := range downstream {
+		for i, concept := range path.Nodes {
+			addNode(concept, DirectionDownstream, i)
+		}
+		addEdges(path.Edges)
+	}
+
+	ordered := make([]NeighborhoodNode, 0, len(nodes))
+	for _, node := range nodes {
+		ordered = append(ordered, *node)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].Distance != ordered[j].Distance {
+			return ordered[i].Distance < ordered[j].Distance
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	neighborhood := &Neighborhood{CenterID: center.ID, Depth: depth, Nodes: ordered, Edges: []NeighborhoodEdge{}}
+	if maxNodes > 0 && len(ordered) > maxNodes {
+		neighborhood.Nodes = ordered[:maxNodes]
+		neighborhood.Truncated = true
+	}
+
+	kept := make(map[string]bool, len(neighborhood.Nodes))
+	for _, node := range neighborhood.Nodes {
+		kept[node.ID] = true
+	}
+	for _, edge := range edges {
+		if kept[edge.From] && kept[edge.To] {
+			neighborhood.Edges = append(neighborhood.Edges, edge)
+		}
+	}
+
+	return neighborhood
+}
+
+func toConcept(raw interface{}) Concept {
+	m, _ := raw.(map[string]interface{})
+	return Concept{
+		ID:          toString(m["id"]),
+		Name:        toString(m["name"]),
+		Description: toString(m["description"]),
+	}
+}
+
+func toGraphPaths(raw interface{}) []graphPath {
+	list, _ := raw.([]interface{})
+	paths := make([]graphPath, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var path graphPath
+		nodes, _ := m["nodes"].([]interface{})
+		for _, node := range nodes {
+			path.Nodes = append(path.Nodes, toConcept(node))
+		}
+		edges, _ := m["edges"].([]interface{})
+		for _, edge := range edges {
+			e, _ := edge.(map[string]interface{})
+			path.Edges = append(path.Edges, NeighborhoodEdge{
+				From:   toString(e["from"]),
+				To:     toString(e["to"]),
+				Type:   toString(e["type"]),
+				Weight: toWeight(e["weight"]),
+			})
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+const edgesAmongQuery = `
+	MATCH (a:Concept)-[r:PREREQUISITE_FOR]->(b:Concept)
+	WHERE a.id IN $ids AND b.id IN $ids
+	RETURN a.id AS from, b.id AS to, type(r) AS type, r.weight AS weight
+`
+
+// GetEdgesAmong returns the prerequisite relationships whose endpoints are
+// both in conceptIDs
+func (c *Client) GetEdgesAmong(ctx context.Context, conceptIDs []string) ([]NeighborhoodEdge, error) {
+	if len(conceptIDs) == 0 {
+		return []NeighborhoodEdge{}, nil
+	}
+
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		records, err := tx.Run(ctx, edgesAmongQuery, map[string]interface{}{
+			"ids": conceptIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		edges := []NeighborhoodEdge{}
+		for records.Next(ctx) {
+			rec := records.Record()
+			from, _ := rec.Get("from")
+			to, _ := rec.Get("to")
+			relType, _ := rec.Get("type")
+			weight, _ := rec.Get("weight")
+			edges = append(edges, NeighborhoodEdge{From: toString(from), To: toString(to), Type: toString(relType), Weight: toWeight(weight)})
+		}
+		return edges, records.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edges among concepts: %w", err)
+	}
+
+	return result.([]NeighborhoodEdge), nil
+}
+
+const prerequisiteEdgesQuery = `
+	MATCH (a:Concept)-[r:PREREQUISITE_FOR]->(b:Concept)
+	RETURN a.id AS from, b.id AS to, type(r) AS type, r.weight AS weight
+`
+
+// GetPrerequisiteEdges returns every prerequisite relationship in the graph
+func (c *Client) GetPrerequisiteEdges(ctx context.Context) ([]NeighborhoodEdge, error) {
+	session := c.driver.NewSession(ctx, c.sessionConfig(neo4j.AccessModeRead))
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		records, err := tx.Run(ctx, prerequisiteEdgesQuery, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		edges := []NeighborhoodEdge{}
+		for records.Next(ctx) {
+			rec := records.Record()
+			from, _ := rec.Get("from")
+			to, _ := rec.Get("to")
+			relType, _ := rec.Get("type")
+			weight, _ := rec.Get("weight")
+			edges = append(edges, NeighborhoodEdge{From: toString(from), To: toString(to), Type: toString(relType), Weight: toWeight(weight)})
+		}
+		return edges, records.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prerequisite edges: %w", err)
+	}
+
+	return result.([]NeighborhoodEdge), nil
+}
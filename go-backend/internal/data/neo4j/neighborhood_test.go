@@ -0,0 +1,124 @@
+package neo4j
+
+import "testing"
+
+func concept(id string) Concept {
+	return Concept{ID: id, Name: id}
+}
+
+func edge(from, to string) NeighborhoodEdge {
+	return NeighborhoodEdge{From: from, To: to, Type: "PREREQUISITE_FOR"}
+}
+
+// path builds a chain of concepts linked by PREREQUISITE_FOR edges
+func path(ids ...string) graphPath {
+	var p graphPath
+	for i, id := range ids {
+		p.Nodes = append(p.Nodes, concept(id))
+		if i > 0 {
+			p.Edges = append(p.Edges, edge(ids[i-1], id))
+		}
+	}
+	return p
+}
+
+func TestBuildNeighborhoodIncludesBothDirections(t *testing.T) {
+	// algebra -> functions -> limits -> derivatives -> integrals
+	upstream := []graphPath{
+		path("functions", "limits"),
+		path("algebra", "functions", "limits"),
+	}
+	downstream := []graphPath{
+		path("limits", "derivatives"),
+		path("limits", "derivatives", "integrals"),
+	}
+
+	n := buildNeighborhood(concept("limits"), upstream, downstream, 2, 0)
+
+	want := map[string]struct {
+		direction string
+		distance  int
+	}{
+		"limits":      {DirectionCenter, 0},
+		"functions":   {DirectionUpstream, 1},
+		"derivatives": {DirectionDownstream, 1},
+		"algebra":     {DirectionUpstream, 2},
+		"integrals":   {DirectionDownstream, 2},
+	}
+	if len(n.Nodes) != len(want) {
+		t.Fatalf("expected %d nodes, got %+v", len(want), n.Nodes)
+	}
+	for _, node := range n.Nodes {
+		w, ok := want[node.ID]
+		if !ok {
+			t.Errorf("unexpected node %s", node.ID)
+			continue
+		}
+		if node.Direction != w.direction || node.Distance != w.distance {
+			t.Errorf("node %s = %s/%d, want %s/%d", node.ID, node.Direction, node.Distance, w.direction, w.distance)
+		}
+	}
+
+	if len(n.Edges) != 4 {
+		t.Errorf("expected 4 deduplicated edges, got %+v", n.Edges)
+	}
+	if n.Truncated {
+		t.Error("did not expect truncation")
+	}
+}
+
+func TestBuildNeighborhoodCapsNodesByDistance(t *testing.T) {
+	upstream := []graphPath{path("algebra", "functions", "limits")}
+	downstream := []graphPath{path("limits", "derivatives", "integrals")}
+
+	n := buildNeighborhood(concept("limits"), upstream, downstream, 2, 3)
+
+	if !n.Truncated {
+		t.Error("expected truncation")
+	}
+	if len(n.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(n.Nodes))
+	}
+	for _, node := range n.Nodes {
+		if node.Distance > 1 {
+			t.Errorf("expected the farthest nodes to be dropped, kept %s at %d", node.ID, node.Distance)
+		}
+	}
+	// Only edges between kept nodes survive
+	for _, e := range n.Edges {
+		if e.From == "algebra" || e.To == "integrals" {
+			t.Errorf("edge %+v references a dropped node", e)
+		}
+	}
+	if len(n.Edges) != 2 {
+		t.Errorf("expected 2 edges, got %+v", n.Edges)
+	}
+}
+
+func TestToGraphPathsReadsEdgeWeights(t *testing.T) {
+	raw := []interface{}{
+		map[string]interface{}{
+			"nodes": []interface{}{
+				map[string]interface{}{"id": "sequences"},
+				map[string]interface{}{"id": "limits"},
+				map[string]interface{}{"id": "derivatives"},
+			},
+			"edges": []interface{}{
+				map[string]interface{}{"from": "sequences", "to": "limits", "type": "PREREQUISITE_FOR", "weight": 0.25},
+				// Stored before edges had weights
+				map[string]interface{}{"from": "limits", "to": "derivatives", "type": "PREREQUISITE_FOR"},
+			},
+		},
+	}
+
+	paths := toGraphPaths(raw)
+	if len(paths) != 1 || len(paths[0].Edges) != 2 {
+		t.Fatalf("expected one path with two edges, got %+v", paths)
+	}
+	if w := paths[0].Edges[0].Weight; w != 0.25 {
+		t.Errorf("expected the stored weight 0.25, got %v", w)
+	}
+	if w := paths[0].Edges[1].Weight; w != 1 {
+		t.Errorf("expected an unweighted edge to default to 1, got %v", w)
+	}
+}
@@ -3,6 +3,7 @@ package scraper
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -46,6 +47,20 @@ type EducationalResource struct {
 	PublishedAt     *time.Time         `bson:"published_at,omitempty" json:"published_at,omitempty"`
 	Tags            []string           `bson:"tags" json:"tags"`
 	IsVerified      bool               `bson:"is_verified" json:"is_verified"`
+
+	// Pinned resources are vetted by a curator and listed before all others,
+	// lowest PinOrder first. Both are omitted when unset so rescraping a
+	// resource does not unpin it.
+	Pinned   bool `bson:"pinned,omitempty" json:"pinned"`
+	PinOrder int  `bson:"pin_order,omitempty" json:"pin_order,omitempty"`
+
+	// MetadataCheckedAt is when video metadata was last fetched, so the
+	// backfill skips videos already enriched
+	MetadataCheckedAt *time.Time `bson:"metadata_checked_at,omitempty" json:"-"`
+
+	// RelevantConcepts lists the requested concepts this resource was found
+	// for when resources for several concepts are merged; it is not stored
+	RelevantConcepts []string `bson:"-" json:"relevant_concepts,omitempty"`
 }
 
 // ScraperConfig holds configuration for the scraper
@@ -74,8 +89,18 @@ type EducationalWebScraper struct {
 
 	// Educational domains to target
 	educationalDomains []string
+
+	// Video metadata endpoints, the YouTube ones when empty
+	oembedURL string
+	watchURL  string
+
+	// searchers replaces the platform searches when set, for tests
+	searchers []searchFunc
 }
 
+// searchFunc finds resources for a concept on one platform
+type searchFunc func(ctx context.Context, conceptID, conceptName string) ([]EducationalResource, error)
+
 // YouTubeVideoData represents YouTube video information
 type YouTubeVideoData struct {
 	VideoID       string `json:"videoId"`
@@ -189,6 +214,15 @@ func createIndexes(ctx context.Context, collection *mongo.Collection) error {
 				{"quality_score", -1},
 			},
 		},
+		{
+			// Serves resourceOrder within a concept
+			Keys: bson.D{
+				{"concept_id", 1},
+				{"pinned", -1},
+				{"pin_order", 1},
+				{"quality_score", -1},
+			},
+		},
 	}
 
 	_, err := collection.Indexes().CreateMany(ctx, indexes)
@@ -214,8 +248,11 @@ func (s *EducationalWebScraper) Close(ctx context.Context) error {
 func (s *EducationalWebScraper) ScrapeResourcesForConcepts(ctx context.Context, conceptNames []string) error {
 	s.logger.Info("Starting resource scraping", zap.Int("concepts", len(conceptNames)))
 
-	// Process concepts in batches
+	// Process concepts in batches; a failed batch does not stop the rest but
+	// is reported so queued scrapes can be retried. Concepts that succeeded
+	// are skipped on retry as recently scraped.
 	batchSize := 3
+	var failed []error
 	for i := 0; i < len(conceptNames); i += batchSize {
 		end := i + batchSize
 		if end > len(conceptNames) {
@@ -229,6 +266,7 @@ func (s *EducationalWebScraper) ScrapeResourcesForConcepts(ctx context.Context,
 
 		if err := s.processBatch(ctx, batch); err != nil {
 			s.logger.Error("Batch processing failed", zap.Error(err))
+			failed = append(failed, err)
 			continue
 		}
 
@@ -236,6 +274,13 @@ func (s *EducationalWebScraper) ScrapeResourcesForConcepts(ctx context.Context,
 		time.Sleep(2 * time.Second)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d batches failed: %w", len(failed), (len(conceptNames)+batchSize-1)/batchSize, errors.Join(failed...))
+	}
+
 	s.logger.Info("Resource scraping completed", zap.Int("total_concepts", len(conceptNames)))
 	return nil
 }
@@ -267,17 +312,65 @@ func (s *EducationalWebScraper) scrapeResourcesForConcept(ctx context.Context, c
 		return nil
 	}
 
+	found, qualityResources, err := s.collectResources(ctx, conceptID, conceptName)
+	if err != nil {
+		return err
+	}
+
+	// Store in MongoDB
+	if len(qualityResources) > 0 {
+		if err := s.storeResources(ctx, qualityResources); err != nil {
+			s.logger.Error("Failed to store resources", zap.Error(err))
+			return err
+		}
+	}
+
+	s.logger.Info("Successfully scraped concept",
+		zap.String("concept", conceptName),
+		zap.Int("total_found", found),
+		zap.Int("quality_stored", len(qualityResources)))
+
+	return nil
+}
+
+// PreviewResourcesForConcepts runs the same searches, scoring and filtering
+// as ScrapeResourcesForConcepts and returns the resources it would store,
+// best first within each concept, without writing anything. Concepts are
+// searched even when they were scraped recently.
+func (s *EducationalWebScraper) PreviewResourcesForConcepts(ctx context.Context, conceptNames []string) ([]EducationalResource, error) {
+	var candidates []EducationalResource
+	for _, conceptName := range conceptNames {
+		_, resources, err := s.collectResources(ctx, s.generateConceptID(conceptName), conceptName)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, resources...)
+	}
+
+	s.logger.Info("Previewed resource scraping",
+		zap.Int("concepts", len(conceptNames)),
+		zap.Int("candidates", len(candidates)))
+	return candidates, nil
+}
+
+// collectResources searches every platform for a concept and returns how
+// many results were found and the deduplicated, quality-filtered and
+// enriched resources worth storing
+func (s *EducationalWebScraper) collectResources(ctx context.Context, conceptID, conceptName string) (int, []EducationalResource, error) {
 	var allResources []EducationalResource
 
 	// Search different platforms concurrently
 	g, gCtx := errgroup.WithContext(ctx)
 	var mu sync.Mutex
 
-	searchFunctions := []func(context.Context, string, string) ([]EducationalResource, error){
-		s.searchYouTube,
-		s.searchKhanAcademy,
-		s.searchMathWorld,
-		s.searchGeneralEducationSites,
+	searchFunctions := s.searchers
+	if searchFunctions == nil {
+		searchFunctions = []searchFunc{
+			s.searchYouTube,
+			s.searchKhanAcademy,
+			s.searchMathWorld,
+			s.searchGeneralEducationSites,
+		}
 	}
 
 	for _, searchFunc := range searchFunctions {
@@ -298,27 +391,15 @@ func (s *EducationalWebScraper) scrapeResourcesForConcept(ctx context.Context, c
 	}
 
 	if err := g.Wait(); err != nil {
-		return fmt.Errorf("failed to search platforms: %w", err)
+		return 0, nil, fmt.Errorf("failed to search platforms: %w", err)
 	}
 
 	// Post-process resources
 	uniqueResources := s.deduplicateResources(allResources)
 	qualityResources := s.filterQualityResources(uniqueResources)
+	s.enrichVideos(ctx, qualityResources)
 
-	// Store in MongoDB
-	if len(qualityResources) > 0 {
-		if err := s.storeResources(ctx, qualityResources); err != nil {
-			s.logger.Error("Failed to store resources", zap.Error(err))
-			return err
-		}
-	}
-
-	s.logger.Info("Successfully scraped concept",
-		zap.String("concept", conceptName),
-		zap.Int("total_found", len(allResources)),
-		zap.Int("quality_stored", len(qualityResources)))
-
-	return nil
+	return len(allResources), qualityResources, nil
 }
 
 // generateConceptID creates a standardized concept ID
@@ -431,7 +512,7 @@ func (s *EducationalWebScraper) GetResourcesForConcept(ctx context.Context, conc
 	filter := bson.M{"concept_id": conceptID}
 
 	opts := options.Find().
-		SetSort(bson.D{{"quality_score", -1}}).
+		SetSort(resourceOrder).
 		SetLimit(int64(limit))
 
 	cursor, err := s.collection.Find(ctx, filter, opts)
@@ -448,6 +529,68 @@ func (s *EducationalWebScraper) GetResourcesForConcept(ctx context.Context, conc
 	return resources, nil
 }
 
+// resourceOrder lists pinned resources first, by pin order, then the rest
+// best quality first
+var resourceOrder = bson.D{{"pinned", -1}, {"pin_order", 1}, {"quality_score", -1}}
+
+// ErrInvalidResourceID is returned for a resource ID that is not an ObjectID
+var ErrInvalidResourceID = errors.New("invalid resource id")
+
+// SetResourcePinned pins a resource at order, or unpins it, and returns the
+// updated resource. It returns nil when the resource does not exist.
+func (s *EducationalWebScraper) SetResourcePinned(ctx context.Context, resourceID string, pinned bool, order int) (*EducationalResource, error) {
+	id, err := primitive.ObjectIDFromHex(resourceID)
+	if err != nil {
+		return nil, ErrInvalidResourceID
+	}
+
+	update := bson.M{"$unset": bson.M{"pinned": "", "pin_order": ""}}
+	if pinned {
+		update = bson.M{"$set": bson.M{"pinned": true}, "$unset": bson.M{"pin_order": ""}}
+		if order > 0 {
+			update = bson.M{"$set": bson.M{"pinned": true, "pin_order": order}}
+		}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var resource EducationalResource
+	if err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&resource); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to pin resource: %w", err)
+	}
+	return &resource, nil
+}
+
+// MoveResources reassigns every resource of one concept to another, as when
+// duplicate concepts are merged, and returns how many were moved
+func (s *EducationalWebScraper) MoveResources(ctx context.Context, fromConceptID, toConceptID, toConceptName string) (int64, error) {
+	update := bson.M{"$set": bson.M{"concept_id": toConceptID, "concept_name": toConceptName}}
+	result, err := s.collection.UpdateMany(ctx, bson.M{"concept_id": fromConceptID}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move resources: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// ConceptIDsWithResources returns the IDs of concepts that have at least one
+// stored resource
+func (s *EducationalWebScraper) ConceptIDsWithResources(ctx context.Context) ([]string, error) {
+	values, err := s.collection.Distinct(ctx, "concept_id", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list concepts with resources: %w", err)
+	}
+
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
 // GetResourceStats returns statistics about stored resources
 func (s *EducationalWebScraper) GetResourceStats(ctx context.Context) (map[string]interface{}, error) {
 	pipeline := mongo.Pipeline{
@@ -598,9 +741,9 @@ func (s *EducationalWebScraper) scrapeYouTubeResults(ctx context.Context, search
 			ContentPreview:  s.truncateString(video.Description, 200),
 			ScrapedAt:       time.Now(),
 			Language:        "en",
-			Duration:        &video.Duration,
-			ThumbnailURL:    &video.ThumbnailURL,
-			AuthorChannel:   &video.Channel,
+			Duration:        optionalString(video.Duration),
+			ThumbnailURL:    optionalString(video.ThumbnailURL),
+			AuthorChannel:   optionalString(video.Channel),
 			Tags:            s.extractVideoTags(video),
 			IsVerified:      s.isVerifiedChannel(video.Channel),
 		}
@@ -617,6 +760,15 @@ func (s *EducationalWebScraper) scrapeYouTubeResults(ctx context.Context, search
 	return resources, nil
 }
 
+// optionalString leaves missing video details unset, so storing a
+// rescraped video does not clear metadata found earlier
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
 // extractVideoInfoFromYouTubeData extracts video information from YouTube's data
 func (s *EducationalWebScraper) extractVideoInfoFromYouTubeData(data map[string]interface{}) []YouTubeVideoData {
 	var videos []YouTubeVideoData
@@ -0,0 +1,211 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestGetResourcesForConceptSortsPinnedFirst(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("sorts by pin, then quality", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.educational_resources", mtest.FirstBatch,
+			bson.D{{Key: "url", Value: "https://example.com/vetted"}, {Key: "pinned", Value: true}, {Key: "quality_score", Value: 0.2}},
+			bson.D{{Key: "url", Value: "https://example.com/scraped"}, {Key: "quality_score", Value: 0.9}},
+		))
+
+		s := &EducationalWebScraper{collection: mt.Coll, logger: zap.NewNop()}
+		resources, err := s.GetResourcesForConcept(context.Background(), "derivatives", 10)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(resources) != 2 || !resources[0].Pinned || resources[1].Pinned {
+			mt.Errorf("unexpected resources %+v", resources)
+		}
+
+		sort, ok := mt.GetStartedEvent().Command.Lookup("sort").DocumentOK()
+		if !ok {
+			mt.Fatal("expected a sort on the find")
+		}
+		keys, _ := sort.Elements()
+		var got []string
+		for _, key := range keys {
+			got = append(got, key.Key())
+		}
+		want := []string{"pinned", "pin_order", "quality_score"}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+			mt.Errorf("sort keys = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestSetResourcePinned(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	id := primitive.NewObjectID()
+
+	mt.Run("pins at an order", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "concept_id", Value: "derivatives"},
+				{Key: "pinned", Value: true},
+				{Key: "pin_order", Value: 2},
+			}},
+		})
+
+		s := &EducationalWebScraper{collection: mt.Coll, logger: zap.NewNop()}
+		resource, err := s.SetResourcePinned(context.Background(), id.Hex(), true, 2)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if resource == nil || !resource.Pinned || resource.PinOrder != 2 {
+			mt.Errorf("unexpected resource %+v", resource)
+		}
+
+		set := mt.GetStartedEvent().Command.Lookup("update", "$set").Document()
+		if pinned, _ := set.Lookup("pinned").BooleanOK(); !pinned {
+			mt.Errorf("expected pinned set, got %v", set)
+		}
+	})
+
+	mt.Run("unknown resource", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		s := &EducationalWebScraper{collection: mt.Coll, logger: zap.NewNop()}
+		resource, err := s.SetResourcePinned(context.Background(), id.Hex(), false, 0)
+		if err != nil || resource != nil {
+			mt.Errorf("expected no resource, got %+v, %v", resource, err)
+		}
+	})
+
+	mt.Run("invalid id", func(mt *mtest.T) {
+		s := &EducationalWebScraper{collection: mt.Coll, logger: zap.NewNop()}
+		if _, err := s.SetResourcePinned(context.Background(), "not-an-id", true, 0); !errors.Is(err, ErrInvalidResourceID) {
+			mt.Errorf("expected ErrInvalidResourceID, got %v", err)
+		}
+	})
+}
+
+func TestRescrapeKeepsPin(t *testing.T) {
+	// storeResources $sets the scraped resource; an unpinned copy must not
+	// carry pin fields that would overwrite a curator's pin
+	doc, err := bson.Marshal(EducationalResource{URL: "https://example.com/vetted", QualityScore: 0.4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"pinned", "pin_order"} {
+		if _, err := bson.Raw(doc).LookupErr(field); err == nil {
+			t.Errorf("expected %s left out of a scraped resource", field)
+		}
+	}
+}
+
+func TestMoveResources(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("moves every resource of the concept", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 3}, {Key: "nModified", Value: 3}})
+
+		s := &EducationalWebScraper{collection: mt.Coll, logger: zap.NewNop()}
+		moved, err := s.MoveResources(context.Background(), "limit", "limits", "Limits")
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if moved != 3 {
+			mt.Errorf("expected 3 moved, got %d", moved)
+		}
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		if id := update.Lookup("q", "concept_id").StringValue(); id != "limit" {
+			mt.Errorf("expected resources of limit selected, got %s", id)
+		}
+		if id := update.Lookup("u", "$set", "concept_id").StringValue(); id != "limits" {
+			mt.Errorf("expected resources moved to limits, got %s", id)
+		}
+		if multi, _ := update.Lookup("multi").BooleanOK(); !multi {
+			mt.Error("expected every matching resource updated")
+		}
+	})
+}
+
+// fixedSearch returns the same resources for every concept
+func fixedSearch(resources ...EducationalResource) searchFunc {
+	return func(ctx context.Context, conceptID, conceptName string) ([]EducationalResource, error) {
+		found := make([]EducationalResource, len(resources))
+		for i, resource := range resources {
+			resource.ConceptID, resource.ConceptName = conceptID, conceptName
+			found[i] = resource
+		}
+		return found, nil
+	}
+}
+
+func previewScraper(coll *mongo.Collection) *EducationalWebScraper {
+	return &EducationalWebScraper{
+		collection: coll,
+		logger:     zap.NewNop(),
+		searchers: []searchFunc{
+			fixedSearch(
+				EducationalResource{URL: "https://mathinsight.org/chain_rule", ResourceType: "article", SourceDomain: "mathinsight.org", QualityScore: 0.8},
+				EducationalResource{URL: "https://example.com/spam", ResourceType: "article", SourceDomain: "example.com", QualityScore: 0.1},
+			),
+			fixedSearch(
+				EducationalResource{URL: "https://tutorial.math.lamar.edu/chain", ResourceType: "tutorial", SourceDomain: "tutorial.math.lamar.edu", QualityScore: 0.9},
+				EducationalResource{URL: "https://mathinsight.org/chain_rule", ResourceType: "article", SourceDomain: "mathinsight.org", QualityScore: 0.8},
+			),
+		},
+	}
+}
+
+func TestPreviewResourcesForConceptsWritesNothing(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("returns candidates only", func(mt *mtest.T) {
+		s := previewScraper(mt.Coll)
+		candidates, err := s.PreviewResourcesForConcepts(context.Background(), []string{"Chain Rule"})
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		// Deduplicated, filtered by quality and best first, as a scrape stores them
+		if len(candidates) != 2 ||
+			candidates[0].URL != "https://tutorial.math.lamar.edu/chain" || candidates[0].QualityScore != 0.9 ||
+			candidates[1].SourceDomain != "mathinsight.org" {
+			mt.Fatalf("candidates = %+v", candidates)
+		}
+		if candidates[0].ConceptID != "chain_rule" {
+			mt.Errorf("ConceptID = %q, want chain_rule", candidates[0].ConceptID)
+		}
+		if events := mt.GetAllStartedEvents(); len(events) != 0 {
+			mt.Errorf("preview sent %d commands to MongoDB, want none", len(events))
+		}
+	})
+
+	mt.Run("scrape stores the same candidates", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.educational_resources", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}),
+		)
+
+		s := previewScraper(mt.Coll)
+		if err := s.scrapeResourcesForConcept(context.Background(), "Chain Rule"); err != nil {
+			mt.Fatal(err)
+		}
+
+		var commands []string
+		for _, event := range mt.GetAllStartedEvents() {
+			commands = append(commands, event.CommandName)
+		}
+		if len(commands) != 2 || commands[1] != "update" {
+			mt.Errorf("commands = %v, want the recency check then an update", commands)
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// YouTube endpoints video metadata is read from. oEmbed gives the thumbnail
+// and channel; the watch page's microdata gives the duration and publish date.
+const (
+	youTubeOEmbedURL = "https://www.youtube.com/oembed"
+	youTubeWatchURL  = "https://www.youtube.com/watch"
+)
+
+// ErrNotYouTubeVideo is returned when enriching a resource whose URL is not
+// a YouTube video
+var ErrNotYouTubeVideo = errors.New("resource is not a YouTube video")
+
+// VideoMetadata is the metadata found for a video; fields that were not
+// available are left empty
+type VideoMetadata struct {
+	ThumbnailURL string
+	Duration     string
+	Channel      string
+	PublishedAt  *time.Time
+}
+
+// VideoBackfillResult reports one run of the video metadata backfill
+type VideoBackfillResult struct {
+	Checked  int      `json:"checked"`
+	Enriched int      `json:"enriched"`
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// EnrichVideoMetadata fills in the thumbnail, duration, channel and publish
+// date of a YouTube video resource. Values that cannot be found keep what
+// the resource had, so it fails only when neither source answers.
+func (s *EducationalWebScraper) EnrichVideoMetadata(ctx context.Context, resource *EducationalResource) error {
+	videoID := youTubeVideoID(resource.URL)
+	if videoID == "" {
+		return ErrNotYouTubeVideo
+	}
+
+	oembed, oembedErr := s.fetchOEmbed(ctx, videoID)
+	page, pageErr := s.fetchWatchPageMetadata(ctx, videoID)
+	if oembedErr != nil && pageErr != nil {
+		return fmt.Errorf("failed to fetch video metadata: %w", errors.Join(oembedErr, pageErr))
+	}
+	if oembedErr != nil || pageErr != nil {
+		s.logger.Debug("Video metadata partially available",
+			zap.String("url", resource.URL),
+			zap.NamedError("oembed_error", oembedErr),
+			zap.NamedError("page_error", pageErr))
+	}
+
+	applyVideoMetadata(resource, mergeVideoMetadata(oembed, page))
+	checkedAt := time.Now()
+	resource.MetadataCheckedAt = &checkedAt
+	return nil
+}
+
+// BackfillVideoMetadata enriches up to limit stored YouTube videos whose
+// metadata was never checked, best quality first
+func (s *EducationalWebScraper) BackfillVideoMetadata(ctx context.Context, limit int) (*VideoBackfillResult, error) {
+	filter := bson.M{
+		"resource_type":       "video",
+		"source_domain":       "youtube.com",
+		"metadata_checked_at": nil,
+	}
+	opts := options.Find().SetSort(bson.D{{"quality_score", -1}}).SetLimit(int64(limit))
+
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query videos: %w", err)
+	}
+	var videos []EducationalResource
+	if err := cursor.All(ctx, &videos); err != nil {
+		return nil, fmt.Errorf("failed to decode videos: %w", err)
+	}
+
+	result := &VideoBackfillResult{}
+	for i := range videos {
+		if ctx.Err() != nil {
+			break
+		}
+		video := &videos[i]
+		result.Checked++
+
+		if err := s.EnrichVideoMetadata(ctx, video); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", video.URL, err))
+			continue
+		}
+
+		update := bson.M{"$set": bson.M{
+			"thumbnail_url":       video.ThumbnailURL,
+			"duration":            video.Duration,
+			"author_channel":      video.AuthorChannel,
+			"published_at":        video.PublishedAt,
+			"metadata_checked_at": video.MetadataCheckedAt,
+		}}
+		if _, err := s.collection.UpdateOne(ctx, bson.M{"_id": video.ID}, update); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", video.URL, err))
+			continue
+		}
+		result.Enriched++
+	}
+
+	s.logger.Info("Video metadata backfill finished",
+		zap.Int("checked", result.Checked),
+		zap.Int("enriched", result.Enriched),
+		zap.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// enrichVideos adds metadata to freshly scraped videos before they are
+// stored. Videos whose metadata cannot be fetched are stored as scraped.
+func (s *EducationalWebScraper) enrichVideos(ctx context.Context, resources []EducationalResource) {
+	for i := range resources {
+		if resources[i].ResourceType != "video" || ctx.Err() != nil {
+			continue
+		}
+		if err := s.EnrichVideoMetadata(ctx, &resources[i]); err != nil {
+			s.logger.Debug("Storing video without enriched metadata",
+				zap.String("url", resources[i].URL),
+				zap.Error(err))
+		}
+	}
+}
+
+func (s *EducationalWebScraper) fetchOEmbed(ctx context.Context, videoID string) (VideoMetadata, error) {
+	endpoint := s.oembedURL
+	if endpoint == "" {
+		endpoint = youTubeOEmbedURL
+	}
+	watchURL := youTubeWatchURL + "?v=" + url.QueryEscape(videoID)
+
+	body, err := s.fetchVideoMetadata(ctx, endpoint+"?format=json&url="+url.QueryEscape(watchURL))
+	if err != nil {
+		return VideoMetadata{}, fmt.Errorf("oEmbed: %w", err)
+	}
+	defer body.Close()
+
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return VideoMetadata{}, fmt.Errorf("oEmbed: %w", err)
+	}
+	return parseOEmbed(raw)
+}
+
+func (s *EducationalWebScraper) fetchWatchPageMetadata(ctx context.Context, videoID string) (VideoMetadata, error) {
+	endpoint := s.watchURL
+	if endpoint == "" {
+		endpoint = youTubeWatchURL
+	}
+
+	body, err := s.fetchVideoMetadata(ctx, endpoint+"?v="+url.QueryEscape(videoID))
+	if err != nil {
+		return VideoMetadata{}, fmt.Errorf("watch page: %w", err)
+	}
+	defer body.Close()
+
+	return parseWatchPageMetadata(body)
+}
+
+func (s *EducationalWebScraper) fetchVideoMetadata(ctx context.Context, target string) (io.ReadCloser, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", s.config.UserAgent)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// parseOEmbed reads the thumbnail and channel of a YouTube oEmbed response
+func parseOEmbed(raw []byte) (VideoMetadata, error) {
+	var response struct {
+		AuthorName   string `json:"author_name"`
+		ThumbnailURL string `json:"thumbnail_url"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return VideoMetadata{}, fmt.Errorf("invalid oEmbed response: %w", err)
+	}
+	return VideoMetadata{
+		ThumbnailURL: strings.TrimSpace(response.ThumbnailURL),
+		Channel:      strings.TrimSpace(response.AuthorName),
+	}, nil
+}
+
+// parseWatchPageMetadata reads the schema.org microdata of a watch page
+func parseWatchPageMetadata(r io.Reader) (VideoMetadata, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return VideoMetadata{}, err
+	}
+
+	meta := func(selector string) string {
+		value, _ := doc.Find(selector).First().Attr("content")
+		return strings.TrimSpace(value)
+	}
+
+	metadata := VideoMetadata{
+		ThumbnailURL: meta(`meta[property="og:image"]`),
+		Duration:     formatISODuration(meta(`meta[itemprop="duration"]`)),
+		Channel:      meta(`[itemprop="author"] [itemprop="name"]`),
+	}
+
+	published := meta(`meta[itemprop="datePublished"]`)
+	if published == "" {
+		published = meta(`meta[itemprop="uploadDate"]`)
+	}
+	if published != "" {
+		metadata.PublishedAt = parsePublishDate(published)
+	}
+	return metadata, nil
+}
+
+// parsePublishDate accepts the date-only and timestamp forms YouTube uses
+func parsePublishDate(value string) *time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
+
+var isoDurationPattern = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// formatISODuration turns an ISO 8601 duration such as PT1H2M3S into the
+// clock form YouTube shows, 1:02:03. Anything else gives "".
+func formatISODuration(value string) string {
+	match := isoDurationPattern.FindStringSubmatch(value)
+	if match == nil || value == "PT" {
+		return ""
+	}
+
+	var parts [3]int
+	for i, part := range match[1:] {
+		if part != "" {
+			parts[i], _ = strconv.Atoi(part)
+		}
+	}
+	seconds := parts[0]*3600 + parts[1]*60 + parts[2]
+
+	hours, minutes, seconds := seconds/3600, seconds%3600/60, seconds%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+// mergeVideoMetadata prefers oEmbed values and falls back to the watch page
+func mergeVideoMetadata(oembed, page VideoMetadata) VideoMetadata {
+	merged := page
+	if oembed.ThumbnailURL != "" {
+		merged.ThumbnailURL = oembed.ThumbnailURL
+	}
+	if oembed.Channel != "" {
+		merged.Channel = oembed.Channel
+	}
+	return merged
+}
+
+// applyVideoMetadata sets the metadata that was found on resource
+func applyVideoMetadata(resource *EducationalResource, metadata VideoMetadata) {
+	if metadata.ThumbnailURL != "" {
+		resource.ThumbnailURL = &metadata.ThumbnailURL
+	}
+	if metadata.Duration != "" {
+		resource.Duration = &metadata.Duration
+	}
+	if metadata.Channel != "" {
+		resource.AuthorChannel = &metadata.Channel
+	}
+	if metadata.PublishedAt != nil {
+		resource.PublishedAt = metadata.PublishedAt
+	}
+}
+
+// youTubeVideoID returns the video ID of a watch, youtu.be, embed or shorts
+// URL, or "" for any other URL
+func youTubeVideoID(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	path := strings.Trim(parsed.Path, "/")
+
+	switch host {
+	case "youtu.be":
+		return path
+	case "youtube.com":
+		if path == "watch" {
+			return parsed.Query().Get("v")
+		}
+		for _, prefix := range []string{"embed/", "shorts/"} {
+			if id, ok := strings.CutPrefix(path, prefix); ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,253 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const sampleOEmbed = `{
+	"title": "The paradox of the derivative",
+	"author_name": "3Blue1Brown",
+	"author_url": "https://www.youtube.com/@3blue1brown",
+	"type": "video",
+	"thumbnail_url": "https://i.ytimg.com/vi/9vKqVkMQHKk/hqdefault.jpg",
+	"thumbnail_width": 480,
+	"thumbnail_height": 360
+}`
+
+const sampleWatchPage = `<html><head>
+<meta property="og:image" content="https://i.ytimg.com/vi/9vKqVkMQHKk/maxresdefault.jpg">
+</head><body>
+<div itemscope itemtype="http://schema.org/VideoObject">
+	<meta itemprop="duration" content="PT17M57S">
+	<span itemprop="author" itemscope itemtype="http://schema.org/Person">
+		<link itemprop="name" content="3Blue1Brown">
+	</span>
+	<meta itemprop="datePublished" content="2017-04-28">
+</div>
+</body></html>`
+
+func TestParseOEmbed(t *testing.T) {
+	metadata, err := parseOEmbed([]byte(sampleOEmbed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Channel != "3Blue1Brown" || metadata.ThumbnailURL != "https://i.ytimg.com/vi/9vKqVkMQHKk/hqdefault.jpg" {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+
+	if _, err := parseOEmbed([]byte("Not Found")); err == nil {
+		t.Error("expected an error for a non-JSON response")
+	}
+}
+
+func TestParseWatchPageMetadata(t *testing.T) {
+	metadata, err := parseWatchPageMetadata(strings.NewReader(sampleWatchPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Duration != "17:57" || metadata.Channel != "3Blue1Brown" {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+	if metadata.PublishedAt == nil || !metadata.PublishedAt.Equal(time.Date(2017, 4, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected publish date %v", metadata.PublishedAt)
+	}
+
+	empty, err := parseWatchPageMetadata(strings.NewReader("<html><body>consent wall</body></html>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty != (VideoMetadata{}) {
+		t.Errorf("expected no metadata from a page without microdata, got %+v", empty)
+	}
+}
+
+func TestFormatISODuration(t *testing.T) {
+	tests := map[string]string{
+		"PT17M57S":  "17:57",
+		"PT45S":     "0:45",
+		"PT1H2M3S":  "1:02:03",
+		"PT90M":     "1:30:00",
+		"PT":        "",
+		"17:57":     "",
+		"P1DT2H":    "",
+		"":          "",
+		"PT0M300S":  "5:00",
+		"PT4M13.5S": "",
+	}
+	for input, want := range tests {
+		if got := formatISODuration(input); got != want {
+			t.Errorf("formatISODuration(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestYouTubeVideoID(t *testing.T) {
+	tests := map[string]string{
+		"https://www.youtube.com/watch?v=9vKqVkMQHKk":        "9vKqVkMQHKk",
+		"https://m.youtube.com/watch?v=9vKqVkMQHKk&t=30":     "9vKqVkMQHKk",
+		"https://youtu.be/9vKqVkMQHKk":                       "9vKqVkMQHKk",
+		"https://www.youtube.com/embed/9vKqVkMQHKk":          "9vKqVkMQHKk",
+		"https://www.youtube.com/shorts/9vKqVkMQHKk":         "9vKqVkMQHKk",
+		"https://www.khanacademy.org/math/calculus-1/limits": "",
+		"https://www.youtube.com/@3blue1brown":               "",
+	}
+	for input, want := range tests {
+		if got := youTubeVideoID(input); got != want {
+			t.Errorf("youTubeVideoID(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// newMetadataScraper serves oEmbed and watch pages from handlers; a nil
+// handler answers 404
+func newMetadataScraper(t *testing.T, oembed, watch http.HandlerFunc) *EducationalWebScraper {
+	t.Helper()
+	notFound := func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }
+	if oembed == nil {
+		oembed = notFound
+	}
+	if watch == nil {
+		watch = notFound
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oembed", oembed)
+	mux.HandleFunc("/watch", watch)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return &EducationalWebScraper{
+		httpClient: server.Client(),
+		limiter:    rate.NewLimiter(rate.Inf, 1),
+		logger:     zap.NewNop(),
+		oembedURL:  server.URL + "/oembed",
+		watchURL:   server.URL + "/watch",
+	}
+}
+
+func serve(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+func TestEnrichVideoMetadata(t *testing.T) {
+	var oembedQuery string
+	s := newMetadataScraper(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			oembedQuery = r.URL.Query().Get("url")
+			w.Write([]byte(sampleOEmbed))
+		},
+		serve(sampleWatchPage))
+
+	resource := &EducationalResource{URL: "https://youtu.be/9vKqVkMQHKk", ResourceType: "video"}
+	if err := s.EnrichVideoMetadata(context.Background(), resource); err != nil {
+		t.Fatal(err)
+	}
+
+	if oembedQuery != "https://www.youtube.com/watch?v=9vKqVkMQHKk" {
+		t.Errorf("expected oEmbed asked for the watch URL, got %q", oembedQuery)
+	}
+	// oEmbed wins over the watch page for the thumbnail
+	if resource.ThumbnailURL == nil || *resource.ThumbnailURL != "https://i.ytimg.com/vi/9vKqVkMQHKk/hqdefault.jpg" {
+		t.Errorf("unexpected thumbnail %v", resource.ThumbnailURL)
+	}
+	if resource.Duration == nil || *resource.Duration != "17:57" {
+		t.Errorf("unexpected duration %v", resource.Duration)
+	}
+	if resource.AuthorChannel == nil || *resource.AuthorChannel != "3Blue1Brown" {
+		t.Errorf("unexpected channel %v", resource.AuthorChannel)
+	}
+	if resource.PublishedAt == nil || resource.MetadataCheckedAt == nil {
+		t.Errorf("expected publish date and check time set, got %v and %v", resource.PublishedAt, resource.MetadataCheckedAt)
+	}
+}
+
+func TestEnrichVideoMetadataHandlesMissingMetadata(t *testing.T) {
+	duration := "4:13"
+
+	t.Run("oEmbed unavailable", func(t *testing.T) {
+		s := newMetadataScraper(t, nil, serve("<html><body>no microdata</body></html>"))
+		resource := &EducationalResource{URL: "https://www.youtube.com/watch?v=abc", Duration: &duration}
+
+		if err := s.EnrichVideoMetadata(context.Background(), resource); err != nil {
+			t.Fatal(err)
+		}
+		if resource.Duration != &duration || resource.ThumbnailURL != nil || resource.AuthorChannel != nil {
+			t.Errorf("expected existing values kept and missing ones unset, got %+v", resource)
+		}
+	})
+
+	t.Run("both sources fail", func(t *testing.T) {
+		s := newMetadataScraper(t, nil, nil)
+		resource := &EducationalResource{URL: "https://www.youtube.com/watch?v=abc"}
+
+		if err := s.EnrichVideoMetadata(context.Background(), resource); err == nil {
+			t.Error("expected an error when no metadata can be fetched")
+		}
+		if resource.MetadataCheckedAt != nil {
+			t.Error("expected a failed video left unchecked so the backfill retries it")
+		}
+	})
+
+	t.Run("not a YouTube video", func(t *testing.T) {
+		s := newMetadataScraper(t, nil, nil)
+		resource := &EducationalResource{URL: "https://www.khanacademy.org/v/limits"}
+
+		if err := s.EnrichVideoMetadata(context.Background(), resource); err != ErrNotYouTubeVideo {
+			t.Errorf("expected ErrNotYouTubeVideo, got %v", err)
+		}
+	})
+}
+
+func TestBackfillVideoMetadata(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("enriches unchecked videos", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.educational_resources", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: id}, {Key: "url", Value: "https://www.youtube.com/watch?v=9vKqVkMQHKk"}, {Key: "resource_type", Value: "video"}},
+			),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+		)
+
+		s := newMetadataScraper(t, serve(sampleOEmbed), serve(sampleWatchPage))
+		s.collection = mt.Coll
+
+		result, err := s.BackfillVideoMetadata(context.Background(), 10)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if result.Checked != 1 || result.Enriched != 1 || result.Failed != 0 {
+			mt.Errorf("unexpected result %+v", result)
+		}
+
+		events := mt.GetAllStartedEvents()
+		if len(events) != 2 {
+			mt.Fatalf("expected a find and an update, got %d commands", len(events))
+		}
+		filter := events[0].Command.Lookup("filter").Document()
+		if _, err := filter.LookupErr("metadata_checked_at"); err != nil {
+			mt.Errorf("expected only unchecked videos selected, got %v", filter)
+		}
+		set := events[1].Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+		if duration := set.Lookup("duration").StringValue(); duration != "17:57" {
+			mt.Errorf("expected the duration stored, got %q", duration)
+		}
+		if _, err := set.LookupErr("metadata_checked_at"); err != nil {
+			mt.Error("expected the video marked as checked")
+		}
+	})
+}
@@ -2,12 +2,16 @@ package weaviate
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/go-openapi/strfmt"
 	"github.com/google/uuid"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/auth"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/fault"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
 
@@ -16,10 +20,16 @@ import (
 	"go.uber.org/zap"
 )
 
+// Vectorizer is the module both classes are created with; stored vectors
+// are only comparable with queries embedded by the same one
+const Vectorizer = "text2vec-weaviate"
+
 type Client struct {
-	client *weaviate.Client
-	logger *zap.Logger
-	class  string
+	client       *weaviate.Client
+	logger       *zap.Logger
+	class        string
+	conceptClass string
+	retry        retryPolicy
 }
 
 type Source struct {
@@ -39,10 +49,19 @@ type ContentChunk struct {
 	ChunkIndex int    `json:"chunk_index"`
 }
 
+// ConceptVector is a graph concept stored in the concept class so context
+// can be retrieved from concept descriptions as well as textbook chunks
+type ConceptVector struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 type SearchResult struct {
 	Content  string                 `json:"content"`
 	Concept  string                 `json:"concept"`
 	Chapter  string                 `json:"chapter"`
+	Source   string                 `json:"source,omitempty"`
 	Score    float32                `json:"score"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
@@ -74,11 +93,27 @@ func NewClient(cfg config.WeaviateConfig) (*Client, error) {
 	if className == "" {
 		className = "MathChunk" // Default fallback
 	}
+	conceptClassName := cfg.ConceptClassName
+	if conceptClassName == "" {
+		conceptClassName = "MathConcept"
+	}
 
 	client := &Client{
-		client: weaviateClient,
-		logger: logger,
-		class:  className,
+		client:       weaviateClient,
+		logger:       logger,
+		class:        className,
+		conceptClass: conceptClassName,
+		retry: retryPolicy{
+			timeout:    cfg.OperationTimeout,
+			maxRetries: cfg.MaxRetries,
+			backoff:    cfg.RetryBackoff,
+		},
+	}
+	if client.retry.timeout <= 0 {
+		client.retry.timeout = DefaultOperationTimeout
+	}
+	if client.retry.backoff <= 0 {
+		client.retry.backoff = DefaultRetryBackoff
 	}
 
 	// Test connection
@@ -90,6 +125,9 @@ func NewClient(cfg config.WeaviateConfig) (*Client, error) {
 	if err := client.initSchema(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
+	if err := client.initConceptSchema(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to initialize concept schema: %w", err)
+	}
 
 	logger.Info("Weaviate client initialized successfully",
 		zap.String("host", cfg.Host),
@@ -113,7 +151,7 @@ func (c *Client) initSchema(ctx context.Context) error {
 	// Create class schema
 	classObj := &models.Class{
 		Class:      c.class,
-		Vectorizer: "text2vec-weaviate",
+		Vectorizer: Vectorizer,
 		Properties: []*models.Property{
 			{
 				DataType:    []string{"text"},
@@ -152,20 +190,125 @@ func (c *Client) initSchema(ctx context.Context) error {
 	return nil
 }
 
+// initConceptSchema creates the concept class when it is missing
+func (c *Client) initConceptSchema(ctx context.Context) error {
+	exists, err := c.client.Schema().ClassExistenceChecker().WithClassName(c.conceptClass).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check class existence: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	classObj := &models.Class{
+		Class:      c.conceptClass,
+		Vectorizer: Vectorizer,
+		Properties: []*models.Property{
+			{
+				DataType:    []string{"string"},
+				Name:        "conceptId",
+				Description: "The ID of the concept in the knowledge graph",
+			},
+			{
+				DataType:    []string{"text"},
+				Name:        "name",
+				Description: "The concept name",
+			},
+			{
+				DataType:    []string{"text"},
+				Name:        "description",
+				Description: "What the concept covers",
+			},
+		},
+	}
+	if err := c.client.Schema().ClassCreator().WithClass(classObj).Do(ctx); err != nil {
+		return fmt.Errorf("failed to create class: %w", err)
+	}
+
+	c.logger.Info("Created schema class", zap.String("class", c.conceptClass))
+	return nil
+}
+
+// ClassName is the textbook chunk class
+func (c *Client) ClassName() string {
+	return c.class
+}
+
+// ConceptClassName is the concept description class
+func (c *Client) ConceptClassName() string {
+	return c.conceptClass
+}
+
+// ClassVectorizer returns the vectorizer a class was created with, or an
+// error when the class does not exist
+func (c *Client) ClassVectorizer(ctx context.Context, class string) (string, error) {
+	exists, err := c.client.Schema().ClassExistenceChecker().WithClassName(class).Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to check class existence: %w", err)
+	}
+	if !exists {
+		return "", fmt.Errorf("class %s does not exist", class)
+	}
+
+	classObj, err := c.client.Schema().ClassGetter().WithClassName(class).Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read class %s: %w", class, err)
+	}
+	return classObj.Vectorizer, nil
+}
+
+// SemanticSearch finds chunks near the whole query text, treated as a
+// single nearText concept
 func (c *Client) SemanticSearch(ctx context.Context, query string, limit int) ([]SearchResult, error) {
 	c.logger.Info("Performing semantic search",
 		zap.String("query", query),
 		zap.Int("limit", limit))
 
+	return c.nearTextSearch(ctx, []string{query}, limit)
+}
+
+// SemanticSearchByConcepts finds chunks near the given concepts, passing
+// each one to the vectorizer as a separate nearText concept
+func (c *Client) SemanticSearchByConcepts(ctx context.Context, concepts []string, limit int) ([]SearchResult, error) {
+	concepts = cleanConcepts(concepts)
+	if len(concepts) == 0 {
+		return nil, fmt.Errorf("semantic search requires at least one concept")
+	}
+
+	c.logger.Info("Performing semantic search by concepts",
+		zap.Strings("concepts", concepts),
+		zap.Int("limit", limit))
+
+	return c.nearTextSearch(ctx, concepts, limit)
+}
+
+// cleanConcepts trims concepts and drops blanks and case-insensitive duplicates
+func cleanConcepts(concepts []string) []string {
+	cleaned := make([]string, 0, len(concepts))
+	seen := make(map[string]bool, len(concepts))
+	for _, concept := range concepts {
+		concept = strings.TrimSpace(concept)
+		key := strings.ToLower(concept)
+		if concept == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, concept)
+	}
+	return cleaned
+}
+
+func (c *Client) nearTextSearch(ctx context.Context, concepts []string, limit int) ([]SearchResult, error) {
 	// Build the nearText argument
 	nearText := c.client.GraphQL().NearTextArgBuilder().
-		WithConcepts([]string{query})
+		WithConcepts(concepts)
 
 	// Build fields using the proper field builders
 	fields := []graphql.Field{
 		{Name: "content"},
 		{Name: "concept"},
 		{Name: "chapter"},
+		{Name: "source"},
 		{
 			Name: "_additional",
 			Fields: []graphql.Field{
@@ -174,13 +317,18 @@ func (c *Client) SemanticSearch(ctx context.Context, query string, limit int) ([
 		},
 	}
 
-	// Build the GraphQL query
-	result, err := c.client.GraphQL().Get().
-		WithClassName(c.class).
-		WithFields(fields...).
-		WithNearText(nearText).
-		WithLimit(limit).
-		Do(ctx)
+	// Build the GraphQL query, retrying transient failures
+	var result *models.GraphQLResponse
+	err := c.retry.do(ctx, c.logger, "semantic_search", func(ctx context.Context) error {
+		var err error
+		result, err = c.client.GraphQL().Get().
+			WithClassName(c.class).
+			WithFields(fields...).
+			WithNearText(nearText).
+			WithLimit(limit).
+			Do(ctx)
+		return err
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("semantic search failed: %w", err)
@@ -195,16 +343,12 @@ func (c *Client) SemanticSearch(ctx context.Context, query string, limit int) ([
 				for _, item := range classData {
 					if obj, ok := item.(map[string]interface{}); ok {
 						searchResult := SearchResult{
-							Content: getStringField(obj, "content"),
-							Concept: getStringField(obj, "concept"),
-							Chapter: getStringField(obj, "chapter"),
-						}
-
-						// Extract certainty score from _additional
-						if additional, ok := obj["_additional"].(map[string]interface{}); ok {
-							if certainty, ok := additional["certainty"].(float64); ok {
-								searchResult.Score = float32(certainty)
-							}
+							Content:  getStringField(obj, "content"),
+							Concept:  getStringField(obj, "concept"),
+							Chapter:  getStringField(obj, "chapter"),
+							Source:   getStringField(obj, "source"),
+							Score:    certaintyOf(obj),
+							Metadata: map[string]interface{}{"source_class": c.class},
 						}
 
 						searchResults = append(searchResults, searchResult)
@@ -220,6 +364,156 @@ func (c *Client) SemanticSearch(ctx context.Context, query string, limit int) ([
 	return searchResults, nil
 }
 
+// SearchConceptVectors finds concepts whose name and description are near
+// the given terms. Each result's content is the concept's description, led
+// by its name.
+func (c *Client) SearchConceptVectors(ctx context.Context, terms []string, limit int) ([]SearchResult, error) {
+	terms = cleanConcepts(terms)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("concept search requires at least one term")
+	}
+
+	c.logger.Info("Performing concept vector search",
+		zap.Strings("terms", terms),
+		zap.Int("limit", limit))
+
+	nearText := c.client.GraphQL().NearTextArgBuilder().WithConcepts(terms)
+	fields := []graphql.Field{
+		{Name: "conceptId"},
+		{Name: "name"},
+		{Name: "description"},
+		{Name: "_additional", Fields: []graphql.Field{{Name: "certainty"}}},
+	}
+
+	var result *models.GraphQLResponse
+	err := c.retry.do(ctx, c.logger, "concept_search", func(ctx context.Context) error {
+		var err error
+		result, err = c.client.GraphQL().Get().
+			WithClassName(c.conceptClass).
+			WithFields(fields...).
+			WithNearText(nearText).
+			WithLimit(limit).
+			Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("concept search failed: %w", err)
+	}
+
+	var searchResults []SearchResult
+	if result.Data != nil {
+		if get, ok := result.Data["Get"].(map[string]interface{}); ok {
+			if classData, ok := get[c.conceptClass].([]interface{}); ok {
+				for _, item := range classData {
+					obj, ok := item.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					name := getStringField(obj, "name")
+					content := name
+					if description := getStringField(obj, "description"); description != "" {
+						content = name + ": " + description
+					}
+					searchResults = append(searchResults, SearchResult{
+						Content: content,
+						Concept: name,
+						Score:   certaintyOf(obj),
+						Metadata: map[string]interface{}{
+							"source_class": c.conceptClass,
+							"concept_id":   getStringField(obj, "conceptId"),
+						},
+					})
+				}
+			}
+		}
+	}
+
+	c.logger.Info("Concept vector search completed",
+		zap.Int("results", len(searchResults)))
+
+	return searchResults, nil
+}
+
+// certaintyOf reads the certainty score from a result's _additional fields
+func certaintyOf(obj map[string]interface{}) float32 {
+	if additional, ok := obj["_additional"].(map[string]interface{}); ok {
+		if certainty, ok := additional["certainty"].(float64); ok {
+			return float32(certainty)
+		}
+	}
+	return 0
+}
+
+// conceptVectorID is the object ID of a concept in the concept class
+func conceptVectorID(conceptID string) strfmt.UUID {
+	return strfmt.UUID(uuid.NewSHA1(uuid.NameSpaceOID, []byte(conceptID)).String())
+}
+
+// DeleteConceptVector removes a concept from the concept class. A concept
+// that was never stored is not an error.
+func (c *Client) DeleteConceptVector(ctx context.Context, conceptID string) error {
+	err := c.retry.do(ctx, c.logger, "delete_concept", func(ctx context.Context) error {
+		return c.client.Data().Deleter().
+			WithClassName(c.conceptClass).
+			WithID(conceptVectorID(conceptID).String()).
+			Do(ctx)
+	})
+	var clientErr *fault.WeaviateClientError
+	if errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("concept delete failed: %w", err)
+	}
+	return nil
+}
+
+// AddConceptVectors stores concepts in the concept class. Object IDs are
+// derived from concept IDs, so reloading a concept replaces it.
+func (c *Client) AddConceptVectors(ctx context.Context, concepts []ConceptVector) error {
+	if len(concepts) == 0 {
+		return nil
+	}
+
+	objects := make([]*models.Object, 0, len(concepts))
+	for _, concept := range concepts {
+		objects = append(objects, &models.Object{
+			Class: c.conceptClass,
+			ID:    conceptVectorID(concept.ID),
+			Properties: map[string]interface{}{
+				"conceptId":   concept.ID,
+				"name":        concept.Name,
+				"description": concept.Description,
+			},
+		})
+	}
+
+	var batchResult []models.ObjectsGetResponse
+	err := c.retry.do(ctx, c.logger, "add_concepts", func(ctx context.Context) error {
+		var err error
+		batchResult, err = c.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("concept batch insert failed: %w", err)
+	}
+
+	failed := 0
+	for _, result := range batchResult {
+		if result.Result != nil && result.Result.Errors != nil && len(result.Result.Errors.Error) > 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		c.logger.Warn("Some concepts failed to insert",
+			zap.Int("total_concepts", len(concepts)),
+			zap.Int("failed_concepts", failed))
+	}
+
+	c.logger.Info("Added concepts to vector store", zap.Int("concepts", len(concepts)))
+	return nil
+}
+
 func (c *Client) AddContent(ctx context.Context, content []ContentChunk) error {
 	c.logger.Info("Adding content to vector store",
 		zap.Int("chunks", len(content)))
@@ -230,7 +524,7 @@ func (c *Client) AddContent(ctx context.Context, content []ContentChunk) error {
 	}
 
 	// Batch insert for better performance
-	batcher := c.client.Batch().ObjectsBatcher()
+	objects := make([]*models.Object, 0, len(content))
 
 	for _, chunk := range content {
 		// Convert Source struct to string for Weaviate storage
@@ -250,8 +544,11 @@ func (c *Client) AddContent(ctx context.Context, content []ContentChunk) error {
 			"chunkIndex": chunk.ChunkIndex,
 		}
 
-		// Generate a proper UUID for the chunk
-		uuidValue := uuid.New().String()
+		// Reuse deterministic chunk IDs so re-ingesting overwrites instead of duplicating
+		uuidValue := chunk.ID
+		if _, err := uuid.Parse(uuidValue); err != nil {
+			uuidValue = uuid.New().String()
+		}
 
 		obj := &models.Object{
 			Class:      c.class,
@@ -259,11 +556,17 @@ func (c *Client) AddContent(ctx context.Context, content []ContentChunk) error {
 			Properties: properties,
 		}
 
-		batcher = batcher.WithObjects(obj)
+		objects = append(objects, obj)
 	}
 
-	// Execute batch
-	batchResult, err := batcher.Do(ctx)
+	// Execute batch; the batcher resets after Do, so each attempt builds a new one.
+	// Object IDs are fixed above, so a retried batch overwrites rather than duplicates.
+	var batchResult []models.ObjectsGetResponse
+	err := c.retry.do(ctx, c.logger, "add_content", func(ctx context.Context) error {
+		var err error
+		batchResult, err = c.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("batch insert failed: %w", err)
 	}
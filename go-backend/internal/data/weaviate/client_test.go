@@ -0,0 +1,183 @@
+package weaviate
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/weaviate/weaviate-go-client/v4/weaviate"
+	"go.uber.org/zap"
+)
+
+// recordingTransport captures GraphQL queries and answers with an empty result
+type recordingTransport struct {
+	queries []string
+	// response is the GraphQL body returned, an empty result when unset
+	response string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body struct {
+		Query string `json:"query"`
+	}
+	if strings.HasSuffix(req.URL.Path, "/graphql") && req.Body != nil {
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		t.queries = append(t.queries, body.Query)
+	}
+	response := t.response
+	if response == "" {
+		response = `{"data":{"Get":{"MathChunk":[]}}}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(response)),
+		Request:    req,
+	}, nil
+}
+
+func newRecordingClient(t *testing.T) (*Client, *recordingTransport) {
+	t.Helper()
+	transport := &recordingTransport{}
+	wc, err := weaviate.NewClient(weaviate.Config{
+		Host:             "weaviate.test",
+		Scheme:           "http",
+		ConnectionClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return &Client{
+		client:       wc,
+		logger:       zap.NewNop(),
+		class:        "MathChunk",
+		conceptClass: "MathConcept",
+		retry:        retryPolicy{timeout: time.Second},
+	}, transport
+}
+
+func TestSemanticSearchByConceptsPassesEachConcept(t *testing.T) {
+	client, transport := newRecordingClient(t)
+
+	_, err := client.SemanticSearchByConcepts(context.Background(),
+		[]string{"derivatives", " chain rule ", "Derivatives", ""}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transport.queries) != 1 {
+		t.Fatalf("expected one GraphQL request, got %d", len(transport.queries))
+	}
+	query := transport.queries[0]
+	if !strings.Contains(query, `concepts: ["derivatives","chain rule"]`) {
+		t.Errorf("expected both concepts as separate nearText concepts, got %s", query)
+	}
+}
+
+func TestSemanticSearchPassesQueryAsOneConcept(t *testing.T) {
+	client, transport := newRecordingClient(t)
+
+	if _, err := client.SemanticSearch(context.Background(), "how do derivatives use the chain rule", 5); err != nil {
+		t.Fatal(err)
+	}
+	if query := transport.queries[0]; !strings.Contains(query, `concepts: ["how do derivatives use the chain rule"]`) {
+		t.Errorf("expected the query as a single concept, got %s", query)
+	}
+}
+
+func TestSemanticSearchByConceptsRequiresAConcept(t *testing.T) {
+	client, transport := newRecordingClient(t)
+
+	if _, err := client.SemanticSearchByConcepts(context.Background(), []string{" ", ""}, 5); err == nil {
+		t.Fatal("expected an error without concepts")
+	}
+	if len(transport.queries) != 0 {
+		t.Errorf("expected no request, got %d", len(transport.queries))
+	}
+}
+
+func TestSearchConceptVectorsQueriesConceptClass(t *testing.T) {
+	client, transport := newRecordingClient(t)
+
+	if _, err := client.SearchConceptVectors(context.Background(), []string{"limits"}, 3); err != nil {
+		t.Fatal(err)
+	}
+	query := transport.queries[0]
+	if !strings.Contains(query, "MathConcept") || strings.Contains(query, "MathChunk") {
+		t.Errorf("expected a search of the concept class only, got %s", query)
+	}
+	if !strings.Contains(query, "description") {
+		t.Errorf("expected concept descriptions to be requested, got %s", query)
+	}
+}
+
+func TestSemanticSearchReturnsScoreAndSource(t *testing.T) {
+	client, transport := newRecordingClient(t)
+	transport.response = `{"data":{"Get":{"MathChunk":[{"content":"A limit describes...","concept":"limits","chapter":"2.1","source":"Calculus Vol 1","_additional":{"certainty":0.91}}]}}}`
+
+	results, err := client.SemanticSearch(context.Background(), "what is a limit", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query := transport.queries[0]; !strings.Contains(query, "source") {
+		t.Errorf("expected the chunk source to be requested, got %s", query)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %+v", results)
+	}
+	got := results[0]
+	if got.Source != "Calculus Vol 1" || got.Chapter != "2.1" || got.Score < 0.9 || got.Score > 0.92 {
+		t.Errorf("unexpected result %+v", got)
+	}
+}
+
+// statusTransport answers every request with one status code
+type statusTransport struct {
+	status int
+	paths  []string
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.paths = append(t.paths, req.Method+" "+req.URL.Path)
+	return &http.Response{
+		StatusCode: t.status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestDeleteConceptVector(t *testing.T) {
+	for _, tt := range []struct {
+		status  int
+		wantErr bool
+	}{
+		{http.StatusNoContent, false},
+		{http.StatusNotFound, false},
+		{http.StatusUnprocessableEntity, true},
+	} {
+		transport := &statusTransport{status: tt.status}
+		wc, err := weaviate.NewClient(weaviate.Config{
+			Host:             "weaviate.test",
+			Scheme:           "http",
+			ConnectionClient: &http.Client{Transport: transport},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &Client{client: wc, logger: zap.NewNop(), conceptClass: "MathConcept", retry: retryPolicy{timeout: time.Second}}
+
+		err = client.DeleteConceptVector(context.Background(), "limits")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("status %d: got error %v, want error %v", tt.status, err, tt.wantErr)
+		}
+		last := transport.paths[len(transport.paths)-1]
+		if !strings.HasPrefix(last, "DELETE /v1/objects/") || !strings.HasSuffix(last, conceptVectorID("limits").String()) {
+			t.Errorf("status %d: expected the concept's object deleted, got %v", tt.status, transport.paths)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package weaviate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
+	"github.com/weaviate/weaviate/entities/models"
+	"go.uber.org/zap"
+)
+
+// conceptGroupLimit caps how many distinct concept tags are counted
+const conceptGroupLimit = 10000
+
+// CountChunksByConcept returns how many textbook chunks are tagged with
+// each value of their concept field, as one aggregation. Untagged chunks
+// are counted under "".
+func (c *Client) CountChunksByConcept(ctx context.Context) (map[string]int, error) {
+	var result *models.GraphQLResponse
+	err := c.retry.do(ctx, c.logger, "count_by_concept", func(ctx context.Context) error {
+		var err error
+		result, err = c.client.GraphQL().Aggregate().
+			WithClassName(c.class).
+			WithGroupBy("concept").
+			WithLimit(conceptGroupLimit).
+			WithFields(
+				graphql.Field{Name: "groupedBy", Fields: []graphql.Field{{Name: "value"}}},
+				graphql.Field{Name: "meta", Fields: []graphql.Field{{Name: "count"}}},
+			).
+			Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("concept aggregation failed: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("concept aggregation failed: %s", result.Errors[0].Message)
+	}
+
+	counts := make(map[string]int)
+	for _, group := range aggregateGroups(result, c.class) {
+		value := ""
+		if groupedBy, ok := group["groupedBy"].(map[string]interface{}); ok {
+			value, _ = groupedBy["value"].(string)
+		}
+		counts[value] += metaCount(group)
+	}
+
+	c.logger.Info("Counted chunks by concept", zap.Int("concepts", len(counts)))
+	return counts, nil
+}
+
+// CountChunksNear returns how many textbook chunks are at least certainty
+// close to the term, counting no more than maxChunks
+func (c *Client) CountChunksNear(ctx context.Context, term string, certainty float32, maxChunks int) (int, error) {
+	nearText := c.client.GraphQL().NearTextArgBuilder().
+		WithConcepts([]string{term}).
+		WithCertainty(certainty)
+
+	var result *models.GraphQLResponse
+	err := c.retry.do(ctx, c.logger, "count_near", func(ctx context.Context) error {
+		var err error
+		result, err = c.client.GraphQL().Aggregate().
+			WithClassName(c.class).
+			WithNearText(nearText).
+			WithObjectLimit(maxChunks).
+			WithFields(graphql.Field{Name: "meta", Fields: []graphql.Field{{Name: "count"}}}).
+			Do(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("near count failed: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return 0, fmt.Errorf("near count failed: %s", result.Errors[0].Message)
+	}
+
+	groups := aggregateGroups(result, c.class)
+	if len(groups) == 0 {
+		return 0, nil
+	}
+	return metaCount(groups[0]), nil
+}
+
+// aggregateGroups returns the rows of an Aggregate response for a class
+func aggregateGroups(result *models.GraphQLResponse, class string) []map[string]interface{} {
+	if result == nil || result.Data == nil {
+		return nil
+	}
+	aggregate, ok := result.Data["Aggregate"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	items, _ := aggregate[class].([]interface{})
+	groups := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if group, ok := item.(map[string]interface{}); ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// metaCount reads meta.count from an aggregate row
+func metaCount(group map[string]interface{}) int {
+	meta, ok := group["meta"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	count, _ := meta["count"].(float64)
+	return int(count)
+}
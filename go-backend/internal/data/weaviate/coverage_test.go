@@ -0,0 +1,47 @@
+package weaviate
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCountChunksByConceptGroupsByTag(t *testing.T) {
+	client, transport := newRecordingClient(t)
+	transport.response = `{"data":{"Aggregate":{"MathChunk":[
+		{"groupedBy":{"value":"limits"},"meta":{"count":12}},
+		{"groupedBy":{"value":"derivatives"},"meta":{"count":30}},
+		{"groupedBy":{"value":""},"meta":{"count":4}}
+	]}}}`
+
+	counts, err := client.CountChunksByConcept(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := transport.queries[0]
+	if !strings.Contains(query, "Aggregate") || !strings.Contains(query, `groupBy: "concept"`) {
+		t.Errorf("expected an aggregation grouped by concept, got %s", query)
+	}
+	if counts["limits"] != 12 || counts["derivatives"] != 30 || counts[""] != 4 || len(counts) != 3 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+}
+
+func TestCountChunksNearUsesCertaintyAndLimit(t *testing.T) {
+	client, transport := newRecordingClient(t)
+	transport.response = `{"data":{"Aggregate":{"MathChunk":[{"meta":{"count":7}}]}}}`
+
+	count, err := client.CountChunksNear(context.Background(), "chain rule", 0.8, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 7 {
+		t.Errorf("expected 7 chunks, got %d", count)
+	}
+	query := transport.queries[0]
+	for _, want := range []string{`concepts: ["chain rule"]`, "certainty: 0.8", "objectLimit: 50"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("expected %q in %s", want, query)
+		}
+	}
+}
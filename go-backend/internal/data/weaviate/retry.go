@@ -0,0 +1,89 @@
+package weaviate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/fault"
+	"go.uber.org/zap"
+)
+
+// Defaults used when the config leaves the timeout or backoff unset. Zero
+// retries is a valid setting, so the retry count has no default here.
+const (
+	DefaultOperationTimeout = 10 * time.Second
+	DefaultRetryBackoff     = 200 * time.Millisecond
+)
+
+// retryPolicy bounds each Weaviate call and retries transient failures
+type retryPolicy struct {
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+}
+
+// do runs fn with a per-attempt timeout, retrying transient errors with
+// exponential backoff until maxRetries is exhausted or ctx is done
+func (p retryPolicy) do(ctx context.Context, logger *zap.Logger, operation string, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = p.attempt(ctx, fn)
+		if err == nil || ctx.Err() != nil || !isTransient(err) || attempt >= p.maxRetries {
+			return err
+		}
+
+		delay := p.backoff << attempt
+		logger.Warn("Transient Weaviate error, retrying",
+			zap.String("operation", operation),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (p retryPolicy) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.timeout <= 0 {
+		return fn(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return fn(attemptCtx)
+}
+
+// isTransient reports whether err is worth retrying: network failures,
+// attempt timeouts, rate limiting and 5xx responses. Client errors such as
+// malformed queries are permanent.
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var clientErr *fault.WeaviateClientError
+	if errors.As(err, &clientErr) {
+		if clientErr.IsUnexpectedStatusCode {
+			return clientErr.StatusCode == http.StatusTooManyRequests || clientErr.StatusCode >= 500
+		}
+		if clientErr.DerivedFromError != nil {
+			return isTransient(clientErr.DerivedFromError)
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
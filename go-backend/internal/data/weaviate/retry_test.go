@@ -0,0 +1,100 @@
+package weaviate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/weaviate/weaviate-go-client/v4/weaviate"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/fault"
+	"go.uber.org/zap"
+)
+
+// flakyTransport answers the first request with 503 and later ones with body
+type flakyTransport struct {
+	calls int32
+	body  string
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := http.StatusOK
+	body := t.body
+	if atomic.AddInt32(&t.calls, 1) == 1 {
+		status = http.StatusServiceUnavailable
+		body = `{"error":[{"message":"unavailable"}]}`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestSemanticSearchRetriesTransientFailure(t *testing.T) {
+	transport := &flakyTransport{
+		body: `{"data":{"Get":{"MathChunk":[{"content":"chain rule","concept":"derivatives","chapter":"3","_additional":{"certainty":0.9}}]}}}`,
+	}
+	wc, err := weaviate.NewClient(weaviate.Config{
+		Host:             "weaviate.test",
+		Scheme:           "http",
+		ConnectionClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	client := &Client{
+		client: wc,
+		logger: zap.NewNop(),
+		class:  "MathChunk",
+		retry:  retryPolicy{timeout: time.Second, maxRetries: 2, backoff: time.Millisecond},
+	}
+
+	results, err := client.SemanticSearch(context.Background(), "chain rule", 5)
+	if err != nil {
+		t.Fatalf("SemanticSearch: %v", err)
+	}
+	if len(results) != 1 || results[0].Concept != "derivatives" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if calls := atomic.LoadInt32(&transport.calls); calls != 2 {
+		t.Errorf("expected 2 requests, got %d", calls)
+	}
+}
+
+func TestRetryPolicyStopsOnPermanentError(t *testing.T) {
+	policy := retryPolicy{maxRetries: 3, backoff: time.Millisecond}
+	calls := 0
+	err := policy.do(context.Background(), zap.NewNop(), "test", func(context.Context) error {
+		calls++
+		return &fault.WeaviateClientError{IsUnexpectedStatusCode: true, StatusCode: http.StatusUnprocessableEntity}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("expected a single failed attempt, got %d calls (err=%v)", calls, err)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&fault.WeaviateClientError{IsUnexpectedStatusCode: true, StatusCode: 503}, true},
+		{&fault.WeaviateClientError{IsUnexpectedStatusCode: true, StatusCode: 429}, true},
+		{&fault.WeaviateClientError{IsUnexpectedStatusCode: true, StatusCode: 400}, false},
+		{&fault.WeaviateClientError{DerivedFromError: io.ErrUnexpectedEOF}, true},
+		{context.DeadlineExceeded, true},
+		{errors.New("invalid nearText"), false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.err); got != tt.want {
+			t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
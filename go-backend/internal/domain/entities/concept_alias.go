@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// ConceptAlias is another term students use for a graph concept, such as
+// "ibp" for Integration by Parts. Alias is the key from ConceptAliasKey.
+type ConceptAlias struct {
+	Alias       string    `json:"alias" bson:"_id"`
+	ConceptID   string    `json:"concept_id" bson:"concept_id"`
+	ConceptName string    `json:"concept_name" bson:"concept_name"`
+	AddedBy     string    `json:"added_by,omitempty" bson:"added_by,omitempty"`
+	AddedAt     time.Time `json:"added_at" bson:"added_at"`
+}
+
+// NewConceptAlias creates an alias of a concept
+func NewConceptAlias(alias, conceptID, conceptName, addedBy string) *ConceptAlias {
+	return &ConceptAlias{
+		Alias:       ConceptAliasKey(alias),
+		ConceptID:   conceptID,
+		ConceptName: conceptName,
+		AddedBy:     addedBy,
+		AddedAt:     time.Now(),
+	}
+}
+
+// ConceptAliasKey normalizes an alias so case and spacing variants share
+// one entry
+func ConceptAliasKey(alias string) string {
+	return strings.Join(strings.Fields(strings.ToLower(alias)), " ")
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// ConceptIdentification is the concepts identified in a question, cached
+// under the question's QuestionKey until ExpiresAt
+type ConceptIdentification struct {
+	QuestionKey string    `json:"question_key" bson:"_id"`
+	Concepts    []string  `json:"concepts" bson:"concepts"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" bson:"expires_at"`
+}
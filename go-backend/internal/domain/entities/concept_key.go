@@ -0,0 +1,35 @@
+package entities
+
+import "strings"
+
+// ConceptKey identifies a concept across naming variants, so "Derivatives",
+// "derivative" and the graph ID "derivatives" share one cached explanation.
+// Case, spacing, underscores and hyphens are ignored and a plural last word
+// is made singular; words ending in -ss, -us or -is are already singular.
+func ConceptKey(concept string) string {
+	concept = strings.NewReplacer("_", " ", "-", " ").Replace(strings.ToLower(concept))
+	words := strings.Fields(concept)
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] = singular(words[len(words)-1])
+	return strings.Join(words, " ")
+}
+
+// singular undoes the regular English plural endings. Irregular plurals
+// such as "matrices" are left as they are.
+func singular(word string) string {
+	switch {
+	case len(word) <= 3, word == "series", word == "species":
+		return word
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "sses"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
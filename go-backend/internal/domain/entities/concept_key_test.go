@@ -0,0 +1,26 @@
+package entities
+
+import "testing"
+
+func TestConceptKey(t *testing.T) {
+	tests := map[string]string{
+		"Derivatives":           "derivative",
+		"derivative":            "derivative",
+		"  DERIVATIVES ":        "derivative",
+		"integration_by_parts":  "integration by part",
+		"Integration by Parts":  "integration by part",
+		"Probabilities":         "probability",
+		"Taylor series":         "taylor series",
+		"Calculus":              "calculus",
+		"Hypothesis":            "hypothesis",
+		"Cross-product":         "cross product",
+		"Linear approximations": "linear approximation",
+		"Boxes":                 "box",
+		"":                      "",
+	}
+	for concept, want := range tests {
+		if got := ConceptKey(concept); got != want {
+			t.Errorf("ConceptKey(%q) = %q, want %q", concept, got, want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// WorkedExample is a solved problem illustrating a concept
+type WorkedExample struct {
+	Problem  string `json:"problem" bson:"problem"`
+	Solution string `json:"solution" bson:"solution"`
+}
+
+// ConceptProfile is a sectioned explanation of a concept that the frontend
+// renders section by section. When the LLM reply could not be parsed,
+// Structured is false and RawText carries the unparsed explanation instead.
+type ConceptProfile struct {
+	ConceptID      string          `json:"concept_id" bson:"_id"`
+	ConceptName    string          `json:"concept_name" bson:"concept_name"`
+	Definition     string          `json:"definition,omitempty" bson:"definition,omitempty"`
+	KeyFormulas    []string        `json:"key_formulas" bson:"key_formulas"`
+	WorkedExamples []WorkedExample `json:"worked_examples" bson:"worked_examples"`
+	CommonMistakes []string        `json:"common_mistakes" bson:"common_mistakes"`
+	Applications   []string        `json:"applications" bson:"applications"`
+	Structured     bool            `json:"structured" bson:"structured"`
+	RawText        string          `json:"raw_text,omitempty" bson:"raw_text,omitempty"`
+	LLMModel       string          `json:"llm_model" bson:"llm_model"`
+	GeneratedAt    time.Time       `json:"generated_at" bson:"generated_at"`
+}
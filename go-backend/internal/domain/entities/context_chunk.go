@@ -0,0 +1,48 @@
+package entities
+
+import "github.com/mathprereq/internal/types"
+
+// ContextChunk is one piece of retrieved context stored with a query, kept
+// with its score and origin so answers can be traced to their material
+type ContextChunk struct {
+	Content string `json:"content" bson:"content"`
+	// Score is the vector search certainty, from 0 to 1
+	Score   float64 `json:"score" bson:"score"`
+	Source  string  `json:"source,omitempty" bson:"source,omitempty"`
+	Chapter string  `json:"chapter,omitempty" bson:"chapter,omitempty"`
+	Concept string  `json:"concept,omitempty" bson:"concept,omitempty"`
+	// SourceClass is the vector class searched: textbook chunks or concepts
+	SourceClass string `json:"source_class,omitempty" bson:"source_class,omitempty"`
+}
+
+// NewContextChunks converts vector search results for storage
+func NewContextChunks(results []types.VectorResult) []ContextChunk {
+	chunks := make([]ContextChunk, len(results))
+	for i, result := range results {
+		chunks[i] = ContextChunk{
+			Content:     result.Content,
+			Score:       result.Score,
+			Source:      metadataString(result.Metadata, "source"),
+			Chapter:     metadataString(result.Metadata, "chapter"),
+			Concept:     metadataString(result.Metadata, "concept"),
+			SourceClass: metadataString(result.Metadata, "source_class"),
+		}
+	}
+	return chunks
+}
+
+func metadataString(metadata map[string]interface{}, key string) string {
+	value, _ := metadata[key].(string)
+	return value
+}
+
+// CitedSource is a context chunk the explanation cited; Marker is the
+// context number it was cited by
+type CitedSource struct {
+	Marker      int     `json:"marker" bson:"marker"`
+	Source      string  `json:"source,omitempty" bson:"source,omitempty"`
+	Chapter     string  `json:"chapter,omitempty" bson:"chapter,omitempty"`
+	Concept     string  `json:"concept,omitempty" bson:"concept,omitempty"`
+	SourceClass string  `json:"source_class,omitempty" bson:"source_class,omitempty"`
+	Score       float64 `json:"score" bson:"score"`
+}
@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TrimmedExplanationMarker replaces the middle of an explanation trimmed
+// for storage
+const TrimmedExplanationMarker = "\n\n[… trimmed for storage …]\n\n"
+
+// trimBoundaries are where a trimmed explanation may be cut, best first:
+// paragraphs, lines, sentences, then words. keep is how much of the
+// separator stays with the text before it.
+var trimBoundaries = []struct {
+	sep  string
+	keep int
+}{
+	{"\n\n", 0},
+	{"\n", 0},
+	{". ", 1},
+	{" ", 0},
+}
+
+// TrimExplanation shortens an explanation to at most maxLen characters by
+// dropping its middle, so the introduction and the conclusion, where the
+// final answer usually is, both survive. Each side is cut at the best
+// boundary in its outer half. It reports whether anything was removed;
+// explanations that fit, or a maxLen of zero, are returned unchanged.
+func TrimExplanation(explanation string, maxLen int) (string, bool) {
+	if maxLen <= 0 || utf8.RuneCountInString(explanation) <= maxLen {
+		return explanation, false
+	}
+
+	runes := []rune(explanation)
+	budget := maxLen - utf8.RuneCountInString(TrimmedExplanationMarker)
+	if budget <= 0 {
+		return string(runes[:maxLen]), true
+	}
+	headLen := budget / 2
+	tailLen := budget - headLen
+
+	head := string(runes[:headLen])
+	for _, b := range trimBoundaries {
+		if i := strings.LastIndex(head, b.sep); i > 0 && i >= len(head)/2 {
+			head = head[:i+b.keep]
+			break
+		}
+	}
+
+	tail := string(runes[len(runes)-tailLen:])
+	for _, b := range trimBoundaries {
+		if i := strings.Index(tail, b.sep); i >= 0 && i+len(b.sep) <= len(tail)/2 {
+			tail = tail[i+len(b.sep):]
+			break
+		}
+	}
+
+	return strings.TrimRight(head, " \n") + TrimmedExplanationMarker + strings.TrimLeft(tail, " \n"), true
+}
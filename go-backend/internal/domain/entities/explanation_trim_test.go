@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTrimExplanationKeepsIntroAndConclusion(t *testing.T) {
+	intro := "To differentiate sin(x²) we need the chain rule."
+	conclusion := "Therefore the derivative is 2x·cos(x²)."
+	var body []string
+	for i := 0; i < 40; i++ {
+		body = append(body, "The outer function is differentiated first and the inner one after it.")
+	}
+	explanation := intro + "\n\n" + strings.Join(body, " ") + "\n\n" + conclusion
+
+	trimmed, ok := TrimExplanation(explanation, 400)
+	if !ok {
+		t.Fatal("expected the explanation to be trimmed")
+	}
+	if n := utf8.RuneCountInString(trimmed); n > 400 {
+		t.Errorf("trimmed to %d characters, want at most 400", n)
+	}
+	if !strings.HasPrefix(trimmed, intro) {
+		t.Errorf("trimmed explanation lost its introduction: %q", trimmed)
+	}
+	if !strings.HasSuffix(trimmed, conclusion) {
+		t.Errorf("trimmed explanation lost its conclusion: %q", trimmed)
+	}
+	if !strings.Contains(trimmed, TrimmedExplanationMarker) {
+		t.Errorf("trimmed explanation has no marker: %q", trimmed)
+	}
+	if !strings.HasSuffix(strings.Split(trimmed, TrimmedExplanationMarker)[0], ".") {
+		t.Errorf("head was not cut at a sentence or paragraph boundary: %q", trimmed)
+	}
+}
+
+func TestTrimExplanationLeavesShortOrUnlimited(t *testing.T) {
+	explanation := "The derivative of x² is 2x."
+	for _, maxLen := range []int{0, len(explanation), 1000} {
+		if got, ok := TrimExplanation(explanation, maxLen); ok || got != explanation {
+			t.Errorf("TrimExplanation(%d) = %q, %v, want it unchanged", maxLen, got, ok)
+		}
+	}
+}
+
+func TestTrimExplanationHardCutsBelowMarker(t *testing.T) {
+	got, ok := TrimExplanation(strings.Repeat("x", 50), 10)
+	if !ok || got != strings.Repeat("x", 10) {
+		t.Errorf("TrimExplanation() = %q, %v, want the first 10 characters", got, ok)
+	}
+}
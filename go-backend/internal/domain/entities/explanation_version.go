@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// ExplanationVersion is one generated explanation of a concept. One version
+// per concept is active and served for that concept's cached queries.
+type ExplanationVersion struct {
+	ID          string `json:"id" bson:"_id"`
+	Concept     string `json:"concept" bson:"concept"`
+	Version     int    `json:"version" bson:"version"`
+	QueryID     string `json:"query_id" bson:"query_id"`
+	Explanation string `json:"explanation" bson:"explanation"`
+	LLMProvider string `json:"llm_provider" bson:"llm_provider"`
+	LLMModel    string `json:"llm_model" bson:"llm_model"`
+	Active      bool   `json:"active" bson:"active"`
+	// Pinned marks a version an admin activated; newer versions do not replace it
+	Pinned    bool      `json:"pinned" bson:"pinned"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Formula is a formula extracted from an ingested source chunk, linked to
+// the concepts the chunk covers
+type Formula struct {
+	ID         string   `json:"id" bson:"_id"`
+	Expression string   `json:"expression" bson:"expression"`
+	Display    bool     `json:"display" bson:"display"`
+	Concepts   []string `json:"concepts" bson:"concepts"`
+	// ConceptKeys are the concepts normalized with FormulaConceptKey for lookup
+	ConceptKeys []string  `json:"-" bson:"concept_keys"`
+	Document    string    `json:"document" bson:"document"`
+	Title       string    `json:"title,omitempty" bson:"title,omitempty"`
+	Chapter     string    `json:"chapter,omitempty" bson:"chapter,omitempty"`
+	Page        int       `json:"page,omitempty" bson:"page,omitempty"`
+	ChunkID     string    `json:"chunk_id" bson:"chunk_id"`
+	ExtractedAt time.Time `json:"extracted_at" bson:"extracted_at"`
+}
+
+// NewFormula creates a formula found in a source chunk. The ID is derived
+// from the document and expression, so re-ingesting a source updates its
+// formulas instead of duplicating them.
+func NewFormula(expression string, display bool, concepts []string, document, chunkID string) *Formula {
+	sum := sha256.Sum256([]byte(document + "\x00" + expression))
+	named := make([]string, 0, len(concepts))
+	keys := make([]string, 0, len(concepts))
+	for _, concept := range concepts {
+		if key := FormulaConceptKey(concept); key != "" {
+			named = append(named, concept)
+			keys = append(keys, key)
+		}
+	}
+	return &Formula{
+		ID:          hex.EncodeToString(sum[:16]),
+		Expression:  expression,
+		Display:     display,
+		Concepts:    named,
+		ConceptKeys: keys,
+		Document:    document,
+		ChunkID:     chunkID,
+		ExtractedAt: time.Now(),
+	}
+}
+
+// FormulaConceptKey normalizes a concept name or ID for formula lookup, so
+// "Integration by Parts" and "integration_by_parts" share a key
+func FormulaConceptKey(concept string) string {
+	concept = strings.NewReplacer("_", " ", "-", " ").Replace(strings.ToLower(concept))
+	return strings.Join(strings.Fields(concept), " ")
+}
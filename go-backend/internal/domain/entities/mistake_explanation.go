@@ -0,0 +1,38 @@
+package entities
+
+import "strings"
+
+// MistakeExplanation pinpoints where a student's attempt at a problem goes
+// wrong. When the LLM reply could not be parsed, Structured is false and
+// RawText carries the unparsed explanation instead.
+type MistakeExplanation struct {
+	ErrorStep        string   `json:"error_step,omitempty" bson:"error_step,omitempty"`
+	ErrorType        string   `json:"error_type,omitempty" bson:"error_type,omitempty"`
+	WhatWentWrong    string   `json:"what_went_wrong,omitempty" bson:"what_went_wrong,omitempty"`
+	CorrectApproach  string   `json:"correct_approach,omitempty" bson:"correct_approach,omitempty"`
+	ConceptsToReview []string `json:"concepts_to_review" bson:"concepts_to_review"`
+	Structured       bool     `json:"structured" bson:"structured"`
+	RawText          string   `json:"raw_text,omitempty" bson:"raw_text,omitempty"`
+}
+
+// Text renders the explanation as plain text, for the stored query response
+func (m *MistakeExplanation) Text() string {
+	if !m.Structured {
+		return m.RawText
+	}
+
+	var sections []string
+	if m.ErrorStep != "" {
+		sections = append(sections, "Where it goes wrong: "+m.ErrorStep)
+	}
+	if m.WhatWentWrong != "" {
+		sections = append(sections, m.WhatWentWrong)
+	}
+	if m.CorrectApproach != "" {
+		sections = append(sections, "Correct approach: "+m.CorrectApproach)
+	}
+	if len(m.ConceptsToReview) > 0 {
+		sections = append(sections, "Review: "+strings.Join(m.ConceptsToReview, ", "))
+	}
+	return strings.Join(sections, "\n\n")
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "testing"
+
+func TestMistakeExplanationText(t *testing.T) {
+	structured := &MistakeExplanation{
+		ErrorStep:        "2x = 10",
+		WhatWentWrong:    "You added 3 instead of subtracting it.",
+		ConceptsToReview: []string{"Linear Equations"},
+		Structured:       true,
+	}
+	want := "Where it goes wrong: 2x = 10\n\nYou added 3 instead of subtracting it.\n\nReview: Linear Equations"
+	if got := structured.Text(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	raw := &MistakeExplanation{RawText: "Check your signs."}
+	if got := raw.Text(); got != "Check your signs." {
+		t.Errorf("expected raw text, got %q", got)
+	}
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PathSnapshot is a point-in-time copy of a concept's prerequisite path,
+// used as a baseline to see how the curriculum changed
+type PathSnapshot struct {
+	ID            string            `json:"id" bson:"_id"`
+	ConceptID     string            `json:"concept_id" bson:"concept_id"`
+	Label         string            `json:"label,omitempty" bson:"label,omitempty"`
+	Prerequisites []SnapshotConcept `json:"prerequisites" bson:"prerequisites"`
+	CapturedAt    time.Time         `json:"captured_at" bson:"captured_at"`
+}
+
+type SnapshotConcept struct {
+	ID   string `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+// PathDiff lists prerequisites added or removed since a baseline snapshot
+type PathDiff struct {
+	ConceptID          string            `json:"concept_id"`
+	BaselineID         string            `json:"baseline_id"`
+	BaselineCapturedAt time.Time         `json:"baseline_captured_at"`
+	ComparedAt         time.Time         `json:"compared_at"`
+	Added              []SnapshotConcept `json:"added"`
+	Removed            []SnapshotConcept `json:"removed"`
+	UnchangedCount     int               `json:"unchanged_count"`
+}
+
+func NewPathSnapshot(conceptID, label string, prerequisites []SnapshotConcept) *PathSnapshot {
+	return &PathSnapshot{
+		ID:            uuid.New().String(),
+		ConceptID:     conceptID,
+		Label:         label,
+		Prerequisites: prerequisites,
+		CapturedAt:    time.Now(),
+	}
+}
+
+// DiffPathSnapshots compares two snapshots of the same concept by prerequisite ID
+func DiffPathSnapshots(baseline, current *PathSnapshot) *PathDiff {
+	diff := &PathDiff{
+		ConceptID:          baseline.ConceptID,
+		BaselineID:         baseline.ID,
+		BaselineCapturedAt: baseline.CapturedAt,
+		ComparedAt:         current.CapturedAt,
+		Added:              []SnapshotConcept{},
+		Removed:            []SnapshotConcept{},
+	}
+
+	before := make(map[string]bool, len(baseline.Prerequisites))
+	for _, c := range baseline.Prerequisites {
+		before[c.ID] = true
+	}
+	after := make(map[string]bool, len(current.Prerequisites))
+	for _, c := range current.Prerequisites {
+		after[c.ID] = true
+	}
+
+	for _, c := range current.Prerequisites {
+		if before[c.ID] {
+			diff.UnchangedCount++
+		} else {
+			diff.Added = append(diff.Added, c)
+		}
+	}
+	for _, c := range baseline.Prerequisites {
+		if !after[c.ID] {
+			diff.Removed = append(diff.Removed, c)
+		}
+	}
+
+	return diff
+}
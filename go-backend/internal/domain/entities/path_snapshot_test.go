@@ -0,0 +1,49 @@
+package entities
+
+import "testing"
+
+func TestDiffPathSnapshotsDetectsAddedEdge(t *testing.T) {
+	baseline := NewPathSnapshot("derivatives", "before", []SnapshotConcept{
+		{ID: "functions", Name: "Functions"},
+		{ID: "limits", Name: "Limits"},
+	})
+	current := NewPathSnapshot("derivatives", "", []SnapshotConcept{
+		{ID: "functions", Name: "Functions"},
+		{ID: "limits", Name: "Limits"},
+		{ID: "continuity", Name: "Continuity"},
+	})
+
+	diff := DiffPathSnapshots(baseline, current)
+
+	if len(diff.Added) != 1 || diff.Added[0].ID != "continuity" {
+		t.Errorf("expected continuity to be added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 0 {
+		t.Errorf("expected nothing removed, got %+v", diff.Removed)
+	}
+	if diff.UnchangedCount != 2 {
+		t.Errorf("expected 2 unchanged prerequisites, got %d", diff.UnchangedCount)
+	}
+	if diff.BaselineID != baseline.ID {
+		t.Errorf("expected baseline id %s, got %s", baseline.ID, diff.BaselineID)
+	}
+}
+
+func TestDiffPathSnapshotsDetectsRemovedEdge(t *testing.T) {
+	baseline := NewPathSnapshot("integration", "", []SnapshotConcept{
+		{ID: "derivatives", Name: "Derivatives"},
+		{ID: "limits", Name: "Limits"},
+	})
+	current := NewPathSnapshot("integration", "", []SnapshotConcept{
+		{ID: "derivatives", Name: "Derivatives"},
+	})
+
+	diff := DiffPathSnapshots(baseline, current)
+
+	if len(diff.Removed) != 1 || diff.Removed[0].ID != "limits" {
+		t.Errorf("expected limits to be removed, got %+v", diff.Removed)
+	}
+	if len(diff.Added) != 0 {
+		t.Errorf("expected nothing added, got %+v", diff.Added)
+	}
+}
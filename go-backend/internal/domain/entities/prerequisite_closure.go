@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// PrerequisiteClosure is the full transitive prerequisite set of a concept,
+// precomputed from the graph so readiness checks skip the path traversal
+type PrerequisiteClosure struct {
+	ConceptID       string    `json:"concept_id" bson:"_id"`
+	PrerequisiteIDs []string  `json:"prerequisite_ids" bson:"prerequisite_ids"`
+	ComputedAt      time.Time `json:"computed_at" bson:"computed_at"`
+}
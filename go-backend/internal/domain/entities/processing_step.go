@@ -0,0 +1,29 @@
+package entities
+
+// Data sources the query pipeline steps wait on, for performance breakdowns
+const (
+	SourceLLM      = "llm"
+	SourceNeo4j    = "neo4j"
+	SourceVectorDB = "vector_db"
+	SourceMongoDB  = "mongodb"
+	SourceInternal = "internal" // in-process work with no external call
+)
+
+var stepSources = map[string]string{
+	"identify_concepts": SourceLLM,
+	// A question whose concepts were read from the identification cache
+	"identify_concepts_cached": SourceMongoDB,
+	"find_prerequisites":       SourceNeo4j,
+	"vector_search":            SourceVectorDB,
+	"generate_explanation":     SourceLLM,
+	"explain_mistake":          SourceLLM,
+	"verify_arithmetic":        SourceInternal,
+}
+
+// StepSource returns the data source a processing step waits on
+func StepSource(step string) string {
+	if source, ok := stepSources[step]; ok {
+		return source
+	}
+	return SourceInternal
+}
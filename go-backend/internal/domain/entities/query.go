@@ -3,6 +3,7 @@ package entities
 import (
     "time"
     "github.com/google/uuid"
+    "github.com/mathprereq/internal/arithmetic"
     "github.com/mathprereq/internal/types"
 )
 
@@ -10,8 +11,30 @@ type Query struct {
     ID                 string                `json:"id" bson:"_id"`
     UserID             string                `json:"user_id,omitempty" bson:"user_id,omitempty"`
     Text               string                `json:"text" bson:"text"`
+    Language           string                `json:"language,omitempty" bson:"language,omitempty"`
+    // Category is the subject area of the identified concepts, for analytics
+    Category           string                `json:"category,omitempty" bson:"category,omitempty"`
+    // QuestionKey is QuestionKey(Text), for answering repeated questions from cache
+    QuestionKey        string                `json:"question_key,omitempty" bson:"question_key,omitempty"`
+    // ConceptKey is the ConceptKey of the concept a concept query explains,
+    // for serving naming variants of the concept from one cached answer
+    ConceptKey         string                `json:"concept_key,omitempty" bson:"concept_key,omitempty"`
+    // Kind is empty for questions and QueryKindMistake for explained mistakes
+    Kind               string                `json:"kind,omitempty" bson:"kind,omitempty"`
+    // Attempt is the student's work on the problem in Text, for mistakes
+    Attempt            string                `json:"attempt,omitempty" bson:"attempt,omitempty"`
+    // Experiment is the A/B experiment variant that answered the query, if any
+    Experiment         *QueryExperiment      `json:"experiment,omitempty" bson:"experiment,omitempty"`
+    // Preferences are the asking user's stored explanation preferences, set
+    // only when the explanation was tailored to them
+    Preferences        *UserPreferences      `json:"preferences,omitempty" bson:"preferences,omitempty"`
+    // ModelOverride is the model a tester forced for this query, if any
+    ModelOverride      string                `json:"model_override,omitempty" bson:"model_override,omitempty"`
     IdentifiedConcepts []string              `json:"identified_concepts" bson:"identified_concepts"`
     PrerequisitePath   []types.Concept       `json:"prerequisite_path" bson:"prerequisite_path"`
+    // SuggestedPath is an LLM-suggested prerequisite ordering, set only when
+    // the curated PrerequisitePath was empty and never merged into it
+    SuggestedPath      []types.Concept       `json:"suggested_path,omitempty" bson:"suggested_path,omitempty"`
     Response           QueryResponse         `json:"response" bson:"response"`
     Timestamp          time.Time             `json:"timestamp" bson:"timestamp"`
     ProcessingTimeMs   int64                 `json:"processing_time_ms" bson:"processing_time_ms"`
@@ -22,12 +45,37 @@ type Query struct {
 
 type QueryResponse struct {
     Explanation      string   `json:"explanation" bson:"explanation"`
+    // ExplanationTrimmed marks a stored explanation whose middle was dropped
+    // to fit ANALYTICS_MAX_STORED_EXPLANATION; the client got it in full
+    ExplanationTrimmed bool   `json:"explanation_trimmed,omitempty" bson:"explanation_trimmed,omitempty"`
     RetrievedContext []string `json:"retrieved_context" bson:"retrieved_context"`
+    // ContextChunks are the retrieved chunks with their scores and sources
+    ContextChunks    []ContextChunk `json:"context_chunks,omitempty" bson:"context_chunks,omitempty"`
+    // SourcesUsed are the context chunks the explanation cited, in citation order
+    SourcesUsed      []CitedSource `json:"sources_used,omitempty" bson:"sources_used,omitempty"`
     LLMProvider      string   `json:"llm_provider" bson:"llm_provider"`
     LLMModel         string   `json:"llm_model" bson:"llm_model"`
     TokensUsed       int      `json:"tokens_used" bson:"tokens_used"`
+    // Fallback marks a templated explanation served because the LLM failed
+    Fallback         bool     `json:"fallback,omitempty" bson:"fallback,omitempty"`
+    // ModelProfile is the fast or deep profile the query was routed to
+    ModelProfile     string   `json:"model_profile,omitempty" bson:"model_profile,omitempty"`
+    // ArithmeticCheck is set when numeric steps of the explanation were verified
+    ArithmeticCheck  *arithmetic.Report `json:"arithmetic_check,omitempty" bson:"arithmetic_check,omitempty"`
+    // Mistake is the structured explanation of a QueryKindMistake query
+    Mistake          *MistakeExplanation `json:"mistake,omitempty" bson:"mistake,omitempty"`
 }
 
+// QueryExperiment tags a query with the experiment variant it was assigned
+type QueryExperiment struct {
+    Name    string `json:"name" bson:"name"`
+    Variant string `json:"variant" bson:"variant"`
+}
+
+// QueryKindMistake marks a query that explained a student's mistake rather
+// than answered a question
+const QueryKindMistake = "mistake"
+
 type QueryMetadata struct {
     VectorHits        int               `json:"vector_hits" bson:"vector_hits"`
     GraphHits         int               `json:"graph_hits" bson:"graph_hits"`
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// QuestionKey identifies questions that differ only in case, spacing or
+// sentence punctuation, so repeated questions can share a cached answer.
+// Decimal points and separators between digits are kept, as are math
+// symbols, so "3.5" and "35" or "x^2" and "x2" stay distinct.
+func QuestionKey(question string) string {
+	sum := sha256.Sum256([]byte(NormalizeQuestion(question)))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeQuestion lowercases question, drops sentence punctuation and
+// collapses whitespace
+func NormalizeQuestion(question string) string {
+	runes := []rune(strings.ToLower(question))
+	var b strings.Builder
+	for i, r := range runes {
+		switch r {
+		case '.', ',':
+			if i > 0 && i < len(runes)-1 && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]) {
+				b.WriteRune(r)
+				continue
+			}
+			b.WriteRune(' ')
+		case '?', '!', ';', ':', '"', '\'', '`', '¿', '¡', '“', '”', '‘', '’':
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "testing"
+
+func TestNormalizeQuestion(t *testing.T) {
+	tests := map[string]string{
+		"  What is the Derivative of x^2?? ": "what is the derivative of x^2",
+		"What's 3.5 + 1,000, exactly?":       "what s 3.5 + 1,000 exactly",
+		"¿Qué es un límite?":                 "qué es un límite",
+		"Solve:\n\t2x + 3 = 7.":              "solve 2x + 3 = 7",
+	}
+	for question, want := range tests {
+		if got := NormalizeQuestion(question); got != want {
+			t.Errorf("NormalizeQuestion(%q) = %q, want %q", question, got, want)
+		}
+	}
+}
+
+func TestQuestionKey(t *testing.T) {
+	if QuestionKey("What is a limit?") != QuestionKey("  what IS a limit ") {
+		t.Error("expected questions differing in case, spacing and punctuation to share a key")
+	}
+	if QuestionKey("What is 3.5 squared?") == QuestionKey("What is 35 squared?") {
+		t.Error("expected decimal points to be significant")
+	}
+	if QuestionKey("Differentiate x^2") == QuestionKey("Differentiate x2") {
+		t.Error("expected math symbols to be significant")
+	}
+}
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scrape job statuses
+const (
+	ScrapeJobPending = "pending"
+	ScrapeJobRunning = "running"
+	ScrapeJobDone    = "done"
+	ScrapeJobFailed  = "failed"
+)
+
+// ScrapeJob is a persisted request to scrape resources for a set of
+// concepts. Workers claim a job by leasing it; a job whose lease runs out
+// before it completes is handed to another worker, so every job runs at
+// least once even across restarts.
+type ScrapeJob struct {
+	ID       string   `json:"id" bson:"_id"`
+	Concepts []string `json:"concepts" bson:"concepts"`
+	// Source names what queued the job, e.g. "scrape_resources"
+	Source  string `json:"source" bson:"source"`
+	QueryID string `json:"query_id,omitempty" bson:"query_id,omitempty"`
+	Status  string `json:"status" bson:"status"`
+	// Attempts counts claims, including the one running now
+	Attempts    int       `json:"attempts" bson:"attempts"`
+	MaxAttempts int       `json:"max_attempts" bson:"max_attempts"`
+	AvailableAt time.Time `json:"available_at" bson:"available_at"`
+	// LockedBy and LockedUntil hold the lease of the worker running the job
+	LockedBy    string     `json:"locked_by,omitempty" bson:"locked_by,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty" bson:"locked_until,omitempty"`
+	LastError   string     `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// NewScrapeJob creates a pending job that can be claimed right away
+func NewScrapeJob(source, queryID string, concepts []string, maxAttempts int) *ScrapeJob {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	now := time.Now()
+	return &ScrapeJob{
+		ID:          uuid.New().String(),
+		Concepts:    concepts,
+		Source:      source,
+		QueryID:     queryID,
+		Status:      ScrapeJobPending,
+		MaxAttempts: maxAttempts,
+		AvailableAt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Exhausted reports whether the job may not be claimed again
+func (j *ScrapeJob) Exhausted() bool {
+	return j.Attempts >= j.MaxAttempts
+}
@@ -1,6 +1,8 @@
 package entities
 
 import (
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +22,15 @@ type StagedConcept struct {
 	SuggestedDifficulty    int      `json:"suggested_difficulty" bson:"suggested_difficulty"`
 	SuggestedCategory      string   `json:"suggested_category" bson:"suggested_category"`
 	LLMReasoning           string   `json:"llm_reasoning" bson:"llm_reasoning"`
+	// LLMConfidence (0-1) is how sure the model was the concept belongs
+	LLMConfidence float64 `json:"llm_confidence" bson:"llm_confidence"`
+
+	// Suggested prerequisites mapped onto existing concepts by embedding similarity
+	PrerequisiteMatches []PrerequisiteMatch `json:"prerequisite_matches,omitempty" bson:"prerequisite_matches,omitempty"`
+
+	// Candidate prerequisite edges from the LLM-suggested path of a query the
+	// graph had no path for, most foundational first
+	PathSuggestedPrerequisites []string `json:"path_suggested_prerequisites,omitempty" bson:"path_suggested_prerequisites,omitempty"`
 
 	// Validation status
 	Status      StagedConceptStatus `json:"status" bson:"status"`
@@ -32,10 +43,25 @@ type StagedConcept struct {
 	OccurrenceCount int      `json:"occurrence_count" bson:"occurrence_count"` // How many times this concept appeared
 	RelatedQueryIDs []string `json:"related_query_ids" bson:"related_query_ids"`
 
+	// DemandScore is the occurrence count with older occurrences weighted
+	// down, as of DemandUpdatedAt. Pending review is ordered by it.
+	DemandScore     float64   `json:"demand_score" bson:"demand_score"`
+	DemandUpdatedAt time.Time `json:"demand_updated_at" bson:"demand_updated_at"`
+
 	// If approved, this links to the actual concept created
 	ApprovedConceptID string `json:"approved_concept_id,omitempty" bson:"approved_concept_id,omitempty"`
 }
 
+// PrerequisiteMatch links a free-text prerequisite suggestion to the nearest
+// existing concept. MatchedConceptID is empty when nothing cleared the
+// similarity threshold.
+type PrerequisiteMatch struct {
+	Suggestion         string  `json:"suggestion" bson:"suggestion"`
+	MatchedConceptID   string  `json:"matched_concept_id,omitempty" bson:"matched_concept_id,omitempty"`
+	MatchedConceptName string  `json:"matched_concept_name,omitempty" bson:"matched_concept_name,omitempty"`
+	Similarity         float64 `json:"similarity" bson:"similarity"`
+}
+
 type StagedConceptStatus string
 
 const (
@@ -57,13 +83,14 @@ func NewStagedConcept(
 	suggestedCategory string,
 	llmReasoning string,
 ) *StagedConcept {
+	now := time.Now()
 	return &StagedConcept{
 		ID:                     uuid.New().String(),
 		ConceptName:            conceptName,
 		Description:            description,
 		SourceQueryID:          sourceQueryID,
 		SourceQueryText:        sourceQueryText,
-		IdentifiedAt:           time.Now(),
+		IdentifiedAt:           now,
 		SuggestedPrerequisites: suggestedPrereqs,
 		SuggestedDifficulty:    suggestedDifficulty,
 		SuggestedCategory:      suggestedCategory,
@@ -72,6 +99,8 @@ func NewStagedConcept(
 		SubmittedBy:            submittedBy,
 		OccurrenceCount:        1,
 		RelatedQueryIDs:        []string{sourceQueryID},
+		DemandScore:            1,
+		DemandUpdatedAt:        now,
 	}
 }
 
@@ -104,8 +133,53 @@ func (sc *StagedConcept) Merge(reviewerID string, notes string, existingConceptI
 	sc.ApprovedConceptID = existingConceptID
 }
 
-// IncrementOccurrence increments the occurrence count
-func (sc *StagedConcept) IncrementOccurrence(queryID string) {
+// MatchedPrerequisiteID returns the existing concept ID matched to a suggested
+// prerequisite, or an empty string if the suggestion was not matched
+func (sc *StagedConcept) MatchedPrerequisiteID(suggestion string) string {
+	for _, match := range sc.PrerequisiteMatches {
+		if match.Suggestion == suggestion {
+			return match.MatchedConceptID
+		}
+	}
+	return ""
+}
+
+// AddPathSuggestedPrerequisites records prerequisites from an LLM-suggested
+// path, skipping ones already recorded in any casing
+func (sc *StagedConcept) AddPathSuggestedPrerequisites(names []string) {
+	seen := make(map[string]bool, len(sc.PathSuggestedPrerequisites))
+	for _, name := range sc.PathSuggestedPrerequisites {
+		seen[strings.ToLower(name)] = true
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if key := strings.ToLower(name); key != "" && !seen[key] {
+			seen[key] = true
+			sc.PathSuggestedPrerequisites = append(sc.PathSuggestedPrerequisites, name)
+		}
+	}
+}
+
+// IncrementOccurrence increments the occurrence count and adds the new
+// occurrence to the demand score, aged first by halfLife
+func (sc *StagedConcept) IncrementOccurrence(queryID string, halfLife time.Duration) {
+	sc.DecayDemand(time.Now(), halfLife)
 	sc.OccurrenceCount++
+	sc.DemandScore++
 	sc.RelatedQueryIDs = append(sc.RelatedQueryIDs, queryID)
 }
+
+// DecayDemand ages the demand score to now, halving it every halfLife. A
+// zero halfLife disables decay, leaving the score equal to the count.
+func (sc *StagedConcept) DecayDemand(now time.Time, halfLife time.Duration) {
+	if sc.DemandUpdatedAt.IsZero() {
+		// Stored before demand was tracked; treat every occurrence as first seen
+		sc.DemandScore = float64(sc.OccurrenceCount)
+		sc.DemandUpdatedAt = sc.IdentifiedAt
+	}
+	if halfLife > 0 && now.After(sc.DemandUpdatedAt) {
+		elapsed := now.Sub(sc.DemandUpdatedAt)
+		sc.DemandScore *= math.Exp2(-float64(elapsed) / float64(halfLife))
+	}
+	sc.DemandUpdatedAt = now
+}
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIncrementOccurrenceAgesDemandFirst(t *testing.T) {
+	day := 24 * time.Hour
+	c := NewStagedConcept("limits", "", "q1", "", "", nil, 1, "", "")
+	c.OccurrenceCount, c.DemandScore = 8, 8
+	c.DemandUpdatedAt = time.Now().Add(-14 * day)
+
+	c.IncrementOccurrence("q2", 14*day)
+	if c.OccurrenceCount != 9 {
+		t.Errorf("expected count 9, got %d", c.OccurrenceCount)
+	}
+	if c.DemandScore < 4.99 || c.DemandScore > 5.01 {
+		t.Errorf("expected half of 8 plus 1, got %v", c.DemandScore)
+	}
+}
+
+func TestDecayDemandBackfillsUntrackedConcepts(t *testing.T) {
+	c := &StagedConcept{OccurrenceCount: 3, IdentifiedAt: time.Now().Add(-30 * 24 * time.Hour)}
+
+	// Without a half-life the score tracks the count
+	c.IncrementOccurrence("q3", 0)
+	if c.DemandScore != 4 {
+		t.Errorf("expected undecayed score 4, got %v", c.DemandScore)
+	}
+	if c.DemandUpdatedAt.IsZero() {
+		t.Error("expected the demand timestamp to be set")
+	}
+}
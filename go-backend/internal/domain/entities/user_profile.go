@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Explanation levels a user can prefer
+const (
+	ExplanationLevelBeginner     = "beginner"
+	ExplanationLevelIntermediate = "intermediate"
+	ExplanationLevelAdvanced     = "advanced"
+)
+
+// Explanation styles a user can prefer
+const (
+	ExplanationStyleConcise    = "concise"
+	ExplanationStyleStepByStep = "step_by_step"
+	ExplanationStyleIntuitive  = "intuitive"
+)
+
+// UserPreferences tailor the explanations of a user's queries. Empty fields
+// keep the default explanation.
+type UserPreferences struct {
+	ExplanationLevel string `json:"explanation_level,omitempty" bson:"explanation_level,omitempty"`
+	ExplanationStyle string `json:"explanation_style,omitempty" bson:"explanation_style,omitempty"`
+}
+
+// UserProfile holds what is stored about a user between queries
+type UserProfile struct {
+	UserID      string          `json:"user_id" bson:"_id"`
+	Preferences UserPreferences `json:"preferences" bson:"preferences"`
+	UpdatedAt   time.Time       `json:"updated_at" bson:"updated_at"`
+}
+
+// IsZero reports whether no preference is set
+func (p UserPreferences) IsZero() bool {
+	return p.ExplanationLevel == "" && p.ExplanationStyle == ""
+}
+
+// Normalize lowercases and trims the preferences and checks they are known
+func (p UserPreferences) Normalize() (UserPreferences, error) {
+	p.ExplanationLevel = strings.ToLower(strings.TrimSpace(p.ExplanationLevel))
+	p.ExplanationStyle = strings.ToLower(strings.TrimSpace(p.ExplanationStyle))
+
+	switch p.ExplanationLevel {
+	case "", ExplanationLevelBeginner, ExplanationLevelIntermediate, ExplanationLevelAdvanced:
+	default:
+		return p, fmt.Errorf("unsupported explanation level: %q", p.ExplanationLevel)
+	}
+	switch p.ExplanationStyle {
+	case "", ExplanationStyleConcise, ExplanationStyleStepByStep, ExplanationStyleIntuitive:
+	default:
+		return p, fmt.Errorf("unsupported explanation style: %q", p.ExplanationStyle)
+	}
+	return p, nil
+}
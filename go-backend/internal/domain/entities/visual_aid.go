@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VisualAid is a generated plot attached to a query's explanation
+type VisualAid struct {
+	ID         string    `json:"id" bson:"_id"`
+	QueryID    string    `json:"query_id" bson:"query_id"`
+	Index      int       `json:"index" bson:"index"`
+	Title      string    `json:"title" bson:"title"`
+	Expression string    `json:"expression" bson:"expression"`
+	XMin       float64   `json:"x_min" bson:"x_min"`
+	XMax       float64   `json:"x_max" bson:"x_max"`
+	URL        string    `json:"url" bson:"-"`
+	ImagePNG   []byte    `json:"-" bson:"image_png"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
+
+// NewVisualAid creates a visual aid for the n-th plot of a query
+func NewVisualAid(queryID string, index int, title, expression string, xMin, xMax float64, image []byte) *VisualAid {
+	return &VisualAid{
+		ID:         uuid.New().String(),
+		QueryID:    queryID,
+		Index:      index,
+		Title:      title,
+		Expression: expression,
+		XMin:       xMin,
+		XMax:       xMax,
+		URL:        VisualAidURL(queryID, index),
+		ImagePNG:   image,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// VisualAidURL is the API path serving the n-th visual of a query
+func VisualAidURL(queryID string, index int) string {
+	return fmt.Sprintf("/api/v1/queries/%s/visuals/%d", queryID, index)
+}
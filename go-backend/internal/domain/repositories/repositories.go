@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/mathprereq/internal/domain/entities"
@@ -9,27 +10,81 @@ import (
 )
 
 type ConceptRepository interface {
+	// FindByID returns nil, nil for an ID not in the graph
 	FindByID(ctx context.Context, id string) (*types.Concept, error)
 	FindByName(ctx context.Context, name string) (*types.Concept, error)
 	GetAll(ctx context.Context) ([]types.Concept, error)
+	// List returns one page of concepts matching filter and the total match count
+	List(ctx context.Context, filter ConceptFilter) ([]types.Concept, int, error)
 	FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error)
+	// FindPrerequisitePaths returns the path of each target concept ID as
+	// FindPrerequisitePath would for that target alone, in one query. IDs
+	// not in the graph are missing from the result.
+	FindPrerequisitePaths(ctx context.Context, conceptIDs []string) (map[string][]types.Concept, error)
+	// FindDependentConcepts returns up to limit concepts the concept is a
+	// direct prerequisite for, most essential first
+	FindDependentConcepts(ctx context.Context, conceptID string, limit int) ([]types.Concept, error)
 	GetConceptDetail(ctx context.Context, conceptID string) (*types.ConceptDetailResult, error)
+	GetNeighborhood(ctx context.Context, conceptID string, depth, maxNodes int) (*types.ConceptNeighborhood, error)
+	GetEdgesAmong(ctx context.Context, conceptIDs []string) ([]types.NeighborhoodEdge, error)
 	GetStats(ctx context.Context) (*types.SystemStats, error)
 	IsHealthy(ctx context.Context) bool
 	CreateConcept(ctx context.Context, concept *types.Concept) error
-	CreatePrerequisiteRelationship(ctx context.Context, conceptID, prerequisiteID string) error
+	// CreatePrerequisiteRelationship links a concept to a prerequisite with
+	// a weight between 0 and 1 saying how essential the prerequisite is
+	CreatePrerequisiteRelationship(ctx context.Context, conceptID, prerequisiteID string, weight float64) error
+	// SetPrerequisiteWeight changes the weight of an existing prerequisite
+	// relationship, reporting whether there was one
+	SetPrerequisiteWeight(ctx context.Context, conceptID, prerequisiteID string, weight float64) (bool, error)
 	ExistsByName(ctx context.Context, name string) (bool, error)
+	// UpdateDescription sets a concept's description only if it is still blank,
+	// reporting whether the concept was updated
+	UpdateDescription(ctx context.Context, conceptID, description string) (bool, error)
+	// GetPrerequisiteEdges returns every prerequisite relationship in the graph
+	GetPrerequisiteEdges(ctx context.Context) ([]types.NeighborhoodEdge, error)
+	// MergeConcepts rewires the source concept's prerequisite edges to the
+	// target, skipping duplicates and cycles, and deletes the source
+	MergeConcepts(ctx context.Context, sourceID, targetID string) (*types.ConceptMergeResult, error)
 }
 
 type QueryRepository interface {
 	Save(ctx context.Context, query *entities.Query) error
+	// RecordUnsampled counts a query that was not stored in full so aggregate stats stay accurate
+	RecordUnsampled(ctx context.Context, query *entities.Query) error
 	FindByID(ctx context.Context, id string) (*entities.Query, error)
+	// FindByUserID returns a user's queries, newest first; userID must be set
 	FindByUserID(ctx context.Context, userID string, limit int) ([]*entities.Query, error)
+	// List returns one page of queries matching filter, newest first, and
+	// the total match count. An empty UserID matches every user.
+	List(ctx context.Context, filter QueryListFilter) ([]*entities.Query, int, error)
 	FindByConceptName(ctx context.Context, conceptName string) (*entities.Query, error)
+	// FindByConceptKey returns the newest successful, untailored concept
+	// query stored under the given entities.ConceptKey
+	FindByConceptKey(ctx context.Context, conceptKey string) (*entities.Query, error)
+	// FindByQuestionKey returns the newest successful, non-fallback answer to
+	// a question with the given key and language asked since the cutoff
+	FindByQuestionKey(ctx context.Context, questionKey, language string, since time.Time) (*entities.Query, error)
 	GetAnalytics(ctx context.Context, filters AnalyticsFilter) (*QueryAnalytics, error)
-	GetPopularConcepts(ctx context.Context, limit int) ([]ConceptPopularity, error)
+	// GetPopularConcepts returns the concepts identified in the most queries.
+	// A positive halfLife ranks them by Score instead, each query counting
+	// half as much for every halfLife since it was asked.
+	GetPopularConcepts(ctx context.Context, limit int, halfLife time.Duration) ([]ConceptPopularity, error)
+	// GetConceptPairs returns the concept pairs most often identified in the same query
+	GetConceptPairs(ctx context.Context, limit int) ([]ConceptPair, error)
 	GetQueryTrends(ctx context.Context, days int) ([]QueryTrend, error)
 	GetQueryStats(ctx context.Context) (*QueryStats, error)
+	// GetStepDurations returns the recorded durations of each processing step
+	// of queries stored between since and until
+	GetStepDurations(ctx context.Context, since, until time.Time) ([]StepDurations, error)
+	// GetConceptOutcomes counts, per identified concept, the queries asked
+	// since the cutoff and how many of them were answered successfully
+	GetConceptOutcomes(ctx context.Context, since time.Time) ([]ConceptOutcome, error)
+	// GetQueryConcepts returns the text and identified concepts of up to
+	// limit successful questions asked since the cutoff, newest first
+	GetQueryConcepts(ctx context.Context, since time.Time, limit int) ([]QueryConcepts, error)
+	// GetExperimentOutcomes counts the stored queries of each variant of an
+	// experiment and how they went
+	GetExperimentOutcomes(ctx context.Context, experiment string) ([]ExperimentOutcome, error)
 	IsHealthy(ctx context.Context) bool
 }
 
@@ -43,10 +98,156 @@ type ResourceRepository interface {
 
 type VectorRepository interface {
 	Search(ctx context.Context, query string, limit int) ([]types.VectorResult, error)
+	// SearchByConcepts searches near several concepts at once instead of one query text
+	SearchByConcepts(ctx context.Context, concepts []string, limit int) ([]types.VectorResult, error)
+	// SearchConceptVectors searches concept descriptions instead of textbook chunks
+	SearchConceptVectors(ctx context.Context, terms []string, limit int) ([]types.VectorResult, error)
+	// DeleteConceptVector removes a concept's description vector, if stored
+	DeleteConceptVector(ctx context.Context, conceptID string) error
+	// CountChunksByConcept counts textbook chunks by the concept they are tagged with
+	CountChunksByConcept(ctx context.Context) (map[string]int, error)
+	// CountChunksNear counts textbook chunks at least certainty close to a term, up to maxChunks
+	CountChunksNear(ctx context.Context, term string, certainty float64, maxChunks int) (int, error)
 	IsHealthy(ctx context.Context) bool
 	GetStats(ctx context.Context) (map[string]interface{}, error)
 }
 
+type VisualAidRepository interface {
+	// Save stores a rendered visual aid
+	Save(ctx context.Context, visual *entities.VisualAid) error
+
+	// FindByQueryAndIndex returns the n-th visual aid of a query, or nil if missing
+	FindByQueryAndIndex(ctx context.Context, queryID string, index int) (*entities.VisualAid, error)
+}
+
+type ConceptIdentificationRepository interface {
+	// Save stores an identification, replacing any for the same question
+	Save(ctx context.Context, identification *entities.ConceptIdentification) error
+
+	// FindByQuestionKey returns the unexpired identification of a question, or nil if missing
+	FindByQuestionKey(ctx context.Context, questionKey string) (*entities.ConceptIdentification, error)
+}
+
+type PathSnapshotRepository interface {
+	// Save stores a prerequisite path snapshot
+	Save(ctx context.Context, snapshot *entities.PathSnapshot) error
+
+	// FindByID returns a snapshot by ID, or nil if missing
+	FindByID(ctx context.Context, id string) (*entities.PathSnapshot, error)
+
+	// FindLatestByConcept returns the most recent snapshot of a concept, or nil if none
+	FindLatestByConcept(ctx context.Context, conceptID string) (*entities.PathSnapshot, error)
+
+	// DeleteByConcept removes every snapshot of a concept, returning how many
+	DeleteByConcept(ctx context.Context, conceptID string) (int64, error)
+}
+
+type ConceptProfileRepository interface {
+	// Save stores a concept profile, replacing any previous one for the concept
+	Save(ctx context.Context, profile *entities.ConceptProfile) error
+
+	// FindByConceptID returns the stored profile of a concept, or nil if missing
+	FindByConceptID(ctx context.Context, conceptID string) (*entities.ConceptProfile, error)
+
+	// Delete removes a concept's stored profile, if any
+	Delete(ctx context.Context, conceptID string) error
+}
+
+type UserProfileRepository interface {
+	// Save stores a user profile, replacing any previous one for the user
+	Save(ctx context.Context, profile *entities.UserProfile) error
+
+	// FindByUserID returns the stored profile of a user, or nil if missing
+	FindByUserID(ctx context.Context, userID string) (*entities.UserProfile, error)
+}
+
+type FormulaRepository interface {
+	// SaveBatch stores extracted formulas, replacing any with the same ID
+	SaveBatch(ctx context.Context, formulas []*entities.Formula) error
+
+	// FindByConcept returns formulas linked to a concept key, most recently
+	// extracted first
+	FindByConcept(ctx context.Context, conceptKey string, limit int) ([]*entities.Formula, error)
+}
+
+type ConceptAliasRepository interface {
+	// Save stores an alias, replacing any alias with the same key
+	Save(ctx context.Context, alias *entities.ConceptAlias) error
+
+	// FindAll returns every alias, ordered by alias
+	FindAll(ctx context.Context) ([]*entities.ConceptAlias, error)
+}
+
+// ErrScrapeJobLeaseLost is returned when a worker finishes a scrape job it
+// no longer holds, because its lease ran out and the job was reclaimed
+var ErrScrapeJobLeaseLost = errors.New("scrape job lease lost")
+
+type ScrapeJobRepository interface {
+	// Enqueue stores a new pending job
+	Enqueue(ctx context.Context, job *entities.ScrapeJob) error
+
+	// Claim leases the next available job to workerID until now+lease and
+	// counts the attempt. Pending jobs whose retry time has come and running
+	// jobs whose lease ran out are available. Returns nil when none are.
+	Claim(ctx context.Context, workerID string, lease time.Duration) (*entities.ScrapeJob, error)
+
+	// Complete marks a job workerID holds as done
+	Complete(ctx context.Context, jobID, workerID string) error
+
+	// Retry returns a job workerID holds to the queue, available again at
+	// availableAt
+	Retry(ctx context.Context, jobID, workerID string, availableAt time.Time, lastErr string) error
+
+	// Fail marks a job workerID holds as failed for good
+	Fail(ctx context.Context, jobID, workerID, lastErr string) error
+
+	// FailExpired marks running jobs whose lease ran out with no attempts
+	// left as failed and returns how many were
+	FailExpired(ctx context.Context) (int64, error)
+
+	// Stats counts jobs by status
+	Stats(ctx context.Context) (*ScrapeQueueStats, error)
+}
+
+// ScrapeQueueStats is the depth of the persistent scrape queue
+type ScrapeQueueStats struct {
+	Pending int64 `json:"pending"`
+	Running int64 `json:"running"`
+	Done    int64 `json:"done"`
+	Failed  int64 `json:"failed"`
+	// OldestPendingAgeSeconds is how long the oldest pending job has waited
+	OldestPendingAgeSeconds float64 `json:"oldest_pending_age_seconds"`
+}
+
+// ErrExplanationVersionExists is returned when saving a version number its
+// concept already has, as when two explanations are recorded concurrently
+var ErrExplanationVersionExists = errors.New("explanation version already exists")
+
+type ExplanationVersionRepository interface {
+	// Save stores a new explanation version, returning
+	// ErrExplanationVersionExists if the concept already has its number
+	Save(ctx context.Context, version *entities.ExplanationVersion) error
+
+	// FindByConcept returns all versions of a concept's explanation, newest first
+	FindByConcept(ctx context.Context, concept string) ([]*entities.ExplanationVersion, error)
+
+	// Activate makes one version the active one for its concept, reporting
+	// false if the version does not exist
+	Activate(ctx context.Context, concept string, version int, pinned bool) (bool, error)
+
+	// DeleteByConcept removes every version of a concept, returning how many
+	DeleteByConcept(ctx context.Context, concept string) (int64, error)
+}
+
+type PrerequisiteClosureRepository interface {
+	// ReplaceAll stores closures computed at computedAt and removes any
+	// computed earlier, i.e. of concepts no longer in the graph
+	ReplaceAll(ctx context.Context, closures []*entities.PrerequisiteClosure, computedAt time.Time) error
+
+	// FindByConceptID returns the stored closure of a concept, or nil if missing
+	FindByConceptID(ctx context.Context, conceptID string) (*entities.PrerequisiteClosure, error)
+}
+
 type StagedConceptRepository interface {
 	// Save saves a staged concept
 	Save(ctx context.Context, concept *entities.StagedConcept) error
@@ -57,7 +258,7 @@ type StagedConceptRepository interface {
 	// FindByConceptName finds a staged concept by name
 	FindByConceptName(ctx context.Context, conceptName string) (*entities.StagedConcept, error)
 
-	// GetPending gets all pending staged concepts
+	// GetPending gets all pending staged concepts, highest demand first
 	GetPending(ctx context.Context, limit, offset int) ([]*entities.StagedConcept, error)
 
 	// GetByStatus gets staged concepts by status
@@ -68,6 +269,13 @@ type StagedConceptRepository interface {
 
 	// GetStats gets statistics about staged concepts
 	GetStats(ctx context.Context) (*StagedConceptStats, error)
+
+	// GetStaleStats counts pending staged concepts identified before the cutoff
+	GetStaleStats(ctx context.Context, cutoff time.Time) (*StaleStagedConceptStats, error)
+
+	// UpdateDemand stores a concept's demand score unless its occurrence count
+	// changed since it was read, reporting whether it was stored
+	UpdateDemand(ctx context.Context, concept *entities.StagedConcept) (bool, error)
 }
 
 type StagedConceptStats struct {
@@ -79,6 +287,14 @@ type StagedConceptStats struct {
 	MostRecentPending *entities.StagedConcept `json:"most_recent_pending,omitempty"`
 }
 
+// StaleStagedConceptStats describes pending staged concepts left unreviewed
+type StaleStagedConceptStats struct {
+	MaxAgeDays       float64                 `json:"max_age_days"`
+	StaleCount       int64                   `json:"stale_count"`
+	OldestPending    *entities.StagedConcept `json:"oldest_pending,omitempty"`
+	OldestAgeSeconds float64                 `json:"oldest_age_seconds"`
+}
+
 // Supporting types
 type AnalyticsFilter struct {
 	StartTime *time.Time
@@ -97,8 +313,70 @@ type QueryAnalytics struct {
 }
 
 type ConceptPopularity struct {
-	ConceptName string `json:"concept_name"`
-	QueryCount  int64  `json:"query_count"`
+	ConceptName string `json:"concept_name" bson:"concept_name"`
+	QueryCount  int64  `json:"query_count" bson:"query_count"` // all-time
+	// Score is the recency-weighted query count; set only when popularity decays
+	Score float64 `json:"score,omitempty" bson:"score,omitempty"`
+}
+
+// ConceptPair is two concepts identified together in QueryCount queries.
+// ConceptA sorts before ConceptB.
+type ConceptPair struct {
+	ConceptA   string `json:"concept_a" bson:"concept_a"`
+	ConceptB   string `json:"concept_b" bson:"concept_b"`
+	QueryCount int64  `json:"query_count" bson:"query_count"`
+}
+
+// ConceptOutcome is how often queries identifying a concept were answered.
+// Successful excludes failed queries and fallback answers.
+type ConceptOutcome struct {
+	ConceptName string `json:"concept_name" bson:"concept_name"`
+	Queries     int64  `json:"queries" bson:"queries"`
+	Successful  int64  `json:"successful" bson:"successful"`
+}
+
+// ExperimentOutcome is how the queries assigned to one experiment variant
+// went. Successful excludes failed queries and fallback answers.
+type ExperimentOutcome struct {
+	Variant         string  `json:"variant" bson:"variant"`
+	Queries         int64   `json:"queries" bson:"queries"`
+	Successful      int64   `json:"successful" bson:"successful"`
+	Fallbacks       int64   `json:"fallbacks" bson:"fallbacks"`
+	AvgProcessingMs float64 `json:"avg_processing_ms" bson:"avg_processing_ms"`
+}
+
+// QueryConcepts pairs what a student asked with the concepts identified in it
+type QueryConcepts struct {
+	Text     string   `json:"text" bson:"text"`
+	Concepts []string `json:"concepts" bson:"identified_concepts"`
+}
+
+// StepDurations are the recorded durations of one pipeline step, shortest
+// first
+type StepDurations struct {
+	Step      string          `bson:"_id"`
+	Durations []time.Duration `bson:"durations"`
+}
+
+// TimingPercentiles summarizes the durations of a pipeline step or a data
+// source
+type TimingPercentiles struct {
+	Name   string  `json:"name"`
+	Source string  `json:"source,omitempty"` // set for steps
+	Count  int     `json:"count"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// PerformanceStats breaks down query latency over a time window, slowest p95
+// first. Only queries stored in full by analytics sampling are included.
+type PerformanceStats struct {
+	Since   time.Time           `json:"since"`
+	Until   time.Time           `json:"until"`
+	Steps   []TimingPercentiles `json:"steps"`
+	Sources []TimingPercentiles `json:"sources"`
 }
 
 type QueryTrend struct {
@@ -108,9 +386,53 @@ type QueryTrend struct {
 }
 
 type QueryStats struct {
-	TotalQueries    int64   `json:"total_queries"`
-	SuccessRate     float64 `json:"success_rate"`
-	AvgResponseTime float64 `json:"avg_response_time_ms"`
+	TotalQueries    int64           `json:"total_queries"`
+	SuccessRate     float64         `json:"success_rate"`
+	AvgResponseTime float64         `json:"avg_response_time_ms"`
+	ByCategory      []CategoryStats `json:"by_category"`
+}
+
+// CategoryStats is the query volume and success rate of one subject category
+type CategoryStats struct {
+	Category     string  `json:"category"`
+	TotalQueries int64   `json:"total_queries"`
+	SuccessRate  float64 `json:"success_rate"`
+}
+
+// ConceptFilter narrows a concept listing. Zero values leave a field
+// unfiltered, and a zero Limit returns every match.
+type ConceptFilter struct {
+	Category      string
+	MinDifficulty int
+	MaxDifficulty int
+	Search        string
+	HasResources  *bool
+	// ResourceConceptIDs are the concepts with stored resources, resolved by
+	// the service when HasResources is set
+	ResourceConceptIDs []string
+	Limit              int
+	Offset             int
+}
+
+// Outcomes a stored query can be listed by
+const (
+	// QueryListSuccess is a successful query answered by the LLM
+	QueryListSuccess = "success"
+	// QueryListFallback is a successful query answered with a fallback template
+	QueryListFallback = "fallback"
+	// QueryListFailed is a query that did not succeed
+	QueryListFailed = "failed"
+)
+
+// QueryListFilter selects stored queries; zero fields match everything
+type QueryListFilter struct {
+	UserID string
+	// Status is one of the QueryList* outcomes
+	Status string
+	Since  *time.Time
+	Until  *time.Time
+	Limit  int
+	Offset int
 }
 
 type ResourceFilter struct {
@@ -2,8 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/mathprereq/internal/arithmetic"
+	"github.com/mathprereq/internal/autocomplete"
 	"github.com/mathprereq/internal/data/scraper"
 	"github.com/mathprereq/internal/domain/entities"
 	"github.com/mathprereq/internal/domain/repositories"
@@ -12,24 +15,93 @@ import (
 
 type QueryService interface {
 	ProcessQuery(ctx context.Context, req *QueryRequest) (*QueryResult, error)
+	ProcessQueryBatch(ctx context.Context, reqs []*QueryRequest, concurrency int) []BatchQueryItem
+	ExplainMistake(ctx context.Context, req *MistakeRequest) (*MistakeResult, error)
 	GetConceptDetail(ctx context.Context, conceptID string) (*types.ConceptDetailResult, error)
+	GetConceptNeighborhood(ctx context.Context, conceptID string, depth, maxNodes int) (*types.ConceptNeighborhood, error)
+	GetQueryGraph(ctx context.Context, queryID string) (*types.PathGraph, error)
+	GetQueryContext(ctx context.Context, queryID string) (*QueryContext, error)
+	GetConceptProfile(ctx context.Context, conceptID string, refresh bool) (*entities.ConceptProfile, error)
+	GetVisualAid(ctx context.Context, queryID string, index int) (*entities.VisualAid, error)
+
+	// Concept explanation versions
+	ListExplanationVersions(ctx context.Context, conceptName string) ([]*entities.ExplanationVersion, error)
+	ActivateExplanationVersion(ctx context.Context, conceptName string, version int) (*entities.ExplanationVersion, error)
+
+	// Curriculum change tracking
+	CapturePathSnapshot(ctx context.Context, conceptID, label string) (*entities.PathSnapshot, error)
+	DiffConceptPath(ctx context.Context, conceptID, baselineID string) (*entities.PathDiff, error)
+
+	// Learning readiness from precomputed prerequisite sets
+	CheckReadiness(ctx context.Context, conceptID string, knownConceptIDs []string) (*ReadinessResult, error)
+	EstimateStudyTime(ctx context.Context, conceptID string, knownConceptIDs []string) (*StudyTimeEstimate, error)
+	GetConceptPaths(ctx context.Context, conceptIDs []string) (*ConceptPaths, error)
+	GetConceptRoadmap(ctx context.Context, conceptID string, nextLimit int) (*ConceptRoadmap, error)
+	RefreshPrerequisiteClosures(ctx context.Context) (int, error)
+
+	// Explanation preferences applied to every query of a user
+	GetUserPreferences(ctx context.Context, userID string) (*entities.UserPreferences, error)
+	UpdateUserPreferences(ctx context.Context, userID string, preferences entities.UserPreferences) (*entities.UserPreferences, error)
+
 	GetAllConcepts(ctx context.Context) ([]types.Concept, error)
+	ListConcepts(ctx context.Context, filter repositories.ConceptFilter) ([]types.Concept, int, error)
+
+	// Concept name suggestions from an in-memory prefix index
+	AutocompleteConcepts(ctx context.Context, prefix string, limit int) ([]autocomplete.Entry, error)
+	RefreshConceptIndex(ctx context.Context) (int, error)
+
+	// Concept aliases, suggested from query logs and added by admins
+	SuggestConceptAliases(ctx context.Context, window time.Duration, minQueries int) (*AliasSuggestions, error)
+	AddConceptAlias(ctx context.Context, conceptID, alias, addedBy string) (*entities.ConceptAlias, error)
+	ListConceptAliases(ctx context.Context) ([]*entities.ConceptAlias, error)
+	// MergeConcepts folds a duplicate concept, with its edges, resources and
+	// aliases, into another and deletes it
+	MergeConcepts(ctx context.Context, sourceID, targetID string) (*types.ConceptMergeResult, error)
+	// ValidatePrerequisiteEdge checks whether prerequisiteID could be added as
+	// a prerequisite of sourceID without changing the graph
+	ValidatePrerequisiteEdge(ctx context.Context, sourceID, prerequisiteID string) (*EdgeValidation, error)
+	// SetPrerequisiteWeight changes how essential a prerequisite is to a concept
+	SetPrerequisiteWeight(ctx context.Context, conceptID, prerequisiteID string, weight float64) error
+
+	// A/B experiments on explanation prompts and models
+	GetExperiment(ctx context.Context) *Experiment
+	GetExperimentResults(ctx context.Context, name string) (*ExperimentResults, error)
+
+	// Formulas extracted from ingested sources, by concept
+	SearchFormulas(ctx context.Context, concept string, limit int) ([]*entities.Formula, error)
 	GetQueryStats(ctx context.Context) (*repositories.QueryStats, error)
-	GetPopularConcepts(ctx context.Context, limit int) ([]repositories.ConceptPopularity, error)
+	GetPopularConcepts(ctx context.Context, limit int, halfLife time.Duration) ([]repositories.ConceptPopularity, error)
+	GetConceptPairs(ctx context.Context, limit int) ([]repositories.ConceptPair, error)
 	GetQueryTrends(ctx context.Context, days int) ([]repositories.QueryTrend, error)
+	GetPerformanceStats(ctx context.Context, window time.Duration) (*repositories.PerformanceStats, error)
 	GetSystemStats(ctx context.Context) (*types.SystemStats, error)
 
 	// Resource-related methods for learning materials
 	GetResourcesForConcepts(ctx context.Context, conceptNames []string, limit int) ([]scraper.EducationalResource, error)
+	PinResource(ctx context.Context, resourceID string, pinned bool, order int) (*scraper.EducationalResource, error)
+	BackfillVideoMetadata(ctx context.Context, limit int) (*scraper.VideoBackfillResult, error)
 
 	// Smart concept query - checks cache first, then processes if needed
 	SmartConceptQuery(ctx context.Context, conceptName, userID, requestID string) (*QueryResult, error)
 
 	// Debug and maintenance methods
 	GetCachedConcepts(ctx context.Context, limit int) ([]entities.Query, error)
+	// ListQueries returns one page of stored queries of any user, newest
+	// first, and the total match count
+	ListQueries(ctx context.Context, filter repositories.QueryListFilter) ([]QuerySummary, int, error)
 
+	GetStagedConcept(ctx context.Context, stagedID string) (*entities.StagedConcept, error)
 	GetPendingConcepts(ctx context.Context, limit, offset int) ([]*entities.StagedConcept, error)
 	GetStagedConceptStats(ctx context.Context) (*repositories.StagedConceptStats, error)
+	GetStaleStagedConcepts(ctx context.Context, maxAge time.Duration) (*repositories.StaleStagedConceptStats, error)
+	DecayStagedConceptDemand(ctx context.Context) (int, error)
+	// SendStagedConceptDigest emails the admin a summary of pending staged concepts
+	SendStagedConceptDigest(ctx context.Context) error
+	GetUnknownConcepts(ctx context.Context, limit int) ([]repositories.ConceptPopularity, error)
+	GetDifficultyCalibration(ctx context.Context, window time.Duration, minQueries int) (*DifficultyCalibration, error)
+	GetConceptCoverage(ctx context.Context, minCertainty float64) (*CoverageReport, error)
+	BackfillConceptDescriptions(ctx context.Context, limit int, dryRun bool) (*DescriptionBackfillResult, error)
+	PreviewStagedConceptApproval(ctx context.Context, stagedID string) (*ApprovalPreview, error)
 	ApproveStagedConcept(ctx context.Context, stagedID string, reviewerID string, notes string) error
 	RejectStagedConcept(ctx context.Context, stagedID string, reviewerID string, notes string) error
 	MergeStagedConcept(ctx context.Context, stagedID string, existingConceptID string, reviewerID string, notes string) error
@@ -40,12 +112,322 @@ type ResourceService interface {
 	FindResourcesByConcept(ctx context.Context, conceptID string, limit int) ([]*entities.LearningResource, error)
 }
 
+// ConceptDescriptionProposal is a generated description for a concept that had none
+type ConceptDescriptionProposal struct {
+	ConceptID   string `json:"concept_id"`
+	ConceptName string `json:"concept_name"`
+	Description string `json:"description,omitempty"`
+	Applied     bool   `json:"applied"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DescriptionBackfillResult reports one run of the description backfill.
+// Remaining counts blank concepts beyond the run's limit.
+type DescriptionBackfillResult struct {
+	DryRun    bool                         `json:"dry_run"`
+	Remaining int                          `json:"remaining"`
+	Proposals []ConceptDescriptionProposal `json:"proposals"`
+}
+
+// DifficultySuggestion proposes a new difficulty for a concept whose queries
+// succeed noticeably more or less often than queries overall
+type DifficultySuggestion struct {
+	ConceptID           string  `json:"concept_id"`
+	ConceptName         string  `json:"concept_name"`
+	CurrentDifficulty   int     `json:"current_difficulty"`
+	SuggestedDifficulty int     `json:"suggested_difficulty"`
+	Queries             int64   `json:"queries"`
+	SuccessRate         float64 `json:"success_rate"`
+}
+
+// DifficultyCalibration is a set of suggested difficulty changes. Nothing
+// is applied; admins decide which suggestions to act on.
+type DifficultyCalibration struct {
+	Since           time.Time              `json:"since"`
+	MinQueries      int                    `json:"min_queries"`
+	BaselineSuccess float64                `json:"baseline_success_rate"`
+	ConceptsScored  int                    `json:"concepts_scored"`
+	Suggestions     []DifficultySuggestion `json:"suggestions"`
+}
+
+// AliasSuggestion proposes a term as an alias of a concept: students who
+// wrote it were usually asking about the concept without naming it.
+// Confidence is the share of queries containing the term that did.
+type AliasSuggestion struct {
+	Alias       string  `json:"alias"`
+	ConceptID   string  `json:"concept_id"`
+	ConceptName string  `json:"concept_name"`
+	Queries     int     `json:"queries"`
+	Confidence  float64 `json:"confidence"`
+}
+
+// AliasSuggestions is a set of suggested aliases mined from recent queries.
+// Nothing is added; admins decide which suggestions to accept.
+type AliasSuggestions struct {
+	Since          time.Time         `json:"since"`
+	MinQueries     int               `json:"min_queries"`
+	QueriesScanned int               `json:"queries_scanned"`
+	Suggestions    []AliasSuggestion `json:"suggestions"`
+}
+
+// ConceptCoverage is how much textbook content supports a graph concept.
+// TaggedChunks are chunks whose concept field names it; RelatedChunks are
+// chunks semantically close to its name, which may overlap the tagged ones.
+type ConceptCoverage struct {
+	ConceptID     string `json:"concept_id"`
+	ConceptName   string `json:"concept_name"`
+	TaggedChunks  int    `json:"tagged_chunks"`
+	RelatedChunks int    `json:"related_chunks"`
+	Supported     bool   `json:"supported"`
+}
+
+// TextbookConcept is a concept textbook chunks are tagged with that is not
+// in the graph
+type TextbookConcept struct {
+	Concept string `json:"concept"`
+	Chunks  int    `json:"chunks"`
+}
+
+// CoverageReport compares the graph with the textbook content. Concepts
+// lists unsupported concepts first, then the least covered.
+type CoverageReport struct {
+	GraphConcepts    int               `json:"graph_concepts"`
+	Unsupported      int               `json:"unsupported"`
+	TaggedChunks     int               `json:"tagged_chunks"`
+	UntaggedChunks   int               `json:"untagged_chunks"`
+	MinCertainty     float64           `json:"min_certainty,omitempty"`
+	Concepts         []ConceptCoverage `json:"concepts"`
+	MissingFromGraph []TextbookConcept `json:"missing_from_graph"`
+}
+
+// ExperimentVariant is one arm of an experiment. Share is its fraction of
+// assigned users; empty PromptVariant or Model keep the usual ones.
+type ExperimentVariant struct {
+	Name          string  `json:"name"`
+	Weight        int     `json:"weight"`
+	Share         float64 `json:"share"`
+	PromptVariant string  `json:"prompt_variant,omitempty"`
+	Model         string  `json:"model,omitempty"`
+}
+
+// Experiment is the running A/B experiment
+type Experiment struct {
+	Name     string              `json:"name"`
+	Variants []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentVariantResult compares how one variant's queries went
+type ExperimentVariantResult struct {
+	repositories.ExperimentOutcome
+	SuccessRate  float64 `json:"success_rate"`
+	FallbackRate float64 `json:"fallback_rate"`
+}
+
+// ExperimentResults compares the variants of an experiment. Only queries
+// stored in full are counted, so results follow the analytics sample rate.
+type ExperimentResults struct {
+	Name     string                    `json:"name"`
+	Running  bool                      `json:"running"`
+	Queries  int64                     `json:"queries"`
+	Variants []ExperimentVariantResult `json:"variants"`
+}
+
+// QuerySummary describes a stored query without its full answer
+type QuerySummary struct {
+	ID                 string    `json:"id"`
+	UserID             string    `json:"user_id,omitempty"`
+	Text               string    `json:"text"`
+	IdentifiedConcepts []string  `json:"identified_concepts"`
+	Status             string    `json:"status"`
+	Timestamp          time.Time `json:"timestamp"`
+	ProcessingTimeMs   int64     `json:"processing_time_ms"`
+	ExplanationLength  int       `json:"explanation_length"`
+	PrerequisiteCount  int       `json:"prerequisite_count"`
+}
+
+// QueryContext is the context retrieved for a stored query. Scored is false
+// for queries stored before scores were kept, whose chunks hold text only.
+type QueryContext struct {
+	QueryID string                  `json:"query_id"`
+	Scored  bool                    `json:"scored"`
+	Chunks  []entities.ContextChunk `json:"chunks"`
+}
+
+// ReadinessResult tells whether a learner knows every prerequisite of a
+// concept. Precomputed is false when the set came from a live traversal.
+type ReadinessResult struct {
+	ConceptID     string   `json:"concept_id"`
+	Ready         bool     `json:"ready"`
+	Prerequisites int      `json:"prerequisites"`
+	Missing       []string `json:"missing"`
+	Precomputed   bool     `json:"precomputed"`
+}
+
+// ConceptStudyTime is the estimated study time of one concept on a path.
+// Source is "video" when timed from a stored video, else "difficulty".
+type ConceptStudyTime struct {
+	ConceptID   string `json:"concept_id"`
+	ConceptName string `json:"concept_name"`
+	Difficulty  int    `json:"difficulty"`
+	Minutes     int    `json:"minutes"`
+	Source      string `json:"source"`
+}
+
+// StudyTimeEstimate is roughly how long a learner needs to work through a
+// concept's prerequisite path. Estimated counts concepts timed from their
+// difficulty alone; Skipped counts prerequisites the learner already knows.
+type StudyTimeEstimate struct {
+	ConceptID    string             `json:"concept_id"`
+	TotalMinutes int                `json:"total_minutes"`
+	Total        string             `json:"total"`
+	Estimated    int                `json:"estimated"`
+	Skipped      int                `json:"skipped"`
+	Concepts     []ConceptStudyTime `json:"concepts"`
+}
+
+// ConceptRoadmap is where a concept sits in the curriculum: its
+// prerequisites in an order they can be learned in, the concept itself and
+// the concepts it leads to directly. Each concept's Type is "prerequisite",
+// "target" or "next".
+type ConceptRoadmap struct {
+	Target    types.Concept   `json:"target"`
+	Upstream  []types.Concept `json:"upstream"`
+	NextSteps []types.Concept `json:"next_steps"`
+}
+
+// ConceptPaths is the prerequisite paths of several concepts for curriculum
+// maps. Concepts lists every concept on any path once; each path refers to
+// them by ID. NotFound lists requested IDs that are not in the graph.
+type ConceptPaths struct {
+	Concepts []types.Concept `json:"concepts"`
+	Paths    []ConceptPath   `json:"paths"`
+	NotFound []string        `json:"not_found,omitempty"`
+}
+
+// ConceptPath is one concept's prerequisite path: its prerequisites by
+// name, then the concept itself
+type ConceptPath struct {
+	ConceptID string     `json:"concept_id"`
+	Steps     []PathStep `json:"steps"`
+}
+
+// PathStep is a concept on a path. Weight and Importance say how essential
+// a prerequisite is to this path's concept, so they live on the step rather
+// than the shared concept.
+type PathStep struct {
+	ConceptID  string  `json:"concept_id"`
+	Weight     float64 `json:"weight,omitempty"`
+	Importance string  `json:"importance,omitempty"`
+}
+
+// ApprovalPreview is what approving a staged concept would change in the
+// graph, worked out without changing anything
+type ApprovalPreview struct {
+	StagedID string        `json:"staged_id"`
+	Concept  types.Concept `json:"concept"`
+	// ConceptExists is true when a concept with the same name is already in
+	// the graph, so approval would add a duplicate
+	ConceptExists bool                  `json:"concept_exists"`
+	Relationships []PlannedRelationship `json:"relationships"`
+	CreatesCycle  bool                  `json:"creates_cycle"`
+}
+
+// EdgeValidation is the verdict on a proposed prerequisite edge. Valid is
+// true only when both concepts exist and the edge is neither a duplicate nor
+// closes a cycle; Reasons explains each failed check.
+type EdgeValidation struct {
+	SourceID           string   `json:"source_id"`
+	PrerequisiteID     string   `json:"prerequisite_id"`
+	Valid              bool     `json:"valid"`
+	SourceExists       bool     `json:"source_exists"`
+	PrerequisiteExists bool     `json:"prerequisite_exists"`
+	Duplicate          bool     `json:"duplicate"`
+	CreatesCycle       bool     `json:"creates_cycle"`
+	Reasons            []string `json:"reasons,omitempty"`
+}
+
+// Planned relationship actions
+const (
+	RelationshipCreate = "create"
+	RelationshipSkip   = "skip"
+)
+
+// PlannedRelationship is a suggested prerequisite and the edge approval
+// would create for it, or why it would be skipped
+type PlannedRelationship struct {
+	Suggestion     string `json:"suggestion"`
+	PrerequisiteID string `json:"prerequisite_id,omitempty"`
+	Matched        bool   `json:"matched"` // mapped by embedding similarity during staging
+	Action         string `json:"action"`
+	Reason         string `json:"reason,omitempty"`
+	// Weight is how essential the prerequisite is, 0-1
+	Weight float64 `json:"weight,omitempty"`
+	// CreatesCycle is true when the concept is already a prerequisite of this
+	// prerequisite, at any depth
+	CreatesCycle bool `json:"creates_cycle,omitempty"`
+}
+
 type QueryRequest struct {
 	UserID    string `json:"user_id,omitempty" validate:"omitempty,uuid"`
 	Question  string `json:"question" validate:"required,min=3,max=1000"`
 	RequestID string `json:"request_id,omitempty"`
+
+	// IncludeVisuals opts into plotting functions found in the explanation
+	IncludeVisuals bool `json:"include_visuals,omitempty"`
+
+	// Language is the ISO 639-1 code the explanation is written in; empty means English
+	Language string `json:"language,omitempty"`
+
+	// BypassCache forces a fresh answer even when the question cache has one
+	BypassCache bool `json:"bypass_cache,omitempty"`
+
+	// RetrievalSource overrides where context is retrieved from: textbook,
+	// concepts or both; empty uses the configured default
+	RetrievalSource string `json:"retrieval_source,omitempty"`
+
+	// SessionID keeps anonymous users in one experiment variant across queries
+	SessionID string `json:"session_id,omitempty" validate:"omitempty,max=128"`
+
+	// RetrievalTerms, when set, are searched for context instead of the
+	// question, as for concept queries whose question is an instruction.
+	// ContextChunks overrides how many chunks are retrieved. Neither is
+	// accepted from clients.
+	RetrievalTerms []string `json:"-"`
+	ContextChunks  int      `json:"-"`
+
+	// ModelOverride explains this query with the named model instead of the
+	// routed one. Handlers set it only for authenticated testers, and it
+	// must be one of the allowed override models.
+	ModelOverride string `json:"-"`
+
+	// ConceptKey is stored on the answer of a concept query so naming
+	// variants of the concept find it in the cache; not accepted from clients
+	ConceptKey string `json:"-"`
 }
 
+// ErrModelOverrideNotAllowed is returned for a QueryRequest whose
+// ModelOverride is not one of the allowed override models
+var ErrModelOverrideNotAllowed = errors.New("model override not allowed")
+
+// ErrUnsupportedLanguage is returned for a request whose language is not
+// one of SupportedLanguageCodes
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// QueryFailedMessage is shown to clients in place of the error when a
+// question could not be answered; the details are only logged
+const QueryFailedMessage = "Failed to process your question. Please try again or rephrase your question."
+
+// ErrConceptNotFound is returned for a concept ID that is not in the graph
+var ErrConceptNotFound = errors.New("concept not found")
+
+// ErrMergeIntoItself is returned by MergeConcepts when the source and
+// target are the same concept
+var ErrMergeIntoItself = errors.New("cannot merge a concept into itself")
+
+// ErrBaselineSnapshotNotFound is returned by DiffConceptPath when the
+// concept has no snapshot, or the named snapshot belongs to another concept
+var ErrBaselineSnapshotNotFound = errors.New("baseline snapshot not found")
+
 type QueryResult struct {
 	Query              *entities.Query `json:"query"`
 	IdentifiedConcepts []string        `json:"identified_concepts"`
@@ -54,8 +436,97 @@ type QueryResult struct {
 	RetrievedContext   []string        `json:"retrieved_context"`
 	ProcessingTime     time.Duration   `json:"processing_time"`
 	RequestID          string          `json:"request_id"`
+
+	// SuggestedPath is the LLM's prerequisite ordering for concepts the graph
+	// does not know; only set when PrerequisitePath is empty
+	SuggestedPath []types.Concept `json:"suggested_path,omitempty"`
+
+	VisualAids []*entities.VisualAid `json:"visual_aids,omitempty"`
+
+	// Source records whether the result was served from the query cache or
+	// freshly processed; CacheAge is set only for cached results
+	Source   string         `json:"source,omitempty"`
+	CacheAge *time.Duration `json:"cache_age,omitempty"`
+
+	// Fallback is set when Explanation came from a category template because
+	// the LLM could not generate one
+	Fallback bool `json:"fallback,omitempty"`
+
+	// Status tells clients how to present the result; see QueryStatus*
+	Status string `json:"status"`
+
+	// ModelProfile is the fast or deep model profile that wrote the explanation
+	ModelProfile string `json:"model_profile,omitempty"`
+
+	// ArithmeticCheck reports whether the explanation's numeric steps add up;
+	// nil when verification is off
+	ArithmeticCheck *arithmetic.Report `json:"arithmetic_check,omitempty"`
+
+	// SourcesUsed are the retrieved chunks the explanation cited by their
+	// [Context n] markers; empty when it cited none
+	SourcesUsed []entities.CitedSource `json:"sources_used,omitempty"`
+
+	// Unsampled is set when the query fell outside the analytics sample and
+	// is not stored, so its ID cannot be looked up afterwards
+	Unsampled bool `json:"unsampled,omitempty"`
+}
+
+// MistakeRequest is a problem and a student's incorrect attempt at it
+type MistakeRequest struct {
+	UserID    string `json:"user_id,omitempty"`
+	Problem   string `json:"problem"`
+	Attempt   string `json:"attempt"`
+	RequestID string `json:"request_id,omitempty"`
+
+	// Language is the ISO 639-1 code the explanation is written in; empty means English
+	Language string `json:"language,omitempty"`
+}
+
+// MistakeResult explains where a student's attempt goes wrong. Query is the
+// stored record, kept for analytics like any other query.
+type MistakeResult struct {
+	Query              *entities.Query              `json:"query"`
+	IdentifiedConcepts []string                     `json:"identified_concepts"`
+	PrerequisitePath   []types.Concept              `json:"prerequisite_path"`
+	Explanation        *entities.MistakeExplanation `json:"explanation"`
+	RetrievedContext   []string                     `json:"retrieved_context"`
+	ProcessingTime     time.Duration                `json:"processing_time"`
+	RequestID          string                       `json:"request_id"`
+
+	// Unsampled is set as for QueryResult
+	Unsampled bool `json:"unsampled,omitempty"`
 }
 
+// BatchQueryItem is the outcome of one question in a batch. Exactly one of
+// Result and Error is set. DuplicateOf is the index of an identical earlier
+// question whose outcome this item shares.
+type BatchQueryItem struct {
+	Index       int          `json:"index"`
+	Result      *QueryResult `json:"result,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	DuplicateOf *int         `json:"duplicate_of,omitempty"`
+}
+
+// Query result sources
+const (
+	QuerySourceCache     = "cache"
+	QuerySourceProcessed = "processed"
+)
+
+// Query result statuses
+const (
+	// QueryStatusOK is a full answer for concepts found in the graph
+	QueryStatusOK = "ok"
+	// QueryStatusNoConcepts means the question named no recognizable concepts
+	QueryStatusNoConcepts = "no_concepts_identified"
+	// QueryStatusConceptsNotFound means concepts were identified but none of
+	// them are in the knowledge graph, so there is no learning path
+	QueryStatusConceptsNotFound = "concepts_not_in_graph"
+	// QueryStatusDegraded means the explanation is a fallback template
+	// because the LLM failed
+	QueryStatusDegraded = "degraded"
+)
+
 type ResourceRequest struct {
 	ConceptName string `json:"concept_name" validate:"required"`
 	Limit       int    `json:"limit,omitempty"`
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is used when a query does not ask for a language
+const DefaultLanguage = "en"
+
+// SupportedLanguages maps the ISO 639-1 codes accepted for explanations to
+// the language name given to the LLM
+var SupportedLanguages = map[string]string{
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"pt": "Portuguese",
+	"it": "Italian",
+	"zh": "Chinese",
+	"ja": "Japanese",
+	"hi": "Hindi",
+	"ar": "Arabic",
+	"si": "Sinhala",
+	"ta": "Tamil",
+}
+
+// NormalizeLanguage lowercases a language code and applies the default.
+// It returns false for codes that are not supported.
+func NormalizeLanguage(code string) (string, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultLanguage, true
+	}
+	_, ok := SupportedLanguages[code]
+	return code, ok
+}
+
+// SupportedLanguageCodes returns the supported codes in sorted order
+func SupportedLanguageCodes() []string {
+	codes := make([]string, 0, len(SupportedLanguages))
+	for code := range SupportedLanguages {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
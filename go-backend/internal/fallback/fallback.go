@@ -0,0 +1,182 @@
+// Package fallback renders degraded explanations from templates when the
+// LLM cannot generate one.
+package fallback
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/mathprereq/internal/types"
+)
+
+// GeneralCategory is used when no template matches the concepts' category
+const GeneralCategory = "general"
+
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// Data is what templates can reference
+type Data struct {
+	Query          string
+	Category       string
+	TargetConcepts []string
+	Prerequisites  []string
+}
+
+// Renderer holds one template per category
+type Renderer struct {
+	templates map[string]*template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"inc":  func(i int) int { return i + 1 },
+}
+
+// NewRenderer loads the embedded templates, then any <category>.tmpl files
+// in dir, which replace or add to them. An empty dir uses only the embedded set.
+func NewRenderer(dir string) (*Renderer, error) {
+	r := &Renderer{templates: make(map[string]*template.Template)}
+
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(embedded); err != nil {
+		return nil, fmt.Errorf("failed to load embedded fallback templates: %w", err)
+	}
+
+	if dir != "" {
+		if err := r.load(os.DirFS(dir)); err != nil {
+			return nil, fmt.Errorf("failed to load fallback templates from %s: %w", dir, err)
+		}
+	}
+
+	if _, ok := r.templates[GeneralCategory]; !ok {
+		return nil, fmt.Errorf("missing %s fallback template", GeneralCategory)
+	}
+	return r, nil
+}
+
+func (r *Renderer) load(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		category := strings.ToLower(strings.TrimSuffix(filepath.Base(file), ".tmpl"))
+		tmpl, err := template.New(category).Funcs(templateFuncs).Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		r.templates[category] = tmpl
+	}
+	return nil
+}
+
+// Render picks the template for the path's category, falling back to the
+// general template, and returns the category used with the rendered text
+func (r *Renderer) Render(query string, conceptNames []string, path []types.Concept) (string, string, error) {
+	data := Data{Query: query, Category: SelectCategory(path, conceptNames)}
+	for _, concept := range path {
+		if concept.Type == "target" {
+			data.TargetConcepts = append(data.TargetConcepts, concept.Name)
+		} else {
+			data.Prerequisites = append(data.Prerequisites, concept.Name)
+		}
+	}
+	if len(data.TargetConcepts) == 0 {
+		data.TargetConcepts = conceptNames
+	}
+
+	tmpl, ok := r.templates[data.Category]
+	if !ok {
+		data.Category = GeneralCategory
+		tmpl = r.templates[GeneralCategory]
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s fallback: %w", data.Category, err)
+	}
+	return data.Category, strings.TrimSpace(buf.String()), nil
+}
+
+// categoryKeywords infers a category from concept names when the graph has
+// none stored, as for concepts imported from the textbook
+var categoryKeywords = map[string][]string{
+	"calculus": {"derivative", "differentiat", "integra", "limit", "continuity", "series", "chain rule", "calculus"},
+	"algebra":  {"equation", "polynomial", "algebra", "factor", "quadratic", "inequalit", "exponent", "logarithm", "linear"},
+}
+
+// SelectCategory returns the most common category among target concepts,
+// then among the whole path, then inferred from names; ties and unknowns
+// give GeneralCategory
+func SelectCategory(path []types.Concept, conceptNames []string) string {
+	var targets []types.Concept
+	for _, concept := range path {
+		if concept.Type == "target" {
+			targets = append(targets, concept)
+		}
+	}
+
+	for _, group := range [][]types.Concept{targets, path} {
+		counts := make(map[string]int)
+		for _, concept := range group {
+			if category := strings.ToLower(strings.TrimSpace(concept.Category)); category != "" {
+				counts[category]++
+			}
+		}
+		if category := majority(counts); category != "" {
+			return category
+		}
+	}
+
+	counts := make(map[string]int)
+	names := append([]string{}, conceptNames...)
+	for _, concept := range targets {
+		names = append(names, concept.Name)
+	}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		for category, keywords := range categoryKeywords {
+			for _, keyword := range keywords {
+				if strings.Contains(name, keyword) {
+					counts[category]++
+					break
+				}
+			}
+		}
+	}
+	if category := majority(counts); category != "" {
+		return category
+	}
+	return GeneralCategory
+}
+
+// majority returns the key with the highest count, or "" on a tie or no data
+func majority(counts map[string]int) string {
+	best, bestCount, tied := "", 0, false
+	for category, count := range counts {
+		switch {
+		case count > bestCount:
+			best, bestCount, tied = category, count, false
+		case count == bestCount:
+			tied = true
+		}
+	}
+	if tied {
+		return ""
+	}
+	return best
+}
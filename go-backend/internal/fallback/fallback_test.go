@@ -0,0 +1,107 @@
+package fallback
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mathprereq/internal/types"
+)
+
+func TestSelectCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     []types.Concept
+		concepts []string
+		want     string
+	}{
+		{
+			name: "target category wins",
+			path: []types.Concept{
+				{Name: "Functions", Category: "algebra", Type: "prerequisite"},
+				{Name: "Polynomials", Category: "algebra", Type: "prerequisite"},
+				{Name: "Derivatives", Category: "Calculus", Type: "target"},
+			},
+			want: "calculus",
+		},
+		{
+			name: "path category when targets have none",
+			path: []types.Concept{
+				{Name: "Factoring", Category: "algebra", Type: "prerequisite"},
+				{Name: "Quadratics", Type: "target"},
+			},
+			want: "algebra",
+		},
+		{
+			name:     "inferred from names",
+			concepts: []string{"integration by parts"},
+			want:     "calculus",
+		},
+		{
+			name:     "unknown",
+			concepts: []string{"probability"},
+			want:     GeneralCategory,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectCategory(tt.path, tt.concepts); got != tt.want {
+				t.Errorf("SelectCategory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUsesCategoryTemplate(t *testing.T) {
+	r, err := NewRenderer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := []types.Concept{
+		{Name: "Limits", Type: "prerequisite"},
+		{Name: "Derivatives", Type: "target"},
+	}
+	category, text, err := r.Render("What is a derivative?", []string{"derivatives"}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if category != "calculus" {
+		t.Errorf("category = %q, want calculus", category)
+	}
+	for _, want := range []string{"Fallback explanation", "calculus outline", "Derivatives", "1. Limits"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("rendered fallback missing %q:\n%s", want, text)
+		}
+	}
+
+	category, text, err = r.Render("What is entropy?", []string{"entropy"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if category != GeneralCategory || !strings.Contains(text, "entropy") {
+		t.Errorf("expected general fallback mentioning entropy, got %q:\n%s", category, text)
+	}
+}
+
+func TestNewRendererOverridesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "geometry.tmpl"), []byte("Fallback geometry: {{join .TargetConcepts \", \"}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRenderer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := []types.Concept{{Name: "Triangles", Category: "geometry", Type: "target"}}
+	category, text, err := r.Render("", nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if category != "geometry" || text != "Fallback geometry: Triangles" {
+		t.Errorf("got %q / %q", category, text)
+	}
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoConceptAliasRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoConceptAliasRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.ConceptAliasRepository {
+	return &mongoConceptAliasRepository{
+		collection: client.Database(dbName).Collection("concept_aliases"),
+		logger:     logger,
+	}
+}
+
+func (r *mongoConceptAliasRepository) Save(ctx context.Context, alias *entities.ConceptAlias) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": alias.Alias}, alias, opts); err != nil {
+		return fmt.Errorf("failed to save concept alias: %w", err)
+	}
+
+	r.logger.Info("Concept alias saved",
+		zap.String("alias", alias.Alias),
+		zap.String("concept_id", alias.ConceptID))
+
+	return nil
+}
+
+func (r *mongoConceptAliasRepository) FindAll(ctx context.Context) ([]*entities.ConceptAlias, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find concept aliases: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	aliases := []*entities.ConceptAlias{}
+	if err := cursor.All(ctx, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to decode concept aliases: %w", err)
+	}
+	return aliases, nil
+}
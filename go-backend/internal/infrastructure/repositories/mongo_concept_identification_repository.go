@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoConceptIdentificationRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoConceptIdentificationRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.ConceptIdentificationRepository {
+	collection := client.Database(dbName).Collection("concept_identifications")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// MongoDB deletes each entry once its expires_at has passed
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		logger.Warn("Failed to create indexes for concept_identifications", zap.Error(err))
+	}
+
+	return &mongoConceptIdentificationRepository{
+		collection: collection,
+		logger:     logger,
+	}
+}
+
+func (r *mongoConceptIdentificationRepository) Save(ctx context.Context, identification *entities.ConceptIdentification) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": identification.QuestionKey}, identification, opts); err != nil {
+		return fmt.Errorf("failed to save concept identification: %w", err)
+	}
+	return nil
+}
+
+func (r *mongoConceptIdentificationRepository) FindByQuestionKey(ctx context.Context, questionKey string) (*entities.ConceptIdentification, error) {
+	// The TTL monitor runs about once a minute, so expired entries it has
+	// not removed yet are filtered out here
+	filter := bson.M{"_id": questionKey, "expires_at": bson.M{"$gt": time.Now()}}
+
+	var identification entities.ConceptIdentification
+	err := r.collection.FindOne(ctx, filter).Decode(&identification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find concept identification: %w", err)
+	}
+	return &identification, nil
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoConceptProfileRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoConceptProfileRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.ConceptProfileRepository {
+	return &mongoConceptProfileRepository{
+		collection: client.Database(dbName).Collection("concept_profiles"),
+		logger:     logger,
+	}
+}
+
+func (r *mongoConceptProfileRepository) Save(ctx context.Context, profile *entities.ConceptProfile) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": profile.ConceptID}, profile, opts); err != nil {
+		return fmt.Errorf("failed to save concept profile: %w", err)
+	}
+
+	r.logger.Info("Concept profile saved",
+		zap.String("concept_id", profile.ConceptID),
+		zap.Bool("structured", profile.Structured))
+
+	return nil
+}
+
+func (r *mongoConceptProfileRepository) FindByConceptID(ctx context.Context, conceptID string) (*entities.ConceptProfile, error) {
+	var profile entities.ConceptProfile
+	err := r.collection.FindOne(ctx, bson.M{"_id": conceptID}).Decode(&profile)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find concept profile: %w", err)
+	}
+	return &profile, nil
+}
+
+func (r *mongoConceptProfileRepository) Delete(ctx context.Context, conceptID string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": conceptID}); err != nil {
+		return fmt.Errorf("failed to delete concept profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoExplanationVersionRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoExplanationVersionRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.ExplanationVersionRepository {
+	collection := client.Database(dbName).Collection("explanation_versions")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Unique so concurrent writers cannot both take the next version number;
+	// it also serves the lookups by concept
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "concept", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		logger.Warn("Failed to create indexes for explanation_versions", zap.Error(err))
+	}
+
+	return &mongoExplanationVersionRepository{
+		collection: collection,
+		logger:     logger,
+	}
+}
+
+func (r *mongoExplanationVersionRepository) Save(ctx context.Context, version *entities.ExplanationVersion) error {
+	if _, err := r.collection.InsertOne(ctx, version); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s version %d", repositories.ErrExplanationVersionExists, version.Concept, version.Version)
+		}
+		return fmt.Errorf("failed to save explanation version: %w", err)
+	}
+
+	r.logger.Info("Explanation version saved",
+		zap.String("concept", version.Concept),
+		zap.Int("version", version.Version),
+		zap.String("llm_model", version.LLMModel))
+
+	return nil
+}
+
+func (r *mongoExplanationVersionRepository) FindByConcept(ctx context.Context, concept string) ([]*entities.ExplanationVersion, error) {
+	opts := options.Find().SetSort(bson.M{"version": -1})
+	cursor, err := r.collection.Find(ctx, bson.M{"concept": concept}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find explanation versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	versions := []*entities.ExplanationVersion{}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode explanation versions: %w", err)
+	}
+	return versions, nil
+}
+
+func (r *mongoExplanationVersionRepository) Activate(ctx context.Context, concept string, version int, pinned bool) (bool, error) {
+	target := bson.M{"concept": concept, "version": version}
+	count, err := r.collection.CountDocuments(ctx, target)
+	if err != nil {
+		return false, fmt.Errorf("failed to find explanation version: %w", err)
+	}
+	if count == 0 {
+		return false, nil
+	}
+
+	others := bson.M{"concept": concept, "version": bson.M{"$ne": version}, "active": true}
+	if _, err := r.collection.UpdateMany(ctx, others, bson.M{"$set": bson.M{"active": false, "pinned": false}}); err != nil {
+		return false, fmt.Errorf("failed to deactivate explanation versions: %w", err)
+	}
+	if _, err := r.collection.UpdateOne(ctx, target, bson.M{"$set": bson.M{"active": true, "pinned": pinned}}); err != nil {
+		return false, fmt.Errorf("failed to activate explanation version: %w", err)
+	}
+
+	r.logger.Info("Explanation version activated",
+		zap.String("concept", concept),
+		zap.Int("version", version),
+		zap.Bool("pinned", pinned))
+
+	return true, nil
+}
+
+func (r *mongoExplanationVersionRepository) DeleteByConcept(ctx context.Context, concept string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"concept": concept})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete explanation versions: %w", err)
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestExplanationVersionSaveReportsTakenVersion(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("duplicate key", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "E11000 duplicate key error collection: test.explanation_versions index: concept_1_version_1",
+		}))
+
+		repo := &mongoExplanationVersionRepository{collection: mt.Coll, logger: zap.NewNop()}
+		err := repo.Save(context.Background(), &entities.ExplanationVersion{Concept: "limits", Version: 2})
+		if !errors.Is(err, repositories.ErrExplanationVersionExists) {
+			mt.Errorf("Save() = %v, want ErrExplanationVersionExists", err)
+		}
+	})
+
+	mt.Run("other write errors", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 121, Message: "document failed validation"}))
+
+		repo := &mongoExplanationVersionRepository{collection: mt.Coll, logger: zap.NewNop()}
+		err := repo.Save(context.Background(), &entities.ExplanationVersion{Concept: "limits", Version: 2})
+		if err == nil || errors.Is(err, repositories.ErrExplanationVersionExists) {
+			mt.Errorf("Save() = %v, want a plain save error", err)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoFormulaRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoFormulaRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.FormulaRepository {
+	collection := client.Database(dbName).Collection("formulas")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	index := mongo.IndexModel{Keys: bson.D{{Key: "concept_keys", Value: 1}}}
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		logger.Warn("Failed to create indexes for formulas", zap.Error(err))
+	}
+
+	return &mongoFormulaRepository{
+		collection: collection,
+		logger:     logger,
+	}
+}
+
+func (r *mongoFormulaRepository) SaveBatch(ctx context.Context, formulas []*entities.Formula) error {
+	if len(formulas) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(formulas))
+	for _, formula := range formulas {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": formula.ID}).
+			SetReplacement(formula).
+			SetUpsert(true))
+	}
+	if _, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to save formulas: %w", err)
+	}
+
+	r.logger.Info("Formulas saved", zap.Int("count", len(formulas)))
+	return nil
+}
+
+func (r *mongoFormulaRepository) FindByConcept(ctx context.Context, conceptKey string, limit int) ([]*entities.Formula, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "extracted_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"concept_keys": conceptKey}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find formulas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	formulas := []*entities.Formula{}
+	if err := cursor.All(ctx, &formulas); err != nil {
+		return nil, fmt.Errorf("failed to decode formulas: %w", err)
+	}
+	return formulas, nil
+}
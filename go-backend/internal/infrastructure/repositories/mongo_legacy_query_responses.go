@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// LegacyQueryResponsesCollection held a second copy of each query, written
+// by the old query orchestrator next to the queries collection
+const LegacyQueryResponsesCollection = "query_responses"
+
+const (
+	// legacyDuplicateWindow is how far apart a legacy record and a stored
+	// query of the same question and user may be to count as one request
+	legacyDuplicateWindow = time.Minute
+	// legacyMergeBatch is how many legacy records are merged per write
+	legacyMergeBatch = 500
+)
+
+// legacyQueryResponse is a record of the legacy collection
+type legacyQueryResponse struct {
+	ID                 primitive.ObjectID `bson:"_id"`
+	UserID             string             `bson:"user_id,omitempty"`
+	Query              string             `bson:"query"`
+	IdentifiedConcepts []string           `bson:"identified_concepts"`
+	PrerequisitePath   []types.Concept    `bson:"prerequisite_path"`
+	RetrievedContext   []string           `bson:"retrieved_context"`
+	Explanation        string             `bson:"explanation"`
+	ResponseTime       time.Duration      `bson:"response_time"`
+	ProcessingSuccess  bool               `bson:"processing_success"`
+	ErrorMessage       string             `bson:"error_message,omitempty"`
+	Timestamp          time.Time          `bson:"timestamp"`
+	LLMProvider        string             `bson:"llm_provider"`
+	LLMModel           string             `bson:"llm_model"`
+	KnowledgeGraphHits int                `bson:"knowledge_graph_hits"`
+	VectorStoreHits    int                `bson:"vector_store_hits"`
+	// Duplicate is set by the merge pipeline when the queries collection
+	// already holds the same request
+	Duplicate bool `bson:"duplicate"`
+}
+
+// LegacyMergeResult counts what MergeLegacyQueryResponses did: Duplicates
+// were already stored as queries and only deleted, Migrated were moved
+type LegacyMergeResult struct {
+	Scanned    int `json:"scanned"`
+	Duplicates int `json:"duplicates"`
+	Migrated   int `json:"migrated"`
+}
+
+// legacyMergePipeline marks each legacy record that has a stored query for
+// the same question and user within legacyDuplicateWindow
+func legacyMergePipeline() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from": "queries",
+			"let":  bson.M{"text": "$query", "user": bson.M{"$ifNull": bson.A{"$user_id", ""}}, "ts": "$timestamp"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$text", "$$text"}},
+					bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$user_id", ""}}, "$$user"}},
+					bson.M{"$lte": bson.A{
+						bson.M{"$abs": bson.M{"$subtract": bson.A{"$timestamp", "$$ts"}}},
+						legacyDuplicateWindow.Milliseconds(),
+					}},
+				}}}},
+				bson.M{"$limit": 1},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "matches",
+		}}},
+		{{Key: "$addFields", Value: bson.M{"duplicate": bson.M{"$gt": bson.A{bson.M{"$size": "$matches"}, 0}}}}},
+		{{Key: "$project", Value: bson.M{"matches": 0}}},
+	}
+}
+
+// MergeLegacyQueryResponses folds the legacy query_responses collection
+// into queries so every request has one record. Records the queries
+// collection already holds are dropped; the rest are converted to queries,
+// keyed by their legacy ID so a rerun does not insert them twice. Merged
+// records are deleted from the legacy collection.
+func MergeLegacyQueryResponses(ctx context.Context, db *mongo.Database, logger *zap.Logger) (*LegacyMergeResult, error) {
+	legacy := db.Collection(LegacyQueryResponsesCollection)
+	queries := db.Collection("queries")
+
+	cursor, err := legacy.Aggregate(ctx, legacyMergePipeline())
+	if err != nil {
+		return nil, fmt.Errorf("failed to match legacy query responses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	result := &LegacyMergeResult{}
+	var batch []legacyQueryResponse
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ids := make([]primitive.ObjectID, len(batch))
+		var docs []interface{}
+		for i, record := range batch {
+			ids[i] = record.ID
+			if !record.Duplicate {
+				docs = append(docs, legacyToQuery(record))
+			}
+		}
+		if len(docs) > 0 {
+			_, err := queries.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+			// Records moved by an interrupted earlier run are already there
+			if err != nil && !mongo.IsDuplicateKeyError(err) {
+				return fmt.Errorf("failed to insert migrated queries: %w", err)
+			}
+		}
+		if _, err := legacy.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+			return fmt.Errorf("failed to delete merged legacy records: %w", err)
+		}
+		result.Migrated += len(docs)
+		result.Duplicates += len(batch) - len(docs)
+		batch = batch[:0]
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		var record legacyQueryResponse
+		if err := cursor.Decode(&record); err != nil {
+			return nil, fmt.Errorf("failed to decode legacy query response: %w", err)
+		}
+		result.Scanned++
+		batch = append(batch, record)
+		if len(batch) >= legacyMergeBatch {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read legacy query responses: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Merged legacy query responses",
+		zap.Int("scanned", result.Scanned),
+		zap.Int("duplicates", result.Duplicates),
+		zap.Int("migrated", result.Migrated))
+	return result, nil
+}
+
+// legacyToQuery converts a legacy record to the queries collection shape
+func legacyToQuery(record legacyQueryResponse) *entities.Query {
+	return &entities.Query{
+		ID:                 record.ID.Hex(),
+		UserID:             record.UserID,
+		Text:               record.Query,
+		IdentifiedConcepts: record.IdentifiedConcepts,
+		PrerequisitePath:   record.PrerequisitePath,
+		Response: entities.QueryResponse{
+			Explanation:      record.Explanation,
+			RetrievedContext: record.RetrievedContext,
+			LLMProvider:      record.LLMProvider,
+			LLMModel:         record.LLMModel,
+		},
+		Timestamp:        record.Timestamp,
+		ProcessingTimeMs: record.ResponseTime.Milliseconds(),
+		Success:          record.ProcessingSuccess,
+		ErrorMessage:     record.ErrorMessage,
+		Metadata: entities.QueryMetadata{
+			VectorHits:      record.VectorStoreHits,
+			GraphHits:       record.KnowledgeGraphHits,
+			ProcessingSteps: []entities.ProcessingStep{},
+		},
+	}
+}
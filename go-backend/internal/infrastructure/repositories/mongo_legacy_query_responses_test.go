@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+func TestMergeLegacyQueryResponsesMovesOnlyUnmatched(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("inserts unmatched records and deletes all", func(mt *mtest.T) {
+		duplicate, unmatched := primitive.NewObjectID(), primitive.NewObjectID()
+		asked := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.query_responses", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: duplicate}, {Key: "query", Value: "What is a limit?"}, {Key: "duplicate", Value: true}},
+				bson.D{
+					{Key: "_id", Value: unmatched},
+					{Key: "user_id", Value: "u1"},
+					{Key: "query", Value: "What is a derivative?"},
+					{Key: "explanation", Value: "A derivative measures..."},
+					{Key: "response_time", Value: int64(2 * time.Second)},
+					{Key: "processing_success", Value: true},
+					{Key: "timestamp", Value: asked},
+					{Key: "llm_model", Value: "gemini-2.5-flash"},
+					{Key: "vector_store_hits", Value: int32(3)},
+					{Key: "duplicate", Value: false},
+				},
+			),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: int32(1)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: int32(2)}),
+		)
+
+		result, err := MergeLegacyQueryResponses(context.Background(), mt.DB, zap.NewNop())
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if result.Scanned != 2 || result.Duplicates != 1 || result.Migrated != 1 {
+			mt.Errorf("unexpected result %+v", result)
+		}
+
+		events := mt.GetAllStartedEvents()
+		if len(events) != 3 {
+			mt.Fatalf("expected aggregate, insert and delete, got %d commands", len(events))
+		}
+		insert := events[1].Command
+		if insert.Lookup("insert").StringValue() != "queries" {
+			mt.Fatalf("expected an insert into queries, got %s", insert)
+		}
+		docs, _ := insert.Lookup("documents").Array().Values()
+		if len(docs) != 1 {
+			mt.Fatalf("expected only the unmatched record inserted, got %d", len(docs))
+		}
+		doc := docs[0].Document()
+		if doc.Lookup("_id").StringValue() != unmatched.Hex() ||
+			doc.Lookup("text").StringValue() != "What is a derivative?" ||
+			doc.Lookup("processing_time_ms").AsInt64() != 2000 ||
+			doc.Lookup("response", "llm_model").StringValue() != "gemini-2.5-flash" ||
+			doc.Lookup("metadata", "vector_hits").AsInt64() != 3 {
+			mt.Errorf("unexpected migrated query %s", doc)
+		}
+
+		deleted := events[2].Command
+		if deleted.Lookup("delete").StringValue() != LegacyQueryResponsesCollection {
+			mt.Errorf("expected merged records deleted from the legacy collection, got %s", deleted)
+		}
+	})
+}
+
+// TestMergeLegacyQueryResponsesLeavesOneRecordPerQuery runs the merge
+// against a real MongoDB. Set MONGODB_TEST_URI to enable it.
+func TestMergeLegacyQueryResponsesLeavesOneRecordPerQuery(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(ctx)
+
+	db := client.Database("mathprereq_test_legacy_merge")
+	defer db.Drop(ctx)
+
+	asked := time.Now().UTC().Truncate(time.Millisecond)
+	if _, err := db.Collection("queries").InsertOne(ctx, bson.M{
+		"_id": "q1", "user_id": "u1", "text": "What is a limit?", "timestamp": asked,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Collection(LegacyQueryResponsesCollection).InsertMany(ctx, []interface{}{
+		// The same request as q1, written a moment later
+		bson.M{"user_id": "u1", "query": "What is a limit?", "timestamp": asked.Add(200 * time.Millisecond)},
+		// Only ever stored in the legacy collection
+		bson.M{"user_id": "u1", "query": "What is a derivative?", "timestamp": asked},
+		// Same question asked again an hour later is a separate request
+		bson.M{"user_id": "u1", "query": "What is a limit?", "timestamp": asked.Add(time.Hour)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MergeLegacyQueryResponses(ctx, db, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 3 || result.Duplicates != 1 || result.Migrated != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if n, _ := db.Collection(LegacyQueryResponsesCollection).CountDocuments(ctx, bson.M{}); n != 0 {
+		t.Errorf("expected the legacy collection emptied, %d left", n)
+	}
+	if n, _ := db.Collection("queries").CountDocuments(ctx, bson.M{}); n != 3 {
+		t.Errorf("expected one query per request, got %d", n)
+	}
+
+	// A rerun finds nothing left to merge
+	if again, err := MergeLegacyQueryResponses(ctx, db, zap.NewNop()); err != nil || again.Scanned != 0 {
+		t.Errorf("expected an idempotent rerun, got %+v, %v", again, err)
+	}
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoPathSnapshotRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoPathSnapshotRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.PathSnapshotRepository {
+	collection := client.Database(dbName).Collection("path_snapshots")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	index := mongo.IndexModel{
+		Keys: bson.D{{Key: "concept_id", Value: 1}, {Key: "captured_at", Value: -1}},
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		logger.Warn("Failed to create indexes for path_snapshots", zap.Error(err))
+	}
+
+	return &mongoPathSnapshotRepository{
+		collection: collection,
+		logger:     logger,
+	}
+}
+
+func (r *mongoPathSnapshotRepository) Save(ctx context.Context, snapshot *entities.PathSnapshot) error {
+	if _, err := r.collection.InsertOne(ctx, snapshot); err != nil {
+		return fmt.Errorf("failed to save path snapshot: %w", err)
+	}
+
+	r.logger.Info("Path snapshot saved",
+		zap.String("snapshot_id", snapshot.ID),
+		zap.String("concept_id", snapshot.ConceptID),
+		zap.Int("prerequisites", len(snapshot.Prerequisites)))
+
+	return nil
+}
+
+func (r *mongoPathSnapshotRepository) FindByID(ctx context.Context, id string) (*entities.PathSnapshot, error) {
+	return r.findOne(ctx, bson.M{"_id": id}, options.FindOne())
+}
+
+func (r *mongoPathSnapshotRepository) FindLatestByConcept(ctx context.Context, conceptID string) (*entities.PathSnapshot, error) {
+	opts := options.FindOne().SetSort(bson.M{"captured_at": -1})
+	return r.findOne(ctx, bson.M{"concept_id": conceptID}, opts)
+}
+
+func (r *mongoPathSnapshotRepository) DeleteByConcept(ctx context.Context, conceptID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"concept_id": conceptID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete path snapshots: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *mongoPathSnapshotRepository) findOne(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*entities.PathSnapshot, error) {
+	var snapshot entities.PathSnapshot
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&snapshot)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find path snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
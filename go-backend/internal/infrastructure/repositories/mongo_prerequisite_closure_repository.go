@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoPrerequisiteClosureRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoPrerequisiteClosureRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.PrerequisiteClosureRepository {
+	return &mongoPrerequisiteClosureRepository{
+		collection: client.Database(dbName).Collection("prerequisite_closures"),
+		logger:     logger,
+	}
+}
+
+func (r *mongoPrerequisiteClosureRepository) ReplaceAll(ctx context.Context, closures []*entities.PrerequisiteClosure, computedAt time.Time) error {
+	if len(closures) > 0 {
+		writes := make([]mongo.WriteModel, len(closures))
+		for i, closure := range closures {
+			writes[i] = mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": closure.ConceptID}).
+				SetReplacement(closure).
+				SetUpsert(true)
+		}
+		if _, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return fmt.Errorf("failed to save prerequisite closures: %w", err)
+		}
+	}
+
+	// Concepts no longer in the graph were not rewritten by this run
+	deleted, err := r.collection.DeleteMany(ctx, bson.M{"computed_at": bson.M{"$lt": computedAt}})
+	if err != nil {
+		return fmt.Errorf("failed to remove stale prerequisite closures: %w", err)
+	}
+
+	r.logger.Info("Prerequisite closures saved",
+		zap.Int("concepts", len(closures)),
+		zap.Int64("removed", deleted.DeletedCount))
+
+	return nil
+}
+
+func (r *mongoPrerequisiteClosureRepository) FindByConceptID(ctx context.Context, conceptID string) (*entities.PrerequisiteClosure, error) {
+	var closure entities.PrerequisiteClosure
+	err := r.collection.FindOne(ctx, bson.M{"_id": conceptID}).Decode(&closure)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find prerequisite closure: %w", err)
+	}
+	return &closure, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/mathprereq/internal/domain/entities"
@@ -16,6 +18,25 @@ import (
 	"go.uber.org/zap"
 )
 
+// queryCountersCollection holds daily counts for queries that analytics sampling did not store in full
+const queryCountersCollection = "query_counters"
+
+// uncategorizedCategory labels queries stored without a subject category
+const uncategorizedCategory = "uncategorized"
+
+type queryCounter struct {
+	Date              time.Time                  `bson:"date"`
+	TotalQueries      int64                      `bson:"total_queries"`
+	SuccessfulQueries int64                      `bson:"successful_queries"`
+	TotalProcessingMs int64                      `bson:"total_processing_ms"`
+	Categories        map[string]categoryCounter `bson:"categories,omitempty"`
+}
+
+type categoryCounter struct {
+	TotalQueries      int64 `bson:"total_queries"`
+	SuccessfulQueries int64 `bson:"successful_queries"`
+}
+
 type mongoQueryRepository struct {
 	client     *mongo.Client
 	database   *mongo.Database
@@ -35,6 +56,35 @@ func NewMongoQueryRepository(client *mongo.Client, dbName string, logger *zap.Lo
 	}
 }
 
+// EnsureQueryIndexes creates the indexes query lookups rely on. It is kept
+// out of the constructor so repository tests need not mock index creation.
+func EnsureQueryIndexes(ctx context.Context, client *mongo.Client, dbName string) error {
+	indexes := client.Database(dbName).Collection("queries").Indexes()
+	index := mongo.IndexModel{
+		Keys: bson.D{{Key: "question_key", Value: 1}, {Key: "timestamp", Value: -1}},
+	}
+	if _, err := indexes.CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create question key index: %w", err)
+	}
+	// Only concept queries carry a concept key
+	conceptKeyIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "concept_key", Value: 1}, {Key: "timestamp", Value: -1}},
+		Options: options.Index().SetSparse(true),
+	}
+	if _, err := indexes.CreateOne(ctx, conceptKeyIndex); err != nil {
+		return fmt.Errorf("failed to create concept key index: %w", err)
+	}
+	// Only queries assigned to an experiment are indexed
+	experimentIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "experiment.name", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}
+	if _, err := indexes.CreateOne(ctx, experimentIndex); err != nil {
+		return fmt.Errorf("failed to create experiment index: %w", err)
+	}
+	return nil
+}
+
 // Helper method to get collection
 func (r *mongoQueryRepository) getCollection(name string) *mongo.Collection {
 	return r.database.Collection(name)
@@ -49,6 +99,65 @@ func (r *mongoQueryRepository) Save(ctx context.Context, query *entities.Query)
 	return nil
 }
 
+// RecordUnsampled increments the daily counters for a query that was not stored in full
+func (r *mongoQueryRepository) RecordUnsampled(ctx context.Context, query *entities.Query) error {
+	day := query.Timestamp.UTC().Truncate(24 * time.Hour)
+
+	successful := 0
+	if query.Success {
+		successful = 1
+	}
+
+	inc := bson.M{
+		"total_queries":       1,
+		"successful_queries":  successful,
+		"total_processing_ms": query.ProcessingTimeMs,
+	}
+	category := counterCategoryKey(query.Category)
+	inc["categories."+category+".total_queries"] = 1
+	inc["categories."+category+".successful_queries"] = successful
+
+	update := bson.M{
+		"$setOnInsert": bson.M{"date": day},
+		"$inc":         inc,
+	}
+
+	_, err := r.getCollection(queryCountersCollection).UpdateOne(ctx,
+		bson.M{"_id": day.Format("2006-01-02")},
+		update,
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to record unsampled query: %w", err)
+	}
+	return nil
+}
+
+// counterCategoryKey makes a category usable as a field name in the counters
+// document, where dots and a leading $ would be read as paths and operators
+func counterCategoryKey(category string) string {
+	category = strings.NewReplacer(".", "_", "$", "_").Replace(strings.TrimSpace(category))
+	if category == "" {
+		return uncategorizedCategory
+	}
+	return category
+}
+
+// unsampledCounters sums the daily counters of unsampled queries, optionally within a date range
+func (r *mongoQueryRepository) unsampledCounters(ctx context.Context, match bson.M) ([]queryCounter, error) {
+	opts := options.Find().SetSort(bson.M{"date": 1})
+	cursor, err := r.getCollection(queryCountersCollection).Find(ctx, match, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query counters: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var counters []queryCounter
+	if err := cursor.All(ctx, &counters); err != nil {
+		return nil, fmt.Errorf("failed to decode query counters: %w", err)
+	}
+	return counters, nil
+}
+
 // FindByConceptName finds a successful query that contains the specified concept
 func (r *mongoQueryRepository) FindByConceptName(ctx context.Context, conceptName string) (*entities.Query, error) {
 	collection := r.database.Collection("queries")
@@ -79,12 +188,28 @@ func (r *mongoQueryRepository) FindByConceptName(ctx context.Context, conceptNam
 			{
 				"success": true,
 			},
+			// Mistake explanations answer a student's attempt, not the concept
+			{
+				"kind": bson.M{"$exists": false},
+			},
 			{
 				"response.explanation": bson.M{
 					"$exists": true,
 					"$ne":     "",
 				},
 			},
+			// A trimmed copy is not a whole answer to serve again
+			{
+				"response.explanation_trimmed": bson.M{"$ne": true},
+			},
+			// Answers tailored to one user's preferences are not shared
+			{
+				"preferences": bson.M{"$exists": false},
+			},
+			// Nor are answers from a tester's model override
+			{
+				"model_override": bson.M{"$exists": false},
+			},
 		},
 	}
 
@@ -109,6 +234,58 @@ func (r *mongoQueryRepository) FindByConceptName(ctx context.Context, conceptNam
 	return query, nil
 }
 
+// FindByConceptKey finds the newest successful explanation of a concept
+// stored under its normalized key, skipping answers tailored to one user
+func (r *mongoQueryRepository) FindByConceptKey(ctx context.Context, conceptKey string) (*entities.Query, error) {
+	filter := bson.M{
+		"concept_key":          conceptKey,
+		"success":              true,
+		"kind":                 bson.M{"$exists": false},
+		"preferences":          bson.M{"$exists": false},
+		"model_override":       bson.M{"$exists": false},
+		"response.explanation": bson.M{"$exists": true, "$ne": ""},
+		// A trimmed copy is not a whole answer to serve again
+		"response.explanation_trimmed": bson.M{"$ne": true},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+
+	var query entities.Query
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&query); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find query by concept key: %w", err)
+	}
+	return &query, nil
+}
+
+// FindByQuestionKey finds the newest successful answer to the same normalized
+// question in the same language, skipping fallback templates
+func (r *mongoQueryRepository) FindByQuestionKey(ctx context.Context, questionKey, language string, since time.Time) (*entities.Query, error) {
+	filter := bson.M{
+		"question_key":      questionKey,
+		"language":          language,
+		"success":           true,
+		"kind":              bson.M{"$exists": false},
+		"response.fallback": bson.M{"$ne": true},
+		"preferences":       bson.M{"$exists": false},
+		"model_override":    bson.M{"$exists": false},
+		"timestamp":         bson.M{"$gte": since},
+		// A trimmed copy is not a whole answer to serve again
+		"response.explanation_trimmed": bson.M{"$ne": true},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+
+	var query entities.Query
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&query); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find query by question key: %w", err)
+	}
+	return &query, nil
+}
+
 // bsonToQuery converts a BSON document to a Query entity
 func (r *mongoQueryRepository) bsonToQuery(doc bson.M) (*entities.Query, error) {
 	// Extract basic fields
@@ -150,6 +327,7 @@ func (r *mongoQueryRepository) bsonToQuery(doc bson.M) (*entities.Query, error)
 		if explanation, ok := resp["explanation"].(string); ok {
 			response.Explanation = explanation
 		}
+		response.ExplanationTrimmed, _ = resp["explanation_trimmed"].(bool)
 		if provider, ok := resp["llm_provider"].(string); ok {
 			response.LLMProvider = provider
 		}
@@ -163,6 +341,22 @@ func (r *mongoQueryRepository) bsonToQuery(doc bson.M) (*entities.Query, error)
 				}
 			}
 		}
+		if chunks, ok := resp["context_chunks"].(bson.A); ok {
+			for _, c := range chunks {
+				chunkDoc, ok := c.(bson.M)
+				if !ok {
+					continue
+				}
+				chunk := entities.ContextChunk{}
+				chunk.Content, _ = chunkDoc["content"].(string)
+				chunk.Score, _ = chunkDoc["score"].(float64)
+				chunk.Source, _ = chunkDoc["source"].(string)
+				chunk.Chapter, _ = chunkDoc["chapter"].(string)
+				chunk.Concept, _ = chunkDoc["concept"].(string)
+				chunk.SourceClass, _ = chunkDoc["source_class"].(string)
+				response.ContextChunks = append(response.ContextChunks, chunk)
+			}
+		}
 	}
 
 	// Handle timestamp
@@ -194,12 +388,20 @@ func (r *mongoQueryRepository) FindByID(ctx context.Context, id string) (*entiti
 	var query entities.Query
 	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&query)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
 		return nil, fmt.Errorf("failed to find query: %w", err)
 	}
 	return &query, nil
 }
 
 func (r *mongoQueryRepository) FindByUserID(ctx context.Context, userID string, limit int) ([]*entities.Query, error) {
+	// Anonymous queries have no user_id stored, so an empty ID matches
+	// nothing rather than meaning every user; List covers that
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
 	collection := r.collection
 
 	filter := bson.M{"user_id": userID}
@@ -223,6 +425,63 @@ func (r *mongoQueryRepository) FindByUserID(ctx context.Context, userID string,
 	return queries, nil
 }
 
+func (r *mongoQueryRepository) List(ctx context.Context, filter repositories.QueryListFilter) ([]*entities.Query, int, error) {
+	query := queryListFilter(filter)
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count queries: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(filter.Offset))
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list queries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	queries := []*entities.Query{}
+	if err := cursor.All(ctx, &queries); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode queries: %w", err)
+	}
+	return queries, int(total), nil
+}
+
+// queryListFilter translates a listing filter into a MongoDB filter
+func queryListFilter(filter repositories.QueryListFilter) bson.M {
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	switch filter.Status {
+	case repositories.QueryListSuccess:
+		query["success"] = true
+		query["response.fallback"] = bson.M{"$ne": true}
+	case repositories.QueryListFallback:
+		query["success"] = true
+		query["response.fallback"] = true
+	case repositories.QueryListFailed:
+		query["success"] = false
+	}
+	if filter.Since != nil || filter.Until != nil {
+		timestamp := bson.M{}
+		if filter.Since != nil {
+			timestamp["$gte"] = *filter.Since
+		}
+		if filter.Until != nil {
+			timestamp["$lt"] = *filter.Until
+		}
+		query["timestamp"] = timestamp
+	}
+	return query
+}
+
 func (r *mongoQueryRepository) GetQueryStats(ctx context.Context) (*repositories.QueryStats, error) {
 	collection := r.collection
 
@@ -257,40 +516,110 @@ func (r *mongoQueryRepository) GetQueryStats(ctx context.Context) (*repositories
 		}
 	}
 
+	categories, err := r.queryCategoryCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fold in queries that were only counted because of analytics sampling
+	totalProcessingMs := result.AvgProcessingTime * float64(result.TotalQueries)
+	counters, err := r.unsampledCounters(ctx, bson.M{})
+	if err != nil {
+		r.logger.Warn("Failed to include unsampled query counters", zap.Error(err))
+	}
+	for _, counter := range counters {
+		result.TotalQueries += counter.TotalQueries
+		result.SuccessfulQueries += counter.SuccessfulQueries
+		totalProcessingMs += float64(counter.TotalProcessingMs)
+		for category, count := range counter.Categories {
+			total := categories[category]
+			total.TotalQueries += count.TotalQueries
+			total.SuccessfulQueries += count.SuccessfulQueries
+			categories[category] = total
+		}
+	}
+
 	successRate := float64(0)
+	avgProcessingTime := float64(0)
 	if result.TotalQueries > 0 {
 		successRate = float64(result.SuccessfulQueries) / float64(result.TotalQueries) * 100
+		avgProcessingTime = totalProcessingMs / float64(result.TotalQueries)
 	}
 
 	return &repositories.QueryStats{
 		TotalQueries:    result.TotalQueries,
 		SuccessRate:     successRate,
-		AvgResponseTime: result.AvgProcessingTime,
+		AvgResponseTime: avgProcessingTime,
+		ByCategory:      categoryStats(categories),
 	}, nil
 }
 
-func (r *mongoQueryRepository) GetPopularConcepts(ctx context.Context, limit int) ([]repositories.ConceptPopularity, error) {
-	collection := r.collection
-
+// queryCategoryCounts counts stored queries and successes per subject category
+func (r *mongoQueryRepository) queryCategoryCounts(ctx context.Context) (map[string]categoryCounter, error) {
 	pipeline := []bson.M{
-		{"$unwind": "$identified_concepts"},
 		{
 			"$group": bson.M{
-				"_id":   "$identified_concepts",
-				"count": bson.M{"$sum": 1},
-			},
-		},
-		{"$sort": bson.M{"count": -1}},
-		{"$limit": limit},
-		{
-			"$project": bson.M{
-				"concept_name": "$_id",
-				"query_count":  "$count",
-				"_id":          0,
+				"_id":           bson.M{"$ifNull": bson.A{"$category", uncategorizedCategory}},
+				"total_queries": bson.M{"$sum": 1},
+				"successful_queries": bson.M{
+					"$sum": bson.M{"$cond": bson.M{"if": "$success", "then": 1, "else": 0}},
+				},
 			},
 		},
 	}
 
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get query category stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Category          string `bson:"_id"`
+		TotalQueries      int64  `bson:"total_queries"`
+		SuccessfulQueries int64  `bson:"successful_queries"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode query category stats: %w", err)
+	}
+
+	counts := make(map[string]categoryCounter, len(rows))
+	for _, row := range rows {
+		counts[row.Category] = categoryCounter{
+			TotalQueries:      row.TotalQueries,
+			SuccessfulQueries: row.SuccessfulQueries,
+		}
+	}
+	return counts, nil
+}
+
+// categoryStats orders the per-category counts by volume, busiest first
+func categoryStats(counts map[string]categoryCounter) []repositories.CategoryStats {
+	stats := make([]repositories.CategoryStats, 0, len(counts))
+	for category, count := range counts {
+		successRate := float64(0)
+		if count.TotalQueries > 0 {
+			successRate = float64(count.SuccessfulQueries) / float64(count.TotalQueries) * 100
+		}
+		stats = append(stats, repositories.CategoryStats{
+			Category:     category,
+			TotalQueries: count.TotalQueries,
+			SuccessRate:  successRate,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalQueries != stats[j].TotalQueries {
+			return stats[i].TotalQueries > stats[j].TotalQueries
+		}
+		return stats[i].Category < stats[j].Category
+	})
+	return stats
+}
+
+func (r *mongoQueryRepository) GetPopularConcepts(ctx context.Context, limit int, halfLife time.Duration) ([]repositories.ConceptPopularity, error) {
+	collection := r.collection
+	pipeline := popularConceptsPipeline(limit, halfLife, time.Now())
+
 	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get popular concepts: %w", err)
@@ -309,6 +638,242 @@ func (r *mongoQueryRepository) GetPopularConcepts(ctx context.Context, limit int
 	return concepts, nil
 }
 
+func (r *mongoQueryRepository) GetConceptPairs(ctx context.Context, limit int) ([]repositories.ConceptPair, error) {
+	cursor, err := r.collection.Aggregate(ctx, conceptPairsPipeline(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get concept pairs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	pairs := []repositories.ConceptPair{}
+	if err := cursor.All(ctx, &pairs); err != nil {
+		return nil, fmt.Errorf("failed to decode concept pairs: %w", err)
+	}
+	return pairs, nil
+}
+
+func (r *mongoQueryRepository) GetStepDurations(ctx context.Context, since, until time.Time) ([]repositories.StepDurations, error) {
+	cursor, err := r.collection.Aggregate(ctx, stepDurationsPipeline(since, until))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get step durations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	steps := []repositories.StepDurations{}
+	if err := cursor.All(ctx, &steps); err != nil {
+		return nil, fmt.Errorf("failed to decode step durations: %w", err)
+	}
+	return steps, nil
+}
+
+func (r *mongoQueryRepository) GetConceptOutcomes(ctx context.Context, since time.Time) ([]repositories.ConceptOutcome, error) {
+	cursor, err := r.collection.Aggregate(ctx, conceptOutcomesPipeline(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get concept outcomes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	outcomes := []repositories.ConceptOutcome{}
+	if err := cursor.All(ctx, &outcomes); err != nil {
+		return nil, fmt.Errorf("failed to decode concept outcomes: %w", err)
+	}
+	return outcomes, nil
+}
+
+func (r *mongoQueryRepository) GetQueryConcepts(ctx context.Context, since time.Time, limit int) ([]repositories.QueryConcepts, error) {
+	filter := bson.M{
+		"timestamp":             bson.M{"$gte": since},
+		"kind":                  bson.M{"$exists": false},
+		"success":               true,
+		"identified_concepts.0": bson.M{"$exists": true},
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"text": 1, "identified_concepts": 1, "_id": 0}).
+		SetSort(bson.M{"timestamp": -1}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get query concepts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	pairs := []repositories.QueryConcepts{}
+	if err := cursor.All(ctx, &pairs); err != nil {
+		return nil, fmt.Errorf("failed to decode query concepts: %w", err)
+	}
+	return pairs, nil
+}
+
+func (r *mongoQueryRepository) GetExperimentOutcomes(ctx context.Context, experiment string) ([]repositories.ExperimentOutcome, error) {
+	cursor, err := r.collection.Aggregate(ctx, experimentOutcomesPipeline(experiment))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment outcomes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	outcomes := []repositories.ExperimentOutcome{}
+	if err := cursor.All(ctx, &outcomes); err != nil {
+		return nil, fmt.Errorf("failed to decode experiment outcomes: %w", err)
+	}
+	return outcomes, nil
+}
+
+// experimentOutcomesPipeline groups an experiment's queries by variant,
+// counting answered queries and fallbacks and averaging processing time
+func experimentOutcomesPipeline(experiment string) []bson.M {
+	answered := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$success", true}},
+		bson.M{"$ne": bson.A{"$response.fallback", true}},
+	}}
+	fallback := bson.M{"$eq": bson.A{"$response.fallback", true}}
+	return []bson.M{
+		{"$match": bson.M{"experiment.name": experiment}},
+		{"$group": bson.M{
+			"_id":               "$experiment.variant",
+			"queries":           bson.M{"$sum": 1},
+			"successful":        bson.M{"$sum": bson.M{"$cond": bson.A{answered, 1, 0}}},
+			"fallbacks":         bson.M{"$sum": bson.M{"$cond": bson.A{fallback, 1, 0}}},
+			"avg_processing_ms": bson.M{"$avg": "$processing_time_ms"},
+		}},
+		{"$project": bson.M{
+			"variant":           "$_id",
+			"queries":           1,
+			"successful":        1,
+			"fallbacks":         1,
+			"avg_processing_ms": 1,
+			"_id":               0,
+		}},
+		{"$sort": bson.M{"variant": 1}},
+	}
+}
+
+// conceptOutcomesPipeline counts queries and answered queries per concept.
+// Only regular questions are counted; mistake explanations are a different
+// kind of request. Names are lowercased so case variants share one count.
+func conceptOutcomesPipeline(since time.Time) []bson.M {
+	answered := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$success", true}},
+		bson.M{"$ne": bson.A{"$response.fallback", true}},
+	}}
+	return []bson.M{
+		{"$match": bson.M{
+			"timestamp":             bson.M{"$gte": since},
+			"kind":                  bson.M{"$exists": false},
+			"identified_concepts.0": bson.M{"$exists": true},
+		}},
+		{"$unwind": "$identified_concepts"},
+		{"$group": bson.M{
+			"_id":        bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$identified_concepts"}}},
+			"queries":    bson.M{"$sum": 1},
+			"successful": bson.M{"$sum": bson.M{"$cond": bson.A{answered, 1, 0}}},
+		}},
+		{"$project": bson.M{
+			"concept_name": "$_id",
+			"queries":      1,
+			"successful":   1,
+			"_id":          0,
+		}},
+	}
+}
+
+// stepDurationsPipeline collects each step's durations, sorted so
+// percentiles can be read off by index
+func stepDurationsPipeline(since, until time.Time) []bson.M {
+	return []bson.M{
+		{"$match": bson.M{
+			"timestamp":                   bson.M{"$gte": since, "$lte": until},
+			"metadata.processing_steps.0": bson.M{"$exists": true},
+		}},
+		{"$unwind": "$metadata.processing_steps"},
+		{"$sort": bson.M{"metadata.processing_steps.duration": 1}},
+		{"$group": bson.M{
+			"_id":       "$metadata.processing_steps.name",
+			"durations": bson.M{"$push": "$metadata.processing_steps.duration"},
+		}},
+		{"$sort": bson.M{"_id": 1}},
+	}
+}
+
+// popularConceptsPipeline counts the queries identifying each concept. With
+// a positive halfLife each query also adds 0.5^(age/halfLife) to the
+// concept's score, which orders the result, so a recent spike outranks a
+// concept asked about often long ago. Queries without a timestamp add
+// nothing to the score. The half-life is divided in fractional milliseconds,
+// the unit of date differences, so a sub-millisecond one never divides by 0.
+func popularConceptsPipeline(limit int, halfLife time.Duration, now time.Time) []bson.M {
+	group := bson.M{
+		"_id":   "$identified_concepts",
+		"count": bson.M{"$sum": 1},
+	}
+	sort := bson.D{{Key: "count", Value: -1}}
+	project := bson.M{
+		"concept_name": "$_id",
+		"query_count":  "$count",
+		"_id":          0,
+	}
+	if halfLife > 0 {
+		age := bson.M{"$subtract": bson.A{now, "$timestamp"}} // null without a timestamp
+		group["score"] = bson.M{"$sum": bson.M{"$pow": bson.A{0.5,
+			bson.M{"$divide": bson.A{age, float64(halfLife) / float64(time.Millisecond)}}}}}
+		sort = bson.D{{Key: "score", Value: -1}, {Key: "count", Value: -1}}
+		project["score"] = "$score"
+	}
+	sort = append(sort, bson.E{Key: "_id", Value: 1})
+
+	return []bson.M{
+		{"$unwind": "$identified_concepts"},
+		{"$group": group},
+		{"$sort": sort},
+		{"$limit": limit},
+		{"$project": project},
+	}
+}
+
+// conceptPairsPipeline counts co-occurring identified concepts. Names are
+// lowercased and deduplicated per query, then the array is crossed with
+// itself keeping only a < b so each unordered pair is counted once per query.
+func conceptPairsPipeline(limit int) []bson.M {
+	return []bson.M{
+		{"$match": bson.M{"identified_concepts.1": bson.M{"$exists": true}}},
+		{
+			"$project": bson.M{
+				"concepts": bson.M{"$setUnion": bson.A{
+					bson.M{"$map": bson.M{
+						"input": "$identified_concepts",
+						"as":    "c",
+						"in":    bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$$c"}}},
+					}},
+					bson.A{},
+				}},
+			},
+		},
+		{"$project": bson.M{"concept_a": "$concepts", "concept_b": "$concepts"}},
+		{"$unwind": "$concept_a"},
+		{"$unwind": "$concept_b"},
+		{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+			bson.M{"$lt": bson.A{"$concept_a", "$concept_b"}},
+			bson.M{"$ne": bson.A{"$concept_a", ""}},
+		}}}},
+		{
+			"$group": bson.M{
+				"_id":   bson.M{"a": "$concept_a", "b": "$concept_b"},
+				"count": bson.M{"$sum": 1},
+			},
+		},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id.a", Value: 1}, {Key: "_id.b", Value: 1}}},
+		{"$limit": limit},
+		{
+			"$project": bson.M{
+				"_id":         0,
+				"concept_a":   "$_id.a",
+				"concept_b":   "$_id.b",
+				"query_count": "$count",
+			},
+		},
+	}
+}
+
 func (r *mongoQueryRepository) GetQueryTrends(ctx context.Context, days int) ([]repositories.QueryTrend, error) {
 	collection := r.collection
 
@@ -346,7 +911,24 @@ func (r *mongoQueryRepository) GetQueryTrends(ctx context.Context, days int) ([]
 	}
 	defer cursor.Close(ctx)
 
-	var trends []repositories.QueryTrend
+	type dailyTotals struct {
+		queries    int64
+		successful int64
+	}
+	totals := make(map[time.Time]*dailyTotals)
+	var dates []time.Time
+
+	addTotals := func(date time.Time, queries, successful int64) {
+		t, ok := totals[date]
+		if !ok {
+			t = &dailyTotals{}
+			totals[date] = t
+			dates = append(dates, date)
+		}
+		t.queries += queries
+		t.successful += successful
+	}
+
 	for cursor.Next(ctx) {
 		var result struct {
 			ID struct {
@@ -362,14 +944,34 @@ func (r *mongoQueryRepository) GetQueryTrends(ctx context.Context, days int) ([]
 			continue
 		}
 
+		date := time.Date(result.ID.Year, time.Month(result.ID.Month), result.ID.Day, 0, 0, 0, 0, time.UTC)
+		addTotals(date, result.QueryCount, result.SuccessfulQueries)
+	}
+
+	// Fold in queries that were only counted because of analytics sampling
+	counters, err := r.unsampledCounters(ctx, bson.M{
+		"date": bson.M{"$gte": startDate.UTC().Truncate(24 * time.Hour), "$lte": endDate},
+	})
+	if err != nil {
+		r.logger.Warn("Failed to include unsampled query counters", zap.Error(err))
+	}
+	for _, counter := range counters {
+		addTotals(counter.Date.UTC(), counter.TotalQueries, counter.SuccessfulQueries)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	trends := make([]repositories.QueryTrend, 0, len(dates))
+	for _, date := range dates {
+		t := totals[date]
 		successRate := float64(0)
-		if result.QueryCount > 0 {
-			successRate = float64(result.SuccessfulQueries) / float64(result.QueryCount) * 100
+		if t.queries > 0 {
+			successRate = float64(t.successful) / float64(t.queries) * 100
 		}
 
 		trends = append(trends, repositories.QueryTrend{
-			Date:        time.Date(result.ID.Year, time.Month(result.ID.Month), result.ID.Day, 0, 0, 0, 0, time.UTC),
-			QueryCount:  result.QueryCount,
+			Date:        date,
+			QueryCount:  t.queries,
 			SuccessRate: successRate,
 		})
 	}
@@ -384,7 +986,7 @@ func (r *mongoQueryRepository) GetAnalytics(ctx context.Context, filters reposit
 		return nil, err
 	}
 
-	popular, err := r.GetPopularConcepts(ctx, 10)
+	popular, err := r.GetPopularConcepts(ctx, 10, 0)
 	if err != nil {
 		popular = []repositories.ConceptPopularity{}
 	}
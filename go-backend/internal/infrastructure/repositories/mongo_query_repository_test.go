@@ -0,0 +1,564 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// TestGetConceptPairs runs the co-occurrence aggregation against a real MongoDB.
+// Set MONGODB_TEST_URI to enable it.
+func TestGetConceptPairs(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(ctx)
+
+	dbName := "mathprereq_test_concept_pairs"
+	db := client.Database(dbName)
+	defer db.Drop(ctx)
+
+	docs := []interface{}{
+		bson.M{"identified_concepts": bson.A{"Limits", "Derivatives"}},
+		bson.M{"identified_concepts": bson.A{"derivatives", "limits", "Limits"}},
+		bson.M{"identified_concepts": bson.A{"derivatives", "chain rule", "limits"}},
+		bson.M{"identified_concepts": bson.A{"integrals"}},
+		bson.M{"identified_concepts": bson.A{}},
+	}
+	if _, err := db.Collection("queries").InsertMany(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewMongoQueryRepository(client, dbName, zap.NewNop())
+	pairs, err := repo.GetConceptPairs(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []repositories.ConceptPair{
+		{ConceptA: "derivatives", ConceptB: "limits", QueryCount: 3},
+		{ConceptA: "chain rule", ConceptB: "derivatives", QueryCount: 1},
+		{ConceptA: "chain rule", ConceptB: "limits", QueryCount: 1},
+	}
+	if len(pairs) != len(want) {
+		t.Fatalf("expected %d pairs, got %+v", len(want), pairs)
+	}
+	for i := range want {
+		if pairs[i] != want[i] {
+			t.Errorf("pair %d = %+v, want %+v", i, pairs[i], want[i])
+		}
+	}
+}
+
+func TestGetConceptPairsDecodesAggregation(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("decodes pairs", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+			bson.D{{Key: "concept_a", Value: "derivatives"}, {Key: "concept_b", Value: "limits"}, {Key: "query_count", Value: int64(3)}},
+			bson.D{{Key: "concept_a", Value: "chain rule"}, {Key: "concept_b", Value: "derivatives"}, {Key: "query_count", Value: int64(1)}},
+		))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		pairs, err := repo.GetConceptPairs(context.Background(), 5)
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		want := []repositories.ConceptPair{
+			{ConceptA: "derivatives", ConceptB: "limits", QueryCount: 3},
+			{ConceptA: "chain rule", ConceptB: "derivatives", QueryCount: 1},
+		}
+		if len(pairs) != len(want) || pairs[0] != want[0] || pairs[1] != want[1] {
+			mt.Errorf("GetConceptPairs() = %+v, want %+v", pairs, want)
+		}
+
+		// The pipeline the server sees, stage by stage
+		stages, err := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		if err != nil {
+			mt.Fatal(err)
+		}
+		var names []string
+		for _, stage := range stages {
+			elements, _ := stage.Document().Elements()
+			names = append(names, elements[0].Key())
+		}
+		wantStages := []string{"$match", "$project", "$project", "$unwind", "$unwind", "$match", "$group", "$sort", "$limit", "$project"}
+		if !reflect.DeepEqual(names, wantStages) {
+			mt.Fatalf("pipeline stages = %v, want %v", names, wantStages)
+		}
+
+		// Only queries naming at least two concepts are unwound
+		if _, err := stages[0].Document().LookupErr("$match", "identified_concepts.1", "$exists"); err != nil {
+			mt.Errorf("first stage = %v, want queries with two or more concepts", stages[0])
+		}
+		// Concept names are trimmed, lowercased and deduplicated per query
+		concepts := stages[1].Document().Lookup("$project", "concepts", "$setUnion").Array().Index(0).Value().Document()
+		if _, err := concepts.LookupErr("$map", "in", "$toLower", "$trim"); err != nil {
+			mt.Errorf("concepts are not normalized: %v", concepts)
+		}
+		// Each unordered pair is counted once, without blank names
+		pairFilter := stages[5].Document().Lookup("$match", "$expr", "$and").Array()
+		if _, err := pairFilter.Index(0).Value().Document().LookupErr("$lt"); err != nil {
+			mt.Errorf("pairs are not ordered: %v", pairFilter)
+		}
+		sortKeys, _ := stages[7].Document().Lookup("$sort").Document().Elements()
+		if len(sortKeys) != 3 || sortKeys[0].Key() != "count" || sortKeys[0].Value().Int32() != -1 {
+			mt.Errorf("sort = %v, want by count descending, then by name", stages[7])
+		}
+		if limit := stages[8].Document().Lookup("$limit").AsInt64(); limit != 5 {
+			mt.Errorf("limit = %d, want 5", limit)
+		}
+	})
+}
+
+// TestGetPopularConceptsDecay runs the popularity aggregation against a
+// real MongoDB. Set MONGODB_TEST_URI to enable it.
+func TestGetPopularConceptsDecay(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(ctx)
+
+	dbName := "mathprereq_test_popular_concepts"
+	db := client.Database(dbName)
+	defer db.Drop(ctx)
+
+	// Limits was asked about often two months ago; series spiked this week
+	now := time.Now()
+	var docs []interface{}
+	for i := 0; i < 10; i++ {
+		docs = append(docs, bson.M{"identified_concepts": bson.A{"limits"}, "timestamp": now.Add(-60 * 24 * time.Hour)})
+	}
+	for i := 0; i < 4; i++ {
+		docs = append(docs, bson.M{"identified_concepts": bson.A{"series"}, "timestamp": now.Add(-24 * time.Hour)})
+	}
+	if _, err := db.Collection("queries").InsertMany(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewMongoQueryRepository(client, dbName, zap.NewNop())
+
+	allTime, err := repo.GetPopularConcepts(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allTime) != 2 || allTime[0].ConceptName != "limits" || allTime[0].QueryCount != 10 || allTime[0].Score != 0 {
+		t.Errorf("all-time popularity = %+v, want limits first with 10 queries and no score", allTime)
+	}
+
+	decayed, err := repo.GetPopularConcepts(ctx, 10, 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decayed) != 2 || decayed[0].ConceptName != "series" || decayed[1].ConceptName != "limits" {
+		t.Fatalf("decayed popularity = %+v, want series before limits", decayed)
+	}
+	if decayed[1].QueryCount != 10 || decayed[0].Score <= decayed[1].Score {
+		t.Errorf("decayed popularity = %+v, want all-time counts kept and series scored higher", decayed)
+	}
+}
+
+func TestPopularConceptsPipelineSortsByScoreUnderDecay(t *testing.T) {
+	sortKeys := func(pipeline []bson.M) []string {
+		var keys []string
+		for _, stage := range pipeline {
+			if sort, ok := stage["$sort"].(bson.D); ok {
+				for _, e := range sort {
+					keys = append(keys, e.Key)
+				}
+			}
+		}
+		return keys
+	}
+
+	now := time.Now()
+	if got := sortKeys(popularConceptsPipeline(10, 0, now)); !reflect.DeepEqual(got, []string{"count", "_id"}) {
+		t.Errorf("all-time sort = %v, want count then name", got)
+	}
+	decayed := popularConceptsPipeline(10, time.Hour, now)
+	if got := sortKeys(decayed); !reflect.DeepEqual(got, []string{"score", "count", "_id"}) {
+		t.Errorf("decayed sort = %v, want score, count then name", got)
+	}
+	if _, ok := decayed[1]["$group"].(bson.M)["score"]; !ok {
+		t.Error("decayed pipeline does not compute a score")
+	}
+
+	// A half-life under a millisecond must not divide by zero
+	score := popularConceptsPipeline(10, 500*time.Microsecond, now)[1]["$group"].(bson.M)["score"].(bson.M)
+	exponent := score["$sum"].(bson.M)["$pow"].(bson.A)[1].(bson.M)["$divide"].(bson.A)
+	if divisor := exponent[1].(float64); divisor != 0.5 {
+		t.Errorf("500µs half-life divides by %v ms, want 0.5", divisor)
+	}
+}
+
+func TestGetPopularConceptsDecodesScore(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("decodes score", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+			bson.D{{Key: "concept_name", Value: "series"}, {Key: "query_count", Value: int64(4)}, {Key: "score", Value: 3.6}},
+		))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		concepts, err := repo.GetPopularConcepts(context.Background(), 5, 7*24*time.Hour)
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		want := repositories.ConceptPopularity{ConceptName: "series", QueryCount: 4, Score: 3.6}
+		if len(concepts) != 1 || concepts[0] != want {
+			mt.Errorf("GetPopularConcepts() = %+v, want %+v", concepts, want)
+		}
+	})
+}
+
+func TestGetStepDurationsDecodesAggregation(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("decodes durations", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "find_prerequisites"}, {Key: "durations", Value: bson.A{int64(time.Millisecond), int64(2 * time.Second)}}},
+		))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		steps, err := repo.GetStepDurations(context.Background(), time.Now().Add(-time.Hour), time.Now())
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		if len(steps) != 1 || steps[0].Step != "find_prerequisites" ||
+			len(steps[0].Durations) != 2 || steps[0].Durations[1] != 2*time.Second {
+			mt.Errorf("GetStepDurations() = %+v", steps)
+		}
+	})
+}
+
+func TestGetQueryStatsBreaksDownByCategory(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("merges stored and unsampled counts", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+				bson.D{{Key: "total_queries", Value: int64(4)}, {Key: "successful_queries", Value: int64(3)}, {Key: "avg_processing_time", Value: 100.0}},
+			),
+			mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "calculus"}, {Key: "total_queries", Value: int64(3)}, {Key: "successful_queries", Value: int64(2)}},
+				bson.D{{Key: "_id", Value: "uncategorized"}, {Key: "total_queries", Value: int64(1)}, {Key: "successful_queries", Value: int64(1)}},
+			),
+			mtest.CreateCursorResponse(0, "test.query_counters", mtest.FirstBatch,
+				bson.D{
+					{Key: "total_queries", Value: int64(4)},
+					{Key: "successful_queries", Value: int64(4)},
+					{Key: "total_processing_ms", Value: int64(400)},
+					{Key: "categories", Value: bson.D{
+						{Key: "calculus", Value: bson.D{{Key: "total_queries", Value: int64(1)}, {Key: "successful_queries", Value: int64(1)}}},
+						{Key: "algebra", Value: bson.D{{Key: "total_queries", Value: int64(3)}, {Key: "successful_queries", Value: int64(3)}}},
+					}},
+				},
+			),
+		)
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		stats, err := repo.GetQueryStats(context.Background())
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		if stats.TotalQueries != 8 {
+			mt.Errorf("TotalQueries = %d, want 8", stats.TotalQueries)
+		}
+		want := []repositories.CategoryStats{
+			{Category: "calculus", TotalQueries: 4, SuccessRate: 75},
+			{Category: "algebra", TotalQueries: 3, SuccessRate: 100},
+			{Category: "uncategorized", TotalQueries: 1, SuccessRate: 100},
+		}
+		if len(stats.ByCategory) != len(want) {
+			mt.Fatalf("ByCategory = %+v, want %+v", stats.ByCategory, want)
+		}
+		for i := range want {
+			if stats.ByCategory[i] != want[i] {
+				mt.Errorf("ByCategory[%d] = %+v, want %+v", i, stats.ByCategory[i], want[i])
+			}
+		}
+	})
+}
+
+func TestCounterCategoryKey(t *testing.T) {
+	tests := map[string]string{
+		"calculus":    "calculus",
+		" ":           uncategorizedCategory,
+		"stats.prob":  "stats_prob",
+		"$linear alg": "_linear alg",
+	}
+	for in, want := range tests {
+		if got := counterCategoryKey(in); got != want {
+			t.Errorf("counterCategoryKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFindByQuestionKey(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("decodes the newest answer", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: "q1"},
+				{Key: "text", Value: "What is a limit?"},
+				{Key: "question_key", Value: "abc"},
+				{Key: "language", Value: "en"},
+				{Key: "success", Value: true},
+				{Key: "response", Value: bson.D{{Key: "explanation", Value: "A limit is..."}}},
+			},
+		))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		query, err := repo.FindByQuestionKey(context.Background(), "abc", "en", time.Now().Add(-time.Hour))
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if query == nil || query.ID != "q1" || query.Response.Explanation != "A limit is..." {
+			mt.Errorf("FindByQuestionKey() = %+v", query)
+		}
+	})
+
+	mt.Run("returns nil on a miss", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		query, err := repo.FindByQuestionKey(context.Background(), "abc", "en", time.Now().Add(-time.Hour))
+		if err != nil || query != nil {
+			mt.Errorf("expected a clean miss, got %+v, %v", query, err)
+		}
+	})
+}
+
+func TestFindByConceptKey(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("filters on the key and skips tailored answers", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: "q1"},
+				{Key: "concept_key", Value: "derivative"},
+				{Key: "success", Value: true},
+				{Key: "response", Value: bson.D{{Key: "explanation", Value: "A derivative is..."}}},
+			},
+		))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		query, err := repo.FindByConceptKey(context.Background(), "derivative")
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if query == nil || query.ID != "q1" || query.ConceptKey != "derivative" {
+			mt.Errorf("FindByConceptKey() = %+v", query)
+		}
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		if key := filter.Lookup("concept_key").StringValue(); key != "derivative" {
+			mt.Errorf("expected the concept key filtered on, got %q", key)
+		}
+		if _, err := filter.LookupErr("preferences"); err != nil {
+			mt.Errorf("expected tailored answers excluded, got %v", filter)
+		}
+	})
+
+	mt.Run("returns nil on a miss", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		query, err := repo.FindByConceptKey(context.Background(), "derivative")
+		if err != nil || query != nil {
+			mt.Errorf("expected a clean miss, got %+v, %v", query, err)
+		}
+	})
+}
+
+// andClause finds the clause of an $and filter that filters on key
+func andClause(filter bson.Raw, key string) (bson.RawValue, bool) {
+	clauses, err := filter.Lookup("$and").Array().Values()
+	if err != nil {
+		return bson.RawValue{}, false
+	}
+	for _, clause := range clauses {
+		if value, err := clause.Document().LookupErr(key); err == nil {
+			return value, true
+		}
+	}
+	return bson.RawValue{}, false
+}
+
+func TestFindByConceptName(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("skips trimmed copies and tailored answers", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: "q1"},
+				{Key: "identified_concepts", Value: bson.A{"derivative"}},
+				{Key: "success", Value: true},
+				{Key: "response", Value: bson.D{{Key: "explanation", Value: "A derivative is..."}}},
+			},
+		))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		query, err := repo.FindByConceptName(context.Background(), "derivative")
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if query == nil || query.ID != "q1" {
+			mt.Errorf("FindByConceptName() = %+v", query)
+		}
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		trimmed, ok := andClause(filter, "response.explanation_trimmed")
+		if !ok {
+			mt.Fatalf("expected trimmed copies excluded, got %v", filter)
+		}
+		if ne := trimmed.Document().Lookup("$ne"); !ne.Boolean() {
+			mt.Errorf("expected explanation_trimmed $ne true, got %v", trimmed)
+		}
+		for _, key := range []string{"preferences", "model_override"} {
+			if _, ok := andClause(filter, key); !ok {
+				mt.Errorf("expected answers with %s excluded, got %v", key, filter)
+			}
+		}
+	})
+}
+
+func TestGetConceptOutcomesDecodesAggregation(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("decodes outcomes", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+			bson.D{{Key: "concept_name", Value: "limits"}, {Key: "queries", Value: int32(12)}, {Key: "successful", Value: int32(9)}},
+		))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		outcomes, err := repo.GetConceptOutcomes(context.Background(), time.Now().Add(-time.Hour))
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		want := repositories.ConceptOutcome{ConceptName: "limits", Queries: 12, Successful: 9}
+		if len(outcomes) != 1 || outcomes[0] != want {
+			mt.Errorf("GetConceptOutcomes() = %+v, want [%+v]", outcomes, want)
+		}
+	})
+}
+
+func TestQueryListFilter(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	got := queryListFilter(repositories.QueryListFilter{Status: repositories.QueryListSuccess, Since: &since})
+	want := bson.M{
+		"success":           true,
+		"response.fallback": bson.M{"$ne": true},
+		"timestamp":         bson.M{"$gte": since},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("queryListFilter() = %v, want %v", got, want)
+	}
+
+	// An empty user must not narrow the listing to anonymous queries
+	if got := queryListFilter(repositories.QueryListFilter{}); len(got) != 0 {
+		t.Errorf("expected an empty filter to match every query, got %v", got)
+	}
+}
+
+func TestListQueries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("counts and pages", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch, bson.D{{Key: "n", Value: int32(7)}}),
+			mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "q2"}, {Key: "user_id", Value: "u1"}, {Key: "success", Value: true}},
+				bson.D{{Key: "_id", Value: "q1"}, {Key: "success", Value: false}},
+			),
+		)
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		queries, total, err := repo.List(context.Background(), repositories.QueryListFilter{Limit: 2})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if total != 7 || len(queries) != 2 || queries[0].ID != "q2" || queries[1].UserID != "" {
+			mt.Errorf("List() = %+v, %d", queries, total)
+		}
+	})
+
+	mt.Run("requires a user to find by user", func(mt *mtest.T) {
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		if _, err := repo.FindByUserID(context.Background(), "", 10); err == nil {
+			mt.Error("expected an empty user ID to be rejected")
+		}
+	})
+}
+
+func TestFindByIDKeepsContextChunks(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("round trips scores and sources", func(mt *mtest.T) {
+		stored := entities.NewQuery("u1", "What is a limit?", "")
+		stored.Response.RetrievedContext = []string{"A limit describes..."}
+		stored.Response.ContextChunks = []entities.ContextChunk{{
+			Content:     "A limit describes...",
+			Score:       0.91,
+			Source:      "Calculus Vol 1",
+			Chapter:     "2.1",
+			Concept:     "limits",
+			SourceClass: "MathChunk",
+		}}
+
+		// Serve back exactly what Save would have written
+		raw, err := bson.Marshal(stored)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		var doc bson.D
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			mt.Fatal(err)
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.queries", mtest.FirstBatch, doc))
+
+		repo := NewMongoQueryRepository(mt.Client, "test", zap.NewNop())
+		query, err := repo.FindByID(context.Background(), stored.ID)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if query == nil || !reflect.DeepEqual(query.Response.ContextChunks, stored.Response.ContextChunks) {
+			mt.Errorf("FindByID() chunks = %+v, want %+v", query, stored.Response.ContextChunks)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoScrapeJobRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoScrapeJobRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.ScrapeJobRepository {
+	collection := client.Database(dbName).Collection("scrape_jobs")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		// Claim looks up pending jobs by retry time and running ones by lease
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "available_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "locked_until", Value: 1}}},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Warn("Failed to create indexes for scrape jobs", zap.Error(err))
+	}
+
+	return &mongoScrapeJobRepository{
+		collection: collection,
+		logger:     logger,
+	}
+}
+
+func (r *mongoScrapeJobRepository) Enqueue(ctx context.Context, job *entities.ScrapeJob) error {
+	if _, err := r.collection.InsertOne(ctx, job); err != nil {
+		return fmt.Errorf("failed to enqueue scrape job: %w", err)
+	}
+	return nil
+}
+
+// Claim takes the longest waiting available job in one findOneAndUpdate, so
+// two workers can never lease the same job
+func (r *mongoScrapeJobRepository) Claim(ctx context.Context, workerID string, lease time.Duration) (*entities.ScrapeJob, error) {
+	now := time.Now()
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": entities.ScrapeJobPending, "available_at": bson.M{"$lte": now}},
+		bson.M{
+			"status":       entities.ScrapeJobRunning,
+			"locked_until": bson.M{"$lt": now},
+			"$expr":        bson.M{"$lt": bson.A{"$attempts", "$max_attempts"}},
+		},
+	}}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       entities.ScrapeJobRunning,
+			"locked_by":    workerID,
+			"locked_until": now.Add(lease),
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "available_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job entities.ScrapeJob
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim scrape job: %w", err)
+	}
+	return &job, nil
+}
+
+func (r *mongoScrapeJobRepository) Complete(ctx context.Context, jobID, workerID string) error {
+	now := time.Now()
+	return r.release(ctx, jobID, workerID, bson.M{
+		"$set": bson.M{
+			"status":       entities.ScrapeJobDone,
+			"updated_at":   now,
+			"completed_at": now,
+		},
+		"$unset": bson.M{"locked_by": "", "locked_until": "", "last_error": ""},
+	})
+}
+
+func (r *mongoScrapeJobRepository) Retry(ctx context.Context, jobID, workerID string, availableAt time.Time, lastErr string) error {
+	return r.release(ctx, jobID, workerID, bson.M{
+		"$set": bson.M{
+			"status":       entities.ScrapeJobPending,
+			"available_at": availableAt,
+			"last_error":   lastErr,
+			"updated_at":   time.Now(),
+		},
+		"$unset": bson.M{"locked_by": "", "locked_until": ""},
+	})
+}
+
+func (r *mongoScrapeJobRepository) Fail(ctx context.Context, jobID, workerID, lastErr string) error {
+	return r.release(ctx, jobID, workerID, bson.M{
+		"$set": bson.M{
+			"status":     entities.ScrapeJobFailed,
+			"last_error": lastErr,
+			"updated_at": time.Now(),
+		},
+		"$unset": bson.M{"locked_by": "", "locked_until": ""},
+	})
+}
+
+// release applies update to a job only while workerID still holds it
+func (r *mongoScrapeJobRepository) release(ctx context.Context, jobID, workerID string, update bson.M) error {
+	filter := bson.M{"_id": jobID, "status": entities.ScrapeJobRunning, "locked_by": workerID}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update scrape job: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return repositories.ErrScrapeJobLeaseLost
+	}
+	return nil
+}
+
+func (r *mongoScrapeJobRepository) FailExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+	filter := bson.M{
+		"status":       entities.ScrapeJobRunning,
+		"locked_until": bson.M{"$lt": now},
+		"$expr":        bson.M{"$gte": bson.A{"$attempts", "$max_attempts"}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     entities.ScrapeJobFailed,
+			"last_error": "lease expired on the last attempt",
+			"updated_at": now,
+		},
+		"$unset": bson.M{"locked_by": "", "locked_until": ""},
+	}
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail expired scrape jobs: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *mongoScrapeJobRepository) Stats(ctx context.Context) (*repositories.ScrapeQueueStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":                 "$status",
+			"count":               bson.M{"$sum": 1},
+			"oldest_available_at": bson.M{"$min": "$available_at"},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scrape jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status            string    `bson:"_id"`
+		Count             int64     `bson:"count"`
+		OldestAvailableAt time.Time `bson:"oldest_available_at"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode scrape job counts: %w", err)
+	}
+
+	stats := &repositories.ScrapeQueueStats{}
+	for _, group := range groups {
+		switch group.Status {
+		case entities.ScrapeJobPending:
+			stats.Pending = group.Count
+			// Jobs waiting out a retry delay have not started waiting yet
+			if age := time.Since(group.OldestAvailableAt).Seconds(); age > 0 {
+				stats.OldestPendingAgeSeconds = age
+			}
+		case entities.ScrapeJobRunning:
+			stats.Running = group.Count
+		case entities.ScrapeJobDone:
+			stats.Done = group.Count
+		case entities.ScrapeJobFailed:
+			stats.Failed = group.Count
+		}
+	}
+	return stats, nil
+}
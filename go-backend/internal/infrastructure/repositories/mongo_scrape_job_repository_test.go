@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// TestScrapeJobClaimIsExclusive races workers for jobs against a real
+// MongoDB. Set MONGODB_TEST_URI to enable it.
+func TestScrapeJobClaimIsExclusive(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(ctx)
+
+	dbName := "mathprereq_test_scrape_jobs"
+	defer client.Database(dbName).Drop(ctx)
+
+	repo := NewMongoScrapeJobRepository(client, dbName, zap.NewNop())
+	const jobs = 30
+	for i := 0; i < jobs; i++ {
+		if err := repo.Enqueue(ctx, entities.NewScrapeJob("scrape_resources", "", []string{fmt.Sprintf("concept %d", i)}, 2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	claims := map[string]int{}
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			for {
+				job, err := repo.Claim(ctx, worker, time.Minute)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if job == nil {
+					return
+				}
+				mu.Lock()
+				claims[job.ID]++
+				mu.Unlock()
+			}
+		}(fmt.Sprintf("worker-%d", w))
+	}
+	wg.Wait()
+
+	if len(claims) != jobs {
+		t.Fatalf("expected %d jobs claimed, got %d", jobs, len(claims))
+	}
+	for id, n := range claims {
+		if n != 1 {
+			t.Errorf("job %s claimed %d times", id, n)
+		}
+	}
+}
+
+// TestScrapeJobExpiredLeaseIsReclaimed checks a job abandoned mid-run is
+// handed out again and the old holder can no longer finish it
+func TestScrapeJobExpiredLeaseIsReclaimed(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(ctx)
+
+	dbName := "mathprereq_test_scrape_job_lease"
+	defer client.Database(dbName).Drop(ctx)
+
+	repo := NewMongoScrapeJobRepository(client, dbName, zap.NewNop())
+	if err := repo.Enqueue(ctx, entities.NewScrapeJob("scrape_resources", "", []string{"limits"}, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := repo.Claim(ctx, "crashed", time.Millisecond)
+	if err != nil || first == nil {
+		t.Fatalf("expected a job, got %+v, %v", first, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	second, err := repo.Claim(ctx, "healthy", time.Minute)
+	if err != nil || second == nil || second.ID != first.ID || second.Attempts != 2 {
+		t.Fatalf("expected the job reclaimed on its second attempt, got %+v, %v", second, err)
+	}
+	if err := repo.Complete(ctx, first.ID, "crashed"); !errors.Is(err, repositories.ErrScrapeJobLeaseLost) {
+		t.Errorf("expected the old holder to have lost the lease, got %v", err)
+	}
+	if err := repo.Complete(ctx, second.ID, "healthy"); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := repo.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Done != 1 || stats.Pending != 0 || stats.Running != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestScrapeJobClaimDecodesJob(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("claims a job", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: "job-1"},
+				{Key: "concepts", Value: bson.A{"limits"}},
+				{Key: "status", Value: entities.ScrapeJobRunning},
+				{Key: "attempts", Value: 1},
+				{Key: "max_attempts", Value: 3},
+				{Key: "locked_by", Value: "worker-0"},
+			}},
+		})
+
+		repo := &mongoScrapeJobRepository{collection: mt.Coll, logger: zap.NewNop()}
+		job, err := repo.Claim(context.Background(), "worker-0", time.Minute)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if job == nil || job.ID != "job-1" || job.Attempts != 1 || job.LockedBy != "worker-0" {
+			mt.Errorf("unexpected job %+v", job)
+		}
+	})
+
+	mt.Run("empty queue", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		repo := &mongoScrapeJobRepository{collection: mt.Coll, logger: zap.NewNop()}
+		job, err := repo.Claim(context.Background(), "worker-0", time.Minute)
+		if err != nil || job != nil {
+			mt.Errorf("expected no job, got %+v, %v", job, err)
+		}
+	})
+
+	mt.Run("lease lost", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		repo := &mongoScrapeJobRepository{collection: mt.Coll, logger: zap.NewNop()}
+		if err := repo.Complete(context.Background(), "job-1", "worker-0"); !errors.Is(err, repositories.ErrScrapeJobLeaseLost) {
+			mt.Errorf("expected a lost lease, got %v", err)
+		}
+	})
+}
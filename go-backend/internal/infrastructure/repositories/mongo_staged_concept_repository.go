@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/mathprereq/internal/domain/entities"
@@ -38,6 +39,9 @@ func NewMongoStagedConceptRepository(client *mongo.Client, dbName string, logger
 		{
 			Keys: bson.D{{"occurrence_count", -1}},
 		},
+		{
+			Keys: bson.D{{"demand_score", -1}},
+		},
 		{
 			Keys: bson.D{{"identified_at", -1}},
 		},
@@ -84,7 +88,7 @@ func (r *mongoStagedConceptRepository) FindByConceptName(ctx context.Context, co
 	// Use case-insensitive regex for better matching
 	filter := bson.M{
 		"concept_name": bson.M{
-			"$regex":   fmt.Sprintf("^%s$", conceptName),
+			"$regex":   fmt.Sprintf("^%s$", regexp.QuoteMeta(conceptName)),
 			"$options": "i",
 		},
 	}
@@ -120,14 +124,33 @@ func (r *mongoStagedConceptRepository) Update(ctx context.Context, concept *enti
 	return nil
 }
 
+func (r *mongoStagedConceptRepository) UpdateDemand(ctx context.Context, concept *entities.StagedConcept) (bool, error) {
+	// A concurrent IncrementOccurrence bumps the count; its write wins
+	filter := bson.M{"_id": concept.ID, "occurrence_count": concept.OccurrenceCount}
+	update := bson.M{"$set": bson.M{
+		"demand_score":      concept.DemandScore,
+		"demand_updated_at": concept.DemandUpdatedAt,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to update staged concept demand: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
 func (r *mongoStagedConceptRepository) GetPending(ctx context.Context, limit, offset int) ([]*entities.StagedConcept, error) {
-	filter := bson.M{"status": entities.StagedConceptStatusPending}
-	opts := options.Find().
-		SetLimit(int64(limit)).
-		SetSkip(int64(offset)).
-		SetSort(bson.D{{"occurrence_count", -1}, {"identified_at", -1}})
+	// Concepts stored before demand was tracked rank by their raw count
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.M{"status": entities.StagedConceptStatusPending}}},
+		{{"$addFields", bson.M{"_demand": bson.M{"$ifNull": bson.A{"$demand_score", "$occurrence_count"}}}}},
+		{{"$sort", bson.D{{"_demand", -1}, {"identified_at", -1}}}},
+		{{"$skip", int64(offset)}},
+		{{"$limit", int64(limit)}},
+		{{"$project", bson.M{"_demand": 0}}},
+	}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending staged concepts: %w", err)
 	}
@@ -216,6 +239,33 @@ func (r *mongoStagedConceptRepository) GetStats(ctx context.Context) (*repositor
 	return stats, nil
 }
 
+func (r *mongoStagedConceptRepository) GetStaleStats(ctx context.Context, cutoff time.Time) (*repositories.StaleStagedConceptStats, error) {
+	filter := bson.M{
+		"status":        entities.StagedConceptStatusPending,
+		"identified_at": bson.M{"$lt": cutoff},
+	}
+
+	staleCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count stale staged concepts: %w", err)
+	}
+
+	stats := &repositories.StaleStagedConceptStats{StaleCount: staleCount}
+
+	// The oldest pending concept is reported even when it is not yet stale
+	var oldest entities.StagedConcept
+	opts := options.FindOne().SetSort(bson.M{"identified_at": 1})
+	err = r.collection.FindOne(ctx, bson.M{"status": entities.StagedConceptStatusPending}, opts).Decode(&oldest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to find oldest pending staged concept: %w", err)
+	}
+	if err == nil {
+		stats.OldestPending = &oldest
+	}
+
+	return stats, nil
+}
+
 func (r *mongoStagedConceptRepository) Delete(ctx context.Context, id string) error {
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
 	if err != nil {
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// TestGetStaleStats counts stale pending concepts against a real MongoDB.
+// Set MONGODB_TEST_URI to enable it.
+func TestGetStaleStats(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(ctx)
+
+	dbName := "mathprereq_test_stale_staged_concepts"
+	defer client.Database(dbName).Drop(ctx)
+
+	day := 24 * time.Hour
+	now := time.Now()
+	repo := NewMongoStagedConceptRepository(client, dbName, zap.NewNop())
+	for _, c := range []struct {
+		name   string
+		age    time.Duration
+		status entities.StagedConceptStatus
+	}{
+		{"fresh", 1 * day, entities.StagedConceptStatusPending},
+		{"recent", 10 * day, entities.StagedConceptStatusPending},
+		{"stale", 20 * day, entities.StagedConceptStatusPending},
+		{"oldest", 30 * day, entities.StagedConceptStatusPending},
+		{"reviewed", 60 * day, entities.StagedConceptStatusApproved},
+	} {
+		concept := entities.NewStagedConcept(c.name, "", "q", "", "", nil, 1, "", "")
+		concept.IdentifiedAt = now.Add(-c.age)
+		concept.Status = c.status
+		if err := repo.Save(ctx, concept); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := repo.GetStaleStats(ctx, now.Add(-14*day))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.StaleCount != 2 {
+		t.Errorf("expected 2 stale concepts, got %d", stats.StaleCount)
+	}
+	if stats.OldestPending == nil || stats.OldestPending.ConceptName != "oldest" {
+		t.Errorf("expected the oldest pending concept, got %+v", stats.OldestPending)
+	}
+
+	stats, err = repo.GetStaleStats(ctx, now.Add(-45*day))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.StaleCount != 0 || stats.OldestPending == nil {
+		t.Errorf("expected no stale concepts but an oldest pending one at 45 days, got %+v", stats)
+	}
+}
+
+func TestGetStaleStatsQueries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("counts before the cutoff and finds the oldest pending", func(mt *mtest.T) {
+		identifiedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.staged_concepts", mtest.FirstBatch, bson.D{{Key: "n", Value: int32(3)}}),
+			mtest.CreateCursorResponse(0, "test.staged_concepts", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: "s1"},
+				{Key: "concept_name", Value: "oldest"},
+				{Key: "status", Value: "pending"},
+				{Key: "identified_at", Value: identifiedAt},
+			}),
+		)
+
+		repo := &mongoStagedConceptRepository{collection: mt.Coll, logger: zap.NewNop()}
+		cutoff := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		stats, err := repo.GetStaleStats(context.Background(), cutoff)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if stats.StaleCount != 3 {
+			mt.Errorf("StaleCount = %d, want 3", stats.StaleCount)
+		}
+		if stats.OldestPending == nil || stats.OldestPending.ConceptName != "oldest" || !stats.OldestPending.IdentifiedAt.Equal(identifiedAt) {
+			mt.Errorf("OldestPending = %+v", stats.OldestPending)
+		}
+
+		count := mt.GetStartedEvent().Command
+		match := count.Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match").Document()
+		if status := match.Lookup("status").StringValue(); status != "pending" {
+			mt.Errorf("counted status %q, want pending", status)
+		}
+		if lt := match.Lookup("identified_at", "$lt").Time(); !lt.Equal(cutoff) {
+			mt.Errorf("counted before %v, want the cutoff %v", lt, cutoff)
+		}
+
+		find := mt.GetStartedEvent().Command
+		if status := find.Lookup("filter", "status").StringValue(); status != "pending" {
+			mt.Errorf("searched status %q, want pending", status)
+		}
+		if _, err := find.LookupErr("filter", "identified_at"); err == nil {
+			mt.Error("the oldest pending concept should not be limited to stale ones")
+		}
+		if order := find.Lookup("sort", "identified_at").Int32(); order != 1 {
+			mt.Errorf("sorted identified_at %d, want oldest first", order)
+		}
+	})
+
+	mt.Run("no pending concepts", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.staged_concepts", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, "test.staged_concepts", mtest.FirstBatch),
+		)
+
+		repo := &mongoStagedConceptRepository{collection: mt.Coll, logger: zap.NewNop()}
+		stats, err := repo.GetStaleStats(context.Background(), time.Now())
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if stats.StaleCount != 0 || stats.OldestPending != nil {
+			mt.Errorf("expected empty stats, got %+v", stats)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoUserProfileRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoUserProfileRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.UserProfileRepository {
+	return &mongoUserProfileRepository{
+		collection: client.Database(dbName).Collection("user_profiles"),
+		logger:     logger,
+	}
+}
+
+func (r *mongoUserProfileRepository) Save(ctx context.Context, profile *entities.UserProfile) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": profile.UserID}, profile, opts); err != nil {
+		return fmt.Errorf("failed to save user profile: %w", err)
+	}
+
+	r.logger.Info("User profile saved",
+		zap.String("user_id", profile.UserID),
+		zap.String("explanation_level", profile.Preferences.ExplanationLevel),
+		zap.String("explanation_style", profile.Preferences.ExplanationStyle))
+
+	return nil
+}
+
+func (r *mongoUserProfileRepository) FindByUserID(ctx context.Context, userID string) (*entities.UserProfile, error) {
+	var profile entities.UserProfile
+	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&profile)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find user profile: %w", err)
+	}
+	return &profile, nil
+}
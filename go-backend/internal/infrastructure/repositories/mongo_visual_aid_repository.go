@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type mongoVisualAidRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+func NewMongoVisualAidRepository(client *mongo.Client, dbName string, logger *zap.Logger) repositories.VisualAidRepository {
+	collection := client.Database(dbName).Collection("query_visuals")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "query_id", Value: 1}, {Key: "index", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		logger.Warn("Failed to create indexes for query_visuals", zap.Error(err))
+	}
+
+	return &mongoVisualAidRepository{
+		collection: collection,
+		logger:     logger,
+	}
+}
+
+func (r *mongoVisualAidRepository) Save(ctx context.Context, visual *entities.VisualAid) error {
+	if _, err := r.collection.InsertOne(ctx, visual); err != nil {
+		return fmt.Errorf("failed to save visual aid: %w", err)
+	}
+
+	r.logger.Info("Visual aid saved",
+		zap.String("query_id", visual.QueryID),
+		zap.Int("index", visual.Index),
+		zap.Int("size_bytes", len(visual.ImagePNG)))
+
+	return nil
+}
+
+func (r *mongoVisualAidRepository) FindByQueryAndIndex(ctx context.Context, queryID string, index int) (*entities.VisualAid, error) {
+	var visual entities.VisualAid
+	err := r.collection.FindOne(ctx, bson.M{"query_id": queryID, "index": index}).Decode(&visual)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find visual aid: %w", err)
+	}
+
+	visual.URL = entities.VisualAidURL(visual.QueryID, visual.Index)
+	return &visual, nil
+}
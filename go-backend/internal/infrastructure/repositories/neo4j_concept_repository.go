@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -25,6 +26,9 @@ func NewNeo4jConceptRepository(client *neo4j.Client, logger *zap.Logger) reposit
 
 func (r *neo4jConceptRepository) FindByID(ctx context.Context, id string) (*types.Concept, error) {
 	conceptDetail, err := r.client.GetConceptInfo(ctx, id)
+	if errors.Is(err, neo4j.ErrConceptNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find concept by ID: %w", err)
 	}
@@ -55,6 +59,28 @@ func (r *neo4jConceptRepository) GetAll(ctx context.Context) ([]types.Concept, e
 	return result, nil
 }
 
+func (r *neo4jConceptRepository) List(ctx context.Context, filter repositories.ConceptFilter) ([]types.Concept, int, error) {
+	concepts, total, err := r.client.ListConcepts(ctx, neo4j.ConceptListFilter{
+		Category:           filter.Category,
+		MinDifficulty:      filter.MinDifficulty,
+		MaxDifficulty:      filter.MaxDifficulty,
+		Search:             filter.Search,
+		HasResources:       filter.HasResources,
+		ResourceConceptIDs: filter.ResourceConceptIDs,
+		Limit:              filter.Limit,
+		Offset:             filter.Offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]types.Concept, len(concepts))
+	for i, concept := range concepts {
+		result[i] = *r.convertToEntity(&concept)
+	}
+	return result, total, nil
+}
+
 func (r *neo4jConceptRepository) FindPrerequisitePath(ctx context.Context, targetConcepts []string) ([]types.Concept, error) {
 	concepts, err := r.client.FindPrerequisitePath(ctx, targetConcepts)
 	if err != nil {
@@ -68,6 +94,36 @@ func (r *neo4jConceptRepository) FindPrerequisitePath(ctx context.Context, targe
 	return result, nil
 }
 
+func (r *neo4jConceptRepository) FindPrerequisitePaths(ctx context.Context, conceptIDs []string) (map[string][]types.Concept, error) {
+	paths, err := r.client.FindPrerequisitePaths(ctx, conceptIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]types.Concept, len(paths))
+	for id, path := range paths {
+		concepts := make([]types.Concept, len(path))
+		for i, concept := range path {
+			concepts[i] = *r.convertToEntity(&concept)
+		}
+		result[id] = concepts
+	}
+	return result, nil
+}
+
+func (r *neo4jConceptRepository) FindDependentConcepts(ctx context.Context, conceptID string, limit int) ([]types.Concept, error) {
+	dependents, err := r.client.FindDependentConcepts(ctx, conceptID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	concepts := make([]types.Concept, len(dependents))
+	for i, concept := range dependents {
+		concepts[i] = *r.convertToEntity(&concept)
+	}
+	return concepts, nil
+}
+
 func (r *neo4jConceptRepository) GetConceptDetail(ctx context.Context, conceptID string) (*types.ConceptDetailResult, error) {
 	detail, err := r.client.GetConceptInfo(ctx, conceptID)
 	if err != nil {
@@ -92,6 +148,61 @@ func (r *neo4jConceptRepository) GetConceptDetail(ctx context.Context, conceptID
 	}, nil
 }
 
+func (r *neo4jConceptRepository) GetNeighborhood(ctx context.Context, conceptID string, depth, maxNodes int) (*types.ConceptNeighborhood, error) {
+	neighborhood, err := r.client.GetConceptNeighborhood(ctx, conceptID, depth, maxNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]types.NeighborhoodNode, len(neighborhood.Nodes))
+	for i, node := range neighborhood.Nodes {
+		nodes[i] = types.NeighborhoodNode{
+			Concept:   *r.convertToEntity(&node.Concept),
+			Direction: node.Direction,
+			Distance:  node.Distance,
+		}
+	}
+
+	edges := make([]types.NeighborhoodEdge, len(neighborhood.Edges))
+	for i, edge := range neighborhood.Edges {
+		edges[i] = types.NeighborhoodEdge(edge)
+	}
+
+	return &types.ConceptNeighborhood{
+		CenterID:  neighborhood.CenterID,
+		Depth:     neighborhood.Depth,
+		Nodes:     nodes,
+		Edges:     edges,
+		Truncated: neighborhood.Truncated,
+	}, nil
+}
+
+func (r *neo4jConceptRepository) GetEdgesAmong(ctx context.Context, conceptIDs []string) ([]types.NeighborhoodEdge, error) {
+	edges, err := r.client.GetEdgesAmong(ctx, conceptIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.NeighborhoodEdge, len(edges))
+	for i, edge := range edges {
+		result[i] = types.NeighborhoodEdge(edge)
+	}
+	return result, nil
+}
+
+func (r *neo4jConceptRepository) GetPrerequisiteEdges(ctx context.Context) ([]types.NeighborhoodEdge, error) {
+	edges, err := r.client.GetPrerequisiteEdges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.NeighborhoodEdge, len(edges))
+	for i, edge := range edges {
+		result[i] = types.NeighborhoodEdge(edge)
+	}
+	return result, nil
+}
+
 func (r *neo4jConceptRepository) GetStats(ctx context.Context) (*types.SystemStats, error) {
 	stats, err := r.client.GetStats(ctx)
 	if err != nil {
@@ -113,6 +224,30 @@ func (r *neo4jConceptRepository) IsHealthy(ctx context.Context) bool {
 	return r.client.IsHealthy(ctx)
 }
 
+func (r *neo4jConceptRepository) MergeConcepts(ctx context.Context, sourceID, targetID string) (*types.ConceptMergeResult, error) {
+	merge, err := r.client.MergeConcepts(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.ConceptMergeResult{
+		SourceID:     merge.SourceID,
+		TargetID:     merge.TargetID,
+		EdgesMoved:   make([]types.NeighborhoodEdge, len(merge.Moved)),
+		EdgesSkipped: make([]types.SkippedEdge, len(merge.Skipped)),
+	}
+	for i, edge := range merge.Moved {
+		result.EdgesMoved[i] = types.NeighborhoodEdge(edge)
+	}
+	for i, skipped := range merge.Skipped {
+		result.EdgesSkipped[i] = types.SkippedEdge{
+			NeighborhoodEdge: types.NeighborhoodEdge(skipped.Edge),
+			Reason:           skipped.Reason,
+		}
+	}
+	return result, nil
+}
+
 // CreateConcept creates a new concept in the knowledge graph
 func (r *neo4jConceptRepository) CreateConcept(ctx context.Context, concept *types.Concept) error {
 	query := `
@@ -156,18 +291,21 @@ func (r *neo4jConceptRepository) CreateConcept(ctx context.Context, concept *typ
 	return nil
 }
 
-// CreatePrerequisiteRelationship creates a REQUIRES relationship between two concepts
-func (r *neo4jConceptRepository) CreatePrerequisiteRelationship(ctx context.Context, conceptID, prerequisiteID string) error {
+// CreatePrerequisiteRelationship creates a weighted REQUIRES relationship
+// between two concepts
+func (r *neo4jConceptRepository) CreatePrerequisiteRelationship(ctx context.Context, conceptID, prerequisiteID string, weight float64) error {
 	query := `
 		MATCH (c:Concept {id: $conceptID})
 		MATCH (p:Concept {id: $prerequisiteID})
 		MERGE (c)-[r:REQUIRES]->(p)
+		SET r.weight = $weight
 		RETURN c, r, p
 	`
 
 	params := map[string]interface{}{
 		"conceptID":      conceptID,
 		"prerequisiteID": prerequisiteID,
+		"weight":         weight,
 	}
 
 	_, err := r.client.ExecuteQuery(ctx, query, params)
@@ -181,11 +319,42 @@ func (r *neo4jConceptRepository) CreatePrerequisiteRelationship(ctx context.Cont
 
 	r.logger.Info("Created prerequisite relationship",
 		zap.String("concept_id", conceptID),
-		zap.String("prerequisite_id", prerequisiteID))
+		zap.String("prerequisite_id", prerequisiteID),
+		zap.Float64("weight", weight))
 
 	return nil
 }
 
+// SetPrerequisiteWeight sets the weight of the prerequisite relationship
+// between two concepts, stored either as PREREQUISITE_FOR from the
+// prerequisite or REQUIRES from the concept
+func (r *neo4jConceptRepository) SetPrerequisiteWeight(ctx context.Context, conceptID, prerequisiteID string, weight float64) (bool, error) {
+	query := `
+		MATCH (a:Concept)-[r:PREREQUISITE_FOR|REQUIRES]->(b:Concept)
+		WHERE (type(r) = 'PREREQUISITE_FOR' AND a.id = $prerequisiteID AND b.id = $conceptID)
+		   OR (type(r) = 'REQUIRES' AND a.id = $conceptID AND b.id = $prerequisiteID)
+		SET r.weight = $weight
+		RETURN count(r) as updated
+	`
+
+	params := map[string]interface{}{
+		"conceptID":      conceptID,
+		"prerequisiteID": prerequisiteID,
+		"weight":         weight,
+	}
+
+	result, err := r.client.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		r.logger.Error("Failed to set prerequisite weight",
+			zap.String("concept_id", conceptID),
+			zap.String("prerequisite_id", prerequisiteID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to set prerequisite weight: %w", err)
+	}
+
+	return len(result) > 0 && extractInt64(result[0], "updated") > 0, nil
+}
+
 // ExistsByName checks if a concept exists by name (case-insensitive)
 func (r *neo4jConceptRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
 	query := `
@@ -215,13 +384,42 @@ func (r *neo4jConceptRepository) ExistsByName(ctx context.Context, name string)
 	return false, nil
 }
 
+// UpdateDescription fills in a blank concept description. A description set
+// in the meantime is left alone.
+func (r *neo4jConceptRepository) UpdateDescription(ctx context.Context, conceptID, description string) (bool, error) {
+	query := `
+		MATCH (c:Concept {id: $conceptID})
+		WHERE c.description IS NULL OR trim(c.description) = ''
+		SET c.description = $description, c.updated_at = datetime()
+		RETURN c.id as id
+	`
+
+	params := map[string]interface{}{
+		"conceptID":   conceptID,
+		"description": description,
+	}
+
+	result, err := r.client.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		r.logger.Error("Failed to update concept description",
+			zap.String("concept_id", conceptID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to update concept description: %w", err)
+	}
+
+	return len(result) > 0, nil
+}
+
 // Helper function to convert neo4j.Concept to types.Concept
 func (r *neo4jConceptRepository) convertToEntity(neo4jConcept *neo4j.Concept) *types.Concept {
 	return &types.Concept{
 		ID:          neo4jConcept.ID,
 		Name:        neo4jConcept.Name,
 		Description: neo4jConcept.Description,
+		Category:    neo4jConcept.Category,
+		Difficulty:  neo4jConcept.Difficulty,
 		Type:        neo4jConcept.Type,
+		Weight:      neo4jConcept.Weight,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -28,16 +28,60 @@ func (r *weaviateVectorRepository) Search(ctx context.Context, query string, lim
 		return nil, fmt.Errorf("vector search failed: %w", err)
 	}
 
+	return toVectorResults(results), nil
+}
+
+func (r *weaviateVectorRepository) SearchByConcepts(ctx context.Context, concepts []string, limit int) ([]types.VectorResult, error) {
+	results, err := r.client.SemanticSearchByConcepts(ctx, concepts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("vector search by concepts failed: %w", err)
+	}
+
+	return toVectorResults(results), nil
+}
+
+func (r *weaviateVectorRepository) SearchConceptVectors(ctx context.Context, terms []string, limit int) ([]types.VectorResult, error) {
+	results, err := r.client.SearchConceptVectors(ctx, terms, limit)
+	if err != nil {
+		return nil, fmt.Errorf("concept vector search failed: %w", err)
+	}
+
+	return toVectorResults(results), nil
+}
+
+func (r *weaviateVectorRepository) DeleteConceptVector(ctx context.Context, conceptID string) error {
+	return r.client.DeleteConceptVector(ctx, conceptID)
+}
+
+func (r *weaviateVectorRepository) CountChunksByConcept(ctx context.Context) (map[string]int, error) {
+	return r.client.CountChunksByConcept(ctx)
+}
+
+func (r *weaviateVectorRepository) CountChunksNear(ctx context.Context, term string, certainty float64, maxChunks int) (int, error) {
+	return r.client.CountChunksNear(ctx, term, float32(certainty), maxChunks)
+}
+
+func toVectorResults(results []weaviate.SearchResult) []types.VectorResult {
 	vectorResults := make([]types.VectorResult, len(results))
 	for i, result := range results {
+		metadata := make(map[string]interface{}, len(result.Metadata)+2)
+		for k, v := range result.Metadata {
+			metadata[k] = v
+		}
+		metadata["concept"] = result.Concept
+		metadata["chapter"] = result.Chapter
+		if result.Source != "" {
+			metadata["source"] = result.Source
+		}
+
 		vectorResults[i] = types.VectorResult{
 			Content:  result.Content,
 			Score:    float64(result.Score),
-			Metadata: result.Metadata,
+			Metadata: metadata,
 		}
 	}
 
-	return vectorResults, nil
+	return vectorResults
 }
 
 func (r *weaviateVectorRepository) IsHealthy(ctx context.Context) bool {
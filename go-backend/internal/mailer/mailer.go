@@ -36,35 +36,17 @@ func (m *Mailer) Send(recipient, templateFile string, data any) error {
 		return fmt.Errorf("recipient email is required")
 	}
 
-	tmpl, err := template.ParseFiles(templateFile)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
-	}
-
-	subject := new(bytes.Buffer)
-	err = tmpl.ExecuteTemplate(subject, "subject", data)
-	if err != nil {
-		return fmt.Errorf("failed to execute subject template: %w", err)
-	}
-
-	plainBody := new(bytes.Buffer)
-	err = tmpl.ExecuteTemplate(plainBody, "plainBody", data)
-	if err != nil {
-		return fmt.Errorf("failed to execute plainBody template: %w", err)
-	}
-
-	htmlBody := new(bytes.Buffer)
-	err = tmpl.ExecuteTemplate(htmlBody, "htmlBody", data)
+	subject, plainBody, htmlBody, err := Render(templateFile, data)
 	if err != nil {
-		return fmt.Errorf("failed to execute htmlBody template: %w", err)
+		return err
 	}
 
 	msg := mail.NewMessage()
 	msg.SetHeader("To", recipient)
 	msg.SetHeader("From", m.sender)
-	msg.SetHeader("Subject", subject.String())
-	msg.SetBody("text/plain", plainBody.String())
-	msg.AddAlternative("text/html", htmlBody.String())
+	msg.SetHeader("Subject", subject)
+	msg.SetBody("text/plain", plainBody)
+	msg.AddAlternative("text/html", htmlBody)
 
 	// Retry logic with exponential backoff
 	for i := 1; i <= 3; i++ {
@@ -80,6 +62,25 @@ func (m *Mailer) Send(recipient, templateFile string, data any) error {
 	return fmt.Errorf("failed to send email after 3 attempts: %w", err)
 }
 
+// Render executes the subject, plainBody and htmlBody templates of
+// templateFile with data
+func Render(templateFile string, data any) (subject, plainBody, htmlBody string, err error) {
+	tmpl, err := template.ParseFiles(templateFile)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	parts := make([]string, 3)
+	for i, name := range []string{"subject", "plainBody", "htmlBody"} {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, name, data); err != nil {
+			return "", "", "", fmt.Errorf("failed to execute %s template: %w", name, err)
+		}
+		parts[i] = buf.String()
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
 // IsEnabled returns whether the mailer is enabled
 func (m *Mailer) IsEnabled() bool {
 	return m.enabled
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderStagedConceptsDigest(t *testing.T) {
+	data := map[string]interface{}{
+		"Frequency":    "daily",
+		"Period":       "24 hours",
+		"GeneratedAt":  "2026-03-02 08:00 UTC",
+		"PendingCount": 12,
+		"NewCount":     3,
+		"TopConcepts": []map[string]interface{}{
+			{"Name": "Jacobian", "Category": "calculus", "Occurrences": 7, "FirstSeen": "2026-02-20"},
+			{"Name": "Eigenvalue", "Category": "linear algebra", "Occurrences": 5, "FirstSeen": "2026-02-27"},
+		},
+		"Oldest": map[string]interface{}{"Name": "Fourier Series", "FirstSeen": "2026-01-15", "AgeDays": 46},
+	}
+
+	subject, plain, html, err := Render("templates/staged_concepts_digest.tmpl", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if subject != "MathPrereq daily digest: 12 staged concepts awaiting review" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	for _, want := range []string{
+		"Pending review: 12",
+		"New in the last 24 hours: 3",
+		"- Jacobian (7 occurrences, calculus), first seen 2026-02-20",
+		"- Eigenvalue (5 occurrences, linear algebra)",
+		"Oldest pending: Fourier Series, waiting 46 days since 2026-01-15",
+	} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected plain body to contain %q, got:\n%s", want, plain)
+		}
+	}
+	for _, want := range []string{"<td>Jacobian</td><td>7</td>", "<strong>Fourier Series</strong>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected HTML body to contain %q", want)
+		}
+	}
+}
+
+func TestRenderNewConceptIdentified(t *testing.T) {
+	subject, _, _, err := Render("templates/new_concept_identified.tmpl", map[string]interface{}{"ConceptName": "Jacobian"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "New Mathematical Concept Detected: Jacobian" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"github.com/mathprereq/internal/background"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// JobStatsFunc returns the current statistics of scheduled jobs
+type JobStatsFunc func() []background.JobStats
+
+// SchedulerCollector exposes per-job run counts and durations of the
+// maintenance scheduler
+type SchedulerCollector struct {
+	stats JobStatsFunc
+
+	runsDesc     *prometheus.Desc
+	failuresDesc *prometheus.Desc
+	skippedDesc  *prometheus.Desc
+	durationDesc *prometheus.Desc
+	runningDesc  *prometheus.Desc
+}
+
+func NewSchedulerCollector(stats JobStatsFunc) *SchedulerCollector {
+	labels := []string{"job"}
+	return &SchedulerCollector{
+		stats: stats,
+		runsDesc: prometheus.NewDesc(
+			"mathprereq_scheduled_job_runs_total",
+			"Completed runs of a scheduled job",
+			labels, nil,
+		),
+		failuresDesc: prometheus.NewDesc(
+			"mathprereq_scheduled_job_failures_total",
+			"Runs of a scheduled job that returned an error",
+			labels, nil,
+		),
+		skippedDesc: prometheus.NewDesc(
+			"mathprereq_scheduled_job_skipped_total",
+			"Ticks skipped because the previous run was still in progress",
+			labels, nil,
+		),
+		durationDesc: prometheus.NewDesc(
+			"mathprereq_scheduled_job_last_duration_seconds",
+			"Duration of the most recent completed run",
+			labels, nil,
+		),
+		runningDesc: prometheus.NewDesc(
+			"mathprereq_scheduled_job_running",
+			"Whether the job is currently running (1) or idle (0)",
+			labels, nil,
+		),
+	}
+}
+
+func (c *SchedulerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.runsDesc
+	ch <- c.failuresDesc
+	ch <- c.skippedDesc
+	ch <- c.durationDesc
+	ch <- c.runningDesc
+}
+
+func (c *SchedulerCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, job := range c.stats() {
+		running := 0.0
+		if job.Running {
+			running = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.runsDesc, prometheus.CounterValue, float64(job.Runs), job.Name)
+		ch <- prometheus.MustNewConstMetric(c.failuresDesc, prometheus.CounterValue, float64(job.Failures), job.Name)
+		ch <- prometheus.MustNewConstMetric(c.skippedDesc, prometheus.CounterValue, float64(job.Skipped), job.Name)
+		ch <- prometheus.MustNewConstMetric(c.durationDesc, prometheus.GaugeValue, job.LastDuration.Seconds(), job.Name)
+		ch <- prometheus.MustNewConstMetric(c.runningDesc, prometheus.GaugeValue, running, job.Name)
+	}
+}
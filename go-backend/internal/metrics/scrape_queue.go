@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// ScrapeQueueStatsFunc fetches the current depth of the persistent scrape queue
+type ScrapeQueueStatsFunc func(ctx context.Context) (*repositories.ScrapeQueueStats, error)
+
+// ScrapeQueueCollector exposes the persistent scrape queue's jobs by status.
+// Values are read from MongoDB on every scrape and cover all instances.
+type ScrapeQueueCollector struct {
+	fetch  ScrapeQueueStatsFunc
+	logger *zap.Logger
+
+	jobsDesc   *prometheus.Desc
+	oldestDesc *prometheus.Desc
+}
+
+func NewScrapeQueueCollector(fetch ScrapeQueueStatsFunc, logger *zap.Logger) *ScrapeQueueCollector {
+	return &ScrapeQueueCollector{
+		fetch:  fetch,
+		logger: logger,
+		jobsDesc: prometheus.NewDesc(
+			"mathprereq_scrape_queue_jobs",
+			"Scrape jobs in the persistent queue by status",
+			[]string{"status"}, nil,
+		),
+		oldestDesc: prometheus.NewDesc(
+			"mathprereq_scrape_queue_oldest_pending_age_seconds",
+			"How long the oldest available pending scrape job has waited in seconds",
+			nil, nil,
+		),
+	}
+}
+
+func (c *ScrapeQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.jobsDesc
+	ch <- c.oldestDesc
+}
+
+func (c *ScrapeQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := c.fetch(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to collect scrape queue metrics", zap.Error(err))
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.jobsDesc, prometheus.GaugeValue, float64(stats.Pending), "pending")
+	ch <- prometheus.MustNewConstMetric(c.jobsDesc, prometheus.GaugeValue, float64(stats.Running), "running")
+	ch <- prometheus.MustNewConstMetric(c.jobsDesc, prometheus.GaugeValue, float64(stats.Done), "done")
+	ch <- prometheus.MustNewConstMetric(c.jobsDesc, prometheus.GaugeValue, float64(stats.Failed), "failed")
+	ch <- prometheus.MustNewConstMetric(c.oldestDesc, prometheus.GaugeValue, stats.OldestPendingAgeSeconds)
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// StaleStatsFunc fetches the current stale staged concept statistics
+type StaleStatsFunc func(ctx context.Context, maxAge time.Duration) (*repositories.StaleStagedConceptStats, error)
+
+// StagedConceptCollector exposes the review queue health as Prometheus gauges.
+// Values are read from MongoDB on every scrape.
+type StagedConceptCollector struct {
+	fetch  StaleStatsFunc
+	maxAge time.Duration
+	logger *zap.Logger
+
+	staleDesc  *prometheus.Desc
+	oldestDesc *prometheus.Desc
+}
+
+func NewStagedConceptCollector(fetch StaleStatsFunc, maxAge time.Duration, logger *zap.Logger) *StagedConceptCollector {
+	return &StagedConceptCollector{
+		fetch:  fetch,
+		maxAge: maxAge,
+		logger: logger,
+		staleDesc: prometheus.NewDesc(
+			"mathprereq_staged_concepts_stale",
+			"Number of pending staged concepts older than the configured stale age",
+			nil, nil,
+		),
+		oldestDesc: prometheus.NewDesc(
+			"mathprereq_staged_concepts_oldest_pending_age_seconds",
+			"Age of the oldest pending staged concept in seconds",
+			nil, nil,
+		),
+	}
+}
+
+func (c *StagedConceptCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.staleDesc
+	ch <- c.oldestDesc
+}
+
+func (c *StagedConceptCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := c.fetch(ctx, c.maxAge)
+	if err != nil {
+		c.logger.Warn("Failed to collect staged concept metrics", zap.Error(err))
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.staleDesc, prometheus.GaugeValue, float64(stats.StaleCount))
+	ch <- prometheus.MustNewConstMetric(c.oldestDesc, prometheus.GaugeValue, stats.OldestAgeSeconds)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/repositories"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// gauges gathers a collector's gauges by metric name
+func gauges(t *testing.T, collector prometheus.Collector) map[string]float64 {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] = metric.GetGauge().GetValue()
+		}
+	}
+	return values
+}
+
+func TestStagedConceptCollector(t *testing.T) {
+	var maxAge time.Duration
+	collector := NewStagedConceptCollector(func(ctx context.Context, age time.Duration) (*repositories.StaleStagedConceptStats, error) {
+		maxAge = age
+		return &repositories.StaleStagedConceptStats{StaleCount: 4, OldestAgeSeconds: 86400}, nil
+	}, 14*24*time.Hour, zap.NewNop())
+
+	values := gauges(t, collector)
+	if v := values["mathprereq_staged_concepts_stale"]; v != 4 {
+		t.Errorf("stale gauge = %v, want 4", v)
+	}
+	if v := values["mathprereq_staged_concepts_oldest_pending_age_seconds"]; v != 86400 {
+		t.Errorf("oldest pending age gauge = %v, want 86400", v)
+	}
+	if maxAge != 14*24*time.Hour {
+		t.Errorf("fetched with max age %v, want the configured 336h", maxAge)
+	}
+}
+
+func TestStagedConceptCollectorSkipsFailedFetch(t *testing.T) {
+	collector := NewStagedConceptCollector(func(ctx context.Context, age time.Duration) (*repositories.StaleStagedConceptStats, error) {
+		return nil, errors.New("mongo unavailable")
+	}, time.Hour, zap.NewNop())
+
+	if values := gauges(t, collector); len(values) != 0 {
+		t.Errorf("collected %v after a failed fetch, want nothing", values)
+	}
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"github.com/mathprereq/internal/background"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolStatsFunc returns the current load of a worker pool
+type PoolStatsFunc func() background.PoolStats
+
+// WorkerPoolCollector exposes queue depth, active jobs and totals of a
+// bounded background worker pool
+type WorkerPoolCollector struct {
+	stats PoolStatsFunc
+
+	workersDesc   *prometheus.Desc
+	queuedDesc    *prometheus.Desc
+	activeDesc    *prometheus.Desc
+	completedDesc *prometheus.Desc
+	droppedDesc   *prometheus.Desc
+}
+
+func NewWorkerPoolCollector(stats PoolStatsFunc) *WorkerPoolCollector {
+	labels := []string{"pool"}
+	return &WorkerPoolCollector{
+		stats: stats,
+		workersDesc: prometheus.NewDesc(
+			"mathprereq_worker_pool_workers",
+			"Configured number of workers in the pool",
+			labels, nil,
+		),
+		queuedDesc: prometheus.NewDesc(
+			"mathprereq_worker_pool_queue_depth",
+			"Jobs waiting for a free worker",
+			labels, nil,
+		),
+		activeDesc: prometheus.NewDesc(
+			"mathprereq_worker_pool_active_jobs",
+			"Jobs currently running",
+			labels, nil,
+		),
+		completedDesc: prometheus.NewDesc(
+			"mathprereq_worker_pool_completed_total",
+			"Jobs that finished running",
+			labels, nil,
+		),
+		droppedDesc: prometheus.NewDesc(
+			"mathprereq_worker_pool_dropped_total",
+			"Jobs rejected because the queue was full or the pool was stopping",
+			labels, nil,
+		),
+	}
+}
+
+func (c *WorkerPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.workersDesc
+	ch <- c.queuedDesc
+	ch <- c.activeDesc
+	ch <- c.completedDesc
+	ch <- c.droppedDesc
+}
+
+func (c *WorkerPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.workersDesc, prometheus.GaugeValue, float64(stats.Workers), stats.Name)
+	ch <- prometheus.MustNewConstMetric(c.queuedDesc, prometheus.GaugeValue, float64(stats.Queued), stats.Name)
+	ch <- prometheus.MustNewConstMetric(c.activeDesc, prometheus.GaugeValue, float64(stats.Active), stats.Name)
+	ch <- prometheus.MustNewConstMetric(c.completedDesc, prometheus.CounterValue, float64(stats.Completed), stats.Name)
+	ch <- prometheus.MustNewConstMetric(c.droppedDesc, prometheus.CounterValue, float64(stats.Dropped), stats.Name)
+}
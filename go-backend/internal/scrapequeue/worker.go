@@ -0,0 +1,274 @@
+// Package scrapequeue runs resource scrapes from the persistent job queue.
+package scrapequeue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+// maxRetryBackoff caps the doubling delay between attempts
+const maxRetryBackoff = time.Hour
+
+// releaseTimeout bounds recording a job's outcome, which runs even when the
+// job's own context was cancelled
+const releaseTimeout = 10 * time.Second
+
+// ScrapeFunc scrapes resources for concepts
+type ScrapeFunc func(ctx context.Context, concepts []string) error
+
+type Config struct {
+	Workers      int
+	Lease        time.Duration
+	PollInterval time.Duration
+	RetryBackoff time.Duration
+}
+
+// Worker claims jobs from the queue and scrapes them. A job is completed
+// when the scrape succeeds, retried after a backoff when it fails with
+// attempts left and failed otherwise. A worker that dies mid-job leaves
+// its lease to run out, after which any instance picks the job up again.
+type Worker struct {
+	repo   repositories.ScrapeJobRepository
+	scrape ScrapeFunc
+	cfg    Config
+	host   string
+	logger *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// jobCtx is cancelled only when Stop gives up waiting for running jobs
+	jobCtx    context.Context
+	jobCancel context.CancelFunc
+	wg        sync.WaitGroup
+
+	mu      sync.Mutex
+	started bool
+	active  map[int]string
+}
+
+func NewWorker(repo repositories.ScrapeJobRepository, scrape ScrapeFunc, cfg Config, logger *zap.Logger) *Worker {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobCtx, jobCancel := context.WithCancel(context.Background())
+	return &Worker{
+		repo:      repo,
+		scrape:    scrape,
+		cfg:       cfg,
+		host:      fmt.Sprintf("%s-%d", host, os.Getpid()),
+		logger:    logger.With(zap.String("component", "scrape_queue")),
+		ctx:       ctx,
+		cancel:    cancel,
+		jobCtx:    jobCtx,
+		jobCancel: jobCancel,
+		active:    make(map[int]string),
+	}
+}
+
+// Start launches the workers; it does nothing on a nil or started Worker
+func (w *Worker) Start() {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started {
+		return
+	}
+	w.started = true
+
+	w.wg.Add(w.cfg.Workers)
+	for i := 0; i < w.cfg.Workers; i++ {
+		go w.run(i)
+	}
+	w.logger.Info("Scrape queue workers started", zap.Int("workers", w.cfg.Workers))
+}
+
+func (w *Worker) run(worker int) {
+	defer w.wg.Done()
+
+	id := fmt.Sprintf("%s/%d", w.host, worker)
+	for {
+		if w.ctx.Err() != nil {
+			return
+		}
+		if !w.processNext(worker, id) {
+			if worker == 0 {
+				w.failExpired()
+			}
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(w.cfg.PollInterval):
+			}
+		}
+	}
+}
+
+// processNext claims and runs one job, reporting whether there was one
+func (w *Worker) processNext(worker int, workerID string) bool {
+	claimCtx, cancel := context.WithTimeout(w.ctx, releaseTimeout)
+	job, err := w.repo.Claim(claimCtx, workerID, w.cfg.Lease)
+	cancel()
+	if err != nil {
+		if w.ctx.Err() == nil {
+			w.logger.Warn("Failed to claim scrape job", zap.Error(err))
+		}
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	w.mu.Lock()
+	w.active[worker] = job.ID
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		delete(w.active, worker)
+		w.mu.Unlock()
+	}()
+
+	w.runJob(job, workerID)
+	return true
+}
+
+// runJob scrapes a claimed job within its lease and records the outcome
+func (w *Worker) runJob(job *entities.ScrapeJob, workerID string) {
+	logger := w.logger.With(
+		zap.String("job_id", job.ID),
+		zap.String("source", job.Source),
+		zap.Strings("concepts", job.Concepts),
+		zap.Int("attempt", job.Attempts))
+	logger.Info("Running scrape job")
+
+	ctx, cancel := context.WithTimeout(w.jobCtx, w.cfg.Lease)
+	err := w.safeScrape(ctx, job.Concepts)
+	cancel()
+
+	releaseCtx, cancelRelease := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancelRelease()
+
+	var releaseErr error
+	switch {
+	case err == nil:
+		releaseErr = w.repo.Complete(releaseCtx, job.ID, workerID)
+		logger.Info("Scrape job completed")
+	case job.Exhausted():
+		releaseErr = w.repo.Fail(releaseCtx, job.ID, workerID, err.Error())
+		logger.Error("Scrape job failed, no attempts left", zap.Error(err))
+	default:
+		delay := w.retryDelay(job.Attempts)
+		releaseErr = w.repo.Retry(releaseCtx, job.ID, workerID, time.Now().Add(delay), err.Error())
+		logger.Warn("Scrape job failed, will retry", zap.Error(err), zap.Duration("retry_in", delay))
+	}
+
+	if errors.Is(releaseErr, repositories.ErrScrapeJobLeaseLost) {
+		logger.Warn("Scrape job lease ran out before it finished; another worker may repeat it")
+	} else if releaseErr != nil {
+		logger.Error("Failed to record scrape job outcome", zap.Error(releaseErr))
+	}
+}
+
+// safeScrape turns a panicking scrape into a failed attempt
+func (w *Worker) safeScrape(ctx context.Context, concepts []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("scrape panicked: %v", r)
+		}
+	}()
+	return w.scrape(ctx, concepts)
+}
+
+// retryDelay doubles RetryBackoff for each attempt already made
+func (w *Worker) retryDelay(attempts int) time.Duration {
+	delay := w.cfg.RetryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
+
+func (w *Worker) failExpired() {
+	ctx, cancel := context.WithTimeout(w.ctx, releaseTimeout)
+	defer cancel()
+
+	failed, err := w.repo.FailExpired(ctx)
+	if err != nil {
+		if w.ctx.Err() == nil {
+			w.logger.Warn("Failed to fail expired scrape jobs", zap.Error(err))
+		}
+		return
+	}
+	if failed > 0 {
+		w.logger.Warn("Scrape jobs abandoned on their last attempt marked failed", zap.Int64("count", failed))
+	}
+}
+
+// Stats returns the queue depth across all instances
+func (w *Worker) Stats(ctx context.Context) (*repositories.ScrapeQueueStats, error) {
+	return w.repo.Stats(ctx)
+}
+
+// Pending returns the IDs of jobs this instance is running
+func (w *Worker) Pending() []string {
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ids := make([]string, 0, len(w.active))
+	for _, id := range w.active {
+		ids = append(ids, "scrape_job:"+id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Stop stops claiming jobs and waits for running ones to finish. If ctx
+// ends first, running jobs are cancelled and their IDs returned; they are
+// retried or, once their lease runs out, picked up again.
+func (w *Worker) Stop(ctx context.Context) []string {
+	if w == nil {
+		return nil
+	}
+
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		w.jobCancel()
+		return nil
+	case <-ctx.Done():
+		pending := w.Pending()
+		w.jobCancel()
+		return pending
+	}
+}
@@ -0,0 +1,260 @@
+package scrapequeue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/domain/entities"
+	"github.com/mathprereq/internal/domain/repositories"
+	"go.uber.org/zap"
+)
+
+// memoryQueue is a ScrapeJobRepository with the Mongo repository's claim
+// and lease rules, held under one lock
+type memoryQueue struct {
+	mu   sync.Mutex
+	jobs []*entities.ScrapeJob
+}
+
+func (q *memoryQueue) Enqueue(ctx context.Context, job *entities.ScrapeJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func (q *memoryQueue) Claim(ctx context.Context, workerID string, lease time.Duration) (*entities.ScrapeJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	for _, job := range q.jobs {
+		available := job.Status == entities.ScrapeJobPending && !job.AvailableAt.After(now)
+		expired := job.Status == entities.ScrapeJobRunning && job.LockedUntil.Before(now) && !job.Exhausted()
+		if !available && !expired {
+			continue
+		}
+		until := now.Add(lease)
+		job.Status = entities.ScrapeJobRunning
+		job.LockedBy = workerID
+		job.LockedUntil = &until
+		job.Attempts++
+		claimed := *job
+		return &claimed, nil
+	}
+	return nil, nil
+}
+
+func (q *memoryQueue) release(jobID, workerID string, update func(job *entities.ScrapeJob)) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range q.jobs {
+		if job.ID == jobID && job.Status == entities.ScrapeJobRunning && job.LockedBy == workerID {
+			job.LockedBy = ""
+			job.LockedUntil = nil
+			update(job)
+			return nil
+		}
+	}
+	return repositories.ErrScrapeJobLeaseLost
+}
+
+func (q *memoryQueue) Complete(ctx context.Context, jobID, workerID string) error {
+	return q.release(jobID, workerID, func(job *entities.ScrapeJob) {
+		job.Status = entities.ScrapeJobDone
+	})
+}
+
+func (q *memoryQueue) Retry(ctx context.Context, jobID, workerID string, availableAt time.Time, lastErr string) error {
+	return q.release(jobID, workerID, func(job *entities.ScrapeJob) {
+		job.Status = entities.ScrapeJobPending
+		job.AvailableAt = availableAt
+		job.LastError = lastErr
+	})
+}
+
+func (q *memoryQueue) Fail(ctx context.Context, jobID, workerID, lastErr string) error {
+	return q.release(jobID, workerID, func(job *entities.ScrapeJob) {
+		job.Status = entities.ScrapeJobFailed
+		job.LastError = lastErr
+	})
+}
+
+func (q *memoryQueue) FailExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (q *memoryQueue) Stats(ctx context.Context) (*repositories.ScrapeQueueStats, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := &repositories.ScrapeQueueStats{}
+	for _, job := range q.jobs {
+		switch job.Status {
+		case entities.ScrapeJobPending:
+			stats.Pending++
+		case entities.ScrapeJobRunning:
+			stats.Running++
+		case entities.ScrapeJobDone:
+			stats.Done++
+		case entities.ScrapeJobFailed:
+			stats.Failed++
+		}
+	}
+	return stats, nil
+}
+
+func (q *memoryQueue) job(id string) entities.ScrapeJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, job := range q.jobs {
+		if job.ID == id {
+			return *job
+		}
+	}
+	return entities.ScrapeJob{}
+}
+
+// waitForStats polls until the queue reaches want or a second passes
+func waitForStats(t *testing.T, q *memoryQueue, want repositories.ScrapeQueueStats) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		stats, _ := q.Stats(context.Background())
+		if *stats == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected queue %+v, got %+v", want, *stats)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func testConfig(workers int) Config {
+	return Config{Workers: workers, Lease: time.Second, PollInterval: 5 * time.Millisecond}
+}
+
+func TestWorkerRetriesFailedJob(t *testing.T) {
+	queue := &memoryQueue{}
+	job := entities.NewScrapeJob("scrape_resources", "q1", []string{"limits"}, 3)
+	queue.Enqueue(context.Background(), job)
+
+	var calls int32
+	scrape := func(ctx context.Context, concepts []string) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return errors.New("khan academy timed out")
+		}
+		return nil
+	}
+
+	worker := NewWorker(queue, scrape, testConfig(1), zap.NewNop())
+	worker.Start()
+	waitForStats(t, queue, repositories.ScrapeQueueStats{Done: 1})
+	worker.Stop(context.Background())
+
+	if got := queue.job(job.ID); got.Attempts != 2 {
+		t.Errorf("expected the job to succeed on its second attempt, got %d attempts", got.Attempts)
+	}
+}
+
+func TestWorkerFailsJobWithoutAttemptsLeft(t *testing.T) {
+	queue := &memoryQueue{}
+	job := entities.NewScrapeJob("gather_resources", "", []string{"limits"}, 2)
+	queue.Enqueue(context.Background(), job)
+
+	var calls int32
+	scrape := func(ctx context.Context, concepts []string) error {
+		atomic.AddInt32(&calls, 1)
+		panic("parser blew up")
+	}
+
+	worker := NewWorker(queue, scrape, testConfig(1), zap.NewNop())
+	worker.Start()
+	waitForStats(t, queue, repositories.ScrapeQueueStats{Failed: 1})
+	worker.Stop(context.Background())
+
+	got := queue.job(job.ID)
+	if calls != 2 || got.Attempts != 2 {
+		t.Errorf("expected two attempts, got %d calls and %d attempts", calls, got.Attempts)
+	}
+	if got.LastError != "scrape panicked: parser blew up" {
+		t.Errorf("unexpected last error %q", got.LastError)
+	}
+}
+
+func TestWorkersRunEachJobOnce(t *testing.T) {
+	queue := &memoryQueue{}
+	for i := 0; i < 20; i++ {
+		queue.Enqueue(context.Background(), entities.NewScrapeJob("scrape_resources", "", []string{"limits"}, 1))
+	}
+
+	var mu sync.Mutex
+	runs := 0
+	scrape := func(ctx context.Context, concepts []string) error {
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+
+	worker := NewWorker(queue, scrape, testConfig(4), zap.NewNop())
+	worker.Start()
+	waitForStats(t, queue, repositories.ScrapeQueueStats{Done: 20})
+	worker.Stop(context.Background())
+
+	if runs != 20 {
+		t.Errorf("expected 20 scrapes for 20 jobs, got %d", runs)
+	}
+}
+
+func TestWorkerStopReportsRunningJobs(t *testing.T) {
+	queue := &memoryQueue{}
+	job := entities.NewScrapeJob("scrape_resources", "", []string{"limits"}, 3)
+	queue.Enqueue(context.Background(), job)
+
+	started := make(chan struct{})
+	scrape := func(ctx context.Context, concepts []string) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	worker := NewWorker(queue, scrape, testConfig(1), zap.NewNop())
+	worker.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	pending := worker.Stop(ctx)
+	if len(pending) != 1 || pending[0] != "scrape_job:"+job.ID {
+		t.Errorf("expected the running job reported, got %v", pending)
+	}
+
+	// The interrupted job goes back to the queue for another instance
+	waitForStats(t, queue, repositories.ScrapeQueueStats{Pending: 1})
+}
+
+func TestRetryDelayDoubles(t *testing.T) {
+	w := &Worker{cfg: Config{RetryBackoff: time.Minute}}
+	tests := map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 10: maxRetryBackoff}
+	for attempts, want := range tests {
+		if got := w.retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestNilWorker(t *testing.T) {
+	var w *Worker
+	w.Start()
+	if pending := w.Stop(context.Background()); pending != nil {
+		t.Errorf("expected nothing pending on a nil worker, got %v", pending)
+	}
+}
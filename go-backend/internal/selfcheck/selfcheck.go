@@ -0,0 +1,179 @@
+// Package selfcheck verifies at startup that dependencies are usable, not
+// just reachable: the graph has concepts, the vector classes exist with the
+// expected vectorizer, and the LLM answers. Results feed a readiness gate
+// the /ready endpoint reports.
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mathprereq/internal/types"
+)
+
+// Check is one named startup verification
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report is the outcome of a full self-check run
+type Report struct {
+	Passed    bool      `json:"passed"`
+	CheckedAt time.Time `json:"checked_at"`
+	Results   []Result  `json:"results"`
+}
+
+// Failed returns the names of the checks that did not pass
+func (r *Report) Failed() []string {
+	var failed []string
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed = append(failed, result.Name)
+		}
+	}
+	return failed
+}
+
+// Run runs every check in order, each bounded by timeout, and reports them
+// all even when one fails
+func Run(ctx context.Context, checks []Check, timeout time.Duration) *Report {
+	report := &Report{Passed: true, CheckedAt: time.Now(), Results: make([]Result, 0, len(checks))}
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := check.Run(checkCtx)
+		cancel()
+
+		result := Result{Name: check.Name, Passed: err == nil, Duration: time.Since(start)}
+		if err != nil {
+			result.Error = err.Error()
+			report.Passed = false
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// Gate holds the latest report. When required, the app is only ready once
+// a report has passed; otherwise reports are informational.
+type Gate struct {
+	required bool
+
+	mu     sync.RWMutex
+	report *Report
+	passed bool
+}
+
+// NewGate creates a gate with no report yet
+func NewGate(required bool) *Gate {
+	return &Gate{required: required}
+}
+
+// Record stores a report. A passing report keeps the gate open even if a
+// later run fails, so a transient LLM hiccup cannot take a serving
+// instance out of rotation.
+func (g *Gate) Record(report *Report) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.report = report
+	if report.Passed {
+		g.passed = true
+	}
+}
+
+// Ready reports whether the app may be marked ready
+func (g *Gate) Ready() bool {
+	if !g.required {
+		return true
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.passed
+}
+
+// Required reports whether readiness waits for a passing report
+func (g *Gate) Required() bool {
+	return g.required
+}
+
+// Report returns the latest report, or nil before the first run
+func (g *Gate) Report() *Report {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.report
+}
+
+// StatsSource reports graph statistics
+type StatsSource interface {
+	GetStats(ctx context.Context) (*types.SystemStats, error)
+}
+
+// GraphNotEmpty fails when the knowledge graph has no concepts, which
+// usually means the migration was never run against this database
+func GraphNotEmpty(graph StatsSource) Check {
+	return Check{
+		Name: "graph_not_empty",
+		Run: func(ctx context.Context) error {
+			stats, err := graph.GetStats(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to read graph stats: %w", err)
+			}
+			if stats.TotalConcepts == 0 {
+				return fmt.Errorf("knowledge graph has no concepts")
+			}
+			return nil
+		},
+	}
+}
+
+// SchemaSource reads the vectorizer of a vector store class
+type SchemaSource interface {
+	ClassVectorizer(ctx context.Context, class string) (string, error)
+}
+
+// VectorClass fails when a class is missing or uses another vectorizer
+// than the one its data was embedded with
+func VectorClass(schema SchemaSource, class, vectorizer string) Check {
+	return Check{
+		Name: "weaviate_class_" + class,
+		Run: func(ctx context.Context) error {
+			got, err := schema.ClassVectorizer(ctx, class)
+			if err != nil {
+				return err
+			}
+			if got != vectorizer {
+				return fmt.Errorf("class %s uses vectorizer %q, expected %q", class, got, vectorizer)
+			}
+			return nil
+		},
+	}
+}
+
+// HealthPrompter answers a short health prompt
+type HealthPrompter interface {
+	IsHealthy(ctx context.Context) bool
+}
+
+// LLMResponds fails when the LLM does not answer its health prompt
+func LLMResponds(llm HealthPrompter) Check {
+	return Check{
+		Name: "llm_responds",
+		Run: func(ctx context.Context) error {
+			if !llm.IsHealthy(ctx) {
+				return fmt.Errorf("LLM did not answer the health prompt")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mathprereq/internal/types"
+)
+
+type fakeGraph struct {
+	concepts int64
+	err      error
+}
+
+func (g fakeGraph) GetStats(context.Context) (*types.SystemStats, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	return &types.SystemStats{TotalConcepts: g.concepts}, nil
+}
+
+type fakeSchema map[string]string
+
+func (s fakeSchema) ClassVectorizer(_ context.Context, class string) (string, error) {
+	vectorizer, ok := s[class]
+	if !ok {
+		return "", errors.New("class " + class + " does not exist")
+	}
+	return vectorizer, nil
+}
+
+type fakeLLM bool
+
+func (l fakeLLM) IsHealthy(context.Context) bool { return bool(l) }
+
+func TestGraphNotEmpty(t *testing.T) {
+	ctx := context.Background()
+	if err := GraphNotEmpty(fakeGraph{concepts: 42}).Run(ctx); err != nil {
+		t.Fatalf("populated graph failed: %v", err)
+	}
+	if err := GraphNotEmpty(fakeGraph{}).Run(ctx); err == nil {
+		t.Fatal("empty graph passed")
+	}
+	if err := GraphNotEmpty(fakeGraph{err: errors.New("connection refused")}).Run(ctx); err == nil {
+		t.Fatal("unreadable graph passed")
+	}
+}
+
+func TestVectorClass(t *testing.T) {
+	ctx := context.Background()
+	schema := fakeSchema{"MathChunk": "text2vec-weaviate", "MathConcept": "none"}
+
+	if err := VectorClass(schema, "MathChunk", "text2vec-weaviate").Run(ctx); err != nil {
+		t.Fatalf("matching class failed: %v", err)
+	}
+	if err := VectorClass(schema, "MathConcept", "text2vec-weaviate").Run(ctx); err == nil {
+		t.Fatal("class with another vectorizer passed")
+	}
+	if err := VectorClass(schema, "Missing", "text2vec-weaviate").Run(ctx); err == nil {
+		t.Fatal("missing class passed")
+	}
+	if name := VectorClass(schema, "MathChunk", "").Name; name != "weaviate_class_MathChunk" {
+		t.Fatalf("name = %q", name)
+	}
+}
+
+func TestLLMResponds(t *testing.T) {
+	ctx := context.Background()
+	if err := LLMResponds(fakeLLM(true)).Run(ctx); err != nil {
+		t.Fatalf("healthy LLM failed: %v", err)
+	}
+	if err := LLMResponds(fakeLLM(false)).Run(ctx); err == nil {
+		t.Fatal("unresponsive LLM passed")
+	}
+}
+
+func TestRunReportsEveryCheck(t *testing.T) {
+	checks := []Check{
+		GraphNotEmpty(fakeGraph{}),
+		VectorClass(fakeSchema{"MathChunk": "text2vec-weaviate"}, "MathChunk", "text2vec-weaviate"),
+		LLMResponds(fakeLLM(false)),
+	}
+	report := Run(context.Background(), checks, time.Second)
+
+	if report.Passed {
+		t.Fatal("report passed with failing checks")
+	}
+	if len(report.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(report.Results))
+	}
+	failed := report.Failed()
+	if len(failed) != 2 || failed[0] != "graph_not_empty" || failed[1] != "llm_responds" {
+		t.Fatalf("failed = %v", failed)
+	}
+	if report.Results[0].Error == "" {
+		t.Fatal("failed result has no error")
+	}
+}
+
+func TestRunBoundsEachCheck(t *testing.T) {
+	slow := Check{Name: "slow", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	report := Run(context.Background(), []Check{slow}, 10*time.Millisecond)
+	if report.Passed {
+		t.Fatal("check past its timeout passed")
+	}
+}
+
+func TestGate(t *testing.T) {
+	passed := &Report{Passed: true}
+	failed := &Report{Passed: false}
+
+	optional := NewGate(false)
+	optional.Record(failed)
+	if !optional.Ready() {
+		t.Fatal("optional gate closed on failure")
+	}
+
+	required := NewGate(true)
+	if required.Ready() {
+		t.Fatal("required gate open before any report")
+	}
+	required.Record(failed)
+	if required.Ready() {
+		t.Fatal("required gate open after failure")
+	}
+	required.Record(passed)
+	if !required.Ready() {
+		t.Fatal("required gate closed after passing")
+	}
+	required.Record(failed)
+	if !required.Ready() {
+		t.Fatal("required gate closed again after a later failure")
+	}
+	if required.Report() != failed {
+		t.Fatal("gate does not keep the latest report")
+	}
+}
@@ -13,6 +13,36 @@ type Concept struct {
 	Category      string    `json:"category" bson:"category"`
 	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+	// Weight is how essential a path prerequisite is to the path's targets,
+	// the strongest product of edge weights leading to one; zero when unknown
+	Weight float64 `json:"weight,omitempty" bson:"weight,omitempty"`
+}
+
+// DefaultPrerequisiteWeight is the weight of prerequisite edges that were
+// never given one: fully essential
+const DefaultPrerequisiteWeight = 1.0
+
+// MustKnowWeight is the lowest path weight of a must-know prerequisite;
+// lighter prerequisites are helpful rather than essential
+const MustKnowWeight = 0.5
+
+// Prerequisite importance labels of a learning path
+const (
+	ImportanceMustKnow = "must_know"
+	ImportanceHelpful  = "helpful"
+)
+
+// PrerequisiteImportance labels a path weight, or returns an empty string
+// when the weight is unknown
+func PrerequisiteImportance(weight float64) string {
+	switch {
+	case weight <= 0:
+		return ""
+	case weight >= MustKnowWeight:
+		return ImportanceMustKnow
+	default:
+		return ImportanceHelpful
+	}
 }
 
 // Results from graph queries
@@ -23,6 +53,72 @@ type ConceptDetailResult struct {
 	DetailedExplanation string    `json:"detailed_explanation"`
 }
 
+// ConceptNeighborhood is the subgraph within Depth hops of a concept, covering
+// both its prerequisites (upstream) and dependents (downstream)
+type ConceptNeighborhood struct {
+	CenterID  string             `json:"center_id"`
+	Depth     int                `json:"depth"`
+	Nodes     []NeighborhoodNode `json:"nodes"`
+	Edges     []NeighborhoodEdge `json:"edges"`
+	Truncated bool               `json:"truncated"`
+}
+
+type NeighborhoodNode struct {
+	Concept
+	Direction string `json:"direction"` // center, upstream or downstream
+	Distance  int    `json:"distance"`  // hops from the center concept
+}
+
+type NeighborhoodEdge struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Type   string  `json:"type"`
+	Weight float64 `json:"weight"` // 0-1, how essential From is to To
+}
+
+// ConceptMergeResult reports what merging a duplicate concept into another
+// changed. Warnings list follow-up steps that failed after the graph merge.
+type ConceptMergeResult struct {
+	SourceID       string             `json:"source_id"`
+	TargetID       string             `json:"target_id"`
+	EdgesMoved     []NeighborhoodEdge `json:"edges_moved"`
+	EdgesSkipped   []SkippedEdge      `json:"edges_skipped"`
+	ResourcesMoved int64              `json:"resources_moved"`
+	AliasesMoved   int                `json:"aliases_moved"`
+	Warnings       []string           `json:"warnings,omitempty"`
+}
+
+// SkippedEdge is an edge of a merged concept that was not moved, because it
+// was a self_loop, a duplicate or would close a cycle
+type SkippedEdge struct {
+	NeighborhoodEdge
+	Reason string `json:"reason"`
+}
+
+// PathGraph is a query's prerequisite path as a node-link structure for
+// force-directed layouts
+type PathGraph struct {
+	QueryID string          `json:"query_id"`
+	Nodes   []PathGraphNode `json:"nodes"`
+	Links   []PathGraphLink `json:"links"`
+}
+
+type PathGraphNode struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"` // target or prerequisite
+	IsTarget    bool   `json:"is_target"`
+}
+
+// PathGraphLink uses D3's source/target naming; Source is the prerequisite
+type PathGraphLink struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Type   string  `json:"type"`
+	Weight float64 `json:"weight"`
+}
+
 type PrerequisitePathResult struct {
 	Concepts []Concept `json:"concepts"`
 }
@@ -31,8 +127,10 @@ type SystemStats struct {
 	TotalConcepts  int64  `json:"total_concepts"`
 	TotalChunks    int64  `json:"total_chunks"`
 	TotalEdges     int64  `json:"total_edges"`
+	TotalQueries   int64  `json:"total_queries"`
 	KnowledgeGraph string `json:"knowledge_graph"`
 	VectorStore    string `json:"vector_store"`
+	QueryStore     string `json:"query_store"`
 	LLMProvider    string `json:"llm_provider"`
 	SystemHealth   string `json:"system_health"`
 }
@@ -0,0 +1,278 @@
+package visuals
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Function is a parsed single-variable expression f(x)
+type Function func(x float64) float64
+
+var unaryFunctions = map[string]func(float64) float64{
+	"sin":  math.Sin,
+	"cos":  math.Cos,
+	"tan":  math.Tan,
+	"exp":  math.Exp,
+	"ln":   math.Log,
+	"log":  math.Log10,
+	"sqrt": math.Sqrt,
+	"abs":  math.Abs,
+}
+
+// ParseFunction parses expressions such as "x^2 - 3*x + 2", "sin(x)/x" or
+// "y = 2x + 1" into an evaluable function of x. Implicit multiplication
+// between a number and x or a parenthesis ("2x", "3(x+1)") is supported.
+func ParseFunction(expression string) (Function, error) {
+	expr := strings.TrimSpace(expression)
+	// Drop a leading "y =" or "f(x) =" assignment
+	if idx := strings.Index(expr, "="); idx >= 0 {
+		expr = strings.TrimSpace(expr[idx+1:])
+	}
+	if expr == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	p := &parser{input: expr}
+	p.next()
+	node, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.tok.text, p.tok.pos)
+	}
+	return Function(node), nil
+}
+
+// EvaluateConstant evaluates an expression without variables, such as
+// "3 * (4 + 2)" or "2^10 / 4". Unlike ParseFunction, x is rejected and "="
+// is not stripped.
+func EvaluateConstant(expression string) (float64, error) {
+	expr := strings.TrimSpace(expression)
+	if expr == "" {
+		return 0, fmt.Errorf("empty expression")
+	}
+
+	p := &parser{input: expr, constant: true}
+	p.next()
+	node, err := p.parseExpression()
+	if err != nil {
+		return 0, err
+	}
+	if p.tok.kind != tokenEOF {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.tok.text, p.tok.pos)
+	}
+	return node(0), nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+type parser struct {
+	input string
+	pos   int
+	tok   token
+	// constant rejects the variable x
+	constant bool
+}
+
+func (p *parser) next() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return
+	}
+
+	ch := rune(p.input[p.pos])
+	switch {
+	case unicode.IsDigit(ch) || ch == '.':
+		for p.pos < len(p.input) && (unicode.IsDigit(rune(p.input[p.pos])) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		text := p.input[start:p.pos]
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			value = math.NaN()
+		}
+		p.tok = token{kind: tokenNumber, text: text, value: value, pos: start}
+	case unicode.IsLetter(ch):
+		for p.pos < len(p.input) && unicode.IsLetter(rune(p.input[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenIdent, text: strings.ToLower(p.input[start:p.pos]), pos: start}
+	case ch == '(':
+		p.pos++
+		p.tok = token{kind: tokenLParen, text: "(", pos: start}
+	case ch == ')':
+		p.pos++
+		p.tok = token{kind: tokenRParen, text: ")", pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokenOperator, text: string(ch), pos: start}
+	}
+}
+
+// expression := term (("+" | "-") term)*
+func (p *parser) parseExpression() (Function, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokenOperator && (p.tok.text == "+" || p.tok.text == "-") {
+		op := p.tok.text
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		if op == "+" {
+			left = func(x float64) float64 { return l(x) + r(x) }
+		} else {
+			left = func(x float64) float64 { return l(x) - r(x) }
+		}
+	}
+	return left, nil
+}
+
+// term := unary (("*" | "/" | implicit) unary)*
+func (p *parser) parseTerm() (Function, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op string
+		switch {
+		case p.tok.kind == tokenOperator && (p.tok.text == "*" || p.tok.text == "/"):
+			op = p.tok.text
+			p.next()
+		case p.tok.kind == tokenIdent || p.tok.kind == tokenLParen || p.tok.kind == tokenNumber:
+			op = "*" // implicit multiplication, e.g. 2x or 3(x+1)
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		if op == "*" {
+			left = func(x float64) float64 { return l(x) * r(x) }
+		} else {
+			left = func(x float64) float64 { return l(x) / r(x) }
+		}
+	}
+}
+
+// unary := "-" unary | power
+func (p *parser) parseUnary() (Function, error) {
+	if p.tok.kind == tokenOperator && (p.tok.text == "-" || p.tok.text == "+") {
+		negate := p.tok.text == "-"
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			return func(x float64) float64 { return -operand(x) }, nil
+		}
+		return operand, nil
+	}
+	return p.parsePower()
+}
+
+// power := primary ("^" unary)?
+func (p *parser) parsePower() (Function, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenOperator && p.tok.text == "^" {
+		p.next()
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(x float64) float64 { return math.Pow(base(x), exponent(x)) }, nil
+	}
+	return base, nil
+}
+
+func (p *parser) parsePrimary() (Function, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenNumber:
+		if math.IsNaN(tok.value) {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		p.next()
+		value := tok.value
+		return func(float64) float64 { return value }, nil
+
+	case tokenIdent:
+		p.next()
+		switch tok.text {
+		case "x":
+			if p.constant {
+				return nil, fmt.Errorf("unexpected variable x in constant expression")
+			}
+			return func(x float64) float64 { return x }, nil
+		case "pi":
+			return func(float64) float64 { return math.Pi }, nil
+		case "e":
+			return func(float64) float64 { return math.E }, nil
+		}
+		fn, ok := unaryFunctions[tok.text]
+		if !ok {
+			return nil, fmt.Errorf("unknown identifier %q", tok.text)
+		}
+		if p.tok.kind != tokenLParen {
+			return nil, fmt.Errorf("expected ( after %s", tok.text)
+		}
+		arg, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return func(x float64) float64 { return fn(arg(x)) }, nil
+
+	case tokenLParen:
+		p.next()
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.next()
+		return inner, nil
+	}
+
+	if tok.kind == tokenEOF {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
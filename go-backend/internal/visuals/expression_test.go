@@ -0,0 +1,81 @@
+package visuals
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestParseFunction(t *testing.T) {
+	cases := []struct {
+		expr string
+		x    float64
+		want float64
+	}{
+		{"x^2 - 3*x + 2", 4, 6},
+		{"y = 2x + 1", 3, 7},
+		{"f(x) = 3(x+1)", 1, 6},
+		{"-x^2", 3, -9},
+		{"sin(x)/x", math.Pi / 2, 2 / math.Pi},
+		{"sqrt(abs(x)) + ln(e)", -4, 3},
+		{"2^-1 * x", 8, 4},
+	}
+
+	for _, tc := range cases {
+		fn, err := ParseFunction(tc.expr)
+		if err != nil {
+			t.Errorf("ParseFunction(%q) error: %v", tc.expr, err)
+			continue
+		}
+		if got := fn(tc.x); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%q at x=%v = %v, want %v", tc.expr, tc.x, got, tc.want)
+		}
+	}
+}
+
+func TestParseFunctionRejectsInvalidInput(t *testing.T) {
+	for _, expr := range []string{"", "x +", "foo(x)", "(x + 1", "x ? 2"} {
+		if _, err := ParseFunction(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestEvaluateConstant(t *testing.T) {
+	cases := []struct {
+		expr string
+		want float64
+	}{
+		{"3 * (4 + 2)", 18},
+		{"2^10 / 4", 256},
+		{"-7 + 2", -5},
+		{"2(3 + 1)", 8},
+		{"sqrt(16) + pi - pi", 4},
+	}
+	for _, tc := range cases {
+		got, err := EvaluateConstant(tc.expr)
+		if err != nil {
+			t.Errorf("EvaluateConstant(%q) error: %v", tc.expr, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("EvaluateConstant(%q) = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+
+	for _, expr := range []string{"", "2x + 1", "y = 3", "4 +"} {
+		if _, err := EvaluateConstant(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestRenderFunctionPlotProducesPNG(t *testing.T) {
+	image, err := RenderFunctionPlot("1/x", -5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(image, []byte("\x89PNG")) {
+		t.Fatal("expected PNG output")
+	}
+}
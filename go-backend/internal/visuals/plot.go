@@ -0,0 +1,78 @@
+package visuals
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+const (
+	DefaultXMin = -10.0
+	DefaultXMax = 10.0
+
+	plotSamples = 400
+	plotWidth   = 6 * vg.Inch
+	plotHeight  = 4 * vg.Inch
+)
+
+// RenderFunctionPlot draws expression over [xMin, xMax] and returns a PNG image
+func RenderFunctionPlot(expression string, xMin, xMax float64) ([]byte, error) {
+	fn, err := ParseFunction(expression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expression %q: %w", expression, err)
+	}
+	if xMin >= xMax {
+		xMin, xMax = DefaultXMin, DefaultXMax
+	}
+
+	points, err := samplePoints(fn, xMin, xMax)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample expression %q: %w", expression, err)
+	}
+
+	p := plot.New()
+	p.Title.Text = "y = " + expression
+	p.X.Label.Text = "x"
+	p.Y.Label.Text = "y"
+	p.Add(plotter.NewGrid())
+
+	line, err := plotter.NewLine(points)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build plot line: %w", err)
+	}
+	line.Width = vg.Points(2)
+	p.Add(line)
+
+	writer, err := p.WriterTo(plotWidth, plotHeight, "png")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plot writer: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := writer.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render plot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// samplePoints evaluates fn across the range, skipping undefined values
+func samplePoints(fn Function, xMin, xMax float64) (plotter.XYs, error) {
+	step := (xMax - xMin) / plotSamples
+	points := make(plotter.XYs, 0, plotSamples+1)
+	for i := 0; i <= plotSamples; i++ {
+		x := xMin + float64(i)*step
+		y := fn(x)
+		if math.IsNaN(y) || math.IsInf(y, 0) {
+			continue
+		}
+		points = append(points, plotter.XY{X: x, Y: y})
+	}
+	if len(points) < 2 {
+		return nil, fmt.Errorf("expression is undefined over [%g, %g]", xMin, xMax)
+	}
+	return points, nil
+}
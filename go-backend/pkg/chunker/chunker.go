@@ -0,0 +1,251 @@
+// Package chunker splits source text into overlapping chunks for the vector
+// store. Every ingester uses it so chunks from all sources follow the same
+// size and overlap rules.
+package chunker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mode selects the boundaries text is split on before packing
+type Mode string
+
+const (
+	// ModeParagraph splits on blank lines
+	ModeParagraph Mode = "paragraph"
+	// ModeLine splits on every line, for sources with one statement per line
+	ModeLine Mode = "line"
+	// ModeMath splits on blank lines but keeps display math ($$...$$, \[...\],
+	// \begin...\end) with its paragraph, never breaks a paragraph that contains
+	// math, and never starts an overlap inside inline math
+	ModeMath Mode = "math"
+)
+
+// Options controls chunk size and overlap
+type Options struct {
+	// TargetSize is the most characters a chunk holds. Only a paragraph
+	// containing math in ModeMath may exceed it.
+	TargetSize int
+	// Overlap is how many trailing characters of a full chunk are repeated
+	// at the start of the next one, cut at a word boundary
+	Overlap int
+	// MinLength drops chunks shorter than this many characters
+	MinLength int
+	// Mode defaults to ModeParagraph
+	Mode Mode
+}
+
+// Chunk is one piece of the source text
+type Chunk struct {
+	Text  string
+	Index int
+}
+
+// Validate reports options that cannot produce sensible chunks
+func (o Options) Validate() error {
+	if o.TargetSize <= 0 {
+		return fmt.Errorf("chunk size must be positive, got %d", o.TargetSize)
+	}
+	if o.Overlap < 0 || o.Overlap >= o.TargetSize {
+		return fmt.Errorf("chunk overlap must be between 0 and the chunk size %d, got %d", o.TargetSize, o.Overlap)
+	}
+	if o.MinLength < 0 {
+		return fmt.Errorf("minimum chunk length must not be negative, got %d", o.MinLength)
+	}
+	switch o.Mode {
+	case "", ModeParagraph, ModeLine, ModeMath:
+	default:
+		return fmt.Errorf("unknown chunking mode %q", o.Mode)
+	}
+	return nil
+}
+
+var (
+	blankLines    = regexp.MustCompile(`\n\s*\n`)
+	sentenceEnd   = regexp.MustCompile(`[.!?]\s+`)
+	mathDelimiter = regexp.MustCompile(`\$|\\\[|\\\(|\\begin\{`)
+)
+
+// Split cuts text into chunks of at most opts.TargetSize characters along
+// the mode's boundaries. Boundaries are only broken when a single paragraph
+// or line is too long on its own; it is then split between sentences, or
+// between words for an overlong sentence.
+func Split(text string, opts Options) []Chunk {
+	var pieces []string
+	sep := "\n\n"
+	switch opts.Mode {
+	case ModeLine:
+		pieces = strings.Split(text, "\n")
+		sep = "\n"
+	case ModeMath:
+		pieces = mergeDisplayMath(blankLines.Split(text, -1))
+	default:
+		pieces = blankLines.Split(text, -1)
+	}
+
+	var fitted []string
+	for _, piece := range pieces {
+		piece = strings.TrimSpace(piece)
+		switch {
+		case piece == "":
+		case len(piece) <= opts.TargetSize:
+			fitted = append(fitted, piece)
+		case opts.Mode == ModeMath && mathDelimiter.MatchString(piece):
+			fitted = append(fitted, piece)
+		default:
+			fitted = append(fitted, splitOversized(piece, opts.TargetSize)...)
+		}
+	}
+
+	var chunks []Chunk
+	for _, text := range pack(fitted, sep, opts.TargetSize, opts.Overlap, opts.Mode == ModeMath) {
+		if len(text) < opts.MinLength {
+			continue
+		}
+		chunks = append(chunks, Chunk{Text: text, Index: len(chunks)})
+	}
+	return chunks
+}
+
+// pack accumulates pieces, joined by sep, into chunks of at most size
+// characters. A full chunk's tail is carried into the next one when the
+// next piece still fits with it.
+func pack(pieces []string, sep string, size, overlap int, mathAware bool) []string {
+	var chunks []string
+	var current strings.Builder
+
+	for _, piece := range pieces {
+		if current.Len() > 0 && current.Len()+len(sep)+len(piece) > size {
+			chunk := current.String()
+			chunks = append(chunks, chunk)
+			current.Reset()
+
+			if tail := overlapTail(chunk, overlap, mathAware); tail != "" && len(tail)+len(sep)+len(piece) <= size {
+				current.WriteString(tail)
+			}
+		}
+
+		if current.Len() > 0 {
+			current.WriteString(sep)
+		}
+		current.WriteString(piece)
+	}
+
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// overlapTail returns at most n trailing characters of chunk, starting at a
+// word boundary when there is one. When mathAware is set the tail also
+// skips past a $ it would otherwise start inside of.
+func overlapTail(chunk string, n int, mathAware bool) string {
+	if n <= 0 || chunk == "" {
+		return ""
+	}
+	if len(chunk) <= n {
+		return chunk
+	}
+
+	start := len(chunk) - n
+	if space := strings.IndexAny(chunk[start:], " \n"); space >= 0 {
+		start += space
+	} else {
+		for start < len(chunk) && !utf8.RuneStart(chunk[start]) {
+			start++
+		}
+	}
+
+	tail := chunk[start:]
+	if mathAware && strings.Count(tail, "$")%2 == 1 {
+		tail = tail[strings.Index(tail, "$")+1:]
+		if space := strings.IndexAny(tail, " \n"); space >= 0 {
+			tail = tail[space:]
+		} else {
+			tail = ""
+		}
+	}
+	return strings.TrimSpace(tail)
+}
+
+// splitOversized breaks a piece longer than size between sentences, and an
+// overlong sentence between words
+func splitOversized(piece string, size int) []string {
+	var sentences []string
+	last := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(piece, -1) {
+		sentences = append(sentences, strings.TrimSpace(piece[last:loc[1]]))
+		last = loc[1]
+	}
+	if rest := strings.TrimSpace(piece[last:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+
+	var fitted []string
+	for _, sentence := range sentences {
+		if len(sentence) <= size {
+			fitted = append(fitted, sentence)
+			continue
+		}
+		fitted = append(fitted, pack(splitWords(sentence, size), " ", size, 0, false)...)
+	}
+	return pack(fitted, " ", size, 0, false)
+}
+
+// splitWords splits on whitespace, cutting any single word longer than size.
+// A piece always keeps at least one whole character, so one wider than
+// size stays whole rather than being split.
+func splitWords(text string, size int) []string {
+	var words []string
+	for _, word := range strings.Fields(text) {
+		for len(word) > size {
+			cut := size
+			for cut > 0 && !utf8.RuneStart(word[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				_, cut = utf8.DecodeRuneInString(word)
+				if cut == len(word) {
+					break
+				}
+			}
+			words = append(words, word[:cut])
+			word = word[cut:]
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// mergeDisplayMath joins paragraphs while a display math block opened in
+// one of them is still unclosed, so an equation stays in one piece
+func mergeDisplayMath(paragraphs []string) []string {
+	var merged []string
+	var open strings.Builder
+	for _, paragraph := range paragraphs {
+		if open.Len() > 0 {
+			open.WriteString("\n\n")
+		}
+		open.WriteString(paragraph)
+
+		if !displayMathOpen(open.String()) {
+			merged = append(merged, open.String())
+			open.Reset()
+		}
+	}
+	if open.Len() > 0 {
+		merged = append(merged, open.String())
+	}
+	return merged
+}
+
+// displayMathOpen reports whether text leaves a display math block unclosed
+func displayMathOpen(text string) bool {
+	return strings.Count(text, "$$")%2 == 1 ||
+		strings.Count(text, `\[`) > strings.Count(text, `\]`) ||
+		strings.Count(text, `\begin{`) > strings.Count(text, `\end{`)
+}
@@ -0,0 +1,227 @@
+package chunker
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func texts(chunks []Chunk) []string {
+	out := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		out[i] = chunk.Text
+	}
+	return out
+}
+
+func TestSplitPacksParagraphsUpToTargetSize(t *testing.T) {
+	paragraphs := []string{
+		"The derivative measures the instantaneous rate of change.",
+		"It is defined as the limit of the difference quotient.",
+		"The power rule differentiates monomials term by term.",
+		"The chain rule differentiates compositions of functions.",
+	}
+	opts := Options{TargetSize: 120}
+
+	chunks := Split(strings.Join(paragraphs, "\n\n"), opts)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %q", len(chunks), texts(chunks))
+	}
+	if want := paragraphs[0] + "\n\n" + paragraphs[1]; chunks[0].Text != want {
+		t.Errorf("first chunk = %q, want %q", chunks[0].Text, want)
+	}
+	for i, chunk := range chunks {
+		if len(chunk.Text) > opts.TargetSize {
+			t.Errorf("chunk %d has %d chars, target %d", i, len(chunk.Text), opts.TargetSize)
+		}
+		if chunk.Index != i {
+			t.Errorf("chunk %d has index %d", i, chunk.Index)
+		}
+	}
+}
+
+func TestSplitLineMode(t *testing.T) {
+	text := "Limits describe approach.\nContinuity needs limits.\n\nDerivatives are limits."
+
+	chunks := Split(text, Options{TargetSize: 60, Mode: ModeLine})
+	want := []string{"Limits describe approach.\nContinuity needs limits.", "Derivatives are limits."}
+	if got := texts(chunks); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Split() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitOverlap(t *testing.T) {
+	paragraphs := []string{
+		"Limits describe the value a function approaches near a point.",
+		"Continuity requires the limit to equal the function value.",
+		"Derivatives are limits of difference quotients.",
+	}
+	opts := Options{TargetSize: 100, Overlap: 20}
+
+	chunks := Split(strings.Join(paragraphs, "\n\n"), opts)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %q", len(chunks), texts(chunks))
+	}
+	if want := "near a point.\n\n" + paragraphs[1]; chunks[1].Text != want {
+		t.Errorf("second chunk = %q, want %q", chunks[1].Text, want)
+	}
+	for i := 1; i < len(chunks); i++ {
+		tail := strings.SplitN(chunks[i].Text, "\n\n", 2)[0]
+		if !strings.HasSuffix(chunks[i-1].Text, tail) {
+			t.Errorf("chunk %d starts with %q, which does not end chunk %d", i, tail, i-1)
+		}
+		if len(tail) > opts.Overlap {
+			t.Errorf("overlap %q longer than %d", tail, opts.Overlap)
+		}
+		if len(chunks[i].Text) > opts.TargetSize {
+			t.Errorf("chunk %d has %d chars, target %d", i, len(chunks[i].Text), opts.TargetSize)
+		}
+	}
+}
+
+func TestSplitDropsOverlapThatWouldOverflow(t *testing.T) {
+	first := strings.Repeat("a ", 20)
+	second := strings.Repeat("b", 45)
+
+	chunks := Split(first+"\n\n"+second, Options{TargetSize: 50, Overlap: 10})
+	if len(chunks) != 2 || chunks[1].Text != second {
+		t.Errorf("expected the second paragraph alone, got %q", texts(chunks))
+	}
+}
+
+func TestSplitBreaksOversizedParagraphBetweenSentences(t *testing.T) {
+	paragraph := "A limit describes approach. A derivative is a limit. An integral accumulates change. Series sum terms."
+	opts := Options{TargetSize: 60}
+
+	chunks := Split(paragraph, opts)
+	want := []string{
+		"A limit describes approach. A derivative is a limit.",
+		"An integral accumulates change. Series sum terms.",
+	}
+	got := texts(chunks)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Split() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitBreaksOverlongSentenceBetweenWords(t *testing.T) {
+	sentence := strings.TrimSpace(strings.Repeat("integral ", 30))
+	opts := Options{TargetSize: 50}
+
+	chunks := Split(sentence, opts)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %q", texts(chunks))
+	}
+	for _, chunk := range chunks {
+		if len(chunk.Text) > opts.TargetSize {
+			t.Errorf("chunk has %d chars, target %d", len(chunk.Text), opts.TargetSize)
+		}
+		for _, word := range strings.Fields(chunk.Text) {
+			if word != "integral" {
+				t.Errorf("word cut apart: %q", word)
+			}
+		}
+	}
+	if strings.Join(texts(chunks), " ") != sentence {
+		t.Error("words lost or reordered")
+	}
+}
+
+func TestSplitWordsSmallerThanACharacter(t *testing.T) {
+	done := make(chan []string, 1)
+	go func() { done <- texts(Split("é ∫x", Options{TargetSize: 1})) }()
+
+	select {
+	case got := <-done:
+		want := []string{"é", "∫", "x"}
+		if !slices.Equal(got, want) {
+			t.Errorf("Split() = %q, want %q", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Split() did not return for characters wider than the target size")
+	}
+}
+
+func TestSplitMinLength(t *testing.T) {
+	text := "Short.\n\nThis paragraph is long enough to keep as a chunk."
+
+	chunks := Split(text, Options{TargetSize: 50, MinLength: 10})
+	if len(chunks) != 1 || chunks[0].Index != 0 || chunks[0].Text != "This paragraph is long enough to keep as a chunk." {
+		t.Errorf("Split() = %+v", chunks)
+	}
+}
+
+func TestSplitMathKeepsDisplayBlockTogether(t *testing.T) {
+	text := "The quadratic formula:\n\n$$\nx = \\frac{-b \\pm \\sqrt{b^2 - 4ac}}{2a}\n\n$$\n\nIt solves any quadratic equation."
+
+	chunks := Split(text, Options{TargetSize: 40, Mode: ModeMath})
+	var block string
+	for _, chunk := range chunks {
+		if strings.Contains(chunk.Text, "$$") {
+			if block != "" {
+				t.Fatalf("display math split across chunks: %q", texts(chunks))
+			}
+			block = chunk.Text
+		}
+	}
+	if strings.Count(block, "$$") != 2 || !strings.Contains(block, "\\sqrt{b^2 - 4ac}") {
+		t.Errorf("display block not kept whole: %q", block)
+	}
+
+	// Paragraph mode treats the blank line inside the block as a boundary
+	for _, chunk := range Split(text, Options{TargetSize: 40}) {
+		if strings.Count(chunk.Text, "$$") == 2 {
+			t.Errorf("paragraph mode should not merge display math: %q", chunk.Text)
+		}
+	}
+}
+
+func TestSplitMathDoesNotBreakInlineMath(t *testing.T) {
+	paragraph := "By the power rule $\\frac{d}{dx} x^n = n x^{n-1}$ holds for every real exponent. It extends to negative powers."
+
+	chunks := Split(paragraph, Options{TargetSize: 60, Mode: ModeMath})
+	if len(chunks) != 1 || chunks[0].Text != paragraph {
+		t.Errorf("math paragraph should stay whole, got %q", texts(chunks))
+	}
+}
+
+func TestSplitMathOverlapSkipsInlineMath(t *testing.T) {
+	first := "The slope is $m = \\frac{y_2 - y_1}{x_2 - x_1}$ between points"
+	second := "Lines with equal slopes are parallel."
+
+	chunks := Split(first+"\n\n"+second, Options{TargetSize: 80, Overlap: 30, Mode: ModeMath})
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %q", texts(chunks))
+	}
+	overlap := strings.TrimSuffix(chunks[1].Text, "\n\n"+second)
+	if strings.Count(overlap, "$")%2 != 0 {
+		t.Errorf("overlap starts inside inline math: %q", overlap)
+	}
+	if overlap != "between points" {
+		t.Errorf("overlap = %q, want %q", overlap, "between points")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"valid", Options{TargetSize: 100, Overlap: 20, Mode: ModeMath}, false},
+		{"default mode", Options{TargetSize: 100}, false},
+		{"zero size", Options{}, true},
+		{"overlap equals size", Options{TargetSize: 100, Overlap: 100}, true},
+		{"negative overlap", Options{TargetSize: 100, Overlap: -1}, true},
+		{"negative min length", Options{TargetSize: 100, MinLength: -1}, true},
+		{"unknown mode", Options{TargetSize: 100, Mode: "sentence"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package chunker
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Formula is a formula found in source text. Text is the LaTeX between the
+// delimiters, a whole \begin...\end environment, or a plain-text equation
+// such as "∫u dv = uv - ∫v du".
+type Formula struct {
+	Text string
+	// Display is set for display math: $$...$$, \[...\] and environments
+	Display bool
+}
+
+// latexDelimiters pairs each math opener with its closer, longest first so
+// $$ is not read as an empty inline formula
+var latexDelimiters = []struct {
+	open, close string
+	display     bool
+}{
+	{"$$", "$$", true},
+	{`\[`, `\]`, true},
+	{`\(`, `\)`, false},
+	{"$", "$", false},
+}
+
+// ExtractFormulas returns the formulas in text in order of appearance,
+// without duplicates. LaTeX math is taken from its delimiters; the rest of
+// the text is scanned for plain equations, runs of math-like words around a
+// relation sign.
+func ExtractFormulas(text string) []Formula {
+	var formulas []Formula
+	seen := make(map[string]bool)
+	add := func(f Formula) {
+		f.Text = strings.TrimSpace(f.Text)
+		if f.Text == "" || seen[f.Text] {
+			return
+		}
+		seen[f.Text] = true
+		formulas = append(formulas, f)
+	}
+
+	prose := text
+	if mathDelimiter.MatchString(text) {
+		var latex []Formula
+		latex, prose = extractLaTeX(text)
+		for _, f := range latex {
+			add(f)
+		}
+	}
+	for _, equation := range plainEquations(prose) {
+		add(Formula{Text: equation})
+	}
+	return formulas
+}
+
+// extractLaTeX pulls delimited math out of text and returns it along with
+// the text that is left, with each formula replaced by a clause break
+func extractLaTeX(text string) ([]Formula, string) {
+	var formulas []Formula
+	var rest strings.Builder
+
+	for i := 0; i < len(text); {
+		if strings.HasPrefix(text[i:], `\$`) {
+			rest.WriteString(`\$`)
+			i += 2
+			continue
+		}
+		if f, end, ok := latexAt(text, i); ok {
+			formulas = append(formulas, f)
+			rest.WriteString(". ")
+			i = end
+			continue
+		}
+		rest.WriteByte(text[i])
+		i++
+	}
+	return formulas, rest.String()
+}
+
+// latexAt reads a formula opening at text[i], returning it and the index
+// just past its closer
+func latexAt(text string, i int) (Formula, int, bool) {
+	if strings.HasPrefix(text[i:], `\begin{`) {
+		nameEnd := strings.IndexByte(text[i:], '}')
+		if nameEnd < 0 {
+			return Formula{}, 0, false
+		}
+		name := text[i+len(`\begin{`) : i+nameEnd]
+		closer := `\end{` + name + `}`
+		end := strings.Index(text[i+nameEnd:], closer)
+		if end < 0 {
+			return Formula{}, 0, false
+		}
+		end += i + nameEnd + len(closer)
+		return Formula{Text: text[i:end], Display: true}, end, true
+	}
+
+	for _, d := range latexDelimiters {
+		if !strings.HasPrefix(text[i:], d.open) {
+			continue
+		}
+		start := i + len(d.open)
+		end := strings.Index(text[start:], d.close)
+		if end < 0 {
+			return Formula{}, 0, false
+		}
+		inner := text[start : start+end]
+		// A lone $ only opens math when hugging its content, so prices
+		// like "$5 and $10" are left alone
+		if d.open == "$" && (inner == "" || strings.ContainsRune(inner, '\n') ||
+			unicode.IsSpace(rune(inner[0])) || unicode.IsSpace(rune(inner[len(inner)-1]))) {
+			return Formula{}, 0, false
+		}
+		return Formula{Text: inner, Display: d.display}, start + end + len(d.close), true
+	}
+	return Formula{}, 0, false
+}
+
+// plainEquations finds equations written inline in prose, such as
+// "lim(x→c) f(x) = L", by taking each run of math-like words in a clause
+// that contains a relation sign with something on both sides
+func plainEquations(text string) []string {
+	var equations []string
+	for _, clause := range clauses(text) {
+		if !strings.ContainsAny(clause, "=≤≥≠≈") {
+			continue
+		}
+
+		var run []string
+		flush := func() {
+			if equation := strings.Join(run, " "); isEquation(equation) {
+				equations = append(equations, equation)
+			}
+			run = nil
+		}
+		for _, word := range strings.Fields(clause) {
+			if mathWord(word) {
+				run = append(run, word)
+				continue
+			}
+			flush()
+		}
+		flush()
+	}
+	return equations
+}
+
+// clauses splits prose at sentence punctuation and line breaks. Commas
+// inside brackets, as in f(x, y), do not end a clause.
+func clauses(text string) []string {
+	var out []string
+	var current strings.Builder
+	depth := 0
+	runes := []rune(text)
+	for i, r := range runes {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		}
+
+		atBreak := i+1 == len(runes) || unicode.IsSpace(runes[i+1])
+		switch {
+		case r == '\n':
+		case strings.ContainsRune(".;:!?", r) && atBreak:
+		case r == ',' && atBreak && depth == 0:
+		default:
+			current.WriteRune(r)
+			continue
+		}
+		out = append(out, current.String())
+		current.Reset()
+		depth = 0
+	}
+	return append(out, current.String())
+}
+
+// isEquation reports whether a run of math words has a relation sign with
+// something on both sides of it
+func isEquation(run string) bool {
+	rel := strings.IndexAny(run, "=≤≥≠≈")
+	if rel < 0 {
+		return false
+	}
+	_, size := utf8.DecodeRuneInString(run[rel:])
+	return strings.TrimSpace(run[:rel]) != "" && strings.Trim(run[rel+size:], " =") != ""
+}
+
+// mathFunctions are the longer letter runs allowed inside a math word
+var mathFunctions = map[string]bool{
+	"lim": true, "sin": true, "cos": true, "tan": true, "sec": true, "csc": true,
+	"cot": true, "log": true, "ln": true, "exp": true, "max": true, "min": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true,
+	"tanh": true, "det": true, "sqrt": true,
+}
+
+// proseWords are short English words that would otherwise pass as variables
+var proseWords = map[string]bool{
+	"a": true, "i": true, "an": true, "as": true, "at": true, "be": true,
+	"by": true, "if": true, "in": true, "is": true, "it": true, "of": true,
+	"on": true, "or": true, "so": true, "to": true, "we": true, "no": true,
+}
+
+// mathWord reports whether a word reads as part of a formula: every run of
+// three or more letters is a known function, and it either contains a
+// digit or math symbol or is a short variable name like x, dv or uv.
+// Contractions such as "it's" are prose.
+func mathWord(word string) bool {
+	runes := []rune(word)
+	hasSymbol := false
+	letters := 0
+	runStart := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && isASCIILetter(runes[i]) {
+			if runStart < 0 {
+				runStart = i
+			}
+			letters++
+			continue
+		}
+		if runStart >= 0 {
+			name := strings.ToLower(string(runes[runStart:i]))
+			if i-runStart >= 3 && !mathFunctions[name] {
+				return false
+			}
+			runStart = -1
+		}
+		if i == len(runes) {
+			break
+		}
+		if runes[i] == '\'' && i+1 < len(runes) && isASCIILetter(runes[i+1]) {
+			return false
+		}
+		hasSymbol = true
+	}
+	if hasSymbol {
+		return true
+	}
+	return letters <= 2 && !proseWords[strings.ToLower(word)]
+}
+
+func isASCIILetter(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsLetter(r)
+}
@@ -0,0 +1,71 @@
+package chunker
+
+import "testing"
+
+func formulaTexts(formulas []Formula) []string {
+	out := make([]string, len(formulas))
+	for i, f := range formulas {
+		out[i] = f.Text
+	}
+	return out
+}
+
+func TestExtractFormulasPlainText(t *testing.T) {
+	text := "The limit of f(x) as x approaches c is L. We write this as lim(x→c) f(x) = L. " +
+		"The formula is ∫u dv = uv - ∫v du, where u and dv are chosen strategically. " +
+		"It's clear that the derivative of f(x) is denoted as f'(x) or df/dx."
+
+	got := formulaTexts(ExtractFormulas(text))
+	want := []string{"lim(x→c) f(x) = L", "∫u dv = uv - ∫v du"}
+	if len(got) != len(want) {
+		t.Fatalf("ExtractFormulas() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("formula %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExtractFormulasLaTeX(t *testing.T) {
+	text := `The power rule says $\frac{d}{dx} x^n = n x^{n-1}$ for every n.
+
+$$\int_a^b f(x)\,dx = F(b) - F(a)$$
+
+\begin{align}
+(fg)' &= f'g + fg'
+\end{align}
+
+A book costs $5 and a pen $2, and \(e^{i\pi} + 1 = 0\) holds.
+The power rule says $\frac{d}{dx} x^n = n x^{n-1}$ again.`
+
+	formulas := ExtractFormulas(text)
+	want := []Formula{
+		{Text: `\frac{d}{dx} x^n = n x^{n-1}`},
+		{Text: `\int_a^b f(x)\,dx = F(b) - F(a)`, Display: true},
+		{Text: "\\begin{align}\n(fg)' &= f'g + fg'\n\\end{align}", Display: true},
+		{Text: `e^{i\pi} + 1 = 0`},
+	}
+	if len(formulas) != len(want) {
+		t.Fatalf("ExtractFormulas() = %q, want %d formulas", formulaTexts(formulas), len(want))
+	}
+	for i := range want {
+		if formulas[i] != want[i] {
+			t.Errorf("formula %d = %+v, want %+v", i, formulas[i], want[i])
+		}
+	}
+}
+
+func TestExtractFormulasKeepsBracketedArguments(t *testing.T) {
+	got := formulaTexts(ExtractFormulas("For two variables, f(x, y) = x^2 + y^2, which is a paraboloid."))
+	if len(got) != 1 || got[0] != "f(x, y) = x^2 + y^2" {
+		t.Errorf("ExtractFormulas() = %q", got)
+	}
+}
+
+func TestExtractFormulasIgnoresProse(t *testing.T) {
+	text := "Integration is the reverse process of differentiation. Set the price = cost plus margin."
+	if got := ExtractFormulas(text); len(got) != 0 {
+		t.Errorf("expected no formulas, got %q", formulaTexts(got))
+	}
+}